@@ -123,6 +123,11 @@ type Dev struct {
 	name      string
 	dataRates map[int]uint16
 	mu        sync.Mutex // For executePreparedQuery()
+
+	// Continuous conversion started with StartContinuous.
+	stop   chan struct{}
+	wg     sync.WaitGroup
+	config uint16
 }
 
 // NewADS1015 creates a new driver for the ADS1015 (12-bit ADC).
@@ -165,9 +170,23 @@ func (d *Dev) String() string {
 	return d.name
 }
 
+// Halt stops the continuous conversion started with StartContinuous, if
+// any, and puts the ADC in power-down.
+//
 // Halt implements conn.Resource.
 func (d *Dev) Halt() error {
-	return nil
+	d.mu.Lock()
+	stop := d.stop
+	d.stop = nil
+	d.mu.Unlock()
+	if stop == nil {
+		return nil
+	}
+	close(stop)
+	d.wg.Wait()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.writeRegister(ads1x15PointerConfig, d.config&^ads1x15ConfigOsSingle|ads1x15ConfigModeSingle|ads1x15ConfigCompQueDisable)
 }
 
 // PinForChannel returns an AnalogPin for the requested channel at the
@@ -175,28 +194,57 @@ func (d *Dev) Halt() error {
 //
 // The channel can either be an absolute reading or a differential one.
 func (d *Dev) PinForChannel(c Channel, maxVoltage physic.ElectricPotential, f physic.Frequency, q ConversionQuality) (PinADC, error) {
+	config, voltageMultiplier, dataRate, err := d.prepareConfig(c, maxVoltage, f, q)
+	if err != nil {
+		return nil, err
+	}
+	// Set the mode (continuous or single shot).
+	config |= ads1x15ConfigModeSingle
+	config |= ads1x15ConfigCompQueDisable // Disable comparator mode.
+
+	// Build the query to the ADC.
+	configBytes := [2]byte{}
+	binary.BigEndian.PutUint16(configBytes[:], config)
+
+	// The wait for the ADC sample to finish is based on the sample rate.
+	waitTime := time.Second / time.Duration(dataRate)
+
+	return &analogPin{
+		adc:                d,
+		c:                  c,
+		query:              [...]byte{ads1x15PointerConfig, configBytes[0], configBytes[1]},
+		voltageMultiplier:  voltageMultiplier,
+		waitTime:           waitTime,
+		requestedFrequency: f,
+	}, nil
+}
+
+// prepareConfig returns the configuration register value for the channel,
+// gain and data rate, without the mode and the comparator bits, along the
+// full scale voltage and the data rate in samples per second.
+func (d *Dev) prepareConfig(c Channel, maxVoltage physic.ElectricPotential, f physic.Frequency, q ConversionQuality) (uint16, physic.ElectricPotential, int, error) {
 	// Determine the most appropriate gain
 	gain, err := d.bestGainForElectricPotential(maxVoltage)
 	if err != nil {
-		return nil, err
+		return 0, 0, 0, err
 	}
 
 	// Validate the gain.
 	gainConf, ok := gainConfig[gain]
 	if !ok {
-		return nil, errors.New("gain must be one of: 2/3, 1, 2, 4, 8, 16")
+		return 0, 0, 0, errors.New("gain must be one of: 2/3, 1, 2, 4, 8, 16")
 	}
 
 	// Determine the voltage multiplier for this gain.
 	voltageMultiplier, ok := gainVoltage[gain]
 	if !ok {
-		return nil, errors.New("gain must be one of: 2/3, 1, 2, 4, 8, 16")
+		return 0, 0, 0, errors.New("gain must be one of: 2/3, 1, 2, 4, 8, 16")
 	}
 
 	// Determine the most appropriate data rate.
 	dataRate, err := d.bestDataRateForFrequency(f, q)
 	if err != nil {
-		return nil, err
+		return 0, 0, 0, err
 	}
 
 	dataRateConf, ok := d.dataRates[dataRate]
@@ -206,7 +254,7 @@ func (d *Dev) PinForChannel(c Channel, maxVoltage physic.ElectricPotential, f ph
 		for k := range d.dataRates {
 			keys = append(keys, k)
 		}
-		return nil, fmt.Errorf("invalid data rate. Accepted values: %d", keys)
+		return 0, 0, 0, fmt.Errorf("invalid data rate. Accepted values: %d", keys)
 	}
 
 	// Build the configuration value
@@ -216,35 +264,19 @@ func (d *Dev) PinForChannel(c Channel, maxVoltage physic.ElectricPotential, f ph
 	config |= uint16(c) << ads1x15ConfigMuxOffset
 	// Validate the passed in gain and then set it in the config.
 	config |= gainConf
-	// Set the mode (continuous or single shot).
-	config |= ads1x15ConfigModeSingle
-
 	// Set the data rate (this is controlled by the subclass as it differs
 	// between ADS1015 and ADS1115).
 	config |= dataRateConf
-	config |= ads1x15ConfigCompQueDisable // Disable comparator mode.
-
-	// Build the query to the ADC.
-	configBytes := [2]byte{}
-	binary.BigEndian.PutUint16(configBytes[:], config)
-
-	// The wait for the ADC sample to finish is based on the sample rate.
-	waitTime := time.Second / time.Duration(dataRate)
-
-	return &analogPin{
-		adc:                d,
-		c:                  c,
-		query:              [...]byte{ads1x15PointerConfig, configBytes[0], configBytes[1]},
-		voltageMultiplier:  voltageMultiplier,
-		waitTime:           waitTime,
-		requestedFrequency: f,
-	}, nil
+	return config, voltageMultiplier, dataRate, nil
 }
 
 func (d *Dev) executePreparedQuery(query []byte, waitTime time.Duration, voltageMultiplier physic.ElectricPotential) (analog.Sample, error) {
 	// Lock the ADC converter to avoid multiple simultaneous readings.
 	d.mu.Lock()
 	defer d.mu.Unlock()
+	if d.stop != nil {
+		return analog.Sample{}, errContinuous
+	}
 
 	// Send the config value to start the ADC conversion.
 	// Explicitly break the 16-bit value down to a big endian pair of bytes.
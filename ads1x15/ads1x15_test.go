@@ -9,6 +9,8 @@ import (
 	"testing"
 
 	"periph.io/x/conn/v3/analog"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpiotest"
 	"periph.io/x/conn/v3/i2c/i2ctest"
 	"periph.io/x/conn/v3/physic"
 	"periph.io/x/conn/v3/pin"
@@ -252,3 +254,86 @@ func TestPinADC_ReadContinous(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestStartContinuous_Ready(t *testing.T) {
+	b := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x48, W: []byte{0x2, 0x00, 0x00}},
+			{Addr: 0x48, W: []byte{0x3, 0x80, 0x00}},
+			{Addr: 0x48, W: []byte{0x1, 0xc2, 0xe0}},
+			{Addr: 0x48, W: []byte{0x0}, R: []byte{0x3e, 0x80}},
+			{Addr: 0x48, W: []byte{0x0}, R: []byte{0xc1, 0x80}},
+			{Addr: 0x48, W: []byte{0x1, 0x43, 0xe3}},
+		},
+	}
+	defer b.Close()
+	d, err := NewADS1115(&b, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alert := &gpiotest.Pin{N: "ALERT", EdgesChan: make(chan gpio.Level, 1)}
+	c, err := d.StartContinuous(Channel0, 4*physic.Volt, 800*physic.Hertz, SaveEnergy, &ContinuousOpts{Alert: alert})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alert.P != gpio.PullUp {
+		t.Fatal(alert.P)
+	}
+	for _, expected := range []physic.ElectricPotential{2 * physic.Volt, -2 * physic.Volt} {
+		alert.EdgesChan <- gpio.Low
+		if s := <-c; s.V != expected {
+			t.Fatalf("Found %s, expected %s", s.V, expected)
+		}
+	}
+	p, err := d.PinForChannel(Channel1, 4*physic.Volt, 800*physic.Hertz, SaveEnergy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Read(); err != errContinuous {
+		t.Fatal(err)
+	}
+	if err := d.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := <-c; ok {
+		t.Fatal("channel not closed")
+	}
+}
+
+func TestStartContinuous_Comparator(t *testing.T) {
+	r := i2ctest.Record{}
+	d, err := NewADS1115(&r, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmp := &Comparator{Mode: Window, Low: physic.Volt, High: 2 * physic.Volt, Latching: true, Queue: 2}
+	c, err := d.StartContinuous(Channel0, 4*physic.Volt, 800*physic.Hertz, SaveEnergy, &ContinuousOpts{Comparator: cmp})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := <-c; ok {
+		t.Fatal("channel not closed")
+	}
+	expected := [][]byte{{0x2, 0x1f, 0x40}, {0x3, 0x3e, 0x80}, {0x1, 0xc2, 0xf5}}
+	for i, w := range expected {
+		if !reflect.DeepEqual(r.Ops[i].W, w) {
+			t.Fatalf("#%d: %#v != %#v", i, r.Ops[i].W, w)
+		}
+	}
+	if w := r.Ops[len(r.Ops)-1].W; !reflect.DeepEqual(w, []byte{0x1, 0x43, 0xf7}) {
+		t.Fatalf("%#v", w)
+	}
+
+	for _, cmp := range []Comparator{
+		{Low: 2 * physic.Volt, High: physic.Volt},
+		{High: 5 * physic.Volt},
+		{Queue: 3},
+	} {
+		if _, err := d.StartContinuous(Channel0, 4*physic.Volt, 800*physic.Hertz, SaveEnergy, &ContinuousOpts{Comparator: &cmp}); err == nil {
+			t.Fatalf("expected error for %+v", cmp)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ads1x15
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"time"
+
+	"periph.io/x/conn/v3/analog"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/physic"
+)
+
+// ComparatorMode is the mode of the comparator driving the ALERT/RDY pin.
+type ComparatorMode int
+
+const (
+	// Traditional asserts ALERT/RDY when the conversion exceeds High and
+	// deasserts it when it goes below Low.
+	Traditional ComparatorMode = 0
+	// Window asserts ALERT/RDY when the conversion is above High or below
+	// Low.
+	Window ComparatorMode = 1
+)
+
+// Comparator configures the digital comparator of the ADC.
+type Comparator struct {
+	Mode ComparatorMode
+	// Low and High are the thresholds. They must be within the full scale
+	// range of the channel.
+	Low  physic.ElectricPotential
+	High physic.ElectricPotential
+	// ActiveHigh makes ALERT/RDY active high instead of active low.
+	ActiveHigh bool
+	// Latching keeps ALERT/RDY asserted until the conversion is read.
+	Latching bool
+	// Queue is the number of successive conversions exceeding the thresholds
+	// before ALERT/RDY is asserted: 1, 2 or 4. 0 means 1.
+	Queue int
+}
+
+// ContinuousOpts holds the options of a continuous conversion.
+type ContinuousOpts struct {
+	// Alert is the GPIO connected to the ALERT/RDY pin of the ADC. It is
+	// optional. When set and Comparator is nil, ALERT/RDY is configured as a
+	// conversion ready signal and each conversion is read as soon as it is
+	// done, instead of being polled at the data rate.
+	Alert gpio.PinIn
+	// Comparator enables the comparator on ALERT/RDY. The conversions are
+	// then polled at the data rate.
+	Comparator *Comparator
+}
+
+// StartContinuous starts the continuous conversion of a channel and returns
+// the conversions.
+//
+// The gain and the data rate are selected as with PinForChannel. The ADC
+// converts a single channel in continuous mode, so reading a pin of the same
+// ADC fails until Halt is called. The channel is closed by Halt.
+func (d *Dev) StartContinuous(c Channel, maxVoltage physic.ElectricPotential, f physic.Frequency, q ConversionQuality, opts *ContinuousOpts) (<-chan analog.Sample, error) {
+	config, voltageMultiplier, dataRate, err := d.prepareConfig(c, maxVoltage, f, q)
+	if err != nil {
+		return nil, err
+	}
+	config |= ads1x15ConfigModeContinuous
+	lo, hi := uint16(0x0000), uint16(0x8000)
+	ready := opts.Alert != nil && opts.Comparator == nil
+	if cmp := opts.Comparator; cmp != nil {
+		if cmp.Low > cmp.High {
+			return nil, errors.New("ads1x15: comparator Low must not be greater than High")
+		}
+		l, err := toRaw(cmp.Low, voltageMultiplier)
+		if err != nil {
+			return nil, err
+		}
+		h, err := toRaw(cmp.High, voltageMultiplier)
+		if err != nil {
+			return nil, err
+		}
+		lo, hi = uint16(l), uint16(h)
+		switch cmp.Queue {
+		case 0, 1:
+		case 2:
+			config |= 0x0001
+		case 4:
+			config |= 0x0002
+		default:
+			return nil, errors.New("ads1x15: comparator queue must be 1, 2 or 4")
+		}
+		if cmp.Mode == Window {
+			config |= ads1x15ConfigCompWindow
+		}
+		if cmp.ActiveHigh {
+			config |= ads1x15ConfigCompAactiveHigh
+		}
+		if cmp.Latching {
+			config |= ads1x15ConfigCompLatching
+		}
+	} else if !ready {
+		config |= ads1x15ConfigCompQueDisable
+	}
+	// Otherwise the comparator asserts ALERT/RDY after each conversion:
+	// the MSB of the high threshold set and the one of the low threshold
+	// cleared select the conversion ready mode.
+
+	if err := d.Halt(); err != nil {
+		return nil, err
+	}
+	if ready {
+		// ALERT/RDY is open drain and pulses low at the end of each
+		// conversion.
+		if err := opts.Alert.In(gpio.PullUp, gpio.FallingEdge); err != nil {
+			return nil, err
+		}
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.writeRegister(ads1x15PointerLowThreshold, lo); err != nil {
+		return nil, err
+	}
+	if err := d.writeRegister(ads1x15PointerHighThreshold, hi); err != nil {
+		return nil, err
+	}
+	if err := d.writeRegister(ads1x15PointerConfig, config); err != nil {
+		return nil, err
+	}
+	d.config = config
+	d.stop = make(chan struct{})
+	reading := make(chan analog.Sample, 16)
+	var alert gpio.PinIn
+	if ready {
+		alert = opts.Alert
+	}
+	period := time.Second / time.Duration(dataRate)
+	d.wg.Add(1)
+	go func(stop <-chan struct{}) {
+		defer d.wg.Done()
+		defer close(reading)
+		d.readingContinuous(alert, period, voltageMultiplier, reading, stop)
+	}(d.stop)
+	return reading, nil
+}
+
+//
+
+var errContinuous = errors.New("ads1x15: continuous conversion in progress, call Halt first")
+
+// toRaw converts a voltage to the value of a threshold register.
+func toRaw(v, voltageMultiplier physic.ElectricPotential) (int16, error) {
+	if v < -voltageMultiplier || v > voltageMultiplier {
+		return 0, errors.New("ads1x15: comparator threshold out of range, maximum is " + voltageMultiplier.String())
+	}
+	r := int64(v) * (1 << 15) / int64(voltageMultiplier)
+	if r > math.MaxInt16 {
+		r = math.MaxInt16
+	}
+	return int16(r), nil
+}
+
+func (d *Dev) writeRegister(reg byte, v uint16) error {
+	w := [3]byte{reg}
+	binary.BigEndian.PutUint16(w[1:], v)
+	return d.c.Tx(w[:], nil)
+}
+
+func (d *Dev) readConversion(voltageMultiplier physic.ElectricPotential) (analog.Sample, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	data := []byte{0, 0}
+	if err := d.c.Tx([]byte{ads1x15PointerConversion}, data); err != nil {
+		return analog.Sample{}, err
+	}
+	raw := int16(binary.BigEndian.Uint16(data))
+	return analog.Sample{
+		Raw: int32(raw),
+		V:   physic.ElectricPotential(raw) * voltageMultiplier / physic.ElectricPotential(1<<15),
+	}, nil
+}
+
+// readingContinuous reads the conversions, either on the edges of the alert
+// pin when it is not nil, or at each period.
+func (d *Dev) readingContinuous(alert gpio.PinIn, period time.Duration, voltageMultiplier physic.ElectricPotential, reading chan<- analog.Sample, stop <-chan struct{}) {
+	var tick <-chan time.Time
+	if alert == nil {
+		t := time.NewTicker(period)
+		defer t.Stop()
+		tick = t.C
+	}
+	for {
+		if alert != nil {
+			// Wake up regularly to check for stop.
+			if !alert.WaitForEdge(2*period + alertTimeout) {
+				select {
+				case <-stop:
+					return
+				default:
+					continue
+				}
+			}
+		} else {
+			select {
+			case <-stop:
+				return
+			case <-tick:
+			}
+		}
+		value, err := d.readConversion(voltageMultiplier)
+		if err != nil {
+			// In continuous mode, we'll ignore errors silently.
+			continue
+		}
+		select {
+		case reading <- value:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// alertTimeout is added to the conversion period when waiting for the
+// ALERT/RDY pin.
+const alertTimeout = 10 * time.Millisecond
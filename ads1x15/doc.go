@@ -5,6 +5,12 @@
 // Package ads1x15 controls ADS1015/ADS1115 Analog-Digital Converters (ADC) via
 // I²C interface.
 //
+// PinForChannel returns an analog.PinADC doing single shot conversions, with
+// the gain and the data rate selected for each channel. StartContinuous
+// runs the ADC in continuous conversion mode on one channel, optionally using
+// the ALERT/RDY pin as a conversion ready signal or as the output of the
+// threshold comparator.
+//
 // # Datasheet
 //
 // ADS1015: http://www.ti.com/product/ADS1015
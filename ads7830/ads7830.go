@@ -0,0 +1,229 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ads7830
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/analog"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/pin"
+)
+
+// Channel is the analog reading to do. It can be either a single-ended
+// reading or a pseudo-differential reading between two inputs.
+type Channel int
+
+// Value channels.
+const (
+	// Single-ended reading.
+	Channel0 Channel = 8
+	Channel1 Channel = 9
+	Channel2 Channel = 10
+	Channel3 Channel = 11
+	Channel4 Channel = 12
+	Channel5 Channel = 13
+	Channel6 Channel = 14
+	Channel7 Channel = 15
+
+	// Pseudo-differential reading. A negative difference reads as 0.
+	Channel0Minus1 Channel = 0
+	Channel1Minus0 Channel = 1
+	Channel2Minus3 Channel = 2
+	Channel3Minus2 Channel = 3
+	Channel4Minus5 Channel = 4
+	Channel5Minus4 Channel = 5
+	Channel6Minus7 Channel = 6
+	Channel7Minus6 Channel = 7
+)
+
+func (c Channel) String() string {
+	if c < 0 || c > Channel7 {
+		return "Invalid"
+	}
+	if c >= Channel0 {
+		return fmt.Sprintf("%d", c-Channel0)
+	}
+	return fmt.Sprintf("%d-%d", c, c^1)
+}
+
+// InternalRef is the voltage of the internal reference.
+const InternalRef = 2500 * physic.MilliVolt
+
+// Opts holds the configuration options.
+type Opts struct {
+	// Addr is the I²C address, from 0x48 to 0x4B depending on the A0-A1
+	// pins.
+	Addr uint16
+	// Vref is the voltage applied to the REF pin, which is the full scale of
+	// the conversions. If 0, the internal reference is used and kept on.
+	Vref physic.ElectricPotential
+}
+
+// DefaultOpts are the recommended default options, for a device with its
+// address pins tied to ground and 3.3V applied to REF.
+var DefaultOpts = Opts{
+	Addr: 0x48,
+	Vref: 3300 * physic.MilliVolt,
+}
+
+// Dev is a handle to an ADS7830.
+type Dev struct {
+	c    i2c.Dev
+	vref physic.ElectricPotential
+	// pd is the power-down selection of the command byte.
+	pd byte
+	mu sync.Mutex
+}
+
+// NewI2C returns a handle to an ADS7830.
+func NewI2C(b i2c.Bus, opts *Opts) (*Dev, error) {
+	if opts.Addr < 0x48 || opts.Addr > 0x4B {
+		return nil, errors.New("ads7830: invalid address, must be between 0x48 and 0x4B")
+	}
+	if opts.Vref < 0 {
+		return nil, errors.New("ads7830: Vref must be positive")
+	}
+	d := &Dev{c: i2c.Dev{Bus: b, Addr: opts.Addr}, vref: opts.Vref, pd: externalRef}
+	if opts.Vref == 0 {
+		d.vref = InternalRef
+		d.pd = internalRef
+	}
+	return d, nil
+}
+
+func (d *Dev) String() string {
+	return fmt.Sprintf("ADS7830{%s}", &d.c)
+}
+
+// Halt implements conn.Resource.
+//
+// The ADC is in power-down between conversions. Halt also turns the internal
+// reference off, until the next conversion.
+func (d *Dev) Halt() error {
+	if d.pd != internalRef {
+		return nil
+	}
+	_, err := d.convert(byte(Channel0)<<4 | powerDown)
+	return err
+}
+
+// PinForChannel returns an analog pin reading the requested channel.
+func (d *Dev) PinForChannel(c Channel) (analog.PinADC, error) {
+	if c < 0 || c > Channel7 {
+		return nil, fmt.Errorf("ads7830: invalid channel %s", c)
+	}
+	return &analogPin{adc: d, c: c}, nil
+}
+
+//
+
+// Power-down selection of the command byte.
+const (
+	// powerDown powers down the reference and the ADC between conversions.
+	powerDown = 0x00
+	// externalRef keeps the ADC on and the internal reference off.
+	externalRef = 0x04
+	// internalRef keeps the ADC and the internal reference on.
+	internalRef = 0x0C
+)
+
+// read does a conversion of the channel and returns the raw value.
+func (d *Dev) read(c Channel) (int32, error) {
+	// The single-ended flag is bit 3 of the channel. The channel selection
+	// starts with the even inputs, then the odd ones.
+	sel := byte(c&8) | byte(c&6)>>1 | byte(c&1)<<2
+	return d.convert(sel<<4 | d.pd)
+}
+
+// convert sends the command byte and returns the result.
+func (d *Dev) convert(cmd byte) (int32, error) {
+	var r [1]byte
+	d.mu.Lock()
+	err := d.c.Tx([]byte{cmd}, r[:])
+	d.mu.Unlock()
+	if err != nil {
+		return 0, fmt.Errorf("ads7830: %v", err)
+	}
+	return int32(r[0]), nil
+}
+
+func (d *Dev) toSample(raw int32) analog.Sample {
+	return analog.Sample{
+		Raw: raw,
+		V:   physic.ElectricPotential(raw) * d.vref / 256,
+	}
+}
+
+type analogPin struct {
+	adc *Dev
+	c   Channel
+}
+
+// Range returns the maximum supported range [min, max] of the values.
+func (p *analogPin) Range() (analog.Sample, analog.Sample) {
+	return p.adc.toSample(0), p.adc.toSample(255)
+}
+
+// Read returns the current pin level.
+func (p *analogPin) Read() (analog.Sample, error) {
+	raw, err := p.adc.read(p.c)
+	if err != nil {
+		return analog.Sample{}, err
+	}
+	return p.adc.toSample(raw), nil
+}
+
+func (p *analogPin) Name() string {
+	return "ADS7830(" + p.c.String() + ")"
+}
+
+// Number returns the channel number, the differential pairs following the
+// single-ended channels.
+func (p *analogPin) Number() int {
+	if p.c >= Channel0 {
+		return int(p.c - Channel0)
+	}
+	return 8 + int(p.c)
+}
+
+func (p *analogPin) Function() string {
+	return string(p.Func())
+}
+
+// Func implements pin.PinFunc.
+func (p *analogPin) Func() pin.Func {
+	return analog.ADC
+}
+
+// SupportedFuncs implements pin.PinFunc.
+func (p *analogPin) SupportedFuncs() []pin.Func {
+	return []pin.Func{analog.ADC}
+}
+
+// SetFunc implements pin.PinFunc.
+func (p *analogPin) SetFunc(f pin.Func) error {
+	if f == analog.ADC {
+		return nil
+	}
+	return errors.New("ads7830: pin function cannot be changed")
+}
+
+func (p *analogPin) Halt() error {
+	return nil
+}
+
+func (p *analogPin) String() string {
+	return p.Name()
+}
+
+var _ conn.Resource = &Dev{}
+var _ analog.PinADC = &analogPin{}
+var _ pin.Pin = &analogPin{}
+var _ pin.PinFunc = &analogPin{}
@@ -0,0 +1,147 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ads7830
+
+import (
+	"reflect"
+	"testing"
+
+	"periph.io/x/conn/v3/analog"
+	"periph.io/x/conn/v3/i2c/i2ctest"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/pin"
+)
+
+func TestChannel_String(t *testing.T) {
+	data := []struct {
+		c        Channel
+		expected string
+	}{
+		{Channel0, "0"},
+		{Channel7, "7"},
+		{Channel0Minus1, "0-1"},
+		{Channel5Minus4, "5-4"},
+		{Channel(-1), "Invalid"},
+		{Channel(16), "Invalid"},
+	}
+	for _, line := range data {
+		if actual := line.c.String(); actual != line.expected {
+			t.Fatalf("%s != %s", line.expected, actual)
+		}
+	}
+}
+
+func TestNewI2C(t *testing.T) {
+	if _, err := NewI2C(&i2ctest.Record{}, &Opts{Addr: 0x4C}); err == nil {
+		t.Fatal("expected error on address")
+	}
+	if _, err := NewI2C(&i2ctest.Record{}, &Opts{Addr: 0x48, Vref: -physic.Volt}); err == nil {
+		t.Fatal("expected error on Vref")
+	}
+	d, err := NewI2C(&i2ctest.Record{}, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := d.String(); s != "ADS7830{record(72)}" {
+		t.Fatal(s)
+	}
+	if _, err := d.PinForChannel(Channel(16)); err == nil {
+		t.Fatal("expected error on channel")
+	}
+	// Nothing to do without the internal reference.
+	if err := d.Halt(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPinADC(t *testing.T) {
+	d, err := NewI2C(&i2ctest.Record{}, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := d.PinForChannel(Channel2Minus3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := p.String(); v != "ADS7830(2-3)" {
+		t.Fatal(v)
+	}
+	if v := p.Number(); v != 10 {
+		t.Fatal(v)
+	}
+	if v := p.Function(); v != "ADC" {
+		t.Fatal(v)
+	}
+	if v := p.(pin.PinFunc).SupportedFuncs(); !reflect.DeepEqual(v, []pin.Func{analog.ADC}) {
+		t.Fatal(v)
+	}
+	if err := p.(pin.PinFunc).SetFunc(pin.FuncNone); err == nil {
+		t.Fatal("expected failure")
+	}
+	min, max := p.Range()
+	if min.Raw != 0 || max.Raw != 255 || max.V != 3287109375*physic.NanoVolt {
+		t.Fatal(min, max)
+	}
+	if err := p.Halt(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPinADC_Read(t *testing.T) {
+	data := []struct {
+		vref     physic.ElectricPotential
+		c        Channel
+		w        byte
+		r        byte
+		expected analog.Sample
+	}{
+		{3300 * physic.MilliVolt, Channel0, 0x84, 0x80, analog.Sample{Raw: 128, V: 1650 * physic.MilliVolt}},
+		{3300 * physic.MilliVolt, Channel1, 0xC4, 0x00, analog.Sample{Raw: 0}},
+		{3300 * physic.MilliVolt, Channel7, 0xF4, 0x40, analog.Sample{Raw: 64, V: 825 * physic.MilliVolt}},
+		{3300 * physic.MilliVolt, Channel2Minus3, 0x14, 0x80, analog.Sample{Raw: 128, V: 1650 * physic.MilliVolt}},
+		{3300 * physic.MilliVolt, Channel7Minus6, 0x74, 0x80, analog.Sample{Raw: 128, V: 1650 * physic.MilliVolt}},
+		{0, Channel4, 0xAC, 0x40, analog.Sample{Raw: 64, V: 625 * physic.MilliVolt}},
+	}
+	for i, line := range data {
+		bus := i2ctest.Playback{
+			Ops: []i2ctest.IO{{Addr: 0x48, W: []byte{line.w}, R: []byte{line.r}}},
+		}
+		d, err := NewI2C(&bus, &Opts{Addr: 0x48, Vref: line.vref})
+		if err != nil {
+			t.Fatal(err)
+		}
+		p, err := d.PinForChannel(line.c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s, err := p.Read()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != line.expected {
+			t.Fatalf("#%d: %v != %v", i, s, line.expected)
+		}
+		if err := bus.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestHalt(t *testing.T) {
+	// The internal reference is turned off with a conversion.
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{{Addr: 0x4B, W: []byte{0x80}, R: []byte{0x00}}},
+	}
+	d, err := NewI2C(&bus, &Opts{Addr: 0x4B})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,18 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package ads7830 controls a Texas Instruments ADS7830 8 bits Analog-Digital
+// Converter (ADC) with 8 inputs via I²C interface.
+//
+// It is found on many learning kits for the Raspberry Pi, which has no ADC.
+// Each input can be read single-ended or as a pseudo-differential pair,
+// through the analog.PinADC returned by PinForChannel.
+//
+// The conversions are relative either to the voltage applied to the REF pin
+// or to the internal 2.5V reference.
+//
+// # Datasheet
+//
+// https://www.ti.com/lit/ds/symlink/ads7830.pdf
+package ads7830
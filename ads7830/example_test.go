@@ -0,0 +1,44 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ads7830_test
+
+import (
+	"fmt"
+	"log"
+
+	"periph.io/x/conn/v3/i2c/i2creg"
+	"periph.io/x/devices/v3/ads7830"
+	"periph.io/x/host/v3"
+)
+
+func Example() {
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Open default I²C bus.
+	bus, err := i2creg.Open("")
+	if err != nil {
+		log.Fatalf("failed to open I²C: %v", err)
+	}
+	defer bus.Close()
+
+	adc, err := ads7830.NewI2C(bus, &ads7830.DefaultOpts)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	// Read the first input, relative to the voltage applied to REF.
+	pin, err := adc.PinForChannel(ads7830.Channel0)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	reading, err := pin.Read()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Println(reading)
+}
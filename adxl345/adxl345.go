@@ -7,6 +7,8 @@ package adxl345
 import (
 	"encoding/binary"
 	"fmt"
+	"sync"
+
 	"periph.io/x/conn/v3"
 	"periph.io/x/conn/v3/i2c"
 	"periph.io/x/conn/v3/physic"
@@ -34,10 +36,12 @@ const (
 	TimeInact        = 0x26 // Inactivity time
 	ActInactCtl      = 0x27 // Axis control for activity/inactivity detection
 	ThreshFf         = 0x28 // Free-fall threshold
+	TimeFf           = 0x29 // Free-fall time
 	TapAxes          = 0x2A // Axis control for single tap/double tap
+	ActTapStatus     = 0x2B // Source of single tap/double tap and activity
 	TapStatus        = 0x2B // Source of single tap/double tap
-	ActivityStatus   = 0x2A // Source of activity detection
-	InactivityStatus = 0x2B // Source of inactivity detection
+	ActivityStatus   = 0x2A // Deprecated: use ActTapStatus.
+	InactivityStatus = 0x2B // Deprecated: use ActTapStatus.
 
 	// Control registers
 
@@ -94,10 +98,11 @@ var DefaultOpts = Opts{
 type Opts struct {
 	ExpectedDeviceID byte        // Expected device ID used to verify that the device is an ADXL345.
 	Sensitivity      Sensitivity // Sensitivity of the device (2G, 4G, 8G, 16G)
+	DataRate         DataRate    // Output data rate, 0 keeps the default of 100Hz; use SetDataRate for Rate0_10Hz.
 }
 
 // Dev is a driver for the ADXL345 accelerometer
-// It uses the SPI interface to communicate with the device.
+// It uses the I²C or SPI interface to communicate with the device.
 type Dev struct {
 	c     conn.Conn
 	name  string
@@ -105,6 +110,10 @@ type Dev struct {
 	// The sensitivity of the device (2G, 4G, 8G, 16G)
 	// Set to 2G by default, can be changed in the Opts at initialization.
 	sensitivity Sensitivity
+
+	mu   sync.Mutex
+	stop chan struct{}
+	wg   sync.WaitGroup
 }
 
 func (d *Dev) Mode() string {
@@ -161,19 +170,23 @@ func (d *Dev) makeDev(o *Opts) error {
 		return err
 	}
 	if o.Sensitivity != S2G { // default
-		err = d.setSensitivity(o.Sensitivity)
+		err = d.SetSensitivity(o.Sensitivity)
 		if err != nil {
 			return err
 		}
 	}
+	if o.DataRate != 0 {
+		if err = d.SetDataRate(o.DataRate); err != nil {
+			return err
+		}
+	}
 	// Verify that the device Id
-	tx := []byte{DeviceID | 0x80, 0x00}
-	rx := make([]byte, len(tx))
-	err = d.c.Tx(tx, rx)
+	var rx [1]byte
+	err = d.readRegs(DeviceID, rx[:])
 	if err != nil {
 		return fmt.Errorf("unable to read the deviceID \"%s\"", err.Error())
 	}
-	switch rx[1] {
+	switch rx[0] {
 	case Adxl345:
 		d.name = "adxl345"
 		return nil
@@ -181,21 +194,29 @@ func (d *Dev) makeDev(o *Opts) error {
 		d.name = fmt.Sprintf("expected%#x", o.ExpectedDeviceID)
 		return nil
 	default:
-		return fmt.Errorf("unrecognized device expected=\"%#02x\" or \"%#02x\"found=\"%#02x\" ", o.ExpectedDeviceID, Adxl345, rx)
+		return fmt.Errorf("unrecognized device expected=\"%#02x\" or \"%#02x\"found=\"%#02x\" ", o.ExpectedDeviceID, Adxl345, rx[0])
 	}
 }
 
 // SetSensitivity sets the sensitivity of the ADXL345.
-// The sensitivity parameter should be one of 2, 4, 8, or 16, representing ±2g, ±4g, ±8g, or ±16g respectively.
-func (d *Dev) setSensitivity(sensitivity Sensitivity) error {
+// The sensitivity parameter should be one of S2G, S4G, S8G or S16G, representing ±2g, ±4g, ±8g, or ±16g respectively.
+func (d *Dev) SetSensitivity(sensitivity Sensitivity) error {
 	switch sensitivity {
 	case S2G, S4G, S8G, S16G:
 		// Write to the DataFormat register
 		d.sensitivity = sensitivity
 		return d.Write(DataFormat, byte(sensitivity))
 	default:
-		return fmt.Errorf("invalid sensitivity: %d. Valid values are 2, 4, 8, 16", sensitivity)
+		return fmt.Errorf("invalid sensitivity: %d. Valid values are 2, 4, 8, 16", byte(sensitivity))
+	}
+}
+
+// SetDataRate sets the output data rate of the ADXL345.
+func (d *Dev) SetDataRate(r DataRate) error {
+	if r > Rate3200Hz {
+		return fmt.Errorf("invalid data rate: %#x", byte(r))
 	}
+	return d.Write(BwRate, byte(r))
 }
 
 // TurnOn turns on the measurement mode of the ADXL345.
@@ -211,40 +232,37 @@ func (d *Dev) TurnOff() error {
 
 // Update reads the acceleration values from the ADXL345.
 // By reading the acceleration the 3 axes acceleration values.
-// This is a simple synchronous implementation.
+// This is a simple synchronous implementation, errors are ignored. Use Sense
+// to get them.
 func (d *Dev) Update() Acceleration {
-	return Acceleration{
-		X: d.readAndCombine(DataX0, DataX1),
-		Y: d.readAndCombine(DataY0, DataY1),
-		Z: d.readAndCombine(DataZ0, DataZ1),
-	}
+	a, _ := d.Sense()
+	return a
 }
 
-// readAndCombine combines two registers to form a 16-bit value.
-// The ADXL345 uses two 8-bit registers to store the output data for each axis.
-// X := d.readAndCombine(DataX0, DataX1) where:
-// `DataX0` is the address of the lower byte (LSB, least significant byte)
-// `DataX1` is the address of the upper byte (MSB, most significant byte)
-// The ADXL345 combines both registers to deliver 16-bit output for each acceleration axis.
-// A similar approach is used for the Y and Z axes. This technique provides higher precision in the measurements.
-func (d *Dev) readAndCombine(reg1, reg2 byte) int16 {
-	low, _ := d.Read(reg1)
-	high, _ := d.Read(reg2)
-	return int16(uint16(high)<<8) | int16(low)
+// Sense reads the acceleration values of the 3 axes from the ADXL345.
+//
+// The 6 data registers are read in a single transaction so the values of the
+// 3 axes belong to the same sample.
+func (d *Dev) Sense() (Acceleration, error) {
+	var b [6]byte
+	if err := d.readRegs(DataX0, b[:]); err != nil {
+		return Acceleration{}, err
+	}
+	return Acceleration{
+		X: int16(binary.LittleEndian.Uint16(b[0:])),
+		Y: int16(binary.LittleEndian.Uint16(b[2:])),
+		Z: int16(binary.LittleEndian.Uint16(b[4:])),
+	}, nil
 }
 
-// Read reads a 16-bit value from the specified register address.
+// Read reads a 16-bit value from the specified register address and the
+// following one, in little endian.
 func (d *Dev) Read(regAddress byte) (int16, error) {
-	// Send a two-byte sequence:
-	// - The first byte contains the address with bit 7 set high to indicate read op
-	// - The second byte is a "don't care" value, usually zero
-	tx := []byte{regAddress | 0x80, 0x00}
-	rx := make([]byte, len(tx))
-	err := d.c.Tx(tx, rx)
-	if err != nil {
+	var b [2]byte
+	if err := d.readRegs(regAddress, b[:]); err != nil {
 		return 0, err
 	}
-	return int16(binary.LittleEndian.Uint16(rx)), nil
+	return int16(binary.LittleEndian.Uint16(b[:])), nil
 }
 
 // Write writes a 1 byte value to the specified register address.
@@ -252,6 +270,27 @@ func (d *Dev) Write(regAddress byte, value byte) error {
 	return d.c.Tx([]byte{regAddress, value}, nil)
 }
 
+// readRegs reads consecutive registers starting at regAddress.
+func (d *Dev) readRegs(regAddress byte, b []byte) error {
+	if !d.isSPI {
+		return d.c.Tx([]byte{regAddress}, b)
+	}
+	// On SPI, the first byte contains the address with bit 7 set high to
+	// indicate a read and bit 6 for a multi-byte read. The data is clocked out
+	// during the following "don't care" bytes.
+	tx := make([]byte, len(b)+1)
+	tx[0] = regAddress | 0x80
+	if len(b) > 1 {
+		tx[0] |= 0x40
+	}
+	rx := make([]byte, len(tx))
+	if err := d.c.Tx(tx, rx); err != nil {
+		return err
+	}
+	copy(b, rx[1:])
+	return nil
+}
+
 // Acceleration represents the acceleration on the three axes X,Y,Z.
 // The sensitivity can be set to different levels: ±2g, ±4g, ±8g, or ±16g. (S2G, S4G, S8G, S16G)
 // The output are 16-bit integers, so the device measures between -32768 and +32767 for each axis.
@@ -282,6 +321,30 @@ func (s Sensitivity) String() string {
 	case S16G:
 		return "+/-16g"
 	default:
-		return fmt.Sprintf("unknown sensitivity: %#x", s)
+		return fmt.Sprintf("unknown sensitivity: %#x", byte(s))
 	}
 }
+
+// DataRate is the output data rate of the ADXL345, as set in the BwRate
+// register.
+type DataRate byte
+
+// Output data rates.
+const (
+	Rate0_10Hz DataRate = 0x00
+	Rate0_20Hz DataRate = 0x01
+	Rate0_39Hz DataRate = 0x02
+	Rate0_78Hz DataRate = 0x03
+	Rate1_56Hz DataRate = 0x04
+	Rate3_13Hz DataRate = 0x05
+	Rate6_25Hz DataRate = 0x06
+	Rate12_5Hz DataRate = 0x07
+	Rate25Hz   DataRate = 0x08
+	Rate50Hz   DataRate = 0x09
+	Rate100Hz  DataRate = 0x0A // Default
+	Rate200Hz  DataRate = 0x0B
+	Rate400Hz  DataRate = 0x0C
+	Rate800Hz  DataRate = 0x0D
+	Rate1600Hz DataRate = 0x0E
+	Rate3200Hz DataRate = 0x0F
+)
@@ -0,0 +1,202 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package adxl345
+
+import (
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3/conntest"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpiotest"
+	"periph.io/x/conn/v3/i2c/i2ctest"
+	"periph.io/x/conn/v3/spi/spitest"
+)
+
+const addr = 0x53
+
+var initOps = []i2ctest.IO{
+	{Addr: addr, W: []byte{PowerCtl, 0x08}},
+	{Addr: addr, W: []byte{DeviceID}, R: []byte{Adxl345}},
+}
+
+func TestNewI2C(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: addr, W: []byte{PowerCtl, 0x08}},
+			{Addr: addr, W: []byte{DataFormat, byte(S8G)}},
+			{Addr: addr, W: []byte{BwRate, byte(Rate400Hz)}},
+			{Addr: addr, W: []byte{DeviceID}, R: []byte{Adxl345}},
+			{Addr: addr, W: []byte{DataX0}, R: []byte{0x01, 0x00, 0xFF, 0xFF, 0x00, 0x01}},
+		},
+	}
+	d, err := NewI2C(&bus, addr, &Opts{Sensitivity: S8G, DataRate: Rate400Hz})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := d.String(); s != "adxl345{Sensitivity:+/-8g, Mode:I²C}" {
+		t.Fatal(s)
+	}
+	if a := d.Update(); a != (Acceleration{X: 1, Y: -1, Z: 256}) {
+		t.Fatal(a)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewSpi(t *testing.T) {
+	port := spitest.Playback{
+		Playback: conntest.Playback{
+			Ops: []conntest.IO{
+				{W: []byte{PowerCtl, 0x08}},
+				{W: []byte{DeviceID | 0x80, 0x00}, R: []byte{0x00, Adxl345}},
+				{W: []byte{DataX0 | 0xC0, 0, 0, 0, 0, 0, 0}, R: []byte{0x00, 0x02, 0x00, 0x00, 0x80, 0xFE, 0xFF}},
+			},
+		},
+	}
+	d, err := NewSpi(&port, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := d.Sense()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a != (Acceleration{X: 2, Y: -32768, Z: -2}) {
+		t.Fatal(a)
+	}
+	if err := port.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: append(initOps,
+			// ConfigureTap
+			i2ctest.IO{Addr: addr, W: []byte{ThreshTap, 48}},
+			i2ctest.IO{Addr: addr, W: []byte{Dur, 16}},
+			i2ctest.IO{Addr: addr, W: []byte{Latent, 80}},
+			i2ctest.IO{Addr: addr, W: []byte{Window, 200}},
+			i2ctest.IO{Addr: addr, W: []byte{TapAxes, 0x01}},
+			// ConfigureActivity
+			i2ctest.IO{Addr: addr, W: []byte{ThreshAct, 16}},
+			i2ctest.IO{Addr: addr, W: []byte{ActInactCtl}, R: []byte{0x07}},
+			i2ctest.IO{Addr: addr, W: []byte{ActInactCtl, 0xF7}},
+			// ConfigureFreeFall
+			i2ctest.IO{Addr: addr, W: []byte{ThreshFf, 6}},
+			i2ctest.IO{Addr: addr, W: []byte{TimeFf, 40}},
+			// EnableInterrupts
+			i2ctest.IO{Addr: addr, W: []byte{IntEnable, 0}},
+			i2ctest.IO{Addr: addr, W: []byte{IntMap, 0x04}},
+			i2ctest.IO{Addr: addr, W: []byte{IntEnable, 0x64}},
+		),
+	}
+	d, err := NewI2C(&bus, addr, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tap := TapOpts{
+		Axes:      AxisZ,
+		Threshold: 3000,
+		Duration:  10 * time.Millisecond,
+		Latency:   100 * time.Millisecond,
+		Window:    250 * time.Millisecond,
+	}
+	if err := d.ConfigureTap(&tap); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.ConfigureActivity(&ActivityOpts{Axes: AxisAll, Threshold: 1000, ACCoupled: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.ConfigureFreeFall(375, 200*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.EnableInterrupts(SingleTap|DoubleTap|FreeFall, FreeFall); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.ConfigureFreeFall(17000, 0); err == nil {
+		t.Fatal("threshold out of range")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatchEvents(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: append(initOps,
+			i2ctest.IO{Addr: addr, W: []byte{IntSource}, R: []byte{0x60}},
+			i2ctest.IO{Addr: addr, W: []byte{IntSource}, R: []byte{0x00}},
+		),
+		DontPanic: true,
+	}
+	d, err := NewI2C(&bus, addr, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	intPin := &gpiotest.Pin{N: "INT1", EdgesChan: make(chan gpio.Level, 1)}
+	events, err := d.WatchEvents(intPin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	intPin.EdgesChan <- gpio.High
+	if e := <-events; e != SingleTap|DoubleTap {
+		t.Fatal(e)
+	}
+	if err := d.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := <-events; ok {
+		t.Fatal("channel must be closed")
+	}
+}
+
+func TestStreamFIFO(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: append(initOps,
+			i2ctest.IO{Addr: addr, W: []byte{FifoCtl, 0x82}},
+			i2ctest.IO{Addr: addr, W: []byte{IntEnable, 0}},
+			i2ctest.IO{Addr: addr, W: []byte{IntMap, 0}},
+			i2ctest.IO{Addr: addr, W: []byte{IntEnable, 0x02}},
+			i2ctest.IO{Addr: addr, W: []byte{FifoStatus}, R: []byte{0x02}},
+			i2ctest.IO{Addr: addr, W: []byte{DataX0}, R: []byte{1, 0, 0, 0, 0, 0}},
+			i2ctest.IO{Addr: addr, W: []byte{DataX0}, R: []byte{2, 0, 0, 0, 0, 0}},
+			i2ctest.IO{Addr: addr, W: []byte{FifoStatus}, R: []byte{0x00}},
+		),
+		DontPanic: true,
+	}
+	d, err := NewI2C(&bus, addr, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	intPin := &gpiotest.Pin{N: "INT1", EdgesChan: make(chan gpio.Level, 1)}
+	samples, err := d.StreamFIFO(intPin, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	intPin.EdgesChan <- gpio.High
+	for i := int16(1); i <= 2; i++ {
+		if a := <-samples; a.X != i {
+			t.Fatal(a)
+		}
+	}
+	if err := d.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.StreamFIFO(intPin, 0); err == nil {
+		t.Fatal("watermark must be at least 1")
+	}
+}
+
+func TestEvent_String(t *testing.T) {
+	if s := (SingleTap | Overrun).String(); s != "Overrun|SingleTap" {
+		t.Fatal(s)
+	}
+	if s := Event(0).String(); s != "0" {
+		t.Fatal(s)
+	}
+}
@@ -2,7 +2,12 @@
 // Use of this source code is governed under the Apache License, Version 2.0
 // that can be found in the LICENSE file.
 
-// Package adxl345 controls an ADXL345 3-axis accelerometer over SPI.
+// Package adxl345 controls an ADXL345 3-axis accelerometer over I²C or SPI.
+//
+// Besides reading the acceleration, the tap, double tap, activity,
+// inactivity and free-fall detection can be configured. The events and the
+// samples collected in the FIFO can be received on a channel with WatchEvents
+// and StreamFIFO, driven by the INT pins of the device instead of polling.
 //
 // # Datasheet
 //
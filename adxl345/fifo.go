@@ -0,0 +1,93 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package adxl345
+
+import (
+	"errors"
+
+	"periph.io/x/conn/v3/gpio"
+)
+
+// FIFOMode is the operating mode of the 32 samples FIFO.
+type FIFOMode byte
+
+// FIFO modes.
+const (
+	// Bypass disables the FIFO.
+	Bypass FIFOMode = 0x00
+	// FIFO collects samples until it is full, then stops.
+	FIFO FIFOMode = 0x40
+	// Stream collects samples, discarding the oldest ones when it is full.
+	Stream FIFOMode = 0x80
+	// Trigger keeps the last samples before a trigger event and then
+	// collects samples until it is full.
+	Trigger FIFOMode = 0xC0
+)
+
+// ConfigureFIFO sets the FIFO mode and the number of samples, 0 to 31, that
+// trigger the Watermark event.
+func (d *Dev) ConfigureFIFO(mode FIFOMode, samples int) error {
+	if samples < 0 || samples > 31 {
+		return errors.New("adxl345: FIFO watermark must be between 0 and 31")
+	}
+	return d.Write(FifoCtl, byte(mode&0xC0)|byte(samples))
+}
+
+// ReadFIFO returns the samples currently held in the FIFO, oldest first.
+func (d *Dev) ReadFIFO() ([]Acceleration, error) {
+	var b [1]byte
+	if err := d.readRegs(FifoStatus, b[:]); err != nil {
+		return nil, err
+	}
+	n := int(b[0] & 0x3F)
+	out := make([]Acceleration, 0, n)
+	for i := 0; i < n; i++ {
+		// Each read of the 6 data registers pops a sample.
+		a, err := d.Sense()
+		if err != nil {
+			return out, err
+		}
+		out = append(out, a)
+	}
+	return out, nil
+}
+
+// StreamFIFO puts the FIFO in Stream mode and sends the samples on the
+// returned channel each time it holds at least watermark samples.
+//
+// intPin is the host pin wired to the device pin the Watermark event is routed
+// to. The Watermark event is enabled on INT1 and the other interrupt sources
+// are disabled. Call Halt to stop; it closes the channel. Calling WatchEvents
+// or StreamFIFO again stops the previous reader.
+func (d *Dev) StreamFIFO(intPin gpio.PinIn, watermark int) (<-chan Acceleration, error) {
+	if watermark < 1 {
+		return nil, errors.New("adxl345: FIFO watermark must be between 1 and 31")
+	}
+	if err := d.ConfigureFIFO(Stream, watermark); err != nil {
+		return nil, err
+	}
+	if err := d.EnableInterrupts(Watermark, 0); err != nil {
+		return nil, err
+	}
+	samples := make(chan Acceleration, 32)
+	err := d.startReader(intPin, func(stop <-chan struct{}) bool {
+		s, err := d.ReadFIFO()
+		if err != nil || len(s) == 0 {
+			return false
+		}
+		for _, a := range s {
+			select {
+			case samples <- a:
+			case <-stop:
+				return false
+			}
+		}
+		return true
+	}, func() { close(samples) })
+	if err != nil {
+		return nil, err
+	}
+	return samples, nil
+}
@@ -0,0 +1,336 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package adxl345
+
+import (
+	"fmt"
+	"time"
+
+	"periph.io/x/conn/v3/gpio"
+)
+
+// Event is a set of interrupt sources, as found in the IntEnable, IntMap and
+// IntSource registers.
+type Event byte
+
+// Interrupt sources.
+const (
+	Overrun    Event = 0x01 // FIFO overrun, samples were lost
+	Watermark  Event = 0x02 // FIFO holds at least the configured samples
+	FreeFall   Event = 0x04
+	Inactivity Event = 0x08
+	Activity   Event = 0x10
+	DoubleTap  Event = 0x20
+	SingleTap  Event = 0x40
+	DataReady  Event = 0x80
+)
+
+func (e Event) String() string {
+	if e == 0 {
+		return "0"
+	}
+	out := ""
+	for i, n := range eventNames {
+		if e&(1<<uint(i)) != 0 {
+			if out != "" {
+				out += "|"
+			}
+			out += n
+		}
+	}
+	return out
+}
+
+var eventNames = [...]string{"Overrun", "Watermark", "FreeFall", "Inactivity", "Activity", "DoubleTap", "SingleTap", "DataReady"}
+
+// Axis is a set of axes participating in tap or activity detection.
+type Axis byte
+
+// Axes.
+const (
+	AxisZ Axis = 0x01
+	AxisY Axis = 0x02
+	AxisX Axis = 0x04
+	// AxisAll enables the detection on the 3 axes.
+	AxisAll = AxisX | AxisY | AxisZ
+)
+
+// TapOpts configures the single and double tap detection.
+type TapOpts struct {
+	// Axes participating in the detection.
+	Axes Axis
+	// Threshold is the minimum acceleration in mg, with a resolution of
+	// 62.5mg, up to 16g.
+	Threshold int
+	// Duration is the maximum time the acceleration stays above Threshold,
+	// with a resolution of 625µs, up to 159ms.
+	Duration time.Duration
+	// Latency is the wait time after a tap before the double tap window
+	// starts, with a resolution of 1.25ms, up to 318ms. Use 0 to disable
+	// double tap detection.
+	Latency time.Duration
+	// Window is the time after Latency during which a second tap is detected,
+	// with a resolution of 1.25ms, up to 318ms. Use 0 to disable double tap
+	// detection.
+	Window time.Duration
+}
+
+// ConfigureTap configures the single and double tap detection.
+//
+// The SingleTap and DoubleTap events must be enabled with EnableInterrupts.
+func (d *Dev) ConfigureTap(o *TapOpts) error {
+	thresh, err := toRegister(o.Threshold, 625, 10, "tap threshold")
+	if err != nil {
+		return err
+	}
+	dur, err := toRegister(int(o.Duration/time.Microsecond), 625, 1, "tap duration")
+	if err != nil {
+		return err
+	}
+	latent, err := toRegister(int(o.Latency/time.Microsecond), 1250, 1, "tap latency")
+	if err != nil {
+		return err
+	}
+	window, err := toRegister(int(o.Window/time.Microsecond), 1250, 1, "tap window")
+	if err != nil {
+		return err
+	}
+	return d.writeRegs(
+		ThreshTap, thresh,
+		Dur, dur,
+		Latent, latent,
+		Window, window,
+		TapAxes, byte(o.Axes&AxisAll),
+	)
+}
+
+// ActivityOpts configures the activity or inactivity detection.
+type ActivityOpts struct {
+	// Axes participating in the detection.
+	Axes Axis
+	// Threshold is the acceleration in mg above which activity is detected, or
+	// below which inactivity is detected, with a resolution of 62.5mg, up to
+	// 16g.
+	Threshold int
+	// Time is the duration the acceleration must stay below Threshold for
+	// inactivity to be detected, with a resolution of 1s, up to 255s. It is
+	// ignored for activity detection.
+	Time time.Duration
+	// ACCoupled compares the acceleration with the one at the start of the
+	// detection instead of with 0g, so the gravity doesn't matter.
+	ACCoupled bool
+}
+
+// ConfigureActivity configures the activity detection.
+//
+// The Activity event must be enabled with EnableInterrupts.
+func (d *Dev) ConfigureActivity(o *ActivityOpts) error {
+	thresh, err := toRegister(o.Threshold, 625, 10, "activity threshold")
+	if err != nil {
+		return err
+	}
+	if err := d.Write(ThreshAct, thresh); err != nil {
+		return err
+	}
+	ctl := byte(o.Axes&AxisAll) << 4
+	if o.ACCoupled {
+		ctl |= 0x80
+	}
+	return d.updateReg(ActInactCtl, 0xF0, ctl)
+}
+
+// ConfigureInactivity configures the inactivity detection.
+//
+// The Inactivity event must be enabled with EnableInterrupts.
+func (d *Dev) ConfigureInactivity(o *ActivityOpts) error {
+	thresh, err := toRegister(o.Threshold, 625, 10, "inactivity threshold")
+	if err != nil {
+		return err
+	}
+	t, err := toRegister(int(o.Time/time.Second), 1, 1, "inactivity time")
+	if err != nil {
+		return err
+	}
+	if err := d.writeRegs(ThreshInact, thresh, TimeInact, t); err != nil {
+		return err
+	}
+	ctl := byte(o.Axes & AxisAll)
+	if o.ACCoupled {
+		ctl |= 0x08
+	}
+	return d.updateReg(ActInactCtl, 0x0F, ctl)
+}
+
+// ConfigureFreeFall configures the free-fall detection, which triggers when
+// the acceleration on all the axes stays below threshold, in mg with a
+// resolution of 62.5mg, for at least t, with a resolution of 5ms up to 1.275s.
+//
+// The datasheet recommends a threshold between 300mg and 600mg and a time
+// between 100ms and 350ms. The FreeFall event must be enabled with
+// EnableInterrupts.
+func (d *Dev) ConfigureFreeFall(threshold int, t time.Duration) error {
+	thresh, err := toRegister(threshold, 625, 10, "free-fall threshold")
+	if err != nil {
+		return err
+	}
+	tff, err := toRegister(int(t/time.Millisecond), 5, 1, "free-fall time")
+	if err != nil {
+		return err
+	}
+	return d.writeRegs(ThreshFf, thresh, TimeFf, tff)
+}
+
+// EnableInterrupts enables the interrupt sources in e. The sources also set
+// in int2 are routed to the INT2 pin, the others to the INT1 pin.
+//
+// The INT pins are active high.
+func (d *Dev) EnableInterrupts(e, int2 Event) error {
+	// Disable the interrupts while changing the mapping, as recommended by
+	// the datasheet.
+	return d.writeRegs(IntEnable, 0, IntMap, byte(int2), IntEnable, byte(e))
+}
+
+// Events reads and clears the pending interrupt sources.
+//
+// DataReady, Watermark and Overrun are only cleared once the data is read.
+func (d *Dev) Events() (Event, error) {
+	var b [1]byte
+	if err := d.readRegs(IntSource, b[:]); err != nil {
+		return 0, err
+	}
+	return Event(b[0]), nil
+}
+
+// TapSource returns the axes involved in the last tap or activity event.
+//
+// The first returned value is for taps, the second for activity.
+func (d *Dev) TapSource() (Axis, Axis, error) {
+	var b [1]byte
+	if err := d.readRegs(ActTapStatus, b[:]); err != nil {
+		return 0, 0, err
+	}
+	return Axis(b[0]) & AxisAll, Axis(b[0]>>4) & AxisAll, nil
+}
+
+// WatchEvents starts reading the interrupt sources each time intPin, wired to
+// the INT1 or INT2 pin of the device, is asserted and sends them on the
+// returned channel.
+//
+// The sources must be enabled with EnableInterrupts beforehand. Call Halt to
+// stop; it closes the channel. Calling WatchEvents or StreamFIFO again stops
+// the previous reader.
+func (d *Dev) WatchEvents(intPin gpio.PinIn) (<-chan Event, error) {
+	events := make(chan Event, 8)
+	err := d.startReader(intPin, func(stop <-chan struct{}) bool {
+		e, err := d.Events()
+		if err != nil || e == 0 {
+			return false
+		}
+		select {
+		case events <- e:
+		case <-stop:
+		}
+		return true
+	}, func() { close(events) })
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// Halt stops the reader started by WatchEvents or StreamFIFO, if any.
+func (d *Dev) Halt() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.stopReader()
+	return nil
+}
+
+//
+
+// interruptPoll is how often the reader checks if it must stop.
+var interruptPoll = 100 * time.Millisecond
+
+// startReader starts a goroutine calling service each time intPin is
+// asserted, until it returns false.
+func (d *Dev) startReader(intPin gpio.PinIn, service func(stop <-chan struct{}) bool, done func()) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.stopReader()
+	if err := intPin.In(gpio.PullNoChange, gpio.RisingEdge); err != nil {
+		return err
+	}
+	stop := make(chan struct{})
+	d.stop = stop
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		defer done()
+		// The interrupt may already be asserted, in which case there would
+		// be no edge.
+		for d.serviceWhileAsserted(intPin, service, stop) {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			intPin.WaitForEdge(interruptPoll)
+		}
+	}()
+	return nil
+}
+
+// serviceWhileAsserted calls service while intPin is asserted. It returns
+// false if the reader must stop.
+func (d *Dev) serviceWhileAsserted(intPin gpio.PinIn, service func(stop <-chan struct{}) bool, stop <-chan struct{}) bool {
+	for intPin.Read() == gpio.High {
+		select {
+		case <-stop:
+			return false
+		default:
+		}
+		if !service(stop) {
+			break
+		}
+	}
+	return true
+}
+
+func (d *Dev) stopReader() {
+	if d.stop != nil {
+		close(d.stop)
+		d.stop = nil
+		d.wg.Wait()
+	}
+}
+
+// writeRegs writes pairs of register address and value.
+func (d *Dev) writeRegs(regValues ...byte) error {
+	for i := 0; i < len(regValues); i += 2 {
+		if err := d.Write(regValues[i], regValues[i+1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateReg changes the bits in mask of a register.
+func (d *Dev) updateReg(regAddress, mask, value byte) error {
+	var b [1]byte
+	if err := d.readRegs(regAddress, b[:]); err != nil {
+		return err
+	}
+	return d.Write(regAddress, b[0]&^mask|value&mask)
+}
+
+// toRegister converts v to a 8 bits register value with a resolution of
+// num/den units per LSB.
+func toRegister(v, num, den int, name string) (byte, error) {
+	r := (v*den + num/2) / num
+	if v < 0 || r > 255 {
+		return 0, fmt.Errorf("adxl345: %s out of range", name)
+	}
+	return byte(r), nil
+}
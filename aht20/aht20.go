@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"periph.io/x/conn/v3/i2c"
 	"periph.io/x/conn/v3/physic"
+	"periph.io/x/devices/v3"
 	"sync"
 	"time"
 )
@@ -39,6 +40,8 @@ type Dev struct {
 	mu   sync.Mutex
 	stop chan struct{}
 	wg   sync.WaitGroup
+	// Communication health, reported by the devices.Health methods.
+	health devices.HealthCounter
 }
 
 // Opts holds the configuration options for the device.
@@ -90,7 +93,7 @@ func (d *Dev) Sense(e *physic.Env) error {
 	defer d.mu.Unlock()
 
 	// trigger measurement
-	if err := d.d.Tx(argsMeasure, nil); err != nil {
+	if err := d.tx(argsMeasure, nil); err != nil {
 		return err
 	}
 	time.Sleep(80 * time.Millisecond) // wait for 80ms according to datasheet
@@ -100,14 +103,14 @@ func (d *Dev) Sense(e *physic.Env) error {
 	for d.opts.MeasurementReadTimeout <= 0 || time.Now().Before(end) {
 
 		// read measurement
-		if err := d.d.Tx(nil, data); err != nil {
+		if err := d.tx(nil, data); err != nil {
 			return err
 		}
 
 		// validate data
 		if d.opts.ValidateData {
 			if dataCrc := calculateCRC8(data[:6]); dataCrc != data[6] {
-				return &DataCorruptionError{Received: data[6], Calculated: dataCrc}
+				return d.health.RecordCRCError(&DataCorruptionError{Received: data[6], Calculated: dataCrc})
 			}
 		}
 
@@ -171,7 +174,7 @@ func (d *Dev) Precision(e *physic.Env) {
 
 // SoftReset resets the sensor. It includes a reboot and a re-calibration.
 func (d *Dev) SoftReset() error {
-	if err := d.d.Tx([]byte{cmdSoftReset}, nil); err != nil {
+	if err := d.tx([]byte{cmdSoftReset}, nil); err != nil {
 		return err
 	}
 	time.Sleep(20 * time.Millisecond) // wait for 20ms according to datasheet
@@ -194,7 +197,7 @@ func (d *Dev) Halt() error {
 // IsInitialized returns true if the sensor is initialized (calibrated)
 func (d *Dev) IsInitialized() (error, bool) {
 	data := make([]byte, 1)
-	if err := d.d.Tx([]byte{cmdStatus}, data); err != nil {
+	if err := d.tx([]byte{cmdStatus}, data); err != nil {
 		return err, false
 	}
 	return nil, (data[0] & bitInitialized) != 0
@@ -202,13 +205,33 @@ func (d *Dev) IsInitialized() (error, bool) {
 
 // Initialize calibrates the sensor. It takes 10ms.
 func (d *Dev) Initialize() error {
-	if err := d.d.Tx(argsInitialize, nil); err != nil {
+	if err := d.tx(argsInitialize, nil); err != nil {
 		return err
 	}
 	time.Sleep(10 * time.Millisecond) // wait for 10ms according to datasheet
 	return nil
 }
 
+// LastError implements devices.Health.
+func (d *Dev) LastError() error {
+	return d.health.LastError()
+}
+
+// TxCount implements devices.Health.
+func (d *Dev) TxCount() uint64 {
+	return d.health.TxCount()
+}
+
+// CRCErrorCount implements devices.Health.
+func (d *Dev) CRCErrorCount() uint64 {
+	return d.health.CRCErrorCount()
+}
+
+// tx runs a transaction on the bus and records it.
+func (d *Dev) tx(w, r []byte) error {
+	return d.health.Record(d.d.Tx(w, r))
+}
+
 func calculateCRC8(data []byte) uint8 {
 	var crc uint8 = 0xFF // initial value according to datasheet
 
@@ -225,3 +248,5 @@ func calculateCRC8(data []byte) uint8 {
 
 	return crc
 }
+
+var _ devices.Health = &Dev{}
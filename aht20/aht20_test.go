@@ -162,6 +162,39 @@ func TestDev_Sense_error(t *testing.T) {
 	}
 }
 
+func TestDev_Health(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: deviceAddress, W: argsMeasure},
+			{Addr: deviceAddress, R: []byte{byteStatusInitialized, 0x75, 0x52, 0x05, 0x8E, 0x40, 0x7F}},
+			{Addr: deviceAddress, W: argsMeasure},
+			{Addr: deviceAddress, R: []byte{byteStatusInitialized, 0x75, 0x52, 0x05, 0x8E, 0x40, 0x7E}},
+		},
+		DontPanic: true,
+	}
+	dev := Dev{d: &i2c.Dev{Bus: &bus, Addr: deviceAddress}, opts: DefaultOpts}
+	e := physic.Env{}
+	if err := dev.Sense(&e); err != nil {
+		t.Fatal(err)
+	}
+	if dev.LastError() != nil || dev.TxCount() != 2 || dev.CRCErrorCount() != 0 {
+		t.Fatal(dev.LastError(), dev.TxCount(), dev.CRCErrorCount())
+	}
+	if err := dev.Sense(&e); err == nil {
+		t.Fatal("expected error")
+	}
+	if _, ok := dev.LastError().(*DataCorruptionError); !ok || dev.TxCount() != 4 || dev.CRCErrorCount() != 1 {
+		t.Fatal(dev.LastError(), dev.TxCount(), dev.CRCErrorCount())
+	}
+	// The playback is exhausted.
+	if err := dev.Sense(&e); err == nil {
+		t.Fatal("expected error")
+	}
+	if dev.LastError() == nil || dev.TxCount() != 5 || dev.CRCErrorCount() != 1 {
+		t.Fatal(dev.LastError(), dev.TxCount(), dev.CRCErrorCount())
+	}
+}
+
 func TestDev_SoftReset(t *testing.T) {
 	bus := i2ctest.Playback{
 		Ops: []i2ctest.IO{
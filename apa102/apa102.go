@@ -34,6 +34,29 @@ func ToRGB(p []color.NRGBA) []byte {
 // you specified, without temperature correction.
 const NeutralTemp uint16 = 6500
 
+// ColorOrder is the order in which the color channels are sent to each LED,
+// after the global brightness.
+//
+// Genuine APA102 use BGR. Some clones, like some SK9822 and APA107 strips,
+// use another order.
+type ColorOrder uint8
+
+const (
+	BGR ColorOrder = iota // Genuine APA102
+	BRG
+	GBR
+	GRB
+	RBG
+	RGB
+)
+
+func (c ColorOrder) String() string {
+	if c > RGB {
+		return "ColorOrder(?)"
+	}
+	return [...]string{"BGR", "BRG", "GBR", "GRB", "RBG", "RGB"}[c]
+}
+
 // DefaultOpts is the recommended default options.
 var DefaultOpts = Opts{
 	NumPixels:        150,   // 150 LEDs is a common strip length.
@@ -81,6 +104,14 @@ type Opts struct {
 	// to 8 bits, this also disables the dynamic perceptual mapping of intensity
 	// since there is not enough bits of resolution to do it effectively.
 	DisableGlobalPWM bool
+	// Order is the order of the color channels expected by the LEDs. The zero
+	// value is the APA102 order.
+	Order ColorOrder
+	// Dither enables temporal dithering: the rounding error of each channel
+	// is carried over to the next frame, so that the average output matches
+	// the requested intensity. This smooths the steps at low brightness but
+	// requires to refresh the LEDs continuously, see Dev.Refresh.
+	Dither bool
 }
 
 // New returns a strip that communicates over SPI to APA102 LEDs.
@@ -93,6 +124,9 @@ type Opts struct {
 // https://en.wikipedia.org/wiki/Flicker_fusion_threshold is a recommended
 // reading.
 func New(p spi.Port, o *Opts) (*Dev, error) {
+	if o.Order > RGB {
+		return nil, errors.New("apa102: invalid color order")
+	}
 	c, err := p.Connect(20*physic.MegaHertz, spi.Mode3, 8)
 	if err != nil {
 		return nil, err
@@ -109,10 +143,13 @@ func New(p spi.Port, o *Opts) (*Dev, error) {
 		Intensity:        o.Intensity,
 		Temperature:      o.Temperature,
 		DisableGlobalPWM: o.DisableGlobalPWM,
+		Order:            o.Order,
+		Dither:           o.Dither,
 		s:                c,
 		numPixels:        o.NumPixels,
 		rawBuf:           buf,
 		pixels:           buf[4 : 4+4*o.NumPixels],
+		rgb:              make([]byte, 3*o.NumPixels),
 		rect:             image.Rect(0, 0, o.NumPixels, 1),
 	}, nil
 }
@@ -141,12 +178,27 @@ type Dev struct {
 	//
 	// Takes effect on the next Draw() or Write() call.
 	DisableGlobalPWM bool
+	// Order is the order of the color channels.
+	//
+	// See Opts.Order for more information.
+	//
+	// Takes effect on the next Draw() or Write() call.
+	Order ColorOrder
+	// Dither enables temporal dithering.
+	//
+	// See Opts.Dither for more information.
+	//
+	// Takes effect on the next Draw(), Write() or Refresh() call.
+	Dither bool
 
 	s         spi.Conn        //
 	l         lut             // Updated at each .Write() call.
 	numPixels int             //
 	rawBuf    []byte          // Raw buffer sent over SPI. Cached to reduce heap fragmentation.
 	pixels    []byte          // Double buffer of pixels, to enable partial painting via Draw(). Effectively points inside rawBuf.
+	rgb       []byte          // RGB pixels as drawn, before correction.
+	errs      []uint16        // Dithering error carried over, per channel. Allocated on first use.
+	seg       []byte          // Scratch buffer for Segment transfers.
 	rect      image.Rectangle // Device bounds
 }
 
@@ -170,21 +222,9 @@ func (d *Dev) Bounds() image.Rectangle {
 // Using something else than image.NRGBA is 10x slower. When using image.NRGBA,
 // the alpha channel is ignored.
 func (d *Dev) Draw(r image.Rectangle, src image.Image, sp image.Point) error {
-	if r = r.Intersect(d.rect); r.Empty() {
+	if !d.draw(r.Intersect(d.rect), src, sp) {
 		return nil
 	}
-	srcR := src.Bounds()
-	srcR.Min = srcR.Min.Add(sp)
-	if dX := r.Dx(); dX < srcR.Dx() {
-		srcR.Max.X = srcR.Min.X + dX
-	}
-	if dY := r.Dy(); dY < srcR.Dy() {
-		srcR.Max.Y = srcR.Min.Y + dY
-	}
-	if srcR.Empty() {
-		return nil
-	}
-	d.rasterImg(d.pixels, r, src, srcR)
 	return d.s.Tx(d.rawBuf, nil)
 }
 
@@ -195,13 +235,41 @@ func (d *Dev) Write(pixels []byte) (int, error) {
 		return 0, errors.New("apa102: invalid RGB stream length")
 	}
 	// Do not touch header and footer.
-	d.raster(d.pixels, pixels, false)
+	d.raster(0, pixels, false)
 	err := d.s.Tx(d.rawBuf, nil)
 	return len(pixels), err
 }
 
+// Refresh sends the pixels drawn again, applying the current Intensity,
+// Temperature and Order.
+//
+// With Dither, call it continuously, at 100Hz or more, so the dithering is
+// not visible.
+func (d *Dev) Refresh() error {
+	d.encode(0, d.numPixels)
+	return d.s.Tx(d.rawBuf, nil)
+}
+
+// Segment returns the pixels [start, end) of the strip, which can be
+// updated without sending the pixels after end.
+//
+// This saves SPI bandwidth when updating the beginning of a long strip. The
+// pixels before start are sent too, as the data is shifted through each LED.
+func (d *Dev) Segment(start, end int) (*Segment, error) {
+	if start < 0 || end > d.numPixels || start >= end {
+		return nil, errors.New("apa102: invalid segment")
+	}
+	return &Segment{d: d, start: start, end: end}, nil
+}
+
 // Halt turns off all the lights.
 func (d *Dev) Halt() error {
+	for i := range d.rgb {
+		d.rgb[i] = 0
+	}
+	for i := range d.errs {
+		d.errs[i] = 0
+	}
 	// Zap out the buffer.
 	for i := range d.pixels {
 		if i&3 == 0 {
@@ -214,71 +282,204 @@ func (d *Dev) Halt() error {
 	return d.s.Tx(d.rawBuf, nil)
 }
 
-// raster serializes a buffer of RGB bytes to the APA102 SPI format.
+// Segment is a range of pixels of a Dev.
 //
-// It is expected to be given the part where pixels are, not the header nor
-// footer.
+// Drawing a Segment sends the pixels up to its end, terminated by zeros so
+// the following LEDs are not changed.
+type Segment struct {
+	d          *Dev
+	start, end int
+}
+
+func (s *Segment) String() string {
+	return fmt.Sprintf("%s[%d:%d]", s.d, s.start, s.end)
+}
+
+// ColorModel implements display.Drawer. It is color.NRGBAModel.
+func (s *Segment) ColorModel() color.Model {
+	return color.NRGBAModel
+}
+
+// Bounds implements display.Drawer. Min is guaranteed to be {0, 0}.
+func (s *Segment) Bounds() image.Rectangle {
+	return image.Rect(0, 0, s.end-s.start, 1)
+}
+
+// Draw implements display.Drawer.
+func (s *Segment) Draw(r image.Rectangle, src image.Image, sp image.Point) error {
+	r = r.Intersect(s.Bounds()).Add(image.Pt(s.start, 0))
+	if !s.d.draw(r, src, sp) {
+		return nil
+	}
+	return s.d.txPrefix(s.end)
+}
+
+// Write accepts a stream of raw RGB pixels for the segment and sends it.
+func (s *Segment) Write(pixels []byte) (int, error) {
+	if len(pixels)%3 != 0 || len(pixels) > 3*(s.end-s.start) {
+		return 0, errors.New("apa102: invalid RGB stream length")
+	}
+	s.d.raster(s.start, pixels, false)
+	return len(pixels), s.d.txPrefix(s.end)
+}
+
+// Halt implements conn.Resource. It turns off the lights of the segment.
+func (s *Segment) Halt() error {
+	_, err := s.Write(make([]byte, 3*(s.end-s.start)))
+	return err
+}
+
+// draw copies src into the pixels in r, which must be within the bounds. It
+// returns false if there is nothing to draw.
+func (d *Dev) draw(r image.Rectangle, src image.Image, sp image.Point) bool {
+	if r.Empty() {
+		return false
+	}
+	srcR := src.Bounds()
+	srcR.Min = srcR.Min.Add(sp)
+	if dX := r.Dx(); dX < srcR.Dx() {
+		srcR.Max.X = srcR.Min.X + dX
+	}
+	if dY := r.Dy(); dY < srcR.Dy() {
+		srcR.Max.Y = srcR.Min.Y + dY
+	}
+	if srcR.Empty() {
+		return false
+	}
+	d.rasterImg(r, src, srcR)
+	return true
+}
+
+// txPrefix sends the first n pixels, followed by an end frame of zeros
+// instead of ones so that the next LED doesn't interpret it as a LED frame.
+func (d *Dev) txPrefix(n int) error {
+	if d.seg == nil {
+		d.seg = make([]byte, len(d.rawBuf))
+	}
+	l := 4 + 4*n
+	b := d.seg[:l+n/2/8+1]
+	copy(b, d.rawBuf[:l])
+	for i := l; i < len(b); i++ {
+		b[i] = 0
+	}
+	return d.s.Tx(b, nil)
+}
+
+// raster stores a buffer of RGB bytes in the back buffer starting at pixel
+// off and serializes it to the APA102 SPI format.
 //
-// dst is in APA102 SPI 32 bits word format. src is in RGB 24 bits, or 32 bits
-// word format when srcHasAlpha is true. The src alpha channel is ignored in
-// this case.
+// src is in RGB 24 bits, or 32 bits word format when srcHasAlpha is true.
+// The src alpha channel is ignored in this case.
 //
-// src cannot be longer in pixel count than dst.
-func (d *Dev) raster(dst []byte, src []byte, srcHasAlpha bool) {
+// The pixels past the end of the strip are ignored.
+func (d *Dev) raster(off int, src []byte, srcHasAlpha bool) {
 	pBytes := 3
 	if srcHasAlpha {
 		pBytes = 4
 	}
 	length := len(src) / pBytes
-	if l := len(dst) / 4; l < length {
+	if l := d.numPixels - off; l < length {
 		length = l
 	}
-	if length == 0 {
+	if length <= 0 {
 		// Save ourself some unneeded processing.
 		return
 	}
+	rgb := d.rgb[3*off:]
+	for i := 0; i < length; i++ {
+		copy(rgb[3*i:3*i+3], src[pBytes*i:])
+	}
+	d.encode(off, length)
+}
+
+// encode serializes n pixels of the back buffer starting at off to the
+// APA102 SPI 32 bits word format.
+func (d *Dev) encode(off, n int) {
 	d.l.init(d.Intensity, d.Temperature, !d.DisableGlobalPWM)
-	if d.DisableGlobalPWM {
-		// Faster path when the global 5 bits PWM is forced to full intensity.
-		for i := 0; i < length; i++ {
-			sOff := pBytes * i
-			dOff := 4 * i
-			r, g, b := d.l.r[src[sOff]], d.l.g[src[sOff+1]], d.l.b[src[sOff+2]]
-			dst[dOff], dst[dOff+1], dst[dOff+2], dst[dOff+3] = 0xFF, byte(b), byte(g), byte(r)
+	if d.Dither && d.errs == nil {
+		d.errs = make([]uint16, 3*d.numPixels)
+	}
+	o := orders[d.Order]
+	for i := off; i < off+n; i++ {
+		src := d.rgb[3*i : 3*i+3]
+		dst := d.pixels[4*i : 4*i+4]
+		r, g, b := d.l.r[src[0]], d.l.g[src[1]], d.l.b[src[2]]
+		if d.Dither {
+			d.encodeDither(dst, d.errs[3*i:3*i+3], src, r, g, b)
+		} else if d.DisableGlobalPWM {
+			// Faster path when the global 5 bits PWM is forced to full
+			// intensity.
+			dst[0] = 0xFF
+			dst[o[0]], dst[o[1]], dst[o[2]] = byte(r), byte(g), byte(b)
+		} else {
+			// The goal is to use brightness!=31 as little as possible.
+			//
+			// Global brightness frequency is 580Hz and color frequency at 19.2kHz.
+			// https://cpldcpu.wordpress.com/2014/08/27/apa102/
+			// Both are multiplicative, so brightness@50% and color@50% means an
+			// effective 25% duty cycle but it is not properly distributed, which is
+			// the main problem.
+			//
+			// It is unclear to me if brightness is exactly in 1/31 increment as I don't
+			// have an oscilloscope to confirm. Same for color in 1/255 increment.
+			// TODO(maruel): I have one now!
+			//
+			// Each channel duty cycle ramps from 100% to 1/(31*255) == 1/7905.
+			//
+			// Computes brightness, blue, green, red.
+			m := r | g | b
+			switch {
+			case m <= 255:
+				dst[0] = 0xE1
+				dst[o[0]], dst[o[1]], dst[o[2]] = byte(r), byte(g), byte(b)
+			case m <= 511:
+				dst[0] = 0xE2
+				dst[o[0]], dst[o[1]], dst[o[2]] = byte(r/2), byte(g/2), byte(b/2)
+			case m <= 1023:
+				dst[0] = 0xE4
+				dst[o[0]], dst[o[1]], dst[o[2]] = byte((r+2)/4), byte((g+2)/4), byte((b+2)/4)
+			default:
+				dst[0] = 0xFF
+				dst[o[0]], dst[o[1]], dst[o[2]] = byte((r+15)/31), byte((g+15)/31), byte((b+15)/31)
+			}
 		}
-		return
 	}
+}
 
-	for i := 0; i < length; i++ {
-		// The goal is to use brightness!=31 as little as possible.
-		//
-		// Global brightness frequency is 580Hz and color frequency at 19.2kHz.
-		// https://cpldcpu.wordpress.com/2014/08/27/apa102/
-		// Both are multiplicative, so brightness@50% and color@50% means an
-		// effective 25% duty cycle but it is not properly distributed, which is
-		// the main problem.
-		//
-		// It is unclear to me if brightness is exactly in 1/31 increment as I don't
-		// have an oscilloscope to confirm. Same for color in 1/255 increment.
-		// TODO(maruel): I have one now!
-		//
-		// Each channel duty cycle ramps from 100% to 1/(31*255) == 1/7905.
-		//
-		// Computes brightness, blue, green, red.
-		sOff := pBytes * i
-		dOff := 4 * i
-		r, g, b := d.l.r[src[sOff]], d.l.g[src[sOff+1]], d.l.b[src[sOff+2]]
-		m := r | g | b
-		switch {
+// encodeDither serializes a pixel, carrying the rounding error of each
+// channel over in errs.
+//
+// With the global PWM, r, g and b are divided by the global brightness. In
+// linear mode, the exact intensity is src*max/255 for each channel.
+func (d *Dev) encodeDither(dst []byte, errs []uint16, src []byte, r, g, b uint16) {
+	o := orders[d.Order]
+	var v [3]uint32
+	var div uint32
+	if d.DisableGlobalPWM {
+		dst[0] = 0xFF
+		div = 255
+		v = [3]uint32{uint32(src[0]) * d.l.maxR, uint32(src[1]) * d.l.maxG, uint32(src[2]) * d.l.maxB}
+	} else {
+		v = [3]uint32{uint32(r), uint32(g), uint32(b)}
+		switch m := r | g | b; {
 		case m <= 255:
-			dst[dOff], dst[dOff+1], dst[dOff+2], dst[dOff+3] = 0xE1, byte(b), byte(g), byte(r)
+			dst[0], div = 0xE1, 1
 		case m <= 511:
-			dst[dOff], dst[dOff+1], dst[dOff+2], dst[dOff+3] = 0xE2, byte(b/2), byte(g/2), byte(r/2)
+			dst[0], div = 0xE2, 2
 		case m <= 1023:
-			dst[dOff], dst[dOff+1], dst[dOff+2], dst[dOff+3] = 0xE4, byte((b+2)/4), byte((g+2)/4), byte((r+2)/4)
+			dst[0], div = 0xE4, 4
 		default:
-			dst[dOff], dst[dOff+1], dst[dOff+2], dst[dOff+3] = 0xFF, byte((b+15)/31), byte((g+15)/31), byte((r+15)/31)
+			dst[0], div = 0xFF, 31
+		}
+	}
+	for c := range v {
+		x := v[c] + uint32(errs[c])
+		q := x / div
+		if q > 255 {
+			q = 255
 		}
+		errs[c] = uint16(x - q*div)
+		dst[o[c]] = byte(q)
 	}
 }
 
@@ -291,7 +492,7 @@ func (d *Dev) raster(dst []byte, src []byte, srcHasAlpha bool) {
 // rect specifies where into the output buffer to draw.
 //
 // srcR specifies what portion of the source image to use.
-func (d *Dev) rasterImg(dst []byte, rect image.Rectangle, src image.Image, srcR image.Rectangle) {
+func (d *Dev) rasterImg(rect image.Rectangle, src image.Image, srcR image.Rectangle) {
 	// Render directly into the buffer for maximum performance and to keep
 	// untouched sections intact.
 	switch im := src.(type) {
@@ -300,14 +501,14 @@ func (d *Dev) rasterImg(dst []byte, rect image.Rectangle, src image.Image, srcR
 		// srcR.Min.Y since the output display has only a single column
 		end := im.PixOffset(srcR.Max.X, srcR.Min.Y)
 		// Offset into the output buffer using rect
-		d.raster(dst[4*rect.Min.X:], im.Pix[start:end], true)
+		d.raster(rect.Min.X, im.Pix[start:end], true)
 	case *image.NRGBA:
 		// Ignores alpha
 		start := im.PixOffset(srcR.Min.X, srcR.Min.Y)
 		// srcR.Min.Y since the output display has only a single column
 		end := im.PixOffset(srcR.Max.X, srcR.Min.Y)
 		// Offset into the output buffer using rect
-		d.raster(dst[4*rect.Min.X:], im.Pix[start:end], true)
+		d.raster(rect.Min.X, im.Pix[start:end], true)
 	default:
 		// Slow path.  Convert to RGBA
 		b := im.Bounds()
@@ -317,7 +518,7 @@ func (d *Dev) rasterImg(dst []byte, rect image.Rectangle, src image.Image, srcR
 		// srcR.Min.Y since the output display has only a single column
 		end := m.PixOffset(srcR.Max.X, srcR.Min.Y)
 		// Offset into the output buffer using rect
-		d.raster(dst[4*rect.Min.X:], m.Pix[start:end], true)
+		d.raster(rect.Min.X, m.Pix[start:end], true)
 	}
 }
 
@@ -377,6 +578,9 @@ type lut struct {
 	r [256]uint16
 	g [256]uint16
 	b [256]uint16
+	// maxR, maxG and maxB are the maximum intensity of each channel when
+	// globalPWM is false.
+	maxR, maxG, maxB uint32
 }
 
 func (l *lut) init(i uint8, t uint16, g bool) {
@@ -394,6 +598,7 @@ func (l *lut) init(i uint8, t uint16, g bool) {
 		maxR := (int(i)*int(tr) + 127) / 255
 		maxG := (int(i)*int(tg) + 127) / 255
 		maxB := (int(i)*int(tb) + 127) / 255
+		l.maxR, l.maxG, l.maxB = uint32(maxR), uint32(maxG), uint32(maxB)
 		for j := range l.r {
 			// Store uint8 range instead of uint16, so it makes the inner loop faster.
 			l.r[j] = uint16((j*maxR + 127) / 255)
@@ -428,4 +633,16 @@ func (l *lut) init(i uint8, t uint16, g bool) {
 	}
 }
 
+// orders is the position of the red, green and blue channels in a LED frame
+// for each ColorOrder.
+var orders = [...][3]int{
+	BGR: {3, 2, 1},
+	BRG: {2, 3, 1},
+	GBR: {3, 1, 2},
+	GRB: {2, 1, 3},
+	RBG: {1, 3, 2},
+	RGB: {1, 2, 3},
+}
+
 var _ display.Drawer = &Dev{}
+var _ display.Drawer = &Segment{}
@@ -913,6 +913,169 @@ func TestInit(t *testing.T) {
 	}
 }
 
+func TestColorOrder(t *testing.T) {
+	buf := bytes.Buffer{}
+	o := PassThruOpts
+	o.NumPixels = 1
+	o.Order = GRB
+	d, err := New(spitest.NewRecordRaw(&buf), &o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Write([]byte{1, 2, 3}); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []byte{0, 0, 0, 0, 0xFF, 2, 1, 3, 0xFF}; !bytes.Equal(expected, buf.Bytes()) {
+		t.Fatalf("\ngot:  %#02v\nwant: %#02v\n", buf.Bytes(), expected)
+	}
+	// Changing the order takes effect on the next refresh.
+	buf.Reset()
+	d.Order = RBG
+	if err := d.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []byte{0, 0, 0, 0, 0xFF, 1, 3, 2, 0xFF}; !bytes.Equal(expected, buf.Bytes()) {
+		t.Fatalf("\ngot:  %#02v\nwant: %#02v\n", buf.Bytes(), expected)
+	}
+	if s := RGB.String(); s != "RGB" {
+		t.Fatal(s)
+	}
+	if s := ColorOrder(6).String(); s != "ColorOrder(?)" {
+		t.Fatal(s)
+	}
+	o.Order = ColorOrder(6)
+	if _, err := New(spitest.NewRecordRaw(&buf), &o); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestDither_Linear(t *testing.T) {
+	buf := bytes.Buffer{}
+	o := PassThruOpts
+	o.NumPixels = 1
+	o.Intensity = 128
+	o.Dither = true
+	d, err := New(spitest.NewRecordRaw(&buf), &o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Write([]byte{1, 1, 1}); err != nil {
+		t.Fatal(err)
+	}
+	// 1*128/255 is alternatively rounded down and up.
+	for i := 0; i < 4; i++ {
+		if i != 0 {
+			buf.Reset()
+			if err := d.Refresh(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		v := byte(i & 1)
+		if expected := []byte{0, 0, 0, 0, 0xFF, v, v, v, 0xFF}; !bytes.Equal(expected, buf.Bytes()) {
+			t.Fatalf("%d:\ngot:  %#02v\nwant: %#02v\n", i, buf.Bytes(), expected)
+		}
+	}
+	d.Dither = false
+	buf.Reset()
+	if err := d.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []byte{0, 0, 0, 0, 0xFF, 1, 1, 1, 0xFF}; !bytes.Equal(expected, buf.Bytes()) {
+		t.Fatalf("\ngot:  %#02v\nwant: %#02v\n", buf.Bytes(), expected)
+	}
+}
+
+func TestDither_GlobalPWM(t *testing.T) {
+	o := DefaultOpts
+	o.NumPixels = 1
+	o.Temperature = NeutralTemp
+	o.Dither = true
+	d, err := New(spitest.NewRecordRaw(ioutil.Discard), &o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []byte{80, 160, 250} {
+		if _, err := d.Write([]byte{v, v, v}); err != nil {
+			t.Fatal(err)
+		}
+		// The average of the output matches the intensity.
+		want := 31 * uint32(d.l.r[v])
+		sum := uint32(0)
+		for i := 0; i < 31; i++ {
+			if i != 0 {
+				if err := d.Refresh(); err != nil {
+					t.Fatal(err)
+				}
+			}
+			sum += uint32(d.pixels[0]&0x1F) * uint32(d.pixels[1])
+		}
+		if sum+31 < want || sum > want {
+			t.Fatalf("%d: %d != %d", v, sum, want)
+		}
+	}
+}
+
+func TestSegment(t *testing.T) {
+	buf := bytes.Buffer{}
+	o := PassThruOpts
+	o.NumPixels = 20
+	d, err := New(spitest.NewRecordRaw(&buf), &o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range [][2]int{{-1, 2}, {2, 2}, {3, 21}} {
+		if _, err := d.Segment(r[0], r[1]); err == nil {
+			t.Fatal("expected error")
+		}
+	}
+	s, err := d.Segment(1, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := s.Bounds(); b != image.Rect(0, 0, 2, 1) {
+		t.Fatal(b)
+	}
+	if str := s.String(); str != "APA102{I:255, T:6500K, GPWM:false, 20LEDs, recordraw}[1:3]" {
+		t.Fatal(str)
+	}
+	if _, err := s.Write(make([]byte, 9)); err == nil {
+		t.Fatal("expected error")
+	}
+	if _, err := s.Write([]byte{1, 2, 3, 4, 5, 6}); err != nil {
+		t.Fatal(err)
+	}
+	expected := []byte{0, 0, 0, 0, 0, 0, 0, 0, 0xFF, 3, 2, 1, 0xFF, 6, 5, 4, 0}
+	if !bytes.Equal(expected, buf.Bytes()) {
+		t.Fatalf("\ngot:  %#02v\nwant: %#02v\n", buf.Bytes(), expected)
+	}
+	buf.Reset()
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 1))
+	img.SetNRGBA(1, 0, color.NRGBA{R: 7, G: 8, B: 9, A: 255})
+	if err := s.Draw(image.Rect(1, 0, 5, 1), img, image.Pt(1, 0)); err != nil {
+		t.Fatal(err)
+	}
+	expected = []byte{0, 0, 0, 0, 0, 0, 0, 0, 0xFF, 3, 2, 1, 0xFF, 9, 8, 7, 0}
+	if !bytes.Equal(expected, buf.Bytes()) {
+		t.Fatalf("\ngot:  %#02v\nwant: %#02v\n", buf.Bytes(), expected)
+	}
+	buf.Reset()
+	if err := s.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	expected = []byte{0, 0, 0, 0, 0, 0, 0, 0, 0xFF, 0, 0, 0, 0xFF, 0, 0, 0, 0}
+	if !bytes.Equal(expected, buf.Bytes()) {
+		t.Fatalf("\ngot:  %#02v\nwant: %#02v\n", buf.Bytes(), expected)
+	}
+	// The whole strip is still sent by the Dev.
+	buf.Reset()
+	if err := d.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if l := buf.Len(); l != 4*21+2 {
+		t.Fatal(l)
+	}
+}
+
 //
 
 type genColor func(int) [3]byte
@@ -11,6 +11,11 @@
 // This driver handles color intensity and temperature correction and uses the
 // full near 8000:1 dynamic range as supported by the device.
 //
+// Clone strips using another color order are supported with Opts.Order.
+// Temporal dithering, enabled with Opts.Dither, smooths the low intensities
+// when the strip is refreshed continuously. A Segment updates the beginning
+// of a long strip without sending the pixels after it.
+//
 // # More details
 //
 // See https://periph.io/device/apa102/ for more details about the device.
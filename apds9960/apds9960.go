@@ -0,0 +1,365 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package apds9960
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"periph.io/x/conn/v3/i2c"
+)
+
+// I2CAddr is the only I²C address of the device.
+const I2CAddr uint16 = 0x39
+
+// ALSGain is the gain of the ambient light and color engine.
+type ALSGain byte
+
+const (
+	ALSGain1x  ALSGain = 0
+	ALSGain4x  ALSGain = 1
+	ALSGain16x ALSGain = 2
+	ALSGain64x ALSGain = 3
+)
+
+// ProximityGain is the gain of the proximity and gesture engines.
+type ProximityGain byte
+
+const (
+	ProximityGain1x ProximityGain = 0
+	ProximityGain2x ProximityGain = 1
+	ProximityGain4x ProximityGain = 2
+	ProximityGain8x ProximityGain = 3
+)
+
+// LEDDrive is the current driving the IR LED for the proximity and gesture
+// engines.
+type LEDDrive byte
+
+const (
+	LED100mA  LEDDrive = 0
+	LED50mA   LEDDrive = 1
+	LED25mA   LEDDrive = 2
+	LED12_5mA LEDDrive = 3
+)
+
+// Color is a raw measurement of the color engine. A higher ALS gain or
+// integration time increases the counts.
+type Color struct {
+	Clear uint16
+	Red   uint16
+	Green uint16
+	Blue  uint16
+}
+
+func (c Color) String() string {
+	return fmt.Sprintf("C:%d R:%d G:%d B:%d", c.Clear, c.Red, c.Green, c.Blue)
+}
+
+// Opts holds the configuration options.
+type Opts struct {
+	// ALSGain is the gain of the ambient light and color engine.
+	ALSGain ALSGain
+	// ALSIntegration is the integration time of the ambient light and color
+	// engine, from 2.78ms to 712ms in steps of 2.78ms. 0 means 100ms.
+	ALSIntegration time.Duration
+	// ProximityGain is the gain of the proximity engine.
+	ProximityGain ProximityGain
+	// LEDDrive is the IR LED current of the proximity and gesture engines.
+	LEDDrive LEDDrive
+	// GestureEnter is the proximity count above which the gesture engine
+	// starts. 0 means 40.
+	GestureEnter uint8
+	// GestureExit is the count below which the gesture engine stops when the
+	// 4 directions are below it. 0 means 30.
+	GestureExit uint8
+}
+
+// DefaultOpts is the recommended default options.
+var DefaultOpts = Opts{
+	ALSGain:        ALSGain4x,
+	ALSIntegration: 100 * time.Millisecond,
+	ProximityGain:  ProximityGain4x,
+	LEDDrive:       LED100mA,
+	GestureEnter:   40,
+	GestureExit:    30,
+}
+
+// Dev is a handle to an APDS-9960.
+type Dev struct {
+	mu          sync.Mutex
+	c           i2c.Dev
+	integration time.Duration
+	// enable is the value of the ENABLE register.
+	enable    byte
+	gesturing bool
+}
+
+// New opens a handle to the device, applies opts and starts the ambient light
+// and proximity engines.
+func New(bus i2c.Bus, opts *Opts) (*Dev, error) {
+	integration := opts.ALSIntegration
+	if integration == 0 {
+		integration = DefaultOpts.ALSIntegration
+	}
+	cycles := int((integration + alsCycle/2) / alsCycle)
+	if cycles < 1 || cycles > 256 {
+		return nil, errors.New("apds9960: ALS integration time out of range")
+	}
+	if opts.ALSGain > ALSGain64x || opts.ProximityGain > ProximityGain8x || opts.LEDDrive > LED12_5mA {
+		return nil, errors.New("apds9960: invalid gain or LED drive")
+	}
+	enter, exit := opts.GestureEnter, opts.GestureExit
+	if enter == 0 {
+		enter = DefaultOpts.GestureEnter
+	}
+	if exit == 0 {
+		exit = DefaultOpts.GestureExit
+	}
+
+	d := &Dev{c: i2c.Dev{Bus: bus, Addr: I2CAddr}, integration: time.Duration(cycles) * alsCycle}
+	id, err := d.readReg(regID)
+	if err != nil {
+		return nil, err
+	}
+	switch id {
+	case 0xAB, 0x9C, 0xA8:
+	default:
+		return nil, fmt.Errorf("apds9960: unexpected device ID %#x", id)
+	}
+	control := byte(opts.LEDDrive)<<6 | byte(opts.ProximityGain)<<2 | byte(opts.ALSGain)
+	for _, w := range [][2]byte{
+		{regEnable, 0x00},
+		{regATime, byte(256 - cycles)},
+		{regWTime, 0xFF},
+		// 16μs pulses, 8 pulses.
+		{regPPulse, 0x87},
+		{regPOffsetUR, 0x00},
+		{regPOffsetDL, 0x00},
+		{regConfig1, 0x60},
+		{regControl, control},
+		{regPers, 0x11},
+		{regConfig2, 0x01},
+		{regConfig3, 0x00},
+		{regGPEnTh, enter},
+		{regGExTh, exit},
+		// Interrupt after 4 datasets, exit after 1 dataset below GExTh.
+		{regGConf1, 0x40},
+		// 4x gain, same LED drive, 2.8ms between datasets.
+		{regGConf2, 0x40 | byte(opts.LEDDrive)<<3 | 0x01},
+		{regGOffsetU, 0x00},
+		{regGOffsetD, 0x00},
+		{regGOffsetL, 0x00},
+		{regGOffsetR, 0x00},
+		// 32μs pulses, 10 pulses.
+		{regGPulse, 0xC9},
+		{regGConf3, 0x00},
+		{regGConf4, 0x00},
+	} {
+		if err := d.writeReg(w[0], w[1]); err != nil {
+			return nil, err
+		}
+	}
+	if err := d.setEnable(enablePON | enableAEN | enablePEN); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *Dev) String() string {
+	return fmt.Sprintf("APDS9960{%s}", &d.c)
+}
+
+// Color returns the last clear, red, green and blue measurement.
+func (d *Dev) Color() (Color, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.waitValid(statusAValid, d.integration); err != nil {
+		return Color{}, err
+	}
+	var b [8]byte
+	if err := d.c.Tx([]byte{regCData}, b[:]); err != nil {
+		return Color{}, err
+	}
+	return Color{
+		Clear: binary.LittleEndian.Uint16(b[0:]),
+		Red:   binary.LittleEndian.Uint16(b[2:]),
+		Green: binary.LittleEndian.Uint16(b[4:]),
+		Blue:  binary.LittleEndian.Uint16(b[6:]),
+	}, nil
+}
+
+// Proximity returns the last proximity measurement, from 0 to 255. The count
+// increases as an object gets closer.
+func (d *Dev) Proximity() (uint8, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.waitValid(statusPValid, proximityCycle); err != nil {
+		return 0, err
+	}
+	return d.readReg(regPData)
+}
+
+// SetALSThresholds sets the clear channel counts out of which the ambient
+// light interrupt is asserted, when enabled with EnableInterrupts.
+func (d *Dev) SetALSThresholds(low, high uint16) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.c.Tx([]byte{regAILTL, byte(low), byte(low >> 8), byte(high), byte(high >> 8)}, nil)
+}
+
+// SetProximityThresholds sets the proximity counts out of which the proximity
+// interrupt is asserted, when enabled with EnableInterrupts.
+func (d *Dev) SetProximityThresholds(low, high uint8) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.writeReg(regPILT, low); err != nil {
+		return err
+	}
+	return d.writeReg(regPIHT, high)
+}
+
+// EnableInterrupts enables or disables the assertion of the INT pin by the
+// ambient light and the proximity engines.
+func (d *Dev) EnableInterrupts(als, proximity bool) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	e := d.enable &^ (enableAIEN | enablePIEN)
+	if als {
+		e |= enableAIEN
+	}
+	if proximity {
+		e |= enablePIEN
+	}
+	return d.setEnable(e)
+}
+
+// ClearInterrupts clears the ambient light and proximity interrupts, releasing
+// the INT pin.
+func (d *Dev) ClearInterrupts() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.c.Tx([]byte{regAIClear}, nil)
+}
+
+// Halt powers the device off. The channel returned by Gestures, if any, is
+// not closed until its context is done.
+//
+// Halt implements conn.Resource.
+func (d *Dev) Halt() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.setEnable(0)
+}
+
+//
+
+// Registers.
+const (
+	regEnable    = 0x80
+	regATime     = 0x81
+	regWTime     = 0x83
+	regAILTL     = 0x84
+	regPILT      = 0x89
+	regPIHT      = 0x8B
+	regPers      = 0x8C
+	regConfig1   = 0x8D
+	regPPulse    = 0x8E
+	regControl   = 0x8F
+	regConfig2   = 0x90
+	regID        = 0x92
+	regStatus    = 0x93
+	regCData     = 0x94
+	regPData     = 0x9C
+	regPOffsetUR = 0x9D
+	regPOffsetDL = 0x9E
+	regConfig3   = 0x9F
+	regGPEnTh    = 0xA0
+	regGExTh     = 0xA1
+	regGConf1    = 0xA2
+	regGConf2    = 0xA3
+	regGOffsetU  = 0xA4
+	regGOffsetD  = 0xA5
+	regGPulse    = 0xA6
+	regGOffsetL  = 0xA7
+	regGOffsetR  = 0xA9
+	regGConf3    = 0xAA
+	regGConf4    = 0xAB
+	regGFLvl     = 0xAE
+	regAIClear   = 0xE7
+	regGFIFOU    = 0xFC
+)
+
+// ENABLE bits.
+const (
+	enablePON  = 0x01
+	enableAEN  = 0x02
+	enablePEN  = 0x04
+	enableAIEN = 0x10
+	enablePIEN = 0x20
+	enableGEN  = 0x40
+)
+
+// STATUS bits.
+const (
+	statusAValid = 0x01
+	statusPValid = 0x02
+)
+
+// GCONF4 bits.
+const (
+	gconf4GMode    = 0x01
+	gconf4FIFOClr  = 0x04
+	gestureDataset = 4
+)
+
+const (
+	alsCycle       = 2780 * time.Microsecond
+	proximityCycle = 3 * time.Millisecond
+)
+
+var sleep = time.Sleep
+
+// waitValid waits for the status bit to be set, sleeping at most once for
+// delay.
+func (d *Dev) waitValid(bit byte, delay time.Duration) error {
+	s, err := d.readReg(regStatus)
+	if err != nil {
+		return err
+	}
+	if s&bit == 0 {
+		sleep(delay)
+		if s, err = d.readReg(regStatus); err != nil {
+			return err
+		}
+		if s&bit == 0 {
+			return errors.New("apds9960: no valid measurement")
+		}
+	}
+	return nil
+}
+
+func (d *Dev) setEnable(e byte) error {
+	if err := d.writeReg(regEnable, e); err != nil {
+		return err
+	}
+	d.enable = e
+	return nil
+}
+
+func (d *Dev) readReg(reg byte) (byte, error) {
+	var b [1]byte
+	err := d.c.Tx([]byte{reg}, b[:])
+	return b[0], err
+}
+
+func (d *Dev) writeReg(reg, v byte) error {
+	return d.c.Tx([]byte{reg, v}, nil)
+}
+
+var _ fmt.Stringer = &Dev{}
@@ -0,0 +1,203 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package apds9960
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3/i2c/i2ctest"
+)
+
+func initOps() []i2ctest.IO {
+	ops := []i2ctest.IO{{Addr: I2CAddr, W: []byte{regID}, R: []byte{0xAB}}}
+	for _, w := range [][2]byte{
+		{0x80, 0x00}, {0x81, 0xDC}, {0x83, 0xFF}, {0x8E, 0x87}, {0x9D, 0x00},
+		{0x9E, 0x00}, {0x8D, 0x60}, {0x8F, 0x09}, {0x8C, 0x11}, {0x90, 0x01},
+		{0x9F, 0x00}, {0xA0, 40}, {0xA1, 30}, {0xA2, 0x40}, {0xA3, 0x41},
+		{0xA4, 0x00}, {0xA5, 0x00}, {0xA7, 0x00}, {0xA9, 0x00}, {0xA6, 0xC9},
+		{0xAA, 0x00}, {0xAB, 0x00}, {0x80, 0x07},
+	} {
+		ops = append(ops, i2ctest.IO{Addr: I2CAddr, W: w[:]})
+	}
+	return ops
+}
+
+func TestNew_BadID(t *testing.T) {
+	bus := i2ctest.Playback{Ops: []i2ctest.IO{{Addr: I2CAddr, W: []byte{regID}, R: []byte{0x12}}}}
+	if _, err := New(&bus, &DefaultOpts); err == nil {
+		t.Fatal("expected error")
+	}
+	if _, err := New(&bus, &Opts{ALSIntegration: time.Second}); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestColorProximity(t *testing.T) {
+	defer func(f func(time.Duration)) { sleep = f }(sleep)
+	var slept time.Duration
+	sleep = func(d time.Duration) { slept += d }
+	bus := i2ctest.Playback{
+		Ops: append(initOps(),
+			i2ctest.IO{Addr: I2CAddr, W: []byte{regStatus}, R: []byte{0x00}},
+			i2ctest.IO{Addr: I2CAddr, W: []byte{regStatus}, R: []byte{0x01}},
+			i2ctest.IO{Addr: I2CAddr, W: []byte{regCData}, R: []byte{0x10, 0x01, 0x20, 0x00, 0x30, 0x00, 0x40, 0x00}},
+			i2ctest.IO{Addr: I2CAddr, W: []byte{regStatus}, R: []byte{0x02}},
+			i2ctest.IO{Addr: I2CAddr, W: []byte{regPData}, R: []byte{0x80}},
+			i2ctest.IO{Addr: I2CAddr, W: []byte{regStatus}, R: []byte{0x00}},
+			i2ctest.IO{Addr: I2CAddr, W: []byte{regStatus}, R: []byte{0x00}},
+		),
+	}
+	dev, err := New(&bus, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := dev.String(); s != "APDS9960{playback(57)}" {
+		t.Fatal(s)
+	}
+	c, err := dev.Color()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c != (Color{Clear: 0x110, Red: 0x20, Green: 0x30, Blue: 0x40}) {
+		t.Fatal(c)
+	}
+	if slept != 36*alsCycle {
+		t.Fatal(slept)
+	}
+	p, err := dev.Proximity()
+	if err != nil || p != 0x80 {
+		t.Fatal(p, err)
+	}
+	if _, err := dev.Proximity(); err == nil {
+		t.Fatal("expected error")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInterrupts(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: append(initOps(),
+			i2ctest.IO{Addr: I2CAddr, W: []byte{regAILTL, 0x34, 0x12, 0x78, 0x56}},
+			i2ctest.IO{Addr: I2CAddr, W: []byte{regPILT, 10}},
+			i2ctest.IO{Addr: I2CAddr, W: []byte{regPIHT, 200}},
+			i2ctest.IO{Addr: I2CAddr, W: []byte{regEnable, 0x37}},
+			i2ctest.IO{Addr: I2CAddr, W: []byte{regEnable, 0x27}},
+			i2ctest.IO{Addr: I2CAddr, W: []byte{regAIClear}},
+			i2ctest.IO{Addr: I2CAddr, W: []byte{regEnable, 0x00}},
+		),
+	}
+	dev, err := New(&bus, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.SetALSThresholds(0x1234, 0x5678); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.SetProximityThresholds(10, 200); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.EnableInterrupts(true, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.EnableInterrupts(false, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.ClearInterrupts(); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDecodeGesture(t *testing.T) {
+	data := []struct {
+		name string
+		in   [][gestureDataset]byte
+		want Gesture
+		ok   bool
+	}{
+		{"empty", nil, 0, false},
+		{"single", [][gestureDataset]byte{{50, 50, 50, 50}}, 0, false},
+		{"below threshold", [][gestureDataset]byte{{5, 50, 50, 50}, {50, 5, 50, 50}}, 0, false},
+		{"still", [][gestureDataset]byte{{50, 50, 50, 50}, {60, 55, 50, 52}}, 0, false},
+		{"up", [][gestureDataset]byte{{100, 20, 60, 60}, {60, 60, 60, 60}, {20, 100, 60, 60}}, GestureUp, true},
+		{"down", [][gestureDataset]byte{{20, 100, 60, 60}, {100, 20, 60, 60}}, GestureDown, true},
+		{"left", [][gestureDataset]byte{{60, 60, 100, 20}, {60, 60, 20, 100}, {1, 1, 1, 1}}, GestureLeft, true},
+		{"right", [][gestureDataset]byte{{60, 60, 20, 100}, {60, 60, 100, 20}}, GestureRight, true},
+	}
+	for _, line := range data {
+		g, ok := decodeGesture(line.in)
+		if ok != line.ok || g != line.want {
+			t.Errorf("%s: got %s %t, want %s %t", line.name, g, ok, line.want, line.ok)
+		}
+	}
+}
+
+func TestPollGesture(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: append(initOps(),
+			i2ctest.IO{Addr: I2CAddr, W: []byte{regGConf4}, R: []byte{0x01}},
+			i2ctest.IO{Addr: I2CAddr, W: []byte{regGFLvl}, R: []byte{2}},
+			i2ctest.IO{Addr: I2CAddr, W: []byte{regGFIFOU}, R: []byte{1, 2, 3, 4, 5, 6, 7, 8}},
+			i2ctest.IO{Addr: I2CAddr, W: []byte{regGConf4}, R: []byte{0x00}},
+			i2ctest.IO{Addr: I2CAddr, W: []byte{regGFLvl}, R: []byte{0}},
+		),
+	}
+	dev, err := New(&bus, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var data [][gestureDataset]byte
+	if done, err := dev.pollGesture(&data); err != nil || done {
+		t.Fatal(done, err)
+	}
+	if done, err := dev.pollGesture(&data); err != nil || !done {
+		t.Fatal(done, err)
+	}
+	if len(data) != 2 || data[0] != [gestureDataset]byte{1, 2, 3, 4} || data[1] != [gestureDataset]byte{5, 6, 7, 8} {
+		t.Fatal(data)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGestures_Cancel(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: append(initOps(),
+			i2ctest.IO{Addr: I2CAddr, W: []byte{regGConf4, 0x04}},
+			i2ctest.IO{Addr: I2CAddr, W: []byte{regEnable, 0x47}},
+			i2ctest.IO{Addr: I2CAddr, W: []byte{regEnable, 0x07}},
+			i2ctest.IO{Addr: I2CAddr, W: []byte{regGConf4, 0x04}},
+		),
+	}
+	dev, err := New(&bus, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dev.Gestures(context.Background(), 0); err == nil {
+		t.Fatal("expected error")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	ch, err := dev.Gestures(ctx, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range ch {
+		t.Fatal("unexpected gesture")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,29 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package apds9960 controls the Broadcom APDS-9960 digital proximity, ambient
+// light, RGB and gesture sensor over I²C.
+//
+// The ambient light and proximity engines run continuously once New returns.
+// Color returns the raw clear, red, green and blue counts and Proximity the
+// raw proximity count.
+//
+// # Gestures
+//
+// Gestures enables the gesture engine and returns a channel of decoded
+// gestures. The engine starts when an object gets closer than
+// Opts.GestureEnter, fills its FIFO with the reflections seen by the 4
+// directional photodiodes and stops once the object moves away. The FIFO
+// content is then decoded into a Gesture.
+//
+// # Interrupts
+//
+// The INT pin can be asserted when the ambient light clear channel or the
+// proximity goes out of the thresholds set with SetALSThresholds and
+// SetProximityThresholds. ClearInterrupts releases it.
+//
+// # Datasheet
+//
+// https://docs.broadcom.com/doc/AV02-4191EN
+package apds9960
@@ -0,0 +1,55 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package apds9960_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+
+	"periph.io/x/conn/v3/i2c/i2creg"
+	"periph.io/x/devices/v3/apds9960"
+	"periph.io/x/host/v3"
+)
+
+func Example() {
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Open default I²C bus.
+	bus, err := i2creg.Open("")
+	if err != nil {
+		log.Fatalf("failed to open I²C: %v", err)
+	}
+	defer bus.Close()
+
+	dev, err := apds9960.New(bus, &apds9960.DefaultOpts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer dev.Halt()
+
+	c, err := dev.Color()
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(c)
+
+	// Print the gestures until interrupted.
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+	gestures, err := dev.Gestures(ctx, 20*time.Millisecond)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for g := range gestures {
+		fmt.Println(g)
+	}
+}
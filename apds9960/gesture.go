@@ -0,0 +1,184 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package apds9960
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Gesture is a movement decoded by the gesture engine.
+//
+// The directions are relative to the sensor with the IR LED at the bottom.
+type Gesture int
+
+const (
+	GestureUp Gesture = iota
+	GestureDown
+	GestureLeft
+	GestureRight
+)
+
+func (g Gesture) String() string {
+	switch g {
+	case GestureUp:
+		return "Up"
+	case GestureDown:
+		return "Down"
+	case GestureLeft:
+		return "Left"
+	case GestureRight:
+		return "Right"
+	default:
+		return "Gesture(?)"
+	}
+}
+
+// Gestures enables the gesture engine and sends the decoded gestures to the
+// returned channel. The gesture FIFO is polled every interval, 10ms to 30ms
+// is a good value.
+//
+// Movements that cannot be decoded, like an object moving straight towards
+// the sensor, are ignored. The gesture engine is disabled and the channel is
+// closed once ctx is done.
+func (d *Dev) Gestures(ctx context.Context, interval time.Duration) (<-chan Gesture, error) {
+	if interval <= 0 {
+		return nil, errors.New("apds9960: invalid gesture polling interval")
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.gesturing {
+		return nil, errors.New("apds9960: gestures already enabled")
+	}
+	if err := d.writeReg(regGConf4, gconf4FIFOClr); err != nil {
+		return nil, err
+	}
+	if err := d.setEnable(d.enable | enablePON | enablePEN | enableGEN); err != nil {
+		return nil, err
+	}
+	d.gesturing = true
+	ch := make(chan Gesture, 16)
+	go func() {
+		defer close(ch)
+		defer d.stopGestures()
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		var data [][gestureDataset]byte
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+			}
+			done, err := d.pollGesture(&data)
+			if err != nil || !done {
+				continue
+			}
+			g, ok := decodeGesture(data)
+			data = data[:0]
+			if !ok {
+				continue
+			}
+			select {
+			case ch <- g:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// stopGestures disables the gesture engine.
+func (d *Dev) stopGestures() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.gesturing = false
+	_ = d.setEnable(d.enable &^ enableGEN)
+	_ = d.writeReg(regGConf4, gconf4FIFOClr)
+}
+
+// pollGesture appends the datasets in the FIFO to data. It returns true when
+// the gesture engine exited after collecting data, that is when the gesture
+// is complete.
+func (d *Dev) pollGesture(data *[][gestureDataset]byte) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	// Check the engine state before draining the FIFO, so no dataset is left
+	// behind once it exited.
+	gconf4, err := d.readReg(regGConf4)
+	if err != nil {
+		return false, err
+	}
+	n, err := d.readReg(regGFLvl)
+	if err != nil {
+		return false, err
+	}
+	if n != 0 {
+		b := make([]byte, int(n)*gestureDataset)
+		if err := d.c.Tx([]byte{regGFIFOU}, b); err != nil {
+			return false, err
+		}
+		for i := 0; i < len(b); i += gestureDataset {
+			*data = append(*data, [gestureDataset]byte(b[i:i+gestureDataset]))
+		}
+	}
+	return gconf4&gconf4GMode == 0 && len(*data) != 0, nil
+}
+
+// Gesture decoding parameters.
+const (
+	// gestureThreshold is the count the 4 directions must be above for a
+	// dataset to be used.
+	gestureThreshold = 10
+	// gestureSensitivity is the minimum change of the up/down or left/right
+	// ratio, in percent, for a gesture.
+	gestureSensitivity = 50
+)
+
+// decodeGesture decodes the datasets, each holding the up, down, left and
+// right counts, of a gesture.
+//
+// It compares the up/down and left/right ratios of the first and the last
+// datasets above gestureThreshold, the largest change giving the direction.
+func decodeGesture(data [][gestureDataset]byte) (Gesture, bool) {
+	first, last := -1, -1
+	for i, s := range data {
+		if s[0] > gestureThreshold && s[1] > gestureThreshold && s[2] > gestureThreshold && s[3] > gestureThreshold {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	if first < 0 || first == last {
+		return 0, false
+	}
+	ratio := func(a, b byte) int {
+		return (int(a) - int(b)) * 100 / (int(a) + int(b))
+	}
+	ud := ratio(data[last][0], data[last][1]) - ratio(data[first][0], data[first][1])
+	lr := ratio(data[last][2], data[last][3]) - ratio(data[first][2], data[first][3])
+	switch {
+	case abs(ud) < gestureSensitivity && abs(lr) < gestureSensitivity:
+		return 0, false
+	case abs(ud) > abs(lr) && ud > 0:
+		return GestureDown, true
+	case abs(ud) > abs(lr):
+		return GestureUp, true
+	case lr > 0:
+		return GestureRight, true
+	default:
+		return GestureLeft, true
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,252 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package at24cxx
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/i2c"
+)
+
+// Model is the model of EEPROM, which determines its size and page size.
+type Model int
+
+// Supported models.
+const (
+	AT24C02 Model = iota
+	AT24C04
+	AT24C08
+	AT24C16
+	AT24C32
+	AT24C64
+	AT24C128
+	AT24C256
+	AT24C512
+)
+
+func (m Model) String() string {
+	if m < AT24C02 || m > AT24C512 {
+		return fmt.Sprintf("Model(%d)", int(m))
+	}
+	return models[m].name
+}
+
+// Opts holds the configuration options.
+type Opts struct {
+	// Addr is the I²C address, from 0x50 to 0x57 depending on the A0-A2
+	// pins. The AT24C04, AT24C08 and AT24C16 use the lower address bits to
+	// select a block, so they must be at an address aligned on their number
+	// of blocks.
+	Addr uint16
+}
+
+// DefaultOpts is the recommended default options.
+var DefaultOpts = Opts{
+	Addr: 0x50,
+}
+
+// Dev is a handle to an AT24Cxx EEPROM.
+type Dev struct {
+	b     i2c.Bus
+	addr  uint16
+	model Model
+	mu    sync.Mutex
+}
+
+// New returns a handle to an EEPROM of the given model.
+func New(b i2c.Bus, m Model, opts *Opts) (*Dev, error) {
+	if m < AT24C02 || m > AT24C512 {
+		return nil, errors.New("at24cxx: unknown model")
+	}
+	if opts.Addr < 0x50 || opts.Addr > 0x57 {
+		return nil, errors.New("at24cxx: invalid address, must be between 0x50 and 0x57")
+	}
+	if blocks := models[m].blocks(); opts.Addr&uint16(blocks-1) != 0 {
+		return nil, fmt.Errorf("at24cxx: %s address must be a multiple of %d", m, blocks)
+	}
+	return &Dev{b: b, addr: opts.Addr, model: m}, nil
+}
+
+func (d *Dev) String() string {
+	return fmt.Sprintf("%s{%s, %#x}", d.model, d.b, d.addr)
+}
+
+// Size returns the size of the EEPROM in bytes.
+func (d *Dev) Size() int64 {
+	return int64(models[d.model].size)
+}
+
+// PageSize returns the size of a page, the largest chunk that can be written
+// at once.
+func (d *Dev) PageSize() int {
+	return models[d.model].page
+}
+
+// ReadAt implements io.ReaderAt.
+//
+// It returns io.EOF when reading past the end of the EEPROM.
+func (d *Dev) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("at24cxx: negative offset")
+	}
+	size := d.Size()
+	if off >= size {
+		return 0, io.EOF
+	}
+	var err error
+	if int64(len(p)) > size-off {
+		p = p[:size-off]
+		err = io.EOF
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	n := 0
+	for n < len(p) {
+		// Transfers are limited in size and do not cross a block, as
+		// the block is selected by the device address.
+		l := len(p) - n
+		if l > maxRead {
+			l = maxRead
+		}
+		if e := d.blockEnd(off + int64(n)); int64(l) > e-off-int64(n) {
+			l = int(e - off - int64(n))
+		}
+		addr, w := d.address(off + int64(n))
+		if err := d.b.Tx(addr, w, p[n:n+l]); err != nil {
+			return n, fmt.Errorf("at24cxx: %v", err)
+		}
+		n += l
+	}
+	return n, err
+}
+
+// WriteAt implements io.WriterAt.
+//
+// The data is written page by page, waiting for each write cycle to
+// complete. It returns an error when writing past the end of the EEPROM.
+func (d *Dev) WriteAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("at24cxx: negative offset")
+	}
+	size := d.Size()
+	var err error
+	if off >= size {
+		return 0, errors.New("at24cxx: write past the end of the EEPROM")
+	}
+	if int64(len(p)) > size-off {
+		p = p[:size-off]
+		err = errors.New("at24cxx: write past the end of the EEPROM")
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	page := int64(d.PageSize())
+	n := 0
+	for n < len(p) {
+		o := off + int64(n)
+		l := len(p) - n
+		if r := page - o%page; int64(l) > r {
+			l = int(r)
+		}
+		addr, w := d.address(o)
+		if err := d.b.Tx(addr, append(w, p[n:n+l]...), nil); err != nil {
+			return n, fmt.Errorf("at24cxx: %v", err)
+		}
+		if err := d.waitWriteCycle(addr, w); err != nil {
+			return n, err
+		}
+		n += l
+	}
+	return n, err
+}
+
+// Halt implements conn.Resource.
+func (d *Dev) Halt() error {
+	return nil
+}
+
+//
+
+// model describes an EEPROM.
+type model struct {
+	name string
+	size int
+	page int
+	// addrBytes is the number of bytes of the memory address, 1 or 2.
+	addrBytes int
+}
+
+// blocks returns the number of device addresses used by the EEPROM.
+func (m *model) blocks() int {
+	if m.addrBytes == 1 {
+		return m.size / 256
+	}
+	return 1
+}
+
+var models = [...]model{
+	{"AT24C02", 256, 8, 1},
+	{"AT24C04", 512, 16, 1},
+	{"AT24C08", 1024, 16, 1},
+	{"AT24C16", 2048, 16, 1},
+	{"AT24C32", 4096, 32, 2},
+	{"AT24C64", 8192, 32, 2},
+	{"AT24C128", 16384, 64, 2},
+	{"AT24C256", 32768, 64, 2},
+	{"AT24C512", 65536, 128, 2},
+}
+
+const (
+	// maxRead is the largest read done in a single transfer.
+	maxRead = 128
+	// writeCycle is the maximum duration of a write cycle.
+	writeCycle = 10 * time.Millisecond
+	// writePoll is the interval at which the end of a write cycle is
+	// polled.
+	writePoll = time.Millisecond
+)
+
+// address returns the device address and the memory address bytes to access
+// the offset off.
+func (d *Dev) address(off int64) (uint16, []byte) {
+	if models[d.model].addrBytes == 1 {
+		return d.addr | uint16(off>>8), []byte{byte(off)}
+	}
+	return d.addr, []byte{byte(off >> 8), byte(off)}
+}
+
+// blockEnd returns the offset of the end of the block containing off.
+func (d *Dev) blockEnd(off int64) int64 {
+	if models[d.model].addrBytes == 1 {
+		return (off/256 + 1) * 256
+	}
+	return d.Size()
+}
+
+// waitWriteCycle polls the EEPROM until it acknowledges again, which means
+// the write cycle is done.
+//
+// The memory address is written without data, which only sets the address
+// pointer.
+func (d *Dev) waitWriteCycle(addr uint16, w []byte) error {
+	var err error
+	for t := time.Duration(0); t <= writeCycle; t += writePoll {
+		doSleep(writePoll)
+		if err = d.b.Tx(addr, w, nil); err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("at24cxx: timeout waiting for write cycle: %v", err)
+}
+
+var doSleep = time.Sleep
+
+var _ conn.Resource = &Dev{}
+var _ io.ReaderAt = &Dev{}
+var _ io.WriterAt = &Dev{}
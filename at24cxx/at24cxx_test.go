@@ -0,0 +1,169 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package at24cxx
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3/i2c/i2ctest"
+)
+
+// busyBus fails the first polls of each write cycle.
+type busyBus struct {
+	i2ctest.Playback
+	addrLen int
+	polls   int
+	pending int
+}
+
+func (b *busyBus) Tx(addr uint16, w, r []byte) error {
+	if r == nil && len(w) == b.addrLen && b.pending > 0 {
+		b.pending--
+		return errors.New("nack")
+	}
+	err := b.Playback.Tx(addr, w, r)
+	if r == nil && len(w) > b.addrLen {
+		b.pending = b.polls
+	}
+	return err
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(&i2ctest.Playback{}, Model(9), &DefaultOpts); err == nil {
+		t.Fatal("expected error on model")
+	}
+	if _, err := New(&i2ctest.Playback{}, AT24C02, &Opts{Addr: 0x48}); err == nil {
+		t.Fatal("expected error on address")
+	}
+	if _, err := New(&i2ctest.Playback{}, AT24C16, &Opts{Addr: 0x51}); err == nil {
+		t.Fatal("expected error on block alignment")
+	}
+	d, err := New(&i2ctest.Playback{}, AT24C256, &Opts{Addr: 0x53})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := d.String(); s != "AT24C256{playback, 0x53}" {
+		t.Fatal(s)
+	}
+	if d.Size() != 32768 || d.PageSize() != 64 {
+		t.Fatal(d.Size(), d.PageSize())
+	}
+	if s := Model(10).String(); s != "Model(10)" {
+		t.Fatal(s)
+	}
+	if err := d.Halt(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadAt(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Crosses from block 0 to block 1.
+			{Addr: 0x50, W: []byte{0xFE}, R: []byte{1, 2}},
+			{Addr: 0x51, W: []byte{0x00}, R: []byte{3, 4}},
+			// Truncated at the end.
+			{Addr: 0x51, W: []byte{0xFF}, R: []byte{5}},
+		},
+	}
+	d, err := New(&bus, AT24C04, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := make([]byte, 4)
+	if n, err := d.ReadAt(p, 254); n != 4 || err != nil {
+		t.Fatal(n, err)
+	}
+	if !bytes.Equal(p, []byte{1, 2, 3, 4}) {
+		t.Fatal(p)
+	}
+	if n, err := d.ReadAt(p, 511); n != 1 || err != io.EOF || p[0] != 5 {
+		t.Fatal(n, err, p)
+	}
+	if n, err := d.ReadAt(p, 512); n != 0 || err != io.EOF {
+		t.Fatal(n, err)
+	}
+	if _, err := d.ReadAt(p, -1); err == nil {
+		t.Fatal("expected error on offset")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadAt_Large(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x50, W: []byte{0x01, 0x00}, R: make([]byte, 128)},
+			{Addr: 0x50, W: []byte{0x01, 0x80}, R: make([]byte, 72)},
+		},
+	}
+	d, err := New(&bus, AT24C32, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := d.ReadAt(make([]byte, 200), 256); n != 200 || err != nil {
+		t.Fatal(n, err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWriteAt(t *testing.T) {
+	defer func(f func(time.Duration)) { doSleep = f }(doSleep)
+	doSleep = func(time.Duration) {}
+	bus := busyBus{
+		Playback: i2ctest.Playback{
+			Ops: []i2ctest.IO{
+				{Addr: 0x50, W: []byte{0x00, 0x1E, 1, 2}},
+				{Addr: 0x50, W: []byte{0x00, 0x1E}},
+				{Addr: 0x50, W: []byte{0x00, 0x20, 3, 4, 5}},
+				{Addr: 0x50, W: []byte{0x00, 0x20}},
+			},
+		},
+		addrLen: 2,
+		polls:   2,
+	}
+	d, err := New(&bus, AT24C32, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := d.WriteAt([]byte{1, 2, 3, 4, 5}, 30); n != 5 || err != nil {
+		t.Fatal(n, err)
+	}
+	if _, err := d.WriteAt([]byte{1}, 4096); err == nil {
+		t.Fatal("expected error past the end")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWriteAt_Timeout(t *testing.T) {
+	defer func(f func(time.Duration)) { doSleep = f }(doSleep)
+	doSleep = func(time.Duration) {}
+	bus := busyBus{
+		Playback: i2ctest.Playback{
+			Ops: []i2ctest.IO{{Addr: 0x52, W: []byte{0x10, 0xAA}}},
+		},
+		addrLen: 1,
+		polls:   100,
+	}
+	d, err := New(&bus, AT24C08, &Opts{Addr: 0x50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := d.WriteAt([]byte{0xAA}, 0x210); n != 0 || err == nil {
+		t.Fatal(n, err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,17 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package at24cxx controls AT24C02 to AT24C512 serial EEPROMs via I²C
+// interface.
+//
+// These EEPROMs are found on many boards and HATs to identify them. Dev
+// implements io.ReaderAt and io.WriterAt. Writes are split on page
+// boundaries and wait for the internal write cycle of each page to complete.
+//
+// # Datasheet
+//
+// https://ww1.microchip.com/downloads/en/DeviceDoc/AT24C02C-AT24C04C-AT24C08C-I2C-Compatible-Two-Wire-Serial-EEPROM-2-Kbit-4-Kbit-8-Kbit-DS20006093A.pdf
+//
+// https://ww1.microchip.com/downloads/en/DeviceDoc/AT24C512C-Data-Sheet-DS20006120A.pdf
+package at24cxx
@@ -6,6 +6,10 @@ package bh1750
 
 import (
 	"encoding/binary"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
 	"time"
 
 	"periph.io/x/conn/v3/i2c"
@@ -72,9 +76,14 @@ const (
 
 // Dev is a handler to bh1750 controller
 type Dev struct {
+	mu   sync.Mutex
 	dev  i2c.Dev
 	res  Resolution
 	mode Mode
+	// started is when the last measurement command was sent.
+	started time.Time
+	stop    chan struct{}
+	wg      sync.WaitGroup
 }
 
 // NewI2C opens a handle to an bh1750 sensor.
@@ -94,47 +103,189 @@ func (d *Dev) init() error {
 		return err
 	}
 
-	if err := d.SetResolution(ContinuousHighResMode); err != nil {
+	if _, err := d.dev.Write([]byte{reset}); err != nil {
 		return err
 	}
+	return d.SetResolution(ContinuousHighResMode)
+}
 
-	_, err := d.dev.Write([]byte{reset})
-	return err
+func (d *Dev) String() string {
+	return fmt.Sprintf("BH1750{%s}", &d.dev)
 }
 
 // SetMode set the sleep mode of the sensor.
 func (d *Dev) SetMode(m Mode) error {
-	d.mode = m
-	_, err := d.dev.Write([]byte{reset})
-	return err
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.setMode(m)
 }
 
 // SetResolution set the resolution mode of the sensor.
+//
+// Continuous modes start measuring right away. One time modes measure on each
+// call to Sense.
 func (d *Dev) SetResolution(r Resolution) error {
-	d.res = r
-	_, err := d.dev.Write([]byte{byte(r)})
-	return err
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.setResolution(r)
 }
 
 // Sense reads the light value from the bh1750 sensor.
+//
+// The illuminance is returned in lux, as lumen per square metre.
+//
+// In continuous modes, Sense only waits for the first measurement after the
+// resolution was set. In one time modes, it starts a measurement and waits for
+// it.
 func (d *Dev) Sense() (physic.LuminousFlux, error) {
-	if err := d.SetResolution(d.res); err != nil {
-		return 0, err
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.stop != nil {
+		return 0, errors.New("bh1750: continuous sensing in progress")
 	}
+	return d.sense()
+}
 
-	time.Sleep(timeout[d.res])
+// SenseContinuous returns measurements every interval, on a continuous basis.
+//
+// The sensor is switched to the continuous mode of the same resolution. The
+// application must call Halt() to stop the sensing when done to stop the
+// sensor and close the channel.
+//
+// It's the responsibility of the caller to retrieve the values from the channel
+// as fast as possible, otherwise the interval may not be respected.
+func (d *Dev) SenseContinuous(interval time.Duration) (<-chan physic.LuminousFlux, error) {
+	d.mu.Lock()
+	res := d.res
+	d.mu.Unlock()
+	if !res.continuous() {
+		res -= 0x10
+	}
+	if interval < timeout[res] {
+		return nil, errors.New("bh1750: interval shorter than the measurement time")
+	}
+	d.stopSensing()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.setResolution(res); err != nil {
+		return nil, err
+	}
+	sensing := make(chan physic.LuminousFlux)
+	stop := make(chan struct{})
+	d.stop = stop
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		defer close(sensing)
+		d.sensingContinuous(interval, sensing, stop)
+	}()
+	return sensing, nil
+}
+
+// Precision returns the resolution of the measurements, in lux.
+func (d *Dev) Precision() physic.LuminousFlux {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	switch d.res {
+	case ContinuousHighResMode2, OneTimeHighResMode2:
+		return physic.Lumen / 2
+	case ContinuousLowResMode, OneTimeLowResMode:
+		return 4 * physic.Lumen
+	default:
+		return physic.Lumen
+	}
+}
+
+// Halt stops continuous sensing, if any, and turns off the device.
+func (d *Dev) Halt() error {
+	d.stopSensing()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.setMode(PowerDown)
+}
 
-	buf := [2]byte{}
-	if err := d.dev.Tx([]byte{byte(d.res)}, buf[:]); err != nil {
+//
+
+var sleep = time.Sleep
+
+// stopSensing stops the continuous sensing, if any. The lock must not be held,
+// since the sensing goroutine takes it after each tick.
+func (d *Dev) stopSensing() {
+	d.mu.Lock()
+	if d.stop != nil {
+		close(d.stop)
+		d.stop = nil
+	}
+	d.mu.Unlock()
+	d.wg.Wait()
+}
+
+func (r Resolution) continuous() bool {
+	return r&0x10 != 0
+}
+
+func (d *Dev) setMode(m Mode) error {
+	d.mode = m
+	_, err := d.dev.Write([]byte{byte(m)})
+	return err
+}
+
+func (d *Dev) setResolution(r Resolution) error {
+	if _, ok := timeout[r]; !ok {
+		return errors.New("bh1750: invalid resolution")
+	}
+	d.res = r
+	_, err := d.dev.Write([]byte{byte(r)})
+	d.started = time.Now()
+	return err
+}
+
+func (d *Dev) sense() (physic.LuminousFlux, error) {
+	if !d.res.continuous() {
+		if err := d.setResolution(d.res); err != nil {
+			return 0, err
+		}
+	}
+	if w := timeout[d.res] - time.Since(d.started); w > 0 {
+		sleep(w)
+	}
+	var buf [2]byte
+	if err := d.dev.Tx(nil, buf[:]); err != nil {
 		return 0, err
 	}
+	return d.toLux(binary.BigEndian.Uint16(buf[:])), nil
+}
 
-	rawValue := binary.BigEndian.Uint16(buf[:])
-	lux := physic.LuminousFlux(float64(rawValue) / 1.2)
-	return lux * physic.Lumen, nil
+// toLux converts the raw count to lux. The count is divided by 1.2, and by 2
+// in the 0.5lx resolution modes.
+func (d *Dev) toLux(raw uint16) physic.LuminousFlux {
+	lux := physic.LuminousFlux(raw) * physic.Lumen * 5 / 6
+	if d.res == ContinuousHighResMode2 || d.res == OneTimeHighResMode2 {
+		lux /= 2
+	}
+	return lux
 }
 
-// Halt turn off device.
-func (d *Dev) Halt() error {
-	return d.SetMode(PowerDown)
+func (d *Dev) sensingContinuous(interval time.Duration, sensing chan<- physic.LuminousFlux, stop <-chan struct{}) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+		}
+		d.mu.Lock()
+		lux, err := d.sense()
+		d.mu.Unlock()
+		if err != nil {
+			log.Printf("%s: failed to sense: %v", d, err)
+			return
+		}
+		select {
+		case sensing <- lux:
+		case <-stop:
+			return
+		}
+	}
 }
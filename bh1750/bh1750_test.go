@@ -0,0 +1,177 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package bh1750
+
+import (
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/i2c/i2ctest"
+	"periph.io/x/conn/v3/physic"
+)
+
+var initOps = []i2ctest.IO{
+	{Addr: I2CAddr, W: []byte{byte(PowerOn)}},
+	{Addr: I2CAddr, W: []byte{reset}},
+	{Addr: I2CAddr, W: []byte{byte(ContinuousHighResMode)}},
+}
+
+func TestSense(t *testing.T) {
+	defer func(f func(time.Duration)) { sleep = f }(sleep)
+	var slept time.Duration
+	sleep = func(d time.Duration) { slept += d }
+	bus := i2ctest.Playback{
+		Ops: append(initOps,
+			// Continuous high resolution, 600 / 1.2.
+			i2ctest.IO{Addr: I2CAddr, R: []byte{0x02, 0x58}},
+			// One time high resolution 2, 600 / 1.2 / 2.
+			i2ctest.IO{Addr: I2CAddr, W: []byte{byte(OneTimeHighResMode2)}},
+			i2ctest.IO{Addr: I2CAddr, W: []byte{byte(OneTimeHighResMode2)}},
+			i2ctest.IO{Addr: I2CAddr, R: []byte{0x02, 0x58}},
+			i2ctest.IO{Addr: I2CAddr, W: []byte{byte(PowerDown)}},
+		),
+	}
+	dev, err := NewI2C(&bus, I2CAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := dev.String(); s != "BH1750{playback(35)}" {
+		t.Fatal(s)
+	}
+	lux, err := dev.Sense()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lux != 500*physic.Lumen {
+		t.Fatal(lux)
+	}
+	if slept <= 0 || slept > timeout[ContinuousHighResMode] {
+		t.Fatal(slept)
+	}
+	if err := dev.SetResolution(OneTimeHighResMode2); err != nil {
+		t.Fatal(err)
+	}
+	if p := dev.Precision(); p != physic.Lumen/2 {
+		t.Fatal(p)
+	}
+	if lux, err = dev.Sense(); err != nil {
+		t.Fatal(err)
+	}
+	if lux != 250*physic.Lumen {
+		t.Fatal(lux)
+	}
+	if err := dev.SetResolution(Resolution(0x42)); err == nil {
+		t.Fatal("expected error")
+	}
+	if err := dev.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSenseContinuous(t *testing.T) {
+	defer func(f func(time.Duration)) { sleep = f }(sleep)
+	sleep = func(time.Duration) {}
+	bus := i2ctest.Playback{
+		Ops: append(initOps,
+			i2ctest.IO{Addr: I2CAddr, W: []byte{byte(OneTimeLowResMode)}},
+			// Switched to the continuous mode of the same resolution.
+			i2ctest.IO{Addr: I2CAddr, W: []byte{byte(ContinuousLowResMode)}},
+			i2ctest.IO{Addr: I2CAddr, R: []byte{0x00, 0x0C}},
+			i2ctest.IO{Addr: I2CAddr, W: []byte{byte(PowerDown)}},
+		),
+	}
+	dev, err := NewI2C(&bus, I2CAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.SetResolution(OneTimeLowResMode); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dev.SenseContinuous(time.Millisecond); err == nil {
+		t.Fatal("expected error")
+	}
+	c, err := dev.SenseContinuous(50 * time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dev.Sense(); err == nil {
+		t.Fatal("expected error while sensing continuously")
+	}
+	if lux := <-c; lux != 10*physic.Lumen {
+		t.Fatal(lux)
+	}
+	if err := dev.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := <-c; ok {
+		t.Fatal("channel not closed")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHalt_sensing(t *testing.T) {
+	defer func(f func(time.Duration)) { sleep = f }(sleep)
+	sleep = func(time.Duration) {}
+	// The reads are slower than the interval, so a tick is always pending
+	// when Halt is called.
+	interval := timeout[ContinuousLowResMode]
+	for i := 0; i < 10; i++ {
+		dev, err := NewI2C(&slowBus{delay: 2 * interval}, I2CAddr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := dev.SetResolution(ContinuousLowResMode); err != nil {
+			t.Fatal(err)
+		}
+		c, err := dev.SenseContinuous(interval)
+		if err != nil {
+			t.Fatal(err)
+		}
+		go func() {
+			for range c {
+			}
+		}()
+		// Halt while a read is in progress.
+		time.Sleep(interval)
+		done := make(chan error)
+		go func() { done <- dev.Halt() }()
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatal(err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Halt() deadlocked")
+		}
+	}
+}
+
+// slowBus is an i2c.Bus where the reads take delay.
+type slowBus struct {
+	delay time.Duration
+}
+
+func (b *slowBus) String() string {
+	return "slowBus"
+}
+
+func (b *slowBus) Tx(addr uint16, w, r []byte) error {
+	if len(r) != 0 {
+		time.Sleep(b.delay)
+	}
+	return nil
+}
+
+func (b *slowBus) SetSpeed(f physic.Frequency) error {
+	return nil
+}
+
+var _ i2c.Bus = &slowBus{}
@@ -4,6 +4,11 @@
 
 // Package bh1750 controls a ROHM BH1750 ambient light sensor, over an i2c bus.
 //
+// The illuminance is returned in lux as a physic.LuminousFlux, that is in
+// lumen per square metre. The sensor measures at 0.5lx, 1lx or 4lx resolution,
+// either continuously or one time, powering down after each measurement.
+// SenseContinuous returns the measurements on a channel.
+//
 // # Datasheet
 //
 // http://cpre.kmutnb.ac.th/esl/learning/bh1750-light-sensor/bh1750fvi-e_datasheet.pdf
@@ -0,0 +1,405 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package bmp3xx
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/physic"
+)
+
+// Oversampling is the number of samples averaged per measurement.
+type Oversampling uint8
+
+const (
+	O1x  Oversampling = 0
+	O2x  Oversampling = 1
+	O4x  Oversampling = 2
+	O8x  Oversampling = 3
+	O16x Oversampling = 4
+	O32x Oversampling = 5
+)
+
+func (o Oversampling) String() string {
+	if o > O32x {
+		return fmt.Sprintf("Oversampling(%d)", o)
+	}
+	return fmt.Sprintf("%dx", 1<<o)
+}
+
+// Filter is the coefficient of the IIR filter applied to the pressure and
+// temperature.
+type Filter uint8
+
+const (
+	NoFilter Filter = 0
+	F1       Filter = 1
+	F3       Filter = 2
+	F7       Filter = 3
+	F15      Filter = 4
+	F31      Filter = 5
+	F63      Filter = 6
+	F127     Filter = 7
+)
+
+// StandardSeaLevel is the standard atmospheric pressure at sea level.
+const StandardSeaLevel = 101325 * physic.Pascal
+
+// Altitude converts the pressure p to an altitude, p0 being the pressure at
+// sea level.
+func Altitude(p, p0 physic.Pressure) physic.Distance {
+	h := 44330 * (1 - math.Pow(float64(p)/float64(p0), 1/5.255))
+	return physic.Distance(h * float64(physic.Metre))
+}
+
+// SeaLevelPressure converts the pressure p measured at the altitude h to
+// the pressure at sea level.
+func SeaLevelPressure(p physic.Pressure, h physic.Distance) physic.Pressure {
+	r := math.Pow(1-float64(h)/float64(physic.Metre)/44330, 5.255)
+	return physic.Pressure(float64(p) / r)
+}
+
+// Opts defines the options for the device.
+//
+// Recommended settings as per the datasheet:
+//
+// → Weather monitoring: pressure and temperature O1x, NoFilter, one
+// measurement per minute.
+//
+// → Drone: pressure O8x, temperature O1x, F3, 50Hz.
+//
+// → Indoor navigation: pressure O16x, temperature O2x, F3, 25Hz.
+type Opts struct {
+	Temperature Oversampling
+	Pressure    Oversampling
+	Filter      Filter
+}
+
+// DefaultOpts is the recommended default options.
+var DefaultOpts = Opts{
+	Temperature: O1x,
+	Pressure:    O8x,
+}
+
+// Dev is a handle to a BMP388 or BMP390.
+type Dev struct {
+	c    i2c.Dev
+	name string
+	opts Opts
+	cal  calibration
+
+	mu   sync.Mutex
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewI2C returns a handle to a BMP388 or BMP390 at address addr, 0x76 when
+// SDO is low or 0x77 when it's high.
+//
+// The device is reset, its calibration read and it is left in sleep mode.
+func NewI2C(b i2c.Bus, addr uint16, opts *Opts) (*Dev, error) {
+	switch addr {
+	case 0x76, 0x77:
+	default:
+		return nil, errors.New("bmp3xx: given address not supported by device")
+	}
+	if opts.Temperature > O32x || opts.Pressure > O32x || opts.Filter > F127 {
+		return nil, errors.New("bmp3xx: invalid options")
+	}
+	d := &Dev{c: i2c.Dev{Bus: b, Addr: addr}, opts: *opts}
+	var id [1]byte
+	if err := d.readReg(regChipID, id[:]); err != nil {
+		return nil, err
+	}
+	switch id[0] {
+	case 0x50:
+		d.name = "BMP388"
+	case 0x60:
+		d.name = "BMP390"
+	default:
+		return nil, fmt.Errorf("bmp3xx: unexpected chip id %#x", id[0])
+	}
+	if err := d.writeReg(regCmd, cmdSoftReset); err != nil {
+		return nil, err
+	}
+	doSleep(resetTime)
+	var nvm [21]byte
+	if err := d.readReg(regCalib, nvm[:]); err != nil {
+		return nil, err
+	}
+	d.cal = newCalibration(nvm[:])
+	if err := d.writeReg(regOSR, byte(opts.Temperature)<<3|byte(opts.Pressure)); err != nil {
+		return nil, err
+	}
+	if err := d.writeReg(regConfig, byte(opts.Filter)<<1); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *Dev) String() string {
+	return fmt.Sprintf("%s{%s}", d.name, &d.c)
+}
+
+// Sense requests a one time measurement of the temperature and the
+// pressure.
+func (d *Dev) Sense(e *physic.Env) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.stop != nil {
+		return errors.New("bmp3xx: already sensing continuously")
+	}
+	if err := d.writeReg(regPwrCtrl, pwrPressEn|pwrTempEn|modeForced); err != nil {
+		return err
+	}
+	doSleep(d.measDelay())
+	for i := 0; ; i++ {
+		var s [1]byte
+		if err := d.readReg(regStatus, s[:]); err != nil {
+			return err
+		}
+		if s[0]&(statusDrdyPress|statusDrdyTemp) == statusDrdyPress|statusDrdyTemp {
+			break
+		}
+		if i == 10 {
+			return errors.New("bmp3xx: measurement timed out")
+		}
+		doSleep(time.Millisecond)
+	}
+	return d.sense(e)
+}
+
+// SenseContinuous returns measurements on a continuous basis.
+//
+// The device measures in normal mode, at the highest output data rate
+// slower than interval and compatible with the oversampling.
+//
+// The application must call Halt() to stop the sensing when done and close
+// the channel.
+func (d *Dev) SenseContinuous(interval time.Duration) (<-chan physic.Env, error) {
+	d.stopSensing()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.writeReg(regPwrCtrl, 0); err != nil {
+		return nil, err
+	}
+	if err := d.writeReg(regODR, chooseODR(interval, d.measDelay())); err != nil {
+		return nil, err
+	}
+	if err := d.writeReg(regPwrCtrl, pwrPressEn|pwrTempEn|modeNormal); err != nil {
+		return nil, err
+	}
+	var e [1]byte
+	if err := d.readReg(regErr, e[:]); err != nil {
+		return nil, err
+	}
+	if e[0]&errConf != 0 {
+		return nil, errors.New("bmp3xx: output data rate too high for the oversampling")
+	}
+	sensing := make(chan physic.Env)
+	stop := make(chan struct{})
+	d.stop = stop
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		defer close(sensing)
+		d.sensingContinuous(interval, sensing, stop)
+	}()
+	return sensing, nil
+}
+
+// Precision implements physic.SenseEnv.
+func (d *Dev) Precision(e *physic.Env) {
+	e.Temperature = 5 * physic.MilliKelvin >> d.opts.Temperature
+	e.Pressure = 2640 * physic.MilliPascal >> d.opts.Pressure
+}
+
+// Halt stops continuous sensing and puts the device in sleep mode.
+//
+// Halt implements conn.Resource.
+func (d *Dev) Halt() error {
+	if !d.stopSensing() {
+		return nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.writeReg(regPwrCtrl, 0)
+}
+
+//
+
+// Registers.
+const (
+	regChipID  = 0x00
+	regErr     = 0x02
+	regStatus  = 0x03
+	regData    = 0x04
+	regPwrCtrl = 0x1B
+	regOSR     = 0x1C
+	regODR     = 0x1D
+	regConfig  = 0x1F
+	regCalib   = 0x31
+	regCmd     = 0x7E
+)
+
+const (
+	errConf = 0x04
+
+	statusDrdyPress = 0x20
+	statusDrdyTemp  = 0x40
+
+	pwrPressEn = 0x01
+	pwrTempEn  = 0x02
+	modeForced = 0x10
+	modeNormal = 0x30
+
+	cmdSoftReset = 0xB6
+
+	resetTime = 2 * time.Millisecond
+	// odrMax is the highest output data rate selection, 5ms<<17 = 655s.
+	odrMax = 17
+)
+
+// calibration holds the compensation coefficients, converted to floating
+// point as per section 9.1 of the datasheet.
+type calibration struct {
+	t1, t2, t3                                   float64
+	p1, p2, p3, p4, p5, p6, p7, p8, p9, p10, p11 float64
+}
+
+func newCalibration(b []byte) calibration {
+	u16 := func(i int) float64 { return float64(binary.LittleEndian.Uint16(b[i:])) }
+	i16 := func(i int) float64 { return float64(int16(binary.LittleEndian.Uint16(b[i:]))) }
+	i8 := func(i int) float64 { return float64(int8(b[i])) }
+	return calibration{
+		t1:  u16(0) * (1 << 8),
+		t2:  u16(2) / (1 << 30),
+		t3:  i8(4) / (1 << 48),
+		p1:  (i16(5) - (1 << 14)) / (1 << 20),
+		p2:  (i16(7) - (1 << 14)) / (1 << 29),
+		p3:  i8(9) / (1 << 32),
+		p4:  i8(10) / (1 << 37),
+		p5:  u16(11) * (1 << 3),
+		p6:  u16(13) / (1 << 6),
+		p7:  i8(15) / (1 << 8),
+		p8:  i8(16) / (1 << 15),
+		p9:  i16(17) / (1 << 48),
+		p10: i8(19) / (1 << 48),
+		p11: i8(20) / (1 << 65),
+	}
+}
+
+// compensate returns the temperature in °C and the pressure in Pa from the
+// raw values.
+func (c *calibration) compensate(up, ut uint32) (float64, float64) {
+	d1 := float64(ut) - c.t1
+	t := d1*c.t2 + d1*d1*c.t3
+
+	t2, t3 := t*t, t*t*t
+	out1 := c.p5 + c.p6*t + c.p7*t2 + c.p8*t3
+	p := float64(up)
+	out2 := p * (c.p1 + c.p2*t + c.p3*t2 + c.p4*t3)
+	out3 := p*p*(c.p9+c.p10*t) + p*p*p*c.p11
+	return t, out1 + out2 + out3
+}
+
+// measDelay returns the typical conversion time as per section 3.9.2.
+func (d *Dev) measDelay() time.Duration {
+	µs := 234 + 392 + (2000 << d.opts.Pressure) + 163 + (2000 << d.opts.Temperature)
+	return time.Duration(µs) * time.Microsecond
+}
+
+// chooseODR returns the output data rate selection with the longest period
+// not longer than interval, but long enough for a measurement.
+func chooseODR(interval, meas time.Duration) byte {
+	var odr byte
+	for odr < odrMax && 5*time.Millisecond<<(odr+1) <= interval {
+		odr++
+	}
+	for odr < odrMax && 5*time.Millisecond<<odr < meas {
+		odr++
+	}
+	return odr
+}
+
+func (d *Dev) sense(e *physic.Env) error {
+	var b [6]byte
+	if err := d.readReg(regData, b[:]); err != nil {
+		return err
+	}
+	up := uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16
+	ut := uint32(b[3]) | uint32(b[4])<<8 | uint32(b[5])<<16
+	t, p := d.cal.compensate(up, ut)
+	e.Temperature = physic.ZeroCelsius + physic.Temperature(math.Round(t*float64(physic.Kelvin)))
+	e.Pressure = physic.Pressure(math.Round(p * float64(physic.Pascal)))
+	return nil
+}
+
+// stopSensing stops the sensing goroutine, if any, and reports whether it was
+// running. The lock must not be held, since the goroutine takes it after each
+// tick.
+func (d *Dev) stopSensing() bool {
+	d.mu.Lock()
+	running := d.stop != nil
+	if running {
+		close(d.stop)
+		d.stop = nil
+	}
+	d.mu.Unlock()
+	d.wg.Wait()
+	return running
+}
+
+func (d *Dev) sensingContinuous(interval time.Duration, sensing chan<- physic.Env, stop <-chan struct{}) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+		}
+		e := physic.Env{}
+		d.mu.Lock()
+		err := d.sense(&e)
+		d.mu.Unlock()
+		if err != nil {
+			log.Printf("%s: failed to sense: %v", d, err)
+			return
+		}
+		select {
+		case sensing <- e:
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (d *Dev) readReg(reg byte, b []byte) error {
+	if err := d.c.Tx([]byte{reg}, b); err != nil {
+		return fmt.Errorf("bmp3xx: %v", err)
+	}
+	return nil
+}
+
+func (d *Dev) writeReg(reg, v byte) error {
+	if err := d.c.Tx([]byte{reg, v}, nil); err != nil {
+		return fmt.Errorf("bmp3xx: %v", err)
+	}
+	return nil
+}
+
+var doSleep = time.Sleep
+
+var _ conn.Resource = &Dev{}
+var _ physic.SenseEnv = &Dev{}
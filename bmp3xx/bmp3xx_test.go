@@ -0,0 +1,160 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package bmp3xx
+
+import (
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3/i2c/i2ctest"
+	"periph.io/x/conn/v3/physic"
+)
+
+// nvm is a calibration where the temperature is 25°C for a raw value of
+// 0x790000, and the pressure is 100000Pa + raw/1024.
+var nvm = []byte{
+	0x00, 0x60, 0x00, 0x40, 0x00, 0x00, 0x44, 0x00, 0x40, 0x00, 0x00,
+	0xD4, 0x30, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+}
+
+var initOps = []i2ctest.IO{
+	{Addr: 0x77, W: []byte{regChipID}, R: []byte{0x60}},
+	{Addr: 0x77, W: []byte{regCmd, cmdSoftReset}},
+	{Addr: 0x77, W: []byte{regCalib}, R: nvm},
+	{Addr: 0x77, W: []byte{regOSR, 0x03}},
+	{Addr: 0x77, W: []byte{regConfig, 0x00}},
+}
+
+func TestNewI2C(t *testing.T) {
+	defer func(f func(time.Duration)) { doSleep = f }(doSleep)
+	doSleep = func(time.Duration) {}
+	if _, err := NewI2C(&i2ctest.Playback{}, 0x10, &DefaultOpts); err == nil {
+		t.Fatal("expected error on address")
+	}
+	if _, err := NewI2C(&i2ctest.Playback{}, 0x77, &Opts{Pressure: 6}); err == nil {
+		t.Fatal("expected error on oversampling")
+	}
+	bus := i2ctest.Playback{Ops: []i2ctest.IO{{Addr: 0x77, W: []byte{regChipID}, R: []byte{0x58}}}}
+	if _, err := NewI2C(&bus, 0x77, &DefaultOpts); err == nil {
+		t.Fatal("expected error on chip id")
+	}
+}
+
+func TestSense(t *testing.T) {
+	defer func(f func(time.Duration)) { doSleep = f }(doSleep)
+	var slept time.Duration
+	doSleep = func(d time.Duration) { slept += d }
+	bus := i2ctest.Playback{
+		Ops: append(initOps,
+			i2ctest.IO{Addr: 0x77, W: []byte{regPwrCtrl, 0x13}},
+			i2ctest.IO{Addr: 0x77, W: []byte{regStatus}, R: []byte{0x30}},
+			i2ctest.IO{Addr: 0x77, W: []byte{regStatus}, R: []byte{0x70}},
+			i2ctest.IO{Addr: 0x77, W: []byte{regData}, R: []byte{0x00, 0xD0, 0x07, 0x00, 0x00, 0x79}},
+		),
+	}
+	d, err := NewI2C(&bus, 0x77, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := d.String(); s != "BMP390{playback(119)}" {
+		t.Fatal(s)
+	}
+	slept = 0
+	e := physic.Env{}
+	if err := d.Sense(&e); err != nil {
+		t.Fatal(err)
+	}
+	if want := physic.ZeroCelsius + 25*physic.Kelvin; e.Temperature != want {
+		t.Fatal(e.Temperature)
+	}
+	if e.Pressure != 100500*physic.Pascal {
+		t.Fatal(e.Pressure)
+	}
+	// 234 + 392 + 16000 + 163 + 2000µs, then 1ms for the status.
+	if slept != 19789*time.Microsecond {
+		t.Fatal(slept)
+	}
+	d.Precision(&e)
+	if e.Temperature != 5*physic.MilliKelvin || e.Pressure != 330*physic.MilliPascal {
+		t.Fatal(e)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSenseContinuous(t *testing.T) {
+	defer func(f func(time.Duration)) { doSleep = f }(doSleep)
+	doSleep = func(time.Duration) {}
+	bus := i2ctest.Playback{
+		Ops: append(initOps,
+			i2ctest.IO{Addr: 0x77, W: []byte{regPwrCtrl, 0x00}},
+			// 40ms.
+			i2ctest.IO{Addr: 0x77, W: []byte{regODR, 0x03}},
+			i2ctest.IO{Addr: 0x77, W: []byte{regPwrCtrl, 0x33}},
+			i2ctest.IO{Addr: 0x77, W: []byte{regErr}, R: []byte{0x00}},
+			i2ctest.IO{Addr: 0x77, W: []byte{regData}, R: []byte{0x00, 0xD0, 0x07, 0x00, 0x00, 0x79}},
+			i2ctest.IO{Addr: 0x77, W: []byte{regPwrCtrl, 0x00}},
+		),
+	}
+	d, err := NewI2C(&bus, 0x77, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := d.SenseContinuous(50 * time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := <-c; e.Pressure != 100500*physic.Pascal {
+		t.Fatal(e)
+	}
+	if err := d.Sense(&physic.Env{}); err == nil {
+		t.Fatal("expected error while sensing continuously")
+	}
+	if err := d.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := <-c; ok {
+		t.Fatal("channel not closed")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestChooseODR(t *testing.T) {
+	data := []struct {
+		interval, meas time.Duration
+		want           byte
+	}{
+		{time.Millisecond, time.Millisecond, 0},
+		{20 * time.Millisecond, 5 * time.Millisecond, 2},
+		{20 * time.Millisecond, 30 * time.Millisecond, 3},
+		{time.Hour, 0, odrMax},
+	}
+	for _, line := range data {
+		if got := chooseODR(line.interval, line.meas); got != line.want {
+			t.Fatalf("chooseODR(%s, %s) = %d, want %d", line.interval, line.meas, got, line.want)
+		}
+	}
+}
+
+func TestAltitude(t *testing.T) {
+	if h := Altitude(89875*physic.Pascal, StandardSeaLevel); h < 999*physic.Metre || h > 1001*physic.Metre {
+		t.Fatal(h)
+	}
+	if p := SeaLevelPressure(89875*physic.Pascal, 1000*physic.Metre); p < 101320*physic.Pascal || p > 101330*physic.Pascal {
+		t.Fatal(p)
+	}
+}
+
+func TestOversampling_String(t *testing.T) {
+	if s := O16x.String(); s != "16x" {
+		t.Fatal(s)
+	}
+	if s := Oversampling(6).String(); s != "Oversampling(6)" {
+		t.Fatal(s)
+	}
+}
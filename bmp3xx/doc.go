@@ -0,0 +1,23 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package bmp3xx controls a Bosch BMP388 or BMP390 barometric pressure
+// sensor over I²C.
+//
+// The BMP3xx is the successor of the BMP280 supported by package bmxx80,
+// with a lower noise of 3cm of altitude at the highest oversampling. The
+// calibration coefficients are read from the NVM on initialization and the
+// compensation is done in floating point, as recommended by Bosch.
+//
+// Altitude and SeaLevelPressure convert a pressure to an altitude with the
+// international barometric formula, and back.
+//
+// # Datasheets
+//
+// BMP388:
+// https://www.bosch-sensortec.com/media/boschsensortec/downloads/datasheets/bst-bmp388-ds001.pdf
+//
+// BMP390:
+// https://www.bosch-sensortec.com/media/boschsensortec/downloads/datasheets/bst-bmp390-ds002.pdf
+package bmp3xx
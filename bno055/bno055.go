@@ -0,0 +1,446 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package bno055
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/physic"
+)
+
+// Mode is the operation mode of the device.
+//
+// The modes up to ModeAMG report the raw sensor data. The fusion modes, from
+// ModeIMU, also compute the orientation.
+type Mode byte
+
+const (
+	// ModeConfig is the mode in which the configuration and the calibration
+	// offsets can be changed. No data is produced.
+	ModeConfig  Mode = 0x00
+	ModeAccOnly Mode = 0x01
+	ModeMagOnly Mode = 0x02
+	ModeGyrOnly Mode = 0x03
+	ModeAccMag  Mode = 0x04
+	ModeAccGyro Mode = 0x05
+	ModeMagGyro Mode = 0x06
+	ModeAMG     Mode = 0x07
+	// ModeIMU computes the relative orientation from the accelerometer and
+	// the gyroscope.
+	ModeIMU Mode = 0x08
+	// ModeCompass computes the heading from the accelerometer and the
+	// magnetometer.
+	ModeCompass Mode = 0x09
+	// ModeM4G computes the relative orientation from the accelerometer and
+	// the magnetometer.
+	ModeM4G Mode = 0x0A
+	// ModeNDOFFMCOff is ModeNDOF with the fast magnetometer calibration
+	// disabled.
+	ModeNDOFFMCOff Mode = 0x0B
+	// ModeNDOF computes the absolute orientation from all three sensors.
+	ModeNDOF Mode = 0x0C
+)
+
+func (m Mode) String() string {
+	switch m {
+	case ModeConfig:
+		return "Config"
+	case ModeAccOnly:
+		return "AccOnly"
+	case ModeMagOnly:
+		return "MagOnly"
+	case ModeGyrOnly:
+		return "GyrOnly"
+	case ModeAccMag:
+		return "AccMag"
+	case ModeAccGyro:
+		return "AccGyro"
+	case ModeMagGyro:
+		return "MagGyro"
+	case ModeAMG:
+		return "AMG"
+	case ModeIMU:
+		return "IMU"
+	case ModeCompass:
+		return "Compass"
+	case ModeM4G:
+		return "M4G"
+	case ModeNDOFFMCOff:
+		return "NDOFFMCOff"
+	case ModeNDOF:
+		return "NDOF"
+	default:
+		return fmt.Sprintf("Mode(%d)", byte(m))
+	}
+}
+
+// fusion returns true if the mode runs the sensor fusion algorithm.
+func (m Mode) fusion() bool {
+	return m >= ModeIMU && m <= ModeNDOF
+}
+
+// Acceleration is an acceleration stored as an int64 micro metre per second
+// squared.
+//
+// There is no acceleration unit in periph.io/x/conn/v3/physic.
+type Acceleration int64
+
+const (
+	MicroMetrePerSecond2 Acceleration = 1
+	MilliMetrePerSecond2 Acceleration = 1000 * MicroMetrePerSecond2
+	MetrePerSecond2      Acceleration = 1000 * MilliMetrePerSecond2
+)
+
+// String returns the acceleration in m/s² with a mm/s² resolution.
+func (a Acceleration) String() string {
+	return strconv.FormatFloat(float64(a/MilliMetrePerSecond2)/1e3, 'f', -1, 64) + "m/s²"
+}
+
+// Euler is an orientation as Euler angles.
+type Euler struct {
+	// Heading is from 0 to 360°.
+	Heading physic.Angle
+	// Roll is from -90 to 90°.
+	Roll physic.Angle
+	// Pitch is from -180 to 180°.
+	Pitch physic.Angle
+}
+
+// Quaternion is an orientation as a unit quaternion.
+type Quaternion struct {
+	W, X, Y, Z float64
+}
+
+// CalibrationStatus is the calibration progress of the sensors and of the
+// fusion algorithm, each from 0 (not calibrated) to 3 (fully calibrated).
+type CalibrationStatus struct {
+	System byte
+	Gyro   byte
+	Accel  byte
+	Mag    byte
+}
+
+// Calibrated returns true if everything is fully calibrated.
+func (c CalibrationStatus) Calibrated() bool {
+	return c.System == 3 && c.Gyro == 3 && c.Accel == 3 && c.Mag == 3
+}
+
+func (c CalibrationStatus) String() string {
+	return fmt.Sprintf("sys=%d gyro=%d accel=%d mag=%d", c.System, c.Gyro, c.Accel, c.Mag)
+}
+
+// Opts holds the configuration options.
+type Opts struct {
+	// Address is the I²C address, 0x28 or 0x29. 0 means 0x28.
+	Address uint16
+	// Mode is the operation mode to start in. 0 (ModeConfig) means ModeNDOF.
+	Mode Mode
+	// ExternalCrystal uses the external 32.768kHz crystal as clock source,
+	// which improves the accuracy of the fusion. Most breakout boards have
+	// one.
+	ExternalCrystal bool
+	// Calibration, if set, is restored on start.
+	Calibration *Calibration
+}
+
+// DefaultOpts is the recommended default options.
+var DefaultOpts = Opts{
+	Address: 0x28,
+	Mode:    ModeNDOF,
+}
+
+// Dev is a handle to a BNO055.
+type Dev struct {
+	mu        sync.Mutex
+	c         i2c.Dev
+	mode      Mode
+	suspended bool
+}
+
+// New opens a handle to the device and applies opts.
+func New(bus i2c.Bus, opts *Opts) (*Dev, error) {
+	addr := opts.Address
+	if addr == 0 {
+		addr = DefaultOpts.Address
+	}
+	if addr != 0x28 && addr != 0x29 {
+		return nil, errAddressOutOfRange
+	}
+	d := &Dev{c: i2c.Dev{Bus: bus, Addr: addr}}
+	if err := d.writeReg(pageID, 0); err != nil {
+		return nil, err
+	}
+	id, err := d.readReg(chipID)
+	if err != nil {
+		return nil, err
+	}
+	if id != chipIDValue {
+		// The device doesn't answer with its ID until it is done booting.
+		sleep(bootTime)
+		if id, err = d.readReg(chipID); err != nil {
+			return nil, err
+		}
+		if id != chipIDValue {
+			return nil, fmt.Errorf("bno055: unexpected chip ID %#x", id)
+		}
+	}
+	if err := d.setMode(ModeConfig); err != nil {
+		return nil, err
+	}
+	if err := d.writeReg(pwrMode, pwrNormal); err != nil {
+		return nil, err
+	}
+	// m/s², degrees, degrees per second and °C, Windows orientation.
+	if err := d.writeReg(unitSel, 0x00); err != nil {
+		return nil, err
+	}
+	var trigger byte
+	if opts.ExternalCrystal {
+		trigger = sysTriggerClkSel
+	}
+	if err := d.writeReg(sysTrigger, trigger); err != nil {
+		return nil, err
+	}
+	if opts.Calibration != nil {
+		if err := d.writeCalibration(opts.Calibration); err != nil {
+			return nil, err
+		}
+	}
+	mode := opts.Mode
+	if mode == ModeConfig {
+		mode = ModeNDOF
+	}
+	if err := d.setMode(mode); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *Dev) String() string {
+	return fmt.Sprintf("BNO055{%s}", &d.c)
+}
+
+// Mode returns the current operation mode.
+func (d *Dev) Mode() Mode {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.mode
+}
+
+// SetMode changes the operation mode.
+func (d *Dev) SetMode(m Mode) error {
+	if m > ModeNDOF {
+		return errInvalidMode
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.suspended {
+		if err := d.setMode(ModeConfig); err != nil {
+			return err
+		}
+		if err := d.writeReg(pwrMode, pwrNormal); err != nil {
+			return err
+		}
+		d.suspended = false
+	}
+	return d.setMode(m)
+}
+
+// Euler returns the orientation as Euler angles.
+//
+// It is only available in the fusion modes.
+func (d *Dev) Euler() (Euler, error) {
+	var v [3]int16
+	if err := d.readFusion(eulData, v[:]); err != nil {
+		return Euler{}, err
+	}
+	// 16 LSB per degree.
+	return Euler{
+		Heading: physic.Angle(v[0]) * physic.Degree / 16,
+		Roll:    physic.Angle(v[1]) * physic.Degree / 16,
+		Pitch:   physic.Angle(v[2]) * physic.Degree / 16,
+	}, nil
+}
+
+// Quaternion returns the orientation as a unit quaternion.
+//
+// It is only available in the fusion modes.
+func (d *Dev) Quaternion() (Quaternion, error) {
+	var v [4]int16
+	if err := d.readFusion(quaData, v[:]); err != nil {
+		return Quaternion{}, err
+	}
+	// 2^14 LSB per unit.
+	const scale = 1 << 14
+	return Quaternion{
+		W: float64(v[0]) / scale,
+		X: float64(v[1]) / scale,
+		Y: float64(v[2]) / scale,
+		Z: float64(v[3]) / scale,
+	}, nil
+}
+
+// LinearAcceleration returns the acceleration on the X, Y and Z axes with the
+// gravity removed.
+//
+// It is only available in the fusion modes.
+func (d *Dev) LinearAcceleration() ([3]Acceleration, error) {
+	return d.readAcceleration(liaData)
+}
+
+// Gravity returns the gravity vector on the X, Y and Z axes.
+//
+// It is only available in the fusion modes.
+func (d *Dev) Gravity() ([3]Acceleration, error) {
+	return d.readAcceleration(grvData)
+}
+
+// Temperature returns the temperature, with a 1°C resolution.
+func (d *Dev) Temperature() (physic.Temperature, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	v, err := d.readReg(temp)
+	if err != nil {
+		return 0, err
+	}
+	return physic.ZeroCelsius + physic.Temperature(int8(v))*physic.Celsius, nil
+}
+
+// CalibrationStatus returns the calibration progress.
+func (d *Dev) CalibrationStatus() (CalibrationStatus, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	v, err := d.readReg(calibStat)
+	if err != nil {
+		return CalibrationStatus{}, err
+	}
+	return CalibrationStatus{
+		System: v >> 6 & 3,
+		Gyro:   v >> 4 & 3,
+		Accel:  v >> 2 & 3,
+		Mag:    v & 3,
+	}, nil
+}
+
+// Halt stops the sensors and puts the device in suspend mode.
+//
+// SetMode wakes it up.
+//
+// Halt implements conn.Resource.
+func (d *Dev) Halt() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.setMode(ModeConfig); err != nil {
+		return err
+	}
+	if err := d.writeReg(pwrMode, pwrSuspend); err != nil {
+		return err
+	}
+	d.suspended = true
+	return nil
+}
+
+//
+
+// Page 0 registers.
+const (
+	chipID      = 0x00
+	pageID      = 0x07
+	eulData     = 0x1A
+	quaData     = 0x20
+	liaData     = 0x28
+	grvData     = 0x2E
+	temp        = 0x34
+	calibStat   = 0x35
+	unitSel     = 0x3B
+	oprMode     = 0x3D
+	pwrMode     = 0x3E
+	sysTrigger  = 0x3F
+	accOffset   = 0x55
+	chipIDValue = 0xA0
+
+	pwrNormal  = 0x00
+	pwrSuspend = 0x02
+
+	sysTriggerClkSel = 0x80
+)
+
+// Time to switch from ModeConfig to another mode and back, and to boot.
+const (
+	fromConfigTime = 7 * time.Millisecond
+	toConfigTime   = 19 * time.Millisecond
+	bootTime       = 650 * time.Millisecond
+)
+
+var (
+	errAddressOutOfRange = errors.New("bno055: I²C address out of range")
+	errInvalidMode       = errors.New("bno055: invalid mode")
+	errNotFusionMode     = errors.New("bno055: only available in the fusion modes")
+)
+
+var sleep = time.Sleep
+
+// setMode switches the operation mode and waits for the switch to complete.
+func (d *Dev) setMode(m Mode) error {
+	if err := d.writeReg(oprMode, byte(m)); err != nil {
+		return err
+	}
+	if m == ModeConfig {
+		sleep(toConfigTime)
+	} else {
+		sleep(fromConfigTime)
+	}
+	d.mode = m
+	return nil
+}
+
+// readFusion reads the little endian fusion data registers starting at reg
+// into v.
+func (d *Dev) readFusion(reg byte, v []int16) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.mode.fusion() {
+		return errNotFusionMode
+	}
+	b := make([]byte, 2*len(v))
+	if err := d.c.Tx([]byte{reg}, b); err != nil {
+		return err
+	}
+	for i := range v {
+		v[i] = int16(binary.LittleEndian.Uint16(b[2*i:]))
+	}
+	return nil
+}
+
+func (d *Dev) readAcceleration(reg byte) ([3]Acceleration, error) {
+	var v [3]int16
+	if err := d.readFusion(reg, v[:]); err != nil {
+		return [3]Acceleration{}, err
+	}
+	// 100 LSB per m/s².
+	var a [3]Acceleration
+	for i := range v {
+		a[i] = Acceleration(v[i]) * 10 * MilliMetrePerSecond2
+	}
+	return a, nil
+}
+
+func (d *Dev) readReg(reg byte) (byte, error) {
+	var b [1]byte
+	err := d.c.Tx([]byte{reg}, b[:])
+	return b[0], err
+}
+
+func (d *Dev) writeReg(reg, v byte) error {
+	return d.c.Tx([]byte{reg, v}, nil)
+}
+
+var _ fmt.Stringer = &Dev{}
@@ -0,0 +1,246 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package bno055
+
+import (
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3/i2c/i2ctest"
+	"periph.io/x/conn/v3/physic"
+)
+
+func init() {
+	sleep = func(time.Duration) {}
+}
+
+var initNDOF = []i2ctest.IO{
+	{Addr: 0x28, W: []byte{pageID, 0x00}},
+	{Addr: 0x28, W: []byte{chipID}, R: []byte{0xA0}},
+	{Addr: 0x28, W: []byte{oprMode, 0x00}},
+	{Addr: 0x28, W: []byte{pwrMode, 0x00}},
+	{Addr: 0x28, W: []byte{unitSel, 0x00}},
+	{Addr: 0x28, W: []byte{sysTrigger, 0x00}},
+	{Addr: 0x28, W: []byte{oprMode, 0x0C}},
+}
+
+var calibrationBytes = []byte{
+	0xF6, 0xFF, 0x0A, 0x00, 0xE2, 0xFF, // accel
+	0x64, 0x00, 0x38, 0xFF, 0x00, 0x00, // mag
+	0xFF, 0xFF, 0x01, 0x00, 0x00, 0x00, // gyro
+	0xE8, 0x03, 0x7A, 0x02, // radius
+}
+
+var calibration = Calibration{
+	AccelOffset: [3]int16{-10, 10, -30},
+	MagOffset:   [3]int16{100, -200, 0},
+	GyroOffset:  [3]int16{-1, 1, 0},
+	AccelRadius: 1000,
+	MagRadius:   634,
+}
+
+func TestNew(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: append(initNDOF,
+			i2ctest.IO{Addr: 0x28, W: []byte{eulData}, R: []byte{0xA0, 0x05, 0xF0, 0xFF, 0x20, 0x00}},
+			i2ctest.IO{Addr: 0x28, W: []byte{quaData}, R: []byte{0x00, 0x40, 0x00, 0x00, 0x00, 0xE0, 0x00, 0x00}},
+			i2ctest.IO{Addr: 0x28, W: []byte{liaData}, R: []byte{0x64, 0x00, 0x9C, 0xFF, 0x01, 0x00}},
+			i2ctest.IO{Addr: 0x28, W: []byte{grvData}, R: []byte{0x00, 0x00, 0x00, 0x00, 0xD5, 0x03}},
+			i2ctest.IO{Addr: 0x28, W: []byte{temp}, R: []byte{0xFB}},
+			i2ctest.IO{Addr: 0x28, W: []byte{calibStat}, R: []byte{0xFF}},
+			i2ctest.IO{Addr: 0x28, W: []byte{oprMode, 0x00}},
+			i2ctest.IO{Addr: 0x28, W: []byte{pwrMode, 0x02}},
+		),
+	}
+	d, err := New(&bus, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := d.String(); s != "BNO055{playback(40)}" {
+		t.Fatal(s)
+	}
+	if m := d.Mode(); m != ModeNDOF {
+		t.Fatal(m)
+	}
+
+	e, err := d.Euler()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Euler{
+		Heading: 90 * physic.Degree,
+		Roll:    -physic.Degree,
+		Pitch:   2 * physic.Degree,
+	}
+	if e != want {
+		t.Fatalf("got %+v, want %+v", e, want)
+	}
+	q, err := d.Quaternion()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q != (Quaternion{W: 1, Y: -0.5}) {
+		t.Fatalf("got %+v", q)
+	}
+	a, err := d.LinearAcceleration()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a != [3]Acceleration{MetrePerSecond2, -MetrePerSecond2, 10 * MilliMetrePerSecond2} {
+		t.Fatalf("got %v", a)
+	}
+	g, err := d.Gravity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g[2] != 9810*MilliMetrePerSecond2 || g[2].String() != "9.81m/s²" {
+		t.Fatalf("got %v", g)
+	}
+	tmp, err := d.Temperature()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tmp != physic.ZeroCelsius-5*physic.Celsius {
+		t.Fatal(tmp)
+	}
+	c, err := d.CalibrationStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.Calibrated() || c.String() != "sys=3 gyro=3 accel=3 mag=3" {
+		t.Fatal(c)
+	}
+	if err := d.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNew_Opts(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x29, W: []byte{pageID, 0x00}},
+			{Addr: 0x29, W: []byte{chipID}, R: []byte{0x00}},
+			{Addr: 0x29, W: []byte{chipID}, R: []byte{0xA0}},
+			{Addr: 0x29, W: []byte{oprMode, 0x00}},
+			{Addr: 0x29, W: []byte{pwrMode, 0x00}},
+			{Addr: 0x29, W: []byte{unitSel, 0x00}},
+			{Addr: 0x29, W: []byte{sysTrigger, 0x80}},
+			{Addr: 0x29, W: append([]byte{accOffset}, calibrationBytes...)},
+			{Addr: 0x29, W: []byte{oprMode, 0x08}},
+		},
+	}
+	c := calibration
+	opts := Opts{Address: 0x29, Mode: ModeIMU, ExternalCrystal: true, Calibration: &c}
+	if _, err := New(&bus, &opts); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNew_Error(t *testing.T) {
+	if _, err := New(&i2ctest.Playback{}, &Opts{Address: 0x30}); err == nil {
+		t.Fatal("expected error")
+	}
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x28, W: []byte{pageID, 0x00}},
+			{Addr: 0x28, W: []byte{chipID}, R: []byte{0x00}},
+			{Addr: 0x28, W: []byte{chipID}, R: []byte{0x00}},
+		},
+	}
+	if _, err := New(&bus, &DefaultOpts); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestSetMode(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: append(initNDOF,
+			i2ctest.IO{Addr: 0x28, W: []byte{oprMode, 0x07}},
+			i2ctest.IO{Addr: 0x28, W: []byte{oprMode, 0x00}},
+			i2ctest.IO{Addr: 0x28, W: []byte{pwrMode, 0x02}},
+			i2ctest.IO{Addr: 0x28, W: []byte{oprMode, 0x00}},
+			i2ctest.IO{Addr: 0x28, W: []byte{pwrMode, 0x00}},
+			i2ctest.IO{Addr: 0x28, W: []byte{oprMode, 0x0C}},
+		),
+	}
+	d, err := New(&bus, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetMode(ModeAMG); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Euler(); err == nil {
+		t.Fatal("expected error outside of fusion mode")
+	}
+	if err := d.SetMode(0x20); err == nil {
+		t.Fatal("expected error")
+	}
+	if err := d.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Calibration(); err == nil {
+		t.Fatal("expected error while halted")
+	}
+	if err := d.SetMode(ModeNDOF); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCalibration(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: append(initNDOF,
+			i2ctest.IO{Addr: 0x28, W: []byte{oprMode, 0x00}},
+			i2ctest.IO{Addr: 0x28, W: []byte{accOffset}, R: calibrationBytes},
+			i2ctest.IO{Addr: 0x28, W: []byte{oprMode, 0x0C}},
+			i2ctest.IO{Addr: 0x28, W: []byte{oprMode, 0x00}},
+			i2ctest.IO{Addr: 0x28, W: append([]byte{accOffset}, calibrationBytes...)},
+			i2ctest.IO{Addr: 0x28, W: []byte{oprMode, 0x0C}},
+		),
+	}
+	d, err := New(&bus, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := d.Calibration()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *c != calibration {
+		t.Fatalf("got %+v, want %+v", *c, calibration)
+	}
+	if err := d.SetCalibration(c); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCalibration_Binary(t *testing.T) {
+	b, err := calibration.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var c Calibration
+	if err := c.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+	if c != calibration {
+		t.Fatalf("got %+v", c)
+	}
+	if err := c.UnmarshalBinary(b[:4]); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package bno055
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// Calibration is the calibration data computed by the device.
+//
+// The values are in the device's register units; they are meant to be saved
+// and restored as is. MarshalBinary and UnmarshalBinary use the register
+// layout.
+type Calibration struct {
+	AccelOffset [3]int16
+	MagOffset   [3]int16
+	GyroOffset  [3]int16
+	AccelRadius int16
+	MagRadius   int16
+}
+
+// calibrationSize is the size of the calibration registers.
+const calibrationSize = 22
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (c *Calibration) MarshalBinary() ([]byte, error) {
+	b := make([]byte, calibrationSize)
+	v := c.words()
+	for i := range v {
+		binary.LittleEndian.PutUint16(b[2*i:], uint16(*v[i]))
+	}
+	return b, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (c *Calibration) UnmarshalBinary(b []byte) error {
+	if len(b) != calibrationSize {
+		return errCalibrationSize
+	}
+	v := c.words()
+	for i := range v {
+		*v[i] = int16(binary.LittleEndian.Uint16(b[2*i:]))
+	}
+	return nil
+}
+
+// Calibration returns the current calibration data.
+//
+// The device is briefly switched to ModeConfig to read it, which resets the
+// fusion algorithm.
+func (d *Dev) Calibration() (*Calibration, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.suspended {
+		return nil, errSuspended
+	}
+	mode := d.mode
+	if err := d.setMode(ModeConfig); err != nil {
+		return nil, err
+	}
+	var b [calibrationSize]byte
+	if err := d.c.Tx([]byte{accOffset}, b[:]); err != nil {
+		return nil, err
+	}
+	c := &Calibration{}
+	if err := c.UnmarshalBinary(b[:]); err != nil {
+		return nil, err
+	}
+	return c, d.setMode(mode)
+}
+
+// SetCalibration restores calibration data previously returned by
+// Calibration.
+//
+// The device is briefly switched to ModeConfig to write it, which resets the
+// fusion algorithm.
+func (d *Dev) SetCalibration(c *Calibration) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.suspended {
+		return errSuspended
+	}
+	mode := d.mode
+	if err := d.setMode(ModeConfig); err != nil {
+		return err
+	}
+	if err := d.writeCalibration(c); err != nil {
+		return err
+	}
+	return d.setMode(mode)
+}
+
+//
+
+var (
+	errCalibrationSize = errors.New("bno055: calibration data must be 22 bytes")
+	errSuspended       = errors.New("bno055: device is halted")
+)
+
+// words returns the fields in register order.
+func (c *Calibration) words() [11]*int16 {
+	return [11]*int16{
+		&c.AccelOffset[0], &c.AccelOffset[1], &c.AccelOffset[2],
+		&c.MagOffset[0], &c.MagOffset[1], &c.MagOffset[2],
+		&c.GyroOffset[0], &c.GyroOffset[1], &c.GyroOffset[2],
+		&c.AccelRadius, &c.MagRadius,
+	}
+}
+
+// writeCalibration writes the calibration registers. The device must be in
+// ModeConfig.
+func (d *Dev) writeCalibration(c *Calibration) error {
+	b, err := c.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return d.c.Tx(append([]byte{accOffset}, b...), nil)
+}
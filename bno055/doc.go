@@ -0,0 +1,29 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package bno055 controls the Bosch BNO055 absolute orientation sensor over
+// I²C.
+//
+// The BNO055 combines an accelerometer, a gyroscope and a magnetometer with a
+// microcontroller running a sensor fusion algorithm. In the fusion operation
+// modes it reports the orientation as Euler angles or as a quaternion, and
+// the linear acceleration with gravity removed.
+//
+// # Calibration
+//
+// The sensors calibrate themselves while the device is moved around; the
+// progress is reported by CalibrationStatus. The calibration is lost on power
+// down. Save it with Calibration once the device is fully calibrated and
+// restore it with Opts.Calibration or SetCalibration on the next start.
+//
+// # Raspberry Pi
+//
+// The BNO055 uses I²C clock stretching, which the Raspberry Pi's I²C
+// controller doesn't handle well. Lower the bus speed to 50kHz or less if
+// reads fail.
+//
+// # Datasheet
+//
+// https://www.bosch-sensortec.com/media/boschsensortec/downloads/datasheets/bst-bno055-ds000.pdf
+package bno055
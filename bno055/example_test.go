@@ -0,0 +1,77 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package bno055_test
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"periph.io/x/conn/v3/i2c/i2creg"
+	"periph.io/x/devices/v3/bno055"
+	"periph.io/x/host/v3"
+)
+
+func Example() {
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Open default I²C bus.
+	bus, err := i2creg.Open("")
+	if err != nil {
+		log.Fatalf("failed to open I²C: %v", err)
+	}
+	defer bus.Close()
+
+	// Restore the calibration saved during a previous run, if any.
+	opts := bno055.DefaultOpts
+	if b, err := os.ReadFile("bno055.cal"); err == nil {
+		c := &bno055.Calibration{}
+		if err := c.UnmarshalBinary(b); err != nil {
+			log.Fatal(err)
+		}
+		opts.Calibration = c
+	}
+
+	dev, err := bno055.New(bus, &opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer dev.Halt()
+
+	for {
+		s, err := dev.CalibrationStatus()
+		if err != nil {
+			log.Fatal(err)
+		}
+		if s.Calibrated() {
+			break
+		}
+		fmt.Printf("Calibrating: %s\n", s)
+		time.Sleep(time.Second)
+	}
+
+	// Save the calibration for the next run.
+	c, err := dev.Calibration()
+	if err != nil {
+		log.Fatal(err)
+	}
+	b, err := c.MarshalBinary()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("bno055.cal", b, 0o644); err != nil {
+		log.Fatal(err)
+	}
+
+	e, err := dev.Euler()
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Heading: %s Roll: %s Pitch: %s\n", e.Heading, e.Roll, e.Pitch)
+}
@@ -0,0 +1,329 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package busmetrics
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/spi"
+)
+
+// Tx is the record of a single transaction.
+type Tx struct {
+	// Device identifies the device, e.g. "I2C1@0x76" for an I²C device.
+	Device   string
+	Written  int
+	Read     int
+	Duration time.Duration
+	Err      error
+}
+
+// Sink receives the transactions records.
+//
+// Record is called synchronously after each transaction and must not block.
+type Sink interface {
+	Record(tx Tx)
+}
+
+// SinkFunc adapts a function to the Sink interface.
+type SinkFunc func(tx Tx)
+
+// Record implements Sink.
+func (f SinkFunc) Record(tx Tx) {
+	f(tx)
+}
+
+// DefaultBuckets are the upper bounds of the latency histogram buckets used
+// by Stats when Buckets is not set.
+var DefaultBuckets = []time.Duration{
+	100 * time.Microsecond,
+	250 * time.Microsecond,
+	500 * time.Microsecond,
+	time.Millisecond,
+	2500 * time.Microsecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+}
+
+// Stats is a Sink aggregating the transactions per device.
+//
+// The zero value is ready to use.
+type Stats struct {
+	// Buckets are the upper bounds of the latency histogram buckets, in
+	// increasing order. It must not be changed once transactions were
+	// recorded.
+	Buckets []time.Duration
+
+	mu      sync.Mutex
+	devices map[string]*DeviceStats
+}
+
+// DeviceStats are the aggregated statistics of a device.
+type DeviceStats struct {
+	Transactions int64
+	Errors       int64
+	BytesWritten int64
+	BytesRead    int64
+	// TotalLatency is the sum of all the transactions durations.
+	TotalLatency time.Duration
+	// MaxLatency is the longest transaction.
+	MaxLatency time.Duration
+	// Latency holds the count of transactions per bucket. Latency[i] counts
+	// the transactions that took at most Buckets[i], the last item counts
+	// the ones longer than the last bucket.
+	Latency []int64
+}
+
+// Record implements Sink.
+func (s *Stats) Record(tx Tx) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Buckets == nil {
+		s.Buckets = DefaultBuckets
+	}
+	if s.devices == nil {
+		s.devices = map[string]*DeviceStats{}
+	}
+	d := s.devices[tx.Device]
+	if d == nil {
+		d = &DeviceStats{Latency: make([]int64, len(s.Buckets)+1)}
+		s.devices[tx.Device] = d
+	}
+	d.Transactions++
+	if tx.Err != nil {
+		d.Errors++
+	}
+	d.BytesWritten += int64(tx.Written)
+	d.BytesRead += int64(tx.Read)
+	d.TotalLatency += tx.Duration
+	if tx.Duration > d.MaxLatency {
+		d.MaxLatency = tx.Duration
+	}
+	d.Latency[sort.Search(len(s.Buckets), func(i int) bool { return tx.Duration <= s.Buckets[i] })]++
+}
+
+// Devices returns the names of the devices with recorded transactions, sorted.
+func (s *Stats) Devices() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]string, 0, len(s.devices))
+	for k := range s.devices {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Get returns a copy of the statistics of a device.
+func (s *Stats) Get(device string) (DeviceStats, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.devices[device]
+	if !ok {
+		return DeviceStats{}, false
+	}
+	c := *d
+	c.Latency = append([]int64(nil), d.Latency...)
+	return c, true
+}
+
+// Reset clears all the statistics.
+func (s *Stats) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.devices = nil
+}
+
+// I2C implements i2c.BusCloser and records the transactions per device
+// address.
+type I2C struct {
+	bus  i2c.Bus
+	sink Sink
+	// names are the Tx.Device of the 7 bit addresses.
+	names [0x80]string
+}
+
+// NewI2C returns b wrapped to record its transactions to s.
+func NewI2C(b i2c.Bus, s Sink) *I2C {
+	w := &I2C{bus: b, sink: s}
+	for addr := range w.names {
+		w.names[addr] = fmt.Sprintf("%s@%#x", b, addr)
+	}
+	return w
+}
+
+func (b *I2C) String() string {
+	return b.bus.String()
+}
+
+// Close implements i2c.BusCloser.
+func (b *I2C) Close() error {
+	if c, ok := b.bus.(i2c.BusCloser); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// Tx implements i2c.Bus.
+func (b *I2C) Tx(addr uint16, w, r []byte) error {
+	start := now()
+	err := b.bus.Tx(addr, w, r)
+	var name string
+	if int(addr) < len(b.names) {
+		name = b.names[addr]
+	} else {
+		// 10 bit addresses are rare enough to not be cached.
+		name = fmt.Sprintf("%s@%#x", b.bus, addr)
+	}
+	b.sink.Record(Tx{
+		Device:   name,
+		Written:  len(w),
+		Read:     len(r),
+		Duration: now().Sub(start),
+		Err:      err,
+	})
+	return err
+}
+
+// SetSpeed implements i2c.Bus.
+func (b *I2C) SetSpeed(f physic.Frequency) error {
+	return b.bus.SetSpeed(f)
+}
+
+// SCL implements i2c.Pins.
+func (b *I2C) SCL() gpio.PinIO {
+	if p, ok := b.bus.(i2c.Pins); ok {
+		return p.SCL()
+	}
+	return gpio.INVALID
+}
+
+// SDA implements i2c.Pins.
+func (b *I2C) SDA() gpio.PinIO {
+	if p, ok := b.bus.(i2c.Pins); ok {
+		return p.SDA()
+	}
+	return gpio.INVALID
+}
+
+// Conn implements conn.Conn and records the transactions under a name.
+type Conn struct {
+	conn conn.Conn
+	name string
+	sink Sink
+}
+
+// NewConn returns c wrapped to record its transactions to s. name identifies
+// the device in the records; c.String() is used if empty.
+func NewConn(c conn.Conn, name string, s Sink) *Conn {
+	if name == "" {
+		name = c.String()
+	}
+	return &Conn{conn: c, name: name, sink: s}
+}
+
+func (c *Conn) String() string {
+	return c.conn.String()
+}
+
+// Duplex implements conn.Conn.
+func (c *Conn) Duplex() conn.Duplex {
+	return c.conn.Duplex()
+}
+
+// Tx implements conn.Conn.
+func (c *Conn) Tx(w, r []byte) error {
+	start := now()
+	err := c.conn.Tx(w, r)
+	c.record(len(w), len(r), now().Sub(start), err)
+	return err
+}
+
+func (c *Conn) record(w, r int, d time.Duration, err error) {
+	c.sink.Record(Tx{Device: c.name, Written: w, Read: r, Duration: d, Err: err})
+}
+
+// SPI implements spi.PortCloser and records the transactions of the
+// connections returned by Connect under a name.
+type SPI struct {
+	port spi.Port
+	name string
+	sink Sink
+}
+
+// NewSPI returns p wrapped to record the transactions of its connections to
+// s. name identifies the device in the records; p.String() is used if empty.
+func NewSPI(p spi.Port, name string, s Sink) *SPI {
+	if name == "" {
+		name = p.String()
+	}
+	return &SPI{port: p, name: name, sink: s}
+}
+
+func (s *SPI) String() string {
+	return s.port.String()
+}
+
+// Close implements spi.PortCloser.
+func (s *SPI) Close() error {
+	if c, ok := s.port.(spi.PortCloser); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// LimitSpeed implements spi.PortCloser.
+func (s *SPI) LimitSpeed(f physic.Frequency) error {
+	if c, ok := s.port.(spi.PortCloser); ok {
+		return c.LimitSpeed(f)
+	}
+	return nil
+}
+
+// Connect implements spi.Port.
+func (s *SPI) Connect(f physic.Frequency, mode spi.Mode, bits int) (spi.Conn, error) {
+	c, err := s.port.Connect(f, mode, bits)
+	if err != nil {
+		return nil, err
+	}
+	return &spiConn{Conn: Conn{conn: c, name: s.name, sink: s.sink}, c: c}, nil
+}
+
+type spiConn struct {
+	Conn
+	c spi.Conn
+}
+
+// TxPackets is recorded as a single transaction.
+func (s *spiConn) TxPackets(p []spi.Packet) error {
+	w, r := 0, 0
+	for i := range p {
+		w += len(p[i].W)
+		r += len(p[i].R)
+	}
+	start := now()
+	err := s.c.TxPackets(p)
+	s.record(w, r, now().Sub(start), err)
+	return err
+}
+
+var now = time.Now
+
+var _ Sink = &Stats{}
+var _ i2c.BusCloser = &I2C{}
+var _ i2c.Pins = &I2C{}
+var _ conn.Conn = &Conn{}
+var _ spi.PortCloser = &SPI{}
+var _ spi.Conn = &spiConn{}
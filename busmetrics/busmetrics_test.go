@@ -0,0 +1,122 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package busmetrics
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3/conntest"
+	"periph.io/x/conn/v3/i2c/i2ctest"
+	"periph.io/x/conn/v3/spi"
+	"periph.io/x/conn/v3/spi/spitest"
+)
+
+// fakeClock advances by step on each call.
+func fakeClock(step time.Duration) func() time.Time {
+	t := time.Unix(0, 0)
+	return func() time.Time {
+		t = t.Add(step)
+		return t
+	}
+}
+
+func TestI2C(t *testing.T) {
+	now = fakeClock(2 * time.Millisecond)
+	defer func() { now = time.Now }()
+	p := &i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x76, W: []byte{0xd0}, R: []byte{0x60}},
+			{Addr: 0x40, W: []byte{0x00, 0x1f, 0xff}},
+		},
+		DontPanic: true,
+	}
+	s := &Stats{}
+	b := NewI2C(p, s)
+	r := make([]byte, 1)
+	if err := b.Tx(0x76, []byte{0xd0}, r); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Tx(0x40, []byte{0x00, 0x1f, 0xff}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Tx(0x40, []byte{0x00}, nil); err == nil {
+		t.Fatal("expected playback error")
+	}
+	// 10 bit address.
+	_ = b.Tx(0x2a5, nil, nil)
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if d := s.Devices(); !reflect.DeepEqual(d, []string{"playback@0x2a5", "playback@0x40", "playback@0x76"}) {
+		t.Fatalf("Devices() = %v", d)
+	}
+	got, ok := s.Get("playback@0x40")
+	if !ok {
+		t.Fatal("missing device")
+	}
+	want := DeviceStats{
+		Transactions: 2,
+		Errors:       1,
+		BytesWritten: 4,
+		TotalLatency: 4 * time.Millisecond,
+		MaxLatency:   2 * time.Millisecond,
+		Latency:      []int64{0, 0, 0, 0, 2, 0, 0, 0, 0, 0, 0},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Get() = %+v\nwant %+v", got, want)
+	}
+	if _, ok := s.Get("playback@0x20"); ok {
+		t.Fatal("unexpected device")
+	}
+	s.Reset()
+	if len(s.Devices()) != 0 {
+		t.Fatal("Reset() failed")
+	}
+}
+
+func TestSPI(t *testing.T) {
+	now = fakeClock(time.Second)
+	defer func() { now = time.Now }()
+	p := &spitest.Playback{
+		Playback: conntest.Playback{
+			Ops: []conntest.IO{
+				{W: []byte{0x9f, 0x00}, R: []byte{0x00, 0x42}},
+			},
+		},
+	}
+	var got []Tx
+	port := NewSPI(p, "flash", SinkFunc(func(tx Tx) { got = append(got, tx) }))
+	c, err := port.Connect(0, spi.Mode0, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Tx([]byte{0x9f, 0x00}, make([]byte, 2)); err != nil {
+		t.Fatal(err)
+	}
+	want := []Tx{{Device: "flash", Written: 2, Read: 2, Duration: time.Second}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v", got)
+	}
+	if err := port.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStats_buckets(t *testing.T) {
+	s := &Stats{Buckets: []time.Duration{time.Millisecond, 10 * time.Millisecond}}
+	for _, d := range []time.Duration{time.Microsecond, time.Millisecond, 5 * time.Millisecond, time.Second} {
+		s.Record(Tx{Device: "d", Duration: d})
+	}
+	got, _ := s.Get("d")
+	if !reflect.DeepEqual(got.Latency, []int64{2, 1, 1}) {
+		t.Fatalf("Latency = %v", got.Latency)
+	}
+	if got.MaxLatency != time.Second {
+		t.Fatalf("MaxLatency = %s", got.MaxLatency)
+	}
+}
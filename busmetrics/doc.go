@@ -0,0 +1,13 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package busmetrics instruments I²C buses, SPI ports and conn.Conn
+// connections to record per device transaction counts, byte counts, errors and
+// latencies.
+//
+// The wrappers are transparent to the device drivers: wrap the bus or port
+// before passing it to the driver constructor. Each transaction is reported to
+// a Sink. Stats is a Sink keeping counters and latency histograms in memory,
+// which can be exported periodically to a monitoring system.
+package busmetrics
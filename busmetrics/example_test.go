@@ -0,0 +1,48 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package busmetrics_test
+
+import (
+	"fmt"
+	"log"
+
+	"periph.io/x/conn/v3/i2c/i2creg"
+	"periph.io/x/devices/v3/busmetrics"
+	"periph.io/x/devices/v3/ina219"
+	"periph.io/x/host/v3"
+)
+
+func Example() {
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Open default I²C bus.
+	bus, err := i2creg.Open("")
+	if err != nil {
+		log.Fatalf("failed to open I²C: %v", err)
+	}
+	defer bus.Close()
+
+	// Wrap the bus before handing it to the drivers.
+	stats := &busmetrics.Stats{}
+	instrumented := busmetrics.NewI2C(bus, stats)
+
+	sensor, err := ina219.New(instrumented, &ina219.DefaultOpts)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	for i := 0; i < 10; i++ {
+		if _, err := sensor.Sense(); err != nil {
+			log.Println(err)
+		}
+	}
+
+	for _, name := range stats.Devices() {
+		s, _ := stats.Get(name)
+		fmt.Printf("%s: %d transactions, %d errors, max latency %s\n", name, s.Transactions, s.Errors, s.MaxLatency)
+	}
+}
@@ -5,11 +5,14 @@
 package ccs811
 
 import (
+	"errors"
 	"fmt"
+	"time"
 
 	"periph.io/x/conn/v3/physic"
 
 	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/gpio"
 	"periph.io/x/conn/v3/i2c"
 )
 
@@ -90,11 +93,16 @@ func (d *Dev) errorCodeToError(errorCode SensorErrorID) error {
 }
 
 // Opts holds the configuration options. The address must be 0x5A or 0x5B.
+//
+// InterruptPin is optional and is the GPIO connected to the nINT output of
+// the sensor. When set, InterruptWhenReady is implied and WaitForData waits
+// for an edge on the pin instead of polling the status register.
 type Opts struct {
 	Addr               uint16
 	MeasurementMode    MeasurementMode
 	InterruptWhenReady bool
 	UseThreshold       bool
+	InterruptPin       gpio.PinIn
 }
 
 // DefaultOpts are the safe default options.
@@ -103,6 +111,7 @@ var DefaultOpts = Opts{
 	MeasurementMode:    MeasurementModeConstant1000,
 	InterruptWhenReady: false,
 	UseThreshold:       false,
+	InterruptPin:       nil,
 }
 
 // New creates a new driver for CCS811 VOC sensor.
@@ -119,13 +128,20 @@ func New(bus i2c.Bus, opts *Opts) (*Dev, error) {
 		c:    &i2c.Dev{Bus: bus, Addr: opts.Addr},
 		opts: *opts,
 	}
+	if opts.InterruptPin != nil {
+		// nINT is open drain and active low.
+		if err := opts.InterruptPin.In(gpio.PullUp, gpio.FallingEdge); err != nil {
+			return nil, err
+		}
+		dev.opts.InterruptWhenReady = true
+	}
 
 	// From boot mode to measurement mode.
-	if err := dev.StartSensorApp(); err != nil {
+	if err := dev.boot(); err != nil {
 		return nil, fmt.Errorf("error transitioning from boot do app mode: %v", err)
 	}
 	mmp := &MeasurementModeParams{MeasurementMode: opts.MeasurementMode,
-		GenerateInterrupt: opts.InterruptWhenReady,
+		GenerateInterrupt: dev.opts.InterruptWhenReady,
 		UseThreshold:      opts.UseThreshold}
 
 	if err := dev.SetMeasurementModeRegister(*mmp); err != nil {
@@ -148,16 +164,48 @@ const ( //Sensor's registers.
 	rawDataReg         byte = 0x03
 	environmentReg     byte = 0x05
 	baselineReg        byte = 0x11
+	errorIDReg         byte = 0xE0
+	appStartReg        byte = 0xF4
 	resetReg           byte = 0xFF
 )
 
+const ( // Bits of the status register.
+	statusError     byte = 0x01
+	statusDataReady byte = 0x08
+	statusAppValid  byte = 0x10
+	statusFwMode    byte = 0x80
+)
+
 func (d *Dev) String() string {
 	return "CCS811"
 }
 
 // StartSensorApp initializes sensor to application mode.
+//
+// It only sends the APP_START command, New also verifies that a valid
+// application firmware is present and that the sensor did switch to it.
 func (d *Dev) StartSensorApp() error {
-	return d.c.Tx([]byte{0xf4}, nil)
+	return d.c.Tx([]byte{appStartReg}, nil)
+}
+
+// SetDriveMode changes the drive mode, keeping the interrupt and threshold
+// settings.
+//
+// When switching to a mode with a lower sample rate, the sensor should be
+// put in MeasurementModeIdle for at least 10 minutes before setting the new
+// mode.
+func (d *Dev) SetDriveMode(mode MeasurementMode) error {
+	if mode > MeasurementModeConstant250 {
+		return fmt.Errorf("invalid measurement mode")
+	}
+	mmp := MeasurementModeParams{MeasurementMode: mode,
+		GenerateInterrupt: d.opts.InterruptWhenReady,
+		UseThreshold:      d.opts.UseThreshold}
+	if err := d.SetMeasurementModeRegister(mmp); err != nil {
+		return err
+	}
+	d.opts.MeasurementMode = mode
+	return nil
 }
 
 // SetMeasurementModeRegister sets one of the 5 measurement modes, interrupt generation
@@ -234,6 +282,32 @@ func (d *Dev) SetEnvironmentData(temp, humidity float32) error {
 	return d.c.Tx(w, nil)
 }
 
+// SetEnvironment is the same as SetEnvironmentData but takes physic values,
+// for example the ones sensed by a physic.SenseEnv.
+//
+// Values out of the range supported by the sensor, -25°C and 0-100%rH, are
+// clamped.
+func (d *Dev) SetEnvironment(temp physic.Temperature, humidity physic.RelativeHumidity) error {
+	t := temp - physic.ZeroCelsius + 25*physic.Kelvin
+	if t < 0 {
+		t = 0
+	}
+	if humidity < 0 {
+		humidity = 0
+	} else if humidity > 100*physic.PercentRH {
+		humidity = 100 * physic.PercentRH
+	}
+	rawTemp := uint16(int64(t) * 512 / int64(physic.Kelvin))
+	rawHum := uint16(int64(humidity) * 512 / int64(physic.PercentRH))
+	w := []byte{environmentReg,
+		byte(rawHum >> 8),
+		byte(rawHum),
+		byte(rawTemp >> 8),
+		byte(rawTemp)}
+
+	return d.c.Tx(w, nil)
+}
+
 // GetBaseline provides current baseline used by internal measurement algorithm.
 // For better understanding how to use this value, check the SetBaseline and
 // documentation.
@@ -281,6 +355,9 @@ func (d *Dev) GetBaseline() ([]byte, error) {
 //
 // 2) The baseline must be written after the conditioning period
 func (d *Dev) SetBaseline(baseline []byte) error {
+	if len(baseline) != 2 {
+		return fmt.Errorf("baseline must be 2 bytes, got %d", len(baseline))
+	}
 	w := []byte{baselineReg, baseline[0], baseline[1]}
 	return d.c.Tx(w, nil)
 }
@@ -316,6 +393,37 @@ type SensorValues struct {
 	RawDataVoltage physic.ElectricPotential
 }
 
+// WaitForData blocks until a new measurement is available or the timeout
+// expires.
+//
+// If an InterruptPin was provided, it waits for nINT to be asserted,
+// otherwise it polls the data ready bit of the status register. An error
+// reported by the sensor is returned.
+func (d *Dev) WaitForData(timeout time.Duration) error {
+	if d.opts.InterruptPin != nil {
+		if !d.opts.InterruptPin.WaitForEdge(timeout) {
+			return errTimeout
+		}
+		return nil
+	}
+	for deadline := time.Now().Add(timeout); ; {
+		status, err := d.ReadStatus()
+		if err != nil {
+			return err
+		}
+		if status&statusError != 0 {
+			return d.readError()
+		}
+		if status&statusDataReady != 0 {
+			return nil
+		}
+		if !time.Now().Before(deadline) {
+			return errTimeout
+		}
+		doSleep(pollInterval)
+	}
+}
+
 // Sense provides data from the sensor.
 // This function read all 8 available bytes including error, raw data etc.
 // If you want just eCO2 and/or VOC, use SensePartial.
@@ -353,6 +461,61 @@ func (d *Dev) SensePartial(requested NeededData, values *SensorValues) error {
 	return nil
 }
 
+var errTimeout = errors.New("timeout waiting for data")
+
+// pollInterval is the period at which the status is polled when there is no
+// interrupt pin.
+const pollInterval = 10 * time.Millisecond
+
+// appStartTime is the time needed by the sensor to switch to the application
+// after APP_START.
+const appStartTime = time.Millisecond
+
+// doSleep is overridden in tests.
+var doSleep = time.Sleep
+
+// boot switches the sensor from the boot loader to the application
+// firmware. It does nothing if the application is already running, for
+// example when the device was opened before.
+func (d *Dev) boot() error {
+	status, err := d.ReadStatus()
+	if err != nil {
+		return err
+	}
+	if status&statusFwMode != 0 {
+		return nil
+	}
+	if status&statusAppValid == 0 {
+		return errors.New("no valid application firmware")
+	}
+	if err := d.StartSensorApp(); err != nil {
+		return err
+	}
+	doSleep(appStartTime)
+	if status, err = d.ReadStatus(); err != nil {
+		return err
+	}
+	if status&statusFwMode == 0 {
+		if status&statusError != 0 {
+			return d.readError()
+		}
+		return errors.New("sensor still in boot mode")
+	}
+	return nil
+}
+
+// readError reads and clears the ERROR_ID register.
+func (d *Dev) readError() error {
+	r := make([]byte, 1)
+	if err := d.c.Tx([]byte{errorIDReg}, r); err != nil {
+		return err
+	}
+	if err := d.errorCodeToError(SensorErrorID(r[0])); err != nil {
+		return err
+	}
+	return errors.New("sensor error without error code")
+}
+
 // Parse current and voltage from raw data.
 func valuesFromRawData(data []byte) (physic.ElectricCurrent, physic.ElectricPotential) {
 	c := physic.ElectricCurrent(int64(data[0]>>2) * 1000)
@@ -7,7 +7,10 @@ package ccs811
 import (
 	"fmt"
 	"testing"
+	"time"
 
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpiotest"
 	"periph.io/x/conn/v3/i2c/i2ctest"
 	"periph.io/x/conn/v3/physic"
 )
@@ -15,7 +18,9 @@ import (
 func TestBasicInitialisationAndDataRead(t *testing.T) {
 	bus := i2ctest.Playback{
 		Ops: []i2ctest.IO{
+			{Addr: 0x5A, W: []byte{statusReg}, R: []byte{0x10}},
 			{Addr: 0x5A, W: []byte{0xf4}, R: nil},
+			{Addr: 0x5A, W: []byte{statusReg}, R: []byte{0x90}},
 			{Addr: 0x5A, W: []byte{measurementModeReg, 0x1C}, R: nil},
 			{Addr: 0x5A, W: []byte{algoResultsReg}, R: []byte{0x1, 0x2, 0x2, 0x3, 0xF, 0x8, 0xF, 0xF}},
 		},
@@ -53,7 +58,9 @@ func TestBasicInitialisationAndDataRead(t *testing.T) {
 func TestMeasurementModeRegisterRead(t *testing.T) {
 	bus := i2ctest.Playback{
 		Ops: []i2ctest.IO{
+			{Addr: 0x5A, W: []byte{statusReg}, R: []byte{0x10}},
 			{Addr: 0x5A, W: []byte{0xf4}, R: nil},
+			{Addr: 0x5A, W: []byte{statusReg}, R: []byte{0x90}},
 			{Addr: 0x5A, W: []byte{measurementModeReg, 0x4C}, R: nil},
 			{Addr: 0x5A, W: []byte{measurementModeReg}, R: []byte{0x4C}},
 		},
@@ -81,7 +88,9 @@ func TestMeasurementModeRegisterRead(t *testing.T) {
 func TestGetFirmwareData(t *testing.T) {
 	bus := i2ctest.Playback{
 		Ops: []i2ctest.IO{
+			{Addr: 0x5A, W: []byte{statusReg}, R: []byte{0x10}},
 			{Addr: 0x5A, W: []byte{0xf4}, R: nil},
+			{Addr: 0x5A, W: []byte{statusReg}, R: []byte{0x90}},
 			{Addr: 0x5A, W: []byte{measurementModeReg, 0x4C}, R: nil},
 			{Addr: 0x5A, W: []byte{0x20}, R: []byte{0x81}},
 			{Addr: 0x5A, W: []byte{0x21}, R: []byte{0x15}},
@@ -125,7 +134,9 @@ func TestInvalidSensorAddress(t *testing.T) {
 func TestSetEnvironmentData(t *testing.T) {
 	bus := i2ctest.Playback{
 		Ops: []i2ctest.IO{
+			{Addr: 0x5A, W: []byte{statusReg}, R: []byte{0x10}},
 			{Addr: 0x5A, W: []byte{0xf4}, R: nil},
+			{Addr: 0x5A, W: []byte{statusReg}, R: []byte{0x90}},
 			{Addr: 0x5A, W: []byte{measurementModeReg, 0x10}, R: nil},
 			{Addr: 0x5A, W: []byte{environmentReg, 0x61, 0x00, 0x64, 0x00}, R: nil},
 			{Addr: 0x5A, W: []byte{environmentReg, 0x64, 0x00, 0x61, 0x00}, R: nil},
@@ -142,7 +153,9 @@ func TestSetEnvironmentData(t *testing.T) {
 func TestBaseline(t *testing.T) {
 	bus := i2ctest.Playback{
 		Ops: []i2ctest.IO{
+			{Addr: 0x5A, W: []byte{statusReg}, R: []byte{0x10}},
 			{Addr: 0x5A, W: []byte{0xf4}, R: nil},
+			{Addr: 0x5A, W: []byte{statusReg}, R: []byte{0x90}},
 			{Addr: 0x5A, W: []byte{measurementModeReg, 0x10}, R: nil},
 			{Addr: 0x5A, W: []byte{baselineReg}, R: []byte{0xAA, 0xDD}},
 			{Addr: 0x5A, W: []byte{baselineReg, 0xAA, 0xDD}, R: nil},
@@ -162,7 +175,9 @@ func TestBaseline(t *testing.T) {
 func TestReadRawData(t *testing.T) {
 	bus := i2ctest.Playback{
 		Ops: []i2ctest.IO{
+			{Addr: 0x5A, W: []byte{statusReg}, R: []byte{0x10}},
 			{Addr: 0x5A, W: []byte{0xf4}, R: nil},
+			{Addr: 0x5A, W: []byte{statusReg}, R: []byte{0x90}},
 			{Addr: 0x5A, W: []byte{measurementModeReg, 0x10}, R: nil},
 			{Addr: 0x5A, W: []byte{rawDataReg}, R: []byte{0x96, 0xAA}},
 		},
@@ -199,16 +214,168 @@ func TestRawDataParsing(t *testing.T) {
 func TestReset(t *testing.T) {
 	bus := i2ctest.Playback{
 		Ops: []i2ctest.IO{
+			{Addr: 0x5B, W: []byte{statusReg}, R: []byte{0x10}},
 			{Addr: 0x5B, W: []byte{0xf4}, R: nil},
+			{Addr: 0x5B, W: []byte{statusReg}, R: []byte{0x90}},
 			{Addr: 0x5B, W: []byte{measurementModeReg, 0x10}, R: nil},
 			{Addr: 0x5B, W: []byte{resetReg, 0x11, 0xE5, 0x72, 0x8A}, R: nil},
 		},
 	}
-	opts := &DefaultOpts
+	opts := DefaultOpts
 	opts.Addr = 0x5B
-	dev, err := New(&bus, opts)
+	dev, err := New(&bus, &opts)
 	if err != nil {
 		t.Fatal(err)
 	}
 	dev.Reset()
 }
+
+func TestBoot(t *testing.T) {
+	// The application is already running.
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x5A, W: []byte{statusReg}, R: []byte{0x90}},
+			{Addr: 0x5A, W: []byte{measurementModeReg, 0x10}, R: nil},
+		},
+	}
+	if _, err := New(&bus, &Opts{Addr: 0x5A, MeasurementMode: MeasurementModeConstant1000}); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// No valid application firmware.
+	bus = i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x5A, W: []byte{statusReg}, R: []byte{0x00}},
+		},
+	}
+	if _, err := New(&bus, &Opts{Addr: 0x5A, MeasurementMode: MeasurementModeConstant1000}); err == nil {
+		t.Fatal("expected error on invalid firmware")
+	}
+
+	// The sensor stays in boot mode and reports an error.
+	bus = i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x5A, W: []byte{statusReg}, R: []byte{0x10}},
+			{Addr: 0x5A, W: []byte{0xf4}, R: nil},
+			{Addr: 0x5A, W: []byte{statusReg}, R: []byte{0x11}},
+			{Addr: 0x5A, W: []byte{errorIDReg}, R: []byte{0x01}},
+		},
+	}
+	if _, err := New(&bus, &Opts{Addr: 0x5A, MeasurementMode: MeasurementModeConstant1000}); err == nil {
+		t.Fatal("expected error on boot")
+	}
+}
+
+func TestSetDriveMode(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x5A, W: []byte{statusReg}, R: []byte{0x90}},
+			{Addr: 0x5A, W: []byte{measurementModeReg, 0x18}, R: nil},
+			{Addr: 0x5A, W: []byte{measurementModeReg, 0x38}, R: nil},
+		},
+	}
+	opts := DefaultOpts
+	opts.InterruptWhenReady = true
+	dev, err := New(&bus, &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.SetDriveMode(MeasurementMode(5)); err == nil {
+		t.Fatal("expected error on invalid mode")
+	}
+	if err := dev.SetDriveMode(MeasurementModeLowPower); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSetEnvironment(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x5A, W: []byte{statusReg}, R: []byte{0x90}},
+			{Addr: 0x5A, W: []byte{measurementModeReg, 0x10}, R: nil},
+			{Addr: 0x5A, W: []byte{environmentReg, 0x61, 0x00, 0x64, 0x00}, R: nil},
+			{Addr: 0x5A, W: []byte{environmentReg, 0xC8, 0x00, 0x00, 0x00}, R: nil},
+		},
+	}
+	dev, err := New(&bus, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.SetEnvironment(physic.ZeroCelsius+25*physic.Kelvin, 485*physic.PercentRH/10); err != nil {
+		t.Fatal(err)
+	}
+	// Clamped.
+	if err := dev.SetEnvironment(physic.ZeroCelsius-40*physic.Kelvin, 120*physic.PercentRH); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.SetBaseline([]byte{1}); err == nil {
+		t.Fatal("expected error on baseline length")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWaitForData(t *testing.T) {
+	defer func(f func(time.Duration)) { doSleep = f }(doSleep)
+	doSleep = func(time.Duration) {}
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x5A, W: []byte{statusReg}, R: []byte{0x90}},
+			{Addr: 0x5A, W: []byte{measurementModeReg, 0x10}, R: nil},
+			{Addr: 0x5A, W: []byte{statusReg}, R: []byte{0x90}},
+			{Addr: 0x5A, W: []byte{statusReg}, R: []byte{0x98}},
+			{Addr: 0x5A, W: []byte{statusReg}, R: []byte{0x91}},
+			{Addr: 0x5A, W: []byte{errorIDReg}, R: []byte{0x20}},
+			{Addr: 0x5A, W: []byte{statusReg}, R: []byte{0x90}},
+		},
+	}
+	dev, err := New(&bus, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.WaitForData(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.WaitForData(time.Second); err == nil {
+		t.Fatal("expected sensor error")
+	}
+	if err := dev.WaitForData(0); err != errTimeout {
+		t.Fatal(err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWaitForData_Interrupt(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x5A, W: []byte{statusReg}, R: []byte{0x90}},
+			{Addr: 0x5A, W: []byte{measurementModeReg, 0x18}, R: nil},
+		},
+	}
+	pin := &gpiotest.Pin{N: "nINT", L: gpio.High, EdgesChan: make(chan gpio.Level, 1)}
+	opts := DefaultOpts
+	opts.InterruptPin = pin
+	dev, err := New(&bus, &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pin.P != gpio.PullUp {
+		t.Fatal(pin.P)
+	}
+	pin.EdgesChan <- gpio.Low
+	if err := dev.WaitForData(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.WaitForData(time.Millisecond); err != errTimeout {
+		t.Fatal(err)
+	}
+}
@@ -5,6 +5,12 @@
 // Package ccs811 controls CCS811 Volatile Organic Compounds sensor via
 // I²C interface.
 //
+// New switches the sensor from its boot loader to the application firmware.
+// Use SetEnvironment to compensate the measurements with the temperature and
+// humidity of another sensor, and GetBaseline and SetBaseline to save and
+// restore the baseline across power cycles. WaitForData waits for a new
+// measurement, on the nINT pin when one is provided in Opts.
+//
 // # Product page
 //
 // https://ams.com/ccs811
@@ -0,0 +1,216 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package currentsense
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/analog"
+	"periph.io/x/conn/v3/physic"
+)
+
+// Sensor is the transfer function of a current sensor: its output is
+// Zero + Sensitivity*current.
+type Sensor struct {
+	// Sensitivity is the output voltage per ampere, expressed as a
+	// transresistance. For example 185mV/A is 185*physic.MilliOhm.
+	Sensitivity physic.ElectricResistance
+	// Zero is the output voltage when no current flows.
+	Zero physic.ElectricPotential
+}
+
+// Allegro ACS712 bidirectional sensors powered at 5V.
+//
+// The output is ratiometric, the zero and the sensitivity scale with the
+// supply voltage.
+var (
+	ACS712x05B = Sensor{Sensitivity: 185 * physic.MilliOhm, Zero: 2500 * physic.MilliVolt}
+	ACS712x20A = Sensor{Sensitivity: 100 * physic.MilliOhm, Zero: 2500 * physic.MilliVolt}
+	ACS712x30A = Sensor{Sensitivity: 66 * physic.MilliOhm, Zero: 2500 * physic.MilliVolt}
+)
+
+// Allegro ACS723 sensors powered at 5V.
+//
+// The AB variants are bidirectional, the AU variants measure only positive
+// currents and output 0.5V at 0A.
+var (
+	ACS723x05AB = Sensor{Sensitivity: 400 * physic.MilliOhm, Zero: 2500 * physic.MilliVolt}
+	ACS723x10AB = Sensor{Sensitivity: 200 * physic.MilliOhm, Zero: 2500 * physic.MilliVolt}
+	ACS723x20AB = Sensor{Sensitivity: 100 * physic.MilliOhm, Zero: 2500 * physic.MilliVolt}
+	ACS723x40AB = Sensor{Sensitivity: 66 * physic.MilliOhm, Zero: 2500 * physic.MilliVolt}
+	ACS723x10AU = Sensor{Sensitivity: 400 * physic.MilliOhm, Zero: 500 * physic.MilliVolt}
+	ACS723x20AU = Sensor{Sensitivity: 200 * physic.MilliOhm, Zero: 500 * physic.MilliVolt}
+	ACS723x40AU = Sensor{Sensitivity: 100 * physic.MilliOhm, Zero: 500 * physic.MilliVolt}
+)
+
+// CurrentTransformer returns the transfer function of a current transformer
+// with the given turns ratio loaded by a burden resistor, its output biased
+// at bias so negative half waves stay in the ADC range.
+//
+// The measured current is instantaneous, the RMS value of an AC current has
+// to be computed from multiple samples.
+func CurrentTransformer(burden physic.ElectricResistance, turns int, bias physic.ElectricPotential) Sensor {
+	if turns <= 0 {
+		return Sensor{Zero: bias}
+	}
+	return Sensor{Sensitivity: burden / physic.ElectricResistance(turns), Zero: bias}
+}
+
+// Shunt returns the transfer function of a shunt resistor followed by an
+// amplifier with the given gain and output offset.
+func Shunt(r physic.ElectricResistance, gain int, offset physic.ElectricPotential) Sensor {
+	return Sensor{Sensitivity: r * physic.ElectricResistance(gain), Zero: offset}
+}
+
+// Opts holds the configuration options.
+type Opts struct {
+	// Sensor is the transfer function of the sensor.
+	Sensor Sensor
+	// Voltage is the voltage of the monitored supply, used to compute the
+	// power. It is optional.
+	Voltage physic.ElectricPotential
+	// Samples is the number of readings averaged by Sense. Defaults to 1.
+	Samples int
+}
+
+// New returns a current sensor reading the ADC pin p.
+//
+// p must report the voltage of its samples.
+func New(p analog.PinADC, opts *Opts) (*Dev, error) {
+	if opts.Sensor.Sensitivity <= 0 {
+		return nil, errors.New("currentsense: sensitivity must be positive")
+	}
+	if opts.Voltage < 0 {
+		return nil, errors.New("currentsense: voltage cannot be negative")
+	}
+	if opts.Samples < 0 {
+		return nil, errors.New("currentsense: samples cannot be negative")
+	}
+	if _, max := p.Range(); max.V == 0 {
+		return nil, fmt.Errorf("currentsense: %s doesn't report voltages", p)
+	}
+	d := &Dev{p: p, s: opts.Sensor, v: opts.Voltage, n: opts.Samples}
+	if d.n == 0 {
+		d.n = 1
+	}
+	return d, nil
+}
+
+// Dev is a current sensor read through an ADC.
+type Dev struct {
+	p analog.PinADC
+	v physic.ElectricPotential
+	n int
+
+	mu sync.Mutex
+	s  Sensor
+}
+
+func (d *Dev) String() string {
+	return "currentsense{" + d.p.String() + "}"
+}
+
+// PowerMonitor is the measurement, with the same fields as
+// ina219.PowerMonitor.
+type PowerMonitor struct {
+	// Shunt is the sensor output voltage relative to its zero.
+	Shunt physic.ElectricPotential
+	// Voltage is the supply voltage set in Opts.
+	Voltage physic.ElectricPotential
+	Current physic.ElectricCurrent
+	// Power is zero when Opts.Voltage is not set.
+	Power physic.Power
+}
+
+// String returns a PowerMonitor as string
+func (p PowerMonitor) String() string {
+	return fmt.Sprintf("Bus: %s, Current: %s, Power: %s, Shunt: %s", p.Voltage, p.Current, p.Power, p.Shunt)
+}
+
+// Sense reads the ADC and returns the current and the power.
+func (d *Dev) Sense() (PowerMonitor, error) {
+	v, err := d.read(d.n)
+	if err != nil {
+		return PowerMonitor{}, err
+	}
+	d.mu.Lock()
+	s := d.s
+	d.mu.Unlock()
+	pm := PowerMonitor{Shunt: v - s.Zero, Voltage: d.v}
+	pm.Current = current(pm.Shunt, s.Sensitivity)
+	// µV * µA is pW.
+	pm.Power = physic.Power(int64(pm.Voltage/physic.MicroVolt) * int64(pm.Current/physic.MicroAmpere) / 1000)
+	return pm, nil
+}
+
+// Current reads the ADC and returns the current.
+func (d *Dev) Current() (physic.ElectricCurrent, error) {
+	pm, err := d.Sense()
+	return pm.Current, err
+}
+
+// CalibrateZero averages samples readings to measure the sensor output
+// voltage at 0A. No current must flow through the sensor.
+func (d *Dev) CalibrateZero(samples int) error {
+	if samples <= 0 {
+		return errors.New("currentsense: samples must be positive")
+	}
+	v, err := d.read(samples)
+	if err != nil {
+		return err
+	}
+	d.SetZero(v)
+	return nil
+}
+
+// SetZero sets the sensor output voltage at 0A, e.g. to restore a previous
+// calibration.
+func (d *Dev) SetZero(v physic.ElectricPotential) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.s.Zero = v
+}
+
+// Zero returns the sensor output voltage at 0A currently used.
+func (d *Dev) Zero() physic.ElectricPotential {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.s.Zero
+}
+
+// Halt implements conn.Resource.
+//
+// It is a noop, the ADC pin is owned by the caller.
+func (d *Dev) Halt() error {
+	return nil
+}
+
+//
+
+// read returns the average voltage of n readings.
+func (d *Dev) read(n int) (physic.ElectricPotential, error) {
+	var sum physic.ElectricPotential
+	for i := 0; i < n; i++ {
+		s, err := d.p.Read()
+		if err != nil {
+			return 0, fmt.Errorf("currentsense: %w", err)
+		}
+		sum += s.V
+	}
+	return sum / physic.ElectricPotential(n), nil
+}
+
+// current returns v/s without overflowing; both are in nano units.
+func current(v physic.ElectricPotential, s physic.ElectricResistance) physic.ElectricCurrent {
+	q := int64(v) / int64(s)
+	r := int64(v) % int64(s)
+	// r*1e9 may overflow for large sensitivities.
+	return physic.ElectricCurrent(q*1000000000 + int64(float64(r)*1e9/float64(s)))
+}
+
+var _ conn.Resource = &Dev{}
@@ -0,0 +1,156 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package currentsense
+
+import (
+	"errors"
+	"testing"
+
+	"periph.io/x/conn/v3/analog"
+	"periph.io/x/conn/v3/physic"
+)
+
+func TestNew(t *testing.T) {
+	if _, err := New(&fakeADC{}, &Opts{}); err == nil {
+		t.Fatal("sensitivity is required")
+	}
+	if _, err := New(&fakeADC{}, &Opts{Sensor: ACS712x05B, Samples: -1}); err == nil {
+		t.Fatal("invalid samples")
+	}
+	if _, err := New(&fakeADC{noV: true}, &Opts{Sensor: ACS712x05B}); err == nil {
+		t.Fatal("pin must report voltages")
+	}
+	d, err := New(&fakeADC{}, &Opts{Sensor: ACS712x05B})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := d.String(); s != "currentsense{fake}" {
+		t.Fatal(s)
+	}
+	if err := d.Halt(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSense(t *testing.T) {
+	data := []struct {
+		s    Sensor
+		v    physic.ElectricPotential
+		want physic.ElectricCurrent
+	}{
+		{ACS712x05B, 2500 * physic.MilliVolt, 0},
+		{ACS712x05B, 2870 * physic.MilliVolt, 2 * physic.Ampere},
+		{ACS712x20A, 1500 * physic.MilliVolt, -10 * physic.Ampere},
+		{ACS723x10AU, 2500 * physic.MilliVolt, 5 * physic.Ampere},
+		// 100A:50mA transformer with a 22Ω burden resistor biased at 1.65V.
+		{CurrentTransformer(22*physic.Ohm, 2000, 1650*physic.MilliVolt), 1760 * physic.MilliVolt, 10 * physic.Ampere},
+		// 10mΩ shunt with a x50 amplifier.
+		{Shunt(10*physic.MilliOhm, 50, 0), 250 * physic.MilliVolt, 500 * physic.MilliAmpere},
+		// Large sensitivity.
+		{Sensor{Sensitivity: 1000 * physic.Ohm}, 1500 * physic.MilliVolt, 1500 * physic.MicroAmpere},
+	}
+	for i, line := range data {
+		d, err := New(&fakeADC{v: []physic.ElectricPotential{line.v}}, &Opts{Sensor: line.s})
+		if err != nil {
+			t.Fatal(i, err)
+		}
+		c, err := d.Current()
+		if err != nil {
+			t.Fatal(i, err)
+		}
+		if c != line.want {
+			t.Fatalf("#%d: %s != %s", i, c, line.want)
+		}
+	}
+}
+
+func TestSense_power(t *testing.T) {
+	p := &fakeADC{v: []physic.ElectricPotential{2800 * physic.MilliVolt, 2900 * physic.MilliVolt}}
+	d, err := New(p, &Opts{Sensor: ACS712x20A, Voltage: 12 * physic.Volt, Samples: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pm, err := d.Sense()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := PowerMonitor{
+		Shunt:   350 * physic.MilliVolt,
+		Voltage: 12 * physic.Volt,
+		Current: 3500 * physic.MilliAmpere,
+		Power:   42 * physic.Watt,
+	}
+	if pm != want {
+		t.Fatalf("%s != %s", pm, want)
+	}
+	if s := pm.String(); s != "Bus: 12V, Current: 3.500A, Power: 42W, Shunt: 350mV" {
+		t.Fatal(s)
+	}
+}
+
+func TestCalibrateZero(t *testing.T) {
+	p := &fakeADC{v: []physic.ElectricPotential{2480 * physic.MilliVolt, 2490 * physic.MilliVolt, 2485 * physic.MilliVolt, 2670 * physic.MilliVolt}}
+	d, err := New(p, &Opts{Sensor: ACS712x05B})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.CalibrateZero(0); err == nil {
+		t.Fatal("invalid samples")
+	}
+	if err := d.CalibrateZero(3); err != nil {
+		t.Fatal(err)
+	}
+	if z := d.Zero(); z != 2485*physic.MilliVolt {
+		t.Fatal(z)
+	}
+	c, err := d.Current()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c != physic.Ampere {
+		t.Fatal(c)
+	}
+	d.SetZero(2500 * physic.MilliVolt)
+	if z := d.Zero(); z != 2500*physic.MilliVolt {
+		t.Fatal(z)
+	}
+	p.err = errors.New("bus error")
+	if _, err := d.Sense(); err == nil {
+		t.Fatal("read error")
+	}
+	if err := d.CalibrateZero(1); err == nil {
+		t.Fatal("read error")
+	}
+}
+
+//
+
+type fakeADC struct {
+	v   []physic.ElectricPotential
+	noV bool
+	err error
+}
+
+func (f *fakeADC) String() string   { return "fake" }
+func (f *fakeADC) Halt() error      { return nil }
+func (f *fakeADC) Name() string     { return "fake" }
+func (f *fakeADC) Number() int      { return 0 }
+func (f *fakeADC) Function() string { return "ADC" }
+
+func (f *fakeADC) Range() (analog.Sample, analog.Sample) {
+	if f.noV {
+		return analog.Sample{}, analog.Sample{Raw: 4095}
+	}
+	return analog.Sample{}, analog.Sample{Raw: 4095, V: 5 * physic.Volt}
+}
+
+func (f *fakeADC) Read() (analog.Sample, error) {
+	if f.err != nil {
+		return analog.Sample{}, f.err
+	}
+	v := f.v[0]
+	f.v = f.v[1:]
+	return analog.Sample{V: v}, nil
+}
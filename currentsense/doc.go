@@ -0,0 +1,23 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package currentsense converts the voltage read on an analog.PinADC into an
+// electric current.
+//
+// It supports the sensors outputting a voltage proportional to the current:
+// Hall effect sensors like the Allegro ACS712 and ACS723, current
+// transformers loaded by a burden resistor and shunt resistors followed by an
+// amplifier. The ADC can be any driver exposing an analog.PinADC reporting
+// voltages, like ads1x15 or mcp3xxx.
+//
+// The zero current output voltage of Hall effect sensors drifts with the
+// supply voltage and the temperature, Dev.CalibrateZero measures it while no
+// current flows.
+//
+// # Datasheet
+//
+// ACS712: https://www.allegromicro.com/-/media/files/datasheets/acs712-datasheet.pdf
+//
+// ACS723: https://www.allegromicro.com/-/media/files/datasheets/acs723-datasheet.pdf
+package currentsense
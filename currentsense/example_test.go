@@ -0,0 +1,59 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package currentsense_test
+
+import (
+	"fmt"
+	"log"
+
+	"periph.io/x/conn/v3/i2c/i2creg"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/devices/v3/ads1x15"
+	"periph.io/x/devices/v3/currentsense"
+	"periph.io/x/host/v3"
+)
+
+func Example() {
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Open default I²C bus.
+	bus, err := i2creg.Open("")
+	if err != nil {
+		log.Fatalf("failed to open I²C: %v", err)
+	}
+	defer bus.Close()
+
+	// An ACS712 20A module powered at 5V connected to channel 0 of an ADS1115.
+	adc, err := ads1x15.NewADS1115(bus, &ads1x15.DefaultOpts)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	pin, err := adc.PinForChannel(ads1x15.Channel0, 5*physic.Volt, 128*physic.Hertz, ads1x15.SaveEnergy)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer pin.Halt()
+
+	sensor, err := currentsense.New(pin, &currentsense.Opts{
+		Sensor:  currentsense.ACS712x20A,
+		Voltage: 12 * physic.Volt,
+		Samples: 8,
+	})
+	if err != nil {
+		log.Fatalln(err)
+	}
+	// Measure the output at 0A before powering the load.
+	if err := sensor.CalibrateZero(32); err != nil {
+		log.Fatalln(err)
+	}
+	pm, err := sensor.Sense()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Println(pm)
+}
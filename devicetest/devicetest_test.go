@@ -0,0 +1,81 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package devicetest
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"path/filepath"
+	"testing"
+
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/spi"
+)
+
+func TestCommandConn(t *testing.T) {
+	c := NewCommandConn()
+	conn, err := c.Connect(physic.MegaHertz, spi.Mode0, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Connect(physic.MegaHertz, spi.Mode0, 9); err == nil {
+		t.Fatal("expected error")
+	}
+	if err := c.DC.Out(gpio.Low); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Tx([]byte{0x01, 0x02}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.DC.Out(gpio.High); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.TxPackets([]spi.Packet{{W: []byte{0xAA}}, {W: []byte{0xBB}}}); err != nil {
+		t.Fatal(err)
+	}
+	want := []Record{{Cmd: 0x01}, {Cmd: 0x02, Data: []byte{0xAA, 0xBB}}}
+	if diff := c.Diff(want); diff != "" {
+		t.Fatalf("difference (-got +want):\n%s", diff)
+	}
+	c.Reset()
+	c.Data([]byte{0x10})
+	if diff := c.Diff([]Record{{Data: []byte{0x10}}}); diff != "" {
+		t.Fatalf("difference (-got +want):\n%s", diff)
+	}
+}
+
+func TestI2CWrites(t *testing.T) {
+	ops := I2CWrites(0x3C, []byte{0x00, 0xAE}, []byte{0x40, 0xFF})
+	if len(ops) != 2 || ops[1].Addr != 0x3C || ops[1].W[1] != 0xFF || ops[1].R != nil {
+		t.Fatal(ops)
+	}
+}
+
+func TestGolden(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	draw.Draw(img, img.Bounds(), image.Black, image.Point{}, draw.Src)
+	img.Set(1, 1, color.White)
+	path := filepath.Join(t.TempDir(), "golden.png")
+	t.Setenv("DEVICETEST_UPDATE", "1")
+	Golden(t, path, img)
+	t.Setenv("DEVICETEST_UPDATE", "")
+	Golden(t, path, img)
+
+	other := image.NewGray(img.Bounds())
+	other.Set(2, 0, color.White)
+	if err := CompareImages(other, img); err == nil {
+		t.Fatal("expected a difference")
+	}
+	if err := CompareImages(image.NewRGBA(image.Rect(0, 0, 2, 2)), img); err == nil {
+		t.Fatal("expected a bounds difference")
+	}
+	other.Set(2, 0, color.Black)
+	other.Set(1, 1, color.White)
+	if err := CompareImages(other, img); err != nil {
+		t.Fatal(err)
+	}
+}
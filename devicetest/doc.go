@@ -0,0 +1,19 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package devicetest provides fakes and helpers shared by the tests of the
+// device drivers.
+//
+// Recorder records the commands and data sent to a display controller, either
+// directly through a driver's internal controller interface or, with
+// CommandConn, through a 4-wire SPI connection where the D/C pin tells the
+// commands from the data.
+//
+// CompareImages and Golden compare rendered frames, the latter against a PNG
+// file stored next to the tests. Set the environment variable
+// DEVICETEST_UPDATE=1 to write the golden files instead of comparing them.
+//
+// PowerSensor is a conformance test for the drivers implementing
+// devices.PowerSensor.
+package devicetest
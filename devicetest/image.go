@@ -0,0 +1,74 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package devicetest
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"testing"
+)
+
+// CompareImages returns an error describing the first difference between got
+// and want, comparing their bounds and the RGBA values of their pixels.
+func CompareImages(got, want image.Image) error {
+	if got.Bounds() != want.Bounds() {
+		return fmt.Errorf("devicetest: bounds %v != %v", got.Bounds(), want.Bounds())
+	}
+	b := got.Bounds()
+	n := 0
+	var first image.Point
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			r1, g1, b1, a1 := got.At(x, y).RGBA()
+			r2, g2, b2, a2 := want.At(x, y).RGBA()
+			if r1 != r2 || g1 != g2 || b1 != b2 || a1 != a2 {
+				if n == 0 {
+					first = image.Pt(x, y)
+				}
+				n++
+			}
+		}
+	}
+	if n != 0 {
+		return fmt.Errorf("devicetest: %d pixels differ, first at %v: %v != %v", n, first, got.At(first.X, first.Y), want.At(first.X, first.Y))
+	}
+	return nil
+}
+
+// Golden compares got with the PNG file path, usually under testdata/.
+//
+// When the environment variable DEVICETEST_UPDATE is set to 1, the file is
+// written with got instead.
+func Golden(t testing.TB, path string, got image.Image) {
+	t.Helper()
+	if os.Getenv("DEVICETEST_UPDATE") == "1" {
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := png.Encode(f, got); err != nil {
+			f.Close()
+			t.Fatal(err)
+		}
+		if err := f.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("%v; set DEVICETEST_UPDATE=1 to create it", err)
+	}
+	defer f.Close()
+	want, err := png.Decode(f)
+	if err != nil {
+		t.Fatalf("devicetest: %s: %v", path, err)
+	}
+	if err := CompareImages(got, want); err != nil {
+		t.Errorf("%s: %v", path, err)
+	}
+}
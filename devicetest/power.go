@@ -0,0 +1,42 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package devicetest
+
+import (
+	"testing"
+
+	"periph.io/x/devices/v3"
+)
+
+// PowerSensor checks that s implements devices.PowerSensor consistently.
+//
+// The sensor is read with SensePower, then Voltage, Current and Power in this
+// order, each reading must match want. Tests of the drivers provide the bus
+// transactions of the four reads.
+func PowerSensor(t testing.TB, s devices.PowerSensor, want devices.PowerEnv) {
+	t.Helper()
+	var e devices.PowerEnv
+	if err := s.SensePower(&e); err != nil {
+		t.Fatalf("SensePower() failed: %v", err)
+	}
+	if e != want {
+		t.Errorf("SensePower() = %v, want %v", e, want)
+	}
+	if v, err := s.Voltage(); err != nil {
+		t.Errorf("Voltage() failed: %v", err)
+	} else if v != want.Voltage {
+		t.Errorf("Voltage() = %s, want %s", v, want.Voltage)
+	}
+	if c, err := s.Current(); err != nil {
+		t.Errorf("Current() failed: %v", err)
+	} else if c != want.Current {
+		t.Errorf("Current() = %s, want %s", c, want.Current)
+	}
+	if p, err := s.Power(); err != nil {
+		t.Errorf("Power() failed: %v", err)
+	} else if p != want.Power {
+		t.Errorf("Power() = %s, want %s", p, want.Power)
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package devicetest
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpiotest"
+	"periph.io/x/conn/v3/i2c/i2ctest"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/spi"
+)
+
+// Record is a command sent to a display controller followed by its data.
+type Record struct {
+	Cmd  byte
+	Data []byte
+}
+
+// Recorder records the commands and data sent to a display controller.
+//
+// Drivers' tests usually wrap it to implement their internal controller
+// interface:
+//
+//	type fakeController struct{ devicetest.Recorder }
+//
+//	func (c *fakeController) sendCommand(cmd byte)  { c.Command(cmd) }
+//	func (c *fakeController) sendData(data []byte) { c.Data(data) }
+type Recorder struct {
+	Records []Record
+}
+
+// Command records a command.
+func (r *Recorder) Command(cmd byte) {
+	r.Records = append(r.Records, Record{Cmd: cmd})
+}
+
+// Data records data for the last command. Data sent before any command is
+// recorded with the command 0.
+func (r *Recorder) Data(data []byte) {
+	if len(r.Records) == 0 {
+		r.Records = append(r.Records, Record{})
+	}
+	cur := &r.Records[len(r.Records)-1]
+	cur.Data = append(cur.Data, data...)
+}
+
+// Reset clears the records.
+func (r *Recorder) Reset() {
+	r.Records = nil
+}
+
+// Diff returns a human readable difference between the records and want, or
+// an empty string if they are equal. Nil and empty data are equal.
+func (r *Recorder) Diff(want []Record) string {
+	return cmp.Diff(r.Records, want, cmpopts.EquateEmpty())
+}
+
+// CommandConn is a 4-wire SPI connection to a display controller, recording
+// the bytes written while DC is low as commands and the ones written while DC
+// is high as data of the last command.
+//
+// It implements spi.Port and returns itself on Connect, so it can be passed
+// to the drivers' constructors.
+type CommandConn struct {
+	// DC is the data/command pin to pass to the driver.
+	DC gpiotest.Pin
+
+	mu sync.Mutex
+	Recorder
+}
+
+// NewCommandConn returns a CommandConn.
+func NewCommandConn() *CommandConn {
+	return &CommandConn{DC: gpiotest.Pin{N: "DC"}}
+}
+
+// String implements conn.Conn.
+func (c *CommandConn) String() string {
+	return "devicetest.CommandConn"
+}
+
+// Connect implements spi.Port.
+func (c *CommandConn) Connect(f physic.Frequency, mode spi.Mode, bits int) (spi.Conn, error) {
+	if bits != 8 {
+		return nil, fmt.Errorf("devicetest: unsupported %d bits per word", bits)
+	}
+	return c, nil
+}
+
+// Tx implements conn.Conn. Nothing is read back.
+func (c *CommandConn) Tx(w, r []byte) error {
+	data := c.DC.Read() == gpio.High
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if data {
+		c.Data(w)
+		return nil
+	}
+	for _, b := range w {
+		c.Command(b)
+	}
+	return nil
+}
+
+// TxPackets implements spi.Conn.
+func (c *CommandConn) TxPackets(p []spi.Packet) error {
+	for _, pkt := range p {
+		if err := c.Tx(pkt.W, pkt.R); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Duplex implements conn.Conn.
+func (c *CommandConn) Duplex() conn.Duplex {
+	return conn.Half
+}
+
+// I2CWrites returns the playback operations of the writes w to addr.
+func I2CWrites(addr uint16, w ...[]byte) []i2ctest.IO {
+	ops := make([]i2ctest.IO, len(w))
+	for i := range w {
+		ops[i] = i2ctest.IO{Addr: addr, W: w[i]}
+	}
+	return ops
+}
+
+var _ spi.Port = &CommandConn{}
+var _ spi.Conn = &CommandConn{}
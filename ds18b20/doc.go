@@ -9,7 +9,7 @@
 //
 // Both powered sensors and parasitically powered sensors are supported
 // as long as the bus driver can provide sufficient power using an active
-// pull-up.
+// pull-up. Use Dev.Parasite to determine how a sensor is powered.
 //
 // The DS18B20/DS18S20 alarm functionality and reading/writing the 2 alarm
 // bytes in the EEPROM are not supported.
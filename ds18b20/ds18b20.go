@@ -6,6 +6,8 @@ package ds18b20
 
 import (
 	"errors"
+	"log"
+	"sync"
 	"time"
 
 	"periph.io/x/conn/v3"
@@ -111,6 +113,10 @@ func New(o onewire.Bus, addr onewire.Address, resolutionBits int) (*Dev, error)
 type Dev struct {
 	onewire    onewire.Dev // device on 1-wire bus
 	resolution int         // resolution in bits (9..12)
+
+	mu   sync.Mutex
+	stop chan struct{}
+	wg   sync.WaitGroup
 }
 
 func (d *Dev) Family() Family {
@@ -122,7 +128,16 @@ func (d *Dev) String() string {
 }
 
 // Halt implements conn.Resource.
+//
+// It stops the continuous sensing, if any.
 func (d *Dev) Halt() error {
+	d.mu.Lock()
+	if d.stop != nil {
+		close(d.stop)
+		d.stop = nil
+	}
+	d.mu.Unlock()
+	d.wg.Wait()
 	return nil
 }
 
@@ -141,14 +156,49 @@ func (d *Dev) Sense(e *physic.Env) error {
 }
 
 // SenseContinuous implements physic.SenseEnv.
-func (d *Dev) SenseContinuous(time.Duration) (<-chan physic.Env, error) {
-	// TODO(maruel): Manually poll in a loop via time.NewTicker.
-	return nil, errors.New("ds18b20: not implemented")
+//
+// The interval must be at least the conversion time for the configured
+// resolution. Use Halt to stop the sensing and close the channel.
+func (d *Dev) SenseContinuous(interval time.Duration) (<-chan physic.Env, error) {
+	if interval < conversionTime(d.resolution) {
+		return nil, errors.New("ds18b20: interval shorter than the conversion time")
+	}
+	if err := d.Halt(); err != nil {
+		return nil, err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	sensing := make(chan physic.Env)
+	stop := make(chan struct{})
+	d.stop = stop
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		defer close(sensing)
+		d.sensingContinuous(interval, sensing, stop)
+	}()
+	return sensing, nil
 }
 
 // Precision implements physic.SenseEnv.
+//
+// The precision depends on the resolution: 9bits:0.5°C, 10bits:0.25°C,
+// 11bits:0.125°C, 12bits:0.0625°C.
 func (d *Dev) Precision(e *physic.Env) {
-	e.Temperature = physic.Kelvin / 16
+	e.Temperature = physic.Kelvin / physic.Temperature(2<<uint(d.resolution-9))
+}
+
+// Parasite returns true if the device is powered parasitically from the data
+// line, in which case the bus driver must provide a strong pull-up during
+// conversions.
+func (d *Dev) Parasite() (bool, error) {
+	// Read Power Supply; a parasite powered device pulls the bus low during the
+	// first read time slot.
+	var r [1]byte
+	if err := d.onewire.Tx([]byte{0xb4}, r[:]); err != nil {
+		return false, err
+	}
+	return r[0]&1 == 0, nil
 }
 
 // LastTemp reads the temperature resulting from the last conversion from the
@@ -197,6 +247,30 @@ func (d *Dev) parseTemperature(spad []byte) physic.Temperature {
 	return v*physic.Kelvin/16 + physic.ZeroCelsius
 }
 
+func (d *Dev) sensingContinuous(interval time.Duration, sensing chan<- physic.Env, stop <-chan struct{}) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		// Do one initial sensing right away.
+		e := physic.Env{}
+		if err := d.Sense(&e); err != nil {
+			log.Printf("%s: failed to sense: %v", d, err)
+			return
+		}
+		select {
+		case sensing <- e:
+		case <-stop:
+			return
+		}
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+		}
+	}
+}
+
 // busError implements error and onewire.BusError.
 type busError string
 
@@ -207,7 +281,12 @@ func (e busError) BusError() bool { return true }
 // on the resolution:
 // 9bits:94ms, 10bits:188ms, 11bits:376ms, 12bits:752ms, datasheet p.6.
 func conversionSleep(bits int) {
-	sleep((94 << uint(bits-9)) * time.Millisecond)
+	sleep(conversionTime(bits))
+}
+
+// conversionTime returns the time a conversion takes at the resolution bits.
+func conversionTime(bits int) time.Duration {
+	return (94 << uint(bits-9)) * time.Millisecond
 }
 
 // readScratchpad reads the 9 bytes of scratchpad and checks the CRC.
@@ -85,6 +85,88 @@ func TestSense(t *testing.T) {
 	}
 }
 
+func TestSenseContinuous(t *testing.T) {
+	ops := []onewiretest.IO{
+		{
+			W: []uint8{0x55, 0x28, 0xac, 0x41, 0xe, 0x7, 0x0, 0x0, 0x74, 0xbe},
+			R: []uint8{0xe0, 0x1, 0x0, 0x0, 0x3f, 0xff, 0x10, 0x10, 0x3f},
+		},
+		{
+			W:    []uint8{0x55, 0x28, 0xac, 0x41, 0xe, 0x7, 0x0, 0x0, 0x74, 0x44},
+			Pull: true,
+		},
+		{
+			W: []uint8{0x55, 0x28, 0xac, 0x41, 0xe, 0x7, 0x0, 0x0, 0x74, 0xbe},
+			R: []uint8{0xe0, 0x1, 0x0, 0x0, 0x3f, 0xff, 0x10, 0x10, 0x3f},
+		},
+	}
+	var addr onewire.Address = 0x740000070e41ac28
+	bus := onewiretest.Playback{Ops: ops}
+	dev, err := New(&bus, addr, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dev.SenseContinuous(100 * time.Millisecond); err == nil {
+		t.Fatal("expected error for too short interval")
+	}
+	c, err := dev.SenseContinuous(time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := <-c
+	if expected := 30*physic.Celsius + physic.ZeroCelsius; e.Temperature != expected {
+		t.Errorf("expected %s, got %s", expected.String(), e.Temperature.String())
+	}
+	if err := dev.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := <-c; ok {
+		t.Fatal("expected channel to be closed")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPrecision(t *testing.T) {
+	for bits, expected := range map[int]physic.Temperature{
+		9:  500 * physic.MilliKelvin,
+		10: 250 * physic.MilliKelvin,
+		11: 125 * physic.MilliKelvin,
+		12: physic.Kelvin / 16,
+	} {
+		d := Dev{resolution: bits}
+		e := physic.Env{}
+		d.Precision(&e)
+		if e.Temperature != expected {
+			t.Errorf("%d bits: expected %s, got %s", bits, expected, e.Temperature)
+		}
+	}
+}
+
+func TestParasite(t *testing.T) {
+	var addr onewire.Address = 0x740000070e41ac28
+	for _, r := range []byte{0x00, 0xff} {
+		bus := onewiretest.Playback{Ops: []onewiretest.IO{
+			{
+				W: []uint8{0x55, 0x28, 0xac, 0x41, 0xe, 0x7, 0x0, 0x0, 0x74, 0xb4},
+				R: []uint8{r},
+			},
+		}}
+		d := Dev{onewire: onewire.Dev{Bus: &bus, Addr: addr}, resolution: 10}
+		p, err := d.Parasite()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p != (r == 0) {
+			t.Fatalf("%#x: unexpected %t", r, p)
+		}
+		if err := bus.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
 // TestParseTemperature tests a temperature parsing from scratchpad for DS18S20
 // and DS18B20
 func TestParseTemperature(t *testing.T) {
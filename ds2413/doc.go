@@ -0,0 +1,20 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package ds2413 interfaces to Maxim DS2413 and DS2406 1-wire dual channel
+// addressable switches.
+//
+// Both PIO channels are exposed as gpio.PinIO. The outputs are open drain: Out
+// with gpio.Low turns the output transistor on, pulling the line low. Out with
+// gpio.High and In release the line, which then needs an external pull-up.
+//
+// The DS2406 optional EPROM memory, the conditional search and the activity
+// latches are not supported.
+//
+// # Datasheets
+//
+// https://datasheets.maximintegrated.com/en/ds/DS2413.pdf
+//
+// https://datasheets.maximintegrated.com/en/ds/DS2406.pdf
+package ds2413
@@ -0,0 +1,303 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ds2413
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpioreg"
+	"periph.io/x/conn/v3/onewire"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/pin"
+)
+
+// Family code of the specific device type.
+type Family byte
+
+func (f Family) String() string {
+	switch f {
+	case DS2406:
+		return "DS2406"
+	case DS2413:
+		return "DS2413"
+	default:
+		return "unknown"
+	}
+}
+
+const (
+	DS2406 Family = 0x12
+	DS2413 Family = 0x3a
+)
+
+// New returns an object that communicates over 1-wire to the DS2413 or DS2406
+// switch with the specified 64-bit address.
+//
+// The device type is determined by the family code in the address. The PIO
+// pins are registered in gpioreg with the name <family>_<address>_PIO<A|B>.
+func New(o onewire.Bus, addr onewire.Address) (*Dev, error) {
+	d := &Dev{onewire: onewire.Dev{Bus: o, Addr: addr}}
+	switch d.Family() {
+	case DS2406, DS2413:
+	default:
+		return nil, fmt.Errorf("ds2413: unsupported family code %#x", byte(d.Family()))
+	}
+	// Read the current state, this also checks the device is present.
+	if _, err := d.readState(); err != nil {
+		return nil, err
+	}
+	for i := range d.Pins {
+		p := &pioPin{dev: d, channel: uint8(i)}
+		d.Pins[i] = p
+		// Ignore registration failure.
+		_ = gpioreg.Register(p)
+	}
+	return d, nil
+}
+
+// Dev is a handle to a DS2413 or DS2406 switch on a 1-wire bus.
+type Dev struct {
+	// Pins are the PIO-A and PIO-B channels.
+	Pins [2]gpio.PinIO
+
+	onewire onewire.Dev // device on 1-wire bus
+
+	mu      sync.Mutex
+	latches uint8 // cached output latches, bit 0 is PIO-A, 1 is released
+}
+
+// Family returns the family code of the device.
+func (d *Dev) Family() Family {
+	return Family(d.onewire.Addr & 0xFF)
+}
+
+func (d *Dev) String() string {
+	return d.Family().String() + "{" + d.onewire.String() + "}"
+}
+
+// Halt implements conn.Resource.
+//
+// It releases both outputs.
+func (d *Dev) Halt() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.writeLatches(0x03)
+}
+
+// Close removes any registration to the device.
+func (d *Dev) Close() error {
+	for _, p := range d.Pins {
+		if err := gpioreg.Unregister(p.Name()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// state is the decoded state of both channels.
+type state struct {
+	levels  uint8 // sensed pin levels, bit 0 is PIO-A
+	latches uint8 // output latches, bit 0 is PIO-A, 1 is released
+}
+
+// readState reads the state of both channels and refreshes the latches cache.
+func (d *Dev) readState() (state, error) {
+	var s state
+	if d.Family() == DS2413 {
+		// PIO Access Read.
+		var r [1]byte
+		if err := d.onewire.Tx([]byte{0xf5}, r[:]); err != nil {
+			return s, err
+		}
+		if r[0]>>4 != ^r[0]&0x0f {
+			return s, busError("ds2413: invalid PIO status")
+		}
+		s.levels = r[0]&1 | (r[0]>>1)&2
+		s.latches = (r[0]>>1)&1 | (r[0]>>2)&2
+	} else {
+		// Channel Access, read both channels once with a CRC after every byte.
+		w := []byte{0xf5, 0x4d, 0xff}
+		var r [4]byte
+		if err := d.onewire.Tx(w, r[:]); err != nil {
+			return s, err
+		}
+		if !checkCRC16(append(w, r[:2]...), r[2:]) {
+			return s, busError("ds2413: incorrect channel access CRC")
+		}
+		s.levels = (r[0] >> 2) & 3
+		s.latches = r[0] & 3
+	}
+	d.latches = s.latches
+	return s, nil
+}
+
+// writeLatches sets both output latches.
+func (d *Dev) writeLatches(v uint8) error {
+	if d.Family() == DS2413 {
+		// PIO Access Write, the data is sent twice, inverted the second time.
+		b := v | 0xfc
+		var r [2]byte
+		if err := d.onewire.Tx([]byte{0x5a, b, ^b}, r[:]); err != nil {
+			return err
+		}
+		if r[0] != 0xaa {
+			return busError("ds2413: PIO write not confirmed")
+		}
+	} else {
+		// Write Status to the SRAM status byte, which holds the flip-flops.
+		w := []byte{0x55, 0x07, 0x00, v<<5 | 0x1f}
+		var c [2]byte
+		if err := d.onewire.Tx(w, c[:]); err != nil {
+			return err
+		}
+		if !checkCRC16(w, c[:]) {
+			return busError("ds2413: incorrect write status CRC")
+		}
+	}
+	d.latches = v
+	return nil
+}
+
+// setLatch changes the output latch of a single channel.
+func (d *Dev) setLatch(channel uint8, released bool) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	v := d.latches &^ (1 << channel)
+	if released {
+		v |= 1 << channel
+	}
+	return d.writeLatches(v)
+}
+
+// busError implements error and onewire.BusError.
+type busError string
+
+func (e busError) Error() string  { return string(e) }
+func (e busError) BusError() bool { return true }
+
+// crc16 computes the 1-wire CRC16 (x^16 + x^15 + x^2 + 1) of buf.
+func crc16(buf []byte) uint16 {
+	var crc uint16
+	for _, b := range buf {
+		crc ^= uint16(b)
+		for i := 0; i < 8; i++ {
+			if crc&1 != 0 {
+				crc = crc>>1 ^ 0xa001
+			} else {
+				crc >>= 1
+			}
+		}
+	}
+	return crc
+}
+
+// checkCRC16 verifies the inverted CRC16 sent by the device, LSB first.
+func checkCRC16(buf, c []byte) bool {
+	return ^crc16(buf) == uint16(c[0])|uint16(c[1])<<8
+}
+
+// pioPin is a PIO channel of the switch.
+type pioPin struct {
+	dev     *Dev
+	channel uint8
+}
+
+func (p *pioPin) String() string {
+	return p.Name()
+}
+
+func (p *pioPin) Halt() error {
+	return p.In(gpio.Float, gpio.NoEdge)
+}
+
+func (p *pioPin) Name() string {
+	return fmt.Sprintf("%s_%x_PIO%c", p.dev.Family(), uint64(p.dev.onewire.Addr), 'A'+p.channel)
+}
+
+func (p *pioPin) Number() int {
+	return int(p.channel)
+}
+
+func (p *pioPin) Function() string {
+	return string(p.Func())
+}
+
+func (p *pioPin) Func() pin.Func {
+	p.dev.mu.Lock()
+	defer p.dev.mu.Unlock()
+	if p.dev.latches&(1<<p.channel) == 0 {
+		return gpio.OUT_LOW
+	}
+	return gpio.IN
+}
+
+func (p *pioPin) SupportedFuncs() []pin.Func {
+	return supportedFuncs[:]
+}
+
+func (p *pioPin) SetFunc(f pin.Func) error {
+	switch f {
+	case gpio.IN, gpio.OUT_HIGH:
+		return p.dev.setLatch(p.channel, true)
+	case gpio.OUT_LOW:
+		return p.dev.setLatch(p.channel, false)
+	default:
+		return errors.New("ds2413: function not supported: " + string(f))
+	}
+}
+
+// In releases the output so the pin can be read.
+func (p *pioPin) In(pull gpio.Pull, edge gpio.Edge) error {
+	if pull != gpio.Float && pull != gpio.PullNoChange {
+		return errors.New("ds2413: pull resistors are not supported")
+	}
+	if edge != gpio.NoEdge {
+		return errors.New("ds2413: edge detection not supported")
+	}
+	return p.dev.setLatch(p.channel, true)
+}
+
+// Read returns the sensed level of the pin.
+func (p *pioPin) Read() gpio.Level {
+	p.dev.mu.Lock()
+	defer p.dev.mu.Unlock()
+	s, err := p.dev.readState()
+	if err != nil {
+		return gpio.Low
+	}
+	return s.levels&(1<<p.channel) != 0
+}
+
+func (p *pioPin) WaitForEdge(timeout time.Duration) bool {
+	return false
+}
+
+func (p *pioPin) Pull() gpio.Pull {
+	return gpio.Float
+}
+
+func (p *pioPin) DefaultPull() gpio.Pull {
+	return gpio.Float
+}
+
+// Out turns the output transistor on for gpio.Low and releases it for
+// gpio.High.
+func (p *pioPin) Out(l gpio.Level) error {
+	return p.dev.setLatch(p.channel, l == gpio.High)
+}
+
+func (p *pioPin) PWM(duty gpio.Duty, f physic.Frequency) error {
+	return errors.New("ds2413: PWM is not supported")
+}
+
+var supportedFuncs = [...]pin.Func{gpio.IN, gpio.OUT_LOW, gpio.OUT_HIGH}
+
+var _ conn.Resource = &Dev{}
+var _ gpio.PinIO = &pioPin{}
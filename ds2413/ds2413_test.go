@@ -0,0 +1,125 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ds2413
+
+import (
+	"testing"
+
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpioreg"
+	"periph.io/x/conn/v3/onewire"
+	"periph.io/x/conn/v3/onewire/onewiretest"
+)
+
+const (
+	addr2413 onewire.Address = 0x8f000000215b233a
+	addr2406 onewire.Address = 0x1c0000004a0c6112
+)
+
+// match returns the match ROM prefix followed by w.
+func match(a onewire.Address, w ...byte) []byte {
+	b := []byte{0x55}
+	for i := 0; i < 8; i++ {
+		b = append(b, byte(a>>(8*i)))
+	}
+	return append(b, w...)
+}
+
+// withCRC appends the inverted CRC16 of buf to r.
+func withCRC(buf, r []byte) []byte {
+	c := ^crc16(buf)
+	return append(r, byte(c), byte(c>>8))
+}
+
+func TestNew_unsupported(t *testing.T) {
+	bus := &onewiretest.Playback{}
+	if d, err := New(bus, 0x740000070e41ac28); d != nil || err == nil {
+		t.Fatal("family 0x28 is not a switch")
+	}
+}
+
+func TestDS2413(t *testing.T) {
+	bus := &onewiretest.Playback{
+		Ops: []onewiretest.IO{
+			// Both latches released, PIO-A sensed high.
+			{W: match(addr2413, 0xf5), R: []byte{0x4b}},
+			// PIO-B pulled low.
+			{W: match(addr2413, 0x5a, 0xfd, 0x02), R: []byte{0xaa, 0x0f}},
+			// State read back.
+			{W: match(addr2413, 0xf5), R: []byte{0xc3}},
+			// Invalid status.
+			{W: match(addr2413, 0xf5), R: []byte{0x00}},
+			// Halt releases both.
+			{W: match(addr2413, 0x5a, 0xff, 0x00), R: []byte{0xaa, 0x0f}},
+		},
+	}
+	d, err := New(bus, addr2413)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+	if s := d.String(); s != "DS2413{playback(0x8f000000215b233a)}" {
+		t.Fatal(s)
+	}
+	p := gpioreg.ByName("DS2413_8f000000215b233a_PIOB")
+	if p == nil {
+		t.Fatal("PIOB is not registered")
+	}
+	if p.Function() != string(gpio.IN) {
+		t.Fatalf("Function() = %s", p.Function())
+	}
+	if err := p.Out(gpio.Low); err != nil {
+		t.Fatal(err)
+	}
+	if p.Function() != string(gpio.OUT_LOW) {
+		t.Fatalf("Function() = %s", p.Function())
+	}
+	if l := d.Pins[0].Read(); l != gpio.High {
+		t.Fatalf("PIOA Read() = %s", l)
+	}
+	if l := p.Read(); l != gpio.Low {
+		t.Fatalf("PIOB Read() = %s", l)
+	}
+	if err := d.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDS2406(t *testing.T) {
+	access := []byte{0xf5, 0x4d, 0xff}
+	bus := &onewiretest.Playback{
+		Ops: []onewiretest.IO{
+			// Both flip-flops released, PIO-B sensed high.
+			{W: match(addr2406, access...), R: withCRC(append(access, 0x4b, 0x00), []byte{0x4b, 0x00})},
+			// PIO-A pulled low.
+			{W: match(addr2406, 0x55, 0x07, 0x00, 0x5f), R: withCRC([]byte{0x55, 0x07, 0x00, 0x5f}, nil)},
+			// Bad CRC.
+			{W: match(addr2406, access...), R: []byte{0x4b, 0x00, 0x00, 0x00}},
+		},
+	}
+	d, err := New(bus, addr2406)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+	if err := d.Pins[0].Out(gpio.Low); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Pins[1].PWM(gpio.DutyHalf, 0); err == nil {
+		t.Fatal("PWM should return an error")
+	}
+	if err := d.Pins[1].In(gpio.PullUp, gpio.NoEdge); err == nil {
+		t.Fatal("PullUp should return an error")
+	}
+	if _, err := d.readState(); err == nil {
+		t.Fatal("expected CRC error")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,22 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package ds243x interfaces to Maxim DS2431 and DS2433 1-wire EEPROMs.
+//
+// The DS2431 has 1024 bits of EEPROM organized as 4 pages of 32 bytes and an 8
+// byte scratchpad. It also has a control page used to write protect individual
+// pages. The DS2433 has 4096 bits of EEPROM organized as 16 pages of 32 bytes
+// and a 32 byte scratchpad. It has no write protection.
+//
+// Writes go through the scratchpad: the data is written to the scratchpad,
+// read back and verified, then copied to EEPROM. On the DS2431 the transfers
+// are verified with the device's CRC16. On the DS2433 the scratchpad content is
+// compared with the data written.
+//
+// # Datasheets
+//
+// https://datasheets.maximintegrated.com/en/ds/DS2431.pdf
+//
+// https://datasheets.maximintegrated.com/en/ds/DS2433.pdf
+package ds243x
@@ -0,0 +1,313 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ds243x
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/onewire"
+)
+
+// Family code of the specific device type.
+type Family byte
+
+func (f Family) String() string {
+	switch f {
+	case DS2431:
+		return "DS2431"
+	case DS2433:
+		return "DS2433"
+	default:
+		return "unknown"
+	}
+}
+
+const (
+	DS2431 Family = 0x2d
+	DS2433 Family = 0x23
+)
+
+// Protection is the value of a DS2431 page protection control byte.
+type Protection byte
+
+const (
+	// Unprotected is any value other than WriteProtected and EPROMMode.
+	Unprotected Protection = 0x00
+	// WriteProtected prevents any further write to the page.
+	WriteProtected Protection = 0x55
+	// EPROMMode only allows bits to change from 1 to 0.
+	EPROMMode Protection = 0xaa
+)
+
+func (p Protection) String() string {
+	switch p {
+	case WriteProtected:
+		return "WriteProtected"
+	case EPROMMode:
+		return "EPROMMode"
+	default:
+		return "Unprotected"
+	}
+}
+
+// PageSize is the size of an EEPROM page in bytes.
+const PageSize = 32
+
+// New returns an object that communicates over 1-wire to the DS2431 or DS2433
+// EEPROM with the specified 64-bit address.
+//
+// The device type is determined by the family code in the address.
+func New(o onewire.Bus, addr onewire.Address) (*Dev, error) {
+	d := &Dev{onewire: onewire.Dev{Bus: o, Addr: addr}}
+	switch d.Family() {
+	case DS2431:
+		d.size = 4 * PageSize
+		d.spad = 8
+		d.tprog = 10 * time.Millisecond
+	case DS2433:
+		d.size = 16 * PageSize
+		d.spad = 32
+		d.tprog = 5 * time.Millisecond
+	default:
+		return nil, fmt.Errorf("ds243x: unsupported family code %#x", byte(d.Family()))
+	}
+	return d, nil
+}
+
+// Dev is a handle to a Maxim DS2431 or DS2433 EEPROM on a 1-wire bus.
+//
+// Dev implements io.ReaderAt and io.WriterAt over the EEPROM memory.
+type Dev struct {
+	onewire onewire.Dev   // device on 1-wire bus
+	size    int           // EEPROM size in bytes
+	spad    int           // scratchpad size in bytes
+	tprog   time.Duration // maximum programming time
+}
+
+// Family returns the family code of the device.
+func (d *Dev) Family() Family {
+	return Family(d.onewire.Addr & 0xFF)
+}
+
+func (d *Dev) String() string {
+	return d.Family().String() + "{" + d.onewire.String() + "}"
+}
+
+// Halt implements conn.Resource.
+func (d *Dev) Halt() error {
+	return nil
+}
+
+// Size returns the size of the EEPROM memory in bytes.
+func (d *Dev) Size() int {
+	return d.size
+}
+
+// ReadAt implements io.ReaderAt.
+func (d *Dev) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 || off > int64(d.size) {
+		return 0, errors.New("ds243x: invalid offset")
+	}
+	n := len(p)
+	if rem := d.size - int(off); n > rem {
+		n = rem
+	}
+	if n != 0 {
+		if err := d.readMemory(uint16(off), p[:n]); err != nil {
+			return 0, err
+		}
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// WriteAt implements io.WriterAt.
+//
+// The data is split in scratchpad sized writes, each one taking up to 10ms on
+// the DS2431 and 5ms on the DS2433. On the DS2431, writes that don't cover a
+// whole 8 bytes row read the row first to preserve the remaining bytes.
+func (d *Dev) WriteAt(p []byte, off int64) (int, error) {
+	if off < 0 || off+int64(len(p)) > int64(d.size) {
+		return 0, errors.New("ds243x: write out of range")
+	}
+	n := 0
+	for n < len(p) {
+		addr := int(off) + n
+		start := addr % d.spad
+		chunk := d.spad - start
+		if rem := len(p) - n; chunk > rem {
+			chunk = rem
+		}
+		if err := d.writeChunk(uint16(addr), p[n:n+chunk]); err != nil {
+			return n, err
+		}
+		n += chunk
+	}
+	return n, nil
+}
+
+// PageProtection returns the protection of a DS2431 page.
+func (d *Dev) PageProtection(page int) (Protection, error) {
+	if d.Family() != DS2431 {
+		return Unprotected, errors.New("ds243x: page protection is only supported on DS2431")
+	}
+	if page < 0 || page > 3 {
+		return Unprotected, errors.New("ds243x: invalid page")
+	}
+	var b [1]byte
+	if err := d.readMemory(uint16(0x80+page), b[:]); err != nil {
+		return Unprotected, err
+	}
+	switch p := Protection(b[0]); p {
+	case WriteProtected, EPROMMode:
+		return p, nil
+	default:
+		return Unprotected, nil
+	}
+}
+
+// ProtectPage sets the protection of a DS2431 page.
+//
+// Warning: this is irreversible. Once a page is write protected or in EPROM
+// mode, its control byte is itself locked.
+func (d *Dev) ProtectPage(page int, p Protection) error {
+	if d.Family() != DS2431 {
+		return errors.New("ds243x: page protection is only supported on DS2431")
+	}
+	if page < 0 || page > 3 {
+		return errors.New("ds243x: invalid page")
+	}
+	if p != WriteProtected && p != EPROMMode {
+		return errors.New("ds243x: invalid protection")
+	}
+	return d.writeChunk(uint16(0x80+page), []byte{byte(p)})
+}
+
+//
+
+// readMemory reads len(p) bytes starting at addr.
+func (d *Dev) readMemory(addr uint16, p []byte) error {
+	return d.onewire.Tx([]byte{0xf0, byte(addr), byte(addr >> 8)}, p)
+}
+
+// writeChunk writes data that fits in a single scratchpad at addr, then copies
+// it to EEPROM.
+func (d *Dev) writeChunk(addr uint16, data []byte) error {
+	if d.Family() == DS2431 && (addr%8 != 0 || len(data) != 8) {
+		// The DS2431 only writes whole 8 bytes rows.
+		row := addr &^ 7
+		var buf [8]byte
+		if err := d.readMemory(row, buf[:]); err != nil {
+			return err
+		}
+		copy(buf[addr-row:], data)
+		addr = row
+		data = buf[:]
+	}
+	if err := d.writeScratchpad(addr, data); err != nil {
+		return err
+	}
+	es, err := d.readScratchpad(addr, data)
+	if err != nil {
+		return err
+	}
+	if err := d.onewire.TxPower([]byte{0x55, byte(addr), byte(addr >> 8), es}, nil); err != nil {
+		return err
+	}
+	sleep(d.tprog)
+	// The authorization accepted flag is set once the copy succeeded.
+	if es, err = d.readScratchpad(addr, nil); err != nil {
+		return err
+	}
+	if es&0x80 == 0 {
+		return busError("ds243x: copy scratchpad failed (page write protected?)")
+	}
+	return nil
+}
+
+// writeScratchpad writes data to the scratchpad, verifying the DS2431 CRC16.
+func (d *Dev) writeScratchpad(addr uint16, data []byte) error {
+	w := append([]byte{0x0f, byte(addr), byte(addr >> 8)}, data...)
+	if d.Family() == DS2433 {
+		return d.onewire.Tx(w, nil)
+	}
+	var c [2]byte
+	if err := d.onewire.Tx(w, c[:]); err != nil {
+		return err
+	}
+	if !checkCRC16(w, c[:]) {
+		return busError("ds243x: incorrect write scratchpad CRC")
+	}
+	return nil
+}
+
+// readScratchpad reads back the scratchpad, checks the target address and
+// data if provided and returns the ending address/status byte.
+func (d *Dev) readScratchpad(addr uint16, data []byte) (byte, error) {
+	n := 3 + d.spad - int(addr)%d.spad
+	if d.Family() == DS2431 {
+		n += 2
+	}
+	r := make([]byte, n)
+	if err := d.onewire.Tx([]byte{0xaa}, r); err != nil {
+		return 0, err
+	}
+	if d.Family() == DS2431 && !checkCRC16(append([]byte{0xaa}, r[:n-2]...), r[n-2:]) {
+		return 0, busError("ds243x: incorrect read scratchpad CRC")
+	}
+	if uint16(r[0])|uint16(r[1])<<8 != addr {
+		return 0, busError("ds243x: scratchpad target address mismatch")
+	}
+	if data != nil {
+		if r[2]&0x20 != 0 {
+			return 0, busError("ds243x: partial scratchpad write")
+		}
+		es := r[2] & 0x1f
+		if int(es)%d.spad != (int(addr)+len(data)-1)%d.spad || !bytes.Equal(r[3:3+len(data)], data) {
+			return 0, busError("ds243x: scratchpad data mismatch")
+		}
+	}
+	return r[2], nil
+}
+
+// busError implements error and onewire.BusError.
+type busError string
+
+func (e busError) Error() string  { return string(e) }
+func (e busError) BusError() bool { return true }
+
+// crc16 computes the 1-wire CRC16 (x^16 + x^15 + x^2 + 1) of buf.
+func crc16(buf []byte) uint16 {
+	var crc uint16
+	for _, b := range buf {
+		crc ^= uint16(b)
+		for i := 0; i < 8; i++ {
+			if crc&1 != 0 {
+				crc = crc>>1 ^ 0xa001
+			} else {
+				crc >>= 1
+			}
+		}
+	}
+	return crc
+}
+
+// checkCRC16 verifies the inverted CRC16 sent by the device, LSB first.
+func checkCRC16(buf, c []byte) bool {
+	return ^crc16(buf) == uint16(c[0])|uint16(c[1])<<8
+}
+
+var sleep = time.Sleep
+
+var _ conn.Resource = &Dev{}
+var _ io.ReaderAt = &Dev{}
+var _ io.WriterAt = &Dev{}
@@ -0,0 +1,237 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ds243x
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3/onewire"
+	"periph.io/x/conn/v3/onewire/onewiretest"
+)
+
+const (
+	addr2431 onewire.Address = 0xa70000017a5e6a2d
+	addr2433 onewire.Address = 0x5c0000000f1c8b23
+)
+
+// match returns the match ROM prefix followed by w.
+func match(a onewire.Address, w ...byte) []byte {
+	b := []byte{0x55}
+	for i := 0; i < 8; i++ {
+		b = append(b, byte(a>>(8*i)))
+	}
+	return append(b, w...)
+}
+
+// withCRC appends the inverted CRC16 of buf to r.
+func withCRC(buf, r []byte) []byte {
+	c := ^crc16(buf)
+	return append(r, byte(c), byte(c>>8))
+}
+
+func TestCRC16(t *testing.T) {
+	if c := crc16([]byte("123456789")); c != 0xbb3d {
+		t.Fatalf("crc16() = %#x; want 0xbb3d", c)
+	}
+	w := []byte{0x0f, 0x00, 0x00, 1, 2, 3, 4, 5, 6, 7, 8}
+	if !checkCRC16(w, withCRC(w, nil)) {
+		t.Fatal("checkCRC16() failed")
+	}
+	if checkCRC16(w, []byte{0, 0}) {
+		t.Fatal("checkCRC16() should fail")
+	}
+}
+
+func TestNew(t *testing.T) {
+	bus := &onewiretest.Playback{}
+	if d, err := New(bus, 0x740000070e41ac28); d != nil || err == nil {
+		t.Fatal("family 0x28 is not an EEPROM")
+	}
+	d, err := New(bus, addr2431)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := d.String(); s != "DS2431{playback(0xa70000017a5e6a2d)}" {
+		t.Fatal(s)
+	}
+	if d.Size() != 128 {
+		t.Fatalf("Size() = %d", d.Size())
+	}
+	if d, err = New(bus, addr2433); err != nil {
+		t.Fatal(err)
+	}
+	if d.Size() != 512 {
+		t.Fatalf("Size() = %d", d.Size())
+	}
+	if _, err := d.PageProtection(0); err == nil {
+		t.Fatal("DS2433 has no page protection")
+	}
+}
+
+func TestReadAt(t *testing.T) {
+	data := []byte{1, 2, 3, 4}
+	bus := &onewiretest.Playback{
+		Ops: []onewiretest.IO{
+			{W: match(addr2431, 0xf0, 0x7e, 0x00), R: data[:2]},
+		},
+	}
+	d, err := New(bus, addr2431)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 4)
+	n, err := d.ReadAt(buf, 126)
+	if n != 2 || err != io.EOF {
+		t.Fatalf("ReadAt() = %d, %v", n, err)
+	}
+	if !bytes.Equal(buf[:2], data[:2]) {
+		t.Fatalf("ReadAt() = %v", buf)
+	}
+	if _, err := d.ReadAt(buf, 129); err == nil {
+		t.Fatal("expected error")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWriteAt_DS2431(t *testing.T) {
+	row := []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+	data := []byte{0xff, 0xff, 0xff, 0xde, 0xad, 0xff, 0xff, 0xff}
+	w := append([]byte{0x0f, 0x08, 0x00}, data...)
+	spad := append([]byte{0x08, 0x00, 0x1f}, data...)
+	copied := append([]byte{0x08, 0x00, 0x9f}, data...)
+	bus := &onewiretest.Playback{
+		Ops: []onewiretest.IO{
+			// The row is read first since the write is partial.
+			{W: match(addr2431, 0xf0, 0x08, 0x00), R: row},
+			{W: match(addr2431, w...), R: withCRC(w, nil)},
+			{W: match(addr2431, 0xaa), R: withCRC(append([]byte{0xaa}, spad...), spad)},
+			{W: match(addr2431, 0x55, 0x08, 0x00, 0x1f), Pull: onewire.StrongPullup},
+			{W: match(addr2431, 0xaa), R: withCRC(append([]byte{0xaa}, copied...), copied)},
+		},
+	}
+	var sleeps []time.Duration
+	sleep = func(d time.Duration) { sleeps = append(sleeps, d) }
+	defer func() { sleep = time.Sleep }()
+	d, err := New(bus, addr2431)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := d.WriteAt([]byte{0xde, 0xad}, 11); n != 2 || err != nil {
+		t.Fatalf("WriteAt() = %d, %v", n, err)
+	}
+	if len(sleeps) != 1 || sleeps[0] != 10*time.Millisecond {
+		t.Fatalf("unexpected sleeps %v", sleeps)
+	}
+	if _, err := d.WriteAt([]byte{0}, 128); err == nil {
+		t.Fatal("expected out of range error")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWriteAt_DS2431_badCRC(t *testing.T) {
+	data := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	w := append([]byte{0x0f, 0x00, 0x00}, data...)
+	bus := &onewiretest.Playback{
+		Ops: []onewiretest.IO{
+			{W: match(addr2431, w...), R: []byte{0x12, 0x34}},
+		},
+	}
+	d, err := New(bus, addr2431)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.WriteAt(data, 0); err == nil {
+		t.Fatal("expected CRC error")
+	} else if b, ok := err.(onewire.BusError); !ok || !b.BusError() {
+		t.Fatalf("expected a onewire.BusError, got %v", err)
+	}
+}
+
+func TestWriteAt_DS2433(t *testing.T) {
+	data := []byte{0xca, 0xfe}
+	spad := make([]byte, 3+32-30)
+	copy(spad, []byte{0x3e, 0x01, 0x1f, 0xca, 0xfe})
+	copied := append([]byte{}, spad...)
+	copied[2] = 0x9f
+	bus := &onewiretest.Playback{
+		Ops: []onewiretest.IO{
+			{W: match(addr2433, 0x0f, 0x3e, 0x01, 0xca, 0xfe)},
+			{W: match(addr2433, 0xaa), R: spad},
+			{W: match(addr2433, 0x55, 0x3e, 0x01, 0x1f), Pull: onewire.StrongPullup},
+			{W: match(addr2433, 0xaa), R: copied},
+		},
+	}
+	sleep = func(time.Duration) {}
+	defer func() { sleep = time.Sleep }()
+	d, err := New(bus, addr2433)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := d.WriteAt(data, 0x13e); n != 2 || err != nil {
+		t.Fatalf("WriteAt() = %d, %v", n, err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWriteAt_protected(t *testing.T) {
+	data := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	w := append([]byte{0x0f, 0x00, 0x00}, data...)
+	spad := append([]byte{0x00, 0x00, 0x1f}, data...)
+	bus := &onewiretest.Playback{
+		Ops: []onewiretest.IO{
+			{W: match(addr2431, w...), R: withCRC(w, nil)},
+			{W: match(addr2431, 0xaa), R: withCRC(append([]byte{0xaa}, spad...), spad)},
+			{W: match(addr2431, 0x55, 0x00, 0x00, 0x1f), Pull: onewire.StrongPullup},
+			// The AA flag is not set, the copy didn't happen.
+			{W: match(addr2431, 0xaa), R: withCRC(append([]byte{0xaa}, spad...), spad)},
+		},
+	}
+	sleep = func(time.Duration) {}
+	defer func() { sleep = time.Sleep }()
+	d, err := New(bus, addr2431)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := d.WriteAt(data, 0); n != 0 || err == nil {
+		t.Fatalf("WriteAt() = %d, %v", n, err)
+	}
+}
+
+func TestPageProtection(t *testing.T) {
+	bus := &onewiretest.Playback{
+		Ops: []onewiretest.IO{
+			{W: match(addr2431, 0xf0, 0x82, 0x00), R: []byte{0x55}},
+			{W: match(addr2431, 0xf0, 0x83, 0x00), R: []byte{0x12}},
+		},
+	}
+	d, err := New(bus, addr2431)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p, err := d.PageProtection(2); p != WriteProtected || err != nil {
+		t.Fatalf("PageProtection() = %s, %v", p, err)
+	}
+	if p, err := d.PageProtection(3); p != Unprotected || err != nil {
+		t.Fatalf("PageProtection() = %s, %v", p, err)
+	}
+	if _, err := d.PageProtection(4); err == nil {
+		t.Fatal("expected invalid page error")
+	}
+	if err := d.ProtectPage(0, Unprotected); err == nil {
+		t.Fatal("expected invalid protection error")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ds243x_test
+
+import (
+	"fmt"
+	"log"
+
+	"periph.io/x/conn/v3/i2c/i2creg"
+	"periph.io/x/devices/v3/ds243x"
+	"periph.io/x/devices/v3/ds248x"
+	"periph.io/x/host/v3"
+)
+
+func Example() {
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Use i2creg I²C bus registry to find the first available I²C bus.
+	b, err := i2creg.Open("")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer b.Close()
+
+	// Open the DS248x to get a 1-wire bus.
+	ob, err := ds248x.New(b, 0x18, &ds248x.DefaultOpts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	devices, err := ob.Search(false)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, addr := range devices {
+		f := ds243x.Family(addr & 0xff)
+		if f != ds243x.DS2431 && f != ds243x.DS2433 {
+			continue
+		}
+		d, err := ds243x.New(ob, addr)
+		if err != nil {
+			log.Fatal(err)
+		}
+		// Store a calibration value at the start of the first page.
+		if _, err := d.WriteAt([]byte("cal=1.0042"), 0); err != nil {
+			log.Fatal(err)
+		}
+		buf := make([]byte, ds243x.PageSize)
+		if _, err := d.ReadAt(buf, 0); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%s: %q\n", d, buf)
+	}
+}
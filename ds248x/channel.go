@@ -0,0 +1,101 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ds248x
+
+import (
+	"fmt"
+
+	"periph.io/x/conn/v3/onewire"
+)
+
+// Channel returns the 1-wire bus connected to channel ch, between 0 and 7, of
+// a DS2482-800. The DS2482-100 and DS2483 only have channel 0.
+//
+// The channel is selected before each transaction, while the ds248x is locked,
+// so the channels can be used concurrently, for example by different
+// ds18b20 devices. Closing the returned bus doesn't close the Dev.
+func (d *Dev) Channel(ch int) (onewire.BusCloser, error) {
+	last := 0
+	if d.isDS248x == isDS2482x800 {
+		last = 7
+	}
+	if ch < 0 || ch > last {
+		return nil, fmt.Errorf("%s: channel %d out of range 0...%d", d, ch, last)
+	}
+	return &channel{d: d, ch: ch}, nil
+}
+
+//
+
+// channel is a 1-wire bus on one channel of a ds248x.
+type channel struct {
+	d  *Dev
+	ch int
+}
+
+func (c *channel) String() string {
+	return fmt.Sprintf("%s/%d", c.d, c.ch)
+}
+
+// Close implements onewire.BusCloser.
+func (c *channel) Close() error {
+	return nil
+}
+
+// Tx implements onewire.Bus.
+func (c *channel) Tx(w, r []byte, power onewire.Pullup) error {
+	c.d.Lock()
+	defer c.d.Unlock()
+	if err := c.selectLocked(); err != nil {
+		return err
+	}
+	return c.d.tx(w, r, power)
+}
+
+// Search implements onewire.Bus.
+//
+// The ds248x stays locked during the whole search.
+func (c *channel) Search(alarmOnly bool) ([]onewire.Address, error) {
+	c.d.Lock()
+	defer c.d.Unlock()
+	if err := c.selectLocked(); err != nil {
+		return nil, err
+	}
+	return onewire.Search(lockedBus{c}, alarmOnly)
+}
+
+// selectLocked selects the channel if it isn't already. The lock must be held.
+func (c *channel) selectLocked() error {
+	if c.d.err != nil {
+		return c.d.err
+	}
+	if c.d.isDS248x != isDS2482x800 || c.d.selected == c.ch {
+		return nil
+	}
+	return c.d.channelSelect(c.ch)
+}
+
+// lockedBus is used by onewire.Search while the ds248x is locked.
+type lockedBus struct {
+	c *channel
+}
+
+func (l lockedBus) String() string {
+	return l.c.String()
+}
+
+func (l lockedBus) Tx(w, r []byte, power onewire.Pullup) error {
+	return l.c.d.tx(w, r, power)
+}
+
+func (l lockedBus) Search(alarmOnly bool) ([]onewire.Address, error) {
+	return onewire.Search(l, alarmOnly)
+}
+
+func (l lockedBus) SearchTriplet(direction byte) (onewire.TripletResult, error) {
+	return l.c.d.SearchTriplet(direction)
+}
+
+var _ onewire.BusCloser = &channel{}
@@ -36,9 +36,31 @@ const (
 	R1000Ω = 6
 )
 
+// Speed is the 1-wire bus speed.
+type Speed uint8
+
+const (
+	// Standard is the regular 1-wire speed, about 15kbps.
+	Standard Speed = iota
+	// Overdrive is the overdrive 1-wire speed, about 110kbps.
+	Overdrive
+)
+
+func (s Speed) String() string {
+	switch s {
+	case Standard:
+		return "Standard"
+	case Overdrive:
+		return "Overdrive"
+	default:
+		return fmt.Sprintf("Speed(%d)", s)
+	}
+}
+
 // Opts contains options to pass to the constructor.
 type Opts struct {
 	PassivePullup bool // false:use active pull-up, true: disable active pullup
+	Overdrive     bool // false: standard speed, true: overdrive speed
 
 	// The following options are only available on the ds2483 (not ds2482-100).
 	// The actual value used is the closest possible value (rounded up or down).
@@ -98,7 +120,23 @@ type Dev struct {
 	confReg    byte          // value written to configuration register
 	tReset     time.Duration // time to perform a 1-wire reset
 	tSlot      time.Duration // time to perform a 1-bit 1-wire read/write
+	stdReset   time.Duration // tReset at standard speed
+	stdSlot    time.Duration // tSlot at standard speed
+	selected   int           // channel selected on ds2482-800, -1 if unknown
 	err        error         // persistent error, device will no longer operate
+	pullupNext bool          // force a strong pull-up at the end of the next Tx
+	stats      Stats         // 1-wire bus statistics
+}
+
+// Stats are counters of the 1-wire bus conditions, to help diagnose long or
+// noisy 1-wire runs.
+type Stats struct {
+	// Resets is the number of 1-wire bus resets issued.
+	Resets uint64
+	// NoPresence is the number of resets to which no device responded.
+	NoPresence uint64
+	// Shorts is the number of resets that detected a short on the bus.
+	Shorts uint64
 }
 
 func (d *Dev) String() string {
@@ -128,36 +166,34 @@ func (d *Dev) Halt() error {
 func (d *Dev) Tx(w, r []byte, power onewire.Pullup) error {
 	d.Lock()
 	defer d.Unlock()
+	return d.tx(w, r, power)
+}
 
-	// Issue 1-wire bus reset.
-	if present, err := d.reset(); err != nil {
-		return err
-	} else if !present {
-		return busError("ds248x: no device present")
-	}
-
-	// Send bytes onto 1-wire bus.
-	for i, b := range w {
-		if power == onewire.StrongPullup && i == len(w)-1 && len(r) == 0 {
-			// This is the last byte, need to activate strong pull-up.
-			d.i2cTx([]byte{cmdWriteConfig, d.confReg&0xbf | 0x4}, nil)
-		}
-		d.i2cTx([]byte{cmd1WWrite, b}, nil)
-		d.waitIdle(7 * d.tSlot)
-	}
+// StrongPullupNext forces the next Tx, on any channel, to end with a strong
+// pull-up even if it is called with onewire.WeakPullup.
+//
+// It is needed when the Tx is done by a device driver unaware that the device
+// is parasite powered, like a DS18B20 whose VDD pin is grounded starting a
+// temperature conversion. The strong pull-up is released by the following
+// 1-wire operation. Calling it with false cancels it.
+func (d *Dev) StrongPullupNext(on bool) {
+	d.Lock()
+	defer d.Unlock()
+	d.pullupNext = on
+}
 
-	// Read bytes from one-wire bus.
-	for i := range r {
-		if power == onewire.StrongPullup && i == len(r)-1 {
-			// This is the last byte, need to activate strong-pull-up
-			d.i2cTx([]byte{cmdWriteConfig, d.confReg&0xbf | 0x4}, nil)
-		}
-		d.i2cTx([]byte{cmd1WRead}, r[i:i+1])
-		d.waitIdle(7 * d.tSlot)
-		d.i2cTx([]byte{cmdSetReadPtr, regRDR}, r[i:i+1])
-	}
+// Stats returns the 1-wire bus statistics since New or the last ResetStats.
+func (d *Dev) Stats() Stats {
+	d.Lock()
+	defer d.Unlock()
+	return d.stats
+}
 
-	return d.err
+// ResetStats resets the 1-wire bus statistics.
+func (d *Dev) ResetStats() {
+	d.Lock()
+	defer d.Unlock()
+	d.stats = Stats{}
 }
 
 // Search performs a "search" cycle on the 1-wire bus and returns the addresses
@@ -175,25 +211,12 @@ func (d *Dev) Search(alarmOnly bool) ([]onewire.Address, error) {
 // 0 and 7. It is expected that application keeps track of
 // with 1-w device is connected to with channel.
 // Communication error is returned if present.
+//
+// Use Channel instead to safely use several channels concurrently.
 func (d *Dev) ChannelSelect(ch int) error {
-	switch d.isDS248x {
-	case isDS2482x800:
-		if ch < 0 || ch > 7 {
-			return fmt.Errorf("%s: channel out of range 0...7", d.String())
-		}
-		buf := []byte{cmdChannelSelect, cscw[ch]}
-		if err := d.i2c.Tx(buf, nil); err != nil {
-			return fmt.Errorf("%s: error: %s", d.String(), err)
-		}
-		return nil
-	case isDS2482x100, isDS2483:
-		if ch != 0 {
-			return fmt.Errorf("%s: invalid channel", d.String())
-		}
-		return nil
-	default:
-		return fmt.Errorf("ds248x: wrong chip")
-	}
+	d.Lock()
+	defer d.Unlock()
+	return d.channelSelect(ch)
 }
 
 // SelectedChannel function is to read with 1-w channel selected on DS2482-800.
@@ -219,6 +242,44 @@ func (d *Dev) SelectedChannel() int {
 	}
 }
 
+// SetSpeed sets the speed of the 1-wire bus.
+//
+// Only devices supporting overdrive, such as most iButton and EEPROM devices,
+// can communicate at overdrive speed. They must first be put in overdrive mode
+// at standard speed with an Overdrive Skip ROM (0x3c) or Overdrive Match ROM
+// (0x69) command. A reset at standard speed returns them to standard speed.
+func (d *Dev) SetSpeed(s Speed) error {
+	d.Lock()
+	defer d.Unlock()
+	if d.err != nil {
+		return d.err
+	}
+	conf := d.confReg
+	switch s {
+	case Standard:
+		conf = conf&^0x08 | 0x80
+	case Overdrive:
+		conf = conf&^0x80 | 0x08
+	default:
+		return fmt.Errorf("%s: invalid speed %s", d, s)
+	}
+	if err := d.writeConfig(conf); err != nil {
+		return fmt.Errorf("%s: %s", d, err)
+	}
+	d.setTiming(s)
+	return nil
+}
+
+// Speed returns the current speed of the 1-wire bus.
+func (d *Dev) Speed() Speed {
+	d.Lock()
+	defer d.Unlock()
+	if d.confReg&0x08 != 0 {
+		return Overdrive
+	}
+	return Standard
+}
+
 // SearchTriplet performs a single bit search triplet command on the bus, waits
 // for it to complete and returs the outcome.
 //
@@ -242,6 +303,95 @@ func (d *Dev) SearchTriplet(direction byte) (onewire.TripletResult, error) {
 
 //
 
+// tx implements Tx. The lock must be held.
+func (d *Dev) tx(w, r []byte, power onewire.Pullup) error {
+	// Issue 1-wire bus reset.
+	if present, err := d.reset(); err != nil {
+		return err
+	} else if !present {
+		return busError("ds248x: no device present")
+	}
+	if d.pullupNext {
+		d.pullupNext = false
+		power = onewire.StrongPullup
+	}
+
+	// Send bytes onto 1-wire bus.
+	for i, b := range w {
+		if power == onewire.StrongPullup && i == len(w)-1 && len(r) == 0 {
+			// This is the last byte, need to activate strong pull-up.
+			d.i2cTx([]byte{cmdWriteConfig, d.confReg&0xbf | 0x4}, nil)
+		}
+		d.i2cTx([]byte{cmd1WWrite, b}, nil)
+		d.waitIdle(7 * d.tSlot)
+	}
+
+	// Read bytes from one-wire bus.
+	for i := range r {
+		if power == onewire.StrongPullup && i == len(r)-1 {
+			// This is the last byte, need to activate strong-pull-up
+			d.i2cTx([]byte{cmdWriteConfig, d.confReg&0xbf | 0x4}, nil)
+		}
+		d.i2cTx([]byte{cmd1WRead}, r[i:i+1])
+		d.waitIdle(7 * d.tSlot)
+		d.i2cTx([]byte{cmdSetReadPtr, regRDR}, r[i:i+1])
+	}
+
+	return d.err
+}
+
+// channelSelect implements ChannelSelect. The lock must be held.
+func (d *Dev) channelSelect(ch int) error {
+	switch d.isDS248x {
+	case isDS2482x800:
+		if ch < 0 || ch > 7 {
+			return fmt.Errorf("%s: channel out of range 0...7", d.String())
+		}
+		buf := []byte{cmdChannelSelect, cscw[ch]}
+		if err := d.i2c.Tx(buf, nil); err != nil {
+			d.selected = -1
+			return fmt.Errorf("%s: error: %s", d.String(), err)
+		}
+		d.selected = ch
+		return nil
+	case isDS2482x100, isDS2483:
+		if ch != 0 {
+			return fmt.Errorf("%s: invalid channel", d.String())
+		}
+		return nil
+	default:
+		return fmt.Errorf("ds248x: wrong chip")
+	}
+}
+
+// writeConfig writes the device configuration register and reads it back to
+// confirm the write.
+func (d *Dev) writeConfig(conf byte) error {
+	var dcr [1]byte
+	if err := d.i2c.Tx([]byte{cmdWriteConfig, conf}, dcr[:]); err != nil {
+		return fmt.Errorf("error while writing device config register: %s", err)
+	}
+	// When reading back we only get the bottom nibble
+	if dcr[0] != conf&0x0f {
+		return fmt.Errorf("failure to write device config register, wrote %#x got %#x back",
+			conf, dcr[0])
+	}
+	d.confReg = conf
+	return nil
+}
+
+// setTiming sets the wait times used for the speed s.
+func (d *Dev) setTiming(s Speed) {
+	if s == Overdrive {
+		// Overdrive timings are fixed, see the DS2482/DS2483 datasheets.
+		d.tReset = 146 * time.Microsecond
+		d.tSlot = 10 * time.Microsecond
+		return
+	}
+	d.tReset = d.stdReset
+	d.tSlot = d.stdSlot
+}
+
 // reset issues a reset signal on the 1-wire bus and returns true if any device
 // responded with a presence pulse.
 func (d *Dev) reset() (bool, error) {
@@ -253,11 +403,17 @@ func (d *Dev) reset() (bool, error) {
 	if d.err != nil {
 		return false, d.err
 	}
+	d.stats.Resets++
 	// Detect bus short and turn into 1-wire error
 	if (status & 4) != 0 {
+		d.stats.Shorts++
 		return false, shortedBusError("onewire/ds248x: bus has a short")
 	}
-	return (status & 2) != 0, nil
+	if (status & 2) == 0 {
+		d.stats.NoPresence++
+		return false, nil
+	}
+	return true, nil
 }
 
 // i2cTx is a helper function to call i2c.Tx and handle the error by persisting
@@ -305,8 +461,8 @@ func (d *Dev) waitIdle(delay time.Duration) byte {
 }
 
 func (d *Dev) makeDev(opts *Opts) error {
-	d.tReset = 2 * opts.ResetLow
-	d.tSlot = opts.Write0Low + opts.Write0Recovery
+	d.stdReset = 2 * opts.ResetLow
+	d.stdSlot = opts.Write0Low + opts.Write0Recovery
 
 	// Issue a reset command.
 	if err := d.i2c.Tx([]byte{cmdReset}, nil); err != nil {
@@ -324,19 +480,19 @@ func (d *Dev) makeDev(opts *Opts) error {
 
 	// Write the device configuration register to get the chip out of reset state, immediately
 	// read it back to get confirmation.
-	d.confReg = 0xe1 // standard-speed, no strong pullup, no powerdown, active pull-up
+	conf := byte(0xe1) // standard-speed, no strong pullup, no powerdown, active pull-up
 	if opts.PassivePullup {
-		d.confReg ^= 0x11
+		conf ^= 0x11
 	}
-	var dcr [1]byte
-	if err := d.i2c.Tx([]byte{cmdWriteConfig, d.confReg}, dcr[:]); err != nil {
-		return fmt.Errorf("ds248x: error while writing device config register: %s", err)
+	speed := Standard
+	if opts.Overdrive {
+		conf ^= 0x88
+		speed = Overdrive
 	}
-	// When reading back we only get the bottom nibble
-	if dcr[0] != d.confReg&0x0f {
-		return fmt.Errorf("ds248x: failure to write device config register, wrote %#x got %#x back",
-			d.confReg, dcr[0])
+	if err := d.writeConfig(conf); err != nil {
+		return fmt.Errorf("ds248x: %s", err)
 	}
+	d.setTiming(speed)
 
 	// Set the read ptr to the port configuration register to determine whether we have a
 	// ds2483 vs ds2482-100 or ds2482-800. This will fail on devices that do not have a port
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"periph.io/x/conn/v3/i2c/i2ctest"
+	"periph.io/x/conn/v3/onewire"
 )
 
 // Testing function New for DS2483.
@@ -91,6 +92,206 @@ func TestNew_DS2482x100(t *testing.T) {
 	}
 }
 
+func TestNew_Overdrive(t *testing.T) {
+	bus := i2ctest.Playback{
+		DontPanic: true,
+		Ops: []i2ctest.IO{
+			{Addr: 0x18, W: []byte{0xf0}},
+			{Addr: 0x18, W: []byte{0xe1, 0xf0}, R: []byte{0x18}},
+			{Addr: 0x18, W: []byte{0xd2, 0x69}, R: []byte{0x9}},
+		},
+	}
+	opts := DefaultOpts
+	opts.Overdrive = true
+	d, err := New(&bus, 0x18, &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := d.Speed(); s != Overdrive {
+		t.Fatal(s)
+	}
+	if d.tReset != 146*time.Microsecond || d.tSlot != 10*time.Microsecond {
+		t.Fatal(d.tReset, d.tSlot)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSetSpeed(t *testing.T) {
+	bus := i2ctest.Playback{
+		DontPanic: true,
+		Ops: []i2ctest.IO{
+			{Addr: 0x18, W: []byte{0xf0}},
+			{Addr: 0x18, W: []byte{0xe1, 0xf0}, R: []byte{0x18}},
+			{Addr: 0x18, W: []byte{0xd2, 0xe1}, R: []byte{0x1}},
+			{Addr: 0x18, W: []byte{0xd2, 0x69}, R: []byte{0x9}},
+			{Addr: 0x18, W: []byte{0xd2, 0xe1}, R: []byte{0x1}},
+			{Addr: 0x18, W: []byte{0xd2, 0x69}, R: []byte{0x1}},
+		},
+	}
+	d, err := New(&bus, 0x18, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := d.Speed(); s != Standard {
+		t.Fatal(s)
+	}
+	if err := d.SetSpeed(Overdrive); err != nil {
+		t.Fatal(err)
+	}
+	if s := d.Speed(); s != Overdrive {
+		t.Fatal(s)
+	}
+	if d.tSlot != 10*time.Microsecond {
+		t.Fatal(d.tSlot)
+	}
+	if err := d.SetSpeed(Standard); err != nil {
+		t.Fatal(err)
+	}
+	if d.tReset != 2*DefaultOpts.ResetLow || d.tSlot != DefaultOpts.Write0Low+DefaultOpts.Write0Recovery {
+		t.Fatal(d.tReset, d.tSlot)
+	}
+	if err := d.SetSpeed(Speed(2)); err == nil {
+		t.Fatal("expected error for invalid speed")
+	}
+	// The read back value doesn't match.
+	if err := d.SetSpeed(Overdrive); err == nil {
+		t.Fatal("expected error")
+	}
+	if s := d.Speed(); s != Standard {
+		t.Fatal(s)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestChannel(t *testing.T) {
+	// A 1-wire transaction writing one byte.
+	txOps := []i2ctest.IO{
+		{Addr: 0x18, W: []byte{0xb4}},
+		{Addr: 0x18, R: []byte{0x2}},
+		{Addr: 0x18, W: []byte{0xa5, 0xcc}},
+		{Addr: 0x18, R: []byte{0x0}},
+	}
+	ops := []i2ctest.IO{
+		{Addr: 0x18, W: []byte{0xf0}},
+		{Addr: 0x18, W: []byte{0xe1, 0xf0}, R: []byte{0x18}},
+		{Addr: 0x18, W: []byte{0xd2, 0xe1}, R: []byte{0x1}},
+		{Addr: 0x18, W: []byte{0xe1, 0xd2}},
+		{Addr: 0x18, W: []byte{0xc3, 0xf0}},
+		{Addr: 0x18, W: []byte{0xc3, 0xc3}}, // Select channel 3
+	}
+	ops = append(ops, txOps...)
+	ops = append(ops, txOps...) // Channel 3 is still selected
+	ops = append(ops, i2ctest.IO{Addr: 0x18, W: []byte{0xc3, 0xf0}})
+	ops = append(ops, txOps...)
+	bus := i2ctest.Playback{DontPanic: true, Ops: ops}
+	d, err := New(&bus, 0x18, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Channel(8); err == nil {
+		t.Fatal("expected error for channel 8")
+	}
+	ch3, err := d.Channel(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := ch3.String(); s != "DS2482-800{playback(24)}/3" {
+		t.Fatal(s)
+	}
+	ch0, err := d.Channel(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range []onewire.BusCloser{ch3, ch3, ch0} {
+		if err := b.Tx([]byte{0xcc}, nil, onewire.WeakPullup); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ch3.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStrongPullupNext_Stats(t *testing.T) {
+	bus := i2ctest.Playback{
+		DontPanic: true,
+		Ops: []i2ctest.IO{
+			{Addr: 0x18, W: []byte{0xf0}},
+			{Addr: 0x18, W: []byte{0xe1, 0xf0}, R: []byte{0x18}},
+			{Addr: 0x18, W: []byte{0xd2, 0xe1}, R: []byte{0x1}},
+			{Addr: 0x18, W: []byte{0xe1, 0xb4}},
+			{Addr: 0x18, W: []byte{0xc3, 0x6, 0x26, 0x46, 0x66, 0x86}},
+			// No presence pulse.
+			{Addr: 0x18, W: []byte{0xb4}},
+			{Addr: 0x18, R: []byte{0x0}},
+			// Short.
+			{Addr: 0x18, W: []byte{0xb4}},
+			{Addr: 0x18, R: []byte{0x4}},
+			// Skip ROM, Convert T with a forced strong pull-up.
+			{Addr: 0x18, W: []byte{0xb4}},
+			{Addr: 0x18, R: []byte{0x2}},
+			{Addr: 0x18, W: []byte{0xa5, 0xcc}},
+			{Addr: 0x18, R: []byte{0x0}},
+			{Addr: 0x18, W: []byte{0xd2, 0xa5}},
+			{Addr: 0x18, W: []byte{0xa5, 0x44}},
+			{Addr: 0x18, R: []byte{0x0}},
+		},
+	}
+	d, err := New(&bus, 0x18, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Tx([]byte{0xcc, 0x44}, nil, onewire.WeakPullup); err == nil {
+		t.Fatal("expected no presence error")
+	}
+	err = d.Tx([]byte{0xcc, 0x44}, nil, onewire.WeakPullup)
+	if s, ok := err.(onewire.ShortedBusError); !ok || !s.IsShorted() {
+		t.Fatalf("expected shorted bus error, got %v", err)
+	}
+	d.StrongPullupNext(true)
+	if err := d.Tx([]byte{0xcc, 0x44}, nil, onewire.WeakPullup); err != nil {
+		t.Fatal(err)
+	}
+	if s := d.Stats(); s != (Stats{Resets: 3, NoPresence: 1, Shorts: 1}) {
+		t.Fatalf("%+v", s)
+	}
+	d.ResetStats()
+	if s := d.Stats(); s != (Stats{}) {
+		t.Fatalf("%+v", s)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestChannel_DS2482x100(t *testing.T) {
+	bus := i2ctest.Playback{
+		DontPanic: true,
+		Ops: []i2ctest.IO{
+			{Addr: 0x18, W: []byte{0xf0}},
+			{Addr: 0x18, W: []byte{0xe1, 0xf0}, R: []byte{0x18}},
+			{Addr: 0x18, W: []byte{0xd2, 0xe1}, R: []byte{0x1}},
+		},
+	}
+	d, err := New(&bus, 0x18, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Channel(1); err == nil {
+		t.Fatal("expected error for channel 1")
+	}
+	if _, err := d.Channel(0); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func init() {
 	sleep = func(time.Duration) {}
 }
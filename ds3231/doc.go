@@ -0,0 +1,15 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package ds3231 controls a Maxim DS3231 real-time clock via I²C interface.
+//
+// The DS3231 has a temperature compensated crystal oscillator accurate to
+// ±2ppm, two alarms that can assert the INT/SQW pin, a programmable square
+// wave output and a 32kHz output. The clock is kept in UTC, between the
+// years 2000 and 2199.
+//
+// # Datasheet
+//
+// https://www.analog.com/media/en/technical-documentation/data-sheets/DS3231.pdf
+package ds3231
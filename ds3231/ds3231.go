@@ -0,0 +1,423 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ds3231
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/physic"
+)
+
+// Addr is the I²C address of the DS3231.
+const Addr uint16 = 0x68
+
+// AlarmID is one of the two alarms.
+type AlarmID int
+
+// Alarms.
+const (
+	Alarm1 AlarmID = 1
+	Alarm2 AlarmID = 2
+)
+
+// AlarmMatch is the set of fields of the alarm time which must match the
+// current time for the alarm to fire.
+type AlarmMatch int
+
+const (
+	// Every fires every second for Alarm1 and every minute for Alarm2.
+	Every AlarmMatch = iota
+	// MatchSecond fires when the seconds match. Only supported by Alarm1.
+	MatchSecond
+	// MatchMinute fires when the minutes and seconds match. Alarm2 has no
+	// seconds and fires at the start of the minute.
+	MatchMinute
+	// MatchHour fires when the hours, minutes and seconds match.
+	MatchHour
+	// MatchDay fires when the day of month, hours, minutes and seconds
+	// match.
+	MatchDay
+	// MatchWeekday fires when the day of week, hours, minutes and seconds
+	// match.
+	MatchWeekday
+)
+
+// Alarm is the configuration of an alarm.
+type Alarm struct {
+	Match AlarmMatch
+	// Time is the time of the alarm. Only the fields selected by Match are
+	// used, in UTC.
+	Time time.Time
+}
+
+// SquareWave is the frequency of the square wave output on the INT/SQW pin.
+type SquareWave int
+
+// Square wave frequencies.
+const (
+	// SquareWaveOff uses the INT/SQW pin as the alarm interrupt output.
+	SquareWaveOff  SquareWave = -1
+	SquareWave1Hz  SquareWave = 0
+	SquareWave1024 SquareWave = 1
+	SquareWave4096 SquareWave = 2
+	SquareWave8192 SquareWave = 3
+)
+
+// Opts holds the configuration options.
+type Opts struct {
+	// Interrupt is the GPIO connected to the INT/SQW pin. It is optional and
+	// only needed by WaitForAlarm.
+	Interrupt gpio.PinIn
+}
+
+// DefaultOpts is the recommended default options.
+var DefaultOpts = Opts{}
+
+// Dev is a handle to a DS3231.
+type Dev struct {
+	c    i2c.Dev
+	intr gpio.PinIn
+}
+
+// NewI2C returns a handle to a DS3231.
+//
+// The oscillator is enabled if it was stopped. INT/SQW is configured as the
+// alarm interrupt output.
+func NewI2C(b i2c.Bus, opts *Opts) (*Dev, error) {
+	d := &Dev{c: i2c.Dev{Bus: b, Addr: Addr}, intr: opts.Interrupt}
+	if d.intr != nil {
+		// INT/SQW is open drain and active low.
+		if err := d.intr.In(gpio.PullUp, gpio.FallingEdge); err != nil {
+			return nil, fmt.Errorf("ds3231: %v", err)
+		}
+	}
+	ctrl, err := d.readReg(regControl)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.writeReg(regControl, ctrl&^ctrlEOSC|ctrlINTCN); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *Dev) String() string {
+	return fmt.Sprintf("DS3231{%s}", &d.c)
+}
+
+// Now returns the current time of the clock, in UTC.
+func (d *Dev) Now() (time.Time, error) {
+	var r [7]byte
+	if err := d.readRegs(regSeconds, r[:]); err != nil {
+		return time.Time{}, err
+	}
+	year := 2000 + fromBCD(r[6])
+	if r[5]&0x80 != 0 {
+		year += 100
+	}
+	return time.Date(year, time.Month(fromBCD(r[5]&0x1F)), fromBCD(r[4]), fromHour(r[2]), fromBCD(r[1]), fromBCD(r[0]), 0, time.UTC), nil
+}
+
+// Set sets the clock to t, converted to UTC, and clears the oscillator stop
+// flag.
+func (d *Dev) Set(t time.Time) error {
+	t = t.UTC()
+	if t.Year() < 2000 || t.Year() > 2199 {
+		return errors.New("ds3231: year must be between 2000 and 2199")
+	}
+	month := toBCD(int(t.Month()))
+	if t.Year() >= 2100 {
+		month |= 0x80
+	}
+	w := []byte{
+		toBCD(t.Second()),
+		toBCD(t.Minute()),
+		toBCD(t.Hour()),
+		byte(t.Weekday()) + 1,
+		toBCD(t.Day()),
+		month,
+		toBCD(t.Year() % 100),
+	}
+	if err := d.writeRegs(regSeconds, w); err != nil {
+		return err
+	}
+	_, err := d.updateStatus(statusOSF, 0)
+	return err
+}
+
+// LostPower returns true if the oscillator stopped since the clock was last
+// set, for example because the backup battery is depleted. The time is then
+// invalid.
+func (d *Dev) LostPower() (bool, error) {
+	status, err := d.readReg(regStatus)
+	return status&statusOSF != 0, err
+}
+
+// SetAlarm configures an alarm and enables its interrupt on the INT/SQW
+// pin, unless the square wave output is enabled.
+func (d *Dev) SetAlarm(id AlarmID, a Alarm) error {
+	t := a.Time.UTC()
+	// The match masks are the bit 7 of each register, from the seconds.
+	regs := []byte{toBCD(t.Second()), toBCD(t.Minute()), toBCD(t.Hour()), toBCD(t.Day())}
+	if a.Match == MatchWeekday {
+		regs[3] = byte(t.Weekday()) + 1 | 0x40
+	}
+	var masked int
+	switch a.Match {
+	case Every:
+		masked = 4
+	case MatchSecond:
+		masked = 3
+	case MatchMinute:
+		masked = 2
+	case MatchHour:
+		masked = 1
+	case MatchDay, MatchWeekday:
+		masked = 0
+	default:
+		return errors.New("ds3231: invalid alarm match")
+	}
+	for i := 4 - masked; i < 4; i++ {
+		regs[i] |= 0x80
+	}
+	reg, ie := byte(regAlarm1), byte(ctrlA1IE)
+	switch id {
+	case Alarm1:
+	case Alarm2:
+		if a.Match == MatchSecond {
+			return errors.New("ds3231: alarm 2 has no seconds")
+		}
+		// Alarm 2 has no seconds register.
+		regs = regs[1:]
+		reg, ie = regAlarm2, ctrlA2IE
+	default:
+		return errors.New("ds3231: invalid alarm")
+	}
+	if err := d.writeRegs(reg, regs); err != nil {
+		return err
+	}
+	if _, err := d.ClearAlarm(id); err != nil {
+		return err
+	}
+	ctrl, err := d.readReg(regControl)
+	if err != nil {
+		return err
+	}
+	return d.writeReg(regControl, ctrl|ie)
+}
+
+// DisableAlarm disables the interrupt of an alarm.
+func (d *Dev) DisableAlarm(id AlarmID) error {
+	ie, err := alarmBit(id, ctrlA1IE, ctrlA2IE)
+	if err != nil {
+		return err
+	}
+	ctrl, err := d.readReg(regControl)
+	if err != nil {
+		return err
+	}
+	return d.writeReg(regControl, ctrl&^ie)
+}
+
+// ClearAlarm returns true if the alarm fired and clears its flag, which
+// releases the INT/SQW pin.
+func (d *Dev) ClearAlarm(id AlarmID) (bool, error) {
+	f, err := alarmBit(id, statusA1F, statusA2F)
+	if err != nil {
+		return false, err
+	}
+	status, err := d.updateStatus(f, 0)
+	return status&f != 0, err
+}
+
+// WaitForAlarm waits for an alarm to assert the INT/SQW pin, up to timeout,
+// and returns the alarms that fired. Their flags are cleared.
+//
+// It requires the Interrupt pin in Opts.
+func (d *Dev) WaitForAlarm(timeout time.Duration) ([]AlarmID, error) {
+	if d.intr == nil {
+		return nil, errors.New("ds3231: no interrupt pin")
+	}
+	if !d.intr.WaitForEdge(timeout) {
+		return nil, errors.New("ds3231: timeout waiting for alarm")
+	}
+	var fired []AlarmID
+	for _, id := range []AlarmID{Alarm1, Alarm2} {
+		ok, err := d.ClearAlarm(id)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			fired = append(fired, id)
+		}
+	}
+	return fired, nil
+}
+
+// SetSquareWave outputs a square wave on the INT/SQW pin, or restores it as
+// the alarm interrupt output with SquareWaveOff.
+func (d *Dev) SetSquareWave(s SquareWave) error {
+	if s < SquareWaveOff || s > SquareWave8192 {
+		return errors.New("ds3231: invalid square wave frequency")
+	}
+	ctrl, err := d.readReg(regControl)
+	if err != nil {
+		return err
+	}
+	ctrl &^= ctrlINTCN | ctrlRS
+	if s == SquareWaveOff {
+		ctrl |= ctrlINTCN
+	} else {
+		ctrl |= byte(s) << 3
+	}
+	return d.writeReg(regControl, ctrl)
+}
+
+// Set32kHz enables or disables the 32kHz output.
+func (d *Dev) Set32kHz(enable bool) error {
+	var err error
+	if enable {
+		_, err = d.updateStatus(0, statusEN32kHz)
+	} else {
+		_, err = d.updateStatus(statusEN32kHz, 0)
+	}
+	return err
+}
+
+// AgingOffset returns the aging offset, which trims the oscillator
+// frequency. One step is about 0.1ppm, a positive value slows the clock.
+func (d *Dev) AgingOffset() (int8, error) {
+	v, err := d.readReg(regAging)
+	return int8(v), err
+}
+
+// SetAgingOffset sets the aging offset. It takes effect on the next
+// temperature conversion, at most 64 seconds later.
+func (d *Dev) SetAgingOffset(offset int8) error {
+	return d.writeReg(regAging, byte(offset))
+}
+
+// Temperature returns the temperature measured for the oscillator
+// compensation, with a resolution of 0.25°C. It is updated every 64 seconds.
+func (d *Dev) Temperature() (physic.Temperature, error) {
+	var r [2]byte
+	if err := d.readRegs(regTemp, r[:]); err != nil {
+		return 0, err
+	}
+	quarters := int64(int16(uint16(r[0])<<8|uint16(r[1])) >> 6)
+	return physic.ZeroCelsius + physic.Temperature(quarters)*250*physic.MilliKelvin, nil
+}
+
+// Halt implements conn.Resource.
+//
+// The clock keeps running.
+func (d *Dev) Halt() error {
+	return nil
+}
+
+//
+
+// Registers.
+const (
+	regSeconds = 0x00
+	regAlarm1  = 0x07
+	regAlarm2  = 0x0B
+	regControl = 0x0E
+	regStatus  = 0x0F
+	regAging   = 0x10
+	regTemp    = 0x11
+)
+
+// Bits of the control and status registers.
+const (
+	ctrlEOSC  = 0x80
+	ctrlRS    = 0x18
+	ctrlINTCN = 0x04
+	ctrlA2IE  = 0x02
+	ctrlA1IE  = 0x01
+
+	statusOSF     = 0x80
+	statusEN32kHz = 0x08
+	statusA2F     = 0x02
+	statusA1F     = 0x01
+)
+
+func alarmBit(id AlarmID, a1, a2 byte) (byte, error) {
+	switch id {
+	case Alarm1:
+		return a1, nil
+	case Alarm2:
+		return a2, nil
+	default:
+		return 0, errors.New("ds3231: invalid alarm")
+	}
+}
+
+// updateStatus clears and sets bits of the status register and returns its
+// previous value.
+//
+// The alarm flags can only be cleared, so they are written as 1 unless they
+// are to be cleared, to not lose an alarm firing in between.
+func (d *Dev) updateStatus(clear, set byte) (byte, error) {
+	status, err := d.readReg(regStatus)
+	if err != nil {
+		return 0, err
+	}
+	return status, d.writeReg(regStatus, (status|statusA1F|statusA2F|set)&^clear)
+}
+
+func toBCD(v int) byte {
+	return byte(v/10<<4 | v%10)
+}
+
+func fromBCD(b byte) int {
+	return int(b>>4)*10 + int(b&0x0F)
+}
+
+// fromHour decodes the hours register, in 12 or 24 hours mode.
+func fromHour(b byte) int {
+	if b&0x40 == 0 {
+		return fromBCD(b & 0x3F)
+	}
+	h := fromBCD(b & 0x1F)
+	if h == 12 {
+		h = 0
+	}
+	if b&0x20 != 0 {
+		h += 12
+	}
+	return h
+}
+
+func (d *Dev) readRegs(reg byte, r []byte) error {
+	if err := d.c.Tx([]byte{reg}, r); err != nil {
+		return fmt.Errorf("ds3231: %v", err)
+	}
+	return nil
+}
+
+func (d *Dev) readReg(reg byte) (byte, error) {
+	var r [1]byte
+	err := d.readRegs(reg, r[:])
+	return r[0], err
+}
+
+func (d *Dev) writeRegs(reg byte, w []byte) error {
+	if err := d.c.Tx(append([]byte{reg}, w...), nil); err != nil {
+		return fmt.Errorf("ds3231: %v", err)
+	}
+	return nil
+}
+
+func (d *Dev) writeReg(reg, v byte) error {
+	return d.writeRegs(reg, []byte{v})
+}
+
+var _ conn.Resource = &Dev{}
@@ -0,0 +1,248 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ds3231
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpiotest"
+	"periph.io/x/conn/v3/i2c/i2ctest"
+	"periph.io/x/conn/v3/physic"
+)
+
+func initOps() []i2ctest.IO {
+	return []i2ctest.IO{
+		{Addr: 0x68, W: []byte{regControl}, R: []byte{0x98}},
+		{Addr: 0x68, W: []byte{regControl, 0x1C}},
+	}
+}
+
+func TestNow(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: append(initOps(),
+			i2ctest.IO{Addr: 0x68, W: []byte{regSeconds}, R: []byte{0x30, 0x45, 0x13, 0x03, 0x15, 0x86, 0x24}},
+			i2ctest.IO{Addr: 0x68, W: []byte{regSeconds}, R: []byte{0x00, 0x00, 0x72, 0x01, 0x01, 0x01, 0x00}},
+			i2ctest.IO{Addr: 0x68, W: []byte{regStatus}, R: []byte{0x88}},
+		),
+	}
+	d, err := NewI2C(&bus, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := d.String(); s != "DS3231{playback(104)}" {
+		t.Fatal(s)
+	}
+	now, err := d.Now()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2124, 6, 15, 13, 45, 30, 0, time.UTC); !now.Equal(want) {
+		t.Fatal(now)
+	}
+	// 12 hours mode, 12PM.
+	if now, err = d.Now(); err != nil || now.Hour() != 12 {
+		t.Fatal(now, err)
+	}
+	if lost, err := d.LostPower(); err != nil || !lost {
+		t.Fatal(lost, err)
+	}
+	if err := d.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSet(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: append(initOps(),
+			i2ctest.IO{Addr: 0x68, W: []byte{regSeconds, 0x58, 0x59, 0x23, 0x05, 0x29, 0x02, 0x24}},
+			i2ctest.IO{Addr: 0x68, W: []byte{regStatus}, R: []byte{0x80}},
+			i2ctest.IO{Addr: 0x68, W: []byte{regStatus, 0x03}},
+		),
+	}
+	d, err := NewI2C(&bus, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loc := time.FixedZone("UTC+2", 2*3600)
+	if err := d.Set(time.Date(2024, 3, 1, 1, 59, 58, 0, loc)); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Set(time.Date(1999, 1, 1, 0, 0, 0, 0, time.UTC)); err == nil {
+		t.Fatal("expected error on year")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSetAlarm(t *testing.T) {
+	at := time.Date(2024, 3, 1, 7, 30, 15, 0, time.UTC)
+	data := []struct {
+		id AlarmID
+		m  AlarmMatch
+		w  []byte
+	}{
+		{Alarm1, Every, []byte{regAlarm1, 0x95, 0xB0, 0x87, 0x81}},
+		{Alarm1, MatchSecond, []byte{regAlarm1, 0x15, 0xB0, 0x87, 0x81}},
+		{Alarm1, MatchDay, []byte{regAlarm1, 0x15, 0x30, 0x07, 0x01}},
+		{Alarm1, MatchWeekday, []byte{regAlarm1, 0x15, 0x30, 0x07, 0x46}},
+		{Alarm2, Every, []byte{regAlarm2, 0xB0, 0x87, 0x81}},
+		{Alarm2, MatchMinute, []byte{regAlarm2, 0x30, 0x87, 0x81}},
+		{Alarm2, MatchHour, []byte{regAlarm2, 0x30, 0x07, 0x81}},
+	}
+	for i, line := range data {
+		f, ie := byte(statusA1F), byte(ctrlA1IE)
+		if line.id == Alarm2 {
+			f, ie = statusA2F, ctrlA2IE
+		}
+		bus := i2ctest.Playback{
+			Ops: append(initOps(),
+				i2ctest.IO{Addr: 0x68, W: line.w},
+				i2ctest.IO{Addr: 0x68, W: []byte{regStatus}, R: []byte{0x00}},
+				i2ctest.IO{Addr: 0x68, W: []byte{regStatus, 0x03 &^ f}},
+				i2ctest.IO{Addr: 0x68, W: []byte{regControl}, R: []byte{0x1C}},
+				i2ctest.IO{Addr: 0x68, W: []byte{regControl, 0x1C | ie}},
+			),
+		}
+		d, err := NewI2C(&bus, &DefaultOpts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := d.SetAlarm(line.id, Alarm{Match: line.m, Time: at}); err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		if err := bus.Close(); err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+	}
+
+	d := Dev{}
+	if err := d.SetAlarm(Alarm2, Alarm{Match: MatchSecond}); err == nil {
+		t.Fatal("expected error on alarm 2 seconds")
+	}
+	if err := d.SetAlarm(AlarmID(3), Alarm{}); err == nil {
+		t.Fatal("expected error on alarm")
+	}
+	if err := d.SetAlarm(Alarm1, Alarm{Match: AlarmMatch(10)}); err == nil {
+		t.Fatal("expected error on match")
+	}
+}
+
+func TestWaitForAlarm(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: append(initOps(),
+			i2ctest.IO{Addr: 0x68, W: []byte{regStatus}, R: []byte{0x02}},
+			i2ctest.IO{Addr: 0x68, W: []byte{regStatus, 0x02}},
+			i2ctest.IO{Addr: 0x68, W: []byte{regStatus}, R: []byte{0x02}},
+			i2ctest.IO{Addr: 0x68, W: []byte{regStatus, 0x01}},
+			i2ctest.IO{Addr: 0x68, W: []byte{regControl}, R: []byte{0x1F}},
+			i2ctest.IO{Addr: 0x68, W: []byte{regControl, 0x1D}},
+		),
+	}
+	if _, err := (&Dev{}).WaitForAlarm(time.Second); err == nil {
+		t.Fatal("expected error without pin")
+	}
+	intr := &gpiotest.Pin{N: "INT", EdgesChan: make(chan gpio.Level, 1)}
+	d, err := NewI2C(&bus, &Opts{Interrupt: intr})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if intr.P != gpio.PullUp {
+		t.Fatal(intr.P)
+	}
+	if _, err := d.WaitForAlarm(time.Millisecond); err == nil {
+		t.Fatal("expected timeout")
+	}
+	intr.EdgesChan <- gpio.Low
+	fired, err := d.WaitForAlarm(time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(fired, []AlarmID{Alarm2}) {
+		t.Fatal(fired)
+	}
+	if err := d.DisableAlarm(Alarm2); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.DisableAlarm(AlarmID(0)); err == nil {
+		t.Fatal("expected error on alarm")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOutputs(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: append(initOps(),
+			i2ctest.IO{Addr: 0x68, W: []byte{regControl}, R: []byte{0x1D}},
+			i2ctest.IO{Addr: 0x68, W: []byte{regControl, 0x11}},
+			i2ctest.IO{Addr: 0x68, W: []byte{regControl}, R: []byte{0x11}},
+			i2ctest.IO{Addr: 0x68, W: []byte{regControl, 0x05}},
+			i2ctest.IO{Addr: 0x68, W: []byte{regStatus}, R: []byte{0x00}},
+			i2ctest.IO{Addr: 0x68, W: []byte{regStatus, 0x0B}},
+			i2ctest.IO{Addr: 0x68, W: []byte{regStatus}, R: []byte{0x08}},
+			i2ctest.IO{Addr: 0x68, W: []byte{regStatus, 0x03}},
+		),
+	}
+	d, err := NewI2C(&bus, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetSquareWave(SquareWave4096); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetSquareWave(SquareWaveOff); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetSquareWave(SquareWave(4)); err == nil {
+		t.Fatal("expected error on frequency")
+	}
+	if err := d.Set32kHz(true); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Set32kHz(false); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAgingAndTemperature(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: append(initOps(),
+			i2ctest.IO{Addr: 0x68, W: []byte{regAging, 0xFD}},
+			i2ctest.IO{Addr: 0x68, W: []byte{regAging}, R: []byte{0xFD}},
+			i2ctest.IO{Addr: 0x68, W: []byte{regTemp}, R: []byte{0x19, 0x40}},
+			i2ctest.IO{Addr: 0x68, W: []byte{regTemp}, R: []byte{0xF5, 0xC0}},
+		),
+	}
+	d, err := NewI2C(&bus, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetAgingOffset(-3); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := d.AgingOffset(); err != nil || v != -3 {
+		t.Fatal(v, err)
+	}
+	if temp, err := d.Temperature(); err != nil || temp != physic.ZeroCelsius+25250*physic.MilliKelvin {
+		t.Fatal(temp, err)
+	}
+	if temp, err := d.Temperature(); err != nil || temp != physic.ZeroCelsius-10250*physic.MilliKelvin {
+		t.Fatal(temp, err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,16 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package envmux combines multiple environmental sensors into a single
+// physic.SenseEnv.
+//
+// Each measured field, temperature, pressure, humidity and CO2, is taken
+// from the first source measuring it, in the order the sources were given
+// to New or as set by SetPriority. When a source fails, the field is taken
+// from the next one.
+//
+// The merged values can be fed back to sensors needing them for their own
+// compensation, for example the pressure of a BMP280 to a SCD4x or the
+// temperature and humidity of a BME280 to a CCS811.
+package envmux
@@ -0,0 +1,423 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package envmux
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/devices/v3/scd4x"
+)
+
+// Field is a set of measurements.
+type Field uint8
+
+// Measurements.
+const (
+	Temperature Field = 1 << iota
+	Pressure
+	Humidity
+	CO2
+)
+
+func (f Field) String() string {
+	var s []string
+	for i, n := range []string{"Temperature", "Pressure", "Humidity", "CO2"} {
+		if f&(1<<uint(i)) != 0 {
+			s = append(s, n)
+		}
+	}
+	if len(s) == 0 {
+		return "None"
+	}
+	return strings.Join(s, "|")
+}
+
+// fields is the list of individual fields.
+var fields = [...]Field{Temperature, Pressure, Humidity, CO2}
+
+// Env is the merged measurement, including the CO2 concentration.
+type Env struct {
+	physic.Env
+	// CO2 is the CO2 concentration in ppm.
+	CO2 int
+}
+
+// Source is a sensor combined by a Mux.
+type Source interface {
+	conn.Resource
+	// Sense reads the sensor. Only the fields returned by Fields are used.
+	Sense(e *Env) error
+	// Precision returns the precision of the fields returned by Fields.
+	Precision(e *Env)
+	// Fields returns the fields measured by the sensor.
+	Fields() Field
+}
+
+// FromSenseEnv returns a Source reading a physic.SenseEnv.
+//
+// The fields measured are the ones with a non-zero precision.
+func FromSenseEnv(s physic.SenseEnv) Source {
+	e := physic.Env{}
+	s.Precision(&e)
+	var f Field
+	if e.Temperature != 0 {
+		f |= Temperature
+	}
+	if e.Pressure != 0 {
+		f |= Pressure
+	}
+	if e.Humidity != 0 {
+		f |= Humidity
+	}
+	return &senseEnv{s: s, f: f}
+}
+
+// FromSCD4x returns a Source reading a SCD4x CO2 sensor.
+func FromSCD4x(d *scd4x.Dev) Source {
+	return &scd4xSource{d: d}
+}
+
+// PressureCompensator is implemented by sensors compensating their
+// measurement with the ambient pressure, like scd4x.Dev.
+type PressureCompensator interface {
+	SetAmbientPressure(p physic.Pressure) error
+}
+
+// EnvironmentCompensator is implemented by sensors compensating their
+// measurement with the temperature and the humidity, like ccs811.Dev.
+type EnvironmentCompensator interface {
+	SetEnvironment(t physic.Temperature, h physic.RelativeHumidity) error
+}
+
+// Mux combines multiple sources in a single physic.SenseEnv.
+type Mux struct {
+	sources []Source
+	// order is the priority of the sources for each field.
+	order [len(fields)][]Source
+	press []PressureCompensator
+	env   []EnvironmentCompensator
+
+	mu   sync.Mutex
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New returns a Mux combining the sources, in decreasing priority.
+func New(sources ...Source) (*Mux, error) {
+	if len(sources) == 0 {
+		return nil, errors.New("envmux: no source")
+	}
+	m := &Mux{sources: sources}
+	for i := range m.order {
+		m.order[i] = sources
+	}
+	return m, nil
+}
+
+func (m *Mux) String() string {
+	s := make([]string, len(m.sources))
+	for i, src := range m.sources {
+		s[i] = src.String()
+	}
+	return "envmux{" + strings.Join(s, ", ") + "}"
+}
+
+// SetPriority sets the priority of the sources for the given fields. The
+// sources not listed follow, in their original order.
+func (m *Mux) SetPriority(f Field, sources ...Source) error {
+	for _, s := range sources {
+		if !m.has(s) {
+			return fmt.Errorf("envmux: unknown source %s", s)
+		}
+	}
+	order := append([]Source{}, sources...)
+	for _, s := range m.sources {
+		if !contains(sources, s) {
+			order = append(order, s)
+		}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, field := range fields {
+		if f&field != 0 {
+			m.order[i] = order
+		}
+	}
+	return nil
+}
+
+// CompensatePressure feeds the merged pressure to c after each measurement.
+func (m *Mux) CompensatePressure(c PressureCompensator) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.press = append(m.press, c)
+}
+
+// CompensateEnvironment feeds the merged temperature and humidity to c after
+// each measurement.
+func (m *Mux) CompensateEnvironment(c EnvironmentCompensator) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.env = append(m.env, c)
+}
+
+// Sense implements physic.SenseEnv.
+func (m *Mux) Sense(e *physic.Env) error {
+	all := Env{}
+	err := m.SenseAll(&all)
+	*e = all.Env
+	return err
+}
+
+// SenseAll reads all the sources and merges their measurements, including
+// the CO2 concentration.
+//
+// It returns an error if a field measured by the sources could not be read
+// from any of them. The fields that could be read are still set.
+func (m *Mux) SenseAll(e *Env) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.sense(e)
+}
+
+// SenseContinuous implements physic.SenseEnv.
+//
+// The sources are read at each interval, failures are logged.
+func (m *Mux) SenseContinuous(interval time.Duration) (<-chan physic.Env, error) {
+	m.stopContinuous()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sensing := make(chan physic.Env)
+	stop := make(chan struct{})
+	m.stop = stop
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		defer close(sensing)
+		m.sensingContinuous(interval, sensing, stop)
+	}()
+	return sensing, nil
+}
+
+// Precision implements physic.SenseEnv.
+//
+// It returns the precision of the source with the highest priority for each
+// field.
+func (m *Mux) Precision(e *physic.Env) {
+	all := Env{}
+	m.PrecisionAll(&all)
+	*e = all.Env
+}
+
+// PrecisionAll is Precision including the CO2 concentration.
+func (m *Mux) PrecisionAll(e *Env) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, f := range fields {
+		for _, s := range m.order[i] {
+			if s.Fields()&f != 0 {
+				p := Env{}
+				s.Precision(&p)
+				copyField(e, &p, f)
+				break
+			}
+		}
+	}
+}
+
+// Halt stops continuous sensing and halts all the sources.
+//
+// Halt implements conn.Resource.
+func (m *Mux) Halt() error {
+	m.stopContinuous()
+	var errs []error
+	for _, s := range m.sources {
+		if err := s.Halt(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+//
+
+// stopContinuous stops continuous sensing, if running. The lock is not held
+// while waiting as the goroutine needs it to finish a reading.
+func (m *Mux) stopContinuous() {
+	m.mu.Lock()
+	if m.stop != nil {
+		close(m.stop)
+		m.stop = nil
+	}
+	m.mu.Unlock()
+	m.wg.Wait()
+}
+
+func (m *Mux) has(s Source) bool {
+	return contains(m.sources, s)
+}
+
+func contains(l []Source, s Source) bool {
+	for _, x := range l {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+func copyField(dst, src *Env, f Field) {
+	switch f {
+	case Temperature:
+		dst.Temperature = src.Temperature
+	case Pressure:
+		dst.Pressure = src.Pressure
+	case Humidity:
+		dst.Humidity = src.Humidity
+	case CO2:
+		dst.CO2 = src.CO2
+	}
+}
+
+func (m *Mux) sense(e *Env) error {
+	var errs []error
+	envs := make(map[Source]*Env, len(m.sources))
+	var measured Field
+	for _, s := range m.sources {
+		measured |= s.Fields()
+		r := &Env{}
+		if err := s.Sense(r); err != nil {
+			errs = append(errs, fmt.Errorf("envmux: %s: %w", s, err))
+			continue
+		}
+		envs[s] = r
+	}
+	var got Field
+	for i, f := range fields {
+		for _, s := range m.order[i] {
+			if r := envs[s]; r != nil && s.Fields()&f != 0 {
+				copyField(e, r, f)
+				got |= f
+				break
+			}
+		}
+	}
+	if missing := measured &^ got; missing != 0 {
+		errs = append(errs, fmt.Errorf("envmux: no source could read %s", missing))
+	}
+	if got&Pressure != 0 {
+		for _, c := range m.press {
+			if err := c.SetAmbientPressure(e.Pressure); err != nil {
+				errs = append(errs, fmt.Errorf("envmux: failed to compensate pressure: %w", err))
+			}
+		}
+	}
+	if got&(Temperature|Humidity) == Temperature|Humidity {
+		for _, c := range m.env {
+			if err := c.SetEnvironment(e.Temperature, e.Humidity); err != nil {
+				errs = append(errs, fmt.Errorf("envmux: failed to compensate environment: %w", err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m *Mux) sensingContinuous(interval time.Duration, sensing chan<- physic.Env, stop <-chan struct{}) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		e := Env{}
+		m.mu.Lock()
+		err := m.sense(&e)
+		m.mu.Unlock()
+		if err != nil {
+			log.Printf("%s: failed to sense: %v", m, err)
+		}
+		select {
+		case sensing <- e.Env:
+		case <-stop:
+			return
+		}
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+		}
+	}
+}
+
+type senseEnv struct {
+	s physic.SenseEnv
+	f Field
+}
+
+func (s *senseEnv) String() string {
+	return s.s.String()
+}
+
+func (s *senseEnv) Halt() error {
+	return s.s.Halt()
+}
+
+func (s *senseEnv) Sense(e *Env) error {
+	return s.s.Sense(&e.Env)
+}
+
+func (s *senseEnv) Precision(e *Env) {
+	s.s.Precision(&e.Env)
+}
+
+func (s *senseEnv) Fields() Field {
+	return s.f
+}
+
+type scd4xSource struct {
+	d *scd4x.Dev
+}
+
+func (s *scd4xSource) String() string {
+	return s.d.String()
+}
+
+func (s *scd4xSource) Halt() error {
+	return s.d.Halt()
+}
+
+func (s *scd4xSource) Sense(e *Env) error {
+	r := scd4x.Env{}
+	if err := s.d.Sense(&r); err != nil {
+		return err
+	}
+	e.Env = r.Env
+	e.CO2 = int(r.CO2)
+	return nil
+}
+
+func (s *scd4xSource) Precision(e *Env) {
+	r := scd4x.Env{}
+	s.d.Precision(&r)
+	e.Env = r.Env
+	e.CO2 = int(r.CO2)
+}
+
+func (s *scd4xSource) Fields() Field {
+	return Temperature | Humidity | CO2
+}
+
+// SetAmbientPressure forwards to the SCD4x so the source can be given to
+// CompensatePressure.
+func (s *scd4xSource) SetAmbientPressure(p physic.Pressure) error {
+	return s.d.SetAmbientPressure(p)
+}
+
+var _ physic.SenseEnv = &Mux{}
+var _ PressureCompensator = &scd4x.Dev{}
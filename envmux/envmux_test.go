@@ -0,0 +1,186 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package envmux
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3/physic"
+)
+
+type fakeEnv struct {
+	name   string
+	e      physic.Env
+	err    error
+	halted bool
+}
+
+func (f *fakeEnv) String() string { return f.name }
+
+func (f *fakeEnv) Halt() error {
+	f.halted = true
+	return nil
+}
+
+func (f *fakeEnv) Sense(e *physic.Env) error {
+	if f.err != nil {
+		return f.err
+	}
+	*e = f.e
+	return nil
+}
+
+func (f *fakeEnv) SenseContinuous(time.Duration) (<-chan physic.Env, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *fakeEnv) Precision(e *physic.Env) {
+	e.Temperature = 0
+	e.Pressure = 0
+	e.Humidity = 0
+	if f.e.Temperature != 0 {
+		e.Temperature = physic.MilliKelvin
+	}
+	if f.e.Pressure != 0 {
+		e.Pressure = physic.Pascal
+	}
+	if f.e.Humidity != 0 {
+		e.Humidity = physic.MilliRH
+	}
+}
+
+type fakeCO2 struct {
+	fakeEnv
+	co2      int
+	pressure physic.Pressure
+}
+
+func (f *fakeCO2) Sense(e *Env) error {
+	if err := f.fakeEnv.Sense(&e.Env); err != nil {
+		return err
+	}
+	e.CO2 = f.co2
+	return nil
+}
+
+func (f *fakeCO2) Precision(e *Env) {
+	f.fakeEnv.Precision(&e.Env)
+	e.CO2 = 1
+}
+
+func (f *fakeCO2) Fields() Field {
+	return Temperature | Humidity | CO2
+}
+
+func (f *fakeCO2) SetAmbientPressure(p physic.Pressure) error {
+	f.pressure = p
+	return nil
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestField_String(t *testing.T) {
+	if s := (Temperature | CO2).String(); s != "Temperature|CO2" {
+		t.Fatal(s)
+	}
+	if s := Field(0).String(); s != "None" {
+		t.Fatal(s)
+	}
+}
+
+func TestSense(t *testing.T) {
+	baro := &fakeEnv{name: "baro", e: physic.Env{Temperature: 300 * physic.Kelvin, Pressure: 100 * physic.KiloPascal}}
+	co2 := &fakeCO2{
+		fakeEnv: fakeEnv{name: "co2", e: physic.Env{Temperature: 301 * physic.Kelvin, Humidity: 40 * physic.PercentRH}},
+		co2:     600,
+	}
+	b := FromSenseEnv(baro)
+	if f := b.Fields(); f != Temperature|Pressure {
+		t.Fatal(f)
+	}
+	m, err := New(b, co2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := m.String(); s != "envmux{baro, co2}" {
+		t.Fatal(s)
+	}
+	m.CompensatePressure(co2)
+
+	e := Env{}
+	if err := m.SenseAll(&e); err != nil {
+		t.Fatal(err)
+	}
+	want := Env{Env: physic.Env{Temperature: 300 * physic.Kelvin, Pressure: 100 * physic.KiloPascal, Humidity: 40 * physic.PercentRH}, CO2: 600}
+	if e != want {
+		t.Fatal(e)
+	}
+	if co2.pressure != 100*physic.KiloPascal {
+		t.Fatal(co2.pressure)
+	}
+
+	if err := m.SetPriority(Temperature, co2); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.SetPriority(Temperature, &fakeCO2{}); err == nil {
+		t.Fatal("expected error on unknown source")
+	}
+	pe := physic.Env{}
+	if err := m.Sense(&pe); err != nil {
+		t.Fatal(err)
+	}
+	if pe.Temperature != 301*physic.Kelvin {
+		t.Fatal(pe)
+	}
+	p := Env{}
+	m.PrecisionAll(&p)
+	if p.Temperature != physic.MilliKelvin || p.Pressure != physic.Pascal || p.Humidity != physic.MilliRH || p.CO2 != 1 {
+		t.Fatal(p)
+	}
+
+	// The temperature falls back to the barometer when the CO2 sensor fails.
+	co2.err = errors.New("fail")
+	e = Env{}
+	if err := m.SenseAll(&e); err == nil {
+		t.Fatal("expected error on missing humidity")
+	}
+	if e.Temperature != 300*physic.Kelvin || e.Pressure != 100*physic.KiloPascal || e.Humidity != 0 {
+		t.Fatal(e)
+	}
+
+	if err := m.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	if !baro.halted || !co2.halted {
+		t.Fatal("sources not halted")
+	}
+}
+
+func TestSenseContinuous(t *testing.T) {
+	baro := &fakeEnv{name: "baro", e: physic.Env{Pressure: 100 * physic.KiloPascal}}
+	m, err := New(FromSenseEnv(baro))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := m.SenseContinuous(time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := <-c; e.Pressure != 100*physic.KiloPascal {
+		t.Fatal(e)
+	}
+	if err := m.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := <-c; ok {
+		t.Fatal("channel not closed")
+	}
+}
@@ -6,8 +6,12 @@ package ep0099
 
 import (
 	"errors"
+	"fmt"
+	"sync"
+	"time"
 
 	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/devices/v3/relay"
 )
 
 var errInvalidAddress = errors.New("invalid EP-0099 address")
@@ -21,8 +25,17 @@ const (
 )
 
 type Dev struct {
+	mu    sync.Mutex
 	i2c   i2c.Dev
 	state [4]State
+
+	// watchdog turns all relays off when it fires, nil when disabled. It is
+	// kept once fired, so the next change of a relay arms it again.
+	watchdog *time.Timer
+	timeout  time.Duration
+	// deadline is when the watchdog is due, to ignore a timer firing while
+	// being refreshed or replaced.
+	deadline time.Time
 }
 
 func New(bus i2c.Bus, address uint16) (*Dev, error) {
@@ -41,37 +54,107 @@ func New(bus i2c.Bus, address uint16) (*Dev, error) {
 	return d, nil
 }
 
+func (d *Dev) String() string {
+	return fmt.Sprintf("EP-0099{%s}", &d.i2c)
+}
+
+// Halt stops the watchdog, if any, and turns all relays off.
 func (d *Dev) Halt() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.stopWatchdog()
 	return d.reset()
 }
 
 func (d *Dev) On(channel uint8) error {
-	if !isValidChannel(channel) {
-		return errInvalidChannel
-	}
-
-	_, err := d.i2c.Write([]byte{channel, byte(StateOn)})
-	d.state[channel-1] = StateOn
-	return err
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.set(channel, StateOn)
 }
 
 func (d *Dev) Off(channel uint8) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.set(channel, StateOff)
+}
+
+// Toggle turns the relay on if it is off, and off if it is on.
+func (d *Dev) Toggle(channel uint8) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
 	if !isValidChannel(channel) {
 		return errInvalidChannel
 	}
-
-	_, err := d.i2c.Write([]byte{channel, byte(StateOff)})
-	d.state[channel-1] = StateOff
-	return err
+	if d.state[channel-1] == StateOff {
+		return d.set(channel, StateOn)
+	}
+	return d.set(channel, StateOff)
 }
 
+// State returns the last state set for the relay.
 func (d *Dev) State(channel uint8) (State, error) {
 	if !isValidChannel(channel) {
 		return 0, errInvalidChannel
 	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
 	return d.state[channel-1], nil
 }
 
+// IsOn returns true if the last state set for the relay is on. It implements
+// relay.Bank.
+func (d *Dev) IsOn(channel uint8) (bool, error) {
+	s, err := d.State(channel)
+	return s == StateOn, err
+}
+
+// ReadState reads back the state of the relay from the board.
+func (d *Dev) ReadState(channel uint8) (State, error) {
+	if !isValidChannel(channel) {
+		return 0, errInvalidChannel
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var b [1]byte
+	if err := d.i2c.Tx([]byte{channel}, b[:]); err != nil {
+		return 0, err
+	}
+	s := StateOff
+	if b[0] != 0 {
+		s = StateOn
+	}
+	d.state[channel-1] = s
+	return s, nil
+}
+
+// SetWatchdog turns all relays off if neither Refresh, On, Off nor Toggle is
+// called for timeout. This protects loads like heaters or pumps in case the
+// program hangs or crashes without calling Halt.
+//
+// The watchdog stays enabled once fired: the next call to Refresh, On, Off or
+// Toggle arms it again. A timeout of 0 disables the watchdog.
+func (d *Dev) SetWatchdog(timeout time.Duration) error {
+	if timeout < 0 {
+		return errors.New("invalid EP-0099 watchdog timeout")
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.stopWatchdog()
+	d.timeout = timeout
+	if timeout != 0 {
+		d.deadline = time.Now().Add(timeout)
+		d.watchdog = time.AfterFunc(timeout, d.expire)
+	}
+	return nil
+}
+
+// Refresh restarts the watchdog set with SetWatchdog.
+func (d *Dev) Refresh() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.refresh()
+}
+
 func (d *Dev) AvailableChannels() []uint8 {
 	return []uint8{0x01, 0x02, 0x03, 0x04}
 }
@@ -83,9 +166,56 @@ func (s State) String() string {
 	return "on"
 }
 
+// set changes the state of a relay. The lock must be held.
+func (d *Dev) set(channel uint8, state State) error {
+	if !isValidChannel(channel) {
+		return errInvalidChannel
+	}
+
+	err := d.write(channel, state)
+	d.refresh()
+	return err
+}
+
+// write sends the state of a relay. The lock must be held.
+func (d *Dev) write(channel uint8, state State) error {
+	_, err := d.i2c.Write([]byte{channel, byte(state)})
+	d.state[channel-1] = state
+	return err
+}
+
+// refresh restarts the watchdog, if any. The lock must be held.
+func (d *Dev) refresh() {
+	if d.watchdog != nil {
+		d.deadline = time.Now().Add(d.timeout)
+		d.watchdog.Reset(d.timeout)
+	}
+}
+
+// stopWatchdog disables the watchdog. The lock must be held.
+func (d *Dev) stopWatchdog() {
+	if d.watchdog != nil {
+		d.watchdog.Stop()
+		d.watchdog = nil
+	}
+}
+
+// expire is called when the watchdog fires.
+func (d *Dev) expire() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.watchdog == nil || time.Now().Before(d.deadline) {
+		// Stopped, refreshed or replaced while firing.
+		return
+	}
+	_ = d.reset()
+}
+
+// reset turns all relays off, without arming the watchdog. The lock must be
+// held.
 func (d *Dev) reset() error {
 	for _, channel := range d.AvailableChannels() {
-		err := d.Off(channel)
+		err := d.write(channel, StateOff)
 		if err != nil {
 			return err
 		}
@@ -108,3 +238,5 @@ func isValidAddress(address uint16) error {
 func isValidChannel(channel uint8) bool {
 	return channel >= 1 && channel <= 4
 }
+
+var _ relay.Bank = &Dev{}
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"periph.io/x/conn/v3/i2c/i2ctest"
 )
@@ -74,6 +75,9 @@ func TestOn(t *testing.T) {
 
 	checkBusHasWrite(t, bus, []byte{3, byte(StateOn)})
 	checkChannelState(t, dev, 3, StateOn)
+	if on, err := dev.IsOn(3); err != nil || !on {
+		t.Fatal("Channel 3 should be on, got ", on, err)
+	}
 }
 
 func TestOff(t *testing.T) {
@@ -90,6 +94,92 @@ func TestOff(t *testing.T) {
 	checkChannelState(t, dev, 4, StateOff)
 }
 
+func TestToggle(t *testing.T) {
+	bus := initTestBus()
+	dev, _ := New(bus, testDefaultValidAddress)
+
+	if err := dev.Toggle(2); err != nil {
+		t.Fatal("Should not return error, got ", err)
+	}
+	checkChannelState(t, dev, 2, StateOn)
+
+	bus.Ops = []i2ctest.IO{}
+	if err := dev.Toggle(2); err != nil {
+		t.Fatal("Should not return error, got ", err)
+	}
+	checkBusHasWrite(t, bus, []byte{2, byte(StateOff)})
+	checkChannelState(t, dev, 2, StateOff)
+}
+
+func TestReadState(t *testing.T) {
+	bus := &i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: testDefaultValidAddress, W: []byte{1, 0}},
+			{Addr: testDefaultValidAddress, W: []byte{2, 0}},
+			{Addr: testDefaultValidAddress, W: []byte{3, 0}},
+			{Addr: testDefaultValidAddress, W: []byte{4, 0}},
+			{Addr: testDefaultValidAddress, W: []byte{3}, R: []byte{0xff}},
+		},
+	}
+	dev, err := New(bus, testDefaultValidAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, err := dev.ReadState(3); err != nil || s != StateOn {
+		t.Fatal("Channel 3 should be read as on, got ", s, err)
+	}
+	checkChannelState(t, dev, 3, StateOn)
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatchdog(t *testing.T) {
+	bus := initTestBus()
+	dev, _ := New(bus, testDefaultValidAddress)
+
+	if err := dev.SetWatchdog(-1); err == nil {
+		t.Fatal("SetWatchdog should return error for a negative timeout")
+	}
+	if err := dev.SetWatchdog(10 * time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	// The watchdog is armed again after firing.
+	for _, channel := range []uint8{1, 2} {
+		if err := dev.On(channel); err != nil {
+			t.Fatal(err)
+		}
+		for {
+			if s, _ := dev.State(channel); s == StateOff {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	// It doesn't fire again until a relay is changed.
+	bus.Lock()
+	n := len(bus.Ops)
+	bus.Unlock()
+	time.Sleep(30 * time.Millisecond)
+	bus.Lock()
+	fired := len(bus.Ops) != n
+	bus.Unlock()
+	if fired {
+		t.Fatal("watchdog fired without any change")
+	}
+
+	// Disabled watchdog.
+	if err := dev.SetWatchdog(time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.SetWatchdog(0); err != nil {
+		t.Fatal(err)
+	}
+	if dev.watchdog != nil {
+		t.Fatal("watchdog should be disabled")
+	}
+}
+
 func TestReturnErrorForInvalidChannel(t *testing.T) {
 	bus := initTestBus()
 	dev, _ := New(bus, testDefaultValidAddress)
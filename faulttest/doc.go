@@ -0,0 +1,14 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package faulttest injects deterministic failures in I²C and SPI
+// transactions to test the error recovery paths of device drivers.
+//
+// The wrappers are meant to be used around i2ctest.Playback and
+// spitest.Playback. A transaction failing with NAK or Timeout is not forwarded
+// to the wrapped bus, so the playback doesn't record it and the driver's retry
+// is matched against the next recorded operation. A Corrupt transaction is
+// forwarded and the data read back is altered, which is useful to exercise CRC
+// verification.
+package faulttest
@@ -0,0 +1,247 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package faulttest
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/spi"
+)
+
+// Kind is the kind of failure to inject.
+type Kind int
+
+const (
+	// NAK fails the transaction as if the device didn't acknowledge it.
+	NAK Kind = iota
+	// Timeout fails the transaction with an error implementing
+	// Timeout() bool.
+	Timeout
+	// Corrupt flips the least significant bit of the last byte read, which
+	// is usually the CRC.
+	Corrupt
+)
+
+func (k Kind) String() string {
+	switch k {
+	case NAK:
+		return "NAK"
+	case Timeout:
+		return "Timeout"
+	case Corrupt:
+		return "Corrupt"
+	default:
+		return fmt.Sprintf("Kind(%d)", int(k))
+	}
+}
+
+// Fault is a failure injected on a specific transaction.
+type Fault struct {
+	// Tx is the zero based index of the transaction to fail, counting all
+	// the transactions going through the wrapper including failed ones.
+	Tx   int
+	Kind Kind
+}
+
+// ErrNAK is returned by transactions failed with NAK.
+var ErrNAK = errors.New("faulttest: injected NAK")
+
+// ErrTimeout is returned by transactions failed with Timeout.
+var ErrTimeout error = timeoutError("faulttest: injected timeout")
+
+type timeoutError string
+
+func (e timeoutError) Error() string { return string(e) }
+func (e timeoutError) Timeout() bool { return true }
+
+// injector holds the state shared by the wrappers.
+type injector struct {
+	mu     sync.Mutex
+	faults []Fault
+	count  int
+}
+
+// next returns the fault to inject for the next transaction, if any.
+func (i *injector) next() (Kind, bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	n := i.count
+	i.count++
+	for _, f := range i.faults {
+		if f.Tx == n {
+			return f.Kind, true
+		}
+	}
+	return 0, false
+}
+
+// do runs tx with the fault injected, if any.
+func (i *injector) do(r []byte, tx func() error) error {
+	k, ok := i.next()
+	if !ok {
+		return tx()
+	}
+	switch k {
+	case NAK:
+		return ErrNAK
+	case Timeout:
+		return ErrTimeout
+	}
+	if err := tx(); err != nil {
+		return err
+	}
+	if len(r) != 0 {
+		r[len(r)-1] ^= 1
+	}
+	return nil
+}
+
+// I2C implements i2c.BusCloser and injects failures in the transactions
+// forwarded to Bus.
+type I2C struct {
+	Bus i2c.Bus
+
+	injector
+}
+
+// NewI2C returns a wrapper injecting faults on the transactions to bus.
+func NewI2C(bus i2c.Bus, faults ...Fault) *I2C {
+	return &I2C{Bus: bus, injector: injector{faults: faults}}
+}
+
+// Count returns the number of transactions attempted, including the failed
+// ones.
+func (b *I2C) Count() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.count
+}
+
+func (b *I2C) String() string {
+	return "faulttest(" + b.Bus.String() + ")"
+}
+
+// Close implements i2c.BusCloser.
+//
+// It closes the wrapped bus if it implements i2c.BusCloser, which verifies
+// that all the operations of a Playback were consumed.
+func (b *I2C) Close() error {
+	if c, ok := b.Bus.(i2c.BusCloser); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// Tx implements i2c.Bus.
+func (b *I2C) Tx(addr uint16, w, r []byte) error {
+	return b.do(r, func() error { return b.Bus.Tx(addr, w, r) })
+}
+
+// SetSpeed implements i2c.Bus.
+func (b *I2C) SetSpeed(f physic.Frequency) error {
+	return b.Bus.SetSpeed(f)
+}
+
+// SCL implements i2c.Pins.
+func (b *I2C) SCL() gpio.PinIO {
+	if p, ok := b.Bus.(i2c.Pins); ok {
+		return p.SCL()
+	}
+	return gpio.INVALID
+}
+
+// SDA implements i2c.Pins.
+func (b *I2C) SDA() gpio.PinIO {
+	if p, ok := b.Bus.(i2c.Pins); ok {
+		return p.SDA()
+	}
+	return gpio.INVALID
+}
+
+// SPI implements spi.PortCloser and injects failures in the transactions of
+// the connections returned by Connect.
+type SPI struct {
+	Port spi.Port
+
+	injector
+}
+
+// NewSPI returns a wrapper injecting faults on the transactions to port.
+func NewSPI(port spi.Port, faults ...Fault) *SPI {
+	return &SPI{Port: port, injector: injector{faults: faults}}
+}
+
+// Count returns the number of transactions attempted, including the failed
+// ones.
+func (s *SPI) Count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.count
+}
+
+func (s *SPI) String() string {
+	return "faulttest(" + s.Port.String() + ")"
+}
+
+// Close implements spi.PortCloser.
+func (s *SPI) Close() error {
+	if c, ok := s.Port.(spi.PortCloser); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// Connect implements spi.Port.
+func (s *SPI) Connect(f physic.Frequency, mode spi.Mode, bits int) (spi.Conn, error) {
+	c, err := s.Port.Connect(f, mode, bits)
+	if err != nil {
+		return nil, err
+	}
+	return &spiConn{c: c, s: s}, nil
+}
+
+// LimitSpeed implements spi.PortCloser.
+func (s *SPI) LimitSpeed(f physic.Frequency) error {
+	if c, ok := s.Port.(spi.PortCloser); ok {
+		return c.LimitSpeed(f)
+	}
+	return nil
+}
+
+type spiConn struct {
+	c spi.Conn
+	s *SPI
+}
+
+func (c *spiConn) String() string {
+	return c.s.String()
+}
+
+func (c *spiConn) Duplex() conn.Duplex {
+	return c.c.Duplex()
+}
+
+func (c *spiConn) Tx(w, r []byte) error {
+	return c.s.do(r, func() error { return c.c.Tx(w, r) })
+}
+
+// TxPackets counts as a single transaction.
+func (c *spiConn) TxPackets(p []spi.Packet) error {
+	var r []byte
+	if len(p) != 0 {
+		r = p[len(p)-1].R
+	}
+	return c.s.do(r, func() error { return c.c.TxPackets(p) })
+}
+
+var _ i2c.BusCloser = &I2C{}
+var _ i2c.Pins = &I2C{}
+var _ spi.PortCloser = &SPI{}
@@ -0,0 +1,86 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package faulttest
+
+import (
+	"bytes"
+	"testing"
+
+	"periph.io/x/conn/v3/conntest"
+	"periph.io/x/conn/v3/i2c/i2ctest"
+	"periph.io/x/conn/v3/spi"
+	"periph.io/x/conn/v3/spi/spitest"
+)
+
+func TestI2C(t *testing.T) {
+	p := &i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x62, W: []byte{0x01}, R: []byte{0xaa, 0x55}},
+			{Addr: 0x62, W: []byte{0x01}, R: []byte{0xaa, 0x55}},
+			{Addr: 0x62, W: []byte{0x01}, R: []byte{0xaa, 0x55}},
+		},
+	}
+	b := NewI2C(p, Fault{Tx: 0, Kind: NAK}, Fault{Tx: 2, Kind: Timeout}, Fault{Tx: 3, Kind: Corrupt})
+	r := make([]byte, 2)
+	if err := b.Tx(0x62, []byte{0x01}, r); err != ErrNAK {
+		t.Fatalf("expected NAK, got %v", err)
+	}
+	if err := b.Tx(0x62, []byte{0x01}, r); err != nil {
+		t.Fatal(err)
+	}
+	err := b.Tx(0x62, []byte{0x01}, r)
+	if to, ok := err.(interface{ Timeout() bool }); !ok || !to.Timeout() {
+		t.Fatalf("expected timeout, got %v", err)
+	}
+	if err := b.Tx(0x62, []byte{0x01}, r); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(r, []byte{0xaa, 0x54}) {
+		t.Fatalf("expected corrupted read, got %#v", r)
+	}
+	if err := b.Tx(0x62, []byte{0x01}, r); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(r, []byte{0xaa, 0x55}) {
+		t.Fatalf("unexpected read %#v", r)
+	}
+	if b.Count() != 5 {
+		t.Fatalf("Count() = %d", b.Count())
+	}
+	if s := b.String(); s != "faulttest(playback)" {
+		t.Fatal(s)
+	}
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSPI(t *testing.T) {
+	p := &spitest.Playback{
+		Playback: conntest.Playback{
+			Ops: []conntest.IO{
+				{W: []byte{0x9f, 0x00}, R: []byte{0x00, 0x42}},
+			},
+		},
+	}
+	s := NewSPI(p, Fault{Tx: 0, Kind: NAK}, Fault{Tx: 1, Kind: Corrupt})
+	c, err := s.Connect(0, spi.Mode0, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := make([]byte, 2)
+	if err := c.Tx([]byte{0x9f, 0x00}, r); err != ErrNAK {
+		t.Fatalf("expected NAK, got %v", err)
+	}
+	if err := c.Tx([]byte{0x9f, 0x00}, r); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(r, []byte{0x00, 0x43}) {
+		t.Fatalf("expected corrupted read, got %#v", r)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
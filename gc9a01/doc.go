@@ -0,0 +1,18 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package gc9a01 controls a 240x240 round color LCD via a GC9A01 controller on
+// a 4-wire SPI bus.
+//
+// The image is square; the pixels outside of the circle inscribed in it are
+// not visible.
+//
+// The driver keeps a copy of the image in RGB565 and only sends the area
+// passed to Draw, split in transfers no larger than what the SPI port
+// supports. The rotation is done by the controller.
+//
+// # Datasheet
+//
+// https://www.buydisplay.com/download/ic/GC9A01A.pdf
+package gc9a01
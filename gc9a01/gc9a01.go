@@ -0,0 +1,228 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gc9a01
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"periph.io/x/conn/v3/display"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/spi"
+	"periph.io/x/devices/v3/internal/mipidcs"
+)
+
+// Rotation is the rotation of the image relative to the native orientation of
+// the panel, done by the controller.
+type Rotation uint8
+
+// Rotations, clockwise.
+const (
+	Rotate0 Rotation = iota
+	Rotate90
+	Rotate180
+	Rotate270
+)
+
+// DefaultFrequency is the SPI clock used when Opts.Frequency is 0.
+const DefaultFrequency = 32 * physic.MegaHertz
+
+// Opts defines the options for the device.
+type Opts struct {
+	// Width and Height are the size of the panel in its native orientation.
+	Width  int
+	Height int
+	// XOffset and YOffset are the position of the panel in the 240x240
+	// controller RAM, in its native orientation.
+	XOffset int
+	YOffset int
+	// Rotation of the image.
+	Rotation Rotation
+	// Invert inverts the colors. The GC9A01 panels need it.
+	Invert bool
+	// BGR swaps the red and blue channels, for panels wired this way.
+	BGR bool
+	// Frequency of the SPI clock, DefaultFrequency if 0. The controller is
+	// specified up to 100MHz.
+	Frequency physic.Frequency
+	// ChunkSize is the largest SPI transfer. If 0, the limit of the SPI
+	// connection is used, or 4096 bytes.
+	ChunkSize int
+}
+
+// Opts240x240 is the options for the 1.28" round panels.
+var Opts240x240 = Opts{
+	Width:  240,
+	Height: 240,
+	Invert: true,
+	BGR:    true,
+}
+
+// New opens a handle to a GC9A01 controller on a 4-wire SPI port and
+// initializes it.
+//
+// dc is the data/command pin. rst is the reset pin, nil if not connected.
+func New(p spi.Port, dc, rst gpio.PinOut, opts *Opts) (*Dev, error) {
+	f := opts.Frequency
+	if f == 0 {
+		f = DefaultFrequency
+	}
+	c, err := p.Connect(f, spi.Mode0, 8)
+	if err != nil {
+		return nil, fmt.Errorf("gc9a01: %v", err)
+	}
+	d, err := mipidcs.New(c, dc, rst, &mipidcs.Opts{
+		Width:       opts.Width,
+		Height:      opts.Height,
+		XOffset:     opts.XOffset,
+		YOffset:     opts.YOffset,
+		RAMWidth:    240,
+		RAMHeight:   240,
+		Rotations:   rotations,
+		Rotation:    int(opts.Rotation),
+		Invert:      opts.Invert,
+		BGR:         opts.BGR,
+		PixelFormat: mipidcs.FormatDBI16Bits,
+		Init:        initCmds,
+		MaxTxSize:   opts.ChunkSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("gc9a01: %v", err)
+	}
+	return &Dev{d: d}, nil
+}
+
+// Dev is an open handle to the display controller.
+type Dev struct {
+	d *mipidcs.Display
+}
+
+func (d *Dev) String() string {
+	return fmt.Sprintf("gc9a01.Dev{%s}", d.d)
+}
+
+// ColorModel implements display.Drawer.
+//
+// It converts the colors to RGB565.
+func (d *Dev) ColorModel() color.Model {
+	return mipidcs.RGB565Model
+}
+
+// Bounds implements display.Drawer.
+func (d *Dev) Bounds() image.Rectangle {
+	return d.d.Bounds()
+}
+
+// Draw implements display.Drawer.
+//
+// Only the area r is sent to the controller.
+func (d *Dev) Draw(r image.Rectangle, src image.Image, sp image.Point) error {
+	return wrap(d.d.Draw(r, src, sp))
+}
+
+// SetRotation rotates the image. The content of the display is lost; the
+// whole image must be drawn again.
+func (d *Dev) SetRotation(r Rotation) error {
+	return wrap(d.d.SetRotation(int(r)))
+}
+
+// Invert inverts the colors, relative to the colors set by Opts.Invert.
+func (d *Dev) Invert(on bool) error {
+	return wrap(d.d.Invert(on))
+}
+
+// Halt turns the display off and puts the controller in sleep mode. The next
+// Draw wakes it up.
+func (d *Dev) Halt() error {
+	return wrap(d.d.Halt())
+}
+
+//
+
+// Commands
+const (
+	displayFunction      byte = 0xB6
+	powerControl2        byte = 0xC3
+	powerControl3        byte = 0xC4
+	powerControl4        byte = 0xC9
+	frameRate            byte = 0xE8
+	setGamma1            byte = 0xF0
+	setGamma2            byte = 0xF1
+	setGamma3            byte = 0xF2
+	setGamma4            byte = 0xF3
+	interRegisterEnable1 byte = 0xFE
+	interRegisterEnable2 byte = 0xEF
+)
+
+// rotations are the mipidcs.MemoryAccessControl flags of each rotation.
+var rotations = [...]byte{
+	Rotate0:   mipidcs.MX,
+	Rotate90:  mipidcs.MV,
+	Rotate180: mipidcs.MY,
+	Rotate270: mipidcs.MX | mipidcs.MY | mipidcs.MV,
+}
+
+// initCmds configures the controller, before the common commands sent by
+// mipidcs.
+var initCmds = []mipidcs.Cmd{
+	// The undocumented commands are from the initialization code of the
+	// vendor.
+	{Cmd: interRegisterEnable2},
+	{Cmd: 0xEB, Data: []byte{0x14}},
+	{Cmd: interRegisterEnable1},
+	{Cmd: interRegisterEnable2},
+	{Cmd: 0xEB, Data: []byte{0x14}},
+	{Cmd: 0x84, Data: []byte{0x40}},
+	{Cmd: 0x85, Data: []byte{0xFF}},
+	{Cmd: 0x86, Data: []byte{0xFF}},
+	{Cmd: 0x87, Data: []byte{0xFF}},
+	{Cmd: 0x88, Data: []byte{0x0A}},
+	{Cmd: 0x89, Data: []byte{0x21}},
+	{Cmd: 0x8A, Data: []byte{0x00}},
+	{Cmd: 0x8B, Data: []byte{0x80}},
+	{Cmd: 0x8C, Data: []byte{0x01}},
+	{Cmd: 0x8D, Data: []byte{0x01}},
+	{Cmd: 0x8E, Data: []byte{0xFF}},
+	{Cmd: 0x8F, Data: []byte{0xFF}},
+	{Cmd: displayFunction, Data: []byte{0x00, 0x20}},
+	{Cmd: 0x90, Data: []byte{0x08, 0x08, 0x08, 0x08}},
+	{Cmd: 0xBD, Data: []byte{0x06}},
+	{Cmd: 0xBC, Data: []byte{0x00}},
+	{Cmd: 0xFF, Data: []byte{0x60, 0x01, 0x04}},
+	{Cmd: powerControl2, Data: []byte{0x13}},
+	{Cmd: powerControl3, Data: []byte{0x13}},
+	{Cmd: powerControl4, Data: []byte{0x22}},
+	{Cmd: 0xBE, Data: []byte{0x11}},
+	{Cmd: 0xE1, Data: []byte{0x10, 0x0E}},
+	{Cmd: 0xDF, Data: []byte{0x21, 0x0C, 0x02}},
+	{Cmd: setGamma1, Data: []byte{0x45, 0x09, 0x08, 0x08, 0x26, 0x2A}},
+	{Cmd: setGamma2, Data: []byte{0x43, 0x70, 0x72, 0x36, 0x37, 0x6F}},
+	{Cmd: setGamma3, Data: []byte{0x45, 0x09, 0x08, 0x08, 0x26, 0x2A}},
+	{Cmd: setGamma4, Data: []byte{0x43, 0x70, 0x72, 0x36, 0x37, 0x6F}},
+	{Cmd: 0xED, Data: []byte{0x1B, 0x0B}},
+	{Cmd: 0xAE, Data: []byte{0x77}},
+	{Cmd: 0xCD, Data: []byte{0x63}},
+	{Cmd: 0x70, Data: []byte{0x07, 0x07, 0x04, 0x0E, 0x0F, 0x09, 0x07, 0x08, 0x03}},
+	{Cmd: frameRate, Data: []byte{0x34}},
+	{Cmd: 0x62, Data: []byte{0x18, 0x0D, 0x71, 0xED, 0x70, 0x70, 0x18, 0x0F, 0x71, 0xEF, 0x70, 0x70}},
+	{Cmd: 0x63, Data: []byte{0x18, 0x11, 0x71, 0xF1, 0x70, 0x70, 0x18, 0x13, 0x71, 0xF3, 0x70, 0x70}},
+	{Cmd: 0x64, Data: []byte{0x28, 0x29, 0xF1, 0x01, 0xF1, 0x00, 0x07}},
+	{Cmd: 0x66, Data: []byte{0x3C, 0x00, 0xCD, 0x67, 0x45, 0x45, 0x10, 0x00, 0x00, 0x00}},
+	{Cmd: 0x67, Data: []byte{0x00, 0x3C, 0x00, 0x00, 0x00, 0x01, 0x54, 0x10, 0x32, 0x98}},
+	{Cmd: 0x74, Data: []byte{0x10, 0x85, 0x80, 0x00, 0x00, 0x4E, 0x00}},
+	{Cmd: 0x98, Data: []byte{0x3E, 0x07}},
+	{Cmd: mipidcs.TearingEffectOn, Data: []byte{0x00}},
+}
+
+func wrap(err error) error {
+	if err != nil {
+		return fmt.Errorf("gc9a01: %v", err)
+	}
+	return nil
+}
+
+var _ display.Drawer = &Dev{}
@@ -0,0 +1,22 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package gps reads the position reported by a GNSS receiver as NMEA 0183
+// sentences, like the u-blox modules on a serial port.
+//
+// The RMC, GGA and GSV sentences of all the talkers (GPS, GLONASS, Galileo,
+// BeiDou or combined) are merged into a Fix per navigation epoch. The other
+// sentences are ignored.
+//
+// The update rate of u-blox receivers can be configured with the UBX
+// protocol, see Opts.UpdateRate.
+//
+// # NMEA 0183
+//
+// https://gpsd.gitlab.io/gpsd/NMEA.html
+//
+// # u-blox protocol
+//
+// https://content.u-blox.com/sites/default/files/products/documents/u-blox8-M8_ReceiverDescrProtSpec_UBX-13003221.pdf
+package gps
@@ -0,0 +1,34 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gps_test
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/devices/v3/gps"
+)
+
+func Example() {
+	// The serial port must already be configured, e.g. with
+	// "stty -F /dev/ttyACM0 9600 raw".
+	f, err := os.OpenFile("/dev/ttyACM0", os.O_RDWR, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	// Ask a u-blox receiver for 5 fixes per second.
+	dev, err := gps.New(f, &gps.Opts{UpdateRate: 5 * physic.Hertz})
+	if err != nil {
+		log.Fatal(err)
+	}
+	for fix := range dev.Fixes() {
+		fmt.Println(&fix)
+	}
+	log.Fatal(dev.Err())
+}
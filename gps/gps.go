@@ -0,0 +1,360 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gps
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/physic"
+)
+
+// Quality is the quality of a position fix, as reported by GGA.
+type Quality uint8
+
+// Fix qualities.
+const (
+	NoFix Quality = iota
+	GPSFix
+	DGPSFix
+	PPSFix
+	RTKFix
+	FloatRTKFix
+	EstimatedFix
+	ManualFix
+	SimulatedFix
+)
+
+func (q Quality) String() string {
+	switch q {
+	case NoFix:
+		return "NoFix"
+	case GPSFix:
+		return "GPS"
+	case DGPSFix:
+		return "DGPS"
+	case PPSFix:
+		return "PPS"
+	case RTKFix:
+		return "RTK"
+	case FloatRTKFix:
+		return "FloatRTK"
+	case EstimatedFix:
+		return "Estimated"
+	case ManualFix:
+		return "Manual"
+	case SimulatedFix:
+		return "Simulated"
+	default:
+		return fmt.Sprintf("Quality(%d)", q)
+	}
+}
+
+// Satellite is a satellite in view, as reported by GSV.
+type Satellite struct {
+	// Talker identifies the constellation: "GP" for GPS, "GL" for GLONASS,
+	// "GA" for Galileo, "GB" or "BD" for BeiDou.
+	Talker string
+	// PRN is the satellite number.
+	PRN       int
+	Elevation physic.Angle
+	Azimuth   physic.Angle
+	// SNR is the carrier to noise ratio in dB-Hz, 0 when not tracked.
+	SNR int
+}
+
+// Fix is the navigation solution of an epoch.
+type Fix struct {
+	// Time is the UTC time of the fix. The date is only known once a RMC
+	// sentence was received.
+	Time time.Time
+	// Valid is the status reported by RMC.
+	Valid   bool
+	Quality Quality
+	// Latitude is positive north of the equator and Longitude east of the
+	// Greenwich meridian.
+	Latitude  physic.Angle
+	Longitude physic.Angle
+	// Altitude is above the mean sea level.
+	Altitude physic.Distance
+	// Speed and Course are over the ground, Course relative to the true
+	// north.
+	Speed  physic.Speed
+	Course physic.Angle
+	// Satellites is the number of satellites used in the solution.
+	Satellites int
+	// HDOP is the horizontal dilution of precision.
+	HDOP float64
+	// InView lists the satellites in view.
+	InView []Satellite
+}
+
+func (f *Fix) String() string {
+	return fmt.Sprintf("%s %s: %s, %s, %s, %d satellites", f.Time.Format(time.RFC3339), f.Quality, f.Latitude, f.Longitude, f.Altitude, f.Satellites)
+}
+
+// Opts holds the configuration options.
+type Opts struct {
+	// UpdateRate configures a u-blox receiver to compute fixes at this rate.
+	// The reader passed to New must then also implement io.Writer. 0 keeps
+	// the receiver's current rate.
+	UpdateRate physic.Frequency
+}
+
+// DefaultOpts is the recommended default options.
+var DefaultOpts = Opts{}
+
+// New starts reading NMEA sentences from r, usually a serial port.
+//
+// Reading stops when r returns an error, for example when it is closed.
+func New(r io.Reader, opts *Opts) (*Dev, error) {
+	d := &Dev{r: r, c: make(chan Fix, 1), inView: map[string][]Satellite{}, pending: map[string][]Satellite{}}
+	if opts.UpdateRate != 0 {
+		if err := d.SetUpdateRate(opts.UpdateRate); err != nil {
+			return nil, err
+		}
+	}
+	go d.loop()
+	return d, nil
+}
+
+// Dev is a handle to a GNSS receiver.
+type Dev struct {
+	r io.Reader
+	c chan Fix
+
+	mu  sync.Mutex
+	fix Fix
+	ok  bool
+	err error
+
+	// Only accessed by loop.
+	cur     Fix
+	epoch   string
+	date    time.Time
+	inView  map[string][]Satellite
+	pending map[string][]Satellite
+}
+
+func (d *Dev) String() string {
+	return fmt.Sprintf("GPS{%v}", d.r)
+}
+
+// Sense returns the last complete fix.
+//
+// A fix is complete once the first sentence of the next epoch is received.
+func (d *Dev) Sense(f *Fix) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.ok {
+		if d.err != nil {
+			return d.err
+		}
+		return errNoFix
+	}
+	*f = d.fix
+	f.InView = append([]Satellite(nil), d.fix.InView...)
+	return nil
+}
+
+// Fixes returns a channel receiving each fix as it completes.
+//
+// Only the most recent fix is kept when the channel isn't drained fast
+// enough. The channel is closed when reading fails, see Err.
+func (d *Dev) Fixes() <-chan Fix {
+	return d.c
+}
+
+// Err returns the error that stopped reading, if any.
+func (d *Dev) Err() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.err
+}
+
+// SetUpdateRate configures a u-blox receiver to compute fixes at rate f with
+// UBX-CFG-RATE, from 0.016Hz to 40Hz. The maximum rate depends on the model.
+//
+// The reader passed to New must implement io.Writer. The acknowledgement isn't
+// checked.
+func (d *Dev) SetUpdateRate(f physic.Frequency) error {
+	w, ok := d.r.(io.Writer)
+	if !ok {
+		return errors.New("gps: the reader must implement io.Writer to configure the receiver")
+	}
+	if f <= 0 {
+		return errors.New("gps: invalid update rate")
+	}
+	ms := f.Period() / time.Millisecond
+	if ms < minMeasRate || ms > maxMeasRate {
+		return fmt.Errorf("gps: update rate %s out of range", f)
+	}
+	// measRate, navRate (cycles per fix) and timeRef (GPS time).
+	payload := []byte{byte(ms), byte(ms >> 8), 1, 0, 1, 0}
+	if _, err := w.Write(ubxPacket(ubxClassCFG, ubxCFGRate, payload)); err != nil {
+		return fmt.Errorf("gps: %v", err)
+	}
+	return nil
+}
+
+// Halt implements conn.Resource.
+//
+// It has no effect, close the reader to stop reading.
+func (d *Dev) Halt() error {
+	return nil
+}
+
+//
+
+const (
+	ubxSync1    = 0xB5
+	ubxSync2    = 0x62
+	ubxClassCFG = 0x06
+	ubxCFGRate  = 0x08
+
+	minMeasRate = 25
+	maxMeasRate = 65535
+)
+
+var errNoFix = errors.New("gps: no fix yet")
+
+// ubxPacket frames a UBX message.
+func ubxPacket(class, id byte, payload []byte) []byte {
+	p := append([]byte{ubxSync1, ubxSync2, class, id, byte(len(payload)), byte(len(payload) >> 8)}, payload...)
+	var a, b byte
+	for _, c := range p[2:] {
+		a += c
+		b += a
+	}
+	return append(p, a, b)
+}
+
+func (d *Dev) loop() {
+	s := bufio.NewScanner(d.r)
+	for s.Scan() {
+		// UBX messages are interleaved with the sentences, skip what precedes
+		// the sentence start.
+		line := s.Text()
+		if i := strings.LastIndexByte(line, '$'); i >= 0 {
+			d.handle(line[i:])
+		}
+	}
+	err := s.Err()
+	if err == nil {
+		err = io.EOF
+	}
+	if d.epoch != "" {
+		d.complete()
+	}
+	d.mu.Lock()
+	d.err = fmt.Errorf("gps: %w", err)
+	d.mu.Unlock()
+	close(d.c)
+}
+
+// handle merges a sentence into the current epoch. Invalid sentences are
+// ignored.
+func (d *Dev) handle(line string) {
+	talker, kind, fields, err := parseSentence(line)
+	if err != nil {
+		return
+	}
+	switch kind {
+	case "RMC":
+		var r rmc
+		if r.parse(fields) != nil {
+			return
+		}
+		d.startEpoch(fields[0])
+		d.date = r.date
+		d.cur.Time = r.date.Add(r.time)
+		d.cur.Valid = r.valid
+		if r.valid {
+			d.cur.Latitude, d.cur.Longitude = r.lat, r.lon
+		}
+		d.cur.Speed = r.speed
+		d.cur.Course = r.course
+	case "GGA":
+		var g gga
+		if g.parse(fields) != nil {
+			return
+		}
+		d.startEpoch(fields[0])
+		d.cur.Time = d.date.Add(g.time)
+		d.cur.Quality = g.quality
+		if g.quality != NoFix {
+			d.cur.Latitude, d.cur.Longitude = g.lat, g.lon
+			d.cur.Altitude = g.alt
+		}
+		d.cur.Satellites = g.satellites
+		d.cur.HDOP = g.hdop
+	case "GSV":
+		var g gsv
+		if g.parse(fields) != nil {
+			return
+		}
+		for i := range g.sats {
+			g.sats[i].Talker = talker
+		}
+		if g.msg == 1 {
+			d.pending[talker] = nil
+		}
+		d.pending[talker] = append(d.pending[talker], g.sats...)
+		if g.msg == g.total {
+			d.inView[talker] = d.pending[talker]
+			delete(d.pending, talker)
+		}
+	}
+}
+
+// startEpoch completes the current fix when the time of day t belongs to the
+// next epoch.
+func (d *Dev) startEpoch(t string) {
+	if t == d.epoch {
+		return
+	}
+	if d.epoch != "" {
+		d.complete()
+	}
+	d.epoch = t
+	d.cur = Fix{}
+}
+
+// complete publishes the current fix.
+func (d *Dev) complete() {
+	talkers := make([]string, 0, len(d.inView))
+	for t := range d.inView {
+		talkers = append(talkers, t)
+	}
+	sort.Strings(talkers)
+	f := d.cur
+	f.InView = nil
+	for _, t := range talkers {
+		f.InView = append(f.InView, d.inView[t]...)
+	}
+	d.mu.Lock()
+	d.fix = f
+	d.ok = true
+	d.mu.Unlock()
+	// Replace the fix not yet received, if any.
+	select {
+	case d.c <- f:
+	default:
+		select {
+		case <-d.c:
+		default:
+		}
+		d.c <- f
+	}
+}
+
+var _ conn.Resource = &Dev{}
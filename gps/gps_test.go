@@ -0,0 +1,169 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gps
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3/physic"
+)
+
+// sentence appends the checksum to body.
+func sentence(body string) string {
+	var sum byte
+	for i := 0; i < len(body); i++ {
+		sum ^= body[i]
+	}
+	return fmt.Sprintf("$%s*%02X\r\n", body, sum)
+}
+
+func TestParseSentence(t *testing.T) {
+	talker, kind, fields, err := parseSentence("$GPGGA,123519,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,*47")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if talker != "GP" || kind != "GGA" || len(fields) != 14 || fields[0] != "123519" {
+		t.Fatal(talker, kind, fields)
+	}
+	for _, line := range []string{
+		"",
+		"GPGGA,123519*47",
+		"$GPGGA,123519,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,*48",
+		"$GPGGA,123519,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,",
+		"$GPGGA,123519,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,*4",
+		"$GP,1*2C",
+	} {
+		if _, _, _, err := parseSentence(line); err == nil {
+			t.Errorf("%q: expected error", line)
+		}
+	}
+}
+
+func TestDev(t *testing.T) {
+	r, w := io.Pipe()
+	d, err := New(r, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var f Fix
+	if err := d.Sense(&f); err != errNoFix {
+		t.Fatal(err)
+	}
+	write := func(lines ...string) {
+		for _, l := range lines {
+			if _, err := io.WriteString(w, l); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	write(
+		sentence("GPRMC,123519.00,A,4807.038,N,01131.000,W,022.4,084.4,230394,003.1,W"),
+		// An UBX acknowledgement precedes the sentence.
+		"\xb5\x62\x05\x01\x02\x00\x06\x08\x16\x3f",
+		sentence("GNGGA,123519.00,4807.038,N,01131.000,W,1,08,0.9,545.4,M,46.9,M,,"),
+		sentence("GPGSV,2,1,05,01,40,083,46,02,17,308,41,12,07,344,39,14,22,228,"),
+		// Ignored.
+		sentence("GPVTG,084.4,T,,M,022.4,N,041.5,K,A"),
+		"$GPGSV,2,2,05,15,10,020,30*00\r\n",
+		sentence("GPGSV,2,2,05,15,10,020,30"),
+		sentence("GLGSV,1,1,01,65,50,100,35,1"),
+		// The next epoch completes the fix.
+		sentence("GPRMC,123520.00,V,,,,,,,230394,,,N"),
+	)
+	f = <-d.Fixes()
+	want := Fix{
+		Time:       time.Date(1994, 3, 23, 12, 35, 19, 0, time.UTC),
+		Valid:      true,
+		Quality:    GPSFix,
+		Latitude:   degrees(48 + 7.038/60),
+		Longitude:  -degrees(11 + 31.0/60),
+		Altitude:   545400 * physic.MilliMetre,
+		Speed:      11523555556 * physic.NanoMetrePerSecond,
+		Course:     degrees(84.4),
+		Satellites: 8,
+		HDOP:       0.9,
+		InView: []Satellite{
+			{Talker: "GL", PRN: 65, Elevation: degrees(50), Azimuth: degrees(100), SNR: 35},
+			{Talker: "GP", PRN: 1, Elevation: degrees(40), Azimuth: degrees(83), SNR: 46},
+			{Talker: "GP", PRN: 2, Elevation: degrees(17), Azimuth: degrees(308), SNR: 41},
+			{Talker: "GP", PRN: 12, Elevation: degrees(7), Azimuth: degrees(344), SNR: 39},
+			{Talker: "GP", PRN: 14, Elevation: degrees(22), Azimuth: degrees(228)},
+			{Talker: "GP", PRN: 15, Elevation: degrees(10), Azimuth: degrees(20), SNR: 30},
+		},
+	}
+	if s1, s2 := fmt.Sprintf("%+v", f), fmt.Sprintf("%+v", want); s1 != s2 {
+		t.Fatalf("got:\n%s\nwant:\n%s", s1, s2)
+	}
+	var sensed Fix
+	if err := d.Sense(&sensed); err != nil {
+		t.Fatal(err)
+	}
+	if s1, s2 := fmt.Sprintf("%+v", sensed), fmt.Sprintf("%+v", want); s1 != s2 {
+		t.Fatalf("got:\n%s\nwant:\n%s", s1, s2)
+	}
+	if s := f.String(); s != "1994-03-23T12:35:19Z GPS: 48.117°, -11.517°, 545.400m, 8 satellites" {
+		t.Fatal(s)
+	}
+
+	// The last fix is completed at the end of the stream.
+	write(sentence("GPGGA,123520.00,,,,,0,00,99.99,,,,,,"))
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f = <-d.Fixes()
+	if f.Valid || f.Quality != NoFix || f.Latitude != 0 || f.Time != time.Date(1994, 3, 23, 12, 35, 20, 0, time.UTC) || len(f.InView) != 6 {
+		t.Fatalf("%+v", f)
+	}
+	if _, ok := <-d.Fixes(); ok {
+		t.Fatal("channel not closed")
+	}
+	if err := d.Err(); !errors.Is(err, io.EOF) {
+		t.Fatal(err)
+	}
+	if err := d.Halt(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type readWriter struct {
+	io.Reader
+	w bytes.Buffer
+}
+
+func (r *readWriter) Write(b []byte) (int, error) {
+	return r.w.Write(b)
+}
+
+func TestSetUpdateRate(t *testing.T) {
+	rw := &readWriter{Reader: strings.NewReader("")}
+	if _, err := New(rw, &Opts{UpdateRate: 10 * physic.Hertz}); err != nil {
+		t.Fatal(err)
+	}
+	want := []byte{0xB5, 0x62, 0x06, 0x08, 0x06, 0x00, 0x64, 0x00, 0x01, 0x00, 0x01, 0x00, 0x7A, 0x12}
+	if !bytes.Equal(rw.w.Bytes(), want) {
+		t.Fatalf("% X", rw.w.Bytes())
+	}
+	if _, err := New(rw, &Opts{UpdateRate: 100 * physic.Hertz}); err == nil {
+		t.Fatal("expected error")
+	}
+	if _, err := New(strings.NewReader(""), &Opts{UpdateRate: physic.Hertz}); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestQuality_String(t *testing.T) {
+	if s := RTKFix.String(); s != "RTK" {
+		t.Fatal(s)
+	}
+	if s := Quality(9).String(); s != "Quality(9)" {
+		t.Fatal(s)
+	}
+}
@@ -0,0 +1,254 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gps
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"periph.io/x/conn/v3/physic"
+)
+
+// knot is a speed of one nautical mile per hour.
+const knot = 1852 * physic.MetrePerSecond / 3600
+
+var errField = errors.New("gps: invalid field")
+
+// parseSentence verifies the checksum of a sentence like
+// "$GPRMC,...*hh" and splits it.
+func parseSentence(line string) (talker, kind string, fields []string, err error) {
+	line = strings.TrimRight(line, "\r\n")
+	star := strings.LastIndexByte(line, '*')
+	if len(line) < 7 || line[0] != '$' || star == -1 || star+3 != len(line) {
+		return "", "", nil, fmt.Errorf("gps: invalid sentence %q", line)
+	}
+	want, err := strconv.ParseUint(line[star+1:], 16, 8)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("gps: invalid sentence %q", line)
+	}
+	var sum byte
+	for i := 1; i < star; i++ {
+		sum ^= line[i]
+	}
+	if sum != byte(want) {
+		return "", "", nil, fmt.Errorf("gps: invalid checksum in %q", line)
+	}
+	fields = strings.Split(line[1:star], ",")
+	if len(fields[0]) != 5 {
+		return "", "", nil, fmt.Errorf("gps: invalid address in %q", line)
+	}
+	return fields[0][:2], fields[0][2:], fields[1:], nil
+}
+
+// rmc is the recommended minimum specific GNSS data.
+type rmc struct {
+	time   time.Duration
+	valid  bool
+	lat    physic.Angle
+	lon    physic.Angle
+	speed  physic.Speed
+	course physic.Angle
+	date   time.Time
+}
+
+func (r *rmc) parse(f []string) error {
+	if len(f) < 9 {
+		return errField
+	}
+	var err error
+	if r.time, err = parseTime(f[0]); err != nil {
+		return err
+	}
+	r.valid = f[1] == "A"
+	if r.lat, err = parseCoordinate(f[2], f[3], 2, "N", "S"); err != nil {
+		return err
+	}
+	if r.lon, err = parseCoordinate(f[4], f[5], 3, "E", "W"); err != nil {
+		return err
+	}
+	v, err := parseFloat(f[6])
+	if err != nil {
+		return err
+	}
+	r.speed = physic.Speed(math.Round(v * float64(knot)))
+	if v, err = parseFloat(f[7]); err != nil {
+		return err
+	}
+	r.course = degrees(v)
+	r.date, err = parseDate(f[8])
+	return err
+}
+
+// gga is the global positioning system fix data.
+type gga struct {
+	time       time.Duration
+	lat        physic.Angle
+	lon        physic.Angle
+	quality    Quality
+	satellites int
+	hdop       float64
+	alt        physic.Distance
+}
+
+func (g *gga) parse(f []string) error {
+	if len(f) < 10 {
+		return errField
+	}
+	var err error
+	if g.time, err = parseTime(f[0]); err != nil {
+		return err
+	}
+	if g.lat, err = parseCoordinate(f[1], f[2], 2, "N", "S"); err != nil {
+		return err
+	}
+	if g.lon, err = parseCoordinate(f[3], f[4], 3, "E", "W"); err != nil {
+		return err
+	}
+	q, err := parseInt(f[5])
+	if err != nil {
+		return err
+	}
+	g.quality = Quality(q)
+	if g.satellites, err = parseInt(f[6]); err != nil {
+		return err
+	}
+	if g.hdop, err = parseFloat(f[7]); err != nil {
+		return err
+	}
+	v, err := parseFloat(f[8])
+	if err != nil {
+		return err
+	}
+	g.alt = physic.Distance(math.Round(v * float64(physic.Metre)))
+	return nil
+}
+
+// gsv is one of the messages listing the satellites in view.
+type gsv struct {
+	total int
+	msg   int
+	sats  []Satellite
+}
+
+func (g *gsv) parse(f []string) error {
+	if len(f) < 3 {
+		return errField
+	}
+	var err error
+	if g.total, err = parseInt(f[0]); err != nil {
+		return err
+	}
+	if g.msg, err = parseInt(f[1]); err != nil {
+		return err
+	}
+	if g.msg < 1 || g.msg > g.total {
+		return errField
+	}
+	// Up to 4 satellites follow, then an optional signal ID since NMEA 4.10.
+	for f = f[3:]; len(f) >= 4; f = f[4:] {
+		var s Satellite
+		if s.PRN, err = parseInt(f[0]); err != nil {
+			return err
+		}
+		e, err := parseInt(f[1])
+		if err != nil {
+			return err
+		}
+		a, err := parseInt(f[2])
+		if err != nil {
+			return err
+		}
+		s.Elevation, s.Azimuth = degrees(float64(e)), degrees(float64(a))
+		if s.SNR, err = parseInt(f[3]); err != nil {
+			return err
+		}
+		g.sats = append(g.sats, s)
+	}
+	return nil
+}
+
+// parseTime parses hhmmss.ss as a duration since midnight.
+func parseTime(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	if len(s) < 6 {
+		return 0, errField
+	}
+	h, err1 := strconv.Atoi(s[:2])
+	m, err2 := strconv.Atoi(s[2:4])
+	sec, err3 := strconv.ParseFloat(s[4:], 64)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return 0, errField
+	}
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(math.Round(sec*1000))*time.Millisecond, nil
+}
+
+// parseDate parses ddmmyy.
+func parseDate(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse("020106", s)
+	if err != nil {
+		return time.Time{}, errField
+	}
+	return t, nil
+}
+
+// parseCoordinate parses (d)ddmm.mmmm with digits digits of degrees, and its
+// hemisphere.
+func parseCoordinate(s, hemisphere string, digits int, pos, neg string) (physic.Angle, error) {
+	if s == "" {
+		return 0, nil
+	}
+	if len(s) < digits+2 {
+		return 0, errField
+	}
+	d, err1 := strconv.Atoi(s[:digits])
+	m, err2 := strconv.ParseFloat(s[digits:], 64)
+	if err1 != nil || err2 != nil {
+		return 0, errField
+	}
+	a := degrees(float64(d) + m/60)
+	switch hemisphere {
+	case pos:
+		return a, nil
+	case neg:
+		return -a, nil
+	default:
+		return 0, errField
+	}
+}
+
+func parseFloat(s string) (float64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, errField
+	}
+	return v, nil
+}
+
+func parseInt(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, errField
+	}
+	return v, nil
+}
+
+func degrees(v float64) physic.Angle {
+	return physic.Angle(math.Round(v * float64(physic.Pi) / 180))
+}
@@ -4,17 +4,27 @@
 
 // Package hd44780 controls the Hitachi LCD display chipset HD-44780
 //
+// The display can be wired in 4 bit mode to GPIO pins, see New, or connected
+// through a PCF8574 based I²C backpack, see NewPCF8574.
+//
+// Dev has the method set of display.TextDisplay, which isn't part of the
+// periph.io/x/conn release used by this module yet. CursorMode and
+// CursorDirection mirror the types of the display package, so Dev implements
+// the interface once they are replaced by aliases.
+//
 // # Datasheet
 //
 // https://www.sparkfun.com/datasheets/LCD/HD44780.pdf
 package hd44780
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
 	"periph.io/x/conn/v3"
 	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/i2c"
 )
 
 // lineTwo offset for the second line in the LCD buffer.
@@ -30,9 +40,31 @@ type Dev struct {
 
 	// enable pin
 	enablePin gpio.PinOut
+
+	// pcf is the PCF8574 I²C backpack, nil when the LCD is wired to GPIO pins.
+	pcf *i2c.Dev
+
+	// ctrl is the state of the RS and backlight bits of the backpack.
+	ctrl byte
+
+	// rows and cols are the size of the display.
+	rows, cols int
+	// row and col are the cursor position.
+	row, col int
+	// control and entry are the last display control and entry mode
+	// instructions.
+	control, entry uint8
 }
 
-// New creates and initializes the LCD device
+// PCF8574 backpack bits. P1 is R/W, which is kept low.
+const (
+	pcfRS        = 0x01
+	pcfEnable    = 0x04
+	pcfBacklight = 0x08
+)
+
+// New creates and initializes the LCD device, assumed to have 2 lines of 16
+// characters.
 //
 //	data - references to data pins
 //	rs - rs pin
@@ -45,6 +77,8 @@ func New(data []gpio.PinOut, rs, e gpio.PinOut) (*Dev, error) {
 		dataPins:  data,
 		enablePin: e,
 		rsPin:     rs,
+		rows:      2,
+		cols:      16,
 	}
 	if err := dev.Reset(); err != nil {
 		return nil, err
@@ -52,6 +86,40 @@ func New(data []gpio.PinOut, rs, e gpio.PinOut) (*Dev, error) {
 	return dev, nil
 }
 
+// NewPCF8574 creates and initializes a LCD device connected through the
+// common I²C backpack based on a PCF8574 or PCF8574A, like the ones sold with
+// 16x2 and 20x4 displays.
+//
+// The backpack is wired as P0:RS, P1:R/W, P2:E, P3:backlight and P4-P7:D4-D7.
+// The backlight is turned on. The default address is 0x27, or 0x3F for the
+// PCF8574A. rows and cols are the size of the display, e.g. 2 and 16.
+func NewPCF8574(bus i2c.Bus, addr uint16, rows, cols int) (*Dev, error) {
+	if rows < 1 || rows > 4 || cols < 1 || cols > 40 || rows*cols > 80 {
+		return nil, fmt.Errorf("hd44780: invalid size %dx%d", rows, cols)
+	}
+	dev := &Dev{pcf: &i2c.Dev{Bus: bus, Addr: addr}, ctrl: pcfBacklight, rows: rows, cols: cols}
+	if err := dev.Reset(); err != nil {
+		return nil, err
+	}
+	return dev, nil
+}
+
+// SetBacklight turns the backlight on or off.
+//
+// It is only supported with the I²C backpack.
+func (r *Dev) SetBacklight(on bool) error {
+	if r.pcf == nil {
+		return errors.New("hd44780: backlight control requires the I²C backpack")
+	}
+	if on {
+		r.ctrl |= pcfBacklight
+	} else {
+		r.ctrl &^= pcfBacklight
+	}
+	_, err := r.pcf.Write([]byte{r.ctrl})
+	return err
+}
+
 // Reset resets the HC-44780 chipset, clears the screen buffer and moves cursor to the
 // home of screen (line 0, column 0).
 func (r *Dev) Reset() error {
@@ -61,10 +129,7 @@ func (r *Dev) Reset() error {
 
 	delayMs(15)
 
-	if err := r.rsPin.Out(gpio.Low); err != nil {
-		return err
-	}
-	if err := r.enablePin.Out(gpio.Low); err != nil {
+	if err := r.sendInstruction(); err != nil {
 		return err
 	}
 
@@ -72,10 +137,18 @@ func (r *Dev) Reset() error {
 		return err
 	}
 
-	return r.bulkSendData(initSequence, r.writeInstruction)
+	if err := r.bulkSendData(initSequence, r.writeInstruction); err != nil {
+		return err
+	}
+	r.row, r.col = 0, 0
+	r.control, r.entry = 0x0c, 0x06
+	return nil
 }
 
 func (r *Dev) String() string {
+	if r.pcf != nil {
+		return fmt.Sprintf("HD44870, I²C backpack{%s}", r.pcf)
+	}
 	return "HD44870, 4 bit mode"
 }
 
@@ -93,7 +166,11 @@ func (r *Dev) Halt() error {
 //	line - screen line, 0-based
 //	column - column, 0-based
 func (r *Dev) SetCursor(line uint8, column uint8) error {
-	return r.writeInstruction(0x80 | (line*lineTwo + column))
+	if err := r.writeInstruction(0x80 | (line*lineTwo + column)); err != nil {
+		return err
+	}
+	r.row, r.col = int(line), int(column)
+	return nil
 }
 
 // Print the data string
@@ -121,6 +198,7 @@ func (r *Dev) WriteChar(data uint8) error {
 	if err := r.write4Bits(data); err != nil {
 		return err
 	}
+	r.col++
 	delayUs(10)
 	return nil
 }
@@ -157,6 +235,10 @@ func (r *Dev) bulkSendData(seq [][]uint, f func(_data uint8) error) error {
 }
 
 func (r *Dev) clearBits() error {
+	if r.pcf != nil {
+		_, err := r.pcf.Write([]byte{r.ctrl &^ pcfRS})
+		return err
+	}
 	for _, v := range r.dataPins {
 		if err := v.Out(gpio.Low); err != nil {
 			return err
@@ -166,6 +248,12 @@ func (r *Dev) clearBits() error {
 }
 
 func (r *Dev) write4Bits(data uint8) error {
+	if r.pcf != nil {
+		// Strobe E with the data set.
+		b := data<<4 | r.ctrl
+		_, err := r.pcf.Write([]byte{b | pcfEnable, b})
+		return err
+	}
 	for i, v := range r.dataPins {
 		if data&(1<<uint(i)) > 0 {
 			if err := v.Out(gpio.High); err != nil {
@@ -181,6 +269,10 @@ func (r *Dev) write4Bits(data uint8) error {
 }
 
 func (r *Dev) sendInstruction() error {
+	if r.pcf != nil {
+		r.ctrl &^= pcfRS
+		return nil
+	}
 	if err := r.rsPin.Out(gpio.Low); err != nil {
 		return err
 	}
@@ -188,6 +280,10 @@ func (r *Dev) sendInstruction() error {
 }
 
 func (r *Dev) sendData() error {
+	if r.pcf != nil {
+		r.ctrl |= pcfRS
+		return nil
+	}
 	if err := r.rsPin.Out(gpio.High); err != nil {
 		return err
 	}
@@ -0,0 +1,144 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package hd44780
+
+import (
+	"testing"
+
+	"periph.io/x/conn/v3/i2c/i2ctest"
+)
+
+func TestNewPCF8574(t *testing.T) {
+	const addr = 0x27
+	// Backlight on, RS low.
+	ops := []i2ctest.IO{{Addr: addr, W: []byte{0x08}}}
+	nibble := func(rs byte, data uint8) i2ctest.IO {
+		b := data<<4 | 0x08 | rs
+		return i2ctest.IO{Addr: addr, W: []byte{b | 0x04, b}}
+	}
+	for _, v := range resetSequence {
+		ops = append(ops, nibble(0, uint8(v[0])))
+	}
+	for _, v := range initSequence {
+		ops = append(ops, nibble(0, uint8(v[0])>>4), nibble(0, uint8(v[0])&0x0f))
+	}
+	// Print "A".
+	ops = append(ops, nibble(1, 0x4), nibble(1, 0x1))
+	// Backlight off.
+	ops = append(ops, i2ctest.IO{Addr: addr, W: []byte{0x01}})
+
+	bus := i2ctest.Playback{Ops: ops}
+	dev, err := NewPCF8574(&bus, addr, 2, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := dev.String(); s != "HD44870, I²C backpack{playback(39)}" {
+		t.Fatal(s)
+	}
+	if err := dev.Print("A"); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.SetBacklight(false); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewPCF8574_size(t *testing.T) {
+	for _, s := range [][2]int{{0, 16}, {5, 16}, {2, 0}, {4, 40}} {
+		if _, err := NewPCF8574(&i2ctest.Playback{}, 0x27, s[0], s[1]); err == nil {
+			t.Fatalf("expected error for %dx%d", s[0], s[1])
+		}
+	}
+}
+
+func TestTextDisplay(t *testing.T) {
+	const addr = 0x27
+	ops := []i2ctest.IO{{Addr: addr, W: []byte{0x08}}}
+	nibble := func(rs byte, data uint8) i2ctest.IO {
+		b := data<<4 | 0x08 | rs
+		return i2ctest.IO{Addr: addr, W: []byte{b | 0x04, b}}
+	}
+	byt := func(rs byte, data uint8) {
+		ops = append(ops, nibble(rs, data>>4), nibble(rs, data&0x0f))
+	}
+	for _, v := range resetSequence {
+		ops = append(ops, nibble(0, uint8(v[0])))
+	}
+	for _, v := range initSequence {
+		byt(0, uint8(v[0]))
+	}
+	byt(0, 0xd5) // MoveTo(3, 1)
+	byt(0, 0x95) // Move(Up)
+	byt(1, 'H')  // WriteString("Hi")
+	byt(1, 'i')
+	byt(0, 0x98) // Move(Forward)
+	byt(0, 0x0e) // Cursor(CursorUnderline)
+	byt(0, 0x0f) // Cursor(CursorBlink)
+	byt(0, 0x0c) // Cursor(CursorOff)
+	byt(0, 0x08) // Display(false)
+	byt(0, 0x07) // AutoScroll(true)
+	byt(0, 0x02) // Home()
+
+	bus := i2ctest.Playback{Ops: ops}
+	dev, err := NewPCF8574(&bus, addr, 4, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dev.Rows() != 4 || dev.Cols() != 20 || dev.MinRow() != 0 || dev.MinCol() != 0 {
+		t.Fatalf("unexpected size %dx%d", dev.Rows(), dev.Cols())
+	}
+	if err := dev.MoveTo(3, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.Move(Up); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := dev.WriteString("Hi"); n != 2 || err != nil {
+		t.Fatal(n, err)
+	}
+	if err := dev.Move(Forward); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.Cursor(CursorUnderline); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.Cursor(CursorBlink); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.Cursor(CursorOff); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.Display(false); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.AutoScroll(true); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.Home(); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.MoveTo(4, 0); err == nil {
+		t.Fatal("expected error moving out of the display")
+	}
+	if err := dev.Move(CursorDirection(-1)); err == nil {
+		t.Fatal("expected error for an invalid direction")
+	}
+	if err := dev.Cursor(CursorMode(-1)); err == nil {
+		t.Fatal("expected error for an invalid cursor mode")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSetBacklight_GPIO(t *testing.T) {
+	dev := &Dev{}
+	if err := dev.SetBacklight(true); err == nil {
+		t.Fatal("expected error without the I²C backpack")
+	}
+}
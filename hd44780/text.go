@@ -0,0 +1,175 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package hd44780
+
+import (
+	"errors"
+	"fmt"
+)
+
+// CursorMode is the appearance of the cursor, see Dev.Cursor.
+//
+// It mirrors display.CursorMode of the newer periph.io/x/conn releases.
+type CursorMode int
+
+const (
+	CursorOff CursorMode = iota
+	CursorUnderline
+	CursorBlock
+	CursorBlink
+)
+
+// CursorDirection is the direction the cursor is moved to, see Dev.Move.
+//
+// It mirrors display.CursorDirection of the newer periph.io/x/conn releases.
+type CursorDirection int
+
+const (
+	Backward CursorDirection = iota
+	Forward
+	Down
+	Up
+)
+
+// AutoScroll enables or disables shifting the display instead of moving the
+// cursor when writing.
+func (r *Dev) AutoScroll(enabled bool) error {
+	if enabled {
+		r.entry |= entryShift
+	} else {
+		r.entry &^= entryShift
+	}
+	return r.writeInstruction(r.entry)
+}
+
+// Rows returns the number of lines of the display.
+func (r *Dev) Rows() int {
+	return r.rows
+}
+
+// Cols returns the number of characters per line of the display.
+func (r *Dev) Cols() int {
+	return r.cols
+}
+
+// MinRow returns the index of the first line, 0.
+func (r *Dev) MinRow() int {
+	return 0
+}
+
+// MinCol returns the index of the first column, 0.
+func (r *Dev) MinCol() int {
+	return 0
+}
+
+// Clear clears the display and moves the cursor home.
+func (r *Dev) Clear() error {
+	if err := r.Halt(); err != nil {
+		return err
+	}
+	r.row, r.col = 0, 0
+	return nil
+}
+
+// Home moves the cursor home without clearing the display.
+func (r *Dev) Home() error {
+	if err := r.writeInstruction(0x02); err != nil {
+		return err
+	}
+	delayMs(2)
+	r.row, r.col = 0, 0
+	return nil
+}
+
+// Cursor sets the cursor appearance. CursorBlock and CursorBlink both show a
+// blinking block, which is the only block cursor of the HD44780.
+func (r *Dev) Cursor(modes ...CursorMode) error {
+	c := r.control
+	for _, m := range modes {
+		switch m {
+		case CursorOff:
+			c &^= controlCursor | controlBlink
+		case CursorUnderline:
+			c |= controlCursor
+		case CursorBlock, CursorBlink:
+			c |= controlBlink
+		default:
+			return fmt.Errorf("hd44780: invalid cursor mode %d", m)
+		}
+	}
+	if err := r.writeInstruction(c); err != nil {
+		return err
+	}
+	r.control = c
+	return nil
+}
+
+// Display turns the display on or off, keeping its content.
+func (r *Dev) Display(on bool) error {
+	c := r.control
+	if on {
+		c |= controlDisplay
+	} else {
+		c &^= controlDisplay
+	}
+	if err := r.writeInstruction(c); err != nil {
+		return err
+	}
+	r.control = c
+	return nil
+}
+
+// Move moves the cursor by one position.
+func (r *Dev) Move(dir CursorDirection) error {
+	switch dir {
+	case Backward:
+		return r.MoveTo(r.row, r.col-1)
+	case Forward:
+		return r.MoveTo(r.row, r.col+1)
+	case Down:
+		return r.MoveTo(r.row+1, r.col)
+	case Up:
+		return r.MoveTo(r.row-1, r.col)
+	default:
+		return fmt.Errorf("hd44780: invalid cursor direction %d", dir)
+	}
+}
+
+// MoveTo moves the cursor to the 0-based row and col.
+func (r *Dev) MoveTo(row, col int) error {
+	if row < 0 || row >= r.rows || col < 0 || col >= r.cols {
+		return errors.New("hd44780: cursor position out of range")
+	}
+	// Lines 2 and 3 continue lines 0 and 1 in the display memory.
+	addr := (row%2)*lineTwo + (row/2)*r.cols + col
+	if err := r.writeInstruction(0x80 | uint8(addr)); err != nil {
+		return err
+	}
+	r.row, r.col = row, col
+	return nil
+}
+
+// Write writes p at the cursor position. It implements io.Writer.
+func (r *Dev) Write(p []byte) (int, error) {
+	for i, c := range p {
+		if err := r.WriteChar(c); err != nil {
+			return i, err
+		}
+	}
+	return len(p), nil
+}
+
+// WriteString writes text at the cursor position.
+func (r *Dev) WriteString(text string) (int, error) {
+	return r.Write([]byte(text))
+}
+
+// Display control and entry mode instruction bits.
+const (
+	controlDisplay = 0x04
+	controlCursor  = 0x02
+	controlBlink   = 0x01
+	entryShift     = 0x01
+)
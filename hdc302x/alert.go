@@ -0,0 +1,75 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package hdc302x
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"periph.io/x/conn/v3/gpio"
+)
+
+// alertPoll is how often the watcher checks if it must stop.
+var alertPoll = 100 * time.Millisecond
+
+// WatchAlerts waits for edges on the ALERT pin, connected to pin, and sends
+// the status word to the returned channel each time the pin changes. The
+// status is cleared after being read. This avoids polling the device to
+// detect the thresholds set with SetConfiguration being crossed.
+//
+// The pin is asserted when an alert becomes active and released when the
+// measurements are back within the clear thresholds, so the status is sent
+// in both cases. If an alert is already active when WatchAlerts is called,
+// its status is sent immediately.
+//
+// The device must be in continuous measurement mode, which is the case unless
+// Halt was called. The channel is closed once ctx is done.
+func (dev *Dev) WatchAlerts(ctx context.Context, pin gpio.PinIn) (<-chan StatusWord, error) {
+	if err := pin.In(gpio.PullNoChange, gpio.BothEdges); err != nil {
+		return nil, fmt.Errorf("hdc302x: %w", err)
+	}
+	// Clear the stale status; report an alert that is already active. The
+	// lock keeps ReadStatus from interleaving with Sense.
+	dev.mu.Lock()
+	initial, err := dev.ReadStatus()
+	dev.mu.Unlock()
+	if err != nil {
+		_ = pin.In(gpio.PullNoChange, gpio.NoEdge)
+		return nil, fmt.Errorf("hdc302x: %w", err)
+	}
+	ch := make(chan StatusWord, 16)
+	go func() {
+		defer close(ch)
+		defer func() { _ = pin.In(gpio.PullNoChange, gpio.NoEdge) }()
+		if initial&StatusActiveAlerts != 0 {
+			select {
+			case ch <- initial:
+			case <-ctx.Done():
+				return
+			}
+		}
+		for {
+			if ctx.Err() != nil {
+				return
+			}
+			if !pin.WaitForEdge(alertPoll) {
+				continue
+			}
+			dev.mu.Lock()
+			s, err := dev.ReadStatus()
+			dev.mu.Unlock()
+			if err != nil {
+				continue
+			}
+			select {
+			case ch <- s:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
@@ -21,6 +21,7 @@ import (
 	"periph.io/x/conn/v3"
 	"periph.io/x/conn/v3/i2c"
 	"periph.io/x/conn/v3/physic"
+	"periph.io/x/devices/v3"
 )
 
 type SampleRate uint16
@@ -37,15 +38,35 @@ const (
 	RateTwoHertz
 	RateFourHertz
 	Rate10Hertz
+	// RateOnDemand doesn't start the continuous measurements. The sensor sleeps
+	// between readings, and each call to Sense triggers a single measurement.
+	// Use it for battery powered applications.
+	RateOnDemand
+)
+
+// PowerMode selects the trade-off between noise and power consumption of the
+// measurements. LPM0 has the lowest noise, LPM3 the lowest power consumption.
+type PowerMode uint8
+
+const (
+	LPM0 PowerMode = iota
+	LPM1
+	LPM2
+	LPM3
 )
 
 // Dev represents a hdc302x sensor.
 type Dev struct {
-	d          *i2c.Dev
-	shutdown   chan struct{}
-	mu         sync.Mutex
+	d  *i2c.Dev
+	mu sync.Mutex
+	// stop is closed to end SenseContinuous, wg waits for its goroutine.
+	stop       chan struct{}
+	wg         sync.WaitGroup
 	sampleRate SampleRate
+	powerMode  PowerMode
 	halted     bool
+	// Communication health, reported by the devices.Health methods.
+	health devices.HealthCounter
 }
 
 // The alert function works with pairs of values Temperature/Humidity. A
@@ -134,9 +155,21 @@ var measure2xSecond = devCommand{0x22, 0x36}
 var measure4xSecond = devCommand{0x23, 0x34}
 var measure10xSecond = devCommand{0x27, 0x37}
 
-var sampleRateCommands = []devCommand{measure2Seconds, measureSecond, measure2xSecond, measure4xSecond, measure10xSecond}
+// Auto mode commands for each sample rate, in LPM0 to LPM3.
+var sampleRateCommands = [][4]devCommand{
+	{measure2Seconds, {0x20, 0x24}, {0x20, 0x2f}, {0x20, 0xff}},
+	{measureSecond, {0x21, 0x26}, {0x21, 0x2d}, {0x21, 0xff}},
+	{measure2xSecond, {0x22, 0x20}, {0x22, 0x2b}, {0x22, 0xff}},
+	{measure4xSecond, {0x23, 0x22}, {0x23, 0x29}, {0x23, 0xff}},
+	{measure10xSecond, {0x27, 0x21}, {0x27, 0x2a}, {0x27, 0xff}},
+}
 var sampleRateDurations = []time.Duration{2 * time.Second, time.Second, 500 * time.Millisecond, 250 * time.Millisecond, 100 * time.Millisecond}
 
+// Trigger on-demand commands, and the maximum measurement durations, in LPM0
+// to LPM3.
+var oneShotCommands = []devCommand{{0x24, 0x00}, {0x24, 0x0b}, {0x24, 0x16}, {0x24, 0xff}}
+var oneShotDurations = []time.Duration{12500 * time.Microsecond, 7500 * time.Microsecond, 5 * time.Millisecond, 3700 * time.Microsecond}
+
 // Other device commands
 var clearStatus = devCommand{0x30, 0x41}
 var disableHeater = devCommand{0x30, 0x66}
@@ -158,6 +191,10 @@ var writeLowAlertThresholds = devCommand{0x61, 0x00}
 var writeHighAlertThresholds = devCommand{0x61, 0x1d}
 var writeLowClearThresholds = devCommand{0x61, 0x0b}
 var writeHighClearThresholds = devCommand{0x61, 0x16}
+var transferThresholdsToNVM = devCommand{0x61, 0x55}
+
+// nvmProgramDuration is the time to wait for the EEPROM to be programmed.
+const nvmProgramDuration = 100 * time.Millisecond
 
 var errInvalidCRC = errors.New("hdc302x: invalid crc")
 
@@ -170,15 +207,23 @@ const (
 )
 
 // NewI2C returns a new HDC302x sensor using the specified bus, address, and
-// sample rate.
+// sample rate. With RateOnDemand, the sensor is left asleep until Sense is
+// called.
 func NewI2C(b i2c.Bus, addr uint16, sampleRate SampleRate) (*Dev, error) {
-	dev := &Dev{d: &i2c.Dev{Bus: b, Addr: addr}, shutdown: nil, sampleRate: sampleRate}
+	if sampleRate > RateOnDemand {
+		return nil, fmt.Errorf("hdc302x: invalid sample rate %d", sampleRate)
+	}
+	dev := &Dev{d: &i2c.Dev{Bus: b, Addr: addr}, sampleRate: sampleRate}
 	return dev, dev.start()
 }
 
 // send continuous measurement start command.
 func (dev *Dev) start() error {
-	if err := dev.d.Tx(sampleRateCommands[dev.sampleRate], nil); err != nil {
+	if dev.sampleRate == RateOnDemand {
+		dev.halted = true
+		return nil
+	}
+	if err := dev.tx(sampleRateCommands[dev.sampleRate][dev.powerMode], nil); err != nil {
 		return fmt.Errorf("hdc302x: init %w", err)
 	}
 	// Sleep for a minimum of one sample acquisition period. If you
@@ -219,17 +264,23 @@ func crc8(bytes []byte) byte {
 }
 
 // Halt shuts down the device. If a SenseContinuous operation is in progress,
-// its aborted. Implements conn.Resource
+// it is aborted and its channel is closed before Halt returns; SenseContinuous
+// can then be called again. Implements conn.Resource
 func (dev *Dev) Halt() error {
 	dev.mu.Lock()
-	defer dev.mu.Unlock()
-	if dev.shutdown != nil {
-		close(dev.shutdown)
+	stop := dev.stop
+	dev.stop = nil
+	dev.mu.Unlock()
+	if stop != nil {
+		close(stop)
+		dev.wg.Wait()
 	}
+	dev.mu.Lock()
+	defer dev.mu.Unlock()
 	var err error
 	if !dev.halted {
 		dev.halted = true
-		err = dev.d.Tx(stopContinuousReadings, nil)
+		err = dev.tx(stopContinuousReadings, nil)
 	}
 	return err
 }
@@ -243,16 +294,19 @@ func (dev *Dev) Sense(env *physic.Env) error {
 	res := make([]byte, 6)
 	dev.mu.Lock()
 	defer dev.mu.Unlock()
+	if dev.sampleRate == RateOnDemand {
+		return dev.oneShot(dev.powerMode, env)
+	}
 	if dev.halted {
 		if err := dev.start(); err != nil {
 			return err
 		}
 	}
-	if err := dev.d.Tx(read, res); err != nil {
+	if err := dev.tx(read, res); err != nil {
 		return fmt.Errorf("hdc302x: %w", err)
 	}
 	if crc8(res[:2]) != res[2] || crc8(res[3:5]) != res[5] {
-		return errInvalidCRC
+		return dev.health.RecordCRCError(errInvalidCRC)
 	}
 	env.Temperature = countToTemperature(res)
 	env.Humidity = countToHumidity(res[3:])
@@ -273,30 +327,39 @@ func humidityToFloat64(humidity physic.RelativeHumidity) float64 {
 //
 // If interval is less than the device sample period, an error is returned.
 func (dev *Dev) SenseContinuous(interval time.Duration) (<-chan physic.Env, error) {
-
-	if dev.shutdown != nil {
+	dev.mu.Lock()
+	defer dev.mu.Unlock()
+	if dev.stop != nil {
 		return nil, errors.New("hdc302x: SenseContinuous already running")
 	}
 
-	if interval < sampleRateDurations[dev.sampleRate] {
+	if dev.sampleRate != RateOnDemand && interval < sampleRateDurations[dev.sampleRate] {
 		return nil, errors.New("hdc302x: sample interval is < device sample rate")
 	}
 
-	dev.shutdown = make(chan struct{})
+	stop := make(chan struct{})
+	dev.stop = stop
 	chResult := make(chan physic.Env, 16)
+	dev.wg.Add(1)
 	go func(ch chan physic.Env) {
+		defer dev.wg.Done()
+		defer close(ch)
 		ticker := time.NewTicker(interval)
 		defer ticker.Stop()
-		defer close(ch)
 		for {
 			select {
-			case <-dev.shutdown:
-				dev.shutdown = nil
+			case <-stop:
 				return
 			case <-ticker.C:
 				env := physic.Env{}
-				if err := dev.Sense(&env); err == nil {
-					ch <- env
+				if err := dev.Sense(&env); err != nil {
+					continue
+				}
+				// Don't block Halt if the reader is gone.
+				select {
+				case ch <- env:
+				case <-stop:
+					return
 				}
 			}
 		}
@@ -304,6 +367,69 @@ func (dev *Dev) SenseContinuous(interval time.Duration) (<-chan physic.Env, erro
 	return chResult, nil
 }
 
+// OneShot triggers a single measurement in the specified power mode and writes
+// the result to env. The sensor goes back to sleep once the measurement is
+// done.
+//
+// If continuous measurements are running, they are stopped first; with a sample
+// rate other than RateOnDemand, the next call to Sense restarts them.
+func (dev *Dev) OneShot(mode PowerMode, env *physic.Env) error {
+	if mode > LPM3 {
+		return fmt.Errorf("hdc302x: invalid power mode %d", mode)
+	}
+	dev.mu.Lock()
+	defer dev.mu.Unlock()
+	if !dev.halted {
+		if err := dev.tx(stopContinuousReadings, nil); err != nil {
+			return fmt.Errorf("hdc302x: %w", err)
+		}
+		dev.halted = true
+	}
+	return dev.oneShot(mode, env)
+}
+
+// SetPowerMode sets the power mode used for the continuous measurements, or by
+// Sense with RateOnDemand. The default is LPM0. Running continuous measurements
+// are restarted in the new mode.
+func (dev *Dev) SetPowerMode(mode PowerMode) error {
+	if mode > LPM3 {
+		return fmt.Errorf("hdc302x: invalid power mode %d", mode)
+	}
+	dev.mu.Lock()
+	defer dev.mu.Unlock()
+	dev.powerMode = mode
+	if dev.halted {
+		return nil
+	}
+	// The auto mode must be exited before being changed.
+	if err := dev.tx(stopContinuousReadings, nil); err != nil {
+		return fmt.Errorf("hdc302x: %w", err)
+	}
+	dev.halted = true
+	return dev.start()
+}
+
+// oneShot triggers a measurement and reads the result. dev.mu must be held.
+func (dev *Dev) oneShot(mode PowerMode, env *physic.Env) error {
+	env.Temperature = 0
+	env.Pressure = 0
+	env.Humidity = 0
+	if err := dev.tx(oneShotCommands[mode], nil); err != nil {
+		return fmt.Errorf("hdc302x: %w", err)
+	}
+	time.Sleep(oneShotDurations[mode])
+	res := make([]byte, 6)
+	if err := dev.tx(nil, res); err != nil {
+		return fmt.Errorf("hdc302x: %w", err)
+	}
+	if crc8(res[:2]) != res[2] || crc8(res[3:5]) != res[5] {
+		return dev.health.RecordCRCError(errInvalidCRC)
+	}
+	env.Temperature = countToTemperature(res)
+	env.Humidity = countToHumidity(res[3:])
+	return nil
+}
+
 // Precision returns the sensor's precision, or minimum value between steps the
 // device can measure. Refer to the datasheet for information on limits and
 // accuracy.
@@ -319,8 +445,11 @@ func (dev *Dev) readSerialNumber() int64 {
 	r := make([]byte, 3)
 	// this is a 6 byte value read in 3 parts
 	for range 3 {
-		err := dev.d.Tx(cmd, r)
-		if err != nil || (crc8(r[:2]) != r[2]) {
+		if err := dev.tx(cmd, r); err != nil {
+			return result
+		}
+		if crc8(r[:2]) != r[2] {
+			_ = dev.health.RecordCRCError(errInvalidCRC)
 			return result
 		}
 		result = result<<16 | (int64(r[0])<<8 | int64(r[1]))
@@ -354,12 +483,12 @@ func (dev *Dev) readAlertValues(cfg *Configuration) error {
 			threshold = &pair.High
 		}
 
-		err := dev.d.Tx(cmd, r)
+		err := dev.tx(cmd, r)
 		if err != nil {
 			return err
 		}
 		if crc8(r[:2]) != r[2] {
-			return errInvalidCRC
+			return dev.health.RecordCRCError(errInvalidCRC)
 		}
 		wValue := uint16(r[0])<<8 | uint16(r[1])
 		// The alert value is returned as a 16 bit words, where bits 0-8 are the
@@ -378,11 +507,11 @@ func (dev *Dev) readAlertValues(cfg *Configuration) error {
 // readOffsets returns temperature/humidity offset values stored to the device.
 func (dev *Dev) readOffsets(cfg *Configuration) error {
 	r := make([]byte, 3)
-	if err := dev.d.Tx(readSetOffsets, r); err != nil {
+	if err := dev.tx(readSetOffsets, r); err != nil {
 		return fmt.Errorf("hdc302x: %w", err)
 	}
 	if crc8(r[:2]) != r[2] {
-		return errInvalidCRC
+		return dev.health.RecordCRCError(errInvalidCRC)
 	}
 
 	// The result comes back as the humidity offset, followed by
@@ -409,7 +538,7 @@ func (dev *Dev) readOffsets(cfg *Configuration) error {
 
 func (dev *Dev) readVendorID() (uint16, error) {
 	r := make([]byte, 3)
-	err := dev.d.Tx(readVendorID, r)
+	err := dev.tx(readVendorID, r)
 	if err == nil {
 		vid := uint16(r[0])<<8 | uint16(r[1])
 		return vid, nil
@@ -421,13 +550,13 @@ func (dev *Dev) readVendorID() (uint16, error) {
 // status. Refer to the Status* constants and the datasheet for interpretation.
 func (dev *Dev) ReadStatus() (StatusWord, error) {
 	r := make([]byte, 3)
-	if err := dev.d.Tx(readStatus, r); err != nil {
+	if err := dev.tx(readStatus, r); err != nil {
 		return 0, err
 	}
 	if crc8(r[:2]) != r[2] {
-		return 0, errInvalidCRC
+		return 0, dev.health.RecordCRCError(errInvalidCRC)
 	}
-	_ = dev.d.Tx(clearStatus, nil)
+	_ = dev.tx(clearStatus, nil)
 	return StatusWord(r[0])<<8 | StatusWord(r[1]), nil
 }
 
@@ -464,7 +593,7 @@ func (dev *Dev) setOffsets(cfg *Configuration) error {
 		0,
 	}
 	w[4] = crc8(w[2:4])
-	return dev.d.Tx(w, nil)
+	return dev.tx(w, nil)
 }
 
 // Refer to the datasheet. Essentially, the offsets are only a specific set of
@@ -509,7 +638,7 @@ func computeHumidityOffsetByte(humidity physic.RelativeHumidity) byte {
 func (dev *Dev) Reset() error {
 	dev.mu.Lock()
 	defer dev.mu.Unlock()
-	err := dev.d.Tx(reset, nil)
+	err := dev.tx(reset, nil)
 	time.Sleep(time.Second)
 	return err
 }
@@ -554,7 +683,7 @@ func (dev *Dev) setThresholds(typeAlert bool, tp *ThresholdPair) error {
 		wval = (humBits & 0xfe00) | tempBits>>7
 		w := []byte{cmds[pair][ix][0], cmds[pair][ix][1], byte(wval >> 8), byte(wval & 0xff), 0}
 		w[4] = crc8(w[2:4])
-		err := dev.d.Tx(w, nil)
+		err := dev.tx(w, nil)
 		if err != nil {
 			return err
 		}
@@ -593,6 +722,31 @@ func (dev *Dev) SetConfiguration(cfg *Configuration) error {
 	return nil
 }
 
+// Persist stores the alert and clear thresholds set with SetConfiguration in
+// the device's non-volatile memory, so they are restored after a power cycle or
+// a Reset. The offsets are always stored in non-volatile memory when set, and
+// don't need to be persisted.
+//
+// The EEPROM has a limited number of write cycles, so only call Persist when
+// the thresholds changed. Continuous measurements are stopped while the EEPROM
+// is programmed; the next call to Sense restarts them.
+func (dev *Dev) Persist() error {
+	dev.mu.Lock()
+	defer dev.mu.Unlock()
+	// The EEPROM can only be programmed in sleep mode.
+	if !dev.halted {
+		if err := dev.tx(stopContinuousReadings, nil); err != nil {
+			return fmt.Errorf("hdc302x: %w", err)
+		}
+		dev.halted = true
+	}
+	if err := dev.tx(transferThresholdsToNVM, nil); err != nil {
+		return fmt.Errorf("hdc302x: %w", err)
+	}
+	time.Sleep(nvmProgramDuration)
+	return nil
+}
+
 // The hdc302x sensors have a built in heater element for operating in environments
 // where the humidity/temperature level is condensing. SetHeater allows you to turn
 // the heater element on and off at specified power levels.  Refer to the datasheet
@@ -602,7 +756,7 @@ func (dev *Dev) SetHeater(powerLevel HeaterPower) error {
 		return fmt.Errorf("hdc302x: invalid value for powerLevel: 0x%x", powerLevel)
 	}
 	if powerLevel == PowerOff {
-		return dev.d.Tx(disableHeater, nil)
+		return dev.tx(disableHeater, nil)
 	}
 	var setValue = []byte{readSetHeater[0],
 		readSetHeater[1],
@@ -610,17 +764,37 @@ func (dev *Dev) SetHeater(powerLevel HeaterPower) error {
 		byte(powerLevel & 0xff),
 		0}
 	setValue[4] = crc8(setValue[2:4])
-	err := dev.d.Tx(setValue, nil)
+	err := dev.tx(setValue, nil)
 	if err != nil {
 		return err
 	}
-	return dev.d.Tx(enableHeater, nil)
+	return dev.tx(enableHeater, nil)
 }
 
 func (dev *Dev) String() string {
 	return fmt.Sprintf("hdc302x: %s", dev.d.String())
 }
 
+// LastError implements devices.Health.
+func (dev *Dev) LastError() error {
+	return dev.health.LastError()
+}
+
+// TxCount implements devices.Health.
+func (dev *Dev) TxCount() uint64 {
+	return dev.health.TxCount()
+}
+
+// CRCErrorCount implements devices.Health.
+func (dev *Dev) CRCErrorCount() uint64 {
+	return dev.health.CRCErrorCount()
+}
+
+// tx runs a transaction on the bus and records it.
+func (dev *Dev) tx(w, r []byte) error {
+	return dev.health.Record(dev.d.Tx(w, r))
+}
+
 func (cfg *Configuration) String() string {
 	return fmt.Sprintf(`{
 		SerialNumber: 0x%x, 
@@ -677,3 +851,4 @@ func (tp *ThresholdPair) Equals(tpCompare *ThresholdPair) bool {
 
 var _ conn.Resource = &Dev{}
 var _ physic.SenseEnv = &Dev{}
+var _ devices.Health = &Dev{}
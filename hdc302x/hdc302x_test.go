@@ -5,6 +5,7 @@
 package hdc302x
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"os"
@@ -13,6 +14,8 @@ import (
 	"testing"
 	"time"
 
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpiotest"
 	"periph.io/x/conn/v3/i2c"
 	"periph.io/x/conn/v3/i2c/i2creg"
 	"periph.io/x/conn/v3/i2c/i2ctest"
@@ -618,3 +621,193 @@ func TestHeater(t *testing.T) {
 		t.Errorf("expected heater to increase sensor temperature. Initial: %s Final: %s", env.Temperature, env2.Temperature)
 	}
 }
+
+// statusIO returns the playback to read and clear a status word.
+func statusIO(s StatusWord) []i2ctest.IO {
+	r := []byte{byte(s >> 8), byte(s)}
+	return []i2ctest.IO{
+		{Addr: DefaultSensorAddress, W: readStatus, R: append(r, crc8(r))},
+		{Addr: DefaultSensorAddress, W: clearStatus},
+	}
+}
+
+func TestWatchAlerts(t *testing.T) {
+	if liveDevice {
+		t.Skip("requires the ALERT pin to be connected")
+	}
+	alertPoll = time.Millisecond
+	alert := StatusActiveAlerts | StatusRHTrackingAlert | StatusRHLowTrackingAlert
+	ops := []i2ctest.IO{{Addr: DefaultSensorAddress, W: measure4xSecond}}
+	ops = append(ops, statusIO(alert)...)
+	ops = append(ops, statusIO(alert)...)
+	ops = append(ops, statusIO(0)...)
+	pb := &i2ctest.Playback{Ops: ops, DontPanic: true}
+	dev, err := NewI2C(pb, DefaultSensorAddress, RateFourHertz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pin := &gpiotest.Pin{N: "ALERT", EdgesChan: make(chan gpio.Level)}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch, err := dev.WatchAlerts(ctx, pin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The alert already active is reported immediately.
+	if s := <-ch; s != alert {
+		t.Fatalf("got %#x, want %#x", s, alert)
+	}
+	pin.EdgesChan <- gpio.High
+	if s := <-ch; s != alert {
+		t.Fatalf("got %#x, want %#x", s, alert)
+	}
+	pin.EdgesChan <- gpio.Low
+	if s := <-ch; s != 0 {
+		t.Fatalf("got %#x, want 0", s)
+	}
+	cancel()
+	if _, ok := <-ch; ok {
+		t.Fatal("expected channel to be closed")
+	}
+	if err := pb.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOneShot(t *testing.T) {
+	if liveDevice {
+		t.Skip("uses its own playback")
+	}
+	r := []uint8{0x68, 0xc5, 0x51, 0x3b, 0x82, 0x31}
+	pb := &i2ctest.Playback{Ops: []i2ctest.IO{
+		{Addr: DefaultSensorAddress, W: []uint8{0x24, 0x00}},
+		{Addr: DefaultSensorAddress, R: r},
+		{Addr: DefaultSensorAddress, W: []uint8{0x24, 0xff}},
+		{Addr: DefaultSensorAddress, R: r},
+		{Addr: DefaultSensorAddress, W: []uint8{0x24, 0x16}},
+		{Addr: DefaultSensorAddress, R: r},
+	}, DontPanic: true}
+	if _, err := NewI2C(pb, DefaultSensorAddress, RateOnDemand+1); err == nil {
+		t.Error("expected error for invalid sample rate")
+	}
+	dev, err := NewI2C(pb, DefaultSensorAddress, RateOnDemand)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := physic.Env{}
+	if err := dev.Sense(&env); err != nil {
+		t.Fatal(err)
+	}
+	if env.Temperature != 299770889600 || env.Humidity != 2324559*physic.TenthMicroRH {
+		t.Errorf("unexpected reading %s %s", env.Temperature, env.Humidity)
+	}
+	if err := dev.OneShot(LPM3, &env); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.OneShot(LPM3+1, &env); err == nil {
+		t.Error("expected error for invalid power mode")
+	}
+	if err := dev.SetPowerMode(LPM2); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.Sense(&env); err != nil {
+		t.Fatal(err)
+	}
+	// Nothing to stop.
+	if err := dev.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	if err := pb.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOneShotFromAutoMode(t *testing.T) {
+	if liveDevice {
+		t.Skip("uses its own playback")
+	}
+	pb := &i2ctest.Playback{Ops: []i2ctest.IO{
+		{Addr: DefaultSensorAddress, W: []uint8{0x23, 0x34}},
+		{Addr: DefaultSensorAddress, W: []uint8{0x30, 0x93}},
+		{Addr: DefaultSensorAddress, W: []uint8{0x23, 0x22}},
+		{Addr: DefaultSensorAddress, W: []uint8{0x30, 0x93}},
+		{Addr: DefaultSensorAddress, W: []uint8{0x24, 0x0b}},
+		{Addr: DefaultSensorAddress, R: []uint8{0x68, 0xc5, 0x51, 0x3b, 0x82, 0x31}},
+		{Addr: DefaultSensorAddress, W: []uint8{0x23, 0x22}},
+		{Addr: DefaultSensorAddress, W: []uint8{0xe0, 0x0}, R: []uint8{0x68, 0xc5, 0x51, 0x3b, 0x82, 0x31}},
+	}, DontPanic: true}
+	dev, err := NewI2C(pb, DefaultSensorAddress, RateFourHertz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The auto mode is restarted in the new power mode.
+	if err := dev.SetPowerMode(LPM1); err != nil {
+		t.Fatal(err)
+	}
+	env := physic.Env{}
+	if err := dev.OneShot(LPM1, &env); err != nil {
+		t.Fatal(err)
+	}
+	// The next Sense restarts the auto mode.
+	if err := dev.Sense(&env); err != nil {
+		t.Fatal(err)
+	}
+	if err := pb.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPersist(t *testing.T) {
+	if liveDevice {
+		t.Skip("writes to the device's EEPROM")
+	}
+	pb := &i2ctest.Playback{Ops: []i2ctest.IO{
+		{Addr: DefaultSensorAddress, W: []uint8{0x23, 0x34}},
+		{Addr: DefaultSensorAddress, W: []uint8{0x30, 0x93}},
+		{Addr: DefaultSensorAddress, W: []uint8{0x61, 0x55}},
+		{Addr: DefaultSensorAddress, W: []uint8{0x61, 0x55}},
+	}, DontPanic: true}
+	dev, err := NewI2C(pb, DefaultSensorAddress, RateFourHertz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.Persist(); err != nil {
+		t.Fatal(err)
+	}
+	// Already in sleep mode.
+	if err := dev.Persist(); err != nil {
+		t.Fatal(err)
+	}
+	if err := pb.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSenseContinuous_Restart(t *testing.T) {
+	if liveDevice {
+		t.Skip("uses playback")
+	}
+	stop := i2ctest.IO{Addr: DefaultSensorAddress,
+		W: []uint8{stopContinuousReadings[0], stopContinuousReadings[1]}}
+	pb := []i2ctest.IO{pbSense[0], pbSense[1], stop, pbSense[0], pbSense[1], stop}
+	dev, err := getDev(t, pb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		ch, err := dev.SenseContinuous(250 * time.Millisecond)
+		if err != nil {
+			t.Fatal(i, err)
+		}
+		if _, ok := <-ch; !ok {
+			t.Fatal(i, "channel closed early")
+		}
+		if err := dev.Halt(); err != nil {
+			t.Fatal(i, err)
+		}
+		// The channel is closed once Halt returns.
+		if _, ok := <-ch; ok {
+			t.Fatal(i, "expected closed channel")
+		}
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package devices
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Health is optionally implemented by device drivers to report the health of
+// the communication with the device.
+//
+// It lets long running applications monitor many devices uniformly without
+// wrapping every call.
+type Health interface {
+	// LastError returns the last communication error, or nil if none
+	// occurred.
+	LastError() error
+	// TxCount returns the number of transactions with the device, including
+	// the failed ones.
+	TxCount() uint64
+	// CRCErrorCount returns the number of replies that failed their CRC
+	// check.
+	CRCErrorCount() uint64
+}
+
+// HealthCounter implements Health for device drivers.
+//
+// Drivers keep it as an unexported field, call Record after each transaction
+// and forward the Health methods to it. The zero value is ready to use and it
+// is safe for concurrent use.
+type HealthCounter struct {
+	tx  atomic.Uint64
+	crc atomic.Uint64
+
+	mu  sync.Mutex
+	err error
+}
+
+// Record counts a transaction and records err if not nil. It returns err.
+func (h *HealthCounter) Record(err error) error {
+	h.tx.Add(1)
+	if err != nil {
+		h.setErr(err)
+	}
+	return err
+}
+
+// RecordCRCError counts a reply that failed its CRC check and records err.
+// It returns err.
+//
+// The transaction itself must already have been counted with Record.
+func (h *HealthCounter) RecordCRCError(err error) error {
+	h.crc.Add(1)
+	h.setErr(err)
+	return err
+}
+
+// LastError implements Health.
+func (h *HealthCounter) LastError() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.err
+}
+
+// TxCount implements Health.
+func (h *HealthCounter) TxCount() uint64 {
+	return h.tx.Load()
+}
+
+// CRCErrorCount implements Health.
+func (h *HealthCounter) CRCErrorCount() uint64 {
+	return h.crc.Load()
+}
+
+//
+
+func (h *HealthCounter) setErr(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.err = err
+}
+
+var _ Health = &HealthCounter{}
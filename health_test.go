@@ -0,0 +1,37 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package devices
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestHealthCounter(t *testing.T) {
+	h := HealthCounter{}
+	if h.LastError() != nil || h.TxCount() != 0 || h.CRCErrorCount() != 0 {
+		t.Fatal("zero value")
+	}
+	if err := h.Record(nil); err != nil {
+		t.Fatal(err)
+	}
+	errBus := errors.New("bus")
+	if err := h.Record(errBus); err != errBus {
+		t.Fatal(err)
+	}
+	if err := h.Record(nil); err != nil {
+		t.Fatal(err)
+	}
+	if h.LastError() != errBus {
+		t.Fatal("the last error is kept after a success")
+	}
+	errCRC := errors.New("crc")
+	if err := h.RecordCRCError(errCRC); err != errCRC {
+		t.Fatal(err)
+	}
+	if h.LastError() != errCRC || h.TxCount() != 3 || h.CRCErrorCount() != 1 {
+		t.Fatal(h.LastError(), h.TxCount(), h.CRCErrorCount())
+	}
+}
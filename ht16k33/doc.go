@@ -6,6 +6,11 @@
 //
 // # More Details
 //
+// The LED backpacks are supported by Display for the 14-segment alphanumeric
+// ones, SevenSegment for the 4 digit 7-segment ones and Matrix, which
+// implements display.Drawer, for the 8x8 and 16x8 matrixes. Dev.ReadKeys reads
+// the keys scanned by the chip.
+//
 // # Datasheets
 //
 // http://www.holtek.com/documents/10179/116711/HT16K33v120.pdf
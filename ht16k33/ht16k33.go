@@ -6,6 +6,7 @@ package ht16k33
 
 import (
 	"errors"
+	"fmt"
 
 	"periph.io/x/conn/v3/i2c"
 )
@@ -54,6 +55,10 @@ func NewI2C(bus i2c.Bus, address uint16) (*Dev, error) {
 	return dev, nil
 }
 
+func (d *Dev) String() string {
+	return fmt.Sprintf("HT16K33{%s}", &d.dev)
+}
+
 func (d *Dev) init() error {
 	// Turn on the oscillator.
 	if _, err := d.dev.Write([]byte{systemSetup | oscillatorOn}); err != nil {
@@ -97,6 +102,23 @@ func (d *Dev) WriteColumn(column int, data uint16) error {
 	return err
 }
 
+// ReadKeys returns the state of the keys scanned on the KS0, KS1 and KS2
+// lines. Bit n of keys[i] is set if the key on ROW n and KSi is pressed. Only
+// ROW0 to ROW12 can be used for key scanning.
+//
+// The keys are scanned continuously while the oscillator runs.
+func (d *Dev) ReadKeys() ([3]uint16, error) {
+	var keys [3]uint16
+	var b [6]byte
+	if err := d.dev.Tx([]byte{cmdKeys}, b[:]); err != nil {
+		return keys, err
+	}
+	for i := range keys {
+		keys[i] = (uint16(b[2*i]) | uint16(b[2*i+1])<<8) & 0x1FFF
+	}
+	return keys, nil
+}
+
 // Halt clear the contents of display buffer.
 func (d *Dev) Halt() error {
 	for i := 0; i < 4; i++ {
@@ -0,0 +1,100 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ht16k33
+
+import (
+	"image"
+	"testing"
+
+	"periph.io/x/conn/v3/i2c/i2ctest"
+	"periph.io/x/devices/v3/ssd1306/image1bit"
+)
+
+var initOps = []i2ctest.IO{
+	{Addr: I2CAddr, W: []byte{0x21}},
+	{Addr: I2CAddr, W: []byte{0x81}},
+	{Addr: I2CAddr, W: []byte{0x81}},
+	{Addr: I2CAddr, W: []byte{0xef}},
+}
+
+func TestReadKeys(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: append(initOps,
+			i2ctest.IO{Addr: I2CAddr, W: []byte{0x40}, R: []byte{0x01, 0xe0, 0x00, 0x10, 0x02, 0x00}},
+		),
+	}
+	dev, err := NewI2C(&bus, I2CAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := dev.String(); s != "HT16K33{playback(112)}" {
+		t.Fatal(s)
+	}
+	keys, err := dev.ReadKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keys != [3]uint16{0x0001, 0x1000, 0x0002} {
+		t.Fatalf("unexpected keys %#v", keys)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSevenSegment_WriteString(t *testing.T) {
+	ops := append([]i2ctest.IO{}, initOps...)
+	for i := 0; i < 10; i += 2 {
+		ops = append(ops, i2ctest.IO{Addr: I2CAddr, W: []byte{byte(i), 0, 0}})
+	}
+	ops = append(ops,
+		i2ctest.IO{Addr: I2CAddr, W: []byte{0x02, 0x06, 0}},    // 1
+		i2ctest.IO{Addr: I2CAddr, W: []byte{0x06, 0xdb, 0}},    // 2.
+		i2ctest.IO{Addr: I2CAddr, W: []byte{0x08, 0x4f, 0}},    // 3
+		i2ctest.IO{Addr: I2CAddr, W: []byte{0x04, 0x02, 0x00}}, // Colon
+	)
+	bus := i2ctest.Playback{Ops: ops}
+	s, err := NewSevenSegmentDisplay(&bus, I2CAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := s.WriteString("1:2.3"); err != nil || n != 4 {
+		t.Fatal(n, err)
+	}
+	if err := s.SetDigit(4, '1', false); err == nil {
+		t.Fatal("expected error for position 4")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMatrix_Draw(t *testing.T) {
+	ops := append([]i2ctest.IO{}, initOps...)
+	ops = append(ops,
+		i2ctest.IO{Addr: I2CAddr, W: []byte{0x00, 0x01, 0x80}},
+		i2ctest.IO{Addr: I2CAddr, W: []byte{0x02, 0x00, 0x00}},
+	)
+	bus := i2ctest.Playback{Ops: ops}
+	if _, err := NewMatrix(&bus, I2CAddr, 8, 16); err == nil {
+		t.Fatal("expected error for height 16")
+	}
+	m, err := NewMatrix(&bus, I2CAddr, 16, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := m.Bounds(); b != image.Rect(0, 0, 16, 8) {
+		t.Fatal(b)
+	}
+	img := image1bit.NewVerticalLSB(m.Bounds())
+	img.SetBit(0, 0, image1bit.On)
+	img.SetBit(15, 0, image1bit.On)
+	if err := m.Draw(image.Rect(0, 0, 16, 2), img, image.Point{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ht16k33
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+
+	"periph.io/x/conn/v3/display"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/devices/v3/ssd1306/image1bit"
+)
+
+// Matrix is a handler to control a LED matrix based on ht16k33, like the
+// Adafruit 8x8 and 16x8 matrix backpacks. It implements display.Drawer.
+//
+// The pixel at (x, y) is the LED connected to ROWx and COMy.
+type Matrix struct {
+	dev   *Dev
+	rect  image.Rectangle
+	frame *image1bit.VerticalLSB
+}
+
+// NewMatrix returns a Matrix object that communicates over I2C to ht16k33.
+// width must be 8 or 16 and height must be 8.
+//
+// To use on the default address, ht16k33.I2CAddr must be passed as argument.
+func NewMatrix(bus i2c.Bus, address uint16, width, height int) (*Matrix, error) {
+	if width != 8 && width != 16 {
+		return nil, errors.New("ht16k33: matrix width must be 8 or 16")
+	}
+	if height != 8 {
+		return nil, errors.New("ht16k33: matrix height must be 8")
+	}
+	dev, err := NewI2C(bus, address)
+	if err != nil {
+		return nil, err
+	}
+	rect := image.Rect(0, 0, width, height)
+	return &Matrix{dev: dev, rect: rect, frame: image1bit.NewVerticalLSB(rect)}, nil
+}
+
+func (m *Matrix) String() string {
+	return fmt.Sprintf("ht16k33.Matrix{%s, %s}", m.dev, m.rect.Max)
+}
+
+// ColorModel implements display.Drawer.
+// It is a one bit color model, as implemented by image1bit.Bit.
+func (m *Matrix) ColorModel() color.Model {
+	return image1bit.BitModel
+}
+
+// Bounds implements display.Drawer. Min is guaranteed to be {0, 0}.
+func (m *Matrix) Bounds() image.Rectangle {
+	return m.rect
+}
+
+// Draw implements display.Drawer.
+//
+// Only the pixels in r are changed, the Matrix keeps the rest.
+func (m *Matrix) Draw(r image.Rectangle, src image.Image, sp image.Point) error {
+	r = r.Intersect(m.rect)
+	if r.Empty() {
+		return nil
+	}
+	draw.Src.Draw(m.frame, r, src, sp)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		var row uint16
+		for x := 0; x < m.rect.Dx(); x++ {
+			if m.frame.BitAt(x, y) {
+				row |= 1 << uint(x)
+			}
+		}
+		if err := m.dev.WriteColumn(y, row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetBlink Blink display at specified frequency.
+func (m *Matrix) SetBlink(freq BlinkFrequency) error {
+	return m.dev.SetBlink(freq)
+}
+
+// SetBrightness of entire display to specified value.
+//
+// Supports 16 levels, from 0 to 15.
+func (m *Matrix) SetBrightness(brightness int) error {
+	return m.dev.SetBrightness(brightness)
+}
+
+// Halt clear all the display.
+func (m *Matrix) Halt() error {
+	m.frame = image1bit.NewVerticalLSB(m.rect)
+	for y := 0; y < m.rect.Dy(); y++ {
+		if err := m.dev.WriteColumn(y, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var _ display.Drawer = &Matrix{}
@@ -0,0 +1,157 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ht16k33
+
+import (
+	"errors"
+
+	"periph.io/x/conn/v3/i2c"
+)
+
+// segmentValues is the PGFEDCBA pattern of the characters that can be
+// represented on a 7-segment digit.
+var segmentValues = map[rune]uint16{
+	' ':  0x00,
+	'"':  0x22,
+	'\'': 0x02,
+	'-':  0x40,
+	'=':  0x48,
+	'_':  0x08,
+	'0':  0x3f,
+	'1':  0x06,
+	'2':  0x5b,
+	'3':  0x4f,
+	'4':  0x66,
+	'5':  0x6d,
+	'6':  0x7d,
+	'7':  0x07,
+	'8':  0x7f,
+	'9':  0x6f,
+	'A':  0x77,
+	'B':  0x7c,
+	'C':  0x39,
+	'D':  0x5e,
+	'E':  0x79,
+	'F':  0x71,
+	'H':  0x76,
+	'L':  0x38,
+	'O':  0x3f,
+	'P':  0x73,
+	'U':  0x3e,
+	'b':  0x7c,
+	'c':  0x58,
+	'd':  0x5e,
+	'h':  0x74,
+	'n':  0x54,
+	'o':  0x5c,
+	'r':  0x50,
+	't':  0x78,
+	'u':  0x1c,
+}
+
+// segmentColumns are the columns of the 4 digits of the Adafruit 7-segment
+// backpack. The colon is in column 2.
+var segmentColumns = [4]int{0, 1, 3, 4}
+
+const (
+	segmentDecimal = 0x80
+	colonColumn    = 2
+	colonOn        = 0x02
+)
+
+// SevenSegment is a handler to control a 4 digit 7-segment display based on
+// ht16k33, like the Adafruit 7-segment backpack.
+type SevenSegment struct {
+	dev *Dev
+}
+
+// NewSevenSegmentDisplay returns a SevenSegment object that communicates over
+// I2C to ht16k33.
+//
+// To use on the default address, ht16k33.I2CAddr must be passed as argument.
+func NewSevenSegmentDisplay(bus i2c.Bus, address uint16) (*SevenSegment, error) {
+	dev, err := NewI2C(bus, address)
+	if err != nil {
+		return nil, err
+	}
+	return &SevenSegment{dev: dev}, nil
+}
+
+// SetDigit at position to provided value.
+//
+// Characters that cannot be represented on 7 segments are left blank.
+func (s *SevenSegment) SetDigit(pos int, digit rune, decimal bool) error {
+	if pos < 0 || pos >= len(segmentColumns) {
+		return errors.New("ht16k33: digit position must be between 0 and 3")
+	}
+	val := segmentValues[digit]
+	if val == 0 && digit >= 'a' && digit <= 'z' {
+		val = segmentValues[digit-'a'+'A']
+	} else if val == 0 && digit >= 'A' && digit <= 'Z' {
+		val = segmentValues[digit-'A'+'a']
+	}
+	if decimal {
+		val |= segmentDecimal
+	}
+	return s.dev.WriteColumn(segmentColumns[pos], val)
+}
+
+// SetColon turns the colon between the second and third digits on or off.
+func (s *SevenSegment) SetColon(on bool) error {
+	var val uint16
+	if on {
+		val = colonOn
+	}
+	return s.dev.WriteColumn(colonColumn, val)
+}
+
+// WriteString print string of values to the display, right aligned.
+//
+// A '.' turns on the decimal point of the previous digit and a ':' turns on
+// the colon. Only the last 4 digits are displayed.
+func (s *SevenSegment) WriteString(str string) (int, error) {
+	type digit struct {
+		c       rune
+		decimal bool
+	}
+	var digits []digit
+	colon := false
+	for _, ch := range str {
+		switch {
+		case ch == ':':
+			colon = true
+		case ch == '.' && len(digits) != 0 && !digits[len(digits)-1].decimal:
+			digits[len(digits)-1].decimal = true
+		case ch == '.':
+			digits = append(digits, digit{' ', true})
+		default:
+			digits = append(digits, digit{ch, false})
+		}
+	}
+	if len(digits) > len(segmentColumns) {
+		digits = digits[len(digits)-len(segmentColumns):]
+	}
+	if err := s.Halt(); err != nil {
+		return 0, err
+	}
+	pos := len(segmentColumns) - len(digits)
+	for _, d := range digits {
+		if err := s.SetDigit(pos, d.c, d.decimal); err != nil {
+			return pos, err
+		}
+		pos++
+	}
+	return pos, s.SetColon(colon)
+}
+
+// Halt clear all the display.
+func (s *SevenSegment) Halt() error {
+	for i := 0; i < 5; i++ {
+		if err := s.dev.WriteColumn(i, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
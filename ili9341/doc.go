@@ -0,0 +1,18 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package ili9341 controls a 240x320 color TFT LCD via an ILI9341 controller
+// on a 4-wire SPI bus.
+//
+// The driver keeps a copy of the image in RGB565 and only sends the area
+// passed to Draw, split in transfers no larger than what the SPI port
+// supports. The rotation is done by the controller.
+//
+// The touch controller found on many modules, usually a XPT2046, is a
+// separate device on the SPI bus and isn't handled by this package.
+//
+// # Datasheet
+//
+// https://cdn-shop.adafruit.com/datasheets/ILI9341.pdf
+package ili9341
@@ -0,0 +1,203 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ili9341
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"periph.io/x/conn/v3/display"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/spi"
+	"periph.io/x/devices/v3/internal/mipidcs"
+)
+
+// Rotation is the rotation of the image relative to the native orientation of
+// the panel, done by the controller.
+type Rotation uint8
+
+// Rotations, clockwise.
+const (
+	Rotate0 Rotation = iota
+	Rotate90
+	Rotate180
+	Rotate270
+)
+
+// DefaultFrequency is the SPI clock used when Opts.Frequency is 0.
+const DefaultFrequency = 32 * physic.MegaHertz
+
+// Opts defines the options for the device.
+type Opts struct {
+	// Width and Height are the size of the panel in its native orientation.
+	Width  int
+	Height int
+	// XOffset and YOffset are the position of the panel in the 240x320
+	// controller RAM, in its native orientation.
+	XOffset int
+	YOffset int
+	// Rotation of the image.
+	Rotation Rotation
+	// Invert inverts the colors.
+	Invert bool
+	// BGR swaps the red and blue channels, for panels wired this way.
+	BGR bool
+	// Frequency of the SPI clock, DefaultFrequency if 0. The controller is
+	// specified up to 10MHz but most modules work much faster.
+	Frequency physic.Frequency
+	// ChunkSize is the largest SPI transfer. If 0, the limit of the SPI
+	// connection is used, or 4096 bytes.
+	ChunkSize int
+}
+
+// Opts240x320 is the options for the common 2.2", 2.4" and 2.8" panels.
+var Opts240x320 = Opts{
+	Width:  240,
+	Height: 320,
+	BGR:    true,
+}
+
+// New opens a handle to a ILI9341 controller on a 4-wire SPI port and
+// initializes it.
+//
+// dc is the data/command pin. rst is the reset pin, nil if not connected.
+func New(p spi.Port, dc, rst gpio.PinOut, opts *Opts) (*Dev, error) {
+	f := opts.Frequency
+	if f == 0 {
+		f = DefaultFrequency
+	}
+	c, err := p.Connect(f, spi.Mode0, 8)
+	if err != nil {
+		return nil, fmt.Errorf("ili9341: %v", err)
+	}
+	d, err := mipidcs.New(c, dc, rst, &mipidcs.Opts{
+		Width:       opts.Width,
+		Height:      opts.Height,
+		XOffset:     opts.XOffset,
+		YOffset:     opts.YOffset,
+		RAMWidth:    240,
+		RAMHeight:   320,
+		Rotations:   rotations,
+		Rotation:    int(opts.Rotation),
+		Invert:      opts.Invert,
+		BGR:         opts.BGR,
+		PixelFormat: mipidcs.FormatDPI16Bits | mipidcs.FormatDBI16Bits,
+		Init:        initCmds,
+		MaxTxSize:   opts.ChunkSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ili9341: %v", err)
+	}
+	return &Dev{d: d}, nil
+}
+
+// Dev is an open handle to the display controller.
+type Dev struct {
+	d *mipidcs.Display
+}
+
+func (d *Dev) String() string {
+	return fmt.Sprintf("ili9341.Dev{%s}", d.d)
+}
+
+// ColorModel implements display.Drawer.
+//
+// It converts the colors to RGB565.
+func (d *Dev) ColorModel() color.Model {
+	return mipidcs.RGB565Model
+}
+
+// Bounds implements display.Drawer.
+func (d *Dev) Bounds() image.Rectangle {
+	return d.d.Bounds()
+}
+
+// Draw implements display.Drawer.
+//
+// Only the area r is sent to the controller.
+func (d *Dev) Draw(r image.Rectangle, src image.Image, sp image.Point) error {
+	return wrap(d.d.Draw(r, src, sp))
+}
+
+// SetRotation rotates the image. The content of the display is lost; the
+// whole image must be drawn again.
+func (d *Dev) SetRotation(r Rotation) error {
+	return wrap(d.d.SetRotation(int(r)))
+}
+
+// Invert inverts the colors, relative to the colors set by Opts.Invert.
+func (d *Dev) Invert(on bool) error {
+	return wrap(d.d.Invert(on))
+}
+
+// Halt turns the display off and puts the controller in sleep mode. The next
+// Draw wakes it up.
+func (d *Dev) Halt() error {
+	return wrap(d.d.Halt())
+}
+
+//
+
+// Commands
+const (
+	frameRateControl byte = 0xB1
+	displayFunction  byte = 0xB6
+	powerControl1    byte = 0xC0
+	powerControl2    byte = 0xC1
+	vcomControl1     byte = 0xC5
+	vcomControl2     byte = 0xC7
+	positiveGamma    byte = 0xE0
+	negativeGamma    byte = 0xE1
+	powerControlA    byte = 0xCB
+	powerControlB    byte = 0xCF
+	driverTimingA    byte = 0xE8
+	driverTimingB    byte = 0xEA
+	powerOnSequence  byte = 0xED
+	enable3Gamma     byte = 0xF2
+	pumpRatioControl byte = 0xF7
+)
+
+// rotations are the mipidcs.MemoryAccessControl flags of each rotation.
+var rotations = [...]byte{
+	Rotate0:   mipidcs.MX,
+	Rotate90:  mipidcs.MV,
+	Rotate180: mipidcs.MY,
+	Rotate270: mipidcs.MX | mipidcs.MY | mipidcs.MV,
+}
+
+// initCmds configures the controller, before the common commands sent by
+// mipidcs.
+var initCmds = []mipidcs.Cmd{
+	// Undocumented, from the initialization code of the vendor.
+	{Cmd: 0xEF, Data: []byte{0x03, 0x80, 0x02}},
+	{Cmd: powerControlB, Data: []byte{0x00, 0xC1, 0x30}},
+	{Cmd: powerOnSequence, Data: []byte{0x64, 0x03, 0x12, 0x81}},
+	{Cmd: driverTimingA, Data: []byte{0x85, 0x00, 0x78}},
+	{Cmd: powerControlA, Data: []byte{0x39, 0x2C, 0x00, 0x34, 0x02}},
+	{Cmd: pumpRatioControl, Data: []byte{0x20}},
+	{Cmd: driverTimingB, Data: []byte{0x00, 0x00}},
+	{Cmd: powerControl1, Data: []byte{0x23}},
+	{Cmd: powerControl2, Data: []byte{0x10}},
+	{Cmd: vcomControl1, Data: []byte{0x3E, 0x28}},
+	{Cmd: vcomControl2, Data: []byte{0x86}},
+	{Cmd: mipidcs.VerticalScrollStart, Data: []byte{0x00}},
+	{Cmd: frameRateControl, Data: []byte{0x00, 0x18}},
+	{Cmd: displayFunction, Data: []byte{0x08, 0x82, 0x27}},
+	{Cmd: enable3Gamma, Data: []byte{0x00}},
+	{Cmd: mipidcs.GammaSet, Data: []byte{0x01}},
+	{Cmd: positiveGamma, Data: []byte{0x0F, 0x31, 0x2B, 0x0C, 0x0E, 0x08, 0x4E, 0xF1, 0x37, 0x07, 0x10, 0x03, 0x0E, 0x09, 0x00}},
+	{Cmd: negativeGamma, Data: []byte{0x00, 0x0E, 0x14, 0x03, 0x11, 0x07, 0x31, 0xC1, 0x48, 0x08, 0x0F, 0x0C, 0x31, 0x36, 0x0F}},
+}
+
+func wrap(err error) error {
+	if err != nil {
+		return fmt.Errorf("ili9341: %v", err)
+	}
+	return nil
+}
+
+var _ display.Drawer = &Dev{}
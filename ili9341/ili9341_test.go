@@ -0,0 +1,104 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ili9341
+
+import (
+	"image"
+	"image/color"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"periph.io/x/devices/v3/devicetest"
+	"periph.io/x/devices/v3/internal/mipidcs"
+)
+
+type record = devicetest.Record
+
+func newDev(t *testing.T, opts *Opts) (*Dev, *devicetest.CommandConn) {
+	mipidcs.Sleep = func(time.Duration) {}
+	c := devicetest.NewCommandConn()
+	d, err := New(c, &c.DC, nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d, c
+}
+
+func TestNew(t *testing.T) {
+	d, c := newDev(t, &Opts240x320)
+	want := []record{{Cmd: mipidcs.SoftReset}}
+	for _, cmd := range initCmds {
+		want = append(want, record{Cmd: cmd.Cmd, Data: cmd.Data})
+	}
+	want = append(want,
+		record{Cmd: mipidcs.MemoryAccessControl, Data: []byte{0x48}},
+		record{Cmd: mipidcs.PixelFormat, Data: []byte{0x55}},
+		record{Cmd: mipidcs.InvertOff},
+		record{Cmd: mipidcs.SleepOut},
+		record{Cmd: mipidcs.NormalDisplayOn},
+		record{Cmd: mipidcs.DisplayOn},
+	)
+	if diff := cmp.Diff(c.Records, want, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("New() difference (-got +want):\n%s", diff)
+	}
+	if s := d.String(); s != "ili9341.Dev{devicetest.CommandConn, DC(0), Width: 240, Height: 320}" {
+		t.Errorf("String() = %q", s)
+	}
+
+	for _, opts := range []Opts{
+		{Width: 0, Height: 320},
+		{Width: 240, Height: 320, XOffset: 1},
+		{Width: 240, Height: 320, Rotation: 4},
+	} {
+		if _, err := New(c, &c.DC, nil, &opts); err == nil {
+			t.Errorf("New(%+v) should fail", opts)
+		}
+	}
+}
+
+func TestRotation(t *testing.T) {
+	for _, tc := range []struct {
+		rotation   Rotation
+		want0x48   byte
+		wantBounds image.Rectangle
+	}{
+		{Rotate0, 0x48, image.Rect(0, 0, 240, 320)},
+		{Rotate90, 0x28, image.Rect(0, 0, 320, 240)},
+		{Rotate180, 0x88, image.Rect(0, 0, 240, 320)},
+		{Rotate270, 0xE8, image.Rect(0, 0, 320, 240)},
+	} {
+		d, c := newDev(t, &Opts240x320)
+		c.Reset()
+		if err := d.SetRotation(tc.rotation); err != nil {
+			t.Fatal(err)
+		}
+		if diff := c.Diff([]record{{Cmd: mipidcs.MemoryAccessControl, Data: []byte{tc.want0x48}}}); diff != "" {
+			t.Errorf("SetRotation(%d) difference (-got +want):\n%s", tc.rotation, diff)
+		}
+		if b := d.Bounds(); b != tc.wantBounds {
+			t.Errorf("SetRotation(%d) bounds = %v, want %v", tc.rotation, b, tc.wantBounds)
+		}
+	}
+}
+
+func TestDraw(t *testing.T) {
+	opts := Opts240x320
+	opts.Rotation = Rotate90
+	d, c := newDev(t, &opts)
+	c.Reset()
+	if err := d.Draw(image.Rect(0, 0, 1, 1), &image.Uniform{color.RGBA{R: 0xFF, A: 0xFF}}, image.Point{}); err != nil {
+		t.Fatal(err)
+	}
+	want := []record{
+		{Cmd: mipidcs.ColumnAddressSet, Data: []byte{0, 0, 0, 0}},
+		{Cmd: mipidcs.PageAddressSet, Data: []byte{0, 0, 0, 0}},
+		{Cmd: mipidcs.MemoryWrite, Data: []byte{0xF8, 0x00}},
+	}
+	if diff := c.Diff(want); diff != "" {
+		t.Errorf("Draw() difference (-got +want):\n%s", diff)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"periph.io/x/conn/v3/i2c"
 	"periph.io/x/conn/v3/mmr"
 	"periph.io/x/conn/v3/physic"
+	"periph.io/x/devices/v3"
 )
 
 // Opts holds the configuration options.
@@ -112,31 +113,90 @@ func (d *Dev) Sense() (PowerMonitor, error) {
 	// Least significant bit is 10µV.
 	pm.Shunt = physic.ElectricPotential(int16(shunt)) * 10 * physic.MicroVolt
 
+	if pm.Voltage, err = d.voltage(); err != nil {
+		return PowerMonitor{}, err
+	}
+	if pm.Current, err = d.current(); err != nil {
+		return PowerMonitor{}, err
+	}
+	if pm.Power, err = d.power(); err != nil {
+		return PowerMonitor{}, err
+	}
+	return pm, nil
+}
+
+// Voltage reads the bus voltage.
+//
+// Voltage implements devices.PowerSensor.
+func (d *Dev) Voltage() (physic.ElectricPotential, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.voltage()
+}
+
+// Current reads the current through the shunt resistor.
+//
+// Current implements devices.PowerSensor.
+func (d *Dev) Current() (physic.ElectricCurrent, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.current()
+}
+
+// Power reads the power drawn by the load.
+//
+// Power implements devices.PowerSensor.
+func (d *Dev) Power() (physic.Power, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.power()
+}
+
+// SensePower reads the bus voltage, the current and the power, without the
+// shunt voltage.
+//
+// SensePower implements devices.PowerSensor.
+func (d *Dev) SensePower(e *devices.PowerEnv) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var err error
+	if e.Voltage, err = d.voltage(); err != nil {
+		return err
+	}
+	if e.Current, err = d.current(); err != nil {
+		return err
+	}
+	e.Power, err = d.power()
+	return err
+}
+
+func (d *Dev) voltage() (physic.ElectricPotential, error) {
 	bus, err := d.m.ReadUint16(busVoltageRegister)
 	if err != nil {
-		return PowerMonitor{}, errReadBus
+		return 0, errReadBus
 	}
 	// Check if bit zero is set, if set the ADC has overflowed.
 	if bus&1 > 0 {
-		return PowerMonitor{}, errRegisterOverflow
+		return 0, errRegisterOverflow
 	}
-
 	// Least significant bit is 4mV.
-	pm.Voltage = physic.ElectricPotential(bus>>3) * 4 * physic.MilliVolt
+	return physic.ElectricPotential(bus>>3) * 4 * physic.MilliVolt, nil
+}
 
+func (d *Dev) current() (physic.ElectricCurrent, error) {
 	current, err := d.m.ReadUint16(currentRegister)
 	if err != nil {
-		return PowerMonitor{}, errReadCurrent
+		return 0, errReadCurrent
 	}
-	pm.Current = physic.ElectricCurrent(int16(current)) * d.currentLSB
+	return physic.ElectricCurrent(int16(current)) * d.currentLSB, nil
+}
 
+func (d *Dev) power() (physic.Power, error) {
 	power, err := d.m.ReadUint16(powerRegister)
 	if err != nil {
-		return PowerMonitor{}, errReadPower
+		return 0, errReadPower
 	}
-	pm.Power = physic.Power(power) * d.powerLSB
-
-	return pm, nil
+	return physic.Power(power) * d.powerLSB, nil
 }
 
 // Since physic electrical is in nano units we need to scale taking care to not
@@ -194,3 +254,5 @@ var (
 	errWritingToConfigRegister   = errors.New("failed to write to configuration register")
 	errCalibrationOverflow       = errors.New("calibration would exceed maximum scaling")
 )
+
+var _ devices.PowerSensor = &Dev{}
@@ -14,6 +14,8 @@ import (
 	"periph.io/x/conn/v3/i2c/i2ctest"
 	"periph.io/x/conn/v3/mmr"
 	"periph.io/x/conn/v3/physic"
+	"periph.io/x/devices/v3"
+	"periph.io/x/devices/v3/devicetest"
 )
 
 func TestNew(t *testing.T) {
@@ -278,6 +280,33 @@ func TestSense(t *testing.T) {
 	}
 }
 
+func TestPowerSensor(t *testing.T) {
+	bus := &i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x40, W: []byte{calibrationRegister, 0x10, 0x62}, R: []byte{}},
+			{Addr: 0x40, W: []byte{configRegister, 0x1f, 0xff}, R: []byte{}},
+			{Addr: 0x40, W: []byte{busVoltageRegister}, R: []byte{0x5d, 0xc0}},
+			{Addr: 0x40, W: []byte{currentRegister}, R: []byte{0x04, 0x00}},
+			{Addr: 0x40, W: []byte{powerRegister}, R: []byte{0x01, 0x00}},
+			{Addr: 0x40, W: []byte{busVoltageRegister}, R: []byte{0x5d, 0xc0}},
+			{Addr: 0x40, W: []byte{currentRegister}, R: []byte{0x04, 0x00}},
+			{Addr: 0x40, W: []byte{powerRegister}, R: []byte{0x01, 0x00}},
+		},
+	}
+	ina, err := New(bus, &Opts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	devicetest.PowerSensor(t, ina, devices.PowerEnv{
+		Voltage: 12 * physic.Volt,
+		Current: 1024 * 97656 * physic.NanoAmpere,
+		Power:   500 * physic.MilliWatt,
+	})
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestCalibrate(t *testing.T) {
 	stringErr := errors.New("use err.Error() error")
 
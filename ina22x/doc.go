@@ -0,0 +1,28 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package ina22x controls the Texas Instruments ina226 and ina228 high side
+// current, voltage and power monitor ICs over an i2c bus.
+//
+// The ina226 has a 16 bit ADC. The ina228 has a 20 bit ADC and additionally
+// accumulates energy and charge.
+//
+// # Calibration
+//
+// Calibration is recommended for accurate current and power measurements.
+// Voltage measurements do not require sensor calibration. To calibrate, measure
+// the actual value of the shunt resistor.
+//
+// # Alerts
+//
+// The ina226 has a single alert limit register, so setting an alert replaces
+// the previous one. The ina228 has a limit register per alert function, all of
+// which can be active at once.
+//
+// # Datasheets
+//
+// https://www.ti.com/lit/ds/symlink/ina226.pdf
+//
+// https://www.ti.com/lit/ds/symlink/ina228.pdf
+package ina22x
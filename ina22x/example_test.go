@@ -0,0 +1,48 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ina22x_test
+
+import (
+	"fmt"
+	"log"
+
+	"periph.io/x/conn/v3/i2c/i2creg"
+	"periph.io/x/devices/v3/ina22x"
+	"periph.io/x/host/v3"
+)
+
+func Example() {
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Open default I²C bus.
+	bus, err := i2creg.Open("")
+	if err != nil {
+		log.Fatalf("failed to open I²C: %v", err)
+	}
+	defer bus.Close()
+
+	// Create a new power sensor.
+	sensor, err := ina22x.New(bus, ina22x.INA228, &ina22x.DefaultOpts)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	// Read values from sensor.
+	measurement, err := sensor.Sense()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Println(measurement)
+
+	// Read the energy accumulated since power on.
+	energy, err := sensor.Energy()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Println(energy)
+}
@@ -0,0 +1,577 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ina22x
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/devices/v3"
+)
+
+// Variant is the type denoting a specific variant of the family.
+type Variant string
+
+const (
+	INA226 Variant = "INA226" // INA226 16 bit power monitor.
+	INA228 Variant = "INA228" // INA228 20 bit power monitor with energy and charge accumulators.
+)
+
+// Opts holds the configuration options.
+//
+// # Slave Address
+//
+// Depending which pins the A1, A0 pins are connected to will change the slave
+// address. Default configuration is address 0x40 (both pins to GND). For a full
+// address table see datasheet.
+type Opts struct {
+	Address       int
+	SenseResistor physic.ElectricResistance
+	MaxCurrent    physic.ElectricCurrent
+}
+
+// DefaultOpts is the recommended default options.
+var DefaultOpts = Opts{
+	Address:       0x40,
+	SenseResistor: 100 * physic.MilliOhm,
+	MaxCurrent:    800 * physic.MilliAmpere,
+}
+
+// New opens a handle to an ina226 or ina228 sensor.
+//
+// The identification registers are checked to match the variant.
+func New(bus i2c.Bus, variant Variant, opts *Opts) (*Dev, error) {
+	i2cAddress := DefaultOpts.Address
+	if opts.Address != 0 {
+		if opts.Address < 0x40 || opts.Address > 0x4f {
+			return nil, errAddressOutOfRange
+		}
+		i2cAddress = opts.Address
+	}
+
+	senseResistor := DefaultOpts.SenseResistor
+	if opts.SenseResistor != 0 {
+		if opts.SenseResistor < 1 {
+			return nil, errSenseResistorValueInvalid
+		}
+		senseResistor = opts.SenseResistor
+	}
+
+	maxCurrent := DefaultOpts.MaxCurrent
+	if opts.MaxCurrent != 0 {
+		if opts.MaxCurrent < 1 {
+			return nil, errMaxCurrentInvalid
+		}
+		maxCurrent = opts.MaxCurrent
+	}
+
+	dev := &Dev{
+		c:       i2c.Dev{Bus: bus, Addr: uint16(i2cAddress)},
+		variant: variant,
+		sense:   senseResistor,
+	}
+	switch variant {
+	case INA226:
+		dev.r = &ina226Regs
+	case INA228:
+		dev.r = &ina228Regs
+	default:
+		return nil, fmt.Errorf("ina22x: unsupported variant %q", string(variant))
+	}
+
+	if err := dev.checkID(); err != nil {
+		return nil, err
+	}
+	if err := dev.calibrate(senseResistor, maxCurrent); err != nil {
+		return nil, err
+	}
+	if variant == INA226 {
+		// Continuous shunt and bus, 1.1ms conversions, no averaging.
+		if err := dev.writeRegister(configRegister, 0x4127, 2); err != nil {
+			return nil, errWritingToConfigRegister
+		}
+	}
+	return dev, nil
+}
+
+// Dev is a handle to the ina226 or ina228 sensor.
+type Dev struct {
+	c       i2c.Dev
+	variant Variant
+	r       *registers
+	sense   physic.ElectricResistance
+
+	mu         sync.Mutex
+	currentLSB physic.ElectricCurrent
+}
+
+func (d *Dev) String() string {
+	return string(d.variant) + "{" + strconv.Itoa(int(d.c.Addr)) + "}"
+}
+
+// Sense reads the power values from the sensor.
+func (d *Dev) Sense() (PowerMonitor, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var pm PowerMonitor
+	if d.variant == INA226 {
+		shunt, err := d.readRegister(ina226ShuntRegister, 2)
+		if err != nil {
+			return PowerMonitor{}, errReadShunt
+		}
+		// Least significant bit is 2.5µV.
+		pm.Shunt = physic.ElectricPotential(int16(shunt)) * 2500 * physic.NanoVolt
+	} else {
+		// The ina228 registers are 24 bits with the 20 bit value left aligned.
+		shunt, err := d.readRegister(ina228ShuntRegister, 3)
+		if err != nil {
+			return PowerMonitor{}, errReadShunt
+		}
+		// Least significant bit is 312.5nV.
+		pm.Shunt = physic.ElectricPotential(signExtend20(shunt) * 3125 / 10)
+	}
+	var err error
+	if pm.Voltage, err = d.voltage(); err != nil {
+		return PowerMonitor{}, err
+	}
+	if pm.Current, err = d.current(); err != nil {
+		return PowerMonitor{}, err
+	}
+	if pm.Power, err = d.power(); err != nil {
+		return PowerMonitor{}, err
+	}
+	return pm, nil
+}
+
+// Voltage reads the bus voltage.
+//
+// Voltage implements devices.PowerSensor.
+func (d *Dev) Voltage() (physic.ElectricPotential, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.voltage()
+}
+
+// Current reads the current through the shunt resistor.
+//
+// Current implements devices.PowerSensor.
+func (d *Dev) Current() (physic.ElectricCurrent, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.current()
+}
+
+// Power reads the power drawn by the load.
+//
+// Power implements devices.PowerSensor.
+func (d *Dev) Power() (physic.Power, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.power()
+}
+
+// SensePower reads the bus voltage, the current and the power, without the
+// shunt voltage.
+//
+// SensePower implements devices.PowerSensor.
+func (d *Dev) SensePower(e *devices.PowerEnv) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var err error
+	if e.Voltage, err = d.voltage(); err != nil {
+		return err
+	}
+	if e.Current, err = d.current(); err != nil {
+		return err
+	}
+	e.Power, err = d.power()
+	return err
+}
+
+// Energy returns the energy accumulated since the last reset. Only supported
+// on the ina228.
+func (d *Dev) Energy() (physic.Energy, error) {
+	if d.variant != INA228 {
+		return 0, errNotSupported
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	v, err := d.readRegister(energyRegister, 5)
+	if err != nil {
+		return 0, errReadEnergy
+	}
+	// Least significant bit is 16 times the power LSB.
+	return physic.Energy(int64(v) * 512 * int64(d.currentLSB) / 10), nil
+}
+
+// Charge returns the charge accumulated since the last reset. Only supported
+// on the ina228.
+func (d *Dev) Charge() (Charge, error) {
+	if d.variant != INA228 {
+		return 0, errNotSupported
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	v, err := d.readRegister(chargeRegister, 5)
+	if err != nil {
+		return 0, errReadCharge
+	}
+	// 40 bit two's complement, least significant bit is the current LSB.
+	return Charge(int64(v<<24)>>24) * Charge(d.currentLSB), nil
+}
+
+// ResetAccumulators resets the energy and charge accumulators to zero. Only
+// supported on the ina228.
+func (d *Dev) ResetAccumulators() error {
+	if d.variant != INA228 {
+		return errNotSupported
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.writeRegister(configRegister, 1<<14, 2); err != nil {
+		return errWritingToConfigRegister
+	}
+	return nil
+}
+
+// SetOverCurrentAlert asserts the alert pin when the current exceeds limit.
+func (d *Dev) SetOverCurrentAlert(limit physic.ElectricCurrent) error {
+	return d.setAlert(&d.r.shuntOver, d.currentToShunt(limit))
+}
+
+// SetUnderCurrentAlert asserts the alert pin when the current is below limit.
+func (d *Dev) SetUnderCurrentAlert(limit physic.ElectricCurrent) error {
+	return d.setAlert(&d.r.shuntUnder, d.currentToShunt(limit))
+}
+
+// SetBusOverVoltageAlert asserts the alert pin when the bus voltage exceeds
+// limit.
+func (d *Dev) SetBusOverVoltageAlert(limit physic.ElectricPotential) error {
+	return d.setAlert(&d.r.busOver, int64(limit))
+}
+
+// SetBusUnderVoltageAlert asserts the alert pin when the bus voltage is below
+// limit.
+func (d *Dev) SetBusUnderVoltageAlert(limit physic.ElectricPotential) error {
+	return d.setAlert(&d.r.busUnder, int64(limit))
+}
+
+// SetPowerAlert asserts the alert pin when the power exceeds limit.
+func (d *Dev) SetPowerAlert(limit physic.Power) error {
+	d.mu.Lock()
+	a := d.r.powerOver
+	a.lsb = d.r.powerLimitLSB(d.currentLSB)
+	d.mu.Unlock()
+	return d.setAlert(&a, int64(limit))
+}
+
+// Alerted returns true if any alert limit was crossed.
+//
+// On the ina226 reading the flag clears it when the alert pin is not latched.
+func (d *Dev) Alerted() (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	v, err := d.readRegister(d.r.alertRegister, 2)
+	if err != nil {
+		return false, errReadAlert
+	}
+	return v&d.r.alertFlags != 0, nil
+}
+
+//
+
+const (
+	configRegister = 0x00
+
+	ina226ShuntRegister   = 0x01
+	ina226BusRegister     = 0x02
+	ina226PowerRegister   = 0x03
+	ina226CurrentRegister = 0x04
+	ina226CalibRegister   = 0x05
+	ina226MaskRegister    = 0x06
+	ina226LimitRegister   = 0x07
+
+	ina228CalibRegister   = 0x02
+	ina228ShuntRegister   = 0x04
+	ina228BusRegister     = 0x05
+	ina228CurrentRegister = 0x07
+	ina228PowerRegister   = 0x08
+	energyRegister        = 0x09
+	chargeRegister        = 0x0a
+	ina228DiagRegister    = 0x0b
+)
+
+// alertLimit describes how an alert limit is programmed.
+type alertLimit struct {
+	reg  uint8  // limit register
+	mask uint16 // ina226 mask/enable function bit, 0 for the ina228
+	lsb  int64  // value of the least significant bit in nano units, 0 for power
+	max  int64  // maximum raw value
+	min  int64  // minimum raw value
+}
+
+// registers describes the register map differences between variants.
+type registers struct {
+	id            uint8  // manufacturer ID register, followed by the device ID
+	deviceID      uint16 // device ID, the 4 lowest bits are the revision on the ina228
+	deviceIDMask  uint16
+	calib         uint8
+	calibMax      int64
+	currentBits   uint // current register resolution
+	alertRegister uint8
+	alertFlags    uint64
+	shuntOver     alertLimit
+	shuntUnder    alertLimit
+	busOver       alertLimit
+	busUnder      alertLimit
+	powerOver     alertLimit
+}
+
+// powerLimitLSB returns the power limit LSB in nW.
+func (r *registers) powerLimitLSB(currentLSB physic.ElectricCurrent) int64 {
+	if r.powerOver.mask != 0 {
+		// Same format as the ina226 power register.
+		return 25 * int64(currentLSB)
+	}
+	// 256 times the ina228 power LSB.
+	return 256 * 32 * int64(currentLSB) / 10
+}
+
+var ina226Regs = registers{
+	id:            0xfe,
+	deviceID:      0x2260,
+	deviceIDMask:  0xffff,
+	calib:         ina226CalibRegister,
+	calibMax:      1<<15 - 1,
+	currentBits:   15,
+	alertRegister: ina226MaskRegister,
+	alertFlags:    1 << 4, // AFF
+	shuntOver:     alertLimit{reg: ina226LimitRegister, mask: 1 << 15, lsb: 2500, max: 1<<15 - 1, min: -1 << 15},
+	shuntUnder:    alertLimit{reg: ina226LimitRegister, mask: 1 << 14, lsb: 2500, max: 1<<15 - 1, min: -1 << 15},
+	busOver:       alertLimit{reg: ina226LimitRegister, mask: 1 << 13, lsb: 1250000, max: 1<<15 - 1},
+	busUnder:      alertLimit{reg: ina226LimitRegister, mask: 1 << 12, lsb: 1250000, max: 1<<15 - 1},
+	powerOver:     alertLimit{reg: ina226LimitRegister, mask: 1 << 11, max: 1<<16 - 1},
+}
+
+var ina228Regs = registers{
+	id:            0x3e,
+	deviceID:      0x2280,
+	deviceIDMask:  0xfff0,
+	calib:         ina228CalibRegister,
+	calibMax:      1<<15 - 1,
+	currentBits:   19,
+	alertRegister: ina228DiagRegister,
+	alertFlags:    0xfc, // POL, BUSUL, BUSOL, SHNTUL, SHNTOL, TMPOL
+	shuntOver:     alertLimit{reg: 0x0c, lsb: 5000, max: 1<<15 - 1, min: -1 << 15},
+	shuntUnder:    alertLimit{reg: 0x0d, lsb: 5000, max: 1<<15 - 1, min: -1 << 15},
+	busOver:       alertLimit{reg: 0x0e, lsb: 3125000, max: 1<<15 - 1},
+	busUnder:      alertLimit{reg: 0x0f, lsb: 3125000, max: 1<<15 - 1},
+	powerOver:     alertLimit{reg: 0x11, max: 1<<16 - 1},
+}
+
+func (d *Dev) checkID() error {
+	m, err := d.readRegister(d.r.id, 2)
+	if err != nil {
+		return err
+	}
+	id, err := d.readRegister(d.r.id+1, 2)
+	if err != nil {
+		return err
+	}
+	if m != 0x5449 || uint16(id)&d.r.deviceIDMask != d.r.deviceID {
+		return fmt.Errorf("ina22x: unexpected device ID %#04x, manufacturer %#04x for %s", id, m, d.variant)
+	}
+	return nil
+}
+
+// calibrate sets the scaling factor of the current and power registers for the
+// maximum resolution. calibrate is run on init.
+func (d *Dev) calibrate(sense physic.ElectricResistance, maxCurrent physic.ElectricCurrent) error {
+	if sense <= 0 {
+		return errSenseResistorValueInvalid
+	}
+	if maxCurrent <= 0 {
+		return errMaxCurrentInvalid
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.currentLSB = maxCurrent / physic.ElectricCurrent(int64(1)<<d.r.currentBits)
+	if d.currentLSB == 0 {
+		return errMaxCurrentInvalid
+	}
+	// Both values are in nano units.
+	var cal int64
+	if d.variant == INA226 {
+		// Calibration Register = 0.00512 / (current LSB * Shunt Resistance)
+		cal = 5120000000000000 / (int64(d.currentLSB) * int64(sense))
+	} else {
+		// SHUNT_CAL = 13107.2e6 * current LSB * Shunt Resistance
+		cal = int64(d.currentLSB) * int64(sense) / 1000 * 131072 / 10000000000
+	}
+	if cal > d.r.calibMax {
+		return errCalibrationOverflow
+	}
+	return d.writeRegister(d.r.calib, uint64(cal), 2)
+}
+
+// currentToShunt converts a current to the matching shunt voltage in nV.
+func (d *Dev) currentToShunt(limit physic.ElectricCurrent) int64 {
+	return int64(limit) * int64(d.sense) / int64(physic.Ohm)
+}
+
+func (d *Dev) setAlert(a *alertLimit, v int64) error {
+	raw := v / a.lsb
+	if raw > a.max || raw < a.min {
+		return errAlertOutOfRange
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.writeRegister(a.reg, uint64(uint16(raw)), 2); err != nil {
+		return errWritingAlert
+	}
+	if a.mask != 0 {
+		// The ina226 has a single alert function enabled at a time.
+		if err := d.writeRegister(ina226MaskRegister, uint64(a.mask), 2); err != nil {
+			return errWritingAlert
+		}
+	}
+	return nil
+}
+
+func (d *Dev) voltage() (physic.ElectricPotential, error) {
+	if d.variant == INA226 {
+		bus, err := d.readRegister(ina226BusRegister, 2)
+		if err != nil {
+			return 0, errReadBus
+		}
+		// Least significant bit is 1.25mV.
+		return physic.ElectricPotential(bus) * 1250 * physic.MicroVolt, nil
+	}
+	bus, err := d.readRegister(ina228BusRegister, 3)
+	if err != nil {
+		return 0, errReadBus
+	}
+	// Least significant bit is 195.3125µV.
+	return physic.ElectricPotential(signExtend20(bus) * 1953125 / 10), nil
+}
+
+func (d *Dev) current() (physic.ElectricCurrent, error) {
+	if d.variant == INA226 {
+		current, err := d.readRegister(ina226CurrentRegister, 2)
+		if err != nil {
+			return 0, errReadCurrent
+		}
+		return physic.ElectricCurrent(int16(current)) * d.currentLSB, nil
+	}
+	current, err := d.readRegister(ina228CurrentRegister, 3)
+	if err != nil {
+		return 0, errReadCurrent
+	}
+	return physic.ElectricCurrent(signExtend20(current)) * d.currentLSB, nil
+}
+
+func (d *Dev) power() (physic.Power, error) {
+	if d.variant == INA226 {
+		power, err := d.readRegister(ina226PowerRegister, 2)
+		if err != nil {
+			return 0, errReadPower
+		}
+		// Least significant bit is 25 times the current LSB.
+		return physic.Power(int64(power) * 25 * int64(d.currentLSB)), nil
+	}
+	power, err := d.readRegister(ina228PowerRegister, 3)
+	if err != nil {
+		return 0, errReadPower
+	}
+	// Least significant bit is 3.2 times the current LSB.
+	return physic.Power(int64(power) * 32 * int64(d.currentLSB) / 10), nil
+}
+
+// readRegister reads a big endian register of n bytes.
+func (d *Dev) readRegister(reg uint8, n int) (uint64, error) {
+	var b [8]byte
+	if err := d.c.Tx([]byte{reg}, b[:n]); err != nil {
+		return 0, err
+	}
+	var v uint64
+	for _, c := range b[:n] {
+		v = v<<8 | uint64(c)
+	}
+	return v, nil
+}
+
+// writeRegister writes a big endian register of n bytes.
+func (d *Dev) writeRegister(reg uint8, v uint64, n int) error {
+	w := make([]byte, n+1)
+	w[0] = reg
+	for i := n; i > 0; i-- {
+		w[i] = byte(v)
+		v >>= 8
+	}
+	return d.c.Tx(w, nil)
+}
+
+// signExtend20 converts a 24 bit register holding a left aligned 20 bit two's
+// complement value.
+func signExtend20(v uint64) int64 {
+	return int64(int32(uint32(v)<<8) >> 12)
+}
+
+// PowerMonitor represents measurements from the sensor.
+type PowerMonitor struct {
+	Shunt   physic.ElectricPotential
+	Voltage physic.ElectricPotential
+	Current physic.ElectricCurrent
+	Power   physic.Power
+}
+
+// String returns a PowerMonitor as string
+func (p PowerMonitor) String() string {
+	return fmt.Sprintf("Bus: %s, Current: %s, Power: %s, Shunt: %s", p.Voltage, p.Current, p.Power, p.Shunt)
+}
+
+// Charge is an electric charge stored as an int64 nano Coulomb.
+//
+// There is no charge unit in periph.io/x/conn/v3/physic.
+type Charge int64
+
+const (
+	NanoCoulomb  Charge = 1
+	MicroCoulomb Charge = 1000 * NanoCoulomb
+	MilliCoulomb Charge = 1000 * MicroCoulomb
+	Coulomb      Charge = 1000 * MilliCoulomb
+)
+
+// String returns the charge in Coulomb with a micro Coulomb resolution.
+func (c Charge) String() string {
+	return strconv.FormatFloat(float64(c/MicroCoulomb)/1e6, 'f', -1, 64) + "C"
+}
+
+var (
+	errReadShunt                 = errors.New("ina22x: failed to read shunt voltage")
+	errReadBus                   = errors.New("ina22x: failed to read bus voltage")
+	errReadPower                 = errors.New("ina22x: failed to read power")
+	errReadCurrent               = errors.New("ina22x: failed to read current")
+	errReadEnergy                = errors.New("ina22x: failed to read energy")
+	errReadCharge                = errors.New("ina22x: failed to read charge")
+	errReadAlert                 = errors.New("ina22x: failed to read alert flags")
+	errAddressOutOfRange         = errors.New("ina22x: i2c address out of range")
+	errSenseResistorValueInvalid = errors.New("ina22x: sense resistor value cannot be negative or zero")
+	errMaxCurrentInvalid         = errors.New("ina22x: max current too small")
+	errWritingToConfigRegister   = errors.New("ina22x: failed to write to configuration register")
+	errCalibrationOverflow       = errors.New("ina22x: calibration would exceed maximum scaling")
+	errAlertOutOfRange           = errors.New("ina22x: alert limit out of range")
+	errWritingAlert              = errors.New("ina22x: failed to write alert limit")
+	errNotSupported              = errors.New("ina22x: not supported by this variant")
+)
+
+var _ devices.PowerSensor = &Dev{}
@@ -0,0 +1,223 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ina22x
+
+import (
+	"testing"
+
+	"periph.io/x/conn/v3/i2c/i2ctest"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/devices/v3"
+	"periph.io/x/devices/v3/devicetest"
+)
+
+var ina226Init = []i2ctest.IO{
+	{Addr: 0x40, W: []byte{0xfe}, R: []byte{0x54, 0x49}},
+	{Addr: 0x40, W: []byte{0xff}, R: []byte{0x22, 0x60}},
+	{Addr: 0x40, W: []byte{ina226CalibRegister, 0x08, 0x31}},
+	{Addr: 0x40, W: []byte{configRegister, 0x41, 0x27}},
+}
+
+var ina228Init = []i2ctest.IO{
+	{Addr: 0x40, W: []byte{0x3e}, R: []byte{0x54, 0x49}},
+	{Addr: 0x40, W: []byte{0x3f}, R: []byte{0x22, 0x81}},
+	{Addr: 0x40, W: []byte{ina228CalibRegister, 0x07, 0xce}},
+}
+
+func TestNew(t *testing.T) {
+	var tests = []struct {
+		name    string
+		variant Variant
+		opts    Opts
+		tx      []i2ctest.IO
+		err     bool
+	}{
+		{name: "ina226", variant: INA226, tx: ina226Init},
+		{name: "ina228", variant: INA228, tx: ina228Init},
+		{name: "badVariant", variant: "INA219", err: true},
+		{name: "badAddressOption", variant: INA226, opts: Opts{Address: 0x60}, err: true},
+		{name: "badSenseResistorOption", variant: INA226, opts: Opts{SenseResistor: -1}, err: true},
+		{name: "badMaxCurrentOption", variant: INA226, opts: Opts{MaxCurrent: -1}, err: true},
+		{name: "wrongDevice",
+			variant: INA228,
+			tx: []i2ctest.IO{
+				{Addr: 0x40, W: []byte{0x3e}, R: []byte{0x54, 0x49}},
+				{Addr: 0x40, W: []byte{0x3f}, R: []byte{0x22, 0x60}},
+			},
+			err: true,
+		},
+		{name: "calibrationOverflow",
+			variant: INA226,
+			opts:    Opts{SenseResistor: physic.MilliOhm, MaxCurrent: 100 * physic.MilliAmpere},
+			tx:      ina226Init[:2],
+			err:     true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			bus := &i2ctest.Playback{Ops: test.tx, DontPanic: true}
+			_, err := New(bus, test.variant, &test.opts)
+			if (err != nil) != test.err {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err := bus.Close(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestSense_ina226(t *testing.T) {
+	bus := &i2ctest.Playback{
+		Ops: append(append([]i2ctest.IO{}, ina226Init...),
+			i2ctest.IO{Addr: 0x40, W: []byte{ina226ShuntRegister}, R: []byte{0x0f, 0xa0}},
+			i2ctest.IO{Addr: 0x40, W: []byte{ina226BusRegister}, R: []byte{0x25, 0x80}},
+			i2ctest.IO{Addr: 0x40, W: []byte{ina226CurrentRegister}, R: []byte{0xff, 0xff}},
+			i2ctest.IO{Addr: 0x40, W: []byte{ina226PowerRegister}, R: []byte{0x00, 0x30}},
+		),
+	}
+	dev, err := New(bus, INA226, &Opts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := dev.Sense()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := PowerMonitor{
+		Shunt:   10 * physic.MilliVolt,
+		Voltage: 12 * physic.Volt,
+		Current: -24414 * physic.NanoAmpere,
+		Power:   48 * 25 * 24414 * physic.NanoWatt,
+	}
+	if got != want {
+		t.Fatalf("wanted: %v, but got: %v", want, got)
+	}
+	if _, err := dev.Energy(); err != errNotSupported {
+		t.Fatalf("wanted err: %v, but got: %v", errNotSupported, err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPowerSensor(t *testing.T) {
+	bus := &i2ctest.Playback{
+		Ops: append(append([]i2ctest.IO{}, ina226Init...),
+			i2ctest.IO{Addr: 0x40, W: []byte{ina226BusRegister}, R: []byte{0x25, 0x80}},
+			i2ctest.IO{Addr: 0x40, W: []byte{ina226CurrentRegister}, R: []byte{0xff, 0xff}},
+			i2ctest.IO{Addr: 0x40, W: []byte{ina226PowerRegister}, R: []byte{0x00, 0x30}},
+			i2ctest.IO{Addr: 0x40, W: []byte{ina226BusRegister}, R: []byte{0x25, 0x80}},
+			i2ctest.IO{Addr: 0x40, W: []byte{ina226CurrentRegister}, R: []byte{0xff, 0xff}},
+			i2ctest.IO{Addr: 0x40, W: []byte{ina226PowerRegister}, R: []byte{0x00, 0x30}},
+		),
+	}
+	dev, err := New(bus, INA226, &Opts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	devicetest.PowerSensor(t, dev, devices.PowerEnv{
+		Voltage: 12 * physic.Volt,
+		Current: -24414 * physic.NanoAmpere,
+		Power:   48 * 25 * 24414 * physic.NanoWatt,
+	})
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSense_ina228(t *testing.T) {
+	bus := &i2ctest.Playback{
+		Ops: append(append([]i2ctest.IO{}, ina228Init...),
+			i2ctest.IO{Addr: 0x40, W: []byte{ina228ShuntRegister}, R: []byte{0x07, 0xd0, 0x00}},
+			i2ctest.IO{Addr: 0x40, W: []byte{ina228BusRegister}, R: []byte{0x0f, 0x00, 0x00}},
+			i2ctest.IO{Addr: 0x40, W: []byte{ina228CurrentRegister}, R: []byte{0xff, 0xff, 0xf0}},
+			i2ctest.IO{Addr: 0x40, W: []byte{ina228PowerRegister}, R: []byte{0x00, 0x01, 0x00}},
+			i2ctest.IO{Addr: 0x40, W: []byte{energyRegister}, R: []byte{0x00, 0x00, 0x00, 0x01, 0x00}},
+			i2ctest.IO{Addr: 0x40, W: []byte{chargeRegister}, R: []byte{0xff, 0xff, 0xff, 0xff, 0xff}},
+			i2ctest.IO{Addr: 0x40, W: []byte{configRegister, 0x40, 0x00}},
+		),
+	}
+	dev, err := New(bus, INA228, &Opts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := dev.Sense()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := PowerMonitor{
+		Shunt:   10 * physic.MilliVolt,
+		Voltage: 12 * physic.Volt,
+		Current: -1525 * physic.NanoAmpere,
+		Power:   1249280 * physic.NanoWatt,
+	}
+	if got != want {
+		t.Fatalf("wanted: %v, but got: %v", want, got)
+	}
+	e, err := dev.Energy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e != 19988480*physic.NanoJoule {
+		t.Fatalf("unexpected energy %s", e)
+	}
+	c, err := dev.Charge()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c != -1525*NanoCoulomb {
+		t.Fatalf("unexpected charge %d", c)
+	}
+	if err := dev.ResetAccumulators(); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAlerts(t *testing.T) {
+	bus := &i2ctest.Playback{
+		Ops: append(append([]i2ctest.IO{}, ina226Init...),
+			// 500mA over a 100mΩ shunt is 50mV, or 20000 2.5µV steps.
+			i2ctest.IO{Addr: 0x40, W: []byte{ina226LimitRegister, 0x4e, 0x20}},
+			i2ctest.IO{Addr: 0x40, W: []byte{ina226MaskRegister, 0x80, 0x00}},
+			// 10V is 8000 1.25mV steps.
+			i2ctest.IO{Addr: 0x40, W: []byte{ina226LimitRegister, 0x1f, 0x40}},
+			i2ctest.IO{Addr: 0x40, W: []byte{ina226MaskRegister, 0x10, 0x00}},
+			i2ctest.IO{Addr: 0x40, W: []byte{ina226MaskRegister}, R: []byte{0x10, 0x10}},
+		),
+	}
+	dev, err := New(bus, INA226, &Opts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.SetOverCurrentAlert(500 * physic.MilliAmpere); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.SetBusUnderVoltageAlert(10 * physic.Volt); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.SetBusOverVoltageAlert(100 * physic.Volt); err != errAlertOutOfRange {
+		t.Fatalf("wanted err: %v, but got: %v", errAlertOutOfRange, err)
+	}
+	alerted, err := dev.Alerted()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !alerted {
+		t.Fatal("expected alert")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestChargeString(t *testing.T) {
+	if s := (1500 * MilliCoulomb).String(); s != "1.5C" {
+		t.Fatal(s)
+	}
+}
@@ -0,0 +1,17 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package ina260 controls a Texas Instruments ina260 current, voltage and
+// power monitor IC over an i2c bus.
+//
+// The ina260 has an integrated 2mΩ precision shunt resistor, so unlike the
+// ina219 and ina22x no calibration is needed.
+//
+// Measurements can be read one at a time with Sense, or delivered on a
+// channel at a regular interval with PowerContinuous until Halt is called.
+//
+// # Datasheet
+//
+// https://www.ti.com/lit/ds/symlink/ina260.pdf
+package ina260
@@ -0,0 +1,45 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ina260_test
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"periph.io/x/conn/v3/i2c/i2creg"
+	"periph.io/x/devices/v3/ina260"
+	"periph.io/x/host/v3"
+)
+
+func Example() {
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Open default I²C bus.
+	bus, err := i2creg.Open("")
+	if err != nil {
+		log.Fatalf("failed to open I²C: %v", err)
+	}
+	defer bus.Close()
+
+	// Create a new power sensor.
+	sensor, err := ina260.New(bus, &ina260.DefaultOpts)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	// Log a measurement every second for 10 seconds.
+	c, err := sensor.PowerContinuous(time.Second)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	time.AfterFunc(10*time.Second, func() { sensor.Halt() })
+	for p := range c {
+		fmt.Println(p)
+	}
+}
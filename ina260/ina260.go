@@ -0,0 +1,294 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ina260
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/devices/v3"
+)
+
+// Opts holds the configuration options.
+//
+// # Slave Address
+//
+// Depending which pins the A1, A0 pins are connected to will change the slave
+// address. Default configuration is address 0x40 (both pins to GND). For a full
+// address table see datasheet.
+type Opts struct {
+	Address int
+}
+
+// DefaultOpts is the recommended default options.
+var DefaultOpts = Opts{
+	Address: 0x40,
+}
+
+// New opens a handle to an ina260 sensor.
+//
+// The identification registers are checked and the sensor is configured to
+// measure continuously.
+func New(bus i2c.Bus, opts *Opts) (*Dev, error) {
+	i2cAddress := DefaultOpts.Address
+	if opts.Address != 0 {
+		if opts.Address < 0x40 || opts.Address > 0x4f {
+			return nil, errAddressOutOfRange
+		}
+		i2cAddress = opts.Address
+	}
+
+	dev := &Dev{c: i2c.Dev{Bus: bus, Addr: uint16(i2cAddress)}}
+	if err := dev.checkID(); err != nil {
+		return nil, err
+	}
+	// Continuous current and voltage, 1.1ms conversions, no averaging.
+	if err := dev.writeRegister(configRegister, 0x6127); err != nil {
+		return nil, errWritingToConfigRegister
+	}
+	return dev, nil
+}
+
+// Dev is a handle to the ina260 sensor.
+type Dev struct {
+	c i2c.Dev
+
+	mu   sync.Mutex
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func (d *Dev) String() string {
+	return "INA260{" + strconv.Itoa(int(d.c.Addr)) + "}"
+}
+
+// Sense reads the power values from the sensor.
+func (d *Dev) Sense() (PowerReading, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.sense()
+}
+
+// Voltage reads the bus voltage.
+//
+// Voltage implements devices.PowerSensor.
+func (d *Dev) Voltage() (physic.ElectricPotential, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.voltage()
+}
+
+// Current reads the current through the integrated shunt.
+//
+// Current implements devices.PowerSensor.
+func (d *Dev) Current() (physic.ElectricCurrent, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.current()
+}
+
+// Power reads the power drawn by the load.
+//
+// Power implements devices.PowerSensor.
+func (d *Dev) Power() (physic.Power, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.power()
+}
+
+// SensePower reads the power values from the sensor.
+//
+// SensePower implements devices.PowerSensor.
+func (d *Dev) SensePower(e *devices.PowerEnv) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	p, err := d.sense()
+	if err != nil {
+		return err
+	}
+	*e = devices.PowerEnv(p)
+	return nil
+}
+
+// PowerContinuous returns measurements on a continuous basis, one every
+// interval.
+//
+// The application must call Halt() to stop the sensing when done and close
+// the channel.
+func (d *Dev) PowerContinuous(interval time.Duration) (<-chan PowerReading, error) {
+	if interval <= 0 {
+		return nil, errors.New("ina260: interval must be positive")
+	}
+	if err := d.Halt(); err != nil {
+		return nil, err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	sensing := make(chan PowerReading)
+	stop := make(chan struct{})
+	d.stop = stop
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		defer close(sensing)
+		d.sensingContinuous(interval, sensing, stop)
+	}()
+	return sensing, nil
+}
+
+// Halt stops continuous sensing. The sensor keeps measuring in the
+// background.
+//
+// Halt implements conn.Resource.
+func (d *Dev) Halt() error {
+	d.mu.Lock()
+	if d.stop != nil {
+		close(d.stop)
+		d.stop = nil
+	}
+	d.mu.Unlock()
+	// The lock is released as the goroutine needs it to finish a reading.
+	d.wg.Wait()
+	return nil
+}
+
+// PowerReading represents measurements from the sensor.
+type PowerReading struct {
+	Voltage physic.ElectricPotential
+	Current physic.ElectricCurrent
+	Power   physic.Power
+}
+
+// String returns a PowerReading as string
+func (p PowerReading) String() string {
+	return fmt.Sprintf("Bus: %s, Current: %s, Power: %s", p.Voltage, p.Current, p.Power)
+}
+
+//
+
+const (
+	configRegister  = 0x00
+	currentRegister = 0x01
+	busRegister     = 0x02
+	powerRegister   = 0x03
+	mfgIDRegister   = 0xFE
+	dieIDRegister   = 0xFF
+
+	mfgID = 0x5449 // "TI"
+	dieID = 0x2270
+)
+
+func (d *Dev) checkID() error {
+	mfg, err := d.readRegister(mfgIDRegister)
+	if err != nil {
+		return fmt.Errorf("ina260: %v", err)
+	}
+	die, err := d.readRegister(dieIDRegister)
+	if err != nil {
+		return fmt.Errorf("ina260: %v", err)
+	}
+	if mfg != mfgID || die != dieID {
+		return fmt.Errorf("ina260: unexpected id %#04x %#04x", mfg, die)
+	}
+	return nil
+}
+
+func (d *Dev) sense() (PowerReading, error) {
+	var p PowerReading
+	var err error
+	if p.Current, err = d.current(); err != nil {
+		return PowerReading{}, err
+	}
+	if p.Voltage, err = d.voltage(); err != nil {
+		return PowerReading{}, err
+	}
+	if p.Power, err = d.power(); err != nil {
+		return PowerReading{}, err
+	}
+	return p, nil
+}
+
+func (d *Dev) current() (physic.ElectricCurrent, error) {
+	current, err := d.readRegister(currentRegister)
+	if err != nil {
+		return 0, errReadCurrent
+	}
+	// Least significant bit is 1.25mA.
+	return physic.ElectricCurrent(int16(current)) * 1250 * physic.MicroAmpere, nil
+}
+
+func (d *Dev) voltage() (physic.ElectricPotential, error) {
+	bus, err := d.readRegister(busRegister)
+	if err != nil {
+		return 0, errReadBus
+	}
+	// Least significant bit is 1.25mV.
+	return physic.ElectricPotential(bus) * 1250 * physic.MicroVolt, nil
+}
+
+func (d *Dev) power() (physic.Power, error) {
+	power, err := d.readRegister(powerRegister)
+	if err != nil {
+		return 0, errReadPower
+	}
+	// Least significant bit is 10mW.
+	return physic.Power(power) * 10 * physic.MilliWatt, nil
+}
+
+func (d *Dev) sensingContinuous(interval time.Duration, sensing chan<- PowerReading, stop <-chan struct{}) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		d.mu.Lock()
+		p, err := d.sense()
+		d.mu.Unlock()
+		if err != nil {
+			log.Printf("%s: failed to sense: %v", d, err)
+			return
+		}
+		select {
+		case sensing <- p:
+		case <-stop:
+			return
+		}
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// readRegister reads a big endian 16 bits register.
+func (d *Dev) readRegister(reg uint8) (uint16, error) {
+	var b [2]byte
+	if err := d.c.Tx([]byte{reg}, b[:]); err != nil {
+		return 0, err
+	}
+	return uint16(b[0])<<8 | uint16(b[1]), nil
+}
+
+// writeRegister writes a big endian 16 bits register.
+func (d *Dev) writeRegister(reg uint8, v uint16) error {
+	return d.c.Tx([]byte{reg, byte(v >> 8), byte(v)}, nil)
+}
+
+var (
+	errReadBus                 = errors.New("ina260: failed to read bus voltage")
+	errReadPower               = errors.New("ina260: failed to read power")
+	errReadCurrent             = errors.New("ina260: failed to read current")
+	errAddressOutOfRange       = errors.New("ina260: i2c address out of range")
+	errWritingToConfigRegister = errors.New("ina260: failed to write to configuration register")
+)
+
+var _ conn.Resource = &Dev{}
+var _ devices.PowerSensor = &Dev{}
@@ -0,0 +1,139 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ina260
+
+import (
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3/i2c/i2ctest"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/devices/v3"
+	"periph.io/x/devices/v3/devicetest"
+)
+
+var initOps = []i2ctest.IO{
+	{Addr: 0x40, W: []byte{0xfe}, R: []byte{0x54, 0x49}},
+	{Addr: 0x40, W: []byte{0xff}, R: []byte{0x22, 0x70}},
+	{Addr: 0x40, W: []byte{configRegister, 0x61, 0x27}},
+}
+
+// senseOps is 10A, 12V and 120W.
+var senseOps = []i2ctest.IO{
+	{Addr: 0x40, W: []byte{currentRegister}, R: []byte{0x1f, 0x40}},
+	{Addr: 0x40, W: []byte{busRegister}, R: []byte{0x25, 0x80}},
+	{Addr: 0x40, W: []byte{powerRegister}, R: []byte{0x2e, 0xe0}},
+}
+
+func TestNew(t *testing.T) {
+	var tests = []struct {
+		name string
+		opts Opts
+		tx   []i2ctest.IO
+		err  bool
+	}{
+		{name: "default", tx: initOps},
+		{name: "badAddressOption", opts: Opts{Address: 0x60}, err: true},
+		{name: "wrongDevice",
+			tx: []i2ctest.IO{
+				{Addr: 0x40, W: []byte{0xfe}, R: []byte{0x54, 0x49}},
+				{Addr: 0x40, W: []byte{0xff}, R: []byte{0x22, 0x60}},
+			},
+			err: true,
+		},
+		{name: "configFail", tx: initOps[:2], err: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			bus := &i2ctest.Playback{Ops: test.tx, DontPanic: true}
+			_, err := New(bus, &test.opts)
+			if (err != nil) != test.err {
+				t.Fatalf("got error %v", err)
+			}
+		})
+	}
+}
+
+func TestSense(t *testing.T) {
+	bus := &i2ctest.Playback{Ops: append(append([]i2ctest.IO{}, initOps...), senseOps...), DontPanic: true}
+	d, err := New(bus, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := d.String(); s != "INA260{64}" {
+		t.Fatal(s)
+	}
+	p, err := d.Sense()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := PowerReading{Voltage: 12 * physic.Volt, Current: 10 * physic.Ampere, Power: 120 * physic.Watt}
+	if p != want {
+		t.Fatal(p)
+	}
+	if s := p.String(); s != "Bus: 12V, Current: 10A, Power: 120W" {
+		t.Fatal(s)
+	}
+	if _, err := d.Sense(); err == nil {
+		t.Fatal("expected error")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPowerSensor(t *testing.T) {
+	ops := append(append([]i2ctest.IO{}, initOps...), senseOps...)
+	ops = append(ops, senseOps[1], senseOps[0], senseOps[2])
+	bus := &i2ctest.Playback{Ops: ops}
+	d, err := New(bus, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	devicetest.PowerSensor(t, d, devices.PowerEnv{Voltage: 12 * physic.Volt, Current: 10 * physic.Ampere, Power: 120 * physic.Watt})
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPowerContinuous(t *testing.T) {
+	ops := append(append(append([]i2ctest.IO{}, initOps...), senseOps...), senseOps...)
+	bus := &i2ctest.Playback{Ops: ops, DontPanic: true}
+	d, err := New(bus, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.PowerContinuous(0); err == nil {
+		t.Fatal("expected error")
+	}
+	c, err := d.PowerContinuous(time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if p := <-c; p.Power != 120*physic.Watt {
+			t.Fatal(p)
+		}
+	}
+	// The playback is exhausted, the next reading fails and closes the
+	// channel.
+	if _, ok := <-c; ok {
+		t.Fatal("channel not closed")
+	}
+	if err := d.Halt(); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err = d.PowerContinuous(time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := <-c; ok {
+		t.Fatal("channel not closed")
+	}
+}
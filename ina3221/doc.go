@@ -0,0 +1,18 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package ina3221 controls a Texas Instruments ina3221 triple channel shunt
+// and bus voltage monitor over an i2c bus.
+//
+// # Current and power
+//
+// The ina3221 only measures voltages. The current of each channel is derived
+// from the shunt voltage and the shunt resistor value, and the power from the
+// current and the bus voltage. To get accurate current readings, measure the
+// actual value of the shunt resistors.
+//
+// # Datasheet
+//
+// https://www.ti.com/lit/ds/symlink/ina3221.pdf
+package ina3221
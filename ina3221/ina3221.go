@@ -0,0 +1,266 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ina3221
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/mmr"
+	"periph.io/x/conn/v3/physic"
+)
+
+// Channel is one of the three monitored channels.
+type Channel int
+
+const (
+	Channel1 Channel = 0
+	Channel2 Channel = 1
+	Channel3 Channel = 2
+)
+
+func (c Channel) String() string {
+	return fmt.Sprintf("CH%d", int(c)+1)
+}
+
+// Opts holds the configuration options.
+//
+// # Slave Address
+//
+// Depending which pin the A0 pin is connected to will change the slave
+// address. Default configuration is address 0x40 (A0 to GND). For a full
+// address table see datasheet.
+type Opts struct {
+	Address int
+	// SenseResistors is the value of the shunt resistor of each channel. Zero
+	// values use the default.
+	SenseResistors [3]physic.ElectricResistance
+}
+
+// DefaultOpts is the recommended default options.
+var DefaultOpts = Opts{
+	Address: 0x40,
+	SenseResistors: [3]physic.ElectricResistance{
+		100 * physic.MilliOhm,
+		100 * physic.MilliOhm,
+		100 * physic.MilliOhm,
+	},
+}
+
+// New opens a handle to an ina3221 sensor.
+//
+// All channels are enabled in continuous shunt and bus voltage mode.
+func New(bus i2c.Bus, opts *Opts) (*Dev, error) {
+	i2cAddress := DefaultOpts.Address
+	if opts.Address != 0 {
+		if opts.Address < 0x40 || opts.Address > 0x43 {
+			return nil, errAddressOutOfRange
+		}
+		i2cAddress = opts.Address
+	}
+
+	dev := &Dev{
+		m: mmr.Dev8{
+			Conn:  &i2c.Dev{Bus: bus, Addr: uint16(i2cAddress)},
+			Order: binary.BigEndian,
+		},
+		config: defaultConfig,
+	}
+	for i, r := range opts.SenseResistors {
+		if r < 0 {
+			return nil, errSenseResistorValueInvalid
+		}
+		if r == 0 {
+			r = DefaultOpts.SenseResistors[i]
+		}
+		dev.sense[i] = r
+	}
+
+	if err := dev.m.WriteUint16(configRegister, dev.config); err != nil {
+		return nil, errWritingToConfigRegister
+	}
+	return dev, nil
+}
+
+// Dev is a handle to the ina3221 sensor.
+type Dev struct {
+	m mmr.Dev8
+
+	mu     sync.Mutex
+	sense  [3]physic.ElectricResistance
+	config uint16
+}
+
+const (
+	configRegister     = 0x00
+	shuntVoltageBase   = 0x01 // Channel n shunt voltage is at 0x01 + 2*n.
+	busVoltageBase     = 0x02 // Channel n bus voltage is at 0x02 + 2*n.
+	criticalAlertBase  = 0x07 // Channel n critical alert limit is at 0x07 + 2*n.
+	warningAlertBase   = 0x08 // Channel n warning alert limit is at 0x08 + 2*n.
+	maskEnableRegister = 0x0f
+
+	// defaultConfig enables all channels, averages 1 sample with 1.1ms
+	// conversion times in continuous shunt and bus mode. This is the power on
+	// value.
+	defaultConfig = 0x7127
+)
+
+// SetChannelEnabled enables or disables the measurement of a channel.
+func (d *Dev) SetChannelEnabled(c Channel, enabled bool) error {
+	if c < Channel1 || c > Channel3 {
+		return errInvalidChannel
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	bit := uint16(1) << (14 - uint(c))
+	config := d.config &^ bit
+	if enabled {
+		config |= bit
+	}
+	if err := d.m.WriteUint16(configRegister, config); err != nil {
+		return errWritingToConfigRegister
+	}
+	d.config = config
+	return nil
+}
+
+// ChannelEnabled returns true if the channel is being measured.
+func (d *Dev) ChannelEnabled(c Channel) bool {
+	if c < Channel1 || c > Channel3 {
+		return false
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.config&(1<<(14-uint(c))) != 0
+}
+
+// Sense reads the power values of all the enabled channels.
+func (d *Dev) Sense() ([]PowerMonitor, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var out []PowerMonitor
+	for c := Channel1; c <= Channel3; c++ {
+		if d.config&(1<<(14-uint(c))) == 0 {
+			continue
+		}
+		pm, err := d.senseChannel(c)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, pm)
+	}
+	return out, nil
+}
+
+// SenseChannel reads the power values of a single channel.
+func (d *Dev) SenseChannel(c Channel) (PowerMonitor, error) {
+	if c < Channel1 || c > Channel3 {
+		return PowerMonitor{}, errInvalidChannel
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.senseChannel(c)
+}
+
+// SetCriticalAlert sets the current above which the critical alert pin is
+// asserted for the channel. The comparison is done on each conversion.
+func (d *Dev) SetCriticalAlert(c Channel, limit physic.ElectricCurrent) error {
+	return d.setAlert(criticalAlertBase, c, limit)
+}
+
+// SetWarningAlert sets the current above which the warning alert pin is
+// asserted for the channel. The comparison is done on the averaged values.
+func (d *Dev) SetWarningAlert(c Channel, limit physic.ElectricCurrent) error {
+	return d.setAlert(warningAlertBase, c, limit)
+}
+
+// Alerts returns the critical and warning alert flags of each channel.
+//
+// Reading the flags clears them.
+func (d *Dev) Alerts() (critical, warning [3]bool, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	v, err := d.m.ReadUint16(maskEnableRegister)
+	if err != nil {
+		return critical, warning, errReadMaskEnable
+	}
+	for c := 0; c < 3; c++ {
+		critical[c] = v&(1<<(9-uint(c))) != 0
+		warning[c] = v&(1<<(5-uint(c))) != 0
+	}
+	return critical, warning, nil
+}
+
+func (d *Dev) senseChannel(c Channel) (PowerMonitor, error) {
+	pm := PowerMonitor{Channel: c}
+
+	shunt, err := d.m.ReadUint16(shuntVoltageBase + 2*uint8(c))
+	if err != nil {
+		return PowerMonitor{}, errReadShunt
+	}
+	// Least significant bit is 40µV, the 3 lowest bits are unused.
+	pm.Shunt = physic.ElectricPotential(int16(shunt)>>3) * 40 * physic.MicroVolt
+
+	bus, err := d.m.ReadUint16(busVoltageBase + 2*uint8(c))
+	if err != nil {
+		return PowerMonitor{}, errReadBus
+	}
+	// Least significant bit is 8mV, the 3 lowest bits are unused.
+	pm.Voltage = physic.ElectricPotential(int16(bus)>>3) * 8 * physic.MilliVolt
+
+	// I = V / R, physic units are nano so scale to keep the resolution.
+	pm.Current = physic.ElectricCurrent(int64(pm.Shunt) * int64(physic.Ohm) / int64(d.sense[c]))
+	// P = V * I, done in micro units to not overflow int64.
+	pm.Power = physic.Power((int64(pm.Voltage) / 1000) * (int64(pm.Current) / 1000) / 1000)
+	return pm, nil
+}
+
+func (d *Dev) setAlert(base uint8, c Channel, limit physic.ElectricCurrent) error {
+	if c < Channel1 || c > Channel3 {
+		return errInvalidChannel
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	// V = I * R, in nano volts.
+	v := int64(limit) * int64(d.sense[c]) / int64(physic.Ohm)
+	raw := v / int64(40*physic.MicroVolt)
+	if raw < -4096 || raw > 4095 {
+		return errAlertOutOfRange
+	}
+	if err := d.m.WriteUint16(base+2*uint8(c), uint16(raw<<3)); err != nil {
+		return errWritingAlert
+	}
+	return nil
+}
+
+// PowerMonitor represents measurements from a channel of the ina3221 sensor.
+type PowerMonitor struct {
+	Channel Channel
+	Shunt   physic.ElectricPotential
+	Voltage physic.ElectricPotential
+	Current physic.ElectricCurrent
+	Power   physic.Power
+}
+
+// String returns a PowerMonitor as string
+func (p PowerMonitor) String() string {
+	return fmt.Sprintf("%s: Bus: %s, Current: %s, Power: %s, Shunt: %s", p.Channel, p.Voltage, p.Current, p.Power, p.Shunt)
+}
+
+var (
+	errReadShunt                 = errors.New("ina3221: failed to read shunt voltage")
+	errReadBus                   = errors.New("ina3221: failed to read bus voltage")
+	errReadMaskEnable            = errors.New("ina3221: failed to read mask/enable register")
+	errAddressOutOfRange         = errors.New("ina3221: i2c address out of range")
+	errSenseResistorValueInvalid = errors.New("ina3221: sense resistor value cannot be negative")
+	errInvalidChannel            = errors.New("ina3221: invalid channel")
+	errAlertOutOfRange           = errors.New("ina3221: alert limit out of range")
+	errWritingAlert              = errors.New("ina3221: failed to write alert limit")
+	errWritingToConfigRegister   = errors.New("ina3221: failed to write to configuration register")
+)
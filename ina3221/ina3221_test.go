@@ -0,0 +1,169 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ina3221
+
+import (
+	"reflect"
+	"testing"
+
+	"periph.io/x/conn/v3/i2c/i2ctest"
+	"periph.io/x/conn/v3/physic"
+)
+
+func TestNew(t *testing.T) {
+	var tests = []struct {
+		name string
+		opts Opts
+		tx   []i2ctest.IO
+		err  error
+	}{
+		{name: "defaults",
+			tx: []i2ctest.IO{
+				{Addr: 0x40, W: []byte{configRegister, 0x71, 0x27}},
+			},
+		},
+		{name: "setAddress",
+			opts: Opts{Address: 0x43},
+			tx: []i2ctest.IO{
+				{Addr: 0x43, W: []byte{configRegister, 0x71, 0x27}},
+			},
+		},
+		{name: "badAddressOption",
+			opts: Opts{Address: 0x44},
+			err:  errAddressOutOfRange,
+		},
+		{name: "badSenseResistorOption",
+			opts: Opts{SenseResistors: [3]physic.ElectricResistance{0, -1, 0}},
+			err:  errSenseResistorValueInvalid,
+		},
+		{name: "errWritingToConfigRegister",
+			tx: []i2ctest.IO{
+				{Addr: 0x40, W: []byte{configRegister}},
+			},
+			err: errWritingToConfigRegister,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			bus := &i2ctest.Playback{Ops: test.tx, DontPanic: true}
+			_, err := New(bus, &test.opts)
+			if err != test.err {
+				t.Fatalf("wanted err: %v, but got: %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestSense(t *testing.T) {
+	bus := &i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x40, W: []byte{configRegister, 0x71, 0x27}},
+			// Channel 2 is disabled.
+			{Addr: 0x40, W: []byte{configRegister, 0x51, 0x27}},
+			{Addr: 0x40, W: []byte{0x01}, R: []byte{0x0c, 0x80}},
+			{Addr: 0x40, W: []byte{0x02}, R: []byte{0x2e, 0xe0}},
+			{Addr: 0x40, W: []byte{0x05}, R: []byte{0xff, 0xf8}},
+			{Addr: 0x40, W: []byte{0x06}, R: []byte{0x00, 0x00}},
+		},
+	}
+	opts := Opts{SenseResistors: [3]physic.ElectricResistance{0, 0, 10 * physic.MilliOhm}}
+	dev, err := New(bus, &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.SetChannelEnabled(Channel2, false); err != nil {
+		t.Fatal(err)
+	}
+	if dev.ChannelEnabled(Channel2) || !dev.ChannelEnabled(Channel1) {
+		t.Fatal("unexpected channel state")
+	}
+	got, err := dev.Sense()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []PowerMonitor{
+		{
+			Channel: Channel1,
+			Shunt:   16 * physic.MilliVolt,
+			Voltage: 12 * physic.Volt,
+			Current: 160 * physic.MilliAmpere,
+			Power:   1920 * physic.MilliWatt,
+		},
+		{
+			Channel: Channel3,
+			Shunt:   -40 * physic.MicroVolt,
+			Current: -4 * physic.MilliAmpere,
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("wanted: %v, but got: %v", want, got)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSenseChannel_error(t *testing.T) {
+	bus := &i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x40, W: []byte{configRegister, 0x71, 0x27}},
+			{Addr: 0x40, W: []byte{0x03}, R: []byte{0x00, 0x00}},
+			{Addr: 0x40, W: []byte{0x04}},
+		},
+		DontPanic: true,
+	}
+	dev, err := New(bus, &Opts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dev.SenseChannel(Channel2); err != errReadBus {
+		t.Fatalf("wanted err: %v, but got: %v", errReadBus, err)
+	}
+	if _, err := dev.SenseChannel(Channel(3)); err != errInvalidChannel {
+		t.Fatalf("wanted err: %v, but got: %v", errInvalidChannel, err)
+	}
+}
+
+func TestAlerts(t *testing.T) {
+	bus := &i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x40, W: []byte{configRegister, 0x71, 0x27}},
+			{Addr: 0x40, W: []byte{0x07, 0x27, 0x10}},
+			{Addr: 0x40, W: []byte{0x0c, 0x13, 0x88}},
+			{Addr: 0x40, W: []byte{maskEnableRegister}, R: []byte{0x02, 0x08}},
+		},
+	}
+	dev, err := New(bus, &Opts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.SetCriticalAlert(Channel1, 500*physic.MilliAmpere); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.SetWarningAlert(Channel3, 250*physic.MilliAmpere); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.SetWarningAlert(Channel3, 2*physic.Ampere); err != errAlertOutOfRange {
+		t.Fatalf("wanted err: %v, but got: %v", errAlertOutOfRange, err)
+	}
+	critical, warning, err := dev.Alerts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if critical != [3]bool{true, false, false} || warning != [3]bool{false, false, true} {
+		t.Fatalf("unexpected alerts %v %v", critical, warning)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPowerStringer(t *testing.T) {
+	p := PowerMonitor{Channel: Channel2, Shunt: 1, Voltage: 1, Current: 1, Power: 1}
+	want := "CH2: Bus: 1nV, Current: 1nA, Power: 1nW, Shunt: 1nV"
+	if got := p.String(); want != got {
+		t.Errorf("wanted %s\n, but got: %s", want, got)
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package inky
+
+import (
+	"time"
+
+	"periph.io/x/conn/v3/gpio"
+)
+
+// BusyPinMode selects how the busy pin is used to wait for the display.
+type BusyPinMode int
+
+const (
+	// BusyEdgePerWait enables edge detection on the busy pin before each wait
+	// and disables it afterward. This is the default.
+	BusyEdgePerWait BusyPinMode = iota
+	// BusyEdgeHeld enables edge detection on the busy pin once when the
+	// device is created and keeps it until Close. This avoids requesting the
+	// GPIO line again for every wait, which can fail with "line_request ioctl:
+	// bad file descriptor" when other processes use the same GPIO chip.
+	BusyEdgeHeld
+	// BusyPoll never enables edge detection and polls the level of the busy
+	// pin instead. Use it for pins already acquired with edge detection
+	// elsewhere, or that don't support it.
+	BusyPoll
+)
+
+// busyPollInterval is the interval at which the busy pin is read in BusyPoll
+// mode.
+var busyPollInterval = 10 * time.Millisecond
+
+// Close releases the edge detection held on the busy pin in BusyEdgeHeld mode.
+func (d *Dev) Close() error {
+	if d.busyMode == BusyEdgeHeld {
+		return d.busy.In(d.busyPull, gpio.NoEdge)
+	}
+	return nil
+}
+
+// initBusy configures the busy pin when the device is created.
+func (d *Dev) initBusy() error {
+	switch d.busyMode {
+	case BusyEdgePerWait:
+		return nil
+	case BusyEdgeHeld:
+		return d.busy.In(d.busyPull, d.busyEdge)
+	default:
+		return d.busy.In(d.busyPull, gpio.NoEdge)
+	}
+}
+
+// armBusy prepares the busy pin before a command that makes the display busy.
+func (d *Dev) armBusy() error {
+	if d.busyMode != BusyEdgePerWait {
+		return nil
+	}
+	return d.busy.In(d.busyPull, d.busyEdge)
+}
+
+// disarmBusy undoes armBusy.
+func (d *Dev) disarmBusy() error {
+	if d.busyMode != BusyEdgePerWait {
+		return nil
+	}
+	return d.busy.In(d.busyPull, gpio.NoEdge)
+}
+
+// waitBusy waits for the display to be ready, up to timeout. Use -1 to wait
+// forever.
+func (d *Dev) waitBusy(timeout time.Duration) {
+	if d.busyMode != BusyPoll {
+		d.busy.WaitForEdge(timeout)
+		return
+	}
+	ready := gpio.Level(d.busyEdge == gpio.RisingEdge)
+	start := time.Now()
+	for d.busy.Read() != ready {
+		if timeout >= 0 && time.Since(start) >= timeout {
+			return
+		}
+		time.Sleep(busyPollInterval)
+	}
+}
@@ -12,4 +12,16 @@
 //
 // The display seems to use a SSD1675 controller:
 // https://www.china-epaper.com/uploads/soft/DEPG0420R01V3.0.pdf
+//
+// # Busy pin
+//
+// By default edge detection is enabled on the busy pin for each wait. When
+// this conflicts with other users of the GPIO chip, set Opts.BusyPinMode to
+// hold the edge detection for the lifetime of the device or to poll the pin.
+//
+// # Simulation
+//
+// NewSimulated and NewSimulatedImpression return devices that render to a PNG
+// file or to memory instead of the panel, to iterate on layouts without the
+// slow refresh of the hardware.
 package inky
@@ -6,11 +6,18 @@ package inky_test
 
 import (
 	"flag"
+	"fmt"
 	"image"
+	"image/color"
+	"image/draw"
 	"image/png"
 	"log"
 	"os"
 
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+
 	"periph.io/x/conn/v3/gpio/gpioreg"
 	"periph.io/x/conn/v3/i2c/i2creg"
 	"periph.io/x/conn/v3/spi/spireg"
@@ -60,6 +67,44 @@ func Example() {
 	}
 }
 
+func ExampleDev_Set() {
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	b, err := spireg.Open("SPI0.0")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	dc := gpioreg.ByName("22")
+	reset := gpioreg.ByName("27")
+	busy := gpioreg.ByName("17")
+
+	dev, err := inky.New(b, dc, reset, busy, &inky.Opts{
+		Model:       inky.PHAT,
+		ModelColor:  inky.Red,
+		BorderColor: inky.Black,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Render the text directly into the device, then refresh the panel once.
+	draw.Draw(dev, dev.Bounds(), &image.Uniform{color.White}, image.Point{}, draw.Src)
+	f := basicfont.Face7x13
+	drawer := font.Drawer{
+		Dst:  dev,
+		Src:  &image.Uniform{color.RGBA{R: 255, A: 255}},
+		Face: f,
+		Dot:  fixed.P(0, f.Ascent),
+	}
+	drawer.DrawString("Hello from periph!")
+	if err := dev.Render(); err != nil {
+		log.Fatal(err)
+	}
+}
+
 func ExampleNewImpression() {
 	path := flag.String("image", "", "Path to image file (600x448) to display")
 	flag.Parse()
@@ -108,3 +153,24 @@ func ExampleNewImpression() {
 		log.Fatal(err)
 	}
 }
+
+func ExampleNewSimulated() {
+	// Simulate a Red Inky pHAT. Pass a path to also save the frames as PNG.
+	dev, err := inky.NewSimulated(&inky.Opts{Model: inky.PHAT, ModelColor: inky.Red}, "")
+	if err != nil {
+		log.Fatal(err)
+	}
+	img := image.NewRGBA(dev.Bounds())
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(0, 0, 10, 10), image.Black, image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(10, 0, 20, 10), &image.Uniform{color.RGBA{R: 200, A: 255}}, image.Point{}, draw.Src)
+	if err := dev.Draw(dev.Bounds(), img, image.Point{}); err != nil {
+		log.Fatal(err)
+	}
+	frame := dev.Frame()
+	fmt.Println(frame.Bounds())
+	fmt.Println(frame.At(0, 0), frame.At(10, 0), frame.At(20, 0))
+	// Output:
+	// (0,0)-(104,212)
+	// {0 0 0 255} {255 0 0 255} {255 255 255 255}
+}
@@ -93,6 +93,8 @@ type DevImpression struct {
 	saturation uint
 	// Resolution magic number used for resetting the panel.
 	res int
+	// Chunk of packed pixels sent to the display.
+	buf []byte
 }
 
 // NewImpression opens a handle to an Inky Impression.
@@ -100,6 +102,9 @@ func NewImpression(p spi.Port, dc gpio.PinOut, reset gpio.PinOut, busy gpio.PinI
 	if o.ModelColor != Multi {
 		return nil, fmt.Errorf("unsupported color: %v", o.ModelColor)
 	}
+	if o.BusyPinMode < BusyEdgePerWait || o.BusyPinMode > BusyPoll {
+		return nil, fmt.Errorf("unsupported busy pin mode: %d", o.BusyPinMode)
+	}
 
 	c, err := p.Connect(3000*physic.KiloHertz, spi.Mode0, cs0Pin)
 	if err != nil {
@@ -123,6 +128,9 @@ func NewImpression(p spi.Port, dc gpio.PinOut, reset gpio.PinOut, busy gpio.PinI
 			dc:         dc,
 			r:          reset,
 			busy:       busy,
+			busyMode:   o.BusyPinMode,
+			busyPull:   gpio.PullDown,
+			busyEdge:   gpio.RisingEdge,
 			color:      o.ModelColor,
 			border:     o.BorderColor,
 			model:      o.Model,
@@ -131,7 +139,15 @@ func NewImpression(p spi.Port, dc gpio.PinOut, reset gpio.PinOut, busy gpio.PinI
 		},
 		saturation: 50, // Looks good enough for most of the images.
 	}
+	if err := d.initBusy(); err != nil {
+		return nil, err
+	}
+	d.setSize(o)
+	return d, nil
+}
 
+// setSize sets the size of the panel of the model, or the one in o if set.
+func (d *DevImpression) setSize(o *Opts) {
 	switch o.Model {
 	case IMPRESSION4:
 		d.width = 640
@@ -143,15 +159,13 @@ func NewImpression(p spi.Port, dc gpio.PinOut, reset gpio.PinOut, busy gpio.PinI
 		d.res = 0b11
 	}
 	// Prefer the passed in values via Opts.
-	if o.Width == 0 && o.Height == 0 {
+	if o.Width != 0 && o.Height != 0 {
 		d.width = o.Width
 		d.height = o.Height
 	}
 	d.bounds = image.Rect(0, 0, d.width, d.height)
 
 	d.Pix = make([]uint8, d.height*d.width)
-
-	return d, nil
 }
 
 // blend recalculates the palette based on the saturation level.
@@ -199,29 +213,14 @@ func (d *DevImpression) SetBorder(c ImpressionColor) {
 }
 
 // Render renders the content of the Pix to the screen.
+//
+// The pixels are packed and flipped while being sent, in chunks of the
+// maximum SPI transfer size, so Pix is left untouched.
 func (d *DevImpression) Render() error {
-	if d.flipVertically {
-		for w := 0; w < len(d.Pix)/2-1; w = w + d.width {
-			for offset := 0; offset < d.width; offset++ {
-				d.Pix[w+offset], d.Pix[len(d.Pix)-d.width-w+offset] = d.Pix[len(d.Pix)-d.width-w+offset], d.Pix[w+offset]
-			}
-		}
+	if d.sim != nil {
+		return d.sim.show(d.frame())
 	}
-
-	if d.flipHorizontally {
-		for offset := 0; offset < len(d.Pix)-1; offset = offset + d.width {
-			for i, j := 0, d.width-1; i < j; i, j = i+1, j-1 {
-				d.Pix[i+offset], d.Pix[j+offset] = d.Pix[j+offset], d.Pix[i+offset]
-			}
-		}
-	}
-
-	merged := make([]uint8, len(d.Pix)/2)
-	for i, offset := 0, 0; i < len(d.Pix)-1; i, offset = i+2, offset+1 {
-		merged[offset] = (d.Pix[i]<<4)&0xF0 | d.Pix[i+1]&0x0F
-	}
-
-	return d.update(merged)
+	return d.update()
 }
 
 func (d *DevImpression) reset() error {
@@ -331,12 +330,12 @@ func (d *DevImpression) reset() error {
 	return nil
 }
 
-func (d *DevImpression) update(pix []uint8) error {
+func (d *DevImpression) update() error {
 	if err := d.reset(); err != nil {
 		return err
 	}
 
-	if err := d.sendCommand(uc8159DTM1, pix); err != nil {
+	if err := d.sendPix(); err != nil {
 		return err
 	}
 
@@ -358,15 +357,65 @@ func (d *DevImpression) update(pix []uint8) error {
 	return nil
 }
 
+// sendPix sends Pix to the display RAM, two pixels per byte. The bytes are
+// packed in a chunk reused across transfers instead of a full frame copy.
+func (d *DevImpression) sendPix() error {
+	if err := d.sendCommand(uc8159DTM1, nil); err != nil {
+		return err
+	}
+	if err := d.dc.Out(gpio.High); err != nil {
+		return err
+	}
+	n := len(d.Pix) / 2
+	if l := min(d.maxTxSize, n); len(d.buf) != l {
+		d.buf = make([]byte, l)
+	}
+	for offset := 0; offset < n; {
+		chunk := d.buf[:min(len(d.buf), n-offset)]
+		d.packPix(chunk, 2*offset)
+		if err := d.c.Tx(chunk, nil); err != nil {
+			return fmt.Errorf("failed to send data for command %x to inky: %v", uc8159DTM1, err)
+		}
+		offset += len(chunk)
+	}
+	return nil
+}
+
+// packPix packs the pixels starting at index i in Pix into dst, applying the
+// flips.
+func (d *DevImpression) packPix(dst []byte, i int) {
+	if !d.flipVertically && !d.flipHorizontally {
+		for j := range dst {
+			dst[j] = d.Pix[i+2*j]<<4 | d.Pix[i+2*j+1]&0x0F
+		}
+		return
+	}
+	for j := range dst {
+		dst[j] = d.flippedPix(i+2*j)<<4 | d.flippedPix(i+2*j+1)&0x0F
+	}
+}
+
+// flippedPix returns the pixel of Pix displayed at index i.
+func (d *DevImpression) flippedPix(i int) uint8 {
+	x, y := i%d.width, i/d.width
+	if d.flipHorizontally {
+		x = d.width - 1 - x
+	}
+	if d.flipVertically {
+		y = d.height - 1 - y
+	}
+	return d.Pix[y*d.width+x]
+}
+
 // Wait for busy/wait pin.
 func (d *DevImpression) wait(dur time.Duration) {
 	// Set it as input, with a pull down and enable rising edge triggering.
-	if err := d.busy.In(gpio.PullDown, gpio.RisingEdge); err != nil {
+	if err := d.armBusy(); err != nil {
 		log.Printf("Err: %s", err)
 		return
 	}
 	// Wait for rising edges (Low -> High) or the timeout.
-	d.busy.WaitForEdge(dur)
+	d.waitBusy(dur)
 }
 
 // ColorModel returns the device native color model.
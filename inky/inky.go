@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"image"
 	"image/color"
+	"image/draw"
 	"time"
 
 	"periph.io/x/conn/v3"
@@ -20,6 +21,7 @@ import (
 
 var _ display.Drawer = &Dev{}
 var _ conn.Resource = &Dev{}
+var _ draw.Image = &Dev{}
 
 const (
 	cs0Pin = 8
@@ -43,6 +45,10 @@ type Dev struct {
 	r gpio.PinOut
 	// High when device is busy.
 	busy gpio.PinIn
+	// How the busy pin is used, and its configuration.
+	busyMode BusyPinMode
+	busyPull gpio.Pull
+	busyEdge gpio.Edge
 	// Size of this model's display.
 	bounds image.Rectangle
 	// Whether this model needs the image flipped vertically.
@@ -65,6 +71,12 @@ type Dev struct {
 	variant uint
 	// PCB Variant of the panel. Represents a version string as a number (12 -> 1.2).
 	pcbVariant uint
+
+	// Pixels drawn with Set, one of pixWhite, pixBlack or pixRed.
+	pix []uint8
+
+	// sim replaces the panel of the devices returned by NewSimulated.
+	sim *simulator
 }
 
 // New opens a handle to an Inky pHAT or wHAT.
@@ -72,6 +84,9 @@ func New(p spi.Port, dc gpio.PinOut, reset gpio.PinOut, busy gpio.PinIn, o *Opts
 	if o.ModelColor != Black && o.ModelColor != Red && o.ModelColor != Yellow {
 		return nil, fmt.Errorf("unsupported color: %v", o.ModelColor)
 	}
+	if o.BusyPinMode < BusyEdgePerWait || o.BusyPinMode > BusyPoll {
+		return nil, fmt.Errorf("unsupported busy pin mode: %d", o.BusyPinMode)
+	}
 
 	c, err := p.Connect(488*physic.KiloHertz, spi.Mode0, cs0Pin)
 	if err != nil {
@@ -94,13 +109,24 @@ func New(p spi.Port, dc gpio.PinOut, reset gpio.PinOut, busy gpio.PinIn, o *Opts
 		dc:         dc,
 		r:          reset,
 		busy:       busy,
+		busyMode:   o.BusyPinMode,
+		busyPull:   gpio.PullUp,
+		busyEdge:   gpio.FallingEdge,
 		color:      o.ModelColor,
 		border:     o.BorderColor,
 		model:      o.Model,
 		variant:    o.DisplayVariant,
 		pcbVariant: o.PCBVariant,
 	}
+	if err := d.initBusy(); err != nil {
+		return nil, err
+	}
+	d.setSize(o)
+	return d, nil
+}
 
+// setSize sets the size of the panel of the model, or the one in o if set.
+func (d *Dev) setSize(o *Opts) {
 	switch o.Model {
 	case PHAT:
 		d.width = 104
@@ -115,12 +141,12 @@ func New(p spi.Port, dc gpio.PinOut, reset gpio.PinOut, busy gpio.PinIn, o *Opts
 		d.height = 300
 	}
 	// Prefer the passed in values via Opts.
-	if o.Width == 0 && o.Height == 0 {
+	if o.Width != 0 && o.Height != 0 {
 		d.width = o.Width
 		d.height = o.Height
 	}
 	d.bounds = image.Rect(0, 0, d.width, d.height)
-	return d, nil
+	d.pix = make([]uint8, d.width*d.height)
 }
 
 // SetBorder changes the border color. This will not take effect until the next Draw().
@@ -205,17 +231,39 @@ func (d *Dev) Bounds() image.Rectangle {
 	return d.bounds
 }
 
-// Draw implements display.Drawer
-func (d *Dev) Draw(dstRect image.Rectangle, src image.Image, srcPtrs image.Point) error {
-	if dstRect != d.Bounds() {
-		return fmt.Errorf("partial update not supported")
+// At returns the color of the pixel at (x, y), as set by Set or Draw.
+func (d *Dev) At(x, y int) color.Color {
+	if !image.Pt(x, y).In(d.bounds) {
+		return color.RGBA{}
 	}
+	return pixColors[d.pix[y*d.width+x]]
+}
 
-	if src.Bounds() != d.Bounds() {
-		return fmt.Errorf("image must be the same size as bounds: %v", d.Bounds())
+// Set sets the pixel at (x, y) to the given color, converted with
+// ColorModel. This will not take effect until the next Render().
+//
+// Set implements draw.Image so text and shape libraries can render directly
+// into the device.
+func (d *Dev) Set(x, y int, c color.Color) {
+	if !image.Pt(x, y).In(d.bounds) {
+		return
+	}
+	r, g, b, _ := d.ColorModel().Convert(c).RGBA()
+	p := uint8(pixBlack)
+	if r >= 0x8000 && g >= 0x8000 && b >= 0x8000 {
+		p = pixWhite
+	} else if r >= 0x8000 {
+		p = pixRed
 	}
+	d.pix[y*d.width+x] = p
+}
 
-	b := src.Bounds()
+// Render renders the pixels set with Set to the screen.
+func (d *Dev) Render() error {
+	if d.sim != nil {
+		return d.sim.show(d.frame())
+	}
+	b := d.Bounds()
 	// Black/white pixels.
 	white := make([]bool, b.Size().Y*b.Size().X)
 	// Red/Transparent pixels.
@@ -223,15 +271,14 @@ func (d *Dev) Draw(dstRect image.Rectangle, src image.Image, srcPtrs image.Point
 	for x := b.Min.X; x < b.Max.X; x++ {
 		for y := b.Min.Y; y < b.Max.Y; y++ {
 			i := y*b.Size().X + x
-			srcX := x
 			srcY := y
 			if d.flipVertically {
 				srcY = b.Max.Y - y - 1
 			}
-			r, g, b, _ := d.ColorModel().Convert(src.At(srcX, srcY)).RGBA()
-			if r >= 0x8000 && g >= 0x8000 && b >= 0x8000 {
+			switch d.pix[srcY*d.width+x] {
+			case pixWhite:
 				white[i] = true
-			} else if r >= 0x8000 {
+			case pixRed:
 				// Red pixels also need white behind them.
 				white[i] = true
 				red[i] = true
@@ -244,6 +291,25 @@ func (d *Dev) Draw(dstRect image.Rectangle, src image.Image, srcPtrs image.Point
 	return d.update(borderColor[d.border], bufA, bufB)
 }
 
+// Draw implements display.Drawer
+func (d *Dev) Draw(dstRect image.Rectangle, src image.Image, srcPtrs image.Point) error {
+	if dstRect != d.Bounds() {
+		return fmt.Errorf("partial update not supported")
+	}
+
+	if src.Bounds() != d.Bounds() {
+		return fmt.Errorf("image must be the same size as bounds: %v", d.Bounds())
+	}
+
+	b := src.Bounds()
+	for x := b.Min.X; x < b.Max.X; x++ {
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			d.Set(x, y, src.At(x, y))
+		}
+	}
+	return d.Render()
+}
+
 // DrawAll redraws the whole display.
 func (d *Dev) DrawAll(src image.Image) error {
 	return d.Draw(d.Bounds(), src, image.Point{})
@@ -296,21 +362,36 @@ func (d *Dev) update(border byte, black []byte, red []byte) error {
 		}
 	}
 
-	if err := d.busy.In(gpio.PullUp, gpio.FallingEdge); err != nil {
+	if err := d.armBusy(); err != nil {
 		return err
 	}
 	var err error
 	if err = d.sendCommand(0x20, nil); err == nil {
-		d.busy.WaitForEdge(-1)
+		d.waitBusy(-1)
 		// Enter deep sleep.
 		err = d.sendCommand(0x10, []byte{0x01})
 	}
-	if err2 := d.busy.In(gpio.PullUp, gpio.NoEdge); err2 != nil {
+	if err2 := d.disarmBusy(); err2 != nil {
 		err = err2
 	}
 	return err
 }
 
+// Values of Dev.pix.
+const (
+	pixWhite = iota
+	pixBlack
+	pixRed
+)
+
+// pixColors is the color returned by At for each pixel value, the ones
+// returned by ColorModel.
+var pixColors = [...]color.Color{
+	pixWhite: color.RGBA{R: 255, G: 255, B: 255, A: 255},
+	pixBlack: color.RGBA{A: 255},
+	pixRed:   color.RGBA{R: 255, A: 255},
+}
+
 func (d *Dev) reset() (err error) {
 	if err = d.r.Out(gpio.Low); err != nil {
 		return err
@@ -321,18 +402,18 @@ func (d *Dev) reset() (err error) {
 	}
 	time.Sleep(100 * time.Millisecond)
 
-	if err = d.busy.In(gpio.PullUp, gpio.FallingEdge); err != nil {
+	if err = d.armBusy(); err != nil {
 		return err
 	}
 	defer func() {
-		if err2 := d.busy.In(gpio.PullUp, gpio.NoEdge); err2 != nil {
+		if err2 := d.disarmBusy(); err2 != nil {
 			err = err2
 		}
 	}()
 	if err := d.sendCommand(0x12, nil); err != nil { // Soft Reset
 		return fmt.Errorf("failed to reset inky: %v", err)
 	}
-	d.busy.WaitForEdge(-1)
+	d.waitBusy(-1)
 	return
 }
 
@@ -0,0 +1,55 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package inky
+
+import (
+	"image"
+	"testing"
+
+	"periph.io/x/conn/v3/gpio/gpiotest"
+	"periph.io/x/conn/v3/spi/spitest"
+)
+
+func TestNew_bounds(t *testing.T) {
+	data := []struct {
+		o        Opts
+		expected image.Rectangle
+	}{
+		{Opts{Model: PHAT, ModelColor: Red}, image.Rect(0, 0, 104, 212)},
+		{Opts{Model: PHAT2, ModelColor: Black}, image.Rect(0, 0, 122, 250)},
+		{Opts{Model: WHAT, ModelColor: Yellow}, image.Rect(0, 0, 400, 300)},
+		// The size in Opts is preferred.
+		{Opts{Model: WHAT, ModelColor: Red, Width: 200, Height: 100}, image.Rect(0, 0, 200, 100)},
+	}
+	for _, line := range data {
+		d, err := New(&spitest.Record{}, &gpiotest.Pin{}, &gpiotest.Pin{}, &gpiotest.Pin{}, &line.o)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b := d.Bounds(); b != line.expected {
+			t.Errorf("%s: expected %v, got %v", line.o.Model, line.expected, b)
+		}
+	}
+}
+
+func TestNewImpression_bounds(t *testing.T) {
+	data := []struct {
+		o        Opts
+		expected image.Rectangle
+	}{
+		{Opts{Model: IMPRESSION4, ModelColor: Multi}, image.Rect(0, 0, 640, 400)},
+		{Opts{Model: IMPRESSION57, ModelColor: Multi}, image.Rect(0, 0, 600, 448)},
+		{Opts{Model: IMPRESSION57, ModelColor: Multi, Width: 300, Height: 200}, image.Rect(0, 0, 300, 200)},
+	}
+	for _, line := range data {
+		d, err := NewImpression(&spitest.Record{}, &gpiotest.Pin{}, &gpiotest.Pin{}, &gpiotest.Pin{}, &line.o)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b := d.Bounds(); b != line.expected {
+			t.Errorf("%s: expected %v, got %v", line.o.Model, line.expected, b)
+		}
+	}
+}
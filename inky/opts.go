@@ -53,6 +53,10 @@ type Opts struct {
 	// Board information.
 	PCBVariant     uint
 	DisplayVariant uint
+
+	// BusyPinMode selects how the busy pin is used to wait for the display.
+	// It defaults to BusyEdgePerWait.
+	BusyPinMode BusyPinMode
 }
 
 // DetectOpts tries to read the device opts from EEPROM.
@@ -0,0 +1,132 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package inky
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+)
+
+// NewSimulated returns a Dev that doesn't drive any hardware, to develop
+// layouts without waiting for the panel to refresh.
+//
+// Render saves the frame, with the colors of the panel, to the PNG file path;
+// with an empty path the frame is only kept in memory. Frame returns it.
+func NewSimulated(o *Opts, path string) (*Dev, error) {
+	if o.ModelColor != Black && o.ModelColor != Red && o.ModelColor != Yellow {
+		return nil, fmt.Errorf("unsupported color: %v", o.ModelColor)
+	}
+	d := &Dev{
+		color:      o.ModelColor,
+		border:     o.BorderColor,
+		model:      o.Model,
+		variant:    o.DisplayVariant,
+		pcbVariant: o.PCBVariant,
+		sim:        &simulator{path: path},
+	}
+	d.setSize(o)
+	// The frame is not flipped like the data sent to the panel.
+	d.flipVertically = false
+	return d, nil
+}
+
+// NewSimulatedImpression returns a DevImpression that doesn't drive any
+// hardware, like NewSimulated.
+func NewSimulatedImpression(o *Opts, path string) (*DevImpression, error) {
+	if o.ModelColor != Multi {
+		return nil, fmt.Errorf("unsupported color: %v", o.ModelColor)
+	}
+	d := &DevImpression{
+		Dev: &Dev{
+			color:      o.ModelColor,
+			border:     o.BorderColor,
+			model:      o.Model,
+			variant:    o.DisplayVariant,
+			pcbVariant: o.PCBVariant,
+			sim:        &simulator{path: path},
+		},
+		saturation: 50,
+	}
+	d.setSize(o)
+	return d, nil
+}
+
+// Frame returns the last frame rendered by a device returned by NewSimulated
+// or NewSimulatedImpression. It returns nil before the first Render, and for
+// real devices.
+func (d *Dev) Frame() image.Image {
+	if d.sim == nil || d.sim.frame == nil {
+		return nil
+	}
+	return d.sim.frame
+}
+
+//
+
+// simulator receives the frames rendered by a simulated device.
+type simulator struct {
+	path  string
+	frame *image.RGBA
+}
+
+// show keeps the frame and writes it to the PNG file, if any.
+func (s *simulator) show(frame *image.RGBA) error {
+	s.frame = frame
+	if s.path == "" {
+		return nil
+	}
+	f, err := os.Create(s.path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, frame); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// frame returns the pixels as displayed by the panel.
+func (d *Dev) frame() *image.RGBA {
+	img := image.NewRGBA(d.bounds)
+	for y := 0; y < d.height; y++ {
+		for x := 0; x < d.width; x++ {
+			c := pixColors[pixWhite]
+			switch d.pix[y*d.width+x] {
+			case pixBlack:
+				c = pixColors[pixBlack]
+			case pixRed:
+				// Black panels only show the black and white pixels.
+				if d.color != Black {
+					c = panelColors[d.color]
+				}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+// frame returns the pixels as displayed by the panel.
+func (d *DevImpression) frame() *image.RGBA {
+	img := image.NewRGBA(d.bounds)
+	for y := 0; y < d.height; y++ {
+		for x := 0; x < d.width; x++ {
+			c := sc[d.Pix[y*d.width+x]&7]
+			c.A = 255
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+// panelColors is the color of the third color pixels of each panel.
+var panelColors = map[Color]color.Color{
+	Red:    color.RGBA{R: 255, A: 255},
+	Yellow: color.RGBA{R: 255, G: 255, A: 255},
+}
@@ -0,0 +1,34 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package mipidcs
+
+import "image/color"
+
+// RGB565 is a color as stored in the controller RAM.
+type RGB565 uint16
+
+// RGBA implements color.Color.
+func (c RGB565) RGBA() (r, g, b, a uint32) {
+	r = uint32(c>>11) & 0x1F
+	g = uint32(c>>5) & 0x3F
+	b = uint32(c) & 0x1F
+	r = (r<<11 | r<<6 | r<<1 | r>>4)
+	g = (g<<10 | g<<4 | g>>2)
+	b = (b<<11 | b<<6 | b<<1 | b>>4)
+	return r, g, b, 0xFFFF
+}
+
+// RGB565Model converts the colors to RGB565.
+var RGB565Model = color.ModelFunc(func(c color.Color) color.Color {
+	return toRGB565(c)
+})
+
+func toRGB565(c color.Color) RGB565 {
+	if c, ok := c.(RGB565); ok {
+		return c
+	}
+	r, g, b, _ := c.RGBA()
+	return RGB565((r>>11)<<11 | (g>>10)<<5 | b>>11)
+}
@@ -0,0 +1,145 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package mipidcs
+
+import (
+	"image"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/gpio"
+)
+
+// Commands
+const (
+	SoftReset           byte = 0x01
+	SleepIn             byte = 0x10
+	SleepOut            byte = 0x11
+	NormalDisplayOn     byte = 0x13
+	InvertOff           byte = 0x20
+	InvertOn            byte = 0x21
+	GammaSet            byte = 0x26
+	DisplayOff          byte = 0x28
+	DisplayOn           byte = 0x29
+	ColumnAddressSet    byte = 0x2A
+	PageAddressSet      byte = 0x2B
+	MemoryWrite         byte = 0x2C
+	TearingEffectOn     byte = 0x35
+	MemoryAccessControl byte = 0x36
+	VerticalScrollStart byte = 0x37
+	PixelFormat         byte = 0x3A
+)
+
+// Flags of the MemoryAccessControl command
+const (
+	// MY mirrors the rows.
+	MY byte = 0x80
+	// MX mirrors the columns.
+	MX byte = 0x40
+	// MV exchanges the rows and the columns.
+	MV byte = 0x20
+	// BGR swaps the red and blue channels.
+	BGR byte = 0x08
+)
+
+// Parameters of the PixelFormat command, for the DBI interface used over SPI
+// and the DPI (RGB) interface.
+const (
+	FormatDBI16Bits byte = 0x05
+	FormatDPI16Bits byte = 0x50
+)
+
+// Cmd is a command, its parameters and the time to wait after it.
+type Cmd struct {
+	Cmd   byte
+	Data  []byte
+	Delay time.Duration
+}
+
+// Sleep is used to wait after commands. Tests can replace it.
+var Sleep = time.Sleep
+
+// Conn is a connection to a display controller, where the data/command pin
+// tells the commands from their parameters.
+type Conn struct {
+	c         conn.Conn
+	dc        gpio.PinOut
+	maxTxSize int
+}
+
+// NewConn returns a Conn sending the data in transfers of at most maxTxSize
+// bytes. If maxTxSize is 0, the limit of c is used, or 4096 bytes.
+func NewConn(c conn.Conn, dc gpio.PinOut, maxTxSize int) *Conn {
+	if maxTxSize == 0 {
+		if limits, ok := c.(conn.Limits); ok {
+			maxTxSize = limits.MaxTxSize()
+		}
+	}
+	if maxTxSize <= 0 {
+		maxTxSize = 4096 // Use a conservative default.
+	}
+	return &Conn{c: c, dc: dc, maxTxSize: maxTxSize}
+}
+
+func (c *Conn) String() string {
+	return c.c.String() + ", " + c.dc.String()
+}
+
+// Command sends cmd followed by its parameters, if any.
+func (c *Conn) Command(cmd byte, data ...byte) error {
+	if err := c.dc.Out(gpio.Low); err != nil {
+		return err
+	}
+	if err := c.c.Tx([]byte{cmd}, nil); err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	return c.Data(data)
+}
+
+// Data sends b as parameters of the last command.
+func (c *Conn) Data(b []byte) error {
+	if err := c.dc.Out(gpio.High); err != nil {
+		return err
+	}
+	for len(b) != 0 {
+		n := min(len(b), c.maxTxSize)
+		if err := c.c.Tx(b[:n], nil); err != nil {
+			return err
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+// Run sends the commands in order, waiting after each of them.
+func (c *Conn) Run(cmds []Cmd) error {
+	for _, cmd := range cmds {
+		if err := c.Command(cmd.Cmd, cmd.Data...); err != nil {
+			return err
+		}
+		if cmd.Delay != 0 {
+			Sleep(cmd.Delay)
+		}
+	}
+	return nil
+}
+
+// WriteMemory sets the window r of the controller RAM and writes pix to it,
+// in RGB565 big endian.
+func (c *Conn) WriteMemory(r image.Rectangle, pix []byte) error {
+	if err := c.Command(ColumnAddressSet, byte(r.Min.X>>8), byte(r.Min.X), byte((r.Max.X-1)>>8), byte(r.Max.X-1)); err != nil {
+		return err
+	}
+	if err := c.Command(PageAddressSet, byte(r.Min.Y>>8), byte(r.Min.Y), byte((r.Max.Y-1)>>8), byte(r.Max.Y-1)); err != nil {
+		return err
+	}
+	if err := c.Command(MemoryWrite); err != nil {
+		return err
+	}
+	return c.Data(pix)
+}
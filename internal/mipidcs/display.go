@@ -0,0 +1,222 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package mipidcs
+
+import (
+	"fmt"
+	"image"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/gpio"
+)
+
+// Opts is the configuration of a Display.
+type Opts struct {
+	// Width and Height are the size of the panel in its native orientation.
+	Width  int
+	Height int
+	// XOffset and YOffset are the position of the panel in the controller
+	// RAM, in its native orientation.
+	XOffset int
+	YOffset int
+	// RAMWidth and RAMHeight are the size of the controller RAM.
+	RAMWidth  int
+	RAMHeight int
+	// Rotations are the MemoryAccessControl flags of the 4 rotations,
+	// clockwise.
+	Rotations [4]byte
+	// Rotation is the index of the rotation of the image in Rotations.
+	Rotation int
+	// Invert inverts the colors.
+	Invert bool
+	// BGR swaps the red and blue channels.
+	BGR bool
+	// PixelFormat is the parameter of the PixelFormat command selecting
+	// RGB565.
+	PixelFormat byte
+	// Init is the commands specific to the controller, sent after the
+	// software reset.
+	Init []Cmd
+	// MaxTxSize is the largest SPI transfer, see NewConn.
+	MaxTxSize int
+}
+
+// Display is a display controller keeping a copy of the image.
+type Display struct {
+	c    *Conn
+	rst  gpio.PinOut
+	opts Opts
+
+	// rect is the size of the image.
+	rect image.Rectangle
+	// origin is the position of the panel in the controller address space.
+	origin image.Point
+	madctl byte
+	asleep bool
+	// buffer is the image in RGB565, big endian.
+	buffer []byte
+}
+
+// New initializes the controller connected to c.
+//
+// dc is the data/command pin. rst is the reset pin, nil if not connected.
+func New(c conn.Conn, dc, rst gpio.PinOut, opts *Opts) (*Display, error) {
+	if opts.Width <= 0 || opts.Height <= 0 || opts.XOffset < 0 || opts.YOffset < 0 ||
+		opts.XOffset+opts.Width > opts.RAMWidth || opts.YOffset+opts.Height > opts.RAMHeight {
+		return nil, fmt.Errorf("invalid panel %dx%d at (%d,%d)", opts.Width, opts.Height, opts.XOffset, opts.YOffset)
+	}
+	if opts.Rotation < 0 || opts.Rotation >= len(opts.Rotations) {
+		return nil, fmt.Errorf("invalid rotation %d", opts.Rotation)
+	}
+	d := &Display{c: NewConn(c, dc, opts.MaxTxSize), rst: rst, opts: *opts}
+	d.setRotation(opts.Rotation)
+	if err := d.init(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *Display) String() string {
+	return fmt.Sprintf("%s, Width: %d, Height: %d", d.c, d.rect.Dx(), d.rect.Dy())
+}
+
+// Bounds returns the size of the image in the current rotation.
+func (d *Display) Bounds() image.Rectangle {
+	return d.rect
+}
+
+// Draw copies the area r of the image src starting at sp, and writes it to
+// the controller. Only the area r is sent.
+func (d *Display) Draw(r image.Rectangle, src image.Image, sp image.Point) error {
+	area := r.Intersect(d.rect)
+	if area.Empty() {
+		return nil
+	}
+	sp = sp.Add(area.Min.Sub(r.Min))
+	w := d.rect.Dx()
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		for x := area.Min.X; x < area.Max.X; x++ {
+			c := toRGB565(src.At(sp.X+x-area.Min.X, sp.Y+y-area.Min.Y))
+			i := 2 * (y*w + x)
+			d.buffer[i], d.buffer[i+1] = byte(c>>8), byte(c)
+		}
+	}
+	return d.flush(area)
+}
+
+// SetRotation rotates the image. The content of the display is lost; the
+// whole image must be drawn again.
+func (d *Display) SetRotation(r int) error {
+	if r < 0 || r >= len(d.opts.Rotations) {
+		return fmt.Errorf("invalid rotation %d", r)
+	}
+	d.setRotation(r)
+	return d.c.Command(MemoryAccessControl, d.madctl)
+}
+
+// Invert inverts the colors, relative to the colors set by Opts.Invert.
+func (d *Display) Invert(on bool) error {
+	if on != d.opts.Invert {
+		return d.c.Command(InvertOn)
+	}
+	return d.c.Command(InvertOff)
+}
+
+// Halt turns the display off and puts the controller in sleep mode. The next
+// Draw wakes it up.
+func (d *Display) Halt() error {
+	if err := d.c.Command(DisplayOff); err != nil {
+		return err
+	}
+	if err := d.c.Command(SleepIn); err != nil {
+		return err
+	}
+	d.asleep = true
+	return nil
+}
+
+//
+
+// setRotation computes the geometry of rotation r.
+//
+// MX and MY mirror the columns and the rows of the RAM, then MV exchanges
+// them.
+func (d *Display) setRotation(r int) {
+	d.madctl = d.opts.Rotations[r]
+	if d.opts.BGR {
+		d.madctl |= BGR
+	}
+	w, h := d.opts.Width, d.opts.Height
+	x, y := d.opts.XOffset, d.opts.YOffset
+	if d.madctl&MX != 0 {
+		x = d.opts.RAMWidth - w - x
+	}
+	if d.madctl&MY != 0 {
+		y = d.opts.RAMHeight - h - y
+	}
+	if d.madctl&MV != 0 {
+		w, h, x, y = h, w, y, x
+	}
+	d.rect = image.Rect(0, 0, w, h)
+	d.origin = image.Pt(x, y)
+	d.buffer = make([]byte, 2*w*h)
+}
+
+// init resets the controller, sends the commands specific to it then the
+// ones setting the pixel format and the rotation, and turns the display on.
+func (d *Display) init() error {
+	if d.rst != nil {
+		if err := d.rst.Out(gpio.Low); err != nil {
+			return err
+		}
+		Sleep(10 * time.Microsecond)
+		if err := d.rst.Out(gpio.High); err != nil {
+			return err
+		}
+		Sleep(120 * time.Millisecond)
+	}
+	if err := d.c.Run([]Cmd{{Cmd: SoftReset, Delay: 150 * time.Millisecond}}); err != nil {
+		return err
+	}
+	if err := d.c.Run(d.opts.Init); err != nil {
+		return err
+	}
+	inversion := InvertOff
+	if d.opts.Invert {
+		inversion = InvertOn
+	}
+	return d.c.Run([]Cmd{
+		{Cmd: MemoryAccessControl, Data: []byte{d.madctl}},
+		{Cmd: PixelFormat, Data: []byte{d.opts.PixelFormat}},
+		{Cmd: inversion},
+		{Cmd: SleepOut, Delay: 120 * time.Millisecond},
+		{Cmd: NormalDisplayOn, Delay: 10 * time.Millisecond},
+		{Cmd: DisplayOn, Delay: 20 * time.Millisecond},
+	})
+}
+
+// flush sends the area r of the buffer.
+func (d *Display) flush(r image.Rectangle) error {
+	if d.asleep {
+		if err := d.c.Run([]Cmd{
+			{Cmd: SleepOut, Delay: 120 * time.Millisecond},
+			{Cmd: DisplayOn},
+		}); err != nil {
+			return err
+		}
+		d.asleep = false
+	}
+	w := d.rect.Dx()
+	if r.Dx() == w {
+		// The rows are contiguous.
+		return d.c.WriteMemory(r.Add(d.origin), d.buffer[2*r.Min.Y*w:2*r.Max.Y*w])
+	}
+	pix := make([]byte, 0, 2*r.Dx()*r.Dy())
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		pix = append(pix, d.buffer[2*(y*w+r.Min.X):2*(y*w+r.Max.X)]...)
+	}
+	return d.c.WriteMemory(r.Add(d.origin), pix)
+}
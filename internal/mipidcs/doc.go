@@ -0,0 +1,16 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package mipidcs implements the parts of the MIPI Display Command Set shared
+// by the color LCD controllers, like the ST7735, ST7789, ILI9341 and GC9A01,
+// on a 4-wire SPI bus.
+//
+// Conn sends commands and their parameters, toggling the data/command pin.
+// Display builds on it to initialize a controller from a table of commands,
+// and to keep an RGB565 copy of the image whose modified areas are written to
+// the controller RAM.
+//
+// The drivers provide the commands specific to their controller and the
+// geometry of the panel.
+package mipidcs
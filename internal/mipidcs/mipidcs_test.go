@@ -0,0 +1,240 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package mipidcs
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"periph.io/x/conn/v3/gpio/gpiotest"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/spi"
+	"periph.io/x/devices/v3/devicetest"
+)
+
+type record = devicetest.Record
+
+// countingConn counts the SPI transfers.
+type countingConn struct {
+	*devicetest.CommandConn
+	tx int
+}
+
+func (c *countingConn) Connect(f physic.Frequency, mode spi.Mode, bits int) (spi.Conn, error) {
+	return c, nil
+}
+
+func (c *countingConn) Tx(w, r []byte) error {
+	c.tx++
+	return c.CommandConn.Tx(w, r)
+}
+
+// testOpts is a 135x240 panel in a 240x320 RAM.
+var testOpts = Opts{
+	Width:       135,
+	Height:      240,
+	XOffset:     52,
+	YOffset:     40,
+	RAMWidth:    240,
+	RAMHeight:   320,
+	Rotations:   [4]byte{0, MX | MV, MX | MY, MY | MV},
+	Invert:      true,
+	PixelFormat: FormatDBI16Bits,
+}
+
+func newDisplay(t *testing.T, opts *Opts) (*Display, *countingConn) {
+	Sleep = func(time.Duration) {}
+	c := &countingConn{CommandConn: devicetest.NewCommandConn()}
+	d, err := New(c, &c.DC, &gpiotest.Pin{}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Reset()
+	c.tx = 0
+	return d, c
+}
+
+func TestNew(t *testing.T) {
+	Sleep = func(time.Duration) {}
+	c := devicetest.NewCommandConn()
+	opts := testOpts
+	opts.Init = []Cmd{{Cmd: 0xB0, Data: []byte{0x01, 0x02}}, {Cmd: 0xB1}}
+	d, err := New(c, &c.DC, nil, &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []record{
+		{Cmd: SoftReset},
+		{Cmd: 0xB0, Data: []byte{0x01, 0x02}},
+		{Cmd: 0xB1},
+		{Cmd: MemoryAccessControl, Data: []byte{0x00}},
+		{Cmd: PixelFormat, Data: []byte{0x05}},
+		{Cmd: InvertOn},
+		{Cmd: SleepOut},
+		{Cmd: NormalDisplayOn},
+		{Cmd: DisplayOn},
+	}
+	if diff := c.Diff(want); diff != "" {
+		t.Errorf("New() difference (-got +want):\n%s", diff)
+	}
+	if s := d.String(); s != "devicetest.CommandConn, DC(0), Width: 135, Height: 240" {
+		t.Errorf("String() = %q", s)
+	}
+
+	for _, opts := range []Opts{
+		{Width: 0, Height: 240, RAMWidth: 240, RAMHeight: 320},
+		{Width: 240, Height: 321, RAMWidth: 240, RAMHeight: 320},
+		{Width: 135, Height: 240, XOffset: 106, RAMWidth: 240, RAMHeight: 320},
+		{Width: 240, Height: 240, RAMWidth: 240, RAMHeight: 320, Rotation: 4},
+	} {
+		if _, err := New(c, &c.DC, nil, &opts); err == nil {
+			t.Errorf("New(%+v) should fail", opts)
+		}
+	}
+}
+
+func TestRotation(t *testing.T) {
+	for _, tc := range []struct {
+		rotation   int
+		wantMADCTL byte
+		wantBounds image.Rectangle
+		wantOrigin image.Point
+	}{
+		{0, 0x08, image.Rect(0, 0, 135, 240), image.Pt(52, 40)},
+		{1, 0x68, image.Rect(0, 0, 240, 135), image.Pt(40, 53)},
+		{2, 0xC8, image.Rect(0, 0, 135, 240), image.Pt(53, 40)},
+		{3, 0xA8, image.Rect(0, 0, 240, 135), image.Pt(40, 52)},
+	} {
+		opts := testOpts
+		opts.BGR = true
+		d, c := newDisplay(t, &opts)
+		if err := d.SetRotation(tc.rotation); err != nil {
+			t.Fatal(err)
+		}
+		if diff := c.Diff([]record{{Cmd: MemoryAccessControl, Data: []byte{tc.wantMADCTL}}}); diff != "" {
+			t.Errorf("SetRotation(%d) difference (-got +want):\n%s", tc.rotation, diff)
+		}
+		if b := d.Bounds(); b != tc.wantBounds {
+			t.Errorf("SetRotation(%d) bounds = %v, want %v", tc.rotation, b, tc.wantBounds)
+		}
+		if d.origin != tc.wantOrigin {
+			t.Errorf("SetRotation(%d) origin = %v, want %v", tc.rotation, d.origin, tc.wantOrigin)
+		}
+	}
+	d, _ := newDisplay(t, &testOpts)
+	if err := d.SetRotation(4); err == nil {
+		t.Error("SetRotation(4) should fail")
+	}
+}
+
+func TestDraw(t *testing.T) {
+	d, c := newDisplay(t, &testOpts)
+
+	// Only the area drawn is sent, clipped to the display.
+	red := &image.Uniform{color.RGBA{R: 0xFF, A: 0xFF}}
+	if err := d.Draw(image.Rect(130, 10, 140, 12), red, image.Point{}); err != nil {
+		t.Fatal(err)
+	}
+	want := []record{
+		{Cmd: ColumnAddressSet, Data: []byte{0, 182, 0, 186}},
+		{Cmd: PageAddressSet, Data: []byte{0, 50, 0, 51}},
+		{Cmd: MemoryWrite, Data: bytes.Repeat([]byte{0xF8, 0x00}, 10)},
+	}
+	if diff := c.Diff(want); diff != "" {
+		t.Errorf("Draw() difference (-got +want):\n%s", diff)
+	}
+
+	// The source is read from sp.
+	src := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	src.Set(1, 0, color.RGBA{G: 0xFF, A: 0xFF})
+	c.Reset()
+	if err := d.Draw(image.Rect(0, 0, 1, 1), src, image.Pt(1, 0)); err != nil {
+		t.Fatal(err)
+	}
+	want = []record{
+		{Cmd: ColumnAddressSet, Data: []byte{0, 52, 0, 52}},
+		{Cmd: PageAddressSet, Data: []byte{0, 40, 0, 40}},
+		{Cmd: MemoryWrite, Data: []byte{0x07, 0xE0}},
+	}
+	if diff := c.Diff(want); diff != "" {
+		t.Errorf("Draw() difference (-got +want):\n%s", diff)
+	}
+
+	c.Reset()
+	if err := d.Draw(image.Rect(200, 0, 210, 10), red, image.Point{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Records) != 0 {
+		t.Errorf("Draw() outside of the display sent %v", c.Records)
+	}
+}
+
+func TestDraw_chunks(t *testing.T) {
+	opts := testOpts
+	opts.Width, opts.Height, opts.XOffset, opts.YOffset = 240, 240, 0, 0
+	opts.MaxTxSize = 1000
+	d, c := newDisplay(t, &opts)
+	if err := d.Draw(d.Bounds(), &image.Uniform{color.White}, image.Point{}); err != nil {
+		t.Fatal(err)
+	}
+	// 3 commands with 2 parameter transfers, and 240*240*2/1000 rounded up.
+	if want := 3 + 2 + 116; c.tx != want {
+		t.Errorf("Draw() sent %d transfers, want %d", c.tx, want)
+	}
+	if got := c.Records[2].Data; !bytes.Equal(got, bytes.Repeat([]byte{0xFF}, 240*240*2)) {
+		t.Errorf("Draw() sent %d bytes of pixels", len(got))
+	}
+}
+
+func TestHalt(t *testing.T) {
+	d, c := newDisplay(t, &testOpts)
+	if err := d.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Draw(image.Rect(0, 0, 1, 1), &image.Uniform{color.Black}, image.Point{}); err != nil {
+		t.Fatal(err)
+	}
+	want := []record{
+		{Cmd: DisplayOff},
+		{Cmd: SleepIn},
+		{Cmd: SleepOut},
+		{Cmd: DisplayOn},
+		{Cmd: ColumnAddressSet, Data: []byte{0, 52, 0, 52}},
+		{Cmd: PageAddressSet, Data: []byte{0, 40, 0, 40}},
+		{Cmd: MemoryWrite, Data: []byte{0, 0}},
+	}
+	if diff := c.Diff(want); diff != "" {
+		t.Errorf("Halt() difference (-got +want):\n%s", diff)
+	}
+
+	c.Reset()
+	if err := d.Invert(true); err != nil {
+		t.Fatal(err)
+	}
+	if diff := c.Diff([]record{{Cmd: InvertOff}}); diff != "" {
+		t.Errorf("Invert() difference (-got +want):\n%s", diff)
+	}
+}
+
+func TestRGB565Model(t *testing.T) {
+	for _, tc := range []struct {
+		in   color.Color
+		want color.RGBA64
+	}{
+		{color.White, color.RGBA64{0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF}},
+		{color.Black, color.RGBA64{0, 0, 0, 0xFFFF}},
+		{color.RGBA{R: 0x84, G: 0x82, B: 0x7F, A: 0xFF}, color.RGBA64{0x8421, 0x8208, 0x7BDE, 0xFFFF}},
+		{RGB565(0xF800), color.RGBA64{0xFFFF, 0, 0, 0xFFFF}},
+	} {
+		r, g, b, a := RGB565Model.Convert(tc.in).RGBA()
+		if diff := cmp.Diff(color.RGBA64{uint16(r), uint16(g), uint16(b), uint16(a)}, tc.want); diff != "" {
+			t.Errorf("Convert(%v) difference (-got +want):\n%s", tc.in, diff)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package ltc2943 controls an Analog Devices (Linear Technology) LTC2943
+// battery gas gauge over an i2c bus.
+//
+// The LTC2943 counts the charge flowing through an external sense resistor
+// and measures the battery voltage, the current and its temperature. The
+// accumulated charge is relative, set it with SetCharge once the battery is
+// known to be full or empty.
+//
+// Thresholds can be set on each measurement; the ALCC pin is configured to
+// signal alerts and Alerts reports which ones fired.
+//
+// # Datasheet
+//
+// https://www.analog.com/media/en/technical-documentation/data-sheets/2943fa.pdf
+package ltc2943
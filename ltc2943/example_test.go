@@ -0,0 +1,48 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ltc2943_test
+
+import (
+	"fmt"
+	"log"
+
+	"periph.io/x/conn/v3/i2c/i2creg"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/devices/v3/ltc2943"
+	"periph.io/x/host/v3"
+)
+
+func Example() {
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Open default I²C bus.
+	bus, err := i2creg.Open("")
+	if err != nil {
+		log.Fatalf("failed to open I²C: %v", err)
+	}
+	defer bus.Close()
+
+	// A 2000mAh battery with a 50mΩ sense resistor.
+	gauge, err := ltc2943.New(bus, &ltc2943.Opts{SenseResistor: 50 * physic.MilliOhm, Prescaler: 1024})
+	if err != nil {
+		log.Fatalln(err)
+	}
+	// The battery was just fully charged.
+	if err := gauge.SetCharge(2000 * ltc2943.MilliAmpereHour); err != nil {
+		log.Fatalln(err)
+	}
+	// Signal when the battery is nearly empty.
+	if err := gauge.SetChargeThresholds(200*ltc2943.MilliAmpereHour, 2000*ltc2943.MilliAmpereHour); err != nil {
+		log.Fatalln(err)
+	}
+	r, err := gauge.Sense()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Println(r)
+}
@@ -0,0 +1,333 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ltc2943
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/physic"
+)
+
+// Charge is an electric charge stored as nAh (nano ampere hour).
+type Charge int64
+
+// Units of charge.
+const (
+	NanoAmpereHour  Charge = 1
+	MicroAmpereHour Charge = 1000 * NanoAmpereHour
+	MilliAmpereHour Charge = 1000 * MicroAmpereHour
+	AmpereHour      Charge = 1000 * MilliAmpereHour
+)
+
+// String returns the charge in mAh.
+func (c Charge) String() string {
+	return strconv.FormatFloat(float64(c)/float64(MilliAmpereHour), 'f', 3, 64) + "mAh"
+}
+
+// Alert is a set of alerts reported by the status register.
+type Alert uint8
+
+// Possible alerts.
+const (
+	// UndervoltageLockout is set when the analog section was reset by an
+	// undervoltage. The accumulated charge is not affected.
+	UndervoltageLockout Alert = 1 << iota
+	VoltageAlert
+	ChargeLowAlert
+	ChargeHighAlert
+	TemperatureAlert
+	// ChargeOverflow is set when the accumulated charge over or underflowed.
+	ChargeOverflow
+	CurrentAlert
+)
+
+func (a Alert) String() string {
+	var s []string
+	for i, n := range []string{"UndervoltageLockout", "Voltage", "ChargeLow", "ChargeHigh", "Temperature", "ChargeOverflow", "Current"} {
+		if a&(1<<uint(i)) != 0 {
+			s = append(s, n)
+		}
+	}
+	if len(s) == 0 {
+		return "None"
+	}
+	return strings.Join(s, "|")
+}
+
+// Opts holds the configuration options.
+type Opts struct {
+	// SenseResistor is the value of the current sense resistor.
+	SenseResistor physic.ElectricResistance
+	// Prescaler is the coulomb counter prescaler M, one of 1, 4, 16, 64, 256,
+	// 1024 or 4096. Lower values increase the resolution of the charge but
+	// overflow faster; see the datasheet to select it from the battery
+	// capacity.
+	Prescaler int
+}
+
+// DefaultOpts is the recommended default options, the values at power on.
+var DefaultOpts = Opts{
+	SenseResistor: 50 * physic.MilliOhm,
+	Prescaler:     4096,
+}
+
+// Address is the fixed i2c address of the LTC2943.
+const Address = 0x64
+
+// New opens a handle to a LTC2943.
+//
+// The ADC is set to measure continuously and the ALCC pin is configured as
+// the alert output.
+func New(bus i2c.Bus, opts *Opts) (*Dev, error) {
+	if opts.SenseResistor <= 0 {
+		return nil, fmt.Errorf("ltc2943: invalid sense resistor %s", opts.SenseResistor)
+	}
+	m := -1
+	for i, v := range prescalers {
+		if v == opts.Prescaler {
+			m = i
+		}
+	}
+	if m == -1 {
+		return nil, fmt.Errorf("ltc2943: invalid prescaler %d", opts.Prescaler)
+	}
+	d := &Dev{
+		c:         i2c.Dev{Bus: bus, Addr: Address},
+		rsense:    opts.SenseResistor,
+		prescaler: int64(opts.Prescaler),
+		control:   adcAutomatic | byte(m)<<3 | alccAlert,
+	}
+	if err := d.c.Tx([]byte{controlRegister, d.control}, nil); err != nil {
+		return nil, fmt.Errorf("ltc2943: %v", err)
+	}
+	return d, nil
+}
+
+// Dev is a handle to a LTC2943.
+type Dev struct {
+	c         i2c.Dev
+	rsense    physic.ElectricResistance
+	prescaler int64
+	control   byte
+
+	mu sync.Mutex
+}
+
+// Reading is a measurement from the LTC2943.
+type Reading struct {
+	// Charge is the accumulated charge.
+	Charge Charge
+	// Voltage is the battery voltage.
+	Voltage physic.ElectricPotential
+	// Current is the current through the sense resistor, positive when
+	// charging.
+	Current physic.ElectricCurrent
+	// Temperature is the die temperature.
+	Temperature physic.Temperature
+}
+
+func (r Reading) String() string {
+	return fmt.Sprintf("Charge: %s, Voltage: %s, Current: %s, Temperature: %s", r.Charge, r.Voltage, r.Current, r.Temperature)
+}
+
+func (d *Dev) String() string {
+	return "LTC2943{" + d.c.String() + "}"
+}
+
+// Sense reads all the measurements.
+func (d *Dev) Sense() (Reading, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	// Read the charge, voltage, current and temperature registers at once.
+	var b [temperatureRegister + 2 - chargeRegister]byte
+	if err := d.c.Tx([]byte{chargeRegister}, b[:]); err != nil {
+		return Reading{}, fmt.Errorf("ltc2943: %v", err)
+	}
+	reg := func(r byte) uint16 {
+		i := r - chargeRegister
+		return uint16(b[i])<<8 | uint16(b[i+1])
+	}
+	return Reading{
+		Charge:      d.countToCharge(reg(chargeRegister)),
+		Voltage:     countToVoltage(reg(voltageRegister)),
+		Current:     d.countToCurrent(reg(currentRegister)),
+		Temperature: countToTemperature(reg(temperatureRegister)),
+	}, nil
+}
+
+// SetCharge sets the accumulated charge, for example to the battery capacity
+// once it is fully charged.
+func (d *Dev) SetCharge(c Charge) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	// The analog section must be shut down while writing the charge.
+	if err := d.c.Tx([]byte{controlRegister, d.control | shutdown}, nil); err != nil {
+		return fmt.Errorf("ltc2943: %v", err)
+	}
+	if err := d.writeRegister(chargeRegister, d.chargeToCount(c)); err != nil {
+		return fmt.Errorf("ltc2943: %v", err)
+	}
+	if err := d.c.Tx([]byte{controlRegister, d.control}, nil); err != nil {
+		return fmt.Errorf("ltc2943: %v", err)
+	}
+	return nil
+}
+
+// SetChargeThresholds sets the accumulated charge thresholds for the
+// ChargeLowAlert and ChargeHighAlert alerts.
+func (d *Dev) SetChargeThresholds(low, high Charge) error {
+	return d.setThresholds(chargeThresholdHighRegister, d.chargeToCount(low), d.chargeToCount(high))
+}
+
+// SetVoltageThresholds sets the battery voltage thresholds for VoltageAlert.
+func (d *Dev) SetVoltageThresholds(low, high physic.ElectricPotential) error {
+	return d.setThresholds(voltageThresholdHighRegister, voltageToCount(low), voltageToCount(high))
+}
+
+// SetCurrentThresholds sets the current thresholds for CurrentAlert.
+func (d *Dev) SetCurrentThresholds(low, high physic.ElectricCurrent) error {
+	return d.setThresholds(currentThresholdHighRegister, d.currentToCount(low), d.currentToCount(high))
+}
+
+// SetTemperatureThresholds sets the temperature thresholds for
+// TemperatureAlert. The thresholds have a resolution of 2K.
+func (d *Dev) SetTemperatureThresholds(low, high physic.Temperature) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	b := []byte{temperatureThresholdHighRegister, byte(temperatureToCount(high) >> 8), byte(temperatureToCount(low) >> 8)}
+	if err := d.c.Tx(b, nil); err != nil {
+		return fmt.Errorf("ltc2943: %v", err)
+	}
+	return nil
+}
+
+// Alerts returns the alerts that fired since the last call. Reading the
+// status clears the alerts whose condition is gone.
+func (d *Dev) Alerts() (Alert, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var b [1]byte
+	if err := d.c.Tx([]byte{statusRegister}, b[:]); err != nil {
+		return 0, fmt.Errorf("ltc2943: %v", err)
+	}
+	return Alert(b[0]), nil
+}
+
+// Halt implements conn.Resource.
+//
+// It is a noop, the LTC2943 keeps counting the charge.
+func (d *Dev) Halt() error {
+	return nil
+}
+
+//
+
+const (
+	statusRegister                   = 0x00
+	controlRegister                  = 0x01
+	chargeRegister                   = 0x02
+	chargeThresholdHighRegister      = 0x04
+	voltageRegister                  = 0x08
+	voltageThresholdHighRegister     = 0x0A
+	currentRegister                  = 0x0E
+	currentThresholdHighRegister     = 0x10
+	temperatureRegister              = 0x14
+	temperatureThresholdHighRegister = 0x16
+
+	adcAutomatic = 0xC0
+	alccAlert    = 0x04
+	shutdown     = 0x01
+)
+
+// prescalers is the value of M for each prescaler setting.
+var prescalers = [...]int{1, 4, 16, 64, 256, 1024, 4096}
+
+const (
+	// chargeLSB is the charge of a count with a 50mΩ sense resistor and
+	// M = 4096.
+	chargeLSB = 340 * MicroAmpereHour
+	// fullScaleVoltage is the voltage at a count of 65535.
+	fullScaleVoltage = 23600 * physic.MilliVolt
+	// fullScaleSense is the sense voltage at a count of 65535 or 1.
+	fullScaleSense = 60 * physic.MilliVolt
+	// fullScaleTemperature is the temperature at a count of 65535.
+	fullScaleTemperature = 510 * physic.Kelvin
+)
+
+// countToCharge converts the accumulated charge register to a charge,
+// chargeLSB * 50mΩ / Rsense * M / 4096 per count.
+func (d *Dev) countToCharge(count uint16) Charge {
+	c := int64(count) * int64(chargeLSB) * d.prescaler / 4096
+	return Charge(c * int64(50*physic.MilliOhm) / int64(d.rsense))
+}
+
+func (d *Dev) chargeToCount(c Charge) uint16 {
+	v := int64(c) * int64(d.rsense) / int64(50*physic.MilliOhm) * 4096 / (int64(chargeLSB) * d.prescaler)
+	return clamp(v, 0, 0xFFFF)
+}
+
+// countToVoltage converts the voltage register, 23.6V full scale.
+func countToVoltage(count uint16) physic.ElectricPotential {
+	return physic.ElectricPotential(int64(count) * int64(fullScaleVoltage) / 0xFFFF)
+}
+
+func voltageToCount(v physic.ElectricPotential) uint16 {
+	return clamp(int64(v)*0xFFFF/int64(fullScaleVoltage), 0, 0xFFFF)
+}
+
+// countToCurrent converts the current register, ±60mV / Rsense full scale
+// centered on 32767.
+func (d *Dev) countToCurrent(count uint16) physic.ElectricCurrent {
+	v := (int64(count) - 0x7FFF) * int64(fullScaleSense) / 0x7FFF
+	return physic.ElectricCurrent(v * int64(physic.Ampere) / int64(d.rsense))
+}
+
+func (d *Dev) currentToCount(i physic.ElectricCurrent) uint16 {
+	v := int64(i) * int64(d.rsense) / int64(physic.Ampere)
+	return clamp(0x7FFF+v*0x7FFF/int64(fullScaleSense), 0, 0xFFFF)
+}
+
+// countToTemperature converts the temperature register, 510K full scale.
+func countToTemperature(count uint16) physic.Temperature {
+	return physic.Temperature(int64(count) * int64(fullScaleTemperature) / 0xFFFF)
+}
+
+func temperatureToCount(t physic.Temperature) uint16 {
+	return clamp(int64(t)*0xFFFF/int64(fullScaleTemperature), 0, 0xFFFF)
+}
+
+func clamp(v, min, max int64) uint16 {
+	if v < min {
+		return uint16(min)
+	}
+	if v > max {
+		return uint16(max)
+	}
+	return uint16(v)
+}
+
+// setThresholds writes the high then the low 16 bits thresholds, which are
+// consecutive registers.
+func (d *Dev) setThresholds(reg byte, low, high uint16) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	b := []byte{reg, byte(high >> 8), byte(high), byte(low >> 8), byte(low)}
+	if err := d.c.Tx(b, nil); err != nil {
+		return fmt.Errorf("ltc2943: %v", err)
+	}
+	return nil
+}
+
+// writeRegister writes a big endian 16 bits register.
+func (d *Dev) writeRegister(reg uint8, v uint16) error {
+	return d.c.Tx([]byte{reg, byte(v >> 8), byte(v)}, nil)
+}
+
+var _ conn.Resource = &Dev{}
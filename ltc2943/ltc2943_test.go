@@ -0,0 +1,145 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ltc2943
+
+import (
+	"testing"
+
+	"periph.io/x/conn/v3/i2c/i2ctest"
+	"periph.io/x/conn/v3/physic"
+)
+
+var initOps = []i2ctest.IO{
+	{Addr: Address, W: []byte{controlRegister, 0xF4}},
+}
+
+func TestNew(t *testing.T) {
+	var tests = []struct {
+		name string
+		opts Opts
+		tx   []i2ctest.IO
+		err  bool
+	}{
+		{name: "default", opts: DefaultOpts, tx: initOps},
+		{
+			name: "prescaler",
+			opts: Opts{SenseResistor: 10 * physic.MilliOhm, Prescaler: 64},
+			tx:   []i2ctest.IO{{Addr: Address, W: []byte{controlRegister, 0xDC}}},
+		},
+		{name: "badPrescaler", opts: Opts{SenseResistor: 50 * physic.MilliOhm, Prescaler: 2}, err: true},
+		{name: "badResistor", opts: Opts{Prescaler: 4096}, err: true},
+		{name: "ioFail", opts: DefaultOpts, err: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			bus := &i2ctest.Playback{Ops: test.tx, DontPanic: true}
+			_, err := New(bus, &test.opts)
+			if (err != nil) != test.err {
+				t.Fatalf("got error %v", err)
+			}
+			if err := bus.Close(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestSense(t *testing.T) {
+	r := make([]byte, 20)
+	// 1000 counts of 340µAh.
+	r[0], r[1] = 0x03, 0xE8
+	// 11.8V.
+	r[6], r[7] = 0x7F, 0xFF
+	// -30mV / 50mΩ.
+	r[12], r[13] = 0x3F, 0xFF
+	// 255K.
+	r[18], r[19] = 0x7F, 0xFF
+	bus := &i2ctest.Playback{
+		Ops:       append(append([]i2ctest.IO{}, initOps...), i2ctest.IO{Addr: Address, W: []byte{chargeRegister}, R: r}),
+		DontPanic: true,
+	}
+	d, err := New(bus, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := d.Sense()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Charge != 340*MilliAmpereHour {
+		t.Errorf("charge %s", got.Charge)
+	}
+	if got.Voltage < 11799*physic.MilliVolt || got.Voltage > 11800*physic.MilliVolt {
+		t.Errorf("voltage %s", got.Voltage)
+	}
+	if got.Current < -601*physic.MilliAmpere || got.Current > -599*physic.MilliAmpere {
+		t.Errorf("current %s", got.Current)
+	}
+	if got.Temperature < 254*physic.Kelvin || got.Temperature > 255*physic.Kelvin {
+		t.Errorf("temperature %s", got.Temperature)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSetCharge_Thresholds_Alerts(t *testing.T) {
+	ops := append([]i2ctest.IO{}, initOps...)
+	ops = append(ops,
+		// SetCharge with the analog section shut down.
+		i2ctest.IO{Addr: Address, W: []byte{controlRegister, 0xF5}},
+		i2ctest.IO{Addr: Address, W: []byte{chargeRegister, 0x03, 0xE8}},
+		i2ctest.IO{Addr: Address, W: []byte{controlRegister, 0xF4}},
+		// SetChargeThresholds.
+		i2ctest.IO{Addr: Address, W: []byte{chargeThresholdHighRegister, 0x03, 0xE8, 0x00, 0x64}},
+		// SetVoltageThresholds.
+		i2ctest.IO{Addr: Address, W: []byte{voltageThresholdHighRegister, 0x7F, 0xFF, 0x00, 0x00}},
+		// SetCurrentThresholds.
+		i2ctest.IO{Addr: Address, W: []byte{currentThresholdHighRegister, 0xBF, 0xFE, 0x40, 0x00}},
+		// SetTemperatureThresholds.
+		i2ctest.IO{Addr: Address, W: []byte{temperatureThresholdHighRegister, 0x7F, 0x00}},
+		// Alerts.
+		i2ctest.IO{Addr: Address, W: []byte{statusRegister}, R: []byte{0x42}},
+	)
+	bus := &i2ctest.Playback{Ops: ops, DontPanic: true}
+	d, err := New(bus, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetCharge(340 * MilliAmpereHour); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetChargeThresholds(34*MilliAmpereHour, 340*MilliAmpereHour); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetVoltageThresholds(-physic.Volt, 11800*physic.MilliVolt); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetCurrentThresholds(-600*physic.MilliAmpere, 600*physic.MilliAmpere); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetTemperatureThresholds(0, 255*physic.Kelvin); err != nil {
+		t.Fatal(err)
+	}
+	a, err := d.Alerts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a != CurrentAlert|VoltageAlert {
+		t.Fatalf("unexpected alerts %s", a)
+	}
+	if s := a.String(); s != "Voltage|Current" {
+		t.Fatal(s)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCharge_String(t *testing.T) {
+	if s := (1500 * MicroAmpereHour).String(); s != "1.500mAh" {
+		t.Fatal(s)
+	}
+}
@@ -0,0 +1,16 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package max17048 controls an Analog Devices (Maxim) MAX17048 or MAX17049
+// battery fuel gauge over an i2c bus.
+//
+// The MAX17048 monitors a single lithium cell and the MAX17049 two cells in
+// series. They estimate the state of charge with the ModelGauge algorithm
+// from the cell voltage only, no sense resistor is needed, so the charge or
+// discharge current is reported as a rate in percent per hour.
+//
+// # Datasheet
+//
+// https://www.analog.com/media/en/technical-documentation/data-sheets/MAX17048-MAX17049.pdf
+package max17048
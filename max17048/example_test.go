@@ -0,0 +1,46 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package max17048_test
+
+import (
+	"fmt"
+	"log"
+
+	"periph.io/x/conn/v3/i2c/i2creg"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/devices/v3/max17048"
+	"periph.io/x/host/v3"
+)
+
+func Example() {
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Open default I²C bus.
+	bus, err := i2creg.Open("")
+	if err != nil {
+		log.Fatalf("failed to open I²C: %v", err)
+	}
+	defer bus.Close()
+
+	gauge, err := max17048.New(bus, &max17048.DefaultOpts)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	// Raise ALRT when the cell is outside 3.3V-4.25V or below 10%.
+	if err := gauge.SetVoltageAlert(3300*physic.MilliVolt, 4250*physic.MilliVolt); err != nil {
+		log.Fatalln(err)
+	}
+	if err := gauge.SetSoCAlert(10, false); err != nil {
+		log.Fatalln(err)
+	}
+	s, err := gauge.Sense()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Println(s)
+}
@@ -0,0 +1,274 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package max17048
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/physic"
+)
+
+// Alert is a set of alerts reported by the status register.
+type Alert uint8
+
+// Possible alerts.
+const (
+	// ResetAlert is set after a power on reset, until cleared.
+	ResetAlert Alert = 1 << iota
+	// VoltageHighAlert is set when the cell voltage is above the maximum.
+	VoltageHighAlert
+	// VoltageLowAlert is set when the cell voltage is below the minimum.
+	VoltageLowAlert
+	// VoltageResetAlert is set when the cell voltage dropped below the reset
+	// threshold, signaling a battery change.
+	VoltageResetAlert
+	// SoCLowAlert is set when the state of charge crossed the empty
+	// threshold.
+	SoCLowAlert
+	// SoCChangeAlert is set when the state of charge changed by 1%, when
+	// enabled.
+	SoCChangeAlert
+)
+
+func (a Alert) String() string {
+	var s []string
+	for i, n := range []string{"Reset", "VoltageHigh", "VoltageLow", "VoltageReset", "SoCLow", "SoCChange"} {
+		if a&(1<<uint(i)) != 0 {
+			s = append(s, n)
+		}
+	}
+	if len(s) == 0 {
+		return "None"
+	}
+	return strings.Join(s, "|")
+}
+
+// Opts holds the configuration options.
+type Opts struct {
+	// Cells is the number of cells in series, 1 for the MAX17048 and 2 for
+	// the MAX17049.
+	Cells int
+}
+
+// DefaultOpts is the recommended default options, for a MAX17048.
+var DefaultOpts = Opts{
+	Cells: 1,
+}
+
+// Address is the fixed i2c address of the MAX17048.
+const Address = 0x36
+
+// New opens a handle to a MAX17048 or MAX17049.
+//
+// The version register is checked.
+func New(bus i2c.Bus, opts *Opts) (*Dev, error) {
+	if opts.Cells != 1 && opts.Cells != 2 {
+		return nil, fmt.Errorf("max17048: invalid number of cells %d", opts.Cells)
+	}
+	d := &Dev{c: i2c.Dev{Bus: bus, Addr: Address}, cells: opts.Cells}
+	v, err := d.readRegister(versionRegister)
+	if err != nil {
+		return nil, fmt.Errorf("max17048: %v", err)
+	}
+	if v&0xFFF0 != 0x0010 {
+		return nil, fmt.Errorf("max17048: unexpected version %#04x", v)
+	}
+	return d, nil
+}
+
+// Dev is a handle to a MAX17048 or MAX17049.
+type Dev struct {
+	c     i2c.Dev
+	cells int
+
+	mu sync.Mutex
+}
+
+// State is the state of the battery.
+type State struct {
+	// Voltage is the voltage of the battery, for all the cells.
+	Voltage physic.ElectricPotential
+	// SoC is the state of charge in percent. It can exceed 100% while the
+	// model adapts to the battery.
+	SoC float64
+	// Rate is the charge (positive) or discharge (negative) rate in percent
+	// per hour.
+	Rate float64
+}
+
+func (s State) String() string {
+	return fmt.Sprintf("Voltage: %s, SoC: %.2f%%, Rate: %.2f%%/h", s.Voltage, s.SoC, s.Rate)
+}
+
+func (d *Dev) String() string {
+	return "MAX17048{" + d.c.String() + "}"
+}
+
+// Sense reads the battery state.
+func (d *Dev) Sense() (State, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	vcell, err := d.readRegister(vcellRegister)
+	if err != nil {
+		return State{}, fmt.Errorf("max17048: %v", err)
+	}
+	soc, err := d.readRegister(socRegister)
+	if err != nil {
+		return State{}, fmt.Errorf("max17048: %v", err)
+	}
+	crate, err := d.readRegister(crateRegister)
+	if err != nil {
+		return State{}, fmt.Errorf("max17048: %v", err)
+	}
+	return State{
+		// 78.125µV per cell.
+		Voltage: physic.ElectricPotential(vcell) * 78125 * physic.NanoVolt * physic.ElectricPotential(d.cells),
+		// 1/256%.
+		SoC: float64(soc) / 256,
+		// 0.208%/h.
+		Rate: float64(int16(crate)) * 0.208,
+	}, nil
+}
+
+// SetVoltageAlert sets the thresholds of the VoltageLowAlert and
+// VoltageHighAlert alerts, for all the cells. The resolution is 20mV per
+// cell.
+func (d *Dev) SetVoltageAlert(min, max physic.ElectricPotential) error {
+	if min < 0 || max < min {
+		return fmt.Errorf("max17048: invalid voltage alert %s-%s", min, max)
+	}
+	step := 20 * physic.MilliVolt * physic.ElectricPotential(d.cells)
+	lo, hi := min/step, max/step
+	if hi > 0xFF {
+		hi = 0xFF
+	}
+	if lo > 0xFF {
+		lo = 0xFF
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.writeRegister(valrtRegister, uint16(lo)<<8|uint16(hi)); err != nil {
+		return fmt.Errorf("max17048: %v", err)
+	}
+	return nil
+}
+
+// SetSoCAlert sets the state of charge threshold of SoCLowAlert, between 1
+// and 32%. changeAlert enables SoCChangeAlert at each 1% change.
+func (d *Dev) SetSoCAlert(threshold int, changeAlert bool) error {
+	if threshold < 1 || threshold > 32 {
+		return fmt.Errorf("max17048: invalid state of charge alert %d%%", threshold)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	config, err := d.readRegister(configRegister)
+	if err != nil {
+		return fmt.Errorf("max17048: %v", err)
+	}
+	config = config&^(configALSC|configATHD) | uint16(32-threshold)
+	if changeAlert {
+		config |= configALSC
+	}
+	if err := d.writeRegister(configRegister, config); err != nil {
+		return fmt.Errorf("max17048: %v", err)
+	}
+	return nil
+}
+
+// Alerts returns the alerts that fired since they were last cleared.
+func (d *Dev) Alerts() (Alert, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	status, err := d.readRegister(statusRegister)
+	if err != nil {
+		return 0, fmt.Errorf("max17048: %v", err)
+	}
+	return Alert(status>>8) & allAlerts, nil
+}
+
+// ClearAlerts clears the given alerts, and releases the ALRT pin once none
+// is left.
+func (d *Dev) ClearAlerts(a Alert) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	status, err := d.readRegister(statusRegister)
+	if err != nil {
+		return fmt.Errorf("max17048: %v", err)
+	}
+	status &^= uint16(a&allAlerts) << 8
+	if err := d.writeRegister(statusRegister, status); err != nil {
+		return fmt.Errorf("max17048: %v", err)
+	}
+	if Alert(status>>8)&allAlerts != 0 {
+		return nil
+	}
+	config, err := d.readRegister(configRegister)
+	if err != nil {
+		return fmt.Errorf("max17048: %v", err)
+	}
+	if err := d.writeRegister(configRegister, config&^configALRT); err != nil {
+		return fmt.Errorf("max17048: %v", err)
+	}
+	return nil
+}
+
+// QuickStart restarts the state of charge estimation from the current
+// voltage, for example after a battery was inserted while loaded.
+func (d *Dev) QuickStart() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.writeRegister(modeRegister, modeQuickStart); err != nil {
+		return fmt.Errorf("max17048: %v", err)
+	}
+	return nil
+}
+
+// Halt implements conn.Resource.
+//
+// It is a noop, the gauge keeps tracking the battery.
+func (d *Dev) Halt() error {
+	return nil
+}
+
+//
+
+const (
+	vcellRegister   = 0x02
+	socRegister     = 0x04
+	modeRegister    = 0x06
+	versionRegister = 0x08
+	configRegister  = 0x0C
+	valrtRegister   = 0x14
+	crateRegister   = 0x16
+	statusRegister  = 0x1A
+
+	modeQuickStart = 0x4000
+
+	configALSC = 0x0040
+	configALRT = 0x0020
+	configATHD = 0x001F
+
+	allAlerts = ResetAlert | VoltageHighAlert | VoltageLowAlert | VoltageResetAlert | SoCLowAlert | SoCChangeAlert
+)
+
+// readRegister reads a big endian 16 bits register.
+func (d *Dev) readRegister(reg uint8) (uint16, error) {
+	var b [2]byte
+	if err := d.c.Tx([]byte{reg}, b[:]); err != nil {
+		return 0, err
+	}
+	return uint16(b[0])<<8 | uint16(b[1]), nil
+}
+
+// writeRegister writes a big endian 16 bits register.
+func (d *Dev) writeRegister(reg uint8, v uint16) error {
+	return d.c.Tx([]byte{reg, byte(v >> 8), byte(v)}, nil)
+}
+
+var _ conn.Resource = &Dev{}
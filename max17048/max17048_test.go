@@ -0,0 +1,150 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package max17048
+
+import (
+	"testing"
+
+	"periph.io/x/conn/v3/i2c/i2ctest"
+	"periph.io/x/conn/v3/physic"
+)
+
+var initOps = []i2ctest.IO{
+	{Addr: Address, W: []byte{versionRegister}, R: []byte{0x00, 0x12}},
+}
+
+func TestNew(t *testing.T) {
+	var tests = []struct {
+		name string
+		opts Opts
+		tx   []i2ctest.IO
+		err  bool
+	}{
+		{name: "default", opts: DefaultOpts, tx: initOps},
+		{name: "badCells", opts: Opts{Cells: 3}, err: true},
+		{
+			name: "wrongDevice",
+			opts: DefaultOpts,
+			tx:   []i2ctest.IO{{Addr: Address, W: []byte{versionRegister}, R: []byte{0x00, 0x22}}},
+			err:  true,
+		},
+		{name: "ioFail", opts: DefaultOpts, err: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			bus := &i2ctest.Playback{Ops: test.tx, DontPanic: true}
+			_, err := New(bus, &test.opts)
+			if (err != nil) != test.err {
+				t.Fatalf("got error %v", err)
+			}
+			if err := bus.Close(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestSense(t *testing.T) {
+	for _, cells := range []int{1, 2} {
+		ops := append([]i2ctest.IO{}, initOps...)
+		ops = append(ops,
+			// 3.7V per cell.
+			i2ctest.IO{Addr: Address, W: []byte{vcellRegister}, R: []byte{0xB9, 0x00}},
+			// 50.5%.
+			i2ctest.IO{Addr: Address, W: []byte{socRegister}, R: []byte{0x32, 0x80}},
+			// -2.08%/h.
+			i2ctest.IO{Addr: Address, W: []byte{crateRegister}, R: []byte{0xFF, 0xF6}},
+		)
+		bus := &i2ctest.Playback{Ops: ops, DontPanic: true}
+		d, err := New(bus, &Opts{Cells: cells})
+		if err != nil {
+			t.Fatal(err)
+		}
+		s, err := d.Sense()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := 3700 * physic.MilliVolt * physic.ElectricPotential(cells); s.Voltage != want {
+			t.Errorf("voltage %s, want %s", s.Voltage, want)
+		}
+		if s.SoC != 50.5 {
+			t.Errorf("soc %f", s.SoC)
+		}
+		if s.Rate > -2.079 || s.Rate < -2.081 {
+			t.Errorf("rate %f", s.Rate)
+		}
+		if err := bus.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestAlerts(t *testing.T) {
+	ops := append([]i2ctest.IO{}, initOps...)
+	ops = append(ops,
+		// SetVoltageAlert.
+		i2ctest.IO{Addr: Address, W: []byte{valrtRegister, 0x96, 0xD2}},
+		// SetSoCAlert.
+		i2ctest.IO{Addr: Address, W: []byte{configRegister}, R: []byte{0x97, 0x1C}},
+		i2ctest.IO{Addr: Address, W: []byte{configRegister, 0x97, 0x56}},
+		// Alerts.
+		i2ctest.IO{Addr: Address, W: []byte{statusRegister}, R: []byte{0x11, 0xFF}},
+		// ClearAlerts, one left.
+		i2ctest.IO{Addr: Address, W: []byte{statusRegister}, R: []byte{0x11, 0xFF}},
+		i2ctest.IO{Addr: Address, W: []byte{statusRegister, 0x10, 0xFF}},
+		// ClearAlerts, none left so ALRT is cleared.
+		i2ctest.IO{Addr: Address, W: []byte{statusRegister}, R: []byte{0x10, 0xFF}},
+		i2ctest.IO{Addr: Address, W: []byte{statusRegister, 0x00, 0xFF}},
+		i2ctest.IO{Addr: Address, W: []byte{configRegister}, R: []byte{0x97, 0x76}},
+		i2ctest.IO{Addr: Address, W: []byte{configRegister, 0x97, 0x56}},
+		// QuickStart.
+		i2ctest.IO{Addr: Address, W: []byte{modeRegister, 0x40, 0x00}},
+	)
+	bus := &i2ctest.Playback{Ops: ops, DontPanic: true}
+	d, err := New(bus, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetVoltageAlert(4*physic.Volt, 3*physic.Volt); err == nil {
+		t.Fatal("invalid voltage alert")
+	}
+	if err := d.SetVoltageAlert(3*physic.Volt, 4200*physic.MilliVolt); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetSoCAlert(0, false); err == nil {
+		t.Fatal("invalid soc alert")
+	}
+	if err := d.SetSoCAlert(10, true); err != nil {
+		t.Fatal(err)
+	}
+	a, err := d.Alerts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a != ResetAlert|SoCLowAlert {
+		t.Fatalf("unexpected alerts %s", a)
+	}
+	if err := d.ClearAlerts(ResetAlert); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.ClearAlerts(SoCLowAlert); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.QuickStart(); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAlert_String(t *testing.T) {
+	if s := (VoltageLowAlert | SoCChangeAlert).String(); s != "VoltageLow|SoCChange" {
+		t.Fatal(s)
+	}
+	if s := Alert(0).String(); s != "None" {
+		t.Fatal(s)
+	}
+}
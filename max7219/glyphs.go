@@ -269,7 +269,7 @@ var CP437Glyphs = [][]byte{
 // reverseGlyphs swaps the endianness of the raster bits to work with the 7219.
 // Returns the a new set with the byte values reversed.
 func reverseGlyphs(digits [][]byte) [][]byte {
-	nibbles := [16]byte{0x0, 0x8, 0x4, 0xc, 0x2, 0xa, 0x6, 0xe, 0x1, 0x9, 0x5, 0xc, 0x3, 0xb, 0x7, 0xf}
+	nibbles := [16]byte{0x0, 0x8, 0x4, 0xc, 0x2, 0xa, 0x6, 0xe, 0x1, 0x9, 0x5, 0xd, 0x3, 0xb, 0x7, 0xf}
 	result := make([][]byte, len(digits))
 	for i := 0; i < len(digits); i++ {
 		newChar := make([]byte, 8)
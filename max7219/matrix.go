@@ -0,0 +1,77 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package max7219
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+
+	"periph.io/x/conn/v3/display"
+	"periph.io/x/devices/v3/ssd1306/image1bit"
+)
+
+// ColorModel implements display.Drawer.
+// It is a one bit color model, as implemented by image1bit.Bit.
+func (d *Dev) ColorModel() color.Model {
+	return image1bit.BitModel
+}
+
+// Bounds implements display.Drawer. Min is guaranteed to be {0, 0}.
+//
+// The cascaded 8x8 matrixes form one wide display, unit 0 being the leftmost
+// one.
+func (d *Dev) Bounds() image.Rectangle {
+	return image.Rect(0, 0, d.units*8, int(d.digits))
+}
+
+// Draw implements display.Drawer.
+//
+// It is only supported in matrix mode, that is with DecodeNone. The pixels
+// are laid out like the glyphs of CP437Glyphs set with reverse true: the
+// first raster line is the top one and bit 0 is the leftmost column.
+//
+// The Dev keeps the frame buffer between calls to Draw, so only the pixels in
+// r are changed. Clear erases it.
+func (d *Dev) Draw(r image.Rectangle, src image.Image, sp image.Point) error {
+	if d.ctrl != nil {
+		return errMAX7219Only
+	}
+	if d.decode != DecodeNone {
+		return errMatrixOnly
+	}
+	if d.frame == nil {
+		d.frame = image1bit.NewVerticalLSB(d.Bounds())
+	}
+	r = r.Intersect(d.Bounds())
+	if r.Empty() {
+		return nil
+	}
+	draw.Src.Draw(d.frame, r, src, sp)
+	return d.WriteCascadedUnits(d.frameUnits())
+}
+
+// frameUnits converts the frame buffer into the raster lines of each unit.
+func (d *Dev) frameUnits() [][]byte {
+	units := make([][]byte, d.units)
+	for u := range units {
+		units[u] = make([]byte, d.digits)
+		for y := range int(d.digits) {
+			var b byte
+			for x := range 8 {
+				if d.frame.BitAt(u*8+x, y) {
+					b |= 1 << x
+				}
+			}
+			units[u][y] = b
+		}
+	}
+	return units
+}
+
+var errMatrixOnly = errors.New("max7219: only supported in matrix mode with DecodeNone")
+
+var _ display.Drawer = &Dev{}
@@ -0,0 +1,67 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package max7219
+
+import (
+	"image"
+	"testing"
+
+	"periph.io/x/conn/v3/conntest"
+	"periph.io/x/conn/v3/spi/spitest"
+	"periph.io/x/devices/v3/ssd1306/image1bit"
+)
+
+func TestDraw(t *testing.T) {
+	record := &spitest.Record{}
+	dev, err := NewSPI(record, 2, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := dev.Bounds(); b != image.Rect(0, 0, 16, 8) {
+		t.Fatalf("unexpected bounds %v", b)
+	}
+	img := image1bit.NewVerticalLSB(dev.Bounds())
+	img.SetBit(9, 0, image1bit.On)
+	img.SetBit(0, 7, image1bit.On)
+
+	record.Ops = make([]conntest.IO, 0)
+	if err := dev.Draw(dev.Bounds(), img, image.Point{}); err != nil {
+		t.Fatal(err)
+	}
+	expected := []conntest.IO{
+		{W: []uint8{0x1, 0x0, 0x1, 0x1}}, // Bottom line, leftmost LED of unit 0
+		{W: []uint8{0x2, 0x0, 0x2, 0x0}},
+		{W: []uint8{0x3, 0x0, 0x3, 0x0}},
+		{W: []uint8{0x4, 0x0, 0x4, 0x0}},
+		{W: []uint8{0x5, 0x0, 0x5, 0x0}},
+		{W: []uint8{0x6, 0x0, 0x6, 0x0}},
+		{W: []uint8{0x7, 0x0, 0x7, 0x0}},
+		{W: []uint8{0x8, 0x2, 0x8, 0x0}}} // Top line, second LED of unit 1
+	if err := verifyOperations(record.Ops, expected); err != nil {
+		t.Error(err)
+	}
+
+	// Drawing a region keeps the rest of the frame.
+	record.Ops = make([]conntest.IO, 0)
+	if err := dev.Draw(image.Rect(0, 0, 8, 8), image.NewUniform(image1bit.Off), image.Point{}); err != nil {
+		t.Fatal(err)
+	}
+	expected[0] = conntest.IO{W: []uint8{0x1, 0x0, 0x1, 0x0}}
+	if err := verifyOperations(record.Ops, expected); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDraw_DecodeB(t *testing.T) {
+	record := &spitest.Record{}
+	dev, err := NewSPI(record, 1, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img := image1bit.NewVerticalLSB(dev.Bounds())
+	if err := dev.Draw(dev.Bounds(), img, image.Point{}); err == nil {
+		t.Fatal("expected error in DecodeB mode")
+	}
+}
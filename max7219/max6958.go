@@ -0,0 +1,106 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package max7219
+
+import (
+	"errors"
+	"fmt"
+
+	"periph.io/x/conn/v3/i2c"
+)
+
+// I²C addresses of the MAX6958 and MAX6959.
+const (
+	// I2CAddr is the address of the MAX6958A and MAX6959A.
+	I2CAddr uint16 = 0x38
+	// I2CAddrB is the address of the MAX6958B and MAX6959B.
+	I2CAddrB uint16 = 0x39
+)
+
+// NewI2C creates a new seven-segment display driven by a MAX6958 or MAX6959,
+// set by opts.Variant, on the specified I²C bus. opts.Digits is the number of
+// digits displayed, up to 4.
+//
+// Digit 0 is the leftmost one. Write, WriteInt, ScrollChars, Clear,
+// SetIntensity and TestDisplay behave as with a MAX7219 numeric display.
+func NewI2C(b i2c.Bus, addr uint16, opts *Opts) (*Dev, error) {
+	if opts.Variant != MAX6958 && opts.Variant != MAX6959 {
+		return nil, fmt.Errorf("max7219: %s isn't an I²C controller", opts.Variant)
+	}
+	if opts.Units > 1 || opts.Rotation != NoRotation {
+		return nil, errMAX7219Only
+	}
+	if opts.CommonAnode {
+		return nil, errCommonAnode
+	}
+	if opts.Digits <= 0 || opts.Digits > 4 {
+		return nil, errors.New("max7219: invalid value for number of digits")
+	}
+	m := &max6958{dev: &i2c.Dev{Bus: b, Addr: addr}}
+	initCommands := [][2]byte{
+		{max6958DisplayTest, 0x00},
+		{max6958DecodeMode, 0x00},
+		{max6958Intensity, 0x20},
+		{max6958ScanLimit, byte(opts.Digits - 1)},
+		{max6958Config, max6958Normal | max6958ClearData},
+	}
+	for _, cmd := range initCommands {
+		if err := m.write(cmd[0], cmd[1]); err != nil {
+			return nil, fmt.Errorf("max7219: %v", err)
+		}
+	}
+	return &Dev{variant: opts.Variant, ctrl: m, decode: DecodeB, units: 1, digits: byte(opts.Digits)}, nil
+}
+
+//
+
+// MAX6958/MAX6959 registers.
+const (
+	max6958DecodeMode  byte = 0x01
+	max6958Intensity   byte = 0x02
+	max6958ScanLimit   byte = 0x03
+	max6958Config      byte = 0x04
+	max6958DisplayTest byte = 0x07
+	max6958Digit0      byte = 0x20
+	// max6958Segments holds the decimal points, bit n for digit n.
+	max6958Segments byte = 0x24
+
+	// Configuration bits.
+	max6958Normal    byte = 0x01
+	max6958ClearData byte = 0x20
+)
+
+type max6958 struct {
+	dev *i2c.Dev
+}
+
+func (m *max6958) write(register, data byte) error {
+	return m.dev.Tx([]byte{register, data}, nil)
+}
+
+func (m *max6958) writeSegments(seg []byte) error {
+	var points byte
+	for i, s := range seg {
+		if s&DecimalPoint != 0 {
+			points |= 1 << i
+		}
+		if err := m.write(max6958Digit0+byte(i), toPABCDEFG(s)&^DecimalPoint); err != nil {
+			return err
+		}
+	}
+	return m.write(max6958Segments, points)
+}
+
+func (m *max6958) setIntensity(intensity byte) error {
+	// The MAX6958 has 64 levels.
+	return m.write(max6958Intensity, byte(uint(intensity&0x0f)*63/15))
+}
+
+func (m *max6958) setTest(on bool) error {
+	if on {
+		return m.write(max6958DisplayTest, 1)
+	}
+	return m.write(max6958DisplayTest, 0)
+}
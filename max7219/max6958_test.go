@@ -0,0 +1,58 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package max7219
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"periph.io/x/conn/v3/i2c/i2ctest"
+)
+
+func TestMAX6958(t *testing.T) {
+	bus := &i2ctest.Record{}
+	for _, opts := range []Opts{
+		{Variant: MAX6958, Digits: 5},
+		{Variant: MAX6959, Digits: 4, CommonAnode: true},
+		{Variant: MAX6958, Digits: 4, Rotation: Rotate90},
+		{Variant: MAX7219, Digits: 4},
+	} {
+		if _, err := NewI2C(bus, I2CAddr, &opts); err == nil {
+			t.Errorf("NewI2C(%+v) should fail", opts)
+		}
+	}
+
+	dev, err := NewI2C(bus, I2CAddrB, &Opts{Variant: MAX6959, Digits: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := dev.Variant(); v != MAX6959 {
+		t.Errorf("variant: %s", v)
+	}
+	if err := dev.Write([]byte("1.2")); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.SetIntensity(8); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.TestDisplay(true); err != nil {
+		t.Fatal(err)
+	}
+	want := []i2ctest.IO{
+		{Addr: 0x39, W: []byte{0x07, 0x00}}, // Disable display test
+		{Addr: 0x39, W: []byte{0x01, 0x00}}, // Decode mode
+		{Addr: 0x39, W: []byte{0x02, 0x20}}, // Intensity
+		{Addr: 0x39, W: []byte{0x03, 0x01}}, // Scan limit
+		{Addr: 0x39, W: []byte{0x04, 0x21}}, // Normal operation, clear digits
+		{Addr: 0x39, W: []byte{0x20, 0x30}},
+		{Addr: 0x39, W: []byte{0x21, 0x6d}},
+		{Addr: 0x39, W: []byte{0x24, 0x01}}, // Decimal point of digit 0
+		{Addr: 0x39, W: []byte{0x02, 0x21}},
+		{Addr: 0x39, W: []byte{0x07, 0x01}},
+	}
+	if diff := cmp.Diff(bus.Ops, want); diff != "" {
+		t.Errorf("ops difference (-got +want):\n%s", diff)
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package max7219
+
+import (
+	"errors"
+	"fmt"
+
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/spi"
+)
+
+// NewMAX6950 creates a new seven-segment display driven by a MAX6950 using
+// the specified spi.Port. numDigits is the number of digits displayed, up to
+// 5.
+//
+// Digit 0 is the leftmost one. Write, WriteInt, ScrollChars, Clear,
+// SetIntensity and TestDisplay behave as with a MAX7219 numeric display.
+func NewMAX6950(p spi.Port, numDigits int) (*Dev, error) {
+	return newMAX695x(p, MAX6950, numDigits, 5, false)
+}
+
+// NewMAX6951 creates a new seven-segment display driven by a MAX6951 using
+// the specified spi.Port. numDigits is the number of digits displayed, up to
+// 8.
+//
+// Digit 0 is the leftmost one. Write, WriteInt, ScrollChars, Clear,
+// SetIntensity and TestDisplay behave as with a MAX7219 numeric display.
+func NewMAX6951(p spi.Port, numDigits int) (*Dev, error) {
+	return newMAX695x(p, MAX6951, numDigits, 8, false)
+}
+
+//
+
+// MAX6950/MAX6951 registers.
+const (
+	max695xDecodeMode  byte = 0x01
+	max695xIntensity   byte = 0x02
+	max695xScanLimit   byte = 0x03
+	max695xConfig      byte = 0x04
+	max695xDisplayTest byte = 0x07
+	// Digit registers writing both blink planes.
+	max695xDigit0 byte = 0x60
+
+	// Configuration bits.
+	max695xNormal    byte = 0x01
+	max695xClearData byte = 0x20
+)
+
+type max695x struct {
+	conn spi.Conn
+}
+
+func newMAX695x(p spi.Port, v Variant, numDigits, maxDigits int, anode bool) (*Dev, error) {
+	if numDigits <= 0 || numDigits > maxDigits {
+		return nil, errors.New("max7219: invalid value for number of digits")
+	}
+	lines := numDigits
+	if anode {
+		// The 8 digit lines drive the segments.
+		if maxDigits != 8 {
+			return nil, errCommonAnode
+		}
+		lines = 8
+	}
+	// Up to 26MHz, Mode0 only.
+	c, err := p.Connect(10*physic.MegaHertz, spi.Mode0, 8)
+	if err != nil {
+		return nil, fmt.Errorf("max7219: %v", err)
+	}
+	m := &max695x{conn: c}
+	// The segment patterns are written as is; the hexadecimal font of the
+	// decoder doesn't have the characters supported by the MAX7219 Code B.
+	initCommands := [][2]byte{
+		{max695xDisplayTest, 0x00},
+		{max695xDecodeMode, 0x00},
+		{max695xIntensity, 0x08},
+		{max695xScanLimit, byte(lines - 1)},
+		{max695xConfig, max695xNormal | max695xClearData},
+	}
+	for _, cmd := range initCommands {
+		if err := m.write(cmd[0], cmd[1]); err != nil {
+			return nil, err
+		}
+	}
+	var ctrl segmentController = m
+	if anode {
+		ctrl = &commonAnode{m}
+	}
+	return &Dev{variant: v, ctrl: ctrl, decode: DecodeB, units: 1, digits: byte(numDigits)}, nil
+}
+
+func (m *max695x) write(register, data byte) error {
+	return m.conn.Tx([]byte{register, data}, nil)
+}
+
+func (m *max695x) writeSegments(seg []byte) error {
+	for i, s := range seg {
+		// The no-decode segment order is the same as the MAX7219.
+		if err := m.write(max695xDigit0+byte(i), toPABCDEFG(s)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *max695x) setIntensity(intensity byte) error {
+	return m.write(max695xIntensity, intensity&0x0f)
+}
+
+func (m *max695x) setTest(on bool) error {
+	if on {
+		return m.write(max695xDisplayTest, 1)
+	}
+	return m.write(max695xDisplayTest, 0)
+}
@@ -0,0 +1,145 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package max7219
+
+import (
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3/conntest"
+	"periph.io/x/conn/v3/spi/spitest"
+)
+
+func TestMAX695x_Init(t *testing.T) {
+	record := &spitest.Record{}
+	if _, err := NewMAX6950(record, 6); err == nil {
+		t.Error("expected error for 6 digits on MAX6950")
+	}
+	dev, err := NewMAX6951(record, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := dev.Variant(); v != MAX6951 {
+		t.Errorf("variant: %s", v)
+	}
+	expected := []conntest.IO{
+		{W: []uint8{0x07, 0x00}}, // Disable display test
+		{W: []uint8{0x01, 0x00}}, // Decode mode
+		{W: []uint8{0x02, 0x08}}, // Intensity
+		{W: []uint8{0x03, 0x07}}, // Scan limit
+		{W: []uint8{0x04, 0x21}}, // Normal operation, clear digits
+	}
+	if err := verifyOperations(record.Ops, expected); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMAX695x_Write(t *testing.T) {
+	record := &spitest.Record{}
+	dev, err := NewMAX6950(record, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	record.Ops = nil
+	if err := dev.Write([]byte("1.2h")); err != nil {
+		t.Fatal(err)
+	}
+	expected := []conntest.IO{
+		{W: []uint8{0x60, 0xb0}},
+		{W: []uint8{0x61, 0x6d}},
+		{W: []uint8{0x62, 0x17}},
+		{W: []uint8{0x63, 0x00}},
+	}
+	if err := verifyOperations(record.Ops, expected); err != nil {
+		t.Error(err)
+	}
+
+	record.Ops = nil
+	if err := dev.WriteInt(-12); err != nil {
+		t.Fatal(err)
+	}
+	expected = []conntest.IO{
+		{W: []uint8{0x60, 0x00}},
+		{W: []uint8{0x61, 0x01}},
+		{W: []uint8{0x62, 0x30}},
+		{W: []uint8{0x63, 0x6d}},
+	}
+	if err := verifyOperations(record.Ops, expected); err != nil {
+		t.Error(err)
+	}
+
+	record.Ops = nil
+	if err := dev.SetIntensity(0x1f); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.TestDisplay(true); err != nil {
+		t.Fatal(err)
+	}
+	expected = []conntest.IO{{W: []uint8{0x02, 0x0f}}, {W: []uint8{0x07, 0x01}}}
+	if err := verifyOperations(record.Ops, expected); err != nil {
+		t.Error(err)
+	}
+
+	if err := dev.SetDecode(DecodeNone); err == nil {
+		t.Error("expected error on DecodeNone")
+	}
+	if err := dev.WriteCascadedUnits([][]byte{{0}}); err == nil {
+		t.Error("expected error on WriteCascadedUnits")
+	}
+	if _, err := dev.Keys(); err == nil {
+		t.Error("expected error on Keys")
+	}
+}
+
+func TestMAX695x_Scroll(t *testing.T) {
+	record := &spitest.Record{}
+	dev, err := NewMAX6950(record, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	record.Ops = nil
+	dev.ScrollChars([]byte("12"), 2, time.Millisecond)
+	expected := []conntest.IO{
+		{W: []uint8{0x60, 0x30}},
+		{W: []uint8{0x60, 0x6d}},
+		{W: []uint8{0x60, 0x00}},
+		{W: []uint8{0x60, 0x30}},
+	}
+	if err := verifyOperations(record.Ops, expected); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMAX695x_CommonAnode(t *testing.T) {
+	record := &spitest.Record{}
+	dev, err := New(record, &Opts{Variant: MAX6951, Digits: 3, CommonAnode: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := dev.Variant(); v != MAX6951 {
+		t.Errorf("variant: %s", v)
+	}
+	if op := record.Ops[3]; op.W[0] != 0x03 || op.W[1] != 0x07 {
+		t.Errorf("scan limit: %#v", op.W)
+	}
+
+	record.Ops = nil
+	if err := dev.Write([]byte("1.")); err != nil {
+		t.Fatal(err)
+	}
+	expected := []conntest.IO{
+		{W: []uint8{0x60, 0x00}},
+		{W: []uint8{0x61, 0x40}},
+		{W: []uint8{0x62, 0x40}},
+		{W: []uint8{0x63, 0x00}},
+		{W: []uint8{0x64, 0x00}},
+		{W: []uint8{0x65, 0x00}},
+		{W: []uint8{0x66, 0x00}},
+		{W: []uint8{0x67, 0x40}},
+	}
+	if err := verifyOperations(record.Ops, expected); err != nil {
+		t.Error(err)
+	}
+}
@@ -6,6 +6,12 @@
 // numeric 7-segment displays, or on matrix displays. It simplifies writes
 // and provides useful features like scrolling characters on either type
 // of display unit.
+//
+// Besides the MAX7219/MAX7221, the same seven-segment API drives the MAX6950
+// and MAX6951, see NewMAX6950 and NewMAX6951, the MAX6958 and MAX6959 on
+// I²C, see NewI2C, and TM1638 modules with key scanning, see NewTM1638.
+//
+// Common anode seven-segment displays are supported with Opts.CommonAnode.
 package max7219
 
 import (
@@ -16,6 +22,7 @@ import (
 
 	"periph.io/x/conn/v3/physic"
 	"periph.io/x/conn/v3/spi"
+	"periph.io/x/devices/v3/ssd1306/image1bit"
 )
 
 // DecodeMode is the mode for handling data. Refer to the datasheet for
@@ -51,6 +58,11 @@ const (
 // Type for a Maxim MAX7219/MAX7221 device.
 type Dev struct {
 	conn spi.Conn
+	// variant is the controller chip.
+	variant Variant
+	// ctrl drives the controllers other than the MAX7219. It is nil for the
+	// MAX7219.
+	ctrl segmentController
 	// decode mode for all data registers
 	decode DecodeMode
 	// units is the number of 7219 units daisy-chained together.
@@ -61,6 +73,11 @@ type Dev struct {
 	// is the code point (e.g. 0x20=32=space. The second index is the 8 byte
 	// raster values for each line in the matrix.
 	glyphs [][]byte
+	// frame is the frame buffer used by Draw in matrix mode. It is allocated
+	// on first use.
+	frame *image1bit.VerticalLSB
+	// rotation is applied to each unit of a matrix display.
+	rotation Rotation
 }
 
 // emptyBytes creates a slice of empty bytes (digit values or byte values)
@@ -131,13 +148,95 @@ func NewSPI(p spi.Port, units, numDigits int) (*Dev, error) {
 	if err != nil {
 		return nil, fmt.Errorf("max7219: %v", err)
 	}
-	d := &Dev{conn: c, digits: byte(numDigits), units: units, glyphs: nil}
+	d := &Dev{conn: c, variant: MAX7219, digits: byte(numDigits), units: units, glyphs: defaultGlyphs}
 	d.init()
 	return d, nil
 }
 
+// Opts is the configuration of a display, for New and NewI2C.
+type Opts struct {
+	// Variant is the controller chip.
+	Variant Variant
+	// Units is the number of MAX7219 daisy-chained together, 1 if 0.
+	Units int
+	// Digits is the number of digits displayed, or the size of the matrix.
+	Digits int
+	// CommonAnode is set for seven-segment displays with a common anode per
+	// digit. The controllers are made for common cathode displays; a common
+	// anode one is wired with the digit and segment lines swapped: the anode
+	// of digit n, leftmost first, on the segment line n in the order A to G
+	// then DP, and the segment n of all the digits on the digit line n.
+	//
+	// It needs the 8 digit lines of a single MAX7219 or of a MAX6951, and
+	// supports up to 8 digits.
+	CommonAnode bool
+	// Rotation is the transformation applied to each unit of a matrix
+	// display, see SetRotation.
+	Rotation Rotation
+}
+
+// New creates a new display driven by one of the SPI controllers using the
+// specified spi.Port.
+//
+// NewSPI, NewMAX6950 and NewMAX6951 are shortcuts for the displays with a
+// common cathode.
+func New(p spi.Port, opts *Opts) (*Dev, error) {
+	units := opts.Units
+	if units == 0 {
+		units = 1
+	}
+	if units != 1 && opts.Variant != MAX7219 {
+		return nil, errMAX7219Only
+	}
+	var d *Dev
+	var err error
+	switch opts.Variant {
+	case MAX7219:
+		if opts.CommonAnode {
+			d, err = newCommonAnodeMAX7219(p, units, opts.Digits)
+		} else {
+			d, err = NewSPI(p, units, opts.Digits)
+		}
+	case MAX6950:
+		d, err = newMAX695x(p, MAX6950, opts.Digits, 5, opts.CommonAnode)
+	case MAX6951:
+		d, err = newMAX695x(p, MAX6951, opts.Digits, 8, opts.CommonAnode)
+	default:
+		return nil, fmt.Errorf("max7219: %s isn't an SPI controller", opts.Variant)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if opts.Rotation != NoRotation {
+		if err := d.SetRotation(opts.Rotation); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+// Variant returns the controller chip driving the display.
+func (d *Dev) Variant() Variant {
+	return d.variant
+}
+
+func (d *Dev) String() string {
+	if d.conn != nil {
+		return fmt.Sprintf("%s{%s}", d.variant, d.conn)
+	}
+	return d.variant.String()
+}
+
+// Halt implements conn.Resource.
+//
+// It clears the display.
+func (d *Dev) Halt() error {
+	return d.Clear()
+}
+
 // Clear erases the content of all display segments or matrix LEDs.
 func (d *Dev) Clear() error {
+	d.frame = nil
 	empty := d.emptyBytes()
 	if d.units > 1 {
 		w := make([][]byte, d.units)
@@ -150,6 +249,73 @@ func (d *Dev) Clear() error {
 	}
 }
 
+var errCommonAnode = errors.New("max7219: common anode displays need a controller with 8 digit lines")
+
+// max7219Segments drives a single MAX7219 without decoding, for the common
+// anode displays.
+type max7219Segments struct {
+	conn spi.Conn
+}
+
+func newCommonAnodeMAX7219(p spi.Port, units, numDigits int) (*Dev, error) {
+	if units != 1 {
+		return nil, errCommonAnode
+	}
+	if numDigits <= 0 || numDigits > 8 {
+		return nil, errors.New("max7219: invalid value for number of digits")
+	}
+	c, err := p.Connect(10*physic.MegaHertz, spi.Mode0, 8)
+	if err != nil {
+		return nil, fmt.Errorf("max7219: %v", err)
+	}
+	m := &max7219Segments{conn: c}
+	// The 8 digit lines drive the segments.
+	initCommands := [][2]byte{
+		{_REGISTER_DISPLAY_TEST, 0x00},
+		{_REGISTER_SHUTDOWN, 0x00},
+		{_REGISTER_DECODE_MODE, byte(DecodeNone)},
+		{_REGISTER_INTENSITY, 0x08},
+		{_REGISTER_SCAN_LIMIT, 0x07},
+	}
+	for _, cmd := range initCommands {
+		if err := m.write(cmd[0], cmd[1]); err != nil {
+			return nil, err
+		}
+	}
+	ctrl := &commonAnode{m}
+	if err := ctrl.writeSegments(nil); err != nil {
+		return nil, err
+	}
+	if err := m.write(_REGISTER_SHUTDOWN, 0x01); err != nil {
+		return nil, err
+	}
+	return &Dev{variant: MAX7219, ctrl: ctrl, decode: DecodeB, units: 1, digits: byte(numDigits)}, nil
+}
+
+func (m *max7219Segments) write(register, data byte) error {
+	return m.conn.Tx([]byte{register, data}, nil)
+}
+
+func (m *max7219Segments) writeSegments(seg []byte) error {
+	for i, s := range seg {
+		if err := m.write(byte(i+1), toPABCDEFG(s)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *max7219Segments) setIntensity(intensity byte) error {
+	return m.write(_REGISTER_INTENSITY, intensity&0x0f)
+}
+
+func (m *max7219Segments) setTest(on bool) error {
+	if on {
+		return m.write(_REGISTER_DISPLAY_TEST, 1)
+	}
+	return m.write(_REGISTER_DISPLAY_TEST, 0)
+}
+
 // shiftBytes shifts an array of raster characters left one LED Column (bit).
 // Used to continuously scroll a display of glyphs.
 func shiftBytes(bytes [][]byte) {
@@ -185,7 +351,14 @@ func shiftBytes(bytes [][]byte) {
 // led column at a time. This can be used to scroll a matrix display of glyphs,
 // or digits on a seven-segment display. If the length of data is less than
 // the number of display units, it writes that directly without scrolling.
+//
+// ScrollChars blocks until scrolling is done. Use StartScroll to scroll text
+// in the background.
 func (d *Dev) ScrollChars(data []byte, scrollCount int, updateInterval time.Duration) {
+	if d.ctrl != nil {
+		d.scrollText(data, scrollCount, updateInterval)
+		return
+	}
 	if d.decode == DecodeNone {
 		// This is a matrix
 
@@ -231,6 +404,7 @@ func (d *Dev) ScrollChars(data []byte, scrollCount int, updateInterval time.Dura
 }
 
 // SetGlyphs allows you to set the character set for use by the matrix display.
+// The default is CP437Glyphs.
 // If the endianness of the charset doesn't match that used by the max7219,
 // pass true for reverse and it will change the endianness of the raster
 // values.
@@ -249,7 +423,15 @@ func (d *Dev) SetGlyphs(glyphs [][]byte, reverse bool) {
 // SetDecode tells the Max7219 whether values should be decoded for a 7 segment
 // display, or if they should be interpreted literally. Refer to the datasheet
 // for more detailed information.
+//
+// The other variants only support DecodeB.
 func (d *Dev) SetDecode(mode DecodeMode) error {
+	if d.ctrl != nil {
+		if mode != DecodeB {
+			return errMAX7219Only
+		}
+		return nil
+	}
 	d.decode = mode
 	return d.sendCommand(_REGISTER_DECODE_MODE, byte(mode))
 }
@@ -258,13 +440,40 @@ func (d *Dev) SetDecode(mode DecodeMode) error {
 // intensity is from 0-15. Keep in mind that the brighter display, the more
 // current drawn.
 func (d *Dev) SetIntensity(intensity byte) error {
+	if d.ctrl != nil {
+		return d.ctrl.setIntensity(intensity)
+	}
 	return d.sendCommand(_REGISTER_INTENSITY, intensity&0x0f)
 }
 
+// SetUnitIntensity controls the brightness of a single unit in a set of
+// cascaded 7219 chips. unit is the 0 based number of the unit. The allowed
+// range for intensity is from 0-15.
+func (d *Dev) SetUnitIntensity(unit int, intensity byte) error {
+	if d.ctrl != nil {
+		return errMAX7219Only
+	}
+	if unit < 0 || unit >= d.units {
+		return errors.New("max7219: invalid unit")
+	}
+	w := make([]byte, 0, d.units*2)
+	for matrix := d.units - 1; matrix >= 0; matrix-- {
+		if matrix == unit {
+			w = append(w, _REGISTER_INTENSITY, intensity&0x0f)
+		} else {
+			w = append(w, _REGISTER_NOOP, 0)
+		}
+	}
+	return d.conn.Tx(w, nil)
+}
+
 // TestDisplay turns on the 7219 display mode which set all segments (or LEDs) on,
 // and  the intensity to maximum. If you're using multiple units, you should be
 // aware  of the current draw, and limit how long you leave this on.
 func (d *Dev) TestDisplay(on bool) error {
+	if d.ctrl != nil {
+		return d.ctrl.setTest(on)
+	}
 	if on {
 		return d.sendCommand(_REGISTER_DISPLAY_TEST, 1)
 	} else {
@@ -334,7 +543,13 @@ func convertBytes(bytes []byte) []byte {
 // supported CodeB values and written. If units are cascaded, this method
 // automatically handles re-formatting the data, and writing it to the cascaded
 // 7219 units.
+//
+// On the other variants, the ASCII characters that can be represented on
+// seven segments are also supported, and the text is written left aligned.
 func (d *Dev) Write(bytes []byte) error {
+	if d.ctrl != nil {
+		return d.writeText(bytes)
+	}
 
 	if d.decode == DecodeNone {
 		return d.writeChars(bytes)
@@ -398,7 +613,17 @@ func (d *Dev) WriteInt(value int) error {
 // the complexities of how data is shifted from one 7219
 // to the next in a chain.
 func (d *Dev) WriteCascadedUnits(bytes [][]byte) error {
+	if d.ctrl != nil {
+		return errMAX7219Only
+	}
 	matrixCount := len(bytes)
+	if d.rotation != NoRotation {
+		rotated := make([][]byte, matrixCount)
+		for ix := range bytes {
+			rotated[ix] = d.rotate(bytes[ix])
+		}
+		bytes = rotated
+	}
 	for rasterLine := 0; rasterLine < int(d.digits); rasterLine++ {
 		w := make([]byte, 0)
 		for matrix := matrixCount - 1; matrix >= 0; matrix-- {
@@ -419,6 +644,10 @@ func (d *Dev) WriteCascadedUnits(bytes [][]byte) error {
 // of a cascaded matrix. Imagine rolling a digit upwards to bring
 // in a new one...
 func (d *Dev) WriteCascadedUnit(offset int, data []byte) error {
+	if d.ctrl != nil {
+		return errMAX7219Only
+	}
+	data = d.rotate(data)
 	for i := byte(0); i < d.digits; i++ {
 		w := make([]byte, 0)
 		for matrix := d.units - 1; matrix >= 0; matrix-- {
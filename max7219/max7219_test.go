@@ -232,3 +232,64 @@ func TestCommand(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestNew_CommonAnode(t *testing.T) {
+	record := &spitest.Record{}
+	dev, err := New(record, &Opts{Digits: 2, CommonAnode: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []conntest.IO{
+		{W: []uint8{0xf, 0x0}}, // Disable self-test
+		{W: []uint8{0xc, 0x0}}, // Shutdown - Enter Shutdown Mode
+		{W: []uint8{0x9, 0x0}}, // Decode Mode
+		{W: []uint8{0xa, 0x8}}, // Intensity
+		{W: []uint8{0xb, 0x7}}, // Scan Limit, all the segments
+		{W: []uint8{0x1, 0x0}}, // Clear segments A-G and DP
+		{W: []uint8{0x2, 0x0}},
+		{W: []uint8{0x3, 0x0}},
+		{W: []uint8{0x4, 0x0}},
+		{W: []uint8{0x5, 0x0}},
+		{W: []uint8{0x6, 0x0}},
+		{W: []uint8{0x7, 0x0}},
+		{W: []uint8{0x8, 0x0}},
+		{W: []uint8{0xc, 0x1}}, // Shutdown - Resume Normal Mode
+	}
+	if err := verifyOperations(record.Ops, expected); err != nil {
+		t.Error(err)
+	}
+
+	// The digits are on the segment lines, leftmost on SEG A.
+	record.Ops = nil
+	if err := dev.Write([]byte("12")); err != nil {
+		t.Fatal(err)
+	}
+	expected = []conntest.IO{
+		{W: []uint8{0x1, 0x20}}, // A: 2
+		{W: []uint8{0x2, 0x60}}, // B: 1 and 2
+		{W: []uint8{0x3, 0x40}}, // C: 1
+		{W: []uint8{0x4, 0x20}}, // D: 2
+		{W: []uint8{0x5, 0x20}}, // E: 2
+		{W: []uint8{0x6, 0x00}}, // F
+		{W: []uint8{0x7, 0x20}}, // G: 2
+		{W: []uint8{0x8, 0x00}}, // DP
+	}
+	if err := verifyOperations(record.Ops, expected); err != nil {
+		t.Error(err)
+	}
+	if err := dev.SetDecode(DecodeNone); err == nil {
+		t.Error("expected error on DecodeNone")
+	}
+
+	for _, opts := range []Opts{
+		{Digits: 4, Units: 2, CommonAnode: true},
+		{Digits: 9, CommonAnode: true},
+		{Variant: MAX6950, Digits: 4, CommonAnode: true},
+		{Variant: MAX6951, Digits: 4, Units: 2},
+		{Variant: MAX6958, Digits: 4},
+	} {
+		if _, err := New(record, &opts); err == nil {
+			t.Errorf("New(%+v) should fail", opts)
+		}
+	}
+}
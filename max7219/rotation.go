@@ -0,0 +1,96 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package max7219
+
+import "errors"
+
+// Rotation is the transformation applied to each 8x8 matrix unit before it is
+// written. Rotations are clockwise.
+type Rotation uint8
+
+const (
+	// NoRotation writes the raster lines as is.
+	NoRotation Rotation = iota
+	// Rotate90 rotates each unit by 90°. Common FC-16 modules need it.
+	Rotate90
+	// Rotate180 rotates each unit by 180°, e.g. for a display mounted upside
+	// down.
+	Rotate180
+	// Rotate270 rotates each unit by 270°.
+	Rotate270
+	// FlipHorizontal mirrors each unit left to right.
+	FlipHorizontal
+	// FlipVertical mirrors each unit top to bottom.
+	FlipVertical
+)
+
+func (r Rotation) String() string {
+	switch r {
+	case NoRotation:
+		return "NoRotation"
+	case Rotate90:
+		return "Rotate90"
+	case Rotate180:
+		return "Rotate180"
+	case Rotate270:
+		return "Rotate270"
+	case FlipHorizontal:
+		return "FlipHorizontal"
+	case FlipVertical:
+		return "FlipVertical"
+	default:
+		return "Rotation(?)"
+	}
+}
+
+// SetRotation sets the transformation applied to each unit of a matrix
+// display by WriteCascadedUnits, WriteCascadedUnit and the methods using
+// them. It is ignored with DecodeB. The initial one is set with Opts.Rotation.
+//
+// Only 8x8 matrixes, with 8 digits, can be rotated.
+func (d *Dev) SetRotation(r Rotation) error {
+	if d.ctrl != nil {
+		return errMAX7219Only
+	}
+	if r > FlipVertical {
+		return errors.New("max7219: invalid rotation")
+	}
+	if r != NoRotation && d.digits != 8 {
+		return errors.New("max7219: only 8x8 matrixes can be rotated")
+	}
+	d.rotation = r
+	return nil
+}
+
+// rotate returns the raster lines of a unit transformed as set with
+// SetRotation.
+func (d *Dev) rotate(unit []byte) []byte {
+	if d.rotation == NoRotation || d.decode != DecodeNone {
+		return unit
+	}
+	out := make([]byte, 8)
+	for y := range 8 {
+		for x := range 8 {
+			// (sx, sy) is the source pixel of (x, y).
+			sx, sy := x, y
+			switch d.rotation {
+			case Rotate90:
+				sx, sy = y, 7-x
+			case Rotate180:
+				sx, sy = 7-x, 7-y
+			case Rotate270:
+				sx, sy = 7-y, x
+			case FlipHorizontal:
+				sx = 7 - x
+			case FlipVertical:
+				sy = 7 - y
+			}
+			if unit[sy]&(1<<sx) != 0 {
+				out[y] |= 1 << x
+			}
+		}
+	}
+	return out
+}
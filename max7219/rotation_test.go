@@ -0,0 +1,104 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package max7219
+
+import (
+	"bytes"
+	"testing"
+
+	"periph.io/x/conn/v3/conntest"
+	"periph.io/x/conn/v3/spi/spitest"
+)
+
+func TestRotate(t *testing.T) {
+	// The two leftmost LEDs of the top line.
+	unit := []byte{0x03, 0, 0, 0, 0, 0, 0, 0}
+	data := []struct {
+		r        Rotation
+		expected []byte
+	}{
+		{NoRotation, []byte{0x03, 0, 0, 0, 0, 0, 0, 0}},
+		{Rotate90, []byte{0x80, 0x80, 0, 0, 0, 0, 0, 0}},
+		{Rotate180, []byte{0, 0, 0, 0, 0, 0, 0, 0xc0}},
+		{Rotate270, []byte{0, 0, 0, 0, 0, 0, 0x01, 0x01}},
+		{FlipHorizontal, []byte{0xc0, 0, 0, 0, 0, 0, 0, 0}},
+		{FlipVertical, []byte{0, 0, 0, 0, 0, 0, 0, 0x03}},
+	}
+	dev := &Dev{digits: 8, decode: DecodeNone}
+	for _, line := range data {
+		if err := dev.SetRotation(line.r); err != nil {
+			t.Fatal(err)
+		}
+		if r := dev.rotate(unit); !bytes.Equal(r, line.expected) {
+			t.Errorf("%s: expected %#v, got %#v", line.r, line.expected, r)
+		}
+	}
+}
+
+func TestSetRotation(t *testing.T) {
+	dev := &Dev{digits: 4}
+	if err := dev.SetRotation(Rotate90); err == nil {
+		t.Fatal("expected error for a 4 digit display")
+	}
+	if err := dev.SetRotation(Rotation(10)); err == nil {
+		t.Fatal("expected error for an invalid rotation")
+	}
+}
+
+func TestNew_rotation(t *testing.T) {
+	record := &spitest.Record{}
+	dev, err := New(record, &Opts{Units: 2, Digits: 8, Rotation: Rotate90})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.SetDecode(DecodeNone); err != nil {
+		t.Fatal(err)
+	}
+	record.Ops = make([]conntest.IO, 0)
+	// The two leftmost LEDs of the top line of the second unit, sent first.
+	if err := dev.WriteCascadedUnits([][]byte{make([]byte, 8), {0x03, 0, 0, 0, 0, 0, 0, 0}}); err != nil {
+		t.Fatal(err)
+	}
+	expected := []conntest.IO{
+		{W: []byte{0x01, 0x00, 0x01, 0x00}},
+		{W: []byte{0x02, 0x00, 0x02, 0x00}},
+		{W: []byte{0x03, 0x00, 0x03, 0x00}},
+		{W: []byte{0x04, 0x00, 0x04, 0x00}},
+		{W: []byte{0x05, 0x00, 0x05, 0x00}},
+		{W: []byte{0x06, 0x00, 0x06, 0x00}},
+		{W: []byte{0x07, 0x80, 0x07, 0x00}},
+		{W: []byte{0x08, 0x80, 0x08, 0x00}},
+	}
+	if err := verifyOperations(record.Ops, expected); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := New(&spitest.Record{}, &Opts{Digits: 4, Rotation: Rotate90}); err == nil {
+		t.Fatal("expected error for a 4 digit display")
+	}
+	if _, err := New(&spitest.Record{}, &Opts{Variant: MAX6951, Digits: 8, Rotation: Rotate90}); err == nil {
+		t.Fatal("expected error for a MAX6951")
+	}
+}
+
+func TestSetUnitIntensity(t *testing.T) {
+	record := &spitest.Record{}
+	dev, err := NewSPI(record, 3, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	record.Ops = make([]conntest.IO, 0)
+	if err := dev.SetUnitIntensity(1, 0x1b); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.SetUnitIntensity(3, 0); err == nil {
+		t.Fatal("expected error for unit 3")
+	}
+	expected := []conntest.IO{
+		{W: []uint8{0x0, 0x0, 0xa, 0xb, 0x0, 0x0}}}
+	if err := verifyOperations(record.Ops, expected); err != nil {
+		t.Error(err)
+	}
+}
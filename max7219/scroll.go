@@ -0,0 +1,165 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package max7219
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// defaultGlyphs is CP437Glyphs in the bit order used by the MAX7219. It is
+// the glyph set used until SetGlyphs is called.
+var defaultGlyphs = reverseGlyphs(CP437Glyphs)
+
+// WriteString writes s to the display. Characters that are not ASCII are
+// displayed as '?'.
+//
+// On a matrix display, each character is drawn on one unit using the glyph
+// set, which defaults to CP437Glyphs.
+func (d *Dev) WriteString(s string) error {
+	return d.Write(toBytes(s))
+}
+
+// StartScroll scrolls text from right to left in a goroutine and returns
+// immediately. The text is scrolled repeatedly, one step each interval, until
+// ctx is done. The returned channel is closed once scrolling stopped.
+//
+// On a matrix display, the text is scrolled one LED column at a time and the
+// glyphs are proportionally spaced: the blank columns around each glyph are
+// removed and a single blank column separates the characters. On a
+// seven-segment display, the text is scrolled one digit at a time.
+//
+// Other methods must not be called until scrolling stopped.
+func (d *Dev) StartScroll(ctx context.Context, text string, interval time.Duration) (<-chan struct{}, error) {
+	if interval <= 0 {
+		return nil, errors.New("max7219: invalid scroll interval")
+	}
+	if len(text) == 0 {
+		return nil, errors.New("max7219: nothing to scroll")
+	}
+	var frames int
+	var show func(int) error
+	if d.ctrl == nil && d.decode == DecodeNone {
+		frames, show = d.matrixScroller(toBytes(text))
+	} else {
+		frames, show = d.segmentScroller(toBytes(text))
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for i := 0; ; i = (i + 1) % frames {
+			if err := show(i); err != nil {
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+			}
+		}
+	}()
+	return done, nil
+}
+
+// matrixScroller returns the number of frames needed to scroll text once on a
+// matrix display, and a function showing one of them.
+func (d *Dev) matrixScroller(text []byte) (int, func(int) error) {
+	// Start with a blank display so the text enters from the right.
+	width := d.units * 8
+	cols := make([]byte, width, width+8*len(text))
+	for _, c := range text {
+		cols = append(cols, glyphColumns(d.glyph(c), int(d.digits))...)
+	}
+	units := make([][]byte, d.units)
+	for u := range units {
+		units[u] = make([]byte, d.digits)
+	}
+	show := func(offset int) error {
+		for u := range units {
+			for y := range units[u] {
+				var b byte
+				for x := range 8 {
+					if cols[(offset+u*8+x)%len(cols)]&(1<<y) != 0 {
+						b |= 1 << x
+					}
+				}
+				units[u][y] = b
+			}
+		}
+		return d.WriteCascadedUnits(units)
+	}
+	return len(cols), show
+}
+
+// segmentScroller returns the number of frames needed to scroll text once on a
+// seven-segment display, and a function showing one of them.
+func (d *Dev) segmentScroller(text []byte) (int, func(int) error) {
+	width := int(d.digits) * d.units
+	data := make([]byte, width, width+len(text))
+	for ix := range data {
+		data[ix] = ClearDigit
+	}
+	data = append(data, convertBytes(text)...)
+	w := make([]byte, width)
+	show := func(offset int) error {
+		for ix := range w {
+			w[ix] = data[(offset+ix)%len(data)]
+		}
+		return d.Write(w)
+	}
+	return len(data), show
+}
+
+// glyph returns the raster lines of the character c.
+func (d *Dev) glyph(c byte) []byte {
+	if int(c) < len(d.glyphs) {
+		return d.glyphs[c]
+	}
+	return nil
+}
+
+// glyphColumns converts a glyph into columns, bit 0 being the top raster
+// line. The blank columns on both sides are trimmed and one blank column is
+// appended. A blank glyph is 3 columns wide.
+func glyphColumns(glyph []byte, lines int) []byte {
+	var all [8]byte
+	for y := 0; y < lines && y < len(glyph); y++ {
+		for x := range 8 {
+			if glyph[y]&(1<<x) != 0 {
+				all[x] |= 1 << y
+			}
+		}
+	}
+	first, last := -1, -1
+	for x, c := range all {
+		if c != 0 {
+			if first < 0 {
+				first = x
+			}
+			last = x
+		}
+	}
+	if first < 0 {
+		return make([]byte, 3)
+	}
+	cols := make([]byte, 0, last-first+2)
+	cols = append(cols, all[first:last+1]...)
+	return append(cols, 0)
+}
+
+// toBytes converts s to single byte characters.
+func toBytes(s string) []byte {
+	b := make([]byte, 0, len(s))
+	for _, r := range s {
+		if r >= 0x80 {
+			r = '?'
+		}
+		b = append(b, byte(r))
+	}
+	return b
+}
@@ -0,0 +1,132 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package max7219
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3/conntest"
+	"periph.io/x/conn/v3/spi/spitest"
+)
+
+func TestGlyphColumns(t *testing.T) {
+	// '!' in the MAX7219 bit order is 2 columns wide, in columns 3 and 4.
+	cols := glyphColumns(defaultGlyphs['!'], 8)
+	expected := []byte{0x5f, 0x5f, 0x00}
+	if !bytes.Equal(cols, expected) {
+		t.Errorf("expected %#v, got %#v", expected, cols)
+	}
+	if cols := glyphColumns(defaultGlyphs[' '], 8); !bytes.Equal(cols, []byte{0, 0, 0}) {
+		t.Errorf("unexpected space %#v", cols)
+	}
+}
+
+func TestWriteString(t *testing.T) {
+	record := &spitest.Record{}
+	dev, err := NewSPI(record, 1, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	record.Ops = make([]conntest.IO, 0)
+	if err := dev.WriteString("-1.5é"); err != nil {
+		t.Fatal(err)
+	}
+	expected := []conntest.IO{
+		{W: []uint8{0x8, MinusSign}},
+		{W: []uint8{0x7, 0x1 | DecimalPoint}},
+		{W: []uint8{0x6, 0x5}},
+		{W: []uint8{0x5, '?'}}}
+	if err := verifyOperations(record.Ops, expected); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestStartScroll(t *testing.T) {
+	record := &spitest.Record{}
+	dev, err := NewSPI(record, 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dev.StartScroll(context.Background(), "", time.Millisecond); err == nil {
+		t.Fatal("expected error for empty text")
+	}
+	record.Ops = make([]conntest.IO, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	done, err := dev.StartScroll(ctx, "12", time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Wait for a full cycle, 2 blank digits then the text, each frame
+	// written in 2 transactions.
+	for {
+		record.Lock()
+		n := len(record.Ops)
+		record.Unlock()
+		if n >= 10 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-done
+	expected := []conntest.IO{
+		{W: []uint8{0x2, ClearDigit}},
+		{W: []uint8{0x1, ClearDigit}},
+		{W: []uint8{0x2, ClearDigit}},
+		{W: []uint8{0x1, 0x1}},
+		{W: []uint8{0x2, 0x1}},
+		{W: []uint8{0x1, 0x2}},
+		{W: []uint8{0x2, 0x2}},
+		{W: []uint8{0x1, ClearDigit}},
+		{W: []uint8{0x2, ClearDigit}},
+		{W: []uint8{0x1, ClearDigit}}}
+	if err := verifyOperations(record.Ops[:10], expected); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestStartScroll_Matrix(t *testing.T) {
+	record := &spitest.Record{}
+	dev, err := NewSPI(record, 1, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = dev.SetDecode(DecodeNone)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	record.Ops = make([]conntest.IO, 0)
+	done, err := dev.StartScroll(ctx, "!", time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-done
+	// The first frame is blank.
+	if len(record.Ops) != 8 {
+		t.Fatalf("expected 8 operations, got %d", len(record.Ops))
+	}
+	for _, op := range record.Ops {
+		if op.W[1] != 0 {
+			t.Fatalf("expected blank frame, got %#v", op.W)
+		}
+	}
+}
+
+func TestReverseGlyphs_Nibbles(t *testing.T) {
+	for i := range 256 {
+		b := byte(i)
+		var expected byte
+		for bit := range 8 {
+			if b&(1<<bit) != 0 {
+				expected |= 0x80 >> bit
+			}
+		}
+		if r := reverseGlyphs([][]byte{{b}})[0][0]; r != expected {
+			t.Fatalf("%#x: expected %#x, got %#x", b, expected, r)
+		}
+	}
+}
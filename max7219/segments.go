@@ -0,0 +1,178 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package max7219
+
+import (
+	"errors"
+	"time"
+)
+
+// Variant is the display controller chip.
+type Variant int
+
+const (
+	// MAX7219 is the MAX7219 or MAX7221. It is the only variant that
+	// supports matrix displays and cascaded units.
+	MAX7219 Variant = iota
+	// MAX6950 drives up to 5 seven-segment digits over SPI.
+	MAX6950
+	// MAX6951 drives up to 8 seven-segment digits over SPI.
+	MAX6951
+	// TM1638 drives 8 seven-segment digits and scans keys over a 3-wire
+	// bus. See NewTM1638.
+	TM1638
+	// MAX6958 drives up to 4 seven-segment digits over I²C. See NewI2C.
+	MAX6958
+	// MAX6959 is a MAX6958 with key scanning, which isn't supported.
+	MAX6959
+)
+
+func (v Variant) String() string {
+	switch v {
+	case MAX7219:
+		return "MAX7219"
+	case MAX6950:
+		return "MAX6950"
+	case MAX6951:
+		return "MAX6951"
+	case TM1638:
+		return "TM1638"
+	case MAX6958:
+		return "MAX6958"
+	case MAX6959:
+		return "MAX6959"
+	default:
+		return "Variant(?)"
+	}
+}
+
+// segmentController is implemented by the controllers other than the
+// MAX7219. They are driven with segment patterns encoded as PGFEDCBA, where
+// P is the decimal point:
+//
+//	 -A-
+//	F   B
+//	 -G-
+//	E   C
+//	 -D-   P
+type segmentController interface {
+	// writeSegments writes the patterns, leftmost digit first.
+	writeSegments(seg []byte) error
+	// setIntensity sets the brightness, from 0 to 15.
+	setIntensity(intensity byte) error
+	// setTest turns all the segments on at full brightness.
+	setTest(on bool) error
+}
+
+var errMAX7219Only = errors.New("max7219: only supported by the MAX7219 with a common cathode display")
+
+// commonAnode drives a common anode display with a controller made for common
+// cathode ones, where the digit and segment lines are swapped. See
+// Opts.CommonAnode.
+type commonAnode struct {
+	segmentController
+}
+
+func (c *commonAnode) writeSegments(seg []byte) error {
+	var lines [8]byte
+	for digit, s := range seg {
+		for segment := range 8 {
+			if s&(1<<segment) != 0 {
+				lines[segment] |= 1 << digit
+			}
+		}
+	}
+	return c.segmentController.writeSegments(lines[:])
+}
+
+// toPABCDEFG converts a PGFEDCBA pattern to the order of the segments in the
+// registers of the Maxim controllers when not decoded.
+func toPABCDEFG(s byte) byte {
+	v := s & DecimalPoint
+	for bit := range 7 {
+		if s&(1<<bit) != 0 {
+			v |= 0x40 >> bit
+		}
+	}
+	return v
+}
+
+// segmentFont is the PGFEDCBA pattern of the ASCII characters that can be
+// represented on a seven-segment display. Letters that only exist in one case
+// are used for both.
+var segmentFont = [128]byte{
+	' ': 0x00, '"': 0x22, '\'': 0x02, '-': 0x40, '=': 0x48, '_': 0x08,
+	'0': 0x3f, '1': 0x06, '2': 0x5b, '3': 0x4f, '4': 0x66,
+	'5': 0x6d, '6': 0x7d, '7': 0x07, '8': 0x7f, '9': 0x6f,
+	'A': 0x77, 'C': 0x39, 'E': 0x79, 'F': 0x71, 'G': 0x3d, 'H': 0x76,
+	'I': 0x06, 'J': 0x1e, 'L': 0x38, 'O': 0x3f, 'P': 0x73, 'S': 0x6d,
+	'U': 0x3e, 'Y': 0x6e,
+	'b': 0x7c, 'c': 0x58, 'd': 0x5e, 'h': 0x74, 'i': 0x04, 'n': 0x54,
+	'o': 0x5c, 'q': 0x67, 'r': 0x50, 't': 0x78, 'u': 0x1c,
+}
+
+// codeBFont is the PGFEDCBA pattern of the Code B values accepted by Write
+// on the MAX7219.
+var codeBFont = [16]byte{
+	0x3f, 0x06, 0x5b, 0x4f, 0x66, 0x6d, 0x7d, 0x07, 0x7f, 0x6f,
+	0x40, 0x79, 0x76, 0x38, 0x73, 0x00,
+}
+
+// convertSegments converts ASCII characters, or Code B values, into segment
+// patterns. A '.' turns on the decimal point of the previous character.
+func convertSegments(bytes []byte) []byte {
+	seg := make([]byte, 0, len(bytes))
+	for ix, c := range bytes {
+		switch {
+		case c == '.' && ix > 0:
+			seg[len(seg)-1] |= DecimalPoint
+		case c&^DecimalPoint < 16:
+			seg = append(seg, codeBFont[c&^DecimalPoint]|c&DecimalPoint)
+		case c < 128:
+			p := segmentFont[c]
+			if p == 0 && c >= 'a' && c <= 'z' {
+				p = segmentFont[c-'a'+'A']
+			} else if p == 0 && c >= 'A' && c <= 'Z' {
+				p = segmentFont[c-'A'+'a']
+			}
+			seg = append(seg, p)
+		default:
+			seg = append(seg, 0)
+		}
+	}
+	return seg
+}
+
+// writeText writes characters left aligned on a controller other than the
+// MAX7219.
+func (d *Dev) writeText(bytes []byte) error {
+	w := make([]byte, d.digits)
+	copy(w, convertSegments(bytes))
+	return d.ctrl.writeSegments(w)
+}
+
+// scrollText is ScrollChars for a controller other than the MAX7219.
+func (d *Dev) scrollText(data []byte, scrollCount int, updateInterval time.Duration) {
+	seg := convertSegments(data)
+	digits := int(d.digits)
+	if len(seg) <= digits {
+		_ = d.writeText(data)
+		time.Sleep(time.Duration(scrollCount*len(seg)) * updateInterval)
+		return
+	}
+	display := make([]byte, 0, 2*len(seg)+1)
+	display = append(display, seg...)
+	display = append(display, 0)
+	display = append(display, seg...)
+	var pos int
+	for shifts := scrollCount * len(seg); shifts > 0; shifts-- {
+		_ = d.ctrl.writeSegments(display[pos : pos+digits])
+		pos++
+		if pos >= len(seg)+1 {
+			pos = 0
+		}
+		time.Sleep(updateInterval)
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package max7219
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"time"
+
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/host/v3/cpu"
+)
+
+// NewTM1638 creates a new seven-segment display driven by a TM1638, like the
+// common "LED&KEY" modules, using three GPIO pins.
+//
+// The 8 digits are wired to the even display addresses, digit 0 being the
+// leftmost one. Write, WriteInt, ScrollChars, Clear, SetIntensity and
+// TestDisplay behave as with a MAX7219 numeric display. Use Keys to scan the
+// keys and SetLEDs to control the discrete LEDs wired to the odd addresses.
+func NewTM1638(stb, clk gpio.PinOut, dio gpio.PinIO) (*Dev, error) {
+	// The bus idles high.
+	for _, p := range []gpio.PinOut{stb, clk, dio} {
+		if err := p.Out(gpio.High); err != nil {
+			return nil, err
+		}
+	}
+	t := &tm1638{stb: stb, clk: clk, dio: dio, brightness: 4}
+	if err := t.flush(); err != nil {
+		return nil, err
+	}
+	return &Dev{variant: TM1638, ctrl: t, decode: DecodeB, units: 1, digits: 8}, nil
+}
+
+// Keys returns the state of the keys scanned by a TM1638, one bit per key
+// pressed.
+//
+// Byte i of the scan data is stored in bits 8*i to 8*i+7. In each byte, bits
+// 0 to 2 are the keys on K3 to K1 of segment 2*i+1 and bits 4 to 6 the keys on
+// K3 to K1 of segment 2*i+2. On "LED&KEY" modules, where the 8 keys are on K3,
+// key n is bit 8*(n%4) + 4*(n/4).
+func (d *Dev) Keys() (uint32, error) {
+	t, ok := d.ctrl.(*tm1638)
+	if !ok {
+		return 0, errTM1638Only
+	}
+	return t.keys()
+}
+
+// SetLEDs turns on the discrete LEDs wired to the odd display addresses of a
+// TM1638, bit n controlling the LED n.
+func (d *Dev) SetLEDs(leds byte) error {
+	t, ok := d.ctrl.(*tm1638)
+	if !ok {
+		return errTM1638Only
+	}
+	return t.setLEDs(leds)
+}
+
+//
+
+// TM1638 commands.
+const (
+	tm1638WriteData byte = 0x40
+	tm1638ReadKeys  byte = 0x42
+	tm1638Address   byte = 0xc0
+	tm1638DisplayOn byte = 0x88
+)
+
+// The maximum clock frequency is 1MHz.
+const tm1638HalfCycle = time.Microsecond
+
+var errTM1638Only = errors.New("max7219: only supported by the TM1638")
+
+type tm1638 struct {
+	mu  sync.Mutex
+	stb gpio.PinOut
+	clk gpio.PinOut
+	dio gpio.PinIO
+	// ram is the display memory. The digits are on the even addresses and
+	// the LEDs on the odd ones.
+	ram        [16]byte
+	brightness byte
+	test       bool
+}
+
+func (t *tm1638) writeSegments(seg []byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i := range 8 {
+		t.ram[2*i] = 0
+		if i < len(seg) {
+			t.ram[2*i] = seg[i]
+		}
+	}
+	return t.flush()
+}
+
+func (t *tm1638) setLEDs(leds byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i := range 8 {
+		t.ram[2*i+1] = leds >> i & 1
+	}
+	return t.flush()
+}
+
+func (t *tm1638) setIntensity(intensity byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	// The TM1638 has 8 levels.
+	t.brightness = (intensity & 0x0f) >> 1
+	return t.flush()
+}
+
+func (t *tm1638) setTest(on bool) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.test = on
+	return t.flush()
+}
+
+// flush writes the display memory and the display control.
+func (t *tm1638) flush() error {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	ram := t.ram
+	brightness := t.brightness
+	if t.test {
+		for i := range ram {
+			ram[i] = 0xff
+		}
+		brightness = 7
+	}
+	if err := t.command(tm1638WriteData); err != nil {
+		return err
+	}
+	if err := t.command(append([]byte{tm1638Address}, ram[:]...)...); err != nil {
+		return err
+	}
+	return t.command(tm1638DisplayOn | brightness)
+}
+
+func (t *tm1638) keys() (uint32, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	if err := t.stb.Out(gpio.Low); err != nil {
+		return 0, err
+	}
+	if err := t.writeByte(tm1638ReadKeys); err != nil {
+		return 0, err
+	}
+	if err := t.dio.In(gpio.PullUp, gpio.NoEdge); err != nil {
+		return 0, err
+	}
+	// Wait at least 1µs before reading.
+	spin(2 * tm1638HalfCycle)
+	var keys uint32
+	for i := range 32 {
+		_ = t.clk.Out(gpio.Low)
+		spin(tm1638HalfCycle)
+		_ = t.clk.Out(gpio.High)
+		if t.dio.Read() == gpio.High {
+			keys |= 1 << i
+		}
+		spin(tm1638HalfCycle)
+	}
+	if err := t.stb.Out(gpio.High); err != nil {
+		return 0, err
+	}
+	return keys, t.dio.Out(gpio.High)
+}
+
+// command sends the bytes with STB asserted.
+func (t *tm1638) command(b ...byte) error {
+	if err := t.stb.Out(gpio.Low); err != nil {
+		return err
+	}
+	for _, v := range b {
+		if err := t.writeByte(v); err != nil {
+			return err
+		}
+	}
+	return t.stb.Out(gpio.High)
+}
+
+// writeByte sends b LSB first, data being latched on the rising edge of CLK.
+func (t *tm1638) writeByte(b byte) error {
+	for i := range 8 {
+		if err := t.clk.Out(gpio.Low); err != nil {
+			return err
+		}
+		if err := t.dio.Out(b&(1<<i) != 0); err != nil {
+			return err
+		}
+		spin(tm1638HalfCycle)
+		if err := t.clk.Out(gpio.High); err != nil {
+			return err
+		}
+		spin(tm1638HalfCycle)
+	}
+	return nil
+}
+
+var spin = cpu.Nanospin
@@ -0,0 +1,151 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package max7219
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpiotest"
+)
+
+// tm1638Bus decodes the commands sent to a simulated TM1638.
+type tm1638Bus struct {
+	stb, clk, dio gpio.Level
+	// cmds holds the bytes sent in each command.
+	cmds [][]byte
+	bits int
+	// keys is the scan data returned when reading.
+	keys    uint32
+	reading bool
+	readBit int
+	out     gpio.Level
+}
+
+type tm1638Pin struct {
+	gpiotest.Pin
+	bus *tm1638Bus
+}
+
+func (p *tm1638Pin) Out(l gpio.Level) error {
+	b := p.bus
+	switch p.N {
+	case "STB":
+		if l == gpio.Low && b.stb == gpio.High {
+			b.cmds = append(b.cmds, nil)
+			b.bits = 0
+		}
+		b.stb = l
+	case "CLK":
+		if l == gpio.High && b.clk == gpio.Low && b.stb == gpio.Low {
+			if b.reading {
+				b.out = b.keys>>b.readBit&1 != 0
+				b.readBit++
+			} else {
+				cmd := &b.cmds[len(b.cmds)-1]
+				if b.bits%8 == 0 {
+					*cmd = append(*cmd, 0)
+				}
+				if b.dio {
+					(*cmd)[len(*cmd)-1] |= 1 << (b.bits % 8)
+				}
+				b.bits++
+			}
+		}
+		b.clk = l
+	case "DIO":
+		b.reading = false
+		b.dio = l
+	}
+	return nil
+}
+
+func (p *tm1638Pin) In(pull gpio.Pull, edge gpio.Edge) error {
+	p.bus.reading = true
+	p.bus.readBit = 0
+	return nil
+}
+
+func (p *tm1638Pin) Read() gpio.Level {
+	return p.bus.out
+}
+
+func newTM1638(t *testing.T) (*Dev, *tm1638Bus) {
+	spin = func(time.Duration) {}
+	bus := &tm1638Bus{}
+	pin := func(name string) *tm1638Pin {
+		return &tm1638Pin{Pin: gpiotest.Pin{N: name}, bus: bus}
+	}
+	dev, err := NewTM1638(pin("STB"), pin("CLK"), pin("DIO"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dev, bus
+}
+
+func tm1638Flush(ram [16]byte, control byte) [][]byte {
+	return [][]byte{{0x40}, append([]byte{0xc0}, ram[:]...), {control}}
+}
+
+func TestTM1638_Write(t *testing.T) {
+	dev, bus := newTM1638(t)
+	if diff := cmp.Diff(tm1638Flush([16]byte{}, 0x8c), bus.cmds); diff != "" {
+		t.Errorf("init (-want +got):\n%s", diff)
+	}
+	if v := dev.Variant(); v != TM1638 {
+		t.Errorf("variant: %s", v)
+	}
+
+	bus.cmds = nil
+	if err := dev.Write([]byte("1.2")); err != nil {
+		t.Fatal(err)
+	}
+	want := tm1638Flush([16]byte{0: 0x86, 2: 0x5b}, 0x8c)
+	if diff := cmp.Diff(want, bus.cmds); diff != "" {
+		t.Errorf("Write (-want +got):\n%s", diff)
+	}
+
+	bus.cmds = nil
+	if err := dev.SetLEDs(0x81); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.SetIntensity(15); err != nil {
+		t.Fatal(err)
+	}
+	want = tm1638Flush([16]byte{0: 0x86, 1: 1, 2: 0x5b, 15: 1}, 0x8f)
+	if diff := cmp.Diff(want, bus.cmds[len(bus.cmds)-3:]); diff != "" {
+		t.Errorf("SetLEDs (-want +got):\n%s", diff)
+	}
+
+	bus.cmds = nil
+	if err := dev.TestDisplay(true); err != nil {
+		t.Fatal(err)
+	}
+	var full [16]byte
+	for i := range full {
+		full[i] = 0xff
+	}
+	if diff := cmp.Diff(tm1638Flush(full, 0x8f), bus.cmds); diff != "" {
+		t.Errorf("TestDisplay (-want +got):\n%s", diff)
+	}
+}
+
+func TestTM1638_Keys(t *testing.T) {
+	dev, bus := newTM1638(t)
+	bus.cmds = nil
+	bus.keys = 0x10000001
+	keys, err := dev.Keys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keys != bus.keys {
+		t.Errorf("keys: %#x, want %#x", keys, bus.keys)
+	}
+	if diff := cmp.Diff([][]byte{{0x42}}, bus.cmds); diff != "" {
+		t.Errorf("Keys (-want +got):\n%s", diff)
+	}
+}
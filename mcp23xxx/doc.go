@@ -4,6 +4,19 @@
 
 // Package mcp23xxx provides driver for the MCP23 family of IO extenders
 //
+// # Interrupts
+//
+// Except for the MCP23016, the pins support interrupt-on-change. Request edges
+// with In, connect the INT output of the device to a host pin and start the
+// dispatcher with Dev.ListenInterrupts. The pins WaitForEdge then return on
+// the requested edges, and callbacks set with
+// InterruptPin.SetInterruptCallback are called.
+//
+// The pins implement gpio.PinIO, so they can be passed to drivers expecting
+// an edge capable input, e.g. the busy pin of an e-paper display. In with
+// gpio.PullUp enables the internal pull-up of the pin in the GPPU register,
+// except on the MCP23016 which has none. Pull-downs are not supported.
+//
 // # Datasheet
 //
 // https://ww1.microchip.com/downloads/en/DeviceDoc/20001952C.pdf
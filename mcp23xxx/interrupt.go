@@ -0,0 +1,274 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package mcp23xxx
+
+import (
+	"errors"
+	"time"
+
+	"periph.io/x/conn/v3/gpio"
+)
+
+// InterruptPin extends Pin with the interrupt-on-change features. All pins
+// implement it, the methods fail on devices without interrupt support.
+type InterruptPin interface {
+	Pin
+	// SetInterruptCallback sets a function called from the interrupt
+	// dispatcher with the captured level when an edge requested with In is
+	// detected. Use nil to remove the callback.
+	//
+	// The callback must not call StopInterrupts: it waits for the
+	// dispatcher to return and would deadlock.
+	SetInterruptCallback(f func(l gpio.Level))
+	// SetInterruptCompare makes the pin interrupt while its level differs
+	// from defval instead of on every change. Use false to revert to
+	// interrupt-on-change.
+	SetInterruptCompare(enable bool, defval gpio.Level) error
+}
+
+// InterruptOpts is the configuration of the INT output pins, set in the IOCON
+// register.
+type InterruptOpts struct {
+	// Mirror connects INTA and INTB together so a single pin can be used for
+	// both ports. Only on 16 bit devices.
+	Mirror bool
+	// OpenDrain configures the INT pins as open drain, which allows wiring
+	// several devices on the same host pin. It overrides ActiveHigh.
+	OpenDrain bool
+	// ActiveHigh sets the polarity of the INT pins. They are active low by
+	// default.
+	ActiveHigh bool
+}
+
+// ConfigureInterrupt sets the INT pins configuration.
+func (d *Dev) ConfigureInterrupt(o InterruptOpts) error {
+	if !d.ports[0].supportInterrupt {
+		return errInterruptNotSupported
+	}
+	v, err := d.iocon.readValue(true)
+	if err != nil {
+		return err
+	}
+	v &^= ioconMirror | ioconODR | ioconINTPOL
+	if o.Mirror {
+		v |= ioconMirror
+	}
+	if o.OpenDrain {
+		v |= ioconODR
+	} else if o.ActiveHigh {
+		v |= ioconINTPOL
+	}
+	if err := d.iocon.writeValue(v, true); err != nil {
+		return err
+	}
+	d.activeHigh = !o.OpenDrain && o.ActiveHigh
+	return nil
+}
+
+// ListenInterrupts starts the interrupt dispatcher.
+//
+// intPins are the host pins connected to the INT outputs of the device. When
+// one of them is asserted, the interrupt flags of all the ports are read and
+// the pins callbacks are called and their WaitForEdge return. Using a single
+// pin requires InterruptOpts.Mirror on 16 bit devices.
+//
+// The pull resistor of the host pins is not changed and must be set by the
+// caller if needed, e.g. when the INT pins are open drain.
+func (d *Dev) ListenInterrupts(intPins ...gpio.PinIn) error {
+	if !d.ports[0].supportInterrupt {
+		return errInterruptNotSupported
+	}
+	if len(intPins) == 0 {
+		return errors.New("MCP23xxx: at least one INT pin is required")
+	}
+	d.intMu.Lock()
+	defer d.intMu.Unlock()
+	if d.stop != nil {
+		return errors.New("MCP23xxx: interrupts are already being listened to")
+	}
+	edge, active := gpio.FallingEdge, gpio.Low
+	if d.activeHigh {
+		edge, active = gpio.RisingEdge, gpio.High
+	}
+	for _, ip := range intPins {
+		if err := ip.In(gpio.PullNoChange, edge); err != nil {
+			return err
+		}
+	}
+	d.stop = make(chan struct{})
+	for _, ip := range intPins {
+		d.wg.Add(1)
+		go d.listen(ip, active, d.stop)
+	}
+	return nil
+}
+
+// StopInterrupts stops the interrupt dispatcher, if running.
+//
+// It must not be called from an interrupt callback.
+func (d *Dev) StopInterrupts() {
+	d.intMu.Lock()
+	stop := d.stop
+	d.stop = nil
+	d.intMu.Unlock()
+	if stop != nil {
+		close(stop)
+		d.wg.Wait()
+	}
+}
+
+//
+
+const (
+	ioconMirror = 1 << 6
+	ioconHAEN   = 1 << 3
+	ioconODR    = 1 << 2
+	ioconINTPOL = 1 << 1
+)
+
+// interruptPoll is how often the dispatcher checks if it must stop.
+var interruptPoll = 100 * time.Millisecond
+
+var errInterruptNotSupported = errors.New("MCP23xxx: interrupts are not supported by this device")
+
+func (d *Dev) listen(ip gpio.PinIn, active gpio.Level, stop <-chan struct{}) {
+	defer d.wg.Done()
+	for {
+		select {
+		case <-stop:
+			return
+		default:
+		}
+		if !ip.WaitForEdge(interruptPoll) {
+			continue
+		}
+		// Keep servicing while the line is asserted, since new interrupts
+		// arriving in the meantime don't generate an edge.
+		for {
+			if !d.serviceInterrupts() || ip.Read() != active {
+				break
+			}
+		}
+	}
+}
+
+// serviceInterrupts reads the interrupt flags and captured levels of all the
+// ports and dispatches them. It returns true if any flag was set.
+//
+// The callbacks are called once intMu is released, so they can use the
+// device.
+func (d *Dev) serviceInterrupts() bool {
+	var events []interruptEvent
+	found := false
+	d.intMu.Lock()
+	for i := range d.ports {
+		p := &d.ports[i]
+		flags, err := p.intf.readValue(false)
+		if err != nil || flags == 0 {
+			continue
+		}
+		// Reading INTCAP clears the interrupt.
+		levels, err := p.intcap.readValue(false)
+		if err != nil {
+			continue
+		}
+		found = true
+		events = p.dispatch(flags, levels, events)
+	}
+	d.intMu.Unlock()
+	for _, e := range events {
+		e.f(e.l)
+	}
+	return found
+}
+
+// interruptEvent is a pin callback to call with the captured level.
+type interruptEvent struct {
+	f func(l gpio.Level)
+	l gpio.Level
+}
+
+// dispatch notifies the pins flagged in flags waiting for an edge and appends
+// the callbacks to call to events.
+func (p *port) dispatch(flags, levels uint8, events []interruptEvent) []interruptEvent {
+	for bit := uint8(0); bit < 8; bit++ {
+		if flags&(1<<bit) == 0 {
+			continue
+		}
+		l := gpio.Level(levels&(1<<bit) != 0)
+		p.mu.Lock()
+		edge, f, c := p.edges[bit], p.callbacks[bit], p.events[bit]
+		p.mu.Unlock()
+		if (edge == gpio.RisingEdge && l == gpio.Low) || (edge == gpio.FallingEdge && l == gpio.High) {
+			continue
+		}
+		if c != nil {
+			select {
+			case c <- l:
+			default:
+			}
+		}
+		if f != nil {
+			events = append(events, interruptEvent{f: f, l: l})
+		}
+	}
+	return events
+}
+
+// setEdge enables or disables the interrupt of the pin.
+//
+// A pending WaitForEdge is stopped.
+func (p *portpin) setEdge(edge gpio.Edge) error {
+	p.port.mu.Lock()
+	prev := p.port.edges[p.pinbit]
+	if c := p.port.cancel[p.pinbit]; c != nil {
+		close(c)
+		p.port.cancel[p.pinbit] = nil
+	}
+	p.port.mu.Unlock()
+	if edge == gpio.NoEdge {
+		if prev == gpio.NoEdge {
+			return nil
+		}
+		if err := p.port.gpinten.getAndSetBit(p.pinbit, false, true); err != nil {
+			return err
+		}
+		p.port.mu.Lock()
+		p.port.edges[p.pinbit] = gpio.NoEdge
+		p.port.events[p.pinbit] = nil
+		p.port.mu.Unlock()
+		return nil
+	}
+	if !p.port.supportInterrupt {
+		return errInterruptNotSupported
+	}
+	p.port.mu.Lock()
+	p.port.edges[p.pinbit] = edge
+	// Flush any pending edge.
+	p.port.events[p.pinbit] = make(chan gpio.Level, 1)
+	p.port.cancel[p.pinbit] = make(chan struct{})
+	p.port.mu.Unlock()
+	return p.port.gpinten.getAndSetBit(p.pinbit, true, true)
+}
+
+var _ InterruptPin = &portpin{}
+
+func (p *portpin) SetInterruptCallback(f func(l gpio.Level)) {
+	p.port.mu.Lock()
+	defer p.port.mu.Unlock()
+	p.port.callbacks[p.pinbit] = f
+}
+
+func (p *portpin) SetInterruptCompare(enable bool, defval gpio.Level) error {
+	if !p.port.supportInterrupt {
+		return errInterruptNotSupported
+	}
+	if enable {
+		if err := p.port.defval.getAndSetBit(p.pinbit, bool(defval), true); err != nil {
+			return err
+		}
+	}
+	return p.port.intcon.getAndSetBit(p.pinbit, enable, true)
+}
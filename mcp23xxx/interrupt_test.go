@@ -0,0 +1,176 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package mcp23xxx
+
+import (
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpiotest"
+	"periph.io/x/conn/v3/i2c/i2ctest"
+)
+
+func TestMCP23017_interrupt(t *testing.T) {
+	const address uint16 = 0x20
+	scenario := &i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// iodir is read on creation
+			{Addr: address, W: []byte{0x00}, R: []byte{0xFF}},
+			{Addr: address, W: []byte{0x01}, R: []byte{0xFF}},
+			// IOCON mirror is set
+			{Addr: address, W: []byte{0x0A}, R: []byte{0x00}},
+			{Addr: address, W: []byte{0x0A, 0x40}},
+			// PORTB_3: pull-up, then interrupt enabled
+			{Addr: address, W: []byte{0x0D}, R: []byte{0x00}},
+			{Addr: address, W: []byte{0x0D, 0x08}},
+			{Addr: address, W: []byte{0x05}, R: []byte{0x00}},
+			{Addr: address, W: []byte{0x05, 0x08}},
+			// a rising edge is filtered out
+			{Addr: address, W: []byte{0x0E}, R: []byte{0x00}},
+			{Addr: address, W: []byte{0x0F}, R: []byte{0x08}},
+			{Addr: address, W: []byte{0x11}, R: []byte{0x08}},
+			// a falling edge is dispatched
+			{Addr: address, W: []byte{0x0E}, R: []byte{0x00}},
+			{Addr: address, W: []byte{0x0F}, R: []byte{0x08}},
+			{Addr: address, W: []byte{0x11}, R: []byte{0x00}},
+		},
+	}
+	dev, err := NewI2C(scenario, MCP23017, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dev.Close()
+
+	if err := dev.ConfigureInterrupt(InterruptOpts{Mirror: true}); err != nil {
+		t.Fatal(err)
+	}
+	intPin := &releasedPin{gpiotest.Pin{N: "INT", L: gpio.High, EdgesChan: make(chan gpio.Level, 1)}}
+	p := dev.Pins[1][3].(InterruptPin)
+	if err := p.In(gpio.PullUp, gpio.FallingEdge); err != nil {
+		t.Fatal(err)
+	}
+	levels := make(chan gpio.Level, 2)
+	held := make(chan bool, 2)
+	p.SetInterruptCallback(func(l gpio.Level) {
+		// The dispatcher lock must be released while calling back.
+		free := dev.intMu.TryLock()
+		if free {
+			dev.intMu.Unlock()
+		}
+		held <- !free
+		levels <- l
+	})
+	if err := dev.ListenInterrupts(intPin); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.ListenInterrupts(intPin); err == nil {
+		t.Fatal("second ListenInterrupts should fail")
+	}
+
+	intPin.EdgesChan <- gpio.Low
+	if p.WaitForEdge(50 * time.Millisecond) {
+		t.Fatal("rising edge must be filtered out")
+	}
+	intPin.EdgesChan <- gpio.Low
+	if !p.WaitForEdge(time.Second) {
+		t.Fatal("expected falling edge")
+	}
+	if l := <-levels; l != gpio.Low {
+		t.Fatalf("callback got %s", l)
+	}
+	if <-held {
+		t.Fatal("callback called with the dispatcher lock held")
+	}
+
+	dev.StopInterrupts()
+	if err := scenario.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// releasedPin is an INT pin that is released as soon as the device is
+// serviced, since reading INTCAP clears the interrupt.
+type releasedPin struct {
+	gpiotest.Pin
+}
+
+func (p *releasedPin) Read() gpio.Level {
+	return gpio.High
+}
+
+func TestMCP23016_interruptNotSupported(t *testing.T) {
+	const address uint16 = 0x20
+	scenario := &i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// iodir is read on creation
+			{Addr: address, W: []byte{0x06}, R: []byte{0xFF}},
+			{Addr: address, W: []byte{0x07}, R: []byte{0xFF}},
+		},
+	}
+	dev, err := NewI2C(scenario, MCP23016, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dev.Close()
+
+	if err := dev.Pins[0][0].In(gpio.Float, gpio.BothEdges); err == nil {
+		t.Fatal("edge detection must fail")
+	}
+	if err := dev.ListenInterrupts(&gpiotest.Pin{}); err == nil {
+		t.Fatal("ListenInterrupts must fail")
+	}
+}
+
+func TestMCP23008_waitForEdgeCanceled(t *testing.T) {
+	const address uint16 = 0x20
+	scenario := &i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// iodir is read on creation
+			{Addr: address, W: []byte{0x00}, R: []byte{0xFF}},
+			// GP0: pull-up, then interrupt enabled
+			{Addr: address, W: []byte{0x06}, R: []byte{0x00}},
+			{Addr: address, W: []byte{0x06, 0x01}},
+			{Addr: address, W: []byte{0x02}, R: []byte{0x00}},
+			{Addr: address, W: []byte{0x02, 0x01}},
+			// GP0: pull-up and interrupt disabled
+			{Addr: address, W: []byte{0x06, 0x00}},
+			{Addr: address, W: []byte{0x02, 0x00}},
+		},
+	}
+	dev, err := NewI2C(scenario, MCP23008, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dev.Close()
+
+	var p gpio.PinIn = dev.Pins[0][0]
+	if err := p.In(gpio.PullUp, gpio.BothEdges); err != nil {
+		t.Fatal(err)
+	}
+	if pull := p.Pull(); pull != gpio.PullUp {
+		t.Fatalf("got %s", pull)
+	}
+	done := make(chan bool)
+	go func() {
+		done <- p.WaitForEdge(-1)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	if err := p.In(gpio.Float, gpio.NoEdge); err != nil {
+		t.Fatal(err)
+	}
+	if <-done {
+		t.Fatal("WaitForEdge must return false when In is called")
+	}
+	if pull := p.Pull(); pull != gpio.Float {
+		t.Fatalf("got %s", pull)
+	}
+	if p.WaitForEdge(-1) {
+		t.Fatal("WaitForEdge must return false without edge detection")
+	}
+	if err := scenario.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -77,6 +77,53 @@ func TestMCP23S17_out(t *testing.T) {
 	pA0.Out(gpio.High)
 }
 
+func TestMCP23S17_hardwareAddress(t *testing.T) {
+	scenario := &spitest.Playback{
+		Playback: conntest.Playback{
+			Ops: []conntest.IO{
+				// HAEN is set on all devices
+				{W: []byte{0x40, 0x0A, 0x08}, R: nil},
+				// iodir is read from address 5
+				{W: []byte{0x4B, 0x00}, R: []byte{0xFF}},
+				{W: []byte{0x4B, 0x01}, R: []byte{0xFF}},
+				// iodira is set to output
+				{W: []byte{0x4A, 0x00, 0xFE}, R: nil},
+				// olata is read
+				{W: []byte{0x4B, 0x14}, R: []byte{0x00}},
+				// writing high output
+				{W: []byte{0x4A, 0x14, 0x01}, R: nil},
+			},
+		},
+	}
+
+	conn, err := scenario.Connect(1, spi.Mode0, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewSPIAddr(conn, MCP23S17, 8); err == nil {
+		t.Fatal("address 8 must be rejected")
+	}
+	if _, err := NewSPIAddr(conn, MCP23S18, 0); err == nil {
+		t.Fatal("MCP23S18 has no address pins")
+	}
+	dev, err := NewSPIAddr(conn, MCP23S17, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dev.Close()
+
+	pA0 := gpioreg.ByName("MCP23S17_5_PORTA_0")
+	if pA0 == nil {
+		t.Fatal("pin is not registered")
+	}
+	if err := pA0.Out(gpio.High); err != nil {
+		t.Fatal(err)
+	}
+	if err := scenario.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestMCP23017_in(t *testing.T) {
 	const address uint16 = 0x20
 	scenario := &i2ctest.Playback{
@@ -7,6 +7,7 @@ package mcp23xxx
 import (
 	"fmt"
 	"strconv"
+	"sync"
 
 	"periph.io/x/conn/v3/gpio/gpioreg"
 	"periph.io/x/conn/v3/i2c"
@@ -17,6 +18,16 @@ import (
 type Dev struct {
 	// Pins provide access to extender pins.
 	Pins [][]Pin
+
+	ports []port
+	// iocon is the configuration register, not present on MCP23016.
+	iocon      registerCache
+	activeHigh bool
+
+	// interrupt dispatcher state
+	intMu sync.Mutex
+	stop  chan struct{}
+	wg    sync.WaitGroup
 }
 
 // Variant is the type denoting a specific variant of the family.
@@ -63,7 +74,7 @@ func NewI2C(b i2c.Bus, variant Variant, addr uint16) (*Dev, error) {
 	return makeDev(ra, variant, devicename)
 }
 
-// NewSPI initializes an IO extender through I2C connection.
+// NewSPI initializes an IO extender through SPI connection.
 func NewSPI(b spi.Conn, variant Variant) (*Dev, error) {
 	devicename := string(variant)
 	ra := &spiRegisterAccess{
@@ -72,8 +83,44 @@ func NewSPI(b spi.Conn, variant Variant) (*Dev, error) {
 	return makeDev(ra, variant, devicename)
 }
 
-// Close removes any registration to the device.
+// NewSPIAddr initializes an IO extender sharing its chip select with other
+// extenders, through SPI connection.
+//
+// addr is the hardware address set with the address pins: 0 - 7 for
+// MCP23S17, 0 - 3 for MCP23S08. The hardware address enable bit (HAEN) is
+// set on all the devices sharing the chip select, since they all respond to
+// address 0 until it is set. This also resets the other bits of the IOCON
+// register.
+func NewSPIAddr(b spi.Conn, variant Variant, addr uint8) (*Dev, error) {
+	var max uint8
+	var iocon uint8
+	switch variant {
+	case MCP23S08:
+		max, iocon = 3, 0x05
+	case MCP23S17:
+		max, iocon = 7, 0x0A
+	default:
+		return nil, fmt.Errorf("%s: Hardware addressing is not supported", variant)
+	}
+	if addr > max {
+		return nil, fmt.Errorf("%s: Supported address range is 0 - %d", variant, max)
+	}
+	broadcast := &spiRegisterAccess{Conn: b}
+	if err := broadcast.writeRegister(iocon, ioconHAEN); err != nil {
+		return nil, err
+	}
+	devicename := string(variant) + "_" + strconv.Itoa(int(addr))
+	ra := &spiRegisterAccess{
+		Conn:   b,
+		hwAddr: addr,
+	}
+	return makeDev(ra, variant, devicename)
+}
+
+// Close stops the interrupt dispatcher and removes any registration to the
+// device.
 func (d *Dev) Close() error {
+	d.StopInterrupts()
 	for _, port := range d.Pins {
 		for _, pin := range port {
 			err := gpioreg.Unregister(pin.Name())
@@ -87,13 +134,16 @@ func (d *Dev) Close() error {
 
 func makeDev(ra registerAccess, variant Variant, devicename string) (*Dev, error) {
 	var ports []port
+	var iocon registerCache
 	switch variant {
 	case MCP23008, MCP23009, MCP23S08, MCP23S09:
 		ports = mcp23x089port(devicename, ra)
+		iocon = ra.define(0x05)
 	case MCP23016:
 		ports = mcp23x16ports(devicename, ra)
 	case MCP23017, MCP23S17, MCP23018, MCP23S18:
 		ports = mcp23x178ports(devicename, ra)
+		iocon = ra.define(0x0A)
 	default:
 		return nil, fmt.Errorf("%s: Unsupported variant", devicename)
 	}
@@ -112,7 +162,9 @@ func makeDev(ra registerAccess, variant Variant, devicename string) (*Dev, error
 		}
 	}
 	return &Dev{
-		Pins: pins,
+		Pins:  pins,
+		ports: ports,
+		iocon: iocon,
 	}, nil
 }
 
@@ -133,6 +185,7 @@ func mcp23x178ports(devicename string, ra registerAccess) []port {
 
 		// interrupt handling registers
 		gpinten:          ra.define(0x04),
+		defval:           ra.define(0x06),
 		intcon:           ra.define(0x08),
 		intf:             ra.define(0x0E),
 		intcap:           ra.define(0x10),
@@ -153,6 +206,7 @@ func mcp23x178ports(devicename string, ra registerAccess) []port {
 
 		// interrupt handling registers
 		gpinten:          ra.define(0x05),
+		defval:           ra.define(0x07),
 		intcon:           ra.define(0x09),
 		intf:             ra.define(0x0F),
 		intcap:           ra.define(0x11),
@@ -177,6 +231,7 @@ func mcp23x089port(devicename string, ra registerAccess) []port {
 
 		// interrupt handling registers
 		gpinten:          ra.define(0x02),
+		defval:           ra.define(0x03),
 		intcon:           ra.define(0x04),
 		intf:             ra.define(0x07),
 		intcap:           ra.define(0x08),
@@ -7,6 +7,7 @@ package mcp23xxx
 import (
 	"errors"
 	"strconv"
+	"sync"
 	"time"
 
 	"periph.io/x/conn/v3/gpio"
@@ -42,9 +43,18 @@ type port struct {
 	// interrupt handling registers
 	supportInterrupt bool
 	gpinten          registerCache
+	defval           registerCache
 	intcon           registerCache
 	intf             registerCache
 	intcap           registerCache
+
+	// interrupt dispatch state, per pin
+	mu        sync.Mutex
+	edges     [8]gpio.Edge
+	callbacks [8]func(gpio.Level)
+	events    [8]chan gpio.Level
+	// cancel is closed when In is called, to stop pending WaitForEdge.
+	cancel [8]chan struct{}
 }
 
 type portpin struct {
@@ -114,9 +124,7 @@ func (p *portpin) In(pull gpio.Pull, edge gpio.Edge) error {
 			}
 		}
 	}
-	// check edge detection
-	// TODO interrupt support
-	return nil
+	return p.setEdge(edge)
 }
 
 func (p *portpin) Read() gpio.Level {
@@ -127,11 +135,36 @@ func (p *portpin) Read() gpio.Level {
 	return gpio.Low
 }
 
+// WaitForEdge waits for an edge requested with In.
+//
+// Edges are only reported while the interrupt dispatcher started with
+// Dev.ListenInterrupts is running. It returns false if In or Halt is called
+// while waiting.
 func (p *portpin) WaitForEdge(timeout time.Duration) bool {
-	// TODO interrupt handling
-	return false
+	p.port.mu.Lock()
+	c, cancel := p.port.events[p.pinbit], p.port.cancel[p.pinbit]
+	p.port.mu.Unlock()
+	if c == nil {
+		return false
+	}
+	var expired <-chan time.Time
+	if timeout >= 0 {
+		t := time.NewTimer(timeout)
+		defer t.Stop()
+		expired = t.C
+	}
+	select {
+	case <-c:
+		return true
+	case <-cancel:
+		return false
+	case <-expired:
+		return false
+	}
 }
 
+// Pull returns the state of the internal pull-up, read from the GPPU
+// register.
 func (p *portpin) Pull() gpio.Pull {
 	if !p.port.supportPullup {
 		return gpio.Float
@@ -195,3 +228,5 @@ func (p *portpin) IsPolarityInverted() (bool, error) {
 }
 
 var supportedFuncs = [...]pin.Func{gpio.IN, gpio.OUT}
+
+var _ gpio.PinIO = &portpin{}
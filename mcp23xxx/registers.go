@@ -35,16 +35,19 @@ func (ra *i2cRegisterAccess) define(address uint8) registerCache {
 
 type spiRegisterAccess struct {
 	spi.Conn
+	// hwAddr is the hardware address set by the address pins, only used when
+	// HAEN is set.
+	hwAddr uint8
 }
 
 func (ra *spiRegisterAccess) readRegister(address uint8) (uint8, error) {
 	r := make([]byte, 1)
-	err := ra.Tx([]byte{0x41, address}, r)
+	err := ra.Tx([]byte{0x41 | ra.hwAddr<<1, address}, r)
 	return r[0], err
 }
 
 func (ra *spiRegisterAccess) writeRegister(address uint8, value uint8) error {
-	return ra.Tx([]byte{0x40, address, value}, nil)
+	return ra.Tx([]byte{0x40 | ra.hwAddr<<1, address, value}, nil)
 }
 
 func (ra *spiRegisterAccess) define(address uint8) registerCache {
@@ -0,0 +1,19 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package mcp3xxx controls the Microchip MCP3004, MCP3008, MCP3204 and MCP3208
+// Analog-Digital Converters (ADC) via SPI interface.
+//
+// The MCP300x are 10 bits and the MCP320x 12 bits ADCs, with 4 or 8
+// channels. They are commonly used to read analog sensors on a Raspberry Pi,
+// which has no ADC. Each channel can be read single-ended or as a
+// pseudo-differential pair, through the analog.PinADC returned by
+// PinForChannel.
+//
+// # Datasheet
+//
+// MCP3004/MCP3008: https://ww1.microchip.com/downloads/en/DeviceDoc/21295d.pdf
+//
+// MCP3204/MCP3208: https://ww1.microchip.com/downloads/en/DeviceDoc/21298e.pdf
+package mcp3xxx
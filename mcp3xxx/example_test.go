@@ -0,0 +1,46 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package mcp3xxx_test
+
+import (
+	"fmt"
+	"log"
+
+	"periph.io/x/conn/v3/spi/spireg"
+	"periph.io/x/devices/v3/mcp3xxx"
+	"periph.io/x/host/v3"
+)
+
+func Example() {
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Open default SPI port.
+	p, err := spireg.Open("")
+	if err != nil {
+		log.Fatalf("failed to open SPI: %v", err)
+	}
+	defer p.Close()
+
+	// Create a new MCP3008 ADC.
+	adc, err := mcp3xxx.NewSPI(p, mcp3xxx.MCP3008, &mcp3xxx.DefaultOpts)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	// Obtain an analog pin from the ADC.
+	pin, err := adc.PinForChannel(mcp3xxx.Channel0)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	reading, err := pin.Read()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Println(reading)
+}
@@ -0,0 +1,258 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package mcp3xxx
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/analog"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/pin"
+	"periph.io/x/conn/v3/spi"
+)
+
+// Variant is the model of the ADC.
+type Variant int
+
+// Supported models.
+const (
+	MCP3004 Variant = iota
+	MCP3008
+	MCP3204
+	MCP3208
+)
+
+func (v Variant) String() string {
+	switch v {
+	case MCP3004:
+		return "MCP3004"
+	case MCP3008:
+		return "MCP3008"
+	case MCP3204:
+		return "MCP3204"
+	case MCP3208:
+		return "MCP3208"
+	default:
+		return fmt.Sprintf("Variant(%d)", int(v))
+	}
+}
+
+// Channel is the analog reading to do. It can be either an absolute reading
+// or a pseudo-differential reading between two pins.
+type Channel int
+
+// Value channels.
+//
+// The MCP3004 and MCP3204 only support the channels 0 to 3.
+const (
+	// Absolute reading.
+	Channel0 Channel = 8
+	Channel1 Channel = 9
+	Channel2 Channel = 10
+	Channel3 Channel = 11
+	Channel4 Channel = 12
+	Channel5 Channel = 13
+	Channel6 Channel = 14
+	Channel7 Channel = 15
+
+	// Pseudo-differential reading. A negative difference reads as 0.
+	Channel0Minus1 Channel = 0
+	Channel1Minus0 Channel = 1
+	Channel2Minus3 Channel = 2
+	Channel3Minus2 Channel = 3
+	Channel4Minus5 Channel = 4
+	Channel5Minus4 Channel = 5
+	Channel6Minus7 Channel = 6
+	Channel7Minus6 Channel = 7
+)
+
+func (c Channel) String() string {
+	if c < 0 || c > Channel7 {
+		return "Invalid"
+	}
+	if c >= Channel0 {
+		return fmt.Sprintf("%d", c-Channel0)
+	}
+	return fmt.Sprintf("%d-%d", c, c^1)
+}
+
+// Opts holds the configuration options.
+type Opts struct {
+	// Vref is the voltage applied to the VREF pin, which is the full scale
+	// of the conversions.
+	Vref physic.ElectricPotential
+	// Speed is the SPI clock frequency. The maximum depends on the supply
+	// voltage: 1.35MHz at 2.7V and 3.6MHz at 5V for the MCP300x, 1MHz at
+	// 2.7V and 2MHz at 5V for the MCP320x.
+	Speed physic.Frequency
+}
+
+// DefaultOpts are the recommended default options, for a device powered
+// with 3.3V and VREF tied to VDD.
+var DefaultOpts = Opts{
+	Vref:  3300 * physic.MilliVolt,
+	Speed: physic.MegaHertz,
+}
+
+// Dev is a handle to a MCP3004/MCP3008/MCP3204/MCP3208 ADC.
+type Dev struct {
+	c       spi.Conn
+	variant Variant
+	vref    physic.ElectricPotential
+	mu      sync.Mutex
+}
+
+// NewSPI returns a handle to an ADC of the given variant.
+func NewSPI(p spi.Port, v Variant, opts *Opts) (*Dev, error) {
+	if v < MCP3004 || v > MCP3208 {
+		return nil, errors.New("mcp3xxx: unknown variant")
+	}
+	if opts.Vref <= 0 {
+		return nil, errors.New("mcp3xxx: Vref must be positive")
+	}
+	c, err := p.Connect(opts.Speed, spi.Mode0, 8)
+	if err != nil {
+		return nil, fmt.Errorf("mcp3xxx: %v", err)
+	}
+	return &Dev{c: c, variant: v, vref: opts.Vref}, nil
+}
+
+// String implements conn.Resource.
+func (d *Dev) String() string {
+	return d.variant.String()
+}
+
+// Halt implements conn.Resource.
+//
+// The ADC is in shutdown between conversions, so there is nothing to do.
+func (d *Dev) Halt() error {
+	return nil
+}
+
+// PinForChannel returns an analog pin reading the requested channel.
+func (d *Dev) PinForChannel(c Channel) (analog.PinADC, error) {
+	if c < 0 || c > Channel7 || (d.channels() == 4 && c&4 != 0) {
+		return nil, fmt.Errorf("mcp3xxx: invalid channel %s for %s", c, d.variant)
+	}
+	return &analogPin{adc: d, c: c}, nil
+}
+
+//
+
+func (d *Dev) channels() int {
+	if d.variant == MCP3004 || d.variant == MCP3204 {
+		return 4
+	}
+	return 8
+}
+
+func (d *Dev) bits() uint {
+	if d.variant == MCP3004 || d.variant == MCP3008 {
+		return 10
+	}
+	return 12
+}
+
+// read does a conversion of the channel and returns the raw value.
+//
+// The request is aligned so the result ends the last byte.
+func (d *Dev) read(c Channel) (int32, error) {
+	var w [3]byte
+	if d.bits() == 10 {
+		// Start bit, then SGL/DIFF and D2-D0 in the high nibble.
+		w[0] = 0x01
+		w[1] = byte(c) << 4
+	} else {
+		// Start bit, SGL/DIFF and D2, then D1-D0 in the high bits.
+		w[0] = 0x04 | byte(c)>>2
+		w[1] = byte(c) << 6
+	}
+	var r [3]byte
+	d.mu.Lock()
+	err := d.c.Tx(w[:], r[:])
+	d.mu.Unlock()
+	if err != nil {
+		return 0, fmt.Errorf("mcp3xxx: %v", err)
+	}
+	mask := uint16(1)<<d.bits() - 1
+	return int32((uint16(r[1])<<8 | uint16(r[2])) & mask), nil
+}
+
+func (d *Dev) toSample(raw int32) analog.Sample {
+	return analog.Sample{
+		Raw: raw,
+		V:   physic.ElectricPotential(raw) * d.vref / physic.ElectricPotential(1<<d.bits()),
+	}
+}
+
+type analogPin struct {
+	adc *Dev
+	c   Channel
+}
+
+// Range returns the maximum supported range [min, max] of the values.
+func (p *analogPin) Range() (analog.Sample, analog.Sample) {
+	return p.adc.toSample(0), p.adc.toSample(1<<p.adc.bits() - 1)
+}
+
+// Read returns the current pin level.
+func (p *analogPin) Read() (analog.Sample, error) {
+	raw, err := p.adc.read(p.c)
+	if err != nil {
+		return analog.Sample{}, err
+	}
+	return p.adc.toSample(raw), nil
+}
+
+func (p *analogPin) Name() string {
+	return p.adc.variant.String() + "(" + p.c.String() + ")"
+}
+
+// Number returns the channel number, the differential pairs following the
+// single-ended channels.
+func (p *analogPin) Number() int {
+	if p.c >= Channel0 {
+		return int(p.c - Channel0)
+	}
+	return p.adc.channels() + int(p.c)
+}
+
+func (p *analogPin) Function() string {
+	return string(p.Func())
+}
+
+// Func implements pin.PinFunc.
+func (p *analogPin) Func() pin.Func {
+	return analog.ADC
+}
+
+// SupportedFuncs implements pin.PinFunc.
+func (p *analogPin) SupportedFuncs() []pin.Func {
+	return []pin.Func{analog.ADC}
+}
+
+// SetFunc implements pin.PinFunc.
+func (p *analogPin) SetFunc(f pin.Func) error {
+	if f == analog.ADC {
+		return nil
+	}
+	return errors.New("mcp3xxx: pin function cannot be changed")
+}
+
+func (p *analogPin) Halt() error {
+	return nil
+}
+
+func (p *analogPin) String() string {
+	return p.Name()
+}
+
+var _ conn.Resource = &Dev{}
+var _ analog.PinADC = &analogPin{}
+var _ pin.Pin = &analogPin{}
+var _ pin.PinFunc = &analogPin{}
@@ -0,0 +1,134 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package mcp3xxx
+
+import (
+	"reflect"
+	"testing"
+
+	"periph.io/x/conn/v3/analog"
+	"periph.io/x/conn/v3/conntest"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/pin"
+	"periph.io/x/conn/v3/spi/spitest"
+)
+
+func TestChannel_String(t *testing.T) {
+	data := []struct {
+		c        Channel
+		expected string
+	}{
+		{Channel0, "0"},
+		{Channel7, "7"},
+		{Channel0Minus1, "0-1"},
+		{Channel1Minus0, "1-0"},
+		{Channel6Minus7, "6-7"},
+		{Channel(-1), "Invalid"},
+		{Channel(16), "Invalid"},
+	}
+	for _, line := range data {
+		if actual := line.c.String(); actual != line.expected {
+			t.Fatalf("%s != %s", line.expected, actual)
+		}
+	}
+}
+
+func TestNewSPI(t *testing.T) {
+	if _, err := NewSPI(&spitest.Record{}, Variant(4), &DefaultOpts); err == nil {
+		t.Fatal("expected error on variant")
+	}
+	if _, err := NewSPI(&spitest.Record{}, MCP3008, &Opts{}); err == nil {
+		t.Fatal("expected error on Vref")
+	}
+	d, err := NewSPI(&spitest.Record{}, MCP3004, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := d.String(); s != "MCP3004" {
+		t.Fatal(s)
+	}
+	if _, err := d.PinForChannel(Channel4); err == nil {
+		t.Fatal("expected error on channel")
+	}
+	if _, err := d.PinForChannel(Channel4Minus5); err == nil {
+		t.Fatal("expected error on channel")
+	}
+	if err := d.Halt(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPinADC(t *testing.T) {
+	d, err := NewSPI(&spitest.Record{}, MCP3008, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := d.PinForChannel(Channel2Minus3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := p.String(); v != "MCP3008(2-3)" {
+		t.Fatal(v)
+	}
+	if v := p.Number(); v != 10 {
+		t.Fatal(v)
+	}
+	if v := p.Function(); v != "ADC" {
+		t.Fatal(v)
+	}
+	if v := p.(pin.PinFunc).SupportedFuncs(); !reflect.DeepEqual(v, []pin.Func{analog.ADC}) {
+		t.Fatal(v)
+	}
+	if err := p.(pin.PinFunc).SetFunc(pin.FuncNone); err == nil {
+		t.Fatal("expected failure")
+	}
+	min, max := p.Range()
+	if min.Raw != 0 || max.Raw != 1023 || max.V != 3296777343*physic.NanoVolt {
+		t.Fatal(min, max)
+	}
+	if err := p.Halt(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPinADC_Read(t *testing.T) {
+	data := []struct {
+		v        Variant
+		c        Channel
+		w        []byte
+		r        []byte
+		expected analog.Sample
+	}{
+		{MCP3008, Channel5, []byte{0x01, 0xd0, 0x00}, []byte{0xff, 0xfa, 0x00}, analog.Sample{Raw: 512, V: 1650 * physic.MilliVolt}},
+		{MCP3008, Channel1Minus0, []byte{0x01, 0x10, 0x00}, []byte{0xff, 0xf8, 0x40}, analog.Sample{Raw: 64, V: 206250 * physic.MicroVolt}},
+		{MCP3208, Channel7, []byte{0x07, 0xc0, 0x00}, []byte{0xff, 0xe8, 0x00}, analog.Sample{Raw: 2048, V: 1650 * physic.MilliVolt}},
+		{MCP3204, Channel2Minus3, []byte{0x04, 0x80, 0x00}, []byte{0xff, 0xef, 0xff}, analog.Sample{Raw: 4095, V: 3299194335 * physic.NanoVolt}},
+	}
+	for i, line := range data {
+		port := spitest.Playback{
+			Playback: conntest.Playback{
+				Ops: []conntest.IO{{W: line.w, R: line.r}},
+			},
+		}
+		d, err := NewSPI(&port, line.v, &DefaultOpts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p, err := d.PinForChannel(line.c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s, err := p.Read()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != line.expected {
+			t.Fatalf("#%d: %v != %v", i, s, line.expected)
+		}
+		if err := port.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package mcp472x
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/pin"
+)
+
+// SetI2CAddress programs the I²C address of the device in its EEPROM, so
+// multiple MCP4728 can share a bus.
+//
+// The MCP4728 only accepts the command when its LDAC pin goes low during the
+// 8th clock of the second byte, so ldac must be connected to the LDAC pin of
+// this device only. As this timing can't be met through the I²C driver, the
+// command is bit-banged on the SCL and SDA pins of the bus, which must
+// implement i2c.Pins. The pins are restored to their I²C function afterward.
+// No other transaction must happen on the bus in the meantime.
+//
+// The current address of the device must be the one the Dev was opened with.
+// On success, the Dev uses the new address.
+func (d *Dev) SetI2CAddress(newAddr uint16, ldac gpio.PinOut) error {
+	if newAddr < 0x60 || newAddr > 0x67 {
+		return errors.New("mcp472x: invalid address, must be between 0x60 and 0x67")
+	}
+	p, ok := d.c.Bus.(i2c.Pins)
+	if !ok {
+		return errors.New("mcp472x: the I²C bus doesn't expose its pins")
+	}
+	b := bitbang{scl: p.SCL(), sda: p.SDA()}
+	if b.scl == nil || b.sda == nil || b.scl == gpio.INVALID || b.sda == gpio.INVALID {
+		return errors.New("mcp472x: the I²C bus doesn't expose its pins")
+	}
+	if err := ldac.Out(gpio.High); err != nil {
+		return fmt.Errorf("mcp472x: %v", err)
+	}
+	restore := b.save()
+	err := b.writeAddress(byte(d.c.Addr&7), byte(newAddr&7), ldac)
+	if err2 := restore(); err == nil {
+		err = err2
+	}
+	if err2 := ldac.Out(gpio.High); err == nil && err2 != nil {
+		err = fmt.Errorf("mcp472x: %v", err2)
+	}
+	if err != nil {
+		return err
+	}
+	d.c.Addr = newAddr
+	return d.waitEEPROM()
+}
+
+//
+
+// halfCycle is the half period of the bit-banged clock, for 100kHz.
+const halfCycle = 5 * time.Microsecond
+
+// bitbang is the minimal I²C master needed to program the address.
+//
+// The lines are driven open drain: low as an output, high by releasing them
+// as a pulled up input.
+type bitbang struct {
+	scl gpio.PinIO
+	sda gpio.PinIO
+}
+
+// save returns a function restoring the functions of the pins, when
+// supported.
+func (b *bitbang) save() func() error {
+	var restore []func() error
+	for _, p := range []gpio.PinIO{b.scl, b.sda} {
+		if pf, ok := p.(pin.PinFunc); ok {
+			f := pf.Func()
+			if f == pin.FuncNone {
+				continue
+			}
+			restore = append(restore, func() error { return pf.SetFunc(f) })
+		}
+	}
+	return func() error {
+		for _, r := range restore {
+			if err := r(); err != nil {
+				return fmt.Errorf("mcp472x: failed to restore I²C pins: %v", err)
+			}
+		}
+		return nil
+	}
+}
+
+// writeAddress sends the Write I²C Address command, pulling ldac low on the
+// 8th clock of the second byte.
+func (b *bitbang) writeAddress(cur, next byte, ldac gpio.PinOut) error {
+	if err := b.start(); err != nil {
+		return err
+	}
+	err := b.writeByte(byte(0x60|cur)<<1, nil)
+	if err == nil {
+		err = b.writeByte(0x61|cur<<2, func() error { return ldac.Out(gpio.Low) })
+	}
+	if err == nil {
+		err = b.writeByte(0x62|next<<2, nil)
+	}
+	if err == nil {
+		err = b.writeByte(0x63|next<<2, nil)
+	}
+	if err2 := b.stop(); err == nil {
+		err = err2
+	}
+	return err
+}
+
+func (b *bitbang) set(p gpio.PinIO, l gpio.Level) error {
+	var err error
+	if l {
+		err = p.In(gpio.PullUp, gpio.NoEdge)
+	} else {
+		err = p.Out(gpio.Low)
+	}
+	if err != nil {
+		return fmt.Errorf("mcp472x: %v", err)
+	}
+	doSleep(halfCycle)
+	return nil
+}
+
+func (b *bitbang) start() error {
+	if err := b.set(b.sda, gpio.High); err != nil {
+		return err
+	}
+	if err := b.set(b.scl, gpio.High); err != nil {
+		return err
+	}
+	if err := b.set(b.sda, gpio.Low); err != nil {
+		return err
+	}
+	return b.set(b.scl, gpio.Low)
+}
+
+func (b *bitbang) stop() error {
+	if err := b.set(b.sda, gpio.Low); err != nil {
+		return err
+	}
+	if err := b.set(b.scl, gpio.High); err != nil {
+		return err
+	}
+	return b.set(b.sda, gpio.High)
+}
+
+// writeByte clocks out v MSB first and checks the acknowledge. last is
+// called while the clock of the last bit is high.
+func (b *bitbang) writeByte(v byte, last func() error) error {
+	for i := 7; i >= 0; i-- {
+		if err := b.set(b.sda, v&(1<<uint(i)) != 0); err != nil {
+			return err
+		}
+		if err := b.set(b.scl, gpio.High); err != nil {
+			return err
+		}
+		if i == 0 && last != nil {
+			if err := last(); err != nil {
+				return fmt.Errorf("mcp472x: %v", err)
+			}
+		}
+		if err := b.set(b.scl, gpio.Low); err != nil {
+			return err
+		}
+	}
+	if err := b.set(b.sda, gpio.High); err != nil {
+		return err
+	}
+	if err := b.set(b.scl, gpio.High); err != nil {
+		return err
+	}
+	ack := b.sda.Read() == gpio.Low
+	if err := b.set(b.scl, gpio.Low); err != nil {
+		return err
+	}
+	if !ack {
+		return fmt.Errorf("mcp472x: no acknowledge for %#x", v)
+	}
+	return nil
+}
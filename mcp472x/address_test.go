@@ -0,0 +1,155 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package mcp472x
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpiotest"
+	"periph.io/x/conn/v3/i2c/i2ctest"
+)
+
+// wire decodes the bit-banged I²C transaction and acknowledges every byte.
+type wire struct {
+	started bool
+	sda     gpio.Level
+	scl     gpio.Level
+	bits    []gpio.Level
+	ldacAt  int
+}
+
+type sclPin struct {
+	gpiotest.Pin
+	w *wire
+}
+
+func (p *sclPin) In(gpio.Pull, gpio.Edge) error {
+	if p.w.scl == gpio.Low && p.w.started {
+		p.w.bits = append(p.w.bits, p.w.sda)
+	}
+	p.w.scl = gpio.High
+	return nil
+}
+
+func (p *sclPin) Out(l gpio.Level) error {
+	p.w.scl = l
+	return nil
+}
+
+type sdaPin struct {
+	gpiotest.Pin
+	w *wire
+}
+
+func (p *sdaPin) In(gpio.Pull, gpio.Edge) error {
+	if p.w.scl == gpio.High && p.w.sda == gpio.Low {
+		p.w.started = false
+	}
+	p.w.sda = gpio.High
+	return nil
+}
+
+func (p *sdaPin) Out(l gpio.Level) error {
+	if p.w.scl == gpio.High && p.w.sda == gpio.High && l == gpio.Low {
+		p.w.started = true
+	}
+	p.w.sda = l
+	return nil
+}
+
+func (p *sdaPin) Read() gpio.Level {
+	// Acknowledge on the 9th clock.
+	return gpio.Level(!(len(p.w.bits)%9 == 0 && p.w.scl == gpio.High))
+}
+
+func (w *wire) bytes() []byte {
+	var out []byte
+	for i := 0; i+8 <= len(w.bits); i += 9 {
+		var v byte
+		for _, b := range w.bits[i : i+8] {
+			v <<= 1
+			if b == gpio.High {
+				v |= 1
+			}
+		}
+		out = append(out, v)
+	}
+	return out
+}
+
+type ldacPin struct {
+	gpiotest.Pin
+	w *wire
+}
+
+func (p *ldacPin) Out(l gpio.Level) error {
+	if l == gpio.Low {
+		p.w.ldacAt = len(p.w.bits)
+	}
+	return p.Pin.Out(l)
+}
+
+type pinsBus struct {
+	i2ctest.Playback
+	scl, sda gpio.PinIO
+}
+
+func (b *pinsBus) SCL() gpio.PinIO {
+	return b.scl
+}
+
+func (b *pinsBus) SDA() gpio.PinIO {
+	return b.sda
+}
+
+func TestSetI2CAddress(t *testing.T) {
+	defer func(f func(time.Duration)) { doSleep = f }(doSleep)
+	doSleep = func(time.Duration) {}
+	w := &wire{sda: gpio.High, scl: gpio.High}
+	bus := &pinsBus{
+		Playback: i2ctest.Playback{Ops: []i2ctest.IO{{Addr: 0x65, R: []byte{0xC5}}}},
+		scl:      &sclPin{w: w},
+		sda:      &sdaPin{w: w},
+	}
+	d, err := NewMCP4728(bus, &Opts{Addr: 0x62})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ldac := &ldacPin{w: w}
+	if err := d.SetI2CAddress(0x68, ldac); err == nil {
+		t.Fatal("expected error on address")
+	}
+	if err := d.SetI2CAddress(0x65, ldac); err != nil {
+		t.Fatal(err)
+	}
+	if b := w.bytes(); !reflect.DeepEqual(b, []byte{0xC4, 0x69, 0x76, 0x77}) {
+		t.Fatalf("%#v", b)
+	}
+	if w.started {
+		t.Fatal("missing stop")
+	}
+	if w.ldacAt != 17 || ldac.L != gpio.High {
+		t.Fatal(w.ldacAt, ldac.L)
+	}
+	if d.c.Addr != 0x65 {
+		t.Fatal(d.c.Addr)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSetI2CAddress_NoPins(t *testing.T) {
+	d, err := NewMCP4728(&i2ctest.Playback{}, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetI2CAddress(0x61, &gpiotest.Pin{}); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,27 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package mcp472x controls a Microchip MCP4728 quad 12 bits
+// Digital-Analog Converter (DAC) via I²C interface.
+//
+// Each channel has its own reference, gain and power-down mode, and a
+// non-volatile EEPROM from which the outputs are restored on power up.
+// FastWrite and MultiWrite only update the DAC registers, while SingleWrite
+// and SequentialWrite also program the EEPROM.
+//
+// When an LDAC pin is provided, the outputs are only updated when Update is
+// called, which allows to change all the channels simultaneously. Otherwise
+// the LDAC pin of the device must be tied to ground and each write updates
+// the outputs immediately.
+//
+// Reset and Wake use the I²C General Call and affect all the devices on the
+// bus supporting it.
+//
+// SetI2CAddress changes the address of a device, which is needed to use more
+// than one MCP4728 on the same bus.
+//
+// # Datasheet
+//
+// https://ww1.microchip.com/downloads/en/DeviceDoc/22187E.pdf
+package mcp472x
@@ -0,0 +1,406 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package mcp472x
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/physic"
+)
+
+// Channel is one of the 4 outputs of the MCP4728.
+type Channel uint8
+
+// Channels.
+const (
+	ChannelA Channel = 0
+	ChannelB Channel = 1
+	ChannelC Channel = 2
+	ChannelD Channel = 3
+)
+
+func (c Channel) String() string {
+	if c > ChannelD {
+		return fmt.Sprintf("Channel(%d)", c)
+	}
+	return string('A' + rune(c))
+}
+
+// Reference is the voltage reference of a channel.
+type Reference uint8
+
+// References.
+const (
+	// VDD uses the supply voltage as reference. The gain is ignored.
+	VDD Reference = 0
+	// Internal uses the internal 2.048V reference.
+	Internal Reference = 1
+)
+
+// Gain is the gain of the output amplifier of a channel, when using the
+// internal reference.
+type Gain uint8
+
+// Gains.
+const (
+	Gain1x Gain = 0
+	Gain2x Gain = 1
+)
+
+// PowerDown is the power-down mode of a channel. In power-down, the output
+// is disconnected and pulled down with a resistor.
+type PowerDown uint8
+
+// Power-down modes.
+const (
+	Normal        PowerDown = 0
+	PowerDown1K   PowerDown = 1
+	PowerDown100K PowerDown = 2
+	PowerDown500K PowerDown = 3
+)
+
+func (p PowerDown) String() string {
+	switch p {
+	case Normal:
+		return "Normal"
+	case PowerDown1K:
+		return "PowerDown1K"
+	case PowerDown100K:
+		return "PowerDown100K"
+	case PowerDown500K:
+		return "PowerDown500K"
+	default:
+		return fmt.Sprintf("PowerDown(%d)", p)
+	}
+}
+
+// MaxValue is the maximum value of a channel.
+const MaxValue = 4095
+
+// ChannelConfig is the configuration of a channel.
+type ChannelConfig struct {
+	// Value is the output code, between 0 and MaxValue.
+	Value     uint16
+	Reference Reference
+	Gain      Gain
+	PowerDown PowerDown
+}
+
+// ChannelUpdate is the configuration to write to a channel.
+type ChannelUpdate struct {
+	Channel Channel
+	ChannelConfig
+}
+
+// ChannelState is the state of a channel as read from the device.
+type ChannelState struct {
+	// DAC is the configuration currently used by the output.
+	DAC ChannelConfig
+	// EEPROM is the configuration restored on power up.
+	EEPROM ChannelConfig
+	// Ready is false while an EEPROM write is in progress.
+	Ready bool
+	// POR is true when the device is powered on.
+	POR bool
+}
+
+// Opts holds the configuration options.
+type Opts struct {
+	// Addr is the I²C address, from 0x60 to 0x67. Devices are shipped with
+	// 0x60.
+	Addr uint16
+	// LDAC is the GPIO connected to the LDAC pin. It is optional, see the
+	// package documentation.
+	LDAC gpio.PinOut
+	// VDD is the supply voltage, used to compute the output voltage of the
+	// channels using VDD as reference.
+	VDD physic.ElectricPotential
+}
+
+// DefaultOpts are the recommended default options.
+var DefaultOpts = Opts{
+	Addr: 0x60,
+	VDD:  3300 * physic.MilliVolt,
+}
+
+// Dev is a handle to a MCP4728 DAC.
+type Dev struct {
+	c    i2c.Dev
+	ldac gpio.PinOut
+	vdd  physic.ElectricPotential
+}
+
+// NewMCP4728 returns a handle to a MCP4728.
+func NewMCP4728(b i2c.Bus, opts *Opts) (*Dev, error) {
+	if opts.Addr < 0x60 || opts.Addr > 0x67 {
+		return nil, errors.New("mcp472x: invalid address, must be between 0x60 and 0x67")
+	}
+	d := &Dev{c: i2c.Dev{Bus: b, Addr: opts.Addr}, ldac: opts.LDAC, vdd: opts.VDD}
+	if d.ldac != nil {
+		if err := d.ldac.Out(gpio.High); err != nil {
+			return nil, fmt.Errorf("mcp472x: %v", err)
+		}
+	}
+	return d, nil
+}
+
+func (d *Dev) String() string {
+	return fmt.Sprintf("MCP4728{%s}", &d.c)
+}
+
+// FastWrite writes the value and the power-down mode of all the channels.
+// The reference and the gain are unchanged and the EEPROM is not written.
+func (d *Dev) FastWrite(cfg [4]ChannelConfig) error {
+	var w [8]byte
+	for i, c := range cfg {
+		if c.Value > MaxValue {
+			return errValue
+		}
+		w[2*i] = byte(c.PowerDown&3)<<4 | byte(c.Value>>8)
+		w[2*i+1] = byte(c.Value)
+	}
+	return d.tx(w[:], nil)
+}
+
+// MultiWrite writes the configuration of one or more channels, without
+// writing the EEPROM.
+func (d *Dev) MultiWrite(updates ...ChannelUpdate) error {
+	w := make([]byte, 0, 3*len(updates))
+	for _, u := range updates {
+		if u.Channel > ChannelD {
+			return errChannel
+		}
+		b, err := u.encode()
+		if err != nil {
+			return err
+		}
+		w = append(w, cmdMultiWrite|byte(u.Channel)<<1|d.udac(), b[0], b[1])
+	}
+	return d.tx(w, nil)
+}
+
+// SingleWrite writes the configuration of a channel to its DAC register and
+// to the EEPROM, and waits for the EEPROM write to complete.
+func (d *Dev) SingleWrite(c Channel, cfg ChannelConfig) error {
+	if c > ChannelD {
+		return errChannel
+	}
+	b, err := cfg.encode()
+	if err != nil {
+		return err
+	}
+	if err := d.tx([]byte{cmdSingleWrite | byte(c)<<1 | d.udac(), b[0], b[1]}, nil); err != nil {
+		return err
+	}
+	return d.waitEEPROM()
+}
+
+// SequentialWrite writes the configurations of the channels from start up to
+// ChannelD to their DAC registers and to the EEPROM, and waits for the EEPROM
+// write to complete.
+func (d *Dev) SequentialWrite(start Channel, cfg []ChannelConfig) error {
+	if start > ChannelD || len(cfg) != 4-int(start) {
+		return errors.New("mcp472x: a configuration is needed for each channel from start to D")
+	}
+	w := []byte{cmdSequentialWrite | byte(start)<<1 | d.udac()}
+	for _, c := range cfg {
+		b, err := c.encode()
+		if err != nil {
+			return err
+		}
+		w = append(w, b[0], b[1])
+	}
+	if err := d.tx(w, nil); err != nil {
+		return err
+	}
+	return d.waitEEPROM()
+}
+
+// SetReference sets the reference of all the channels. The EEPROM is not
+// written.
+func (d *Dev) SetReference(r [4]Reference) error {
+	return d.tx([]byte{cmdWriteVref | byte(r[0]&1)<<3 | byte(r[1]&1)<<2 | byte(r[2]&1)<<1 | byte(r[3]&1)}, nil)
+}
+
+// SetGain sets the gain of all the channels. The EEPROM is not written.
+func (d *Dev) SetGain(g [4]Gain) error {
+	return d.tx([]byte{cmdWriteGain | byte(g[0]&1)<<3 | byte(g[1]&1)<<2 | byte(g[2]&1)<<1 | byte(g[3]&1)}, nil)
+}
+
+// SetPowerDown sets the power-down mode of all the channels. The EEPROM is
+// not written.
+func (d *Dev) SetPowerDown(p [4]PowerDown) error {
+	return d.tx([]byte{
+		cmdWritePowerDown | byte(p[0]&3)<<2 | byte(p[1]&3),
+		byte(p[2]&3)<<6 | byte(p[3]&3)<<4,
+	}, nil)
+}
+
+// ReadChannels returns the DAC and EEPROM configuration of all the
+// channels.
+func (d *Dev) ReadChannels() ([4]ChannelState, error) {
+	var s [4]ChannelState
+	var r [24]byte
+	if err := d.tx(nil, r[:]); err != nil {
+		return s, err
+	}
+	for i := range s {
+		b := r[6*i:]
+		s[i] = ChannelState{
+			DAC:    decode(b[1:3]),
+			EEPROM: decode(b[4:6]),
+			Ready:  b[0]&0x80 != 0,
+			POR:    b[0]&0x40 != 0,
+		}
+	}
+	return s, nil
+}
+
+// Update updates the outputs of all the channels from their DAC registers.
+//
+// It pulses the LDAC pin when one was provided, otherwise it sends the
+// General Call Software Update command, which affects all the MCP4728 on the
+// bus.
+func (d *Dev) Update() error {
+	if d.ldac == nil {
+		return d.generalCall(gcSoftwareUpdate)
+	}
+	if err := d.ldac.Out(gpio.Low); err != nil {
+		return fmt.Errorf("mcp472x: %v", err)
+	}
+	// The minimum LDAC low pulse is 210ns.
+	doSleep(time.Microsecond)
+	if err := d.ldac.Out(gpio.High); err != nil {
+		return fmt.Errorf("mcp472x: %v", err)
+	}
+	return nil
+}
+
+// Reset sends the General Call Reset command. All the devices on the bus
+// supporting it reset, the MCP4728 reload their DAC registers from their
+// EEPROM.
+func (d *Dev) Reset() error {
+	return d.generalCall(gcReset)
+}
+
+// Wake sends the General Call Wake-Up command. All the MCP4728 on the bus
+// exit power-down.
+func (d *Dev) Wake() error {
+	return d.generalCall(gcWakeUp)
+}
+
+// Voltage returns the output voltage of a channel configuration.
+func (d *Dev) Voltage(cfg ChannelConfig) physic.ElectricPotential {
+	if cfg.PowerDown != Normal {
+		return 0
+	}
+	ref := d.vdd
+	if cfg.Reference == Internal {
+		ref = internalRef << cfg.Gain
+	}
+	return ref * physic.ElectricPotential(cfg.Value) / (MaxValue + 1)
+}
+
+// Halt puts all the channels in power-down with a 500kΩ pull-down. The
+// EEPROM is not written, so the outputs are restored on the next power up.
+//
+// Halt implements conn.Resource.
+func (d *Dev) Halt() error {
+	return d.SetPowerDown([4]PowerDown{PowerDown500K, PowerDown500K, PowerDown500K, PowerDown500K})
+}
+
+//
+
+// Commands.
+const (
+	cmdMultiWrite      = 0x40
+	cmdSequentialWrite = 0x50
+	cmdSingleWrite     = 0x58
+	cmdWriteVref       = 0x80
+	cmdWriteGain       = 0xC0
+	cmdWritePowerDown  = 0xA0
+
+	// General Call commands.
+	gcReset          = 0x06
+	gcSoftwareUpdate = 0x08
+	gcWakeUp         = 0x09
+
+	internalRef = 2048 * physic.MilliVolt
+
+	// eepromWriteTime is the maximum time of an EEPROM write.
+	eepromWriteTime = 50 * time.Millisecond
+	eepromPoll      = 5 * time.Millisecond
+)
+
+var (
+	errValue   = errors.New("mcp472x: value must be between 0 and 4095")
+	errChannel = errors.New("mcp472x: invalid channel")
+)
+
+func (c *ChannelConfig) encode() ([2]byte, error) {
+	if c.Value > MaxValue {
+		return [2]byte{}, errValue
+	}
+	return [2]byte{
+		byte(c.Reference&1)<<7 | byte(c.PowerDown&3)<<5 | byte(c.Gain&1)<<4 | byte(c.Value>>8),
+		byte(c.Value),
+	}, nil
+}
+
+func decode(b []byte) ChannelConfig {
+	return ChannelConfig{
+		Value:     uint16(b[0]&0x0F)<<8 | uint16(b[1]),
+		Reference: Reference(b[0] >> 7),
+		Gain:      Gain(b[0] >> 4 & 1),
+		PowerDown: PowerDown(b[0] >> 5 & 3),
+	}
+}
+
+// udac returns the UDAC bit of the write commands. When set, the outputs are
+// only updated by LDAC.
+func (d *Dev) udac() byte {
+	if d.ldac != nil {
+		return 1
+	}
+	return 0
+}
+
+func (d *Dev) tx(w, r []byte) error {
+	if err := d.c.Tx(w, r); err != nil {
+		return fmt.Errorf("mcp472x: %v", err)
+	}
+	return nil
+}
+
+func (d *Dev) generalCall(cmd byte) error {
+	if err := d.c.Bus.Tx(0x00, []byte{cmd}, nil); err != nil {
+		return fmt.Errorf("mcp472x: %v", err)
+	}
+	return nil
+}
+
+// waitEEPROM waits for the RDY/BSY bit to be set.
+func (d *Dev) waitEEPROM() error {
+	var r [1]byte
+	for t := time.Duration(0); t <= eepromWriteTime; t += eepromPoll {
+		doSleep(eepromPoll)
+		if err := d.tx(nil, r[:]); err != nil {
+			return err
+		}
+		if r[0]&0x80 != 0 {
+			return nil
+		}
+	}
+	return errors.New("mcp472x: timeout writing EEPROM")
+}
+
+var doSleep = time.Sleep
+
+var _ conn.Resource = &Dev{}
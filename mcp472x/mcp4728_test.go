@@ -0,0 +1,234 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package mcp472x
+
+import (
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpiotest"
+	"periph.io/x/conn/v3/i2c/i2ctest"
+	"periph.io/x/conn/v3/physic"
+)
+
+func TestNewMCP4728(t *testing.T) {
+	if _, err := NewMCP4728(&i2ctest.Playback{}, &Opts{Addr: 0x48}); err == nil {
+		t.Fatal("expected error on address")
+	}
+	ldac := &gpiotest.Pin{N: "LDAC"}
+	opts := DefaultOpts
+	opts.LDAC = ldac
+	d, err := NewMCP4728(&i2ctest.Playback{}, &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ldac.L != gpio.High {
+		t.Fatal("LDAC not high")
+	}
+	if s := d.String(); s != "MCP4728{playback(96)}" {
+		t.Fatal(s)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	defer func(f func(time.Duration)) { doSleep = f }(doSleep)
+	doSleep = func(time.Duration) {}
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// FastWrite.
+			{Addr: 0x61, W: []byte{0x0F, 0xFF, 0x08, 0x00, 0x30, 0x01, 0x00, 0x00}},
+			// MultiWrite of B and D.
+			{Addr: 0x61, W: []byte{0x42, 0x98, 0x00, 0x46, 0x02, 0x00}},
+			// SingleWrite of C, busy then ready.
+			{Addr: 0x61, W: []byte{0x5C, 0x81, 0x23}},
+			{Addr: 0x61, R: []byte{0x41}},
+			{Addr: 0x61, R: []byte{0xC1}},
+			// SequentialWrite from C.
+			{Addr: 0x61, W: []byte{0x54, 0x00, 0x01, 0x40, 0x02}},
+			{Addr: 0x61, R: []byte{0xC1}},
+			{Addr: 0x61, W: []byte{0x8A}},
+			{Addr: 0x61, W: []byte{0xC3}},
+			{Addr: 0x61, W: []byte{0xA1, 0x80}},
+			// Halt.
+			{Addr: 0x61, W: []byte{0xAF, 0xF0}},
+		},
+	}
+	d, err := NewMCP4728(&bus, &Opts{Addr: 0x61})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.FastWrite([4]ChannelConfig{{Value: 4095}, {Value: 2048}, {Value: 1, PowerDown: PowerDown500K}, {}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.FastWrite([4]ChannelConfig{{Value: 4096}}); err == nil {
+		t.Fatal("expected error on value")
+	}
+	if err := d.MultiWrite(
+		ChannelUpdate{ChannelB, ChannelConfig{Value: 2048, Reference: Internal, Gain: Gain2x}},
+		ChannelUpdate{ChannelD, ChannelConfig{Value: 512}},
+	); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.MultiWrite(ChannelUpdate{Channel: 4}); err == nil {
+		t.Fatal("expected error on channel")
+	}
+	if err := d.SingleWrite(ChannelC, ChannelConfig{Value: 0x123, Reference: Internal}); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SequentialWrite(ChannelC, []ChannelConfig{{Value: 1}, {Value: 2, PowerDown: PowerDown100K}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SequentialWrite(ChannelC, []ChannelConfig{{}}); err == nil {
+		t.Fatal("expected error on length")
+	}
+	if err := d.SetReference([4]Reference{Internal, VDD, Internal, VDD}); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetGain([4]Gain{Gain1x, Gain1x, Gain2x, Gain2x}); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetPowerDown([4]PowerDown{Normal, PowerDown1K, PowerDown100K, Normal}); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSingleWrite_Timeout(t *testing.T) {
+	defer func(f func(time.Duration)) { doSleep = f }(doSleep)
+	doSleep = func(time.Duration) {}
+	ops := []i2ctest.IO{{Addr: 0x60, W: []byte{0x58, 0x00, 0x00}}}
+	for i := 0; i <= int(eepromWriteTime/eepromPoll); i++ {
+		ops = append(ops, i2ctest.IO{Addr: 0x60, R: []byte{0x40}})
+	}
+	bus := i2ctest.Playback{Ops: ops}
+	d, err := NewMCP4728(&bus, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SingleWrite(ChannelA, ChannelConfig{}); err == nil {
+		t.Fatal("expected timeout")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadChannels(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x60, R: []byte{
+				0xC0, 0x98, 0x00, 0xC8, 0x00, 0x00,
+				0xD0, 0x0F, 0xFF, 0xD8, 0x0F, 0xFF,
+				0xE0, 0x40, 0x01, 0xE8, 0x00, 0x01,
+				0x70, 0x00, 0x00, 0x78, 0x00, 0x00,
+			}},
+		},
+	}
+	d, err := NewMCP4728(&bus, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := d.ReadChannels()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (ChannelConfig{Value: 2048, Reference: Internal, Gain: Gain2x}); s[0].DAC != want || s[0].EEPROM != (ChannelConfig{}) {
+		t.Fatalf("%+v", s[0])
+	}
+	if s[1].DAC.Value != 4095 || s[1].EEPROM.Value != 4095 {
+		t.Fatalf("%+v", s[1])
+	}
+	if s[2].DAC.PowerDown != PowerDown100K || s[2].DAC.Value != 1 {
+		t.Fatalf("%+v", s[2])
+	}
+	if !s[0].Ready || !s[0].POR || s[3].Ready || !s[3].POR {
+		t.Fatalf("%+v", s)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	defer func(f func(time.Duration)) { doSleep = f }(doSleep)
+	doSleep = func(time.Duration) {}
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x60, W: []byte{0x41, 0x00, 0x10}},
+			{Addr: 0x00, W: []byte{0x06}},
+			{Addr: 0x00, W: []byte{0x09}},
+			{Addr: 0x00, W: []byte{0x08}},
+		},
+	}
+	ldac := &gpiotest.Pin{N: "LDAC"}
+	opts := DefaultOpts
+	opts.LDAC = ldac
+	d, err := NewMCP4728(&bus, &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// With LDAC, UDAC is set.
+	if err := d.MultiWrite(ChannelUpdate{ChannelA, ChannelConfig{Value: 16}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Update(); err != nil {
+		t.Fatal(err)
+	}
+	if ldac.L != gpio.High {
+		t.Fatal("LDAC not released")
+	}
+	if err := d.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Wake(); err != nil {
+		t.Fatal(err)
+	}
+	d.ldac = nil
+	if err := d.Update(); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVoltage(t *testing.T) {
+	d := Dev{vdd: 5 * physic.Volt}
+	data := []struct {
+		cfg      ChannelConfig
+		expected physic.ElectricPotential
+	}{
+		{ChannelConfig{Value: 2048}, 2500 * physic.MilliVolt},
+		{ChannelConfig{Value: 2048, Reference: Internal}, 1024 * physic.MilliVolt},
+		{ChannelConfig{Value: 2048, Reference: Internal, Gain: Gain2x}, 2048 * physic.MilliVolt},
+		{ChannelConfig{Value: 2048, PowerDown: PowerDown1K}, 0},
+	}
+	for i, line := range data {
+		if v := d.Voltage(line.cfg); v != line.expected {
+			t.Fatalf("#%d: %s != %s", i, v, line.expected)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	if s := ChannelB.String(); s != "B" {
+		t.Fatal(s)
+	}
+	if s := Channel(5).String(); s != "Channel(5)" {
+		t.Fatal(s)
+	}
+	if s := PowerDown100K.String(); s != "PowerDown100K" {
+		t.Fatal(s)
+	}
+	if s := PowerDown(4).String(); s != "PowerDown(4)" {
+		t.Fatal(s)
+	}
+}
@@ -14,18 +14,19 @@ const (
 	PCD_RESETPHASE = 0x0F
 	PCD_CALCCRC    = 0x03
 
-	PICC_REQIDL    = 0x26
-	PICC_REQALL    = 0x52
-	PICC_ANTICOLL  = 0x93
-	PICC_ANTICOLL2 = 0x95
-	PICC_SElECTTAG = 0x93
-	PICC_AUTHENT1A = 0x60
-	PICC_AUTHENT1B = 0x61
-	PICC_READ      = 0x30
-	PICC_WRITE     = 0xA0
-	PICC_DECREMENT = 0xC0
-	PICC_INCREMENT = 0xC1
-	PICC_RESTORE   = 0xC2
-	PICC_TRANSFER  = 0xB0
-	PICC_HALT      = 0x50
+	PICC_REQIDL     = 0x26
+	PICC_REQALL     = 0x52
+	PICC_ANTICOLL   = 0x93
+	PICC_ANTICOLL2  = 0x95
+	PICC_SElECTTAG  = 0x93
+	PICC_SELECTTAG2 = 0x95
+	PICC_AUTHENT1A  = 0x60
+	PICC_AUTHENT1B  = 0x61
+	PICC_READ       = 0x30
+	PICC_WRITE      = 0xA0
+	PICC_DECREMENT  = 0xC0
+	PICC_INCREMENT  = 0xC1
+	PICC_RESTORE    = 0xC2
+	PICC_TRANSFER   = 0xB0
+	PICC_HALT       = 0x50
 )
@@ -5,6 +5,7 @@
 package mfrc522_test
 
 import (
+	"context"
 	"encoding/hex"
 	"log"
 	"reflect"
@@ -174,3 +175,40 @@ func ExampleDev_ReadUID() {
 		}
 	}
 }
+
+func ExampleDev_Watch() {
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Using SPI as an example. See package "periph.io/x/conn/v3/spi/spireg" for more details.
+	p, err := spireg.Open("")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer p.Close()
+
+	rfid, err := mfrc522.NewSPI(p, rpi.P1_22, rpi.P1_18)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Idling device on exit.
+	defer rfid.Halt()
+
+	// Watching cards for a minute.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	events, err := rfid.Watch(ctx, &mfrc522.DefaultWatchOpts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for e := range events {
+		if e.Present {
+			log.Printf("Card %s entered", e.Card)
+		} else {
+			log.Printf("Card %s left", e.Card)
+		}
+	}
+}
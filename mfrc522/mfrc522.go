@@ -27,6 +27,7 @@ type Dev struct {
 	beforeCall       func()
 	afterCall        func()
 	bogusUID         bool
+	irq              bool
 }
 
 // Key is the access key that consists of 6 bytes. There could be two types of keys - keyA and keyB.
@@ -108,6 +109,7 @@ func NewSPI(spiPort spi.Port, resetPin gpio.PinOut, irqPin gpio.PinIn, configs .
 		beforeCall:       cfg.beforeCall,
 		afterCall:        cfg.afterCall,
 		bogusUID:         cfg.bogusUID,
+		irq:              irqPin != nil,
 	}
 	return dev, nil
 }
@@ -364,8 +366,14 @@ func (r *Dev) antiColl2() ([]byte, error) {
 
 // selectTag selects the FOB device by device UUID.
 func (r *Dev) selectTag(serial []byte) (byte, error) {
+	return r.selectTagLevel(commands.PICC_SElECTTAG, serial)
+}
+
+// selectTagLevel selects the FOB device at the given cascade level and
+// returns its SAK.
+func (r *Dev) selectTagLevel(cmd byte, serial []byte) (byte, error) {
 	dataBuf := make([]byte, len(serial)+2)
-	dataBuf[0] = commands.PICC_SElECTTAG
+	dataBuf[0] = cmd
 	dataBuf[1] = 0x70
 	copy(dataBuf[2:], serial)
 	crc, err := r.LowLevel.CRC(dataBuf)
@@ -6,9 +6,11 @@ package mfrc522
 
 import (
 	"bytes"
+	"context"
 	"reflect"
 	"strconv"
 	"testing"
+	"time"
 )
 
 func fromBitString(t *testing.T, s string) (res byte) {
@@ -111,3 +113,68 @@ func TestByteArrayDecipher(t *testing.T) {
 		t.Fatalf("Wrong access calculation: %v != %v", bitsData, access)
 	}
 }
+
+func TestWatch(t *testing.T) {
+	a := Card{UID: []byte{1, 2, 3, 4}, Type: CardClassic1K}
+	b := Card{UID: []byte{4, 3, 2, 1, 0, 1, 2}, Type: CardUltralight}
+	// One card detection per poll, nil when no card is detected.
+	polls := []*Card{nil, &a, &a, nil, &a, nil, nil, nil, &b, &a, nil, nil, nil}
+	want := []Event{
+		{Card: a, Present: true},
+		{Card: a},
+		{Card: b, Present: true},
+		{Card: b},
+		{Card: a, Present: true},
+		{Card: a},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	i := 0
+	detect := func(time.Duration) (Card, bool) {
+		if i == len(polls) {
+			cancel()
+			return Card{}, false
+		}
+		c := polls[i]
+		i++
+		if c == nil {
+			return Card{}, false
+		}
+		return *c, true
+	}
+	c := make(chan Event)
+	go func() {
+		defer close(c)
+		// The card is gone after 3 missed detections.
+		watch(ctx, &WatchOpts{Interval: time.Millisecond, Debounce: 3 * time.Millisecond}, detect, c)
+	}()
+	var got []Event
+	for e := range c {
+		got = append(got, e)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("%v != %v", got, want)
+	}
+}
+
+func TestWatch_invalid(t *testing.T) {
+	d := &Dev{}
+	if _, err := d.Watch(context.Background(), &WatchOpts{}); err == nil {
+		t.Fatal("invalid interval")
+	}
+	if _, err := d.Watch(context.Background(), &WatchOpts{Interval: time.Second, Debounce: -1}); err == nil {
+		t.Fatal("invalid debounce")
+	}
+}
+
+func TestCardType_String(t *testing.T) {
+	if s := CardClassic4K.String(); s != "MIFARE Classic 4K" {
+		t.Fatal(s)
+	}
+	if s := CardType(0x28).String(); s != "CardType(0x28)" {
+		t.Fatal(s)
+	}
+	if s := (Card{UID: []byte{0xDE, 0xAD}, Type: CardMini}).String(); s != "MIFARE Mini dead" {
+		t.Fatal(s)
+	}
+}
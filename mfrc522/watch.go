@@ -0,0 +1,200 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package mfrc522
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"periph.io/x/devices/v3/mfrc522/commands"
+)
+
+// CardType is the SAK (Select AcKnowledge) returned by a card when
+// selected, which identifies its type.
+type CardType byte
+
+// Common card types.
+const (
+	CardUltralight CardType = 0x00
+	CardMini       CardType = 0x09
+	CardClassic1K  CardType = 0x08
+	CardClassic4K  CardType = 0x18
+	CardPlus2K     CardType = 0x10
+	CardPlus4K     CardType = 0x11
+	CardISO14443   CardType = 0x20
+)
+
+func (c CardType) String() string {
+	switch c {
+	case CardUltralight:
+		return "MIFARE Ultralight"
+	case CardMini:
+		return "MIFARE Mini"
+	case CardClassic1K:
+		return "MIFARE Classic 1K"
+	case CardClassic4K:
+		return "MIFARE Classic 4K"
+	case CardPlus2K:
+		return "MIFARE Plus 2K"
+	case CardPlus4K:
+		return "MIFARE Plus 4K"
+	case CardISO14443:
+		return "ISO/IEC 14443-4"
+	default:
+		return fmt.Sprintf("CardType(0x%02x)", byte(c))
+	}
+}
+
+// Card is a card detected in the field of the reader.
+type Card struct {
+	// UID is the 4 or 7 bytes card UID.
+	UID  []byte
+	Type CardType
+}
+
+func (c Card) String() string {
+	return fmt.Sprintf("%s %x", c.Type, c.UID)
+}
+
+// Event is a change of the card in the field of the reader.
+type Event struct {
+	Card Card
+	// Present is true when the card entered the field and false when it left
+	// it.
+	Present bool
+}
+
+// WatchOpts configures Watch.
+type WatchOpts struct {
+	// Interval is the time between two detections. When the IRQ pin is set,
+	// it is the maximum time waiting for it at each detection.
+	Interval time.Duration
+	// Debounce is how long a card must stay undetected to be considered gone.
+	// Readers commonly miss a detection while a card is held near the
+	// antenna.
+	Debounce time.Duration
+}
+
+// DefaultWatchOpts is the recommended default options for Watch.
+var DefaultWatchOpts = WatchOpts{
+	Interval: 100 * time.Millisecond,
+	Debounce: 500 * time.Millisecond,
+}
+
+// Watch detects the cards entering and leaving the field of the reader and
+// reports them over the returned channel, instead of requiring the
+// application to loop on ReadUID.
+//
+// The IRQ pin is used to wait for a card when it was given to NewSPI,
+// otherwise the reader is polled at each interval.
+//
+// The channel is closed once ctx is canceled. Only one card is tracked at a
+// time; when another card replaces it, a leaving event is sent for the
+// previous card first.
+func (r *Dev) Watch(ctx context.Context, opts *WatchOpts) (<-chan Event, error) {
+	if opts.Interval <= 0 {
+		return nil, wrapf("invalid watch interval %s", opts.Interval)
+	}
+	if opts.Debounce < 0 {
+		return nil, wrapf("invalid debounce %s", opts.Debounce)
+	}
+	c := make(chan Event)
+	go func() {
+		defer close(c)
+		watch(ctx, opts, r.detect, c)
+	}()
+	return c, nil
+}
+
+//
+
+// watch sends the changes of the card returned by detect until ctx is
+// canceled.
+func watch(ctx context.Context, opts *WatchOpts, detect func(timeout time.Duration) (Card, bool), c chan<- Event) {
+	// Number of consecutive missed detections before the card is gone.
+	misses := int((opts.Debounce + opts.Interval - 1) / opts.Interval)
+	if misses < 1 {
+		misses = 1
+	}
+	t := time.NewTicker(opts.Interval)
+	defer t.Stop()
+	var current *Card
+	missed := 0
+	send := func(e Event) bool {
+		select {
+		case c <- e:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+	for ctx.Err() == nil {
+		if card, ok := detect(opts.Interval); ok {
+			missed = 0
+			if current == nil || !bytes.Equal(current.UID, card.UID) {
+				if current != nil && !send(Event{Card: *current}) {
+					return
+				}
+				current = &card
+				if !send(Event{Card: card, Present: true}) {
+					return
+				}
+			}
+		} else if current != nil {
+			if missed++; missed >= misses {
+				if !send(Event{Card: *current}) {
+					return
+				}
+				current = nil
+				missed = 0
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// detect returns the card in the field, if any.
+func (r *Dev) detect(timeout time.Duration) (Card, bool) {
+	r.beforeCall()
+	defer r.afterCall()
+	if r.irq {
+		if err := r.LowLevel.WaitForEdge(timeout); err != nil {
+			return Card{}, false
+		}
+		defer r.LowLevel.ClearInterrupt()
+	}
+	if err := r.LowLevel.Init(); err != nil {
+		return Card{}, false
+	}
+	if _, err := r.request(); err != nil {
+		return Card{}, false
+	}
+	uid, err := r.antiColl()
+	if err != nil {
+		return Card{}, false
+	}
+	sak, err := r.selectTag(uid)
+	if err != nil {
+		return Card{}, false
+	}
+	if uid[0] != 0x88 {
+		return Card{UID: uid[:4], Type: CardType(sak)}, true
+	}
+	// Incomplete UID, the card type is given at the second cascade level.
+	end, err := r.antiColl2()
+	if err != nil {
+		return Card{}, false
+	}
+	if sak, err = r.selectTagLevel(commands.PICC_SELECTTAG2, end); err != nil {
+		return Card{}, false
+	}
+	return Card{UID: append(uid[1:4:4], end[:4]...), Type: CardType(sak)}, true
+}
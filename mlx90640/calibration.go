@@ -0,0 +1,283 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package mlx90640
+
+import (
+	"errors"
+	"math"
+
+	"periph.io/x/conn/v3/physic"
+)
+
+// params are the calibration parameters stored in the EEPROM.
+//
+// The extraction and the computation of the temperatures follow the reference
+// implementation from Melexis, MLX90640_API.c.
+type params struct {
+	kVdd       float64
+	vdd25      float64
+	kvPTAT     float64
+	ktPTAT     float64
+	vPTAT25    float64
+	alphaPTAT  float64
+	gainEE     float64
+	tgc        float64
+	ksTa       float64
+	resolution int
+	// calibrationMode is the reading pattern used during the calibration,
+	// 0x80 for the chess pattern and 0 for the interleaved pattern.
+	calibrationMode uint16
+	ksTo            [4]float64
+	ct              [4]float64
+	cpAlpha         [2]float64
+	cpOffset        [2]float64
+	cpKta           float64
+	cpKv            float64
+	ilChessC        [3]float64
+	alpha           [Width * Height]float64
+	offset          [Width * Height]float64
+	kta             [Width * Height]float64
+	kv              [Width * Height]float64
+}
+
+// taShift is the difference between the ambient temperature and the sensor
+// temperature, for a sensor in open air.
+const taShift = 8
+
+var (
+	errEEPROM = errors.New("mlx90640: invalid EEPROM content")
+	errFrame  = errors.New("mlx90640: invalid frame data")
+)
+
+// extract computes the calibration parameters from the EEPROM content.
+func (p *params) extract(ee *[eepromSize]uint16) error {
+	if ee[51]>>8 == 0 || ee[50]&0x3FF == 0 {
+		return errEEPROM
+	}
+	p.kVdd = float64(signed(ee[51]>>8, 8) * 32)
+	p.vdd25 = float64((int(ee[51]&0xFF)-256)<<5 - 8192)
+
+	p.kvPTAT = float64(signed(ee[50]>>10, 6)) / 4096
+	p.ktPTAT = float64(signed(ee[50], 10)) / 8
+	p.vPTAT25 = float64(ee[49])
+	p.alphaPTAT = float64(ee[16]>>12)/4 + 8
+
+	p.gainEE = float64(int16(ee[48]))
+	p.tgc = float64(signed(ee[60], 8)) / 32
+	p.resolution = int(ee[56]>>12) & 3
+	p.ksTa = float64(signed(ee[60]>>8, 8)) / 8192
+
+	// Temperature ranges of the ksTo coefficients.
+	step := float64(ee[63]>>12&3) * 10
+	p.ct[0] = -40
+	p.ct[1] = 0
+	p.ct[2] = float64(ee[63]>>4&0xF) * step
+	p.ct[3] = p.ct[2] + float64(ee[63]>>8&0xF)*step
+	ksToScale := float64(int(1) << (ee[63]&0xF + 8))
+	p.ksTo[0] = float64(signed(ee[61], 8)) / ksToScale
+	p.ksTo[1] = float64(signed(ee[61]>>8, 8)) / ksToScale
+	p.ksTo[2] = float64(signed(ee[62], 8)) / ksToScale
+	p.ksTo[3] = float64(signed(ee[62]>>8, 8)) / ksToScale
+
+	p.extractCP(ee)
+	p.extractAlpha(ee)
+	p.extractOffset(ee)
+	p.extractKta(ee)
+	p.extractKv(ee)
+
+	p.calibrationMode = (ee[10] & 0x0800 >> 4) ^ 0x80
+	p.ilChessC[0] = float64(signed(ee[53], 6)) / 16
+	p.ilChessC[1] = float64(signed(ee[53]>>6, 5)) / 2
+	p.ilChessC[2] = float64(signed(ee[53]>>11, 5)) / 8
+	return nil
+}
+
+// extractCP extracts the parameters of the compensation pixels.
+func (p *params) extractCP(ee *[eepromSize]uint16) {
+	alphaScale := float64(ee[32]>>12) + 27
+	p.cpOffset[0] = float64(signed(ee[58], 10))
+	p.cpOffset[1] = float64(signed(ee[58]>>10, 6)) + p.cpOffset[0]
+	p.cpAlpha[0] = float64(signed(ee[57], 10)) / math.Pow(2, alphaScale)
+	p.cpAlpha[1] = (1 + float64(signed(ee[57]>>10, 6))/128) * p.cpAlpha[0]
+	ktaScale1 := float64(ee[56]>>4&0xF) + 8
+	p.cpKta = float64(signed(ee[59], 8)) / math.Pow(2, ktaScale1)
+	kvScale := float64(ee[56] >> 8 & 0xF)
+	p.cpKv = float64(signed(ee[59]>>8, 8)) / math.Pow(2, kvScale)
+}
+
+func (p *params) extractAlpha(ee *[eepromSize]uint16) {
+	remScale := ee[32] & 0xF
+	columnScale := ee[32] >> 4 & 0xF
+	rowScale := ee[32] >> 8 & 0xF
+	alphaScale := math.Pow(2, float64(ee[32]>>12)+30)
+	ref := int(ee[33])
+	row, column := nibbles(ee, 34), nibbles(ee, 40)
+	for i := 0; i < Height; i++ {
+		for j := 0; j < Width; j++ {
+			n := i*Width + j
+			a := signed(ee[64+n]>>4, 6) << remScale
+			a += ref + row[i]<<rowScale + column[j]<<columnScale
+			p.alpha[n] = float64(a) / alphaScale
+		}
+	}
+}
+
+func (p *params) extractOffset(ee *[eepromSize]uint16) {
+	remScale := ee[16] & 0xF
+	columnScale := ee[16] >> 4 & 0xF
+	rowScale := ee[16] >> 8 & 0xF
+	ref := int(int16(ee[17]))
+	row, column := nibbles(ee, 18), nibbles(ee, 24)
+	for i := 0; i < Height; i++ {
+		for j := 0; j < Width; j++ {
+			n := i*Width + j
+			o := signed(ee[64+n]>>10, 6) << remScale
+			p.offset[n] = float64(ref + row[i]<<rowScale + column[j]<<columnScale + o)
+		}
+	}
+}
+
+func (p *params) extractKta(ee *[eepromSize]uint16) {
+	// Indexed by split, for odd and even rows and columns.
+	rc := [4]int{
+		signed(ee[54]>>8, 8),
+		signed(ee[55]>>8, 8),
+		signed(ee[54], 8),
+		signed(ee[55], 8),
+	}
+	scale1 := math.Pow(2, float64(ee[56]>>4&0xF)+8)
+	scale2 := ee[56] & 0xF
+	for n := range p.kta {
+		k := signed(ee[64+n]>>1, 3) << scale2
+		p.kta[n] = float64(rc[split(n)]+k) / scale1
+	}
+}
+
+func (p *params) extractKv(ee *[eepromSize]uint16) {
+	rc := [4]int{
+		signed(ee[52]>>12, 4),
+		signed(ee[52]>>4, 4),
+		signed(ee[52]>>8, 4),
+		signed(ee[52], 4),
+	}
+	scale := math.Pow(2, float64(ee[56]>>8&0xF))
+	for n := range p.kv {
+		p.kv[n] = float64(rc[split(n)]) / scale
+	}
+}
+
+// vdd returns the supply voltage in volts.
+func (p *params) vdd(sp *subpage) float64 {
+	resolution := int(sp.control>>10) & 3
+	correction := math.Pow(2, float64(p.resolution-resolution))
+	return (correction*float64(int16(sp.ram[810]))-p.vdd25)/p.kVdd + 3.3
+}
+
+// ta returns the temperature of the sensor in °C.
+func (p *params) ta(sp *subpage, vdd float64) float64 {
+	ptat := float64(int16(sp.ram[800]))
+	vbe := float64(int16(sp.ram[768]))
+	art := ptat / (ptat*p.alphaPTAT + vbe) * (1 << 18)
+	return (art/(1+p.kvPTAT*(vdd-3.3))-p.vPTAT25)/p.ktPTAT + 25
+}
+
+// calculate computes the object temperatures of the pixels measured in the
+// subpage and stores them in f.
+func (p *params) calculate(sp *subpage, emissivity float64, f *Frame) error {
+	if int16(sp.ram[778]) == 0 {
+		return errFrame
+	}
+	vdd := p.vdd(sp)
+	ta := p.ta(sp, vdd)
+	f.Ambient = celsius(ta)
+
+	ta4 := math.Pow(ta+273.15, 4)
+	tr4 := math.Pow(ta-taShift+273.15, 4)
+	taTr := tr4 - (tr4-ta4)/emissivity
+
+	var alphaCorr [4]float64
+	alphaCorr[0] = 1 / (1 + p.ksTo[0]*40)
+	alphaCorr[1] = 1
+	alphaCorr[2] = 1 + p.ksTo[1]*p.ct[2]
+	alphaCorr[3] = alphaCorr[2] * (1 + p.ksTo[2]*(p.ct[3]-p.ct[2]))
+
+	gain := p.gainEE / float64(int16(sp.ram[778]))
+	mode := sp.control & 0x1000 >> 5
+	cpComp := (1 + p.cpKta*(ta-25)) * (1 + p.cpKv*(vdd-3.3))
+	var cp [2]float64
+	cp[0] = float64(int16(sp.ram[776]))*gain - p.cpOffset[0]*cpComp
+	if mode == p.calibrationMode {
+		cp[1] = float64(int16(sp.ram[808]))*gain - p.cpOffset[1]*cpComp
+	} else {
+		cp[1] = float64(int16(sp.ram[808]))*gain - (p.cpOffset[1]+p.ilChessC[0])*cpComp
+	}
+
+	for n := range f.Pixels {
+		il := n/32 - n/64*2
+		pattern := il
+		if mode != 0 {
+			pattern = il ^ n%2
+		}
+		if pattern != sp.page {
+			continue
+		}
+		ir := float64(int16(sp.ram[n])) * gain
+		ir -= p.offset[n] * (1 + p.kta[n]*(ta-25)) * (1 + p.kv[n]*(vdd-3.3))
+		if mode != p.calibrationMode {
+			conversion := (n+2)/4 - (n+3)/4 + (n+1)/4 - n/4
+			ir += p.ilChessC[2]*float64(2*il-1) - p.ilChessC[1]*float64(conversion*(1-2*il))
+		}
+		ir /= emissivity
+		ir -= p.tgc * cp[sp.page]
+
+		alpha := (p.alpha[n] - p.tgc*p.cpAlpha[sp.page]) * (1 + p.ksTa*(ta-25))
+		sx := math.Pow(alpha, 3) * (ir + alpha*taTr)
+		sx = math.Sqrt(math.Sqrt(sx)) * p.ksTo[1]
+		to := math.Sqrt(math.Sqrt(ir/(alpha*(1-p.ksTo[1]*273.15)+sx)+taTr)) - 273.15
+
+		r := 3
+		switch {
+		case to < p.ct[1]:
+			r = 0
+		case to < p.ct[2]:
+			r = 1
+		case to < p.ct[3]:
+			r = 2
+		}
+		to = math.Sqrt(math.Sqrt(ir/(alpha*alphaCorr[r]*(1+p.ksTo[r]*(to-p.ct[r])))+taTr)) - 273.15
+		f.Pixels[n] = celsius(to)
+	}
+	return nil
+}
+
+// split returns the index of the parameters shared by the pixels of odd or
+// even rows and columns.
+func split(n int) int {
+	return 2*(n/32-n/64*2) + n%2
+}
+
+// nibbles returns the signed 4 bits values packed in the 6 words for rows or
+// the 8 words for columns starting at ee[i].
+func nibbles(ee *[eepromSize]uint16, i int) [Width]int {
+	var v [Width]int
+	for j := range v {
+		v[j] = signed(ee[i+j/4]>>(4*(j%4)), 4)
+	}
+	return v
+}
+
+// signed returns the lowest bits of v as a two's complement value.
+func signed(v uint16, bits uint) int {
+	v &= 1<<bits - 1
+	if v >= 1<<(bits-1) {
+		return int(v) - 1<<bits
+	}
+	return int(v)
+}
+
+// celsius converts a temperature in °C.
+func celsius(c float64) physic.Temperature {
+	return physic.ZeroCelsius + physic.Temperature(math.Round(c*float64(physic.Kelvin)))
+}
@@ -0,0 +1,24 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package mlx90640 controls a Melexis MLX90640 32x24 pixels far infrared
+// thermal sensor array over I²C.
+//
+// The calibration parameters are extracted from the sensor EEPROM when the
+// device is opened. The sensor measures the pixels in two interleaved
+// subpages, in chess pattern by default; ReadFrame waits for both subpages
+// and computes the object temperature of every pixel, following the
+// reference implementation provided by Melexis.
+//
+// Frame.Image converts the temperatures to an image.Gray16 that can be drawn
+// on the displays supported by this repository, keeping the temperature range
+// mapped to its black and white levels.
+//
+// The I²C bus must support reading 1664 bytes in a single transaction. At
+// refresh rates above 8Hz, a 1MHz bus is required.
+//
+// # Datasheet
+//
+// https://www.melexis.com/-/media/files/documents/datasheets/mlx90640-datasheet-melexis.pdf
+package mlx90640
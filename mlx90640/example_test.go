@@ -0,0 +1,42 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package mlx90640_test
+
+import (
+	"fmt"
+	"log"
+
+	"periph.io/x/conn/v3/i2c/i2creg"
+	"periph.io/x/devices/v3/mlx90640"
+	"periph.io/x/host/v3"
+)
+
+func Example() {
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+	// Open default I²C bus.
+	bus, err := i2creg.Open("")
+	if err != nil {
+		log.Fatalf("failed to open I²C: %v", err)
+	}
+	defer bus.Close()
+
+	dev, err := mlx90640.NewI2C(bus, mlx90640.DefaultAddress, &mlx90640.DefaultOpts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var f mlx90640.Frame
+	if err := dev.ReadFrame(&f); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Ambient: %s, center: %s\n", f.Ambient, f.At(mlx90640.Width/2, mlx90640.Height/2))
+
+	// The image can be drawn on a display, e.g. scaled with
+	// golang.org/x/image/draw.
+	img := f.Image()
+	fmt.Printf("%s is black, %s is white\n", img.Min, img.Max)
+}
@@ -0,0 +1,320 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package mlx90640
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"sync"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/physic"
+)
+
+// Size of the sensor array.
+const (
+	Width  = 32
+	Height = 24
+)
+
+// DefaultAddress is the factory default I²C address.
+const DefaultAddress = 0x33
+
+// RefreshRate is the rate at which the sensor measures a subpage, i.e. half
+// of the pixels.
+type RefreshRate uint8
+
+// Possible refresh rates.
+const (
+	Refresh0_5Hz RefreshRate = iota
+	Refresh1Hz
+	Refresh2Hz
+	Refresh4Hz
+	Refresh8Hz
+	Refresh16Hz
+	Refresh32Hz
+	Refresh64Hz
+)
+
+func (r RefreshRate) String() string {
+	if r > Refresh64Hz {
+		return fmt.Sprintf("RefreshRate(%d)", r)
+	}
+	return refreshRateNames[r]
+}
+
+// Opts holds the configuration options.
+type Opts struct {
+	// RefreshRate of the subpages; a complete frame takes two subpages.
+	RefreshRate RefreshRate
+	// Emissivity of the observed objects, in ]0, 1]. Defaults to 0.95 when 0.
+	Emissivity float64
+}
+
+// DefaultOpts is the recommended default options.
+var DefaultOpts = Opts{
+	RefreshRate: Refresh2Hz,
+	Emissivity:  0.95,
+}
+
+// NewI2C returns a handle to a MLX90640 at address addr.
+//
+// The calibration parameters are read from the EEPROM and the refresh rate is
+// configured.
+func NewI2C(b i2c.Bus, addr uint16, opts *Opts) (*Dev, error) {
+	if opts.RefreshRate > Refresh64Hz {
+		return nil, fmt.Errorf("mlx90640: invalid refresh rate %s", opts.RefreshRate)
+	}
+	if opts.Emissivity < 0 || opts.Emissivity > 1 {
+		return nil, fmt.Errorf("mlx90640: invalid emissivity %g", opts.Emissivity)
+	}
+	d := &Dev{c: i2c.Dev{Bus: b, Addr: addr}, emissivity: opts.Emissivity}
+	if d.emissivity == 0 {
+		d.emissivity = DefaultOpts.Emissivity
+	}
+	var ee [eepromSize]uint16
+	if err := d.readWords(eepromAddr, ee[:]); err != nil {
+		return nil, fmt.Errorf("mlx90640: %v", err)
+	}
+	if err := d.p.extract(&ee); err != nil {
+		return nil, err
+	}
+	if err := d.setRefreshRate(opts.RefreshRate); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Dev is a handle to a MLX90640.
+type Dev struct {
+	c          i2c.Dev
+	emissivity float64
+	p          params
+
+	mu   sync.Mutex
+	rate RefreshRate
+	sp   subpage
+}
+
+func (d *Dev) String() string {
+	return fmt.Sprintf("MLX90640{%s}", &d.c)
+}
+
+// SetRefreshRate changes the rate at which the subpages are measured.
+func (d *Dev) SetRefreshRate(r RefreshRate) error {
+	if r > Refresh64Hz {
+		return fmt.Errorf("mlx90640: invalid refresh rate %s", r)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.setRefreshRate(r)
+}
+
+// ReadFrame waits for the sensor to measure both subpages and stores the
+// object temperatures in f.
+//
+// It blocks up to two subpage periods, e.g. one second at 2Hz.
+func (d *Dev) ReadFrame(f *Frame) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var done [2]bool
+	for !done[0] || !done[1] {
+		if err := d.readSubpage(); err != nil {
+			return err
+		}
+		if err := d.p.calculate(&d.sp, d.emissivity, f); err != nil {
+			return err
+		}
+		done[d.sp.page] = true
+	}
+	return nil
+}
+
+// Halt implements conn.Resource.
+//
+// It is a noop, the sensor measures continuously.
+func (d *Dev) Halt() error {
+	return nil
+}
+
+// Frame is a complete measurement of the sensor array.
+type Frame struct {
+	// Pixels are the object temperatures in row-major order.
+	Pixels [Width * Height]physic.Temperature
+	// Ambient is the temperature of the sensor.
+	Ambient physic.Temperature
+}
+
+// At returns the object temperature of the pixel at x, y.
+func (f *Frame) At(x, y int) physic.Temperature {
+	return f.Pixels[y*Width+x]
+}
+
+// Image returns the frame as a grayscale image scaled between its minimum and
+// maximum temperatures.
+func (f *Frame) Image() *Image {
+	lo, hi := f.Pixels[0], f.Pixels[0]
+	for _, t := range f.Pixels[1:] {
+		lo = min(lo, t)
+		hi = max(hi, t)
+	}
+	return f.ImageRange(lo, hi)
+}
+
+// ImageRange returns the frame as a grayscale image where lo is black and hi
+// is white. Temperatures outside the range are clamped.
+//
+// A fixed range keeps the brightness of a given temperature constant across
+// frames.
+func (f *Frame) ImageRange(lo, hi physic.Temperature) *Image {
+	if hi <= lo {
+		hi = lo + 1
+	}
+	img := &Image{Gray16: image.NewGray16(image.Rect(0, 0, Width, Height)), Min: lo, Max: hi}
+	for i, t := range f.Pixels {
+		t = min(max(t, lo), hi)
+		v := uint16(int64(t-lo) * 0xFFFF / int64(hi-lo))
+		img.Pix[2*i] = byte(v >> 8)
+		img.Pix[2*i+1] = byte(v)
+	}
+	return img
+}
+
+// Image is a thermal image along with its temperature scale.
+type Image struct {
+	*image.Gray16
+	// Min is the temperature of the black level.
+	Min physic.Temperature
+	// Max is the temperature of the white level.
+	Max physic.Temperature
+}
+
+// Temperature returns the temperature of the pixel at x, y, within the
+// precision of the scale.
+func (i *Image) Temperature(x, y int) physic.Temperature {
+	v := i.Gray16At(x, y).Y
+	return i.Min + physic.Temperature(int64(v)*int64(i.Max-i.Min)/0xFFFF)
+}
+
+//
+
+// Registers and memory areas, in 16 bits words.
+const (
+	regStatus   = 0x8000
+	regControl1 = 0x800D
+	ramAddr     = 0x0400
+	eepromAddr  = 0x2400
+
+	ramSize    = 832
+	eepromSize = 832
+)
+
+// Status register bits.
+const (
+	statusSubpage   = 0x0001
+	statusDataReady = 0x0008
+	// statusClear clears the data ready flag, enables the overwrite of the
+	// RAM and starts a measurement.
+	statusClear = 0x0030
+)
+
+// Control register 1 bits.
+const (
+	controlRefreshShift = 7
+	controlRefreshMask  = 0x7 << controlRefreshShift
+)
+
+// maxPolls is the number of status polls, each a tenth of the subpage period,
+// before giving up on a measurement.
+const maxPolls = 30
+
+var refreshRateNames = [...]string{"0.5Hz", "1Hz", "2Hz", "4Hz", "8Hz", "16Hz", "32Hz", "64Hz"}
+
+var errTimeout = errors.New("mlx90640: timed out waiting for a measurement")
+
+// period returns the duration of the measurement of a subpage.
+func (r RefreshRate) period() time.Duration {
+	return 2 * time.Second >> r
+}
+
+// subpage is the content of the RAM after the measurement of a subpage.
+type subpage struct {
+	ram     [ramSize]uint16
+	control uint16
+	page    int
+}
+
+func (d *Dev) setRefreshRate(r RefreshRate) error {
+	ctrl, err := d.readWord(regControl1)
+	if err != nil {
+		return fmt.Errorf("mlx90640: %v", err)
+	}
+	ctrl = ctrl&^controlRefreshMask | uint16(r)<<controlRefreshShift
+	if err := d.writeWord(regControl1, ctrl); err != nil {
+		return fmt.Errorf("mlx90640: %v", err)
+	}
+	d.rate = r
+	return nil
+}
+
+// readSubpage waits for a new subpage and reads it in d.sp.
+func (d *Dev) readSubpage() error {
+	poll := d.rate.period() / 10
+	for i := 0; ; i++ {
+		status, err := d.readWord(regStatus)
+		if err != nil {
+			return fmt.Errorf("mlx90640: %v", err)
+		}
+		if status&statusDataReady != 0 {
+			d.sp.page = int(status & statusSubpage)
+			break
+		}
+		if i == maxPolls {
+			return errTimeout
+		}
+		doSleep(poll)
+	}
+	if err := d.writeWord(regStatus, statusClear); err != nil {
+		return fmt.Errorf("mlx90640: %v", err)
+	}
+	if err := d.readWords(ramAddr, d.sp.ram[:]); err != nil {
+		return fmt.Errorf("mlx90640: %v", err)
+	}
+	ctrl, err := d.readWord(regControl1)
+	if err != nil {
+		return fmt.Errorf("mlx90640: %v", err)
+	}
+	d.sp.control = ctrl
+	return nil
+}
+
+// readWords reads big endian words starting at addr.
+func (d *Dev) readWords(addr uint16, w []uint16) error {
+	b := make([]byte, 2*len(w))
+	if err := d.c.Tx([]byte{byte(addr >> 8), byte(addr)}, b); err != nil {
+		return err
+	}
+	for i := range w {
+		w[i] = uint16(b[2*i])<<8 | uint16(b[2*i+1])
+	}
+	return nil
+}
+
+func (d *Dev) readWord(addr uint16) (uint16, error) {
+	var w [1]uint16
+	err := d.readWords(addr, w[:])
+	return w[0], err
+}
+
+func (d *Dev) writeWord(addr, v uint16) error {
+	return d.c.Tx([]byte{byte(addr >> 8), byte(addr), byte(v >> 8), byte(v)}, nil)
+}
+
+var doSleep = time.Sleep
+
+var _ conn.Resource = &Dev{}
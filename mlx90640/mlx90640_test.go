@@ -0,0 +1,243 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package mlx90640
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/i2c/i2ctest"
+	"periph.io/x/conn/v3/physic"
+)
+
+// testEEPROM returns an EEPROM with the global parameters of the datasheet
+// example and uniform pixels.
+func testEEPROM() *[eepromSize]uint16 {
+	var ee [eepromSize]uint16
+	ee[16] = 0x4210
+	ee[32] = 0x4000
+	ee[33] = 0x2E44
+	ee[48] = 0x1901
+	ee[49] = 0x2FF1
+	ee[50] = 0x5952
+	ee[51] = 0x9D68
+	ee[56] = 0x2363
+	return &ee
+}
+
+func words(w []uint16) []byte {
+	b := make([]byte, 2*len(w))
+	for i, v := range w {
+		b[2*i] = byte(v >> 8)
+		b[2*i+1] = byte(v)
+	}
+	return b
+}
+
+func TestExtract(t *testing.T) {
+	var p params
+	if err := p.extract(&[eepromSize]uint16{}); err != errEEPROM {
+		t.Fatalf("extract() = %v, want %v", err, errEEPROM)
+	}
+	if err := p.extract(testEEPROM()); err != nil {
+		t.Fatal(err)
+	}
+	if p.kVdd != -3168 || p.vdd25 != -13056 {
+		t.Fatalf("kVdd=%g vdd25=%g", p.kVdd, p.vdd25)
+	}
+	if math.Abs(p.kvPTAT-0.005371) > 1e-6 || p.ktPTAT != 42.25 || p.vPTAT25 != 12273 || p.alphaPTAT != 9 {
+		t.Fatalf("kvPTAT=%g ktPTAT=%g vPTAT25=%g alphaPTAT=%g", p.kvPTAT, p.ktPTAT, p.vPTAT25, p.alphaPTAT)
+	}
+	if p.gainEE != 6401 || p.resolution != 2 || p.calibrationMode != 0x80 {
+		t.Fatalf("gainEE=%g resolution=%d calibrationMode=%#x", p.gainEE, p.resolution, p.calibrationMode)
+	}
+	if want := 11844 / math.Pow(2, 34); p.alpha[0] != want || p.alpha[767] != want {
+		t.Fatalf("alpha=%g, want %g", p.alpha[0], want)
+	}
+}
+
+func TestSigned(t *testing.T) {
+	data := []struct {
+		v    uint16
+		bits uint
+		want int
+	}{
+		{0x1F, 6, 31},
+		{0x20, 6, -32},
+		{0xFFFF, 4, -1},
+		{0x9D, 8, -99},
+	}
+	for _, line := range data {
+		if got := signed(line.v, line.bits); got != line.want {
+			t.Errorf("signed(%#x, %d) = %d, want %d", line.v, line.bits, got, line.want)
+		}
+	}
+}
+
+func TestCalculate(t *testing.T) {
+	// Without compensations, To⁴ = ir/alpha + Ta⁴.
+	p := params{
+		kVdd:            -3168,
+		vdd25:           -13056,
+		ktPTAT:          1,
+		vPTAT25:         16384,
+		alphaPTAT:       9,
+		gainEE:          1,
+		resolution:      2,
+		calibrationMode: 0x80,
+		ct:              [4]float64{-40, 0, 100, 200},
+	}
+	for n := range p.alpha {
+		p.alpha[n] = 1e-7
+	}
+	sp := subpage{control: 0x1900}
+	sp.ram[768] = 7000
+	sp.ram[800] = 1000
+	sp.ram[778] = 1
+	sp.ram[810] = 0xCD00
+	ir := math.Round(1e-7 * (math.Pow(273.15+35, 4) - math.Pow(273.15+25, 4)))
+	for n := 0; n < Width*Height; n++ {
+		sp.ram[n] = uint16(ir)
+	}
+	var f Frame
+	if err := p.calculate(&sp, 1, &f); err != nil {
+		t.Fatal(err)
+	}
+	if f.Ambient != physic.ZeroCelsius+25*physic.Kelvin {
+		t.Fatalf("Ambient = %s", f.Ambient)
+	}
+	// Subpage 0 of the chess pattern.
+	if d := f.At(0, 0) - (physic.ZeroCelsius + 35*physic.Kelvin); d < -100*physic.MilliKelvin || d > 100*physic.MilliKelvin {
+		t.Fatalf("At(0, 0) = %s", f.At(0, 0))
+	}
+	if f.At(1, 0) != 0 || f.At(0, 1) != 0 || f.At(1, 1) == 0 {
+		t.Fatal("unexpected pattern")
+	}
+	sp.ram[778] = 0
+	if err := p.calculate(&sp, 1, &f); err != errFrame {
+		t.Fatalf("calculate() = %v, want %v", err, errFrame)
+	}
+}
+
+func TestReadFrame(t *testing.T) {
+	defer func(f func(time.Duration)) { doSleep = f }(doSleep)
+	var slept []time.Duration
+	doSleep = func(d time.Duration) { slept = append(slept, d) }
+
+	var ram [ramSize]uint16
+	ram[768] = 19442
+	ram[800] = 1711
+	ram[778] = 0x1901
+	ram[810] = 0xCD00
+	ops := []i2ctest.IO{
+		{Addr: DefaultAddress, W: []byte{0x24, 0x00}, R: words(testEEPROM()[:])},
+		{Addr: DefaultAddress, W: []byte{0x80, 0x0D}, R: []byte{0x19, 0x01}},
+		{Addr: DefaultAddress, W: []byte{0x80, 0x0D, 0x1A, 0x01}},
+		// No data yet.
+		{Addr: DefaultAddress, W: []byte{0x80, 0x00}, R: []byte{0x00, 0x00}},
+	}
+	for _, page := range []byte{1, 0} {
+		ops = append(ops,
+			i2ctest.IO{Addr: DefaultAddress, W: []byte{0x80, 0x00}, R: []byte{0x00, 0x08 | page}},
+			i2ctest.IO{Addr: DefaultAddress, W: []byte{0x80, 0x00, 0x00, 0x30}},
+			i2ctest.IO{Addr: DefaultAddress, W: []byte{0x04, 0x00}, R: words(ram[:])},
+			i2ctest.IO{Addr: DefaultAddress, W: []byte{0x80, 0x0D}, R: []byte{0x1A, 0x01}},
+		)
+	}
+	bus := i2ctest.Playback{Ops: ops}
+	d, err := NewI2C(&bus, DefaultAddress, &Opts{RefreshRate: Refresh8Hz})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := d.String(); s != "MLX90640{playback(51)}" {
+		t.Fatal(s)
+	}
+	var f Frame
+	if err := d.ReadFrame(&f); err != nil {
+		t.Fatal(err)
+	}
+	if len(slept) != 1 || slept[0] != 12500*time.Microsecond {
+		t.Fatalf("slept %v", slept)
+	}
+	if c := f.Ambient.Celsius(); c < 39 || c > 40 {
+		t.Fatalf("Ambient = %s", f.Ambient)
+	}
+	for i, p := range f.Pixels {
+		if p < f.Ambient || p > f.Ambient+physic.Kelvin {
+			t.Fatalf("Pixels[%d] = %s", i, p)
+		}
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Halt(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadFrame_timeout(t *testing.T) {
+	defer func(f func(time.Duration)) { doSleep = f }(doSleep)
+	doSleep = func(time.Duration) {}
+
+	bus := i2ctest.Playback{}
+	for i := 0; i <= maxPolls; i++ {
+		bus.Ops = append(bus.Ops, i2ctest.IO{Addr: DefaultAddress, W: []byte{0x80, 0x00}, R: []byte{0x00, 0x00}})
+	}
+	d := Dev{c: i2c.Dev{Bus: &bus, Addr: DefaultAddress}}
+	if err := d.ReadFrame(&Frame{}); err != errTimeout {
+		t.Fatalf("ReadFrame() = %v, want %v", err, errTimeout)
+	}
+}
+
+func TestNewI2C_opts(t *testing.T) {
+	if _, err := NewI2C(&i2ctest.Playback{}, DefaultAddress, &Opts{RefreshRate: 8}); err == nil {
+		t.Fatal("invalid refresh rate")
+	}
+	if _, err := NewI2C(&i2ctest.Playback{}, DefaultAddress, &Opts{Emissivity: 2}); err == nil {
+		t.Fatal("invalid emissivity")
+	}
+	if s := RefreshRate(8).String(); s != "RefreshRate(8)" {
+		t.Fatal(s)
+	}
+	if s := Refresh0_5Hz.String(); s != "0.5Hz" {
+		t.Fatal(s)
+	}
+}
+
+func TestImage(t *testing.T) {
+	var f Frame
+	for i := range f.Pixels {
+		f.Pixels[i] = physic.ZeroCelsius + physic.Temperature(i)*10*physic.MilliKelvin
+	}
+	img := f.Image()
+	if img.Min != f.Pixels[0] || img.Max != f.Pixels[767] {
+		t.Fatalf("range %s %s", img.Min, img.Max)
+	}
+	if y := img.Gray16At(0, 0).Y; y != 0 {
+		t.Fatal(y)
+	}
+	if y := img.Gray16At(Width-1, Height-1).Y; y != 0xFFFF {
+		t.Fatal(y)
+	}
+	if d := img.Temperature(5, 3) - f.At(5, 3); d < -physic.MilliKelvin || d > physic.MilliKelvin {
+		t.Fatalf("Temperature() = %s, want %s", img.Temperature(5, 3), f.At(5, 3))
+	}
+
+	img = f.ImageRange(physic.ZeroCelsius+physic.Kelvin, physic.ZeroCelsius+2*physic.Kelvin)
+	if y := img.Gray16At(0, 0).Y; y != 0 {
+		t.Fatal(y)
+	}
+	if y := img.Gray16At(Width-1, Height-1).Y; y != 0xFFFF {
+		t.Fatal(y)
+	}
+
+	// A uniform frame doesn't divide by zero.
+	img = (&Frame{}).Image()
+	if img.Max <= img.Min {
+		t.Fatal("invalid range")
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package mpu6050
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/physic"
+)
+
+// ak8963 is the magnetometer of the MPU-9250, accessed through the I²C
+// bypass.
+type ak8963 struct {
+	c i2c.Dev
+	// adj is the sensitivity adjustment of each axis read from the fuse ROM,
+	// in 1/256.
+	adj [3]int64
+}
+
+// AK8963 registers.
+const (
+	akAddr  = 0x0C
+	akWIA   = 0x00
+	akST1   = 0x02
+	akHXL   = 0x03
+	akCNTL1 = 0x0A
+	akASAX  = 0x10
+
+	akDeviceID = 0x48
+	// akPowerDown, akFuseROM and akContinuous2 are CNTL1 modes. Continuous
+	// mode 2 samples at 100Hz, the 0x10 bit selects the 16 bits output.
+	akPowerDown   = 0x00
+	akFuseROM     = 0x0F
+	akContinuous2 = 0x16
+	akST2Overflow = 0x08
+)
+
+func newAK8963(bus i2c.Bus) (*ak8963, error) {
+	m := &ak8963{c: i2c.Dev{Bus: bus, Addr: akAddr}}
+	var b [3]byte
+	if err := m.c.Tx([]byte{akWIA}, b[:1]); err != nil {
+		return nil, err
+	}
+	if b[0] != akDeviceID {
+		return nil, fmt.Errorf("mpu6050: unexpected magnetometer ID %#x", b[0])
+	}
+	// Read the sensitivity adjustment values from the fuse ROM.
+	if err := m.setMode(akFuseROM); err != nil {
+		return nil, err
+	}
+	if err := m.c.Tx([]byte{akASAX}, b[:]); err != nil {
+		return nil, err
+	}
+	for i, v := range b {
+		// Hadj = H * ((ASA - 128) / 256 + 1)
+		m.adj[i] = int64(v) + 128
+	}
+	if err := m.setMode(akPowerDown); err != nil {
+		return nil, err
+	}
+	if err := m.setMode(akContinuous2); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// setMode changes the mode. The datasheet requires 100µs between mode
+// changes.
+func (m *ak8963) setMode(mode byte) error {
+	if err := m.c.Tx([]byte{akCNTL1, mode}, nil); err != nil {
+		return err
+	}
+	sleep(100 * time.Microsecond)
+	return nil
+}
+
+// sense reads the last measurement. ST1 to ST2 are read in one transaction
+// since reading ST2 unlocks the next measurement.
+func (m *ak8963) sense(out *[3]physic.MagneticFluxDensity) error {
+	var b [8]byte
+	if err := m.c.Tx([]byte{akST1}, b[:]); err != nil {
+		return err
+	}
+	if b[7]&akST2Overflow != 0 {
+		return fmt.Errorf("mpu6050: magnetometer overflow")
+	}
+	for i := 0; i < 3; i++ {
+		// 0.15µT/LSB in 16 bits mode.
+		v := int64(int16(binary.LittleEndian.Uint16(b[1+2*i:])))
+		out[i] = physic.MagneticFluxDensity(v * 150 * m.adj[i] / 256)
+	}
+	return nil
+}
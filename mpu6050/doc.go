@@ -0,0 +1,24 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package mpu6050 controls the InvenSense MPU-6050, MPU-6500 and MPU-9250
+// inertial measurement units over I²C.
+//
+// The accelerometer, gyroscope and temperature readings are converted to
+// physical units, and on the MPU-9250 the AK8963 magnetometer is accessed
+// through the I²C bypass. The digital low pass filter, sample rate and FIFO
+// are configurable, and ComplementaryFilter estimates the orientation from
+// the samples.
+//
+// Package mpu9250 provides lower level register access to the MPU-9250 over
+// SPI.
+//
+// # Datasheet
+//
+// https://invensense.tdk.com/wp-content/uploads/2015/02/MPU-6000-Register-Map1.pdf
+//
+// https://invensense.tdk.com/wp-content/uploads/2015/02/RM-MPU-9250A-00-v1.6.pdf
+//
+// https://www.akm.com/content/dam/documents/products/electronic-compass/ak8963c/ak8963c-en-datasheet.pdf
+package mpu6050
@@ -0,0 +1,51 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package mpu6050_test
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"periph.io/x/conn/v3/i2c/i2creg"
+	"periph.io/x/devices/v3/mpu6050"
+	"periph.io/x/host/v3"
+)
+
+func Example() {
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Open default I²C bus.
+	bus, err := i2creg.Open("")
+	if err != nil {
+		log.Fatalf("failed to open I²C: %v", err)
+	}
+	defer bus.Close()
+
+	d, err := mpu6050.New(bus, &mpu6050.DefaultOpts)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer d.Halt()
+
+	f := mpu6050.ComplementaryFilter{Alpha: 0.98}
+	const interval = 10 * time.Millisecond
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for i := 0; i < 500; i++ {
+		<-t.C
+		var s mpu6050.Sample
+		if err := d.Sense(&s); err != nil {
+			log.Fatalln(err)
+		}
+		f.Update(&s, interval)
+		if i%50 == 0 {
+			fmt.Printf("roll: %s pitch: %s temperature: %s\n", f.Roll, f.Pitch, s.Temperature)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package mpu6050
+
+import (
+	"math"
+	"time"
+
+	"periph.io/x/conn/v3/physic"
+)
+
+// ComplementaryFilter estimates the roll and pitch of the device by fusing
+// the integrated gyroscope rates, which are accurate in the short term but
+// drift, with the angles derived from gravity, which are noisy but stable.
+//
+// The zero value is not usable, Alpha must be set.
+type ComplementaryFilter struct {
+	// Alpha is the weight of the gyroscope, typically 0.95 to 0.98.
+	Alpha float64
+	// Roll is the rotation around the X axis.
+	Roll physic.Angle
+	// Pitch is the rotation around the Y axis.
+	Pitch physic.Angle
+
+	initialized bool
+}
+
+// Update updates Roll and Pitch with a sample taken dt after the previous
+// one.
+func (f *ComplementaryFilter) Update(s *Sample, dt time.Duration) {
+	ax := float64(s.Acceleration[0])
+	ay := float64(s.Acceleration[1])
+	az := float64(s.Acceleration[2])
+	roll := math.Atan2(ay, az)
+	pitch := math.Atan2(-ax, math.Sqrt(ay*ay+az*az))
+	if !f.initialized {
+		f.Roll = toAngle(roll)
+		f.Pitch = toAngle(pitch)
+		f.initialized = true
+		return
+	}
+	sec := dt.Seconds()
+	gRoll := fromAngle(f.Roll) + fromAngle(s.AngularRate[0])*sec
+	gPitch := fromAngle(f.Pitch) + fromAngle(s.AngularRate[1])*sec
+	f.Roll = toAngle(f.Alpha*gRoll + (1-f.Alpha)*roll)
+	f.Pitch = toAngle(f.Alpha*gPitch + (1-f.Alpha)*pitch)
+}
+
+func toAngle(rad float64) physic.Angle {
+	return physic.Angle(math.Round(rad * float64(physic.Radian)))
+}
+
+func fromAngle(a physic.Angle) float64 {
+	return float64(a) / float64(physic.Radian)
+}
@@ -0,0 +1,423 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package mpu6050
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/physic"
+)
+
+// Variant is the detected device.
+type Variant string
+
+const (
+	MPU6050 Variant = "MPU6050"
+	MPU6500 Variant = "MPU6500"
+	MPU9250 Variant = "MPU9250"
+)
+
+// AccelRange is the full scale range of the accelerometer.
+type AccelRange byte
+
+const (
+	Accel2G  AccelRange = 0 // Default
+	Accel4G  AccelRange = 1
+	Accel8G  AccelRange = 2
+	Accel16G AccelRange = 3
+)
+
+// GyroRange is the full scale range of the gyroscope.
+type GyroRange byte
+
+const (
+	Gyro250DPS  GyroRange = 0 // Default
+	Gyro500DPS  GyroRange = 1
+	Gyro1000DPS GyroRange = 2
+	Gyro2000DPS GyroRange = 3
+)
+
+// DLPF is the digital low pass filter configuration, which sets the bandwidth
+// of the gyroscope and the accelerometer.
+type DLPF byte
+
+const (
+	// DLPFOff disables the filter, the gyroscope is sampled at 8kHz.
+	DLPFOff   DLPF = 0
+	DLPF184Hz DLPF = 1
+	DLPF92Hz  DLPF = 2
+	DLPF41Hz  DLPF = 3
+	DLPF20Hz  DLPF = 4
+	DLPF10Hz  DLPF = 5
+	DLPF5Hz   DLPF = 6
+)
+
+// Acceleration is an acceleration stored as an int64 micro metre per second
+// squared.
+//
+// There is no acceleration unit in periph.io/x/conn/v3/physic.
+type Acceleration int64
+
+const (
+	MicroMetrePerSecond2 Acceleration = 1
+	MilliMetrePerSecond2 Acceleration = 1000 * MicroMetrePerSecond2
+	MetrePerSecond2      Acceleration = 1000 * MilliMetrePerSecond2
+
+	// StandardGravity is the standard acceleration due to gravity.
+	StandardGravity Acceleration = 9806650 * MicroMetrePerSecond2
+)
+
+// String returns the acceleration in m/s² with a mm/s² resolution.
+func (a Acceleration) String() string {
+	return strconv.FormatFloat(float64(a/MilliMetrePerSecond2)/1e3, 'f', -1, 64) + "m/s²"
+}
+
+// Sample is a measurement of the device.
+type Sample struct {
+	// Acceleration on the X, Y and Z axes.
+	Acceleration [3]Acceleration
+	// AngularRate is the rotation speed around the X, Y and Z axes, per
+	// second.
+	AngularRate [3]physic.Angle
+	// Temperature of the die. It is not set by ReadFIFO.
+	Temperature physic.Temperature
+	// Magnetic is the magnetic flux density on the X, Y and Z axes of the
+	// magnetometer. It is only set when Opts.Magnetometer is enabled. Note
+	// that the axes of the magnetometer are not aligned with the others: its
+	// X axis is the Y axis of the accelerometer and its Z axis is inverted.
+	Magnetic [3]physic.MagneticFluxDensity
+}
+
+// Opts holds the configuration options.
+type Opts struct {
+	// Address is the I²C address, 0x68 or 0x69. 0 means 0x68.
+	Address uint16
+	// AccelRange is the accelerometer full scale range.
+	AccelRange AccelRange
+	// GyroRange is the gyroscope full scale range.
+	GyroRange GyroRange
+	// DLPF is the low pass filter configuration.
+	DLPF DLPF
+	// SampleRate is the rate at which the data and FIFO registers are
+	// updated. 0 means the gyroscope output rate: 8kHz with DLPFOff, 1kHz
+	// otherwise.
+	SampleRate physic.Frequency
+	// Magnetometer enables the AK8963 magnetometer of the MPU-9250.
+	Magnetometer bool
+}
+
+// DefaultOpts is the recommended default options.
+var DefaultOpts = Opts{
+	Address:    0x68,
+	DLPF:       DLPF41Hz,
+	SampleRate: 100 * physic.Hertz,
+}
+
+// Dev is a handle to an MPU-6050, MPU-6500 or MPU-9250.
+type Dev struct {
+	mu      sync.Mutex
+	c       i2c.Dev
+	variant Variant
+	accel   AccelRange
+	gyro    GyroRange
+	dlpf    DLPF
+	mag     *ak8963
+}
+
+// New opens a handle to the device, resets it and applies opts.
+func New(bus i2c.Bus, opts *Opts) (*Dev, error) {
+	addr := opts.Address
+	if addr == 0 {
+		addr = DefaultOpts.Address
+	}
+	if addr != 0x68 && addr != 0x69 {
+		return nil, errAddressOutOfRange
+	}
+	d := &Dev{c: i2c.Dev{Bus: bus, Addr: addr}}
+	id, err := d.readReg(whoAmI)
+	if err != nil {
+		return nil, err
+	}
+	switch id {
+	case 0x68:
+		d.variant = MPU6050
+	case 0x70:
+		d.variant = MPU6500
+	case 0x71, 0x73:
+		d.variant = MPU9250
+	default:
+		return nil, fmt.Errorf("mpu6050: unexpected device ID %#x", id)
+	}
+	if opts.Magnetometer && d.variant != MPU9250 {
+		return nil, errNoMagnetometer
+	}
+	if err := d.writeReg(pwrMgmt1, pwrReset); err != nil {
+		return nil, err
+	}
+	sleep(100 * time.Millisecond)
+	// Wake up and use the gyroscope PLL as clock source.
+	if err := d.writeReg(pwrMgmt1, 0x01); err != nil {
+		return nil, err
+	}
+	if err := d.SetAccelRange(opts.AccelRange); err != nil {
+		return nil, err
+	}
+	if err := d.SetGyroRange(opts.GyroRange); err != nil {
+		return nil, err
+	}
+	if err := d.SetDLPF(opts.DLPF); err != nil {
+		return nil, err
+	}
+	if opts.SampleRate != 0 {
+		if err := d.SetSampleRate(opts.SampleRate); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Magnetometer {
+		// Expose the auxiliary I²C bus on the host bus.
+		if err := d.writeReg(intPinCfg, intPinBypass); err != nil {
+			return nil, err
+		}
+		if d.mag, err = newAK8963(bus); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+// Variant returns the detected device.
+func (d *Dev) Variant() Variant {
+	return d.variant
+}
+
+func (d *Dev) String() string {
+	return fmt.Sprintf("%s{%s}", d.variant, &d.c)
+}
+
+// SetAccelRange sets the accelerometer full scale range.
+func (d *Dev) SetAccelRange(r AccelRange) error {
+	if r > Accel16G {
+		return errors.New("mpu6050: invalid accelerometer range")
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.writeReg(accelConfig, byte(r)<<3); err != nil {
+		return err
+	}
+	d.accel = r
+	return nil
+}
+
+// SetGyroRange sets the gyroscope full scale range.
+func (d *Dev) SetGyroRange(r GyroRange) error {
+	if r > Gyro2000DPS {
+		return errors.New("mpu6050: invalid gyroscope range")
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.writeReg(gyroConfig, byte(r)<<3); err != nil {
+		return err
+	}
+	d.gyro = r
+	return nil
+}
+
+// SetDLPF sets the digital low pass filter of the gyroscope and the
+// accelerometer.
+func (d *Dev) SetDLPF(f DLPF) error {
+	if f > DLPF5Hz {
+		return errors.New("mpu6050: invalid low pass filter")
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.writeReg(config, byte(f)); err != nil {
+		return err
+	}
+	if d.variant != MPU6050 {
+		// The MPU-6500 has a separate accelerometer filter.
+		a := byte(f)
+		if f == DLPFOff {
+			// Bypass the accelerometer filter too.
+			a = 0x08
+		}
+		if err := d.writeReg(accelConfig2, a); err != nil {
+			return err
+		}
+	}
+	d.dlpf = f
+	return nil
+}
+
+// SetSampleRate sets the rate at which the data and FIFO registers are
+// updated. It is derived from the gyroscope output rate, 8kHz with DLPFOff and
+// 1kHz otherwise, so the closest achievable rate is used.
+func (d *Dev) SetSampleRate(f physic.Frequency) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	base := physic.KiloHertz
+	if d.dlpf == DLPFOff {
+		base = 8 * physic.KiloHertz
+	}
+	if f <= 0 || f > base {
+		return errors.New("mpu6050: sample rate out of range")
+	}
+	div := (base + f/2) / f
+	if div > 256 {
+		return errors.New("mpu6050: sample rate out of range")
+	}
+	return d.writeReg(smplrtDiv, byte(div-1))
+}
+
+// Sense reads a measurement.
+func (d *Dev) Sense(s *Sample) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var b [14]byte
+	if err := d.c.Tx([]byte{accelXoutH}, b[:]); err != nil {
+		return err
+	}
+	d.decode(s, b[0:6], b[8:14])
+	t := int64(int16(binary.BigEndian.Uint16(b[6:])))
+	if d.variant == MPU6050 {
+		// Temperature in °C = raw / 340 + 36.53
+		s.Temperature = physic.ZeroCelsius + 36530*physic.MilliCelsius + physic.Temperature(t)*physic.Celsius/340
+	} else {
+		// Temperature in °C = raw / 333.87 + 21
+		s.Temperature = physic.ZeroCelsius + 21*physic.Celsius + physic.Temperature(t)*physic.Celsius*100/33387
+	}
+	if d.mag != nil {
+		if err := d.mag.sense(&s.Magnetic); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EnableFIFO resets the FIFO and enables or disables the collection of the
+// accelerometer and gyroscope samples in it, at the sample rate.
+func (d *Dev) EnableFIFO(enable bool) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.writeReg(fifoEn, 0); err != nil {
+		return err
+	}
+	if err := d.writeReg(userCtrl, userCtrlFIFOReset); err != nil {
+		return err
+	}
+	if !enable {
+		return nil
+	}
+	if err := d.writeReg(userCtrl, userCtrlFIFOEn); err != nil {
+		return err
+	}
+	return d.writeReg(fifoEn, fifoEnGyro|fifoEnAccel)
+}
+
+// ReadFIFO returns the complete samples held in the FIFO, oldest first.
+//
+// The FIFO holds 1024 bytes, or 85 samples, on the MPU-6050 and 512 bytes on
+// the others; samples are lost if it is not read often enough.
+func (d *Dev) ReadFIFO() ([]Sample, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var c [2]byte
+	if err := d.c.Tx([]byte{fifoCountH}, c[:]); err != nil {
+		return nil, err
+	}
+	n := int(binary.BigEndian.Uint16(c[:])&0x1FFF) / fifoSampleSize
+	if n == 0 {
+		return nil, nil
+	}
+	b := make([]byte, n*fifoSampleSize)
+	if err := d.c.Tx([]byte{fifoRW}, b); err != nil {
+		return nil, err
+	}
+	out := make([]Sample, n)
+	for i := range out {
+		p := b[i*fifoSampleSize:]
+		d.decode(&out[i], p[0:6], p[6:12])
+	}
+	return out, nil
+}
+
+// Halt puts the device to sleep. Calling Sense or ReadFIFO afterward returns
+// stale data.
+func (d *Dev) Halt() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.writeReg(pwrMgmt1, pwrSleep|0x01)
+}
+
+//
+
+// Registers.
+const (
+	smplrtDiv    = 0x19
+	config       = 0x1A
+	gyroConfig   = 0x1B
+	accelConfig  = 0x1C
+	accelConfig2 = 0x1D
+	fifoEn       = 0x23
+	intPinCfg    = 0x37
+	accelXoutH   = 0x3B
+	userCtrl     = 0x6A
+	pwrMgmt1     = 0x6B
+	fifoCountH   = 0x72
+	fifoRW       = 0x74
+	whoAmI       = 0x75
+)
+
+// Register bits.
+const (
+	fifoEnGyro        = 0x70
+	fifoEnAccel       = 0x08
+	intPinBypass      = 0x02
+	userCtrlFIFOEn    = 0x40
+	userCtrlFIFOReset = 0x04
+	pwrReset          = 0x80
+	pwrSleep          = 0x40
+)
+
+// fifoSampleSize is the size of a sample in the FIFO: accelerometer then
+// gyroscope.
+const fifoSampleSize = 12
+
+// gyroLSB10 is ten times the gyroscope sensitivity in LSB/(°/s), per range.
+var gyroLSB10 = [...]int64{1310, 655, 328, 164}
+
+var sleep = time.Sleep
+
+var (
+	errAddressOutOfRange = errors.New("mpu6050: i2c address out of range")
+	errNoMagnetometer    = errors.New("mpu6050: magnetometer is only available on the MPU-9250")
+)
+
+// decode converts the raw accelerometer and gyroscope registers.
+func (d *Dev) decode(s *Sample, accel, gyro []byte) {
+	// 16384 LSB/g at ±2g, halved for each range.
+	accelLSB := int64(16384) >> d.accel
+	for i := 0; i < 3; i++ {
+		a := int64(int16(binary.BigEndian.Uint16(accel[2*i:])))
+		s.Acceleration[i] = Acceleration(a * int64(StandardGravity) / accelLSB)
+		g := int64(int16(binary.BigEndian.Uint16(gyro[2*i:])))
+		s.AngularRate[i] = physic.Angle(g * int64(physic.Degree) * 10 / gyroLSB10[d.gyro])
+	}
+}
+
+func (d *Dev) readReg(reg byte) (byte, error) {
+	var b [1]byte
+	err := d.c.Tx([]byte{reg}, b[:])
+	return b[0], err
+}
+
+func (d *Dev) writeReg(reg, v byte) error {
+	return d.c.Tx([]byte{reg, v}, nil)
+}
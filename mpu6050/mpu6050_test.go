@@ -0,0 +1,213 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package mpu6050
+
+import (
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3/i2c/i2ctest"
+	"periph.io/x/conn/v3/physic"
+)
+
+func init() {
+	sleep = func(time.Duration) {}
+}
+
+var initMPU6050 = []i2ctest.IO{
+	{Addr: 0x68, W: []byte{whoAmI}, R: []byte{0x68}},
+	{Addr: 0x68, W: []byte{pwrMgmt1, 0x80}},
+	{Addr: 0x68, W: []byte{pwrMgmt1, 0x01}},
+	{Addr: 0x68, W: []byte{accelConfig, 0x00}},
+	{Addr: 0x68, W: []byte{gyroConfig, 0x00}},
+	{Addr: 0x68, W: []byte{config, 0x03}},
+	{Addr: 0x68, W: []byte{smplrtDiv, 9}},
+}
+
+func TestNew_MPU6050(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: append(initMPU6050,
+			i2ctest.IO{Addr: 0x68, W: []byte{accelXoutH}, R: []byte{
+				0x40, 0x00, 0x00, 0x00, 0xC0, 0x00, // accel
+				0x00, 0x00, // temperature
+				0x00, 0x83, 0xFF, 0x7D, 0x00, 0x00, // gyro
+			}},
+			i2ctest.IO{Addr: 0x68, W: []byte{pwrMgmt1, 0x41}},
+		),
+	}
+	d, err := New(&bus, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := d.Variant(); v != MPU6050 {
+		t.Fatal(v)
+	}
+	var s Sample
+	if err := d.Sense(&s); err != nil {
+		t.Fatal(err)
+	}
+	want := Sample{
+		Acceleration: [3]Acceleration{StandardGravity, 0, -StandardGravity},
+		AngularRate:  [3]physic.Angle{physic.Degree, -physic.Degree, 0},
+		Temperature:  physic.ZeroCelsius + 36530*physic.MilliCelsius,
+	}
+	if s != want {
+		t.Fatalf("got %+v, want %+v", s, want)
+	}
+	if err := d.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNew_MPU9250(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x69, W: []byte{whoAmI}, R: []byte{0x71}},
+			{Addr: 0x69, W: []byte{pwrMgmt1, 0x80}},
+			{Addr: 0x69, W: []byte{pwrMgmt1, 0x01}},
+			{Addr: 0x69, W: []byte{accelConfig, 0x18}},
+			{Addr: 0x69, W: []byte{gyroConfig, 0x08}},
+			{Addr: 0x69, W: []byte{config, 0x00}},
+			{Addr: 0x69, W: []byte{accelConfig2, 0x08}},
+			{Addr: 0x69, W: []byte{intPinCfg, 0x02}},
+			{Addr: akAddr, W: []byte{akWIA}, R: []byte{0x48}},
+			{Addr: akAddr, W: []byte{akCNTL1, akFuseROM}},
+			{Addr: akAddr, W: []byte{akASAX}, R: []byte{128, 128, 192}},
+			{Addr: akAddr, W: []byte{akCNTL1, akPowerDown}},
+			{Addr: akAddr, W: []byte{akCNTL1, akContinuous2}},
+			{Addr: 0x69, W: []byte{accelXoutH}, R: []byte{
+				0x08, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x01, 0x06,
+			}},
+			{Addr: akAddr, W: []byte{akST1}, R: []byte{0x01, 0x64, 0x00, 0x9C, 0xFF, 0x64, 0x00, 0x10}},
+		},
+	}
+	opts := Opts{Address: 0x69, AccelRange: Accel16G, GyroRange: Gyro500DPS, Magnetometer: true}
+	d, err := New(&bus, &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s Sample
+	if err := d.Sense(&s); err != nil {
+		t.Fatal(err)
+	}
+	want := Sample{
+		Acceleration: [3]Acceleration{StandardGravity, 0, 0},
+		AngularRate:  [3]physic.Angle{0, 0, 4 * physic.Degree},
+		Temperature:  physic.ZeroCelsius + 21*physic.Celsius,
+		Magnetic:     [3]physic.MagneticFluxDensity{15 * physic.MicroTesla, -15 * physic.MicroTesla, 18750 * physic.NanoTesla},
+	}
+	if s != want {
+		t.Fatalf("got %+v, want %+v", s, want)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNew_errors(t *testing.T) {
+	if _, err := New(&i2ctest.Playback{}, &Opts{Address: 0x20}); err == nil {
+		t.Fatal("invalid address")
+	}
+	bus := i2ctest.Playback{Ops: []i2ctest.IO{{Addr: 0x68, W: []byte{whoAmI}, R: []byte{0x68}}}}
+	if _, err := New(&bus, &Opts{Magnetometer: true}); err != errNoMagnetometer {
+		t.Fatal(err)
+	}
+	bus = i2ctest.Playback{Ops: []i2ctest.IO{{Addr: 0x68, W: []byte{whoAmI}, R: []byte{0x12}}}}
+	if _, err := New(&bus, &DefaultOpts); err == nil {
+		t.Fatal("unexpected device")
+	}
+}
+
+func TestFIFO(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: append(initMPU6050,
+			i2ctest.IO{Addr: 0x68, W: []byte{fifoEn, 0}},
+			i2ctest.IO{Addr: 0x68, W: []byte{userCtrl, 0x04}},
+			i2ctest.IO{Addr: 0x68, W: []byte{userCtrl, 0x40}},
+			i2ctest.IO{Addr: 0x68, W: []byte{fifoEn, 0x78}},
+			// 2 complete samples and a partial one.
+			i2ctest.IO{Addr: 0x68, W: []byte{fifoCountH}, R: []byte{0x00, 30}},
+			i2ctest.IO{Addr: 0x68, W: []byte{fifoRW}, R: []byte{
+				0x40, 0x00, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+				0, 0, 0, 0, 0, 0, 0x00, 0x83, 0, 0, 0, 0,
+			}},
+		),
+	}
+	d, err := New(&bus, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.EnableFIFO(true); err != nil {
+		t.Fatal(err)
+	}
+	s, err := d.ReadFIFO()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s) != 2 || s[0].Acceleration[0] != StandardGravity || s[1].AngularRate[0] != physic.Degree {
+		t.Fatalf("%+v", s)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSetSampleRate(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: append(initMPU6050,
+			i2ctest.IO{Addr: 0x68, W: []byte{smplrtDiv, 4}},
+		),
+	}
+	d, err := New(&bus, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetSampleRate(200 * physic.Hertz); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetSampleRate(2 * physic.Hertz); err == nil {
+		t.Fatal("too slow")
+	}
+	if err := d.SetSampleRate(2 * physic.KiloHertz); err == nil {
+		t.Fatal("too fast with the low pass filter enabled")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestComplementaryFilter(t *testing.T) {
+	f := ComplementaryFilter{Alpha: 0.98}
+	// Lying flat.
+	s := Sample{Acceleration: [3]Acceleration{0, 0, StandardGravity}}
+	f.Update(&s, 0)
+	if f.Roll != 0 || f.Pitch != 0 {
+		t.Fatal(f.Roll, f.Pitch)
+	}
+	// Rotating at 10°/s around X for 100ms, gravity not yet reflecting it.
+	s.AngularRate[0] = 10 * physic.Degree
+	f.Update(&s, 100*time.Millisecond)
+	if d := f.Roll - 980*physic.Degree/1000; d < -physic.MicroRadian || d > physic.MicroRadian {
+		t.Fatal(f.Roll)
+	}
+	// Tilted by 90° around Y.
+	f = ComplementaryFilter{Alpha: 0.98}
+	s = Sample{Acceleration: [3]Acceleration{-StandardGravity, 0, 0}}
+	f.Update(&s, 0)
+	if d := f.Pitch - 90*physic.Degree; d < -physic.MicroRadian || d > physic.MicroRadian {
+		t.Fatal(f.Pitch)
+	}
+}
+
+func TestAcceleration_String(t *testing.T) {
+	if s := StandardGravity.String(); s != "9.806m/s²" {
+		t.Fatal(s)
+	}
+}
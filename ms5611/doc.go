@@ -0,0 +1,24 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package ms5611 controls a TE Connectivity MS5611-01BA03 barometric
+// pressure sensor over I²C.
+//
+// The sensor has a resolution of 10cm of altitude at the highest
+// oversampling ratio, which makes it popular on flight controllers. The
+// factory calibration is read from the PROM and checked with its CRC on
+// initialization, and the second order temperature compensation is applied
+// below 20°C.
+//
+// Altitude and SeaLevelPressure convert between pressure and altitude with
+// the international barometric formula.
+//
+// # Datasheet
+//
+// https://www.te.com/commerce/DocumentDelivery/DDEController?Action=showdoc&DocId=Data+Sheet%7FMS5611-01BA03%7FB3%7Fpdf%7FEnglish%7FENG_DS_MS5611-01BA03_B3.pdf
+//
+// # Application note AN520, PROM CRC
+//
+// https://www.te.com/commerce/DocumentDelivery/DDEController?Action=showdoc&DocId=Specification+Or+Standard%7FAN520%7FA%7Fpdf%7FEnglish%7FENG_SS_AN520_A.pdf
+package ms5611
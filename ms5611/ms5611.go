@@ -0,0 +1,318 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ms5611
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/physic"
+)
+
+// OSR is the oversampling ratio of a conversion. A higher ratio lowers the
+// noise but takes longer.
+type OSR uint8
+
+const (
+	OSR256  OSR = 0x00
+	OSR512  OSR = 0x02
+	OSR1024 OSR = 0x04
+	OSR2048 OSR = 0x06
+	OSR4096 OSR = 0x08
+)
+
+func (o OSR) String() string {
+	if !o.valid() {
+		return fmt.Sprintf("OSR(%d)", o)
+	}
+	return fmt.Sprintf("OSR%d", 256<<(o/2))
+}
+
+func (o OSR) valid() bool {
+	return o <= OSR4096 && o&1 == 0
+}
+
+// conversionTime is the maximum conversion time for each OSR.
+func (o OSR) conversionTime() time.Duration {
+	return [...]time.Duration{
+		600 * time.Microsecond,
+		1170 * time.Microsecond,
+		2280 * time.Microsecond,
+		4540 * time.Microsecond,
+		9040 * time.Microsecond,
+	}[o/2]
+}
+
+// StandardSeaLevel is the standard atmospheric pressure at sea level.
+const StandardSeaLevel = 101325 * physic.Pascal
+
+// Altitude returns the altitude at which the pressure is p, given the
+// pressure at sea level p0, in the standard atmosphere.
+func Altitude(p, p0 physic.Pressure) physic.Distance {
+	h := 44330 * (1 - math.Pow(float64(p)/float64(p0), 1/5.255))
+	return physic.Distance(h * float64(physic.Metre))
+}
+
+// SeaLevelPressure returns the pressure at sea level given the pressure p
+// measured at a known altitude h. Use it to calibrate Altitude.
+func SeaLevelPressure(p physic.Pressure, h physic.Distance) physic.Pressure {
+	r := math.Pow(1-float64(h)/float64(physic.Metre)/44330, 5.255)
+	return physic.Pressure(float64(p) / r)
+}
+
+// Opts holds the configuration options.
+type Opts struct {
+	// Temperature and Pressure are the oversampling ratios of each
+	// conversion.
+	Temperature OSR
+	Pressure    OSR
+}
+
+// DefaultOpts is the recommended default options.
+var DefaultOpts = Opts{
+	Temperature: OSR1024,
+	Pressure:    OSR4096,
+}
+
+// Dev is a handle to a MS5611.
+type Dev struct {
+	c    i2c.Dev
+	opts Opts
+	// prom is the factory data and the calibration coefficients C1 to C6,
+	// followed by the CRC.
+	prom [8]uint16
+
+	mu   sync.Mutex
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewI2C returns a handle to a MS5611 at address addr, 0x77 when CSB is low
+// or 0x76 when it's high.
+//
+// The device is reset and its calibration read.
+func NewI2C(b i2c.Bus, addr uint16, opts *Opts) (*Dev, error) {
+	switch addr {
+	case 0x76, 0x77:
+	default:
+		return nil, errors.New("ms5611: given address not supported by device")
+	}
+	if !opts.Temperature.valid() || !opts.Pressure.valid() {
+		return nil, errors.New("ms5611: invalid oversampling ratio")
+	}
+	d := &Dev{c: i2c.Dev{Bus: b, Addr: addr}, opts: *opts}
+	if err := d.c.Tx([]byte{cmdReset}, nil); err != nil {
+		return nil, fmt.Errorf("ms5611: %v", err)
+	}
+	doSleep(resetTime)
+	var buf [2]byte
+	for i := range d.prom {
+		if err := d.c.Tx([]byte{cmdPROMRead + byte(2*i)}, buf[:]); err != nil {
+			return nil, fmt.Errorf("ms5611: %v", err)
+		}
+		d.prom[i] = binary.BigEndian.Uint16(buf[:])
+	}
+	if c := crc4(d.prom); c != byte(d.prom[7]&0xF) {
+		return nil, fmt.Errorf("ms5611: invalid PROM CRC %#x, expected %#x", d.prom[7]&0xF, c)
+	}
+	return d, nil
+}
+
+func (d *Dev) String() string {
+	return fmt.Sprintf("MS5611{%s}", &d.c)
+}
+
+// Sense requests a one time measurement of the temperature and the
+// pressure.
+func (d *Dev) Sense(e *physic.Env) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.stop != nil {
+		return errors.New("ms5611: already sensing continuously")
+	}
+	return d.sense(e)
+}
+
+// SenseContinuous returns measurements on a continuous basis.
+//
+// The application must call Halt() to stop the sensing when done and close
+// the channel.
+func (d *Dev) SenseContinuous(interval time.Duration) (<-chan physic.Env, error) {
+	if need := d.opts.Temperature.conversionTime() + d.opts.Pressure.conversionTime(); interval < need {
+		return nil, fmt.Errorf("ms5611: interval must be at least %s", need)
+	}
+	if err := d.Halt(); err != nil {
+		return nil, err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	sensing := make(chan physic.Env)
+	stop := make(chan struct{})
+	d.stop = stop
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		defer close(sensing)
+		d.sensingContinuous(interval, sensing, stop)
+	}()
+	return sensing, nil
+}
+
+// Precision implements physic.SenseEnv.
+//
+// The results are quantized to 0.01°C and 1Pa.
+func (d *Dev) Precision(e *physic.Env) {
+	e.Temperature = 10 * physic.MilliKelvin
+	if d.opts.Temperature == OSR256 {
+		e.Temperature = 12 * physic.MilliKelvin
+	}
+	e.Pressure = [...]physic.Pressure{6500, 4200, 2700, 1800, 1200}[d.opts.Pressure/2] * physic.MilliPascal
+}
+
+// Halt stops continuous sensing. The device is idle between conversions.
+//
+// Halt implements conn.Resource.
+func (d *Dev) Halt() error {
+	d.mu.Lock()
+	if d.stop != nil {
+		close(d.stop)
+		d.stop = nil
+	}
+	d.mu.Unlock()
+	d.wg.Wait()
+	return nil
+}
+
+//
+
+// Commands.
+const (
+	cmdReset     = 0x1E
+	cmdConvertD1 = 0x40
+	cmdConvertD2 = 0x50
+	cmdADCRead   = 0x00
+	cmdPROMRead  = 0xA0
+
+	resetTime = 3 * time.Millisecond
+)
+
+// crc4 computes the CRC of the PROM as per AN520. The CRC itself, in the
+// lowest 4 bits of the last word, is ignored.
+func crc4(prom [8]uint16) byte {
+	prom[7] &= 0xFF00
+	var rem uint16
+	for i := 0; i < 16; i++ {
+		if i&1 == 1 {
+			rem ^= prom[i>>1] & 0xFF
+		} else {
+			rem ^= prom[i>>1] >> 8
+		}
+		for b := 0; b < 8; b++ {
+			if rem&0x8000 != 0 {
+				rem = rem<<1 ^ 0x3000
+			} else {
+				rem <<= 1
+			}
+		}
+	}
+	return byte(rem>>12) & 0xF
+}
+
+func (d *Dev) sense(e *physic.Env) error {
+	d1, err := d.convert(cmdConvertD1, d.opts.Pressure)
+	if err != nil {
+		return err
+	}
+	d2, err := d.convert(cmdConvertD2, d.opts.Temperature)
+	if err != nil {
+		return err
+	}
+	temp, p := d.compensate(d1, d2)
+	e.Temperature = physic.ZeroCelsius + physic.Temperature(temp)*10*physic.MilliKelvin
+	e.Pressure = physic.Pressure(p) * physic.Pascal
+	return nil
+}
+
+// convert starts a conversion and returns the raw 24 bits result.
+func (d *Dev) convert(cmd byte, o OSR) (uint32, error) {
+	if err := d.c.Tx([]byte{cmd | byte(o)}, nil); err != nil {
+		return 0, fmt.Errorf("ms5611: %v", err)
+	}
+	doSleep(o.conversionTime())
+	var b [3]byte
+	if err := d.c.Tx([]byte{cmdADCRead}, b[:]); err != nil {
+		return 0, fmt.Errorf("ms5611: %v", err)
+	}
+	v := uint32(b[0])<<16 | uint32(b[1])<<8 | uint32(b[2])
+	if v == 0 {
+		// The ADC returns 0 when read before the conversion completed.
+		return 0, errors.New("ms5611: conversion not complete")
+	}
+	return v, nil
+}
+
+// compensate returns the temperature in 0.01°C and the pressure in Pa from
+// the raw pressure d1 and temperature d2, as per the datasheet including the
+// second order compensation.
+func (d *Dev) compensate(d1, d2 uint32) (int64, int64) {
+	c := d.prom
+	dT := int64(d2) - int64(c[5])<<8
+	temp := 2000 + dT*int64(c[6])>>23
+	off := int64(c[2])<<16 + int64(c[4])*dT>>7
+	sens := int64(c[1])<<15 + int64(c[3])*dT>>8
+	if temp < 2000 {
+		t2 := dT * dT >> 31
+		x := (temp - 2000) * (temp - 2000)
+		off2 := 5 * x >> 1
+		sens2 := 5 * x >> 2
+		if temp < -1500 {
+			x = (temp + 1500) * (temp + 1500)
+			off2 += 7 * x
+			sens2 += 11 * x >> 1
+		}
+		temp -= t2
+		off -= off2
+		sens -= sens2
+	}
+	p := (int64(d1)*sens>>21 - off) >> 15
+	return temp, p
+}
+
+func (d *Dev) sensingContinuous(interval time.Duration, sensing chan<- physic.Env, stop <-chan struct{}) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		e := physic.Env{}
+		d.mu.Lock()
+		err := d.sense(&e)
+		d.mu.Unlock()
+		if err != nil {
+			log.Printf("%s: failed to sense: %v", d, err)
+			return
+		}
+		select {
+		case sensing <- e:
+		case <-stop:
+			return
+		}
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+		}
+	}
+}
+
+var doSleep = time.Sleep
+
+var _ conn.Resource = &Dev{}
+var _ physic.SenseEnv = &Dev{}
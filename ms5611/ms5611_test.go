@@ -0,0 +1,173 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ms5611
+
+import (
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3/i2c/i2ctest"
+	"periph.io/x/conn/v3/physic"
+)
+
+// prom is the example calibration of the datasheet.
+var prom = [8]uint16{0x0000, 40127, 36924, 23317, 23282, 33464, 28312, 0x0000}
+
+func promOps(p [8]uint16) []i2ctest.IO {
+	ops := []i2ctest.IO{{Addr: 0x77, W: []byte{cmdReset}}}
+	for i, v := range p {
+		ops = append(ops, i2ctest.IO{Addr: 0x77, W: []byte{cmdPROMRead + byte(2*i)}, R: []byte{byte(v >> 8), byte(v)}})
+	}
+	return ops
+}
+
+func validPROM() [8]uint16 {
+	p := prom
+	p[7] |= uint16(crc4(p))
+	return p
+}
+
+func TestNewI2C(t *testing.T) {
+	defer func(f func(time.Duration)) { doSleep = f }(doSleep)
+	doSleep = func(time.Duration) {}
+	if _, err := NewI2C(&i2ctest.Playback{}, 0x10, &DefaultOpts); err == nil {
+		t.Fatal("expected error on address")
+	}
+	if _, err := NewI2C(&i2ctest.Playback{}, 0x77, &Opts{Pressure: OSR(3)}); err == nil {
+		t.Fatal("expected error on OSR")
+	}
+	p := validPROM()
+	p[3]++
+	bus := i2ctest.Playback{Ops: promOps(p)}
+	if _, err := NewI2C(&bus, 0x77, &DefaultOpts); err == nil {
+		t.Fatal("expected CRC error")
+	}
+}
+
+func TestSense(t *testing.T) {
+	defer func(f func(time.Duration)) { doSleep = f }(doSleep)
+	var slept time.Duration
+	doSleep = func(d time.Duration) { slept += d }
+	bus := i2ctest.Playback{
+		Ops: append(promOps(validPROM()),
+			i2ctest.IO{Addr: 0x77, W: []byte{0x48}},
+			i2ctest.IO{Addr: 0x77, W: []byte{cmdADCRead}, R: []byte{0x8A, 0xA2, 0x1A}},
+			i2ctest.IO{Addr: 0x77, W: []byte{0x54}},
+			i2ctest.IO{Addr: 0x77, W: []byte{cmdADCRead}, R: []byte{0x82, 0xC1, 0x3E}},
+			i2ctest.IO{Addr: 0x77, W: []byte{0x48}},
+			i2ctest.IO{Addr: 0x77, W: []byte{cmdADCRead}, R: []byte{0x00, 0x00, 0x00}},
+		),
+	}
+	d, err := NewI2C(&bus, 0x77, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := d.String(); s != "MS5611{playback(119)}" {
+		t.Fatal(s)
+	}
+	slept = 0
+	e := physic.Env{}
+	if err := d.Sense(&e); err != nil {
+		t.Fatal(err)
+	}
+	// D1 = 9085466, D2 = 8569150 is 20.07°C and 1000.09mbar.
+	if want := physic.ZeroCelsius + 20070*physic.MilliKelvin; e.Temperature != want {
+		t.Fatal(e.Temperature)
+	}
+	if e.Pressure != 100009*physic.Pascal {
+		t.Fatal(e.Pressure)
+	}
+	if slept != 9040*time.Microsecond+2280*time.Microsecond {
+		t.Fatal(slept)
+	}
+	if err := d.Sense(&e); err == nil {
+		t.Fatal("expected error on incomplete conversion")
+	}
+	d.Precision(&e)
+	if e.Pressure != 1200*physic.MilliPascal || e.Temperature != 10*physic.MilliKelvin {
+		t.Fatal(e)
+	}
+	if err := d.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCompensate_Cold(t *testing.T) {
+	d := Dev{prom: prom}
+	// -20°C before the second order compensation, T2 is dT²/2³¹.
+	d2 := uint32(8569150 - (4007<<23)/28312)
+	dT := int64(d2) - int64(prom[5])<<8
+	temp, p := d.compensate(9085466, d2)
+	if want := 2000 + dT*int64(prom[6])>>23 - dT*dT>>31; temp != want || temp > -2600 {
+		t.Fatal(temp, want)
+	}
+	if p <= 0 || p > 120000 {
+		t.Fatal(p)
+	}
+}
+
+func TestSenseContinuous(t *testing.T) {
+	defer func(f func(time.Duration)) { doSleep = f }(doSleep)
+	doSleep = func(time.Duration) {}
+	bus := i2ctest.Playback{
+		Ops: append(promOps(validPROM()),
+			i2ctest.IO{Addr: 0x77, W: []byte{0x48}},
+			i2ctest.IO{Addr: 0x77, W: []byte{cmdADCRead}, R: []byte{0x8A, 0xA2, 0x1A}},
+			i2ctest.IO{Addr: 0x77, W: []byte{0x54}},
+			i2ctest.IO{Addr: 0x77, W: []byte{cmdADCRead}, R: []byte{0x82, 0xC1, 0x3E}},
+		),
+	}
+	d, err := NewI2C(&bus, 0x77, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.SenseContinuous(time.Millisecond); err == nil {
+		t.Fatal("expected error")
+	}
+	c, err := d.SenseContinuous(time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := <-c; e.Pressure != 100009*physic.Pascal {
+		t.Fatal(e)
+	}
+	if err := d.Sense(&physic.Env{}); err == nil {
+		t.Fatal("expected error while sensing continuously")
+	}
+	if err := d.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := <-c; ok {
+		t.Fatal("channel not closed")
+	}
+}
+
+func TestAltitude(t *testing.T) {
+	if h := Altitude(StandardSeaLevel, StandardSeaLevel); h != 0 {
+		t.Fatal(h)
+	}
+	// 89875Pa is about 1000m in the standard atmosphere.
+	if h := Altitude(89875*physic.Pascal, StandardSeaLevel); h < 999*physic.Metre || h > 1001*physic.Metre {
+		t.Fatal(h)
+	}
+	if p0 := SeaLevelPressure(95000*physic.Pascal, 0); p0 != 95000*physic.Pascal {
+		t.Fatal(p0)
+	}
+	if p0 := SeaLevelPressure(95000*physic.Pascal, Altitude(95000*physic.Pascal, 102000*physic.Pascal)); p0 < 101999*physic.Pascal || p0 > 102001*physic.Pascal {
+		t.Fatal(p0)
+	}
+}
+
+func TestOSR_String(t *testing.T) {
+	if s := OSR2048.String(); s != "OSR2048" {
+		t.Fatal(s)
+	}
+	if s := OSR(3).String(); s != "OSR(3)" {
+		t.Fatal(s)
+	}
+}
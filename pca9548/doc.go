@@ -6,6 +6,9 @@
 // from multiple vendors. The main features of this multiplexer is that its has
 // 8 channels and is capable of voltage level translation.
 //
+// The TI TCA9548A is compatible. The 4 channels PCA9546A and TCA9546A, and the
+// 2 channels PCA9543A, are supported with Opts.Ports.
+//
 // # Adjusting the Bus CLK
 //
 // The bus clock is slaved to the master bus clock, different clock for each
@@ -14,4 +17,6 @@
 // # Datasheet
 //
 // https://www.nxp.com/docs/en/data-sheet/PCA9548A.pdf
+//
+// https://www.ti.com/lit/ds/symlink/tca9548a.pdf
 package pca9548
@@ -24,6 +24,9 @@ type Opts struct {
 	// 0x70 to 0x77. The address is set by pulling A0~A2 low or high. Please
 	// refer to the datasheet.
 	Addr int
+	// Ports is the number of downstream channels: 8 for the PCA9548A and
+	// TCA9548A, 4 for the PCA9546A and TCA9546A, 2 for the PCA9543A. 0 means 8.
+	Ports int
 }
 
 // Dev is handle to a pca9548 I²C Multiplexer.
@@ -37,6 +40,8 @@ type Dev struct {
 	// Mutable.
 	mu         sync.Mutex
 	activePort uint8
+	// refs is the number of open ports.
+	refs int
 }
 
 // New creates a new handle to a pca9548 I²C multiplexer.
@@ -44,11 +49,19 @@ func New(bus i2c.Bus, opts *Opts) (*Dev, error) {
 	if opts.Addr < 0x70 || opts.Addr > 0x77 {
 		return nil, errors.New("address outside valid range of 0x70-0x77")
 	}
+	numPorts := uint8(8)
+	switch opts.Ports {
+	case 0, 8:
+	case 2, 4:
+		numPorts = uint8(opts.Ports)
+	default:
+		return nil, errors.New("number of ports must be 2, 4 or 8")
+	}
 	d := &Dev{
 		c:          bus,
 		activePort: 0xFF,
 		address:    uint16(opts.Addr),
-		numPorts:   8,
+		numPorts:   numPorts,
 		name:       "pca9548-" + strconv.FormatUint(uint64(opts.Addr), 16),
 	}
 	r := make([]byte, 1)
@@ -78,6 +91,21 @@ func (d *Dev) RegisterPorts(alias string) ([]string, error) {
 	return portNames, nil
 }
 
+// Bus returns the downstream channel n as an i2c.Bus, without registering it.
+//
+// The channel is selected before each transaction, so devices with the same
+// address on different channels can be used through their usual constructor.
+// The transactions of all the channels are serialized. Once all the ports
+// returned by Bus and RegisterPorts are closed, all the channels are
+// disconnected.
+func (d *Dev) Bus(n int) (i2c.BusCloser, error) {
+	if n < 0 || n >= int(d.numPorts) {
+		return nil, errors.New("port " + strconv.Itoa(n) + " out of range")
+	}
+	name := d.c.String() + "-" + d.name + "-" + strconv.Itoa(n)
+	return d.open(uint8(n), name), nil
+}
+
 // Halt does nothing.
 func (d *Dev) Halt() error {
 	return nil
@@ -106,14 +134,32 @@ func (d *Dev) tx(port uint8, address uint16, w, r []byte) error {
 	return d.c.Tx(address, w, r)
 }
 
+// open returns a new port and counts it as open.
+func (d *Dev) open(number uint8, name string) *port {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.refs++
+	return &port{name: name, mux: d, number: number}
+}
+
+// release disconnects all the channels once the last port is closed.
+func (d *Dev) release() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.refs--; d.refs > 0 {
+		return nil
+	}
+	d.activePort = 0xFF
+	if err := d.c.Tx(d.address, []byte{0}, nil); err != nil {
+		return errors.New("failed to disconnect the multiplexer ports: " + err.Error())
+	}
+	return nil
+}
+
 // newOpener is a helper for creating an opener func.
 func newOpener(d *Dev, portNumber uint8, alias string, name string) i2creg.Opener {
 	return func() (i2c.BusCloser, error) {
-		return &port{
-			name:   name + "(" + alias + ")",
-			mux:    d,
-			number: portNumber,
-		}, nil
+		return d.open(portNumber, name+"("+alias+")"), nil
 	}
 }
 
@@ -149,9 +195,13 @@ func (p *port) Tx(addr uint16, w, r []byte) error {
 // Close closes a port.
 func (p *port) Close() error {
 	p.mu.Lock()
+	mux := p.mux
 	p.mux = nil
 	p.mu.Unlock()
-	return nil
+	if mux == nil {
+		return nil
+	}
+	return mux.release()
 }
 
 var _ conn.Resource = &Dev{}
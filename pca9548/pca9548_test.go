@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"testing"
 
+	"periph.io/x/conn/v3/i2c"
 	"periph.io/x/conn/v3/i2c/i2creg"
 	"periph.io/x/conn/v3/i2c/i2ctest"
 	"periph.io/x/conn/v3/physic"
@@ -337,3 +338,66 @@ func Test_port_SetSpeed(t *testing.T) {
 		t.Errorf("expected error but got none")
 	}
 }
+
+func TestDev_Bus(t *testing.T) {
+	bus := &i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x70, W: nil, R: []byte{0xFF}},
+			// Both devices use the same address on different ports.
+			{Addr: 0x70, W: []byte{0x01}, R: nil},
+			{Addr: 0x40, W: []byte{0xE3}, R: []byte{0x12}},
+			{Addr: 0x70, W: []byte{0x08}, R: nil},
+			{Addr: 0x40, W: []byte{0xE3}, R: []byte{0x34}},
+			{Addr: 0x40, W: []byte{0xE3}, R: []byte{0x56}},
+			// Closing the last port disconnects all the ports.
+			{Addr: 0x70, W: []byte{0x00}, R: nil},
+		},
+	}
+	mux, err := New(bus, &Opts{Addr: 0x70, Ports: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mux.Bus(4); err == nil {
+		t.Fatal("expected error for port out of range")
+	}
+	b0, err := mux.Bus(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b3, err := mux.Bus(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := b3.String(); s != "Port:playback-pca9548-70-3" {
+		t.Fatal(s)
+	}
+	r := []byte{0}
+	for i, b := range []i2c.Bus{b0, b3, b3} {
+		if err := b.Tx(0x40, []byte{0xE3}, r); err != nil {
+			t.Fatal(i, err)
+		}
+	}
+	if r[0] != 0x56 {
+		t.Fatal(r)
+	}
+	if err := b0.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := b3.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// Closing twice is a noop.
+	if err := b3.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNew_Ports(t *testing.T) {
+	bus := &i2ctest.Playback{DontPanic: true}
+	if _, err := New(bus, &Opts{Addr: 0x70, Ports: 3}); err == nil {
+		t.Fatal("expected error for invalid number of ports")
+	}
+}
@@ -0,0 +1,14 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package pcf8563 controls a NXP PCF8563 real-time clock via I²C interface.
+//
+// The PCF8563 is a low power clock with an alarm on the minutes, hours, day
+// and weekday that can assert the INT pin, and a programmable clock output.
+// The clock is kept in UTC, between the years 2000 and 2099.
+//
+// # Datasheet
+//
+// https://www.nxp.com/docs/en/data-sheet/PCF8563.pdf
+package pcf8563
@@ -0,0 +1,256 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package pcf8563
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/i2c"
+)
+
+// Addr is the I²C address of the PCF8563.
+const Addr uint16 = 0x51
+
+// AlarmMatch is the set of fields of the alarm time which must match the
+// current time for the alarm to fire. The fields can be combined.
+type AlarmMatch uint8
+
+// Alarm fields.
+const (
+	MatchMinute  AlarmMatch = 1 << 0
+	MatchHour    AlarmMatch = 1 << 1
+	MatchDay     AlarmMatch = 1 << 2
+	MatchWeekday AlarmMatch = 1 << 3
+)
+
+// Alarm is the configuration of the alarm.
+type Alarm struct {
+	Match AlarmMatch
+	// Time is the time of the alarm. Only the fields selected by Match are
+	// used, in UTC.
+	Time time.Time
+}
+
+// ClockOut is the frequency of the CLKOUT pin.
+type ClockOut int
+
+// Clock output frequencies.
+const (
+	ClockOutOff   ClockOut = -1
+	ClockOut32768 ClockOut = 0
+	ClockOut1024  ClockOut = 1
+	ClockOut32    ClockOut = 2
+	ClockOut1     ClockOut = 3
+)
+
+// Opts holds the configuration options.
+type Opts struct {
+	// Interrupt is the GPIO connected to the INT pin. It is optional and only
+	// needed by WaitForAlarm.
+	Interrupt gpio.PinIn
+}
+
+// DefaultOpts is the recommended default options.
+var DefaultOpts = Opts{}
+
+// Dev is a handle to a PCF8563.
+type Dev struct {
+	c    i2c.Dev
+	intr gpio.PinIn
+}
+
+// NewI2C returns a handle to a PCF8563.
+//
+// The clock is started if it was stopped.
+func NewI2C(b i2c.Bus, opts *Opts) (*Dev, error) {
+	d := &Dev{c: i2c.Dev{Bus: b, Addr: Addr}, intr: opts.Interrupt}
+	if d.intr != nil {
+		// INT is open drain and active low.
+		if err := d.intr.In(gpio.PullUp, gpio.FallingEdge); err != nil {
+			return nil, fmt.Errorf("pcf8563: %v", err)
+		}
+	}
+	if err := d.writeReg(regControl1, 0x00); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *Dev) String() string {
+	return fmt.Sprintf("PCF8563{%s}", &d.c)
+}
+
+// Now returns the current time of the clock, in UTC.
+func (d *Dev) Now() (time.Time, error) {
+	var r [7]byte
+	if err := d.readRegs(regSeconds, r[:]); err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(2000+fromBCD(r[6]), time.Month(fromBCD(r[5]&0x1F)), fromBCD(r[3]&0x3F), fromBCD(r[2]&0x3F), fromBCD(r[1]&0x7F), fromBCD(r[0]&0x7F), 0, time.UTC), nil
+}
+
+// Set sets the clock to t, converted to UTC, and clears the low voltage
+// flag.
+func (d *Dev) Set(t time.Time) error {
+	t = t.UTC()
+	if t.Year() < 2000 || t.Year() > 2099 {
+		return errors.New("pcf8563: year must be between 2000 and 2099")
+	}
+	return d.writeRegs(regSeconds, []byte{
+		toBCD(t.Second()),
+		toBCD(t.Minute()),
+		toBCD(t.Hour()),
+		toBCD(t.Day()),
+		byte(t.Weekday()),
+		toBCD(int(t.Month())),
+		toBCD(t.Year() % 100),
+	})
+}
+
+// LostPower returns true if the supply voltage dropped too low since the
+// clock was last set. The time is then invalid.
+func (d *Dev) LostPower() (bool, error) {
+	v, err := d.readReg(regSeconds)
+	return v&0x80 != 0, err
+}
+
+// SetAlarm configures the alarm and enables its interrupt on the INT pin.
+func (d *Dev) SetAlarm(a Alarm) error {
+	if a.Match == 0 || a.Match > MatchMinute|MatchHour|MatchDay|MatchWeekday {
+		return errors.New("pcf8563: invalid alarm match")
+	}
+	t := a.Time.UTC()
+	regs := []byte{toBCD(t.Minute()), toBCD(t.Hour()), toBCD(t.Day()), byte(t.Weekday())}
+	for i := range regs {
+		// AE set disables the field.
+		if a.Match&(1<<uint(i)) == 0 {
+			regs[i] |= 0x80
+		}
+	}
+	if err := d.writeRegs(regAlarm, regs); err != nil {
+		return err
+	}
+	return d.updateControl2(ctrl2AF, ctrl2AIE)
+}
+
+// DisableAlarm disables the alarm and its interrupt.
+func (d *Dev) DisableAlarm() error {
+	if err := d.writeRegs(regAlarm, []byte{0x80, 0x80, 0x80, 0x80}); err != nil {
+		return err
+	}
+	return d.updateControl2(ctrl2AF|ctrl2AIE, 0)
+}
+
+// ClearAlarm returns true if the alarm fired and clears its flag, which
+// releases the INT pin.
+func (d *Dev) ClearAlarm() (bool, error) {
+	v, err := d.readReg(regControl2)
+	if err != nil || v&ctrl2AF == 0 {
+		return false, err
+	}
+	// The timer flag is written back as 1 to not clear it.
+	return true, d.writeReg(regControl2, (v|ctrl2TF)&^ctrl2AF)
+}
+
+// WaitForAlarm waits for the alarm to assert the INT pin, up to timeout, and
+// clears its flag.
+//
+// It requires the Interrupt pin in Opts.
+func (d *Dev) WaitForAlarm(timeout time.Duration) error {
+	if d.intr == nil {
+		return errors.New("pcf8563: no interrupt pin")
+	}
+	if !d.intr.WaitForEdge(timeout) {
+		return errors.New("pcf8563: timeout waiting for alarm")
+	}
+	_, err := d.ClearAlarm()
+	return err
+}
+
+// SetClockOut sets the frequency of the CLKOUT pin, or disables it with
+// ClockOutOff.
+func (d *Dev) SetClockOut(c ClockOut) error {
+	switch {
+	case c == ClockOutOff:
+		return d.writeReg(regClockOut, 0x00)
+	case c >= ClockOut32768 && c <= ClockOut1:
+		return d.writeReg(regClockOut, 0x80|byte(c))
+	default:
+		return errors.New("pcf8563: invalid clock output frequency")
+	}
+}
+
+// Halt implements conn.Resource.
+//
+// The clock keeps running.
+func (d *Dev) Halt() error {
+	return nil
+}
+
+//
+
+// Registers.
+const (
+	regControl1 = 0x00
+	regControl2 = 0x01
+	regSeconds  = 0x02
+	regAlarm    = 0x09
+	regClockOut = 0x0D
+)
+
+// Bits of the control/status 2 register.
+const (
+	ctrl2AF  = 0x08
+	ctrl2TF  = 0x04
+	ctrl2AIE = 0x02
+)
+
+// updateControl2 clears and sets bits of the control/status 2 register. The
+// timer flag is preserved.
+func (d *Dev) updateControl2(clear, set byte) error {
+	v, err := d.readReg(regControl2)
+	if err != nil {
+		return err
+	}
+	return d.writeReg(regControl2, (v|ctrl2TF|set)&^clear)
+}
+
+func toBCD(v int) byte {
+	return byte(v/10<<4 | v%10)
+}
+
+func fromBCD(b byte) int {
+	return int(b>>4)*10 + int(b&0x0F)
+}
+
+func (d *Dev) readRegs(reg byte, r []byte) error {
+	if err := d.c.Tx([]byte{reg}, r); err != nil {
+		return fmt.Errorf("pcf8563: %v", err)
+	}
+	return nil
+}
+
+func (d *Dev) readReg(reg byte) (byte, error) {
+	var r [1]byte
+	err := d.readRegs(reg, r[:])
+	return r[0], err
+}
+
+func (d *Dev) writeRegs(reg byte, w []byte) error {
+	if err := d.c.Tx(append([]byte{reg}, w...), nil); err != nil {
+		return fmt.Errorf("pcf8563: %v", err)
+	}
+	return nil
+}
+
+func (d *Dev) writeReg(reg, v byte) error {
+	return d.writeRegs(reg, []byte{v})
+}
+
+var _ conn.Resource = &Dev{}
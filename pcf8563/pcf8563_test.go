@@ -0,0 +1,126 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package pcf8563
+
+import (
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpiotest"
+	"periph.io/x/conn/v3/i2c/i2ctest"
+)
+
+var initOp = i2ctest.IO{Addr: 0x51, W: []byte{regControl1, 0x00}}
+
+func TestTime(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			initOp,
+			{Addr: 0x51, W: []byte{regSeconds}, R: []byte{0xB0, 0x45, 0x13, 0x15, 0x06, 0x86, 0x24}},
+			{Addr: 0x51, W: []byte{regSeconds}, R: []byte{0x80}},
+			{Addr: 0x51, W: []byte{regSeconds, 0x58, 0x59, 0x23, 0x29, 0x04, 0x02, 0x24}},
+		},
+	}
+	d, err := NewI2C(&bus, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := d.String(); s != "PCF8563{playback(81)}" {
+		t.Fatal(s)
+	}
+	now, err := d.Now()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2024, 6, 15, 13, 45, 30, 0, time.UTC); !now.Equal(want) {
+		t.Fatal(now)
+	}
+	if lost, err := d.LostPower(); err != nil || !lost {
+		t.Fatal(lost, err)
+	}
+	if err := d.Set(time.Date(2024, 2, 29, 23, 59, 58, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Set(time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC)); err == nil {
+		t.Fatal("expected error on year")
+	}
+	if err := d.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAlarm(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			initOp,
+			{Addr: 0x51, W: []byte{regAlarm, 0x30, 0x07, 0x81, 0x85}},
+			{Addr: 0x51, W: []byte{regControl2}, R: []byte{0x18}},
+			{Addr: 0x51, W: []byte{regControl2, 0x16}},
+			{Addr: 0x51, W: []byte{regControl2}, R: []byte{0x0A}},
+			{Addr: 0x51, W: []byte{regControl2, 0x06}},
+			{Addr: 0x51, W: []byte{regAlarm, 0x80, 0x80, 0x80, 0x80}},
+			{Addr: 0x51, W: []byte{regControl2}, R: []byte{0x02}},
+			{Addr: 0x51, W: []byte{regControl2, 0x04}},
+		},
+	}
+	if err := (&Dev{}).WaitForAlarm(time.Second); err == nil {
+		t.Fatal("expected error without pin")
+	}
+	intr := &gpiotest.Pin{N: "INT", EdgesChan: make(chan gpio.Level, 1)}
+	d, err := NewI2C(&bus, &Opts{Interrupt: intr})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetAlarm(Alarm{}); err == nil {
+		t.Fatal("expected error on match")
+	}
+	at := time.Date(2024, 3, 1, 7, 30, 15, 0, time.UTC)
+	if err := d.SetAlarm(Alarm{Match: MatchMinute | MatchHour, Time: at}); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.WaitForAlarm(time.Millisecond); err == nil {
+		t.Fatal("expected timeout")
+	}
+	intr.EdgesChan <- gpio.Low
+	if err := d.WaitForAlarm(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.DisableAlarm(); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSetClockOut(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			initOp,
+			{Addr: 0x51, W: []byte{regClockOut, 0x83}},
+			{Addr: 0x51, W: []byte{regClockOut, 0x00}},
+		},
+	}
+	d, err := NewI2C(&bus, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetClockOut(ClockOut1); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetClockOut(ClockOutOff); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetClockOut(ClockOut(4)); err == nil {
+		t.Fatal("expected error on frequency")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,34 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package pcf857x provides a driver for the NXP PCF8574 and PCF8575
+// quasi-bidirectional I²C extenders, commonly found on HD44780 LCD backpacks
+// and keypad boards.
+//
+// The following variants are supported:
+//
+//   - PCF8574 - 8 pins, addresses: 0x20 - 0x27
+//   - PCF8574A - 8 pins, addresses: 0x38 - 0x3f
+//   - PCF8575 - 16 pins, addresses: 0x20 - 0x27
+//
+// # Quasi-bidirectional pins
+//
+// The pins have no direction register. A pin driven high is only weakly
+// pulled up so it can be used as an input; a pin driven low sinks current. In
+// therefore sets the pin high and Read returns the level seen on the pin.
+//
+// # Interrupts
+//
+// The INT output is asserted low on any change of the inputs and released
+// when the port is read. Request edges with In, connect INT to a host pin and
+// start the dispatcher with Dev.ListenInterrupts. The pins WaitForEdge then
+// return on the requested edges, and callbacks set with
+// Pin.SetInterruptCallback are called.
+//
+// # Datasheet
+//
+// https://www.nxp.com/docs/en/data-sheet/PCF8574_PCF8574A.pdf
+//
+// https://www.nxp.com/docs/en/data-sheet/PCF8575.pdf
+package pcf857x
@@ -0,0 +1,63 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package pcf857x_test
+
+import (
+	"fmt"
+	"log"
+
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpioreg"
+	"periph.io/x/conn/v3/i2c/i2creg"
+	"periph.io/x/devices/v3/pcf857x"
+	"periph.io/x/host/v3"
+)
+
+func Example() {
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Open default I²C bus.
+	bus, err := i2creg.Open("")
+	if err != nil {
+		log.Fatalf("failed to open I²C: %v", err)
+	}
+	defer bus.Close()
+
+	dev, err := pcf857x.New(bus, pcf857x.PCF8574, 0x20)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer dev.Close()
+
+	// Report key presses on P0, with INT wired to GPIO17.
+	key := dev.Pins[0]
+	if err := key.In(gpio.PullUp, gpio.FallingEdge); err != nil {
+		log.Fatal(err)
+	}
+	key.SetInterruptCallback(func(l gpio.Level) {
+		fmt.Println("key pressed")
+	})
+	intPin := gpioreg.ByName("GPIO17")
+	if intPin == nil {
+		log.Fatal("failed to find GPIO17")
+	}
+	if err := intPin.In(gpio.PullUp, gpio.NoEdge); err != nil {
+		log.Fatal(err)
+	}
+	if err := dev.ListenInterrupts(intPin); err != nil {
+		log.Fatal(err)
+	}
+	defer dev.StopInterrupts()
+
+	// Blink an LED on P7.
+	for _, l := range []gpio.Level{gpio.Low, gpio.High} {
+		if err := dev.Pins[7].Out(l); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
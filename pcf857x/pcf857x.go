@@ -0,0 +1,274 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package pcf857x
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpioreg"
+	"periph.io/x/conn/v3/i2c"
+)
+
+// Variant is the type denoting a specific variant of the family.
+type Variant string
+
+const (
+	// PCF8574 8-bit extender.
+	PCF8574 Variant = "PCF8574"
+	// PCF8574A 8-bit extender with a different address range.
+	PCF8574A Variant = "PCF8574A"
+	// PCF8575 16-bit extender.
+	PCF8575 Variant = "PCF8575"
+)
+
+// Dev is a handle to a PCF857x extender.
+type Dev struct {
+	// Pins provide access to extender pins.
+	Pins []Pin
+
+	c    i2c.Dev
+	name string
+	// n is the number of bytes of the port.
+	n int
+
+	mu sync.Mutex
+	// latch is the last value written to the device. Pins with their bit set
+	// are inputs.
+	latch uint16
+	// last is the last value read by the interrupt dispatcher.
+	last      uint16
+	edges     []gpio.Edge
+	callbacks []func(gpio.Level)
+	events    []chan gpio.Level
+
+	// interrupt dispatcher state
+	intMu sync.Mutex
+	stop  chan struct{}
+	wg    sync.WaitGroup
+}
+
+// New returns a handle to a PCF857x extender.
+//
+// Since the output state can't be read back, all the pins are set high, which
+// is the power on state, so they can be used as inputs.
+func New(bus i2c.Bus, variant Variant, addr uint16) (*Dev, error) {
+	var n int
+	var base uint16
+	switch variant {
+	case PCF8574:
+		n, base = 8, 0x20
+	case PCF8574A:
+		n, base = 8, 0x38
+	case PCF8575:
+		n, base = 16, 0x20
+	default:
+		return nil, fmt.Errorf("pcf857x: unsupported variant %q", string(variant))
+	}
+	if addr&^7 != base {
+		return nil, fmt.Errorf("pcf857x: %s supported address range is %#x - %#x", variant, base, base+7)
+	}
+	d := &Dev{
+		c:         i2c.Dev{Bus: bus, Addr: addr},
+		name:      string(variant) + "_" + strconv.FormatInt(int64(addr), 16),
+		n:         n / 8,
+		latch:     uint16(1<<uint(n) - 1),
+		edges:     make([]gpio.Edge, n),
+		callbacks: make([]func(gpio.Level), n),
+		events:    make([]chan gpio.Level, n),
+	}
+	if err := d.write(d.latch); err != nil {
+		return nil, err
+	}
+	var err error
+	if d.last, err = d.read(); err != nil {
+		return nil, err
+	}
+	d.Pins = make([]Pin, n)
+	for i := range d.Pins {
+		p := &pcfPin{dev: d, bit: uint8(i)}
+		d.Pins[i] = p
+		// Ignore registration failure.
+		_ = gpioreg.Register(p)
+	}
+	return d, nil
+}
+
+// String implements conn.Resource.
+func (d *Dev) String() string {
+	return d.name
+}
+
+// Halt implements conn.Resource.
+//
+// It sets all the pins high, releasing any output driven low.
+func (d *Dev) Halt() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.latch = uint16(1<<uint(len(d.Pins)) - 1)
+	return d.write(d.latch)
+}
+
+// Close stops the interrupt dispatcher and removes the pins registration.
+func (d *Dev) Close() error {
+	d.StopInterrupts()
+	for _, p := range d.Pins {
+		if err := gpioreg.Unregister(p.Name()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadAll returns the level of all the pins, pin 0 in the LSB.
+func (d *Dev) ReadAll() (uint16, error) {
+	return d.read()
+}
+
+// WriteAll sets all the pins at once, pin 0 in the LSB. Pins used as inputs
+// must be kept high.
+func (d *Dev) WriteAll(v uint16) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	v &= uint16(1<<uint(len(d.Pins)) - 1)
+	if err := d.write(v); err != nil {
+		return err
+	}
+	d.latch = v
+	return nil
+}
+
+// ListenInterrupts starts the interrupt dispatcher.
+//
+// intPin is the host pin connected to the INT output of the device. When it is
+// asserted the port is read, and the pins that changed get their callbacks
+// called and their WaitForEdge return.
+//
+// INT is open drain; the pull resistor of intPin is not changed and must be
+// set by the caller if the board doesn't have one.
+func (d *Dev) ListenInterrupts(intPin gpio.PinIn) error {
+	d.intMu.Lock()
+	defer d.intMu.Unlock()
+	if d.stop != nil {
+		return errors.New("pcf857x: interrupts are already being listened to")
+	}
+	if err := intPin.In(gpio.PullNoChange, gpio.FallingEdge); err != nil {
+		return err
+	}
+	d.stop = make(chan struct{})
+	d.wg.Add(1)
+	go d.listen(intPin, d.stop)
+	return nil
+}
+
+// StopInterrupts stops the interrupt dispatcher, if running.
+func (d *Dev) StopInterrupts() {
+	d.intMu.Lock()
+	stop := d.stop
+	d.stop = nil
+	d.intMu.Unlock()
+	if stop != nil {
+		close(stop)
+		d.wg.Wait()
+	}
+}
+
+//
+
+// interruptPoll is how often the dispatcher checks if it must stop.
+var interruptPoll = 100 * time.Millisecond
+
+func (d *Dev) read() (uint16, error) {
+	var b [2]byte
+	r := b[:d.n]
+	if err := d.c.Tx(nil, r); err != nil {
+		return 0, err
+	}
+	return uint16(b[0]) | uint16(b[1])<<8, nil
+}
+
+func (d *Dev) write(v uint16) error {
+	w := []byte{byte(v), byte(v >> 8)}
+	return d.c.Tx(w[:d.n], nil)
+}
+
+// setBit sets the latch bit of a pin.
+func (d *Dev) setBit(bit uint8, l gpio.Level) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	v := d.latch &^ (1 << bit)
+	if l {
+		v |= 1 << bit
+	}
+	if v == d.latch {
+		return nil
+	}
+	if err := d.write(v); err != nil {
+		return err
+	}
+	d.latch = v
+	return nil
+}
+
+func (d *Dev) listen(ip gpio.PinIn, stop <-chan struct{}) {
+	defer d.wg.Done()
+	for {
+		select {
+		case <-stop:
+			return
+		default:
+		}
+		if !ip.WaitForEdge(interruptPoll) {
+			continue
+		}
+		// Keep servicing while the line is asserted, since new changes
+		// arriving in the meantime don't generate an edge. The line may be
+		// shared with other devices so stop when nothing changed.
+		for {
+			if !d.serviceInterrupt() || ip.Read() != gpio.Low {
+				break
+			}
+		}
+	}
+}
+
+// serviceInterrupt reads the port, which releases INT, and dispatches the
+// changed input pins. It returns true if any input changed.
+func (d *Dev) serviceInterrupt() bool {
+	v, err := d.read()
+	if err != nil {
+		return false
+	}
+	d.mu.Lock()
+	changed := (v ^ d.last) & d.latch
+	d.last = v
+	d.mu.Unlock()
+	for bit := range d.Pins {
+		if changed&(1<<uint(bit)) == 0 {
+			continue
+		}
+		l := gpio.Level(v&(1<<uint(bit)) != 0)
+		d.mu.Lock()
+		edge, f, c := d.edges[bit], d.callbacks[bit], d.events[bit]
+		d.mu.Unlock()
+		if edge == gpio.NoEdge || (edge == gpio.RisingEdge && l == gpio.Low) || (edge == gpio.FallingEdge && l == gpio.High) {
+			continue
+		}
+		if c != nil {
+			select {
+			case c <- l:
+			default:
+			}
+		}
+		if f != nil {
+			f(l)
+		}
+	}
+	return changed != 0
+}
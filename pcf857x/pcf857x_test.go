@@ -0,0 +1,172 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package pcf857x
+
+import (
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpioreg"
+	"periph.io/x/conn/v3/gpio/gpiotest"
+	"periph.io/x/conn/v3/i2c/i2ctest"
+)
+
+var _ gpio.PinIO = &pcfPin{}
+
+func TestNew_address(t *testing.T) {
+	if _, err := New(&i2ctest.Playback{}, PCF8574A, 0x20); err == nil {
+		t.Fatal("PCF8574A doesn't support 0x20")
+	}
+	if _, err := New(&i2ctest.Playback{}, Variant("PCF8573"), 0x20); err == nil {
+		t.Fatal("unknown variant")
+	}
+}
+
+func TestPCF8574(t *testing.T) {
+	const address uint16 = 0x27
+	scenario := &i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// all pins set high on creation
+			{Addr: address, W: []byte{0xFF}},
+			{Addr: address, R: []byte{0xF0}},
+			// P2 driven low
+			{Addr: address, W: []byte{0xFB}},
+			// P7 read
+			{Addr: address, R: []byte{0x7B}},
+			// P2 released
+			{Addr: address, W: []byte{0xFF}},
+		},
+	}
+	dev, err := New(scenario, PCF8574, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dev.Close()
+	if p := gpioreg.ByName("PCF8574_27_P2"); p == nil {
+		t.Fatal("pin not registered")
+	}
+	p2 := dev.Pins[2]
+	if err := p2.Out(gpio.Low); err != nil {
+		t.Fatal(err)
+	}
+	if f := p2.Function(); f != string(gpio.OUT) {
+		t.Fatal(f)
+	}
+	// Setting the same level doesn't hit the bus.
+	if err := p2.Out(gpio.Low); err != nil {
+		t.Fatal(err)
+	}
+	if l := dev.Pins[7].Read(); l != gpio.Low {
+		t.Fatal(l)
+	}
+	if err := p2.In(gpio.PullDown, gpio.NoEdge); err == nil {
+		t.Fatal("PullDown is not supported")
+	}
+	if err := p2.In(gpio.PullUp, gpio.NoEdge); err != nil {
+		t.Fatal(err)
+	}
+	if err := scenario.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPCF8575(t *testing.T) {
+	const address uint16 = 0x20
+	scenario := &i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: address, W: []byte{0xFF, 0xFF}},
+			{Addr: address, R: []byte{0xFF, 0xFF}},
+			{Addr: address, W: []byte{0xFF, 0x7F}},
+			{Addr: address, W: []byte{0x34, 0x12}},
+			{Addr: address, R: []byte{0x34, 0x12}},
+		},
+	}
+	dev, err := New(scenario, PCF8575, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dev.Close()
+	if n := dev.Pins[15].Name(); n != "PCF8575_20_P17" {
+		t.Fatal(n)
+	}
+	if err := dev.Pins[15].Out(gpio.Low); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.WriteAll(0x1234); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := dev.ReadAll(); v != 0x1234 || err != nil {
+		t.Fatal(v, err)
+	}
+	if err := scenario.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPCF8574_interrupt(t *testing.T) {
+	const address uint16 = 0x20
+	scenario := &i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: address, W: []byte{0xFF}},
+			{Addr: address, R: []byte{0xFF}},
+			// P0 driven low
+			{Addr: address, W: []byte{0xFE}},
+			// P3 goes low, P0 is an output and ignored
+			{Addr: address, R: []byte{0xF6}},
+			// P3 goes high, filtered out
+			{Addr: address, R: []byte{0xFE}},
+		},
+	}
+	dev, err := New(scenario, PCF8574, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dev.Close()
+	if err := dev.Pins[0].Out(gpio.Low); err != nil {
+		t.Fatal(err)
+	}
+	p := dev.Pins[3]
+	if err := p.In(gpio.PullUp, gpio.FallingEdge); err != nil {
+		t.Fatal(err)
+	}
+	levels := make(chan gpio.Level, 2)
+	p.SetInterruptCallback(func(l gpio.Level) {
+		levels <- l
+	})
+	intPin := &releasedPin{gpiotest.Pin{N: "INT", L: gpio.High, EdgesChan: make(chan gpio.Level, 1)}}
+	if err := dev.ListenInterrupts(intPin); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.ListenInterrupts(intPin); err == nil {
+		t.Fatal("second ListenInterrupts should fail")
+	}
+
+	intPin.EdgesChan <- gpio.Low
+	if !p.WaitForEdge(time.Second) {
+		t.Fatal("expected falling edge")
+	}
+	if l := <-levels; l != gpio.Low {
+		t.Fatalf("callback got %s", l)
+	}
+	intPin.EdgesChan <- gpio.Low
+	if p.WaitForEdge(50 * time.Millisecond) {
+		t.Fatal("rising edge must be filtered out")
+	}
+
+	dev.StopInterrupts()
+	if err := scenario.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// releasedPin is an INT pin that is released as soon as the device is read.
+type releasedPin struct {
+	gpiotest.Pin
+}
+
+func (p *releasedPin) Read() gpio.Level {
+	return gpio.High
+}
@@ -0,0 +1,159 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package pcf857x
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/pin"
+)
+
+// Pin extends gpio.PinIO with the interrupt dispatch of the extender.
+type Pin interface {
+	gpio.PinIO
+	// SetInterruptCallback sets a function called from the interrupt
+	// dispatcher with the new level when an edge requested with In is
+	// detected. Use nil to remove the callback.
+	SetInterruptCallback(f func(l gpio.Level))
+}
+
+type pcfPin struct {
+	dev *Dev
+	bit uint8
+}
+
+func (p *pcfPin) String() string {
+	return p.Name()
+}
+
+// Halt sets the pin high, which releases it.
+func (p *pcfPin) Halt() error {
+	return p.In(gpio.PullNoChange, gpio.NoEdge)
+}
+
+func (p *pcfPin) Name() string {
+	if len(p.dev.Pins) == 16 {
+		// The PCF8575 datasheet names the pins P00-P07 and P10-P17.
+		return p.dev.name + "_P" + strconv.Itoa(int(p.bit/8)) + strconv.Itoa(int(p.bit%8))
+	}
+	return p.dev.name + "_P" + strconv.Itoa(int(p.bit))
+}
+
+func (p *pcfPin) Number() int {
+	return int(p.bit)
+}
+
+func (p *pcfPin) Function() string {
+	return string(p.Func())
+}
+
+// In sets the pin high so it can be pulled low externally.
+//
+// Only gpio.PullUp is supported since the quasi-bidirectional pins are always
+// weakly pulled up.
+func (p *pcfPin) In(pull gpio.Pull, edge gpio.Edge) error {
+	if pull != gpio.PullNoChange && pull != gpio.PullUp {
+		return errors.New("pcf857x: only PullUp is supported")
+	}
+	if err := p.dev.setBit(p.bit, gpio.High); err != nil {
+		return err
+	}
+	p.dev.mu.Lock()
+	defer p.dev.mu.Unlock()
+	p.dev.edges[p.bit] = edge
+	if edge == gpio.NoEdge {
+		p.dev.events[p.bit] = nil
+	} else {
+		// Flush any pending edge.
+		p.dev.events[p.bit] = make(chan gpio.Level, 1)
+	}
+	return nil
+}
+
+func (p *pcfPin) Read() gpio.Level {
+	v, _ := p.dev.read()
+	return gpio.Level(v&(1<<p.bit) != 0)
+}
+
+// WaitForEdge waits for an edge requested with In.
+//
+// Edges are only reported while the interrupt dispatcher started with
+// Dev.ListenInterrupts is running.
+func (p *pcfPin) WaitForEdge(timeout time.Duration) bool {
+	p.dev.mu.Lock()
+	c := p.dev.events[p.bit]
+	p.dev.mu.Unlock()
+	if c == nil {
+		return false
+	}
+	if timeout < 0 {
+		<-c
+		return true
+	}
+	t := time.NewTimer(timeout)
+	defer t.Stop()
+	select {
+	case <-c:
+		return true
+	case <-t.C:
+		return false
+	}
+}
+
+func (p *pcfPin) Pull() gpio.Pull {
+	return gpio.PullUp
+}
+
+func (p *pcfPin) DefaultPull() gpio.Pull {
+	return gpio.PullUp
+}
+
+// Out drives the pin low, or sets it high with a strong pull-up for one
+// clock pulse followed by the weak pull-up.
+func (p *pcfPin) Out(l gpio.Level) error {
+	return p.dev.setBit(p.bit, l)
+}
+
+func (p *pcfPin) PWM(duty gpio.Duty, f physic.Frequency) error {
+	return errors.New("pcf857x: PWM is not supported")
+}
+
+// Func returns gpio.IN when the pin is high, since it then can be used as an
+// input, and gpio.OUT when it is driven low.
+func (p *pcfPin) Func() pin.Func {
+	p.dev.mu.Lock()
+	defer p.dev.mu.Unlock()
+	if p.dev.latch&(1<<p.bit) != 0 {
+		return gpio.IN
+	}
+	return gpio.OUT
+}
+
+func (p *pcfPin) SupportedFuncs() []pin.Func {
+	return supportedFuncs[:]
+}
+
+func (p *pcfPin) SetFunc(f pin.Func) error {
+	switch f {
+	case gpio.IN:
+		return p.In(gpio.PullNoChange, gpio.NoEdge)
+	case gpio.OUT:
+		return p.Out(gpio.Low)
+	default:
+		return errors.New("pcf857x: function not supported: " + string(f))
+	}
+}
+
+func (p *pcfPin) SetInterruptCallback(f func(l gpio.Level)) {
+	p.dev.mu.Lock()
+	defer p.dev.mu.Unlock()
+	p.dev.callbacks[p.bit] = f
+}
+
+var supportedFuncs = [...]pin.Func{gpio.IN, gpio.OUT}
@@ -0,0 +1,20 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package pcf8591 controls a NXP PCF8591 8 bits Analog-Digital Converter (ADC)
+// with 4 inputs and a Digital-Analog Converter (DAC) output via I²C interface.
+//
+// It is found on many learning kits, often on a small board with a
+// thermistor, a photoresistor and a potentiometer wired to the inputs. The
+// inputs can be read single-ended or as differential pairs, through the
+// analog.PinADC returned by PinForChannel. SetOutput sets the voltage of the
+// DAC output.
+//
+// The conversions are relative to the voltage applied to VREF, AGND being
+// tied to ground.
+//
+// # Datasheet
+//
+// https://www.nxp.com/docs/en/data-sheet/PCF8591.pdf
+package pcf8591
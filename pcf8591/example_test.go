@@ -0,0 +1,50 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package pcf8591_test
+
+import (
+	"fmt"
+	"log"
+
+	"periph.io/x/conn/v3/i2c/i2creg"
+	"periph.io/x/devices/v3/pcf8591"
+	"periph.io/x/host/v3"
+)
+
+func Example() {
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Open default I²C bus.
+	bus, err := i2creg.Open("")
+	if err != nil {
+		log.Fatalf("failed to open I²C: %v", err)
+	}
+	defer bus.Close()
+
+	adc, err := pcf8591.NewI2C(bus, &pcf8591.DefaultOpts)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	// Read the first input.
+	pin, err := adc.PinForChannel(pcf8591.Channel0)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	reading, err := pin.Read()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Println(reading)
+
+	// Output the same voltage.
+	if err := adc.SetOutput(reading.V); err != nil {
+		log.Fatalln(err)
+	}
+	defer adc.Halt()
+}
@@ -0,0 +1,239 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package pcf8591
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/analog"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/pin"
+)
+
+// Channel is the analog reading to do. It can be either a single-ended
+// reading or a differential reading between two inputs.
+type Channel uint8
+
+// Value channels.
+const (
+	// Single-ended reading.
+	Channel0 Channel = 0x00
+	Channel1 Channel = 0x01
+	Channel2 Channel = 0x02
+	Channel3 Channel = 0x03
+
+	// Differential reading, from -128 to 127.
+	Channel0Minus3 Channel = 0x10
+	Channel1Minus3 Channel = 0x11
+	Channel2Minus3 Channel = 0x12
+	Channel0Minus1 Channel = 0x30
+)
+
+func (c Channel) String() string {
+	switch c {
+	case Channel0, Channel1, Channel2, Channel3:
+		return strconv.Itoa(int(c))
+	case Channel0Minus3, Channel1Minus3, Channel2Minus3:
+		return fmt.Sprintf("%d-3", c&3)
+	case Channel0Minus1:
+		return "0-1"
+	default:
+		return "Invalid"
+	}
+}
+
+// Opts holds the configuration options.
+type Opts struct {
+	// Addr is the I²C address, from 0x48 to 0x4F depending on the A0-A2
+	// pins.
+	Addr uint16
+	// Vref is the voltage applied to the VREF pin, which is the full scale of
+	// the conversions and of the output.
+	Vref physic.ElectricPotential
+}
+
+// DefaultOpts are the recommended default options, for a device with its
+// address pins tied to ground, powered with 3.3V and VREF tied to VDD.
+var DefaultOpts = Opts{
+	Addr: 0x48,
+	Vref: 3300 * physic.MilliVolt,
+}
+
+// Dev is a handle to a PCF8591.
+type Dev struct {
+	c    i2c.Dev
+	vref physic.ElectricPotential
+
+	mu sync.Mutex
+	// outputOn is set while the DAC output is enabled. It must be kept in
+	// the control byte of the conversions.
+	outputOn bool
+}
+
+// NewI2C returns a handle to a PCF8591.
+func NewI2C(b i2c.Bus, opts *Opts) (*Dev, error) {
+	if opts.Addr < 0x48 || opts.Addr > 0x4F {
+		return nil, errors.New("pcf8591: invalid address, must be between 0x48 and 0x4F")
+	}
+	if opts.Vref <= 0 {
+		return nil, errors.New("pcf8591: Vref must be positive")
+	}
+	return &Dev{c: i2c.Dev{Bus: b, Addr: opts.Addr}, vref: opts.Vref}, nil
+}
+
+func (d *Dev) String() string {
+	return fmt.Sprintf("PCF8591{%s}", &d.c)
+}
+
+// Halt turns the DAC output off, leaving it in high impedance.
+//
+// Halt implements conn.Resource.
+func (d *Dev) Halt() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.c.Tx([]byte{0x00}, nil); err != nil {
+		return fmt.Errorf("pcf8591: %v", err)
+	}
+	d.outputOn = false
+	return nil
+}
+
+// SetOutput enables the DAC output and sets its voltage, from 0 to 255/256 of
+// Vref.
+func (d *Dev) SetOutput(v physic.ElectricPotential) error {
+	raw := (v*256 + d.vref/2) / d.vref
+	if v < 0 || raw > 255 {
+		return fmt.Errorf("pcf8591: output %s out of range", v)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.c.Tx([]byte{outputEnable, byte(raw)}, nil); err != nil {
+		return fmt.Errorf("pcf8591: %v", err)
+	}
+	d.outputOn = true
+	return nil
+}
+
+// PinForChannel returns an analog pin reading the requested channel.
+func (d *Dev) PinForChannel(c Channel) (analog.PinADC, error) {
+	if c.String() == "Invalid" {
+		return nil, fmt.Errorf("pcf8591: invalid channel %d", c)
+	}
+	return &analogPin{adc: d, c: c}, nil
+}
+
+//
+
+// outputEnable is the flag of the control byte enabling the DAC output.
+const outputEnable = 0x40
+
+// read does a conversion of the channel and returns the raw value.
+func (d *Dev) read(c Channel) (int32, error) {
+	// The input mode and the channel number are encoded in the channel, as in
+	// the control byte.
+	w := [1]byte{byte(c)}
+	// The conversion is done while a byte is read, so the first byte is the
+	// result of the previous conversion.
+	var r [2]byte
+	d.mu.Lock()
+	if d.outputOn {
+		w[0] |= outputEnable
+	}
+	err := d.c.Tx(w[:], r[:])
+	d.mu.Unlock()
+	if err != nil {
+		return 0, fmt.Errorf("pcf8591: %v", err)
+	}
+	if c > Channel3 {
+		return int32(int8(r[1])), nil
+	}
+	return int32(r[1]), nil
+}
+
+func (d *Dev) toSample(raw int32) analog.Sample {
+	return analog.Sample{
+		Raw: raw,
+		V:   physic.ElectricPotential(raw) * d.vref / 256,
+	}
+}
+
+type analogPin struct {
+	adc *Dev
+	c   Channel
+}
+
+// Range returns the maximum supported range [min, max] of the values.
+func (p *analogPin) Range() (analog.Sample, analog.Sample) {
+	if p.c > Channel3 {
+		return p.adc.toSample(-128), p.adc.toSample(127)
+	}
+	return p.adc.toSample(0), p.adc.toSample(255)
+}
+
+// Read returns the current pin level.
+func (p *analogPin) Read() (analog.Sample, error) {
+	raw, err := p.adc.read(p.c)
+	if err != nil {
+		return analog.Sample{}, err
+	}
+	return p.adc.toSample(raw), nil
+}
+
+func (p *analogPin) Name() string {
+	return "PCF8591(" + p.c.String() + ")"
+}
+
+// Number returns the channel number, the differential pairs following the
+// single-ended channels.
+func (p *analogPin) Number() int {
+	switch p.c {
+	case Channel0Minus3, Channel1Minus3, Channel2Minus3:
+		return 4 + int(p.c&3)
+	case Channel0Minus1:
+		return 7
+	default:
+		return int(p.c)
+	}
+}
+
+func (p *analogPin) Function() string {
+	return string(p.Func())
+}
+
+// Func implements pin.PinFunc.
+func (p *analogPin) Func() pin.Func {
+	return analog.ADC
+}
+
+// SupportedFuncs implements pin.PinFunc.
+func (p *analogPin) SupportedFuncs() []pin.Func {
+	return []pin.Func{analog.ADC}
+}
+
+// SetFunc implements pin.PinFunc.
+func (p *analogPin) SetFunc(f pin.Func) error {
+	if f == analog.ADC {
+		return nil
+	}
+	return errors.New("pcf8591: pin function cannot be changed")
+}
+
+func (p *analogPin) Halt() error {
+	return nil
+}
+
+func (p *analogPin) String() string {
+	return p.Name()
+}
+
+var _ conn.Resource = &Dev{}
+var _ analog.PinADC = &analogPin{}
+var _ pin.Pin = &analogPin{}
+var _ pin.PinFunc = &analogPin{}
@@ -0,0 +1,144 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package pcf8591
+
+import (
+	"reflect"
+	"testing"
+
+	"periph.io/x/conn/v3/analog"
+	"periph.io/x/conn/v3/i2c/i2ctest"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/pin"
+)
+
+func TestChannel_String(t *testing.T) {
+	data := []struct {
+		c        Channel
+		expected string
+	}{
+		{Channel0, "0"},
+		{Channel3, "3"},
+		{Channel1Minus3, "1-3"},
+		{Channel0Minus1, "0-1"},
+		{Channel(4), "Invalid"},
+		{Channel(0x20), "Invalid"},
+	}
+	for _, line := range data {
+		if actual := line.c.String(); actual != line.expected {
+			t.Fatalf("%s != %s", line.expected, actual)
+		}
+	}
+}
+
+func TestNewI2C(t *testing.T) {
+	if _, err := NewI2C(&i2ctest.Record{}, &Opts{Addr: 0x50, Vref: physic.Volt}); err == nil {
+		t.Fatal("expected error on address")
+	}
+	if _, err := NewI2C(&i2ctest.Record{}, &Opts{Addr: 0x48}); err == nil {
+		t.Fatal("expected error on Vref")
+	}
+	d, err := NewI2C(&i2ctest.Record{}, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := d.String(); s != "PCF8591{record(72)}" {
+		t.Fatal(s)
+	}
+	if _, err := d.PinForChannel(Channel(4)); err == nil {
+		t.Fatal("expected error on channel")
+	}
+}
+
+func TestPinADC(t *testing.T) {
+	d, err := NewI2C(&i2ctest.Record{}, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := d.PinForChannel(Channel2Minus3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := p.String(); v != "PCF8591(2-3)" {
+		t.Fatal(v)
+	}
+	if v := p.Number(); v != 6 {
+		t.Fatal(v)
+	}
+	if v := p.Function(); v != "ADC" {
+		t.Fatal(v)
+	}
+	if v := p.(pin.PinFunc).SupportedFuncs(); !reflect.DeepEqual(v, []pin.Func{analog.ADC}) {
+		t.Fatal(v)
+	}
+	if err := p.(pin.PinFunc).SetFunc(pin.FuncNone); err == nil {
+		t.Fatal("expected failure")
+	}
+	min, max := p.Range()
+	if min.Raw != -128 || min.V != -1650*physic.MilliVolt || max.Raw != 127 {
+		t.Fatal(min, max)
+	}
+	p, err = d.PinForChannel(Channel1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	min, max = p.Range()
+	if min.Raw != 0 || max.Raw != 255 || max.V != 3287109375*physic.NanoVolt {
+		t.Fatal(min, max)
+	}
+	if err := p.Halt(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRead_SetOutput(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x48, W: []byte{0x02}, R: []byte{0x10, 0x80}},
+			{Addr: 0x48, W: []byte{0x30}, R: []byte{0x00, 0xc0}},
+			{Addr: 0x48, W: []byte{0x40, 0x80}},
+			// The output stays enabled.
+			{Addr: 0x48, W: []byte{0x41}, R: []byte{0x80, 0xff}},
+			{Addr: 0x48, W: []byte{0x00}},
+			{Addr: 0x48, W: []byte{0x01}, R: []byte{0xff, 0x00}},
+		},
+	}
+	d, err := NewI2C(&bus, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	read := func(c Channel, expected analog.Sample) {
+		p, err := d.PinForChannel(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s, err := p.Read()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != expected {
+			t.Fatalf("%s: %v != %v", c, s, expected)
+		}
+	}
+	read(Channel2, analog.Sample{Raw: 128, V: 1650 * physic.MilliVolt})
+	read(Channel0Minus1, analog.Sample{Raw: -64, V: -825 * physic.MilliVolt})
+	if err := d.SetOutput(3300 * physic.MilliVolt); err == nil {
+		t.Fatal("expected error on output")
+	}
+	if err := d.SetOutput(-physic.MilliVolt); err == nil {
+		t.Fatal("expected error on output")
+	}
+	if err := d.SetOutput(1650 * physic.MilliVolt); err != nil {
+		t.Fatal(err)
+	}
+	read(Channel1, analog.Sample{Raw: 255, V: 3287109375 * physic.NanoVolt})
+	if err := d.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	read(Channel1, analog.Sample{})
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package devices
+
+import (
+	"fmt"
+
+	"periph.io/x/conn/v3/physic"
+)
+
+// PowerEnv represents the measurements of a power monitor.
+type PowerEnv struct {
+	// Voltage is the bus voltage, i.e. across the load.
+	Voltage physic.ElectricPotential
+	Current physic.ElectricCurrent
+	Power   physic.Power
+}
+
+func (p PowerEnv) String() string {
+	return fmt.Sprintf("Bus: %s, Current: %s, Power: %s", p.Voltage, p.Current, p.Power)
+}
+
+// PowerSensor is implemented by power monitors measuring the voltage, current
+// and power of a load.
+//
+// It lets power monitoring applications swap the hardware without code
+// changes. The individual methods read a single measurement, SensePower reads
+// all of them.
+type PowerSensor interface {
+	// Voltage returns the bus voltage.
+	Voltage() (physic.ElectricPotential, error)
+	// Current returns the current through the load.
+	Current() (physic.ElectricCurrent, error)
+	// Power returns the power drawn by the load.
+	Power() (physic.Power, error)
+	// SensePower reads all the measurements into e.
+	SensePower(e *PowerEnv) error
+}
@@ -0,0 +1,18 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package devices
+
+import (
+	"testing"
+
+	"periph.io/x/conn/v3/physic"
+)
+
+func TestPowerEnv_String(t *testing.T) {
+	p := PowerEnv{Voltage: 12 * physic.Volt, Current: 10 * physic.Ampere, Power: 120 * physic.Watt}
+	if s := p.String(); s != "Bus: 12V, Current: 10A, Power: 120W" {
+		t.Fatal(s)
+	}
+}
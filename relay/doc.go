@@ -0,0 +1,13 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package relay defines Bank, a common interface for relay boards, so
+// applications can switch relay hardware without code changes.
+//
+// Bank is implemented by ep0099.Dev and by GPIO, which drives boards whose
+// relays are controlled by one GPIO pin each. GPIO works with host pins, as on
+// the Waveshare RPi Relay Board, see NewWaveshareRPi, or with the pins of an
+// I/O expander like pcf857x or mcp23xxx. Sequent8 drives the Sequent
+// Microsystems 8-Relays HAT over I²C.
+package relay
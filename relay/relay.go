@@ -0,0 +1,148 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package relay
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpioreg"
+)
+
+// Bank is a set of relays, or other on/off outputs. Channels are numbered
+// from 1, like on the boards.
+type Bank interface {
+	conn.Resource
+	// On turns the relay on.
+	On(channel uint8) error
+	// Off turns the relay off.
+	Off(channel uint8) error
+	// Toggle turns the relay on if it is off, and off if it is on.
+	Toggle(channel uint8) error
+	// IsOn returns true if the relay is on.
+	IsOn(channel uint8) (bool, error)
+	// AvailableChannels returns the channels of the bank.
+	AvailableChannels() []uint8
+}
+
+// GPIO is a Bank of relays each controlled by a GPIO pin.
+type GPIO struct {
+	mu        sync.Mutex
+	pins      []gpio.PinOut
+	activeLow bool
+	on        []bool
+}
+
+// NewGPIO returns a Bank controlling a relay with each pin, pins[0] being
+// channel 1. Set activeLow if a relay is on when its pin is low, which is the
+// case of most relay modules.
+//
+// All the relays are turned off.
+func NewGPIO(pins []gpio.PinOut, activeLow bool) (*GPIO, error) {
+	if len(pins) == 0 || len(pins) > 255 {
+		return nil, errors.New("relay: invalid number of pins")
+	}
+	g := &GPIO{pins: pins, activeLow: activeLow, on: make([]bool, len(pins))}
+	if err := g.Halt(); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// NewWaveshareRPi returns the Bank of the Waveshare RPi Relay Board. Its 3
+// relays are controlled by GPIO26, GPIO20 and GPIO21 and are active low.
+//
+// The host must be initialized.
+func NewWaveshareRPi() (*GPIO, error) {
+	var pins []gpio.PinOut
+	for _, name := range []string{"GPIO26", "GPIO20", "GPIO21"} {
+		p := gpioreg.ByName(name)
+		if p == nil {
+			return nil, fmt.Errorf("relay: pin %s not found", name)
+		}
+		pins = append(pins, p)
+	}
+	return NewGPIO(pins, true)
+}
+
+func (g *GPIO) String() string {
+	return fmt.Sprintf("relay.GPIO%s", g.pins)
+}
+
+// Halt implements conn.Resource.
+//
+// It turns all the relays off.
+func (g *GPIO) Halt() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for i := range g.pins {
+		if err := g.set(i, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// On implements Bank.
+func (g *GPIO) On(channel uint8) error {
+	return g.setChannel(channel, func(bool) bool { return true })
+}
+
+// Off implements Bank.
+func (g *GPIO) Off(channel uint8) error {
+	return g.setChannel(channel, func(bool) bool { return false })
+}
+
+// Toggle implements Bank.
+func (g *GPIO) Toggle(channel uint8) error {
+	return g.setChannel(channel, func(on bool) bool { return !on })
+}
+
+// IsOn implements Bank.
+func (g *GPIO) IsOn(channel uint8) (bool, error) {
+	if channel < 1 || int(channel) > len(g.pins) {
+		return false, errInvalidChannel
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.on[channel-1], nil
+}
+
+// AvailableChannels implements Bank.
+func (g *GPIO) AvailableChannels() []uint8 {
+	c := make([]uint8, len(g.pins))
+	for i := range c {
+		c[i] = uint8(i + 1)
+	}
+	return c
+}
+
+// setChannel sets the relay to the state returned by f, called with the
+// current state.
+func (g *GPIO) setChannel(channel uint8, f func(on bool) bool) error {
+	if channel < 1 || int(channel) > len(g.pins) {
+		return errInvalidChannel
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.set(int(channel-1), f(g.on[channel-1]))
+}
+
+// set sets the relay i. The lock must be held.
+func (g *GPIO) set(i int, on bool) error {
+	l := gpio.Level(on != g.activeLow)
+	if err := g.pins[i].Out(l); err != nil {
+		return fmt.Errorf("relay: %w", err)
+	}
+	g.on[i] = on
+	return nil
+}
+
+var errInvalidChannel = errors.New("relay: invalid channel")
+
+var _ Bank = &GPIO{}
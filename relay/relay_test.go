@@ -0,0 +1,66 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package relay
+
+import (
+	"reflect"
+	"testing"
+
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpiotest"
+)
+
+func TestGPIO(t *testing.T) {
+	p1 := &gpiotest.Pin{N: "P1", L: gpio.Low}
+	p2 := &gpiotest.Pin{N: "P2", L: gpio.Low}
+	g, err := NewGPIO([]gpio.PinOut{p1, p2}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p1.L != gpio.High || p2.L != gpio.High {
+		t.Fatal("relays should be off")
+	}
+	if c := g.AvailableChannels(); !reflect.DeepEqual(c, []uint8{1, 2}) {
+		t.Fatal(c)
+	}
+	if err := g.On(2); err != nil {
+		t.Fatal(err)
+	}
+	if p2.L != gpio.Low {
+		t.Fatal("relay 2 should be on")
+	}
+	if on, err := g.IsOn(2); err != nil || !on {
+		t.Fatal(on, err)
+	}
+	if err := g.Toggle(2); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Toggle(1); err != nil {
+		t.Fatal(err)
+	}
+	if p1.L != gpio.Low || p2.L != gpio.High {
+		t.Fatal("relay 1 should be on and relay 2 off")
+	}
+	if err := g.Off(1); err != nil {
+		t.Fatal(err)
+	}
+	if on, err := g.IsOn(1); err != nil || on {
+		t.Fatal(on, err)
+	}
+	for _, c := range []uint8{0, 3} {
+		if err := g.On(c); err == nil {
+			t.Fatalf("expected error for channel %d", c)
+		}
+		if _, err := g.IsOn(c); err == nil {
+			t.Fatalf("expected error for channel %d", c)
+		}
+	}
+}
+
+func TestNewGPIO_fail(t *testing.T) {
+	if _, err := NewGPIO(nil, false); err == nil {
+		t.Fatal("expected error without pins")
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package relay
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"periph.io/x/conn/v3/i2c"
+)
+
+// Sequent8 is the Sequent Microsystems 8-Relays HAT.
+//
+// The relays are driven by a TCA9534 I/O expander whose outputs aren't wired
+// in the relays order.
+type Sequent8 struct {
+	mu sync.Mutex
+	c  i2c.Dev
+}
+
+// NewSequent8 returns the Bank of the Sequent Microsystems 8-Relays HAT at the
+// stack level stack, 0 to 7, as set by its jumpers.
+//
+// The expander is configured with all its pins as outputs and all the relays
+// are turned off.
+func NewSequent8(b i2c.Bus, stack uint8) (*Sequent8, error) {
+	if stack > 7 {
+		return nil, errors.New("relay: invalid Sequent8 stack level")
+	}
+	// The address is 0x38 to 0x3F, or 0x20 to 0x27 on the boards built with a
+	// PCA9534 expander.
+	var cfg [1]byte
+	s := &Sequent8{c: i2c.Dev{Bus: b, Addr: sequent8Addr + uint16(stack^7)}}
+	if err := s.c.Tx([]byte{tca9534Config}, cfg[:]); err != nil {
+		s.c.Addr = sequent8AltAddr + uint16(stack^7)
+		if err := s.c.Tx([]byte{tca9534Config}, cfg[:]); err != nil {
+			return nil, fmt.Errorf("relay: %w", err)
+		}
+	}
+	if err := s.Halt(); err != nil {
+		return nil, err
+	}
+	if cfg[0] != 0 {
+		if err := s.c.Tx([]byte{tca9534Config, 0}, nil); err != nil {
+			return nil, fmt.Errorf("relay: %w", err)
+		}
+	}
+	return s, nil
+}
+
+func (s *Sequent8) String() string {
+	return fmt.Sprintf("relay.Sequent8{%s}", &s.c)
+}
+
+// Halt implements conn.Resource.
+//
+// It turns all the relays off.
+func (s *Sequent8) Halt() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.write(0)
+}
+
+// On implements Bank.
+func (s *Sequent8) On(channel uint8) error {
+	return s.setChannel(channel, func(bool) bool { return true })
+}
+
+// Off implements Bank.
+func (s *Sequent8) Off(channel uint8) error {
+	return s.setChannel(channel, func(bool) bool { return false })
+}
+
+// Toggle implements Bank.
+func (s *Sequent8) Toggle(channel uint8) error {
+	return s.setChannel(channel, func(on bool) bool { return !on })
+}
+
+// IsOn implements Bank.
+//
+// The state is read from the board, so it reflects changes done by other
+// programs.
+func (s *Sequent8) IsOn(channel uint8) (bool, error) {
+	if channel < 1 || channel > 8 {
+		return false, errInvalidChannel
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, err := s.read()
+	if err != nil {
+		return false, err
+	}
+	return v&sequent8Masks[channel-1] != 0, nil
+}
+
+// AvailableChannels implements Bank.
+func (s *Sequent8) AvailableChannels() []uint8 {
+	return []uint8{1, 2, 3, 4, 5, 6, 7, 8}
+}
+
+//
+
+const (
+	sequent8Addr    = 0x38
+	sequent8AltAddr = 0x20
+)
+
+// TCA9534 registers.
+const (
+	tca9534Output = 0x01
+	tca9534Config = 0x03
+)
+
+// sequent8Masks is the output bit of each relay.
+var sequent8Masks = [8]byte{0x01, 0x04, 0x40, 0x10, 0x20, 0x80, 0x08, 0x02}
+
+// setChannel sets the relay to the state returned by f, called with the
+// current state.
+func (s *Sequent8) setChannel(channel uint8, f func(on bool) bool) error {
+	if channel < 1 || channel > 8 {
+		return errInvalidChannel
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, err := s.read()
+	if err != nil {
+		return err
+	}
+	m := sequent8Masks[channel-1]
+	if f(v&m != 0) {
+		v |= m
+	} else {
+		v &^= m
+	}
+	return s.write(v)
+}
+
+// read reads the output register. The lock must be held.
+func (s *Sequent8) read() (byte, error) {
+	var v [1]byte
+	if err := s.c.Tx([]byte{tca9534Output}, v[:]); err != nil {
+		return 0, fmt.Errorf("relay: %w", err)
+	}
+	return v[0], nil
+}
+
+// write writes the output register. The lock must be held.
+func (s *Sequent8) write(v byte) error {
+	if err := s.c.Tx([]byte{tca9534Output, v}, nil); err != nil {
+		return fmt.Errorf("relay: %w", err)
+	}
+	return nil
+}
+
+var _ Bank = &Sequent8{}
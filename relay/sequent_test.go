@@ -0,0 +1,72 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package relay
+
+import (
+	"testing"
+
+	"periph.io/x/conn/v3/i2c/i2ctest"
+)
+
+func TestSequent8(t *testing.T) {
+	const addr = 0x3E
+	bus := &i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// The expander pins are set as outputs, all off.
+			{Addr: addr, W: []byte{0x03}, R: []byte{0xFF}},
+			{Addr: addr, W: []byte{0x01, 0x00}},
+			{Addr: addr, W: []byte{0x03, 0x00}},
+			// On(2)
+			{Addr: addr, W: []byte{0x01}, R: []byte{0x00}},
+			{Addr: addr, W: []byte{0x01, 0x04}},
+			// IsOn(2)
+			{Addr: addr, W: []byte{0x01}, R: []byte{0x04}},
+			// Toggle(1)
+			{Addr: addr, W: []byte{0x01}, R: []byte{0x04}},
+			{Addr: addr, W: []byte{0x01, 0x05}},
+			// Off(2)
+			{Addr: addr, W: []byte{0x01}, R: []byte{0x05}},
+			{Addr: addr, W: []byte{0x01, 0x01}},
+			// IsOn(8)
+			{Addr: addr, W: []byte{0x01}, R: []byte{0x01}},
+		},
+	}
+	s, err := NewSequent8(bus, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.On(2); err != nil {
+		t.Fatal(err)
+	}
+	if on, err := s.IsOn(2); err != nil || !on {
+		t.Fatal(on, err)
+	}
+	if err := s.Toggle(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Off(2); err != nil {
+		t.Fatal(err)
+	}
+	if on, err := s.IsOn(8); err != nil || on {
+		t.Fatal(on, err)
+	}
+	for _, c := range []uint8{0, 9} {
+		if err := s.On(c); err == nil {
+			t.Fatalf("expected error for channel %d", c)
+		}
+		if _, err := s.IsOn(c); err == nil {
+			t.Fatalf("expected error for channel %d", c)
+		}
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewSequent8_fail(t *testing.T) {
+	if _, err := NewSequent8(&i2ctest.Playback{}, 8); err == nil {
+		t.Fatal("expected error for stack level 8")
+	}
+}
@@ -6,6 +6,14 @@
 // The scd4x family provide a compact sensor that can be used to measure
 // Temperature, Humidity, and CO2 concentration.
 //
+// Older SCD40 firmware doesn't implement the ASC period, ASC target and sensor
+// variant commands. They are detected once and Dev.Features reports what the
+// sensor supports; the matching DevConfig fields are left to their zero value
+// and must not be set when unsupported.
+//
+// Communication errors, like an invalid CRC on a long cable, are returned as
+// TransientError and can be retried automatically with Dev.SetRetryPolicy.
+//
 // Refer to the datasheet for more information.
 //
 // https://sensirion.com/media/documents/48C4B7FB/66E05452/CD_DS_SCD4x_Datasheet_D1.pdf
@@ -7,11 +7,13 @@ package scd4x
 import (
 	"errors"
 	"fmt"
+	"log"
 	"sync"
 	"time"
 
 	"periph.io/x/conn/v3/i2c"
 	"periph.io/x/conn/v3/physic"
+	"periph.io/x/devices/v3"
 )
 
 // PPM=Parts Per Million. Units of measure for CO2 concentration.
@@ -39,6 +41,59 @@ const (
 	SensorAddress uint16 = 0x62
 )
 
+// Features is the set of optional commands supported by the sensor firmware.
+// Older SCD40 firmware doesn't implement them and NAKs the commands.
+type Features struct {
+	// ASCPeriods is true if the ASC initial and standard periods are
+	// supported.
+	ASCPeriods bool
+	// ASCTarget is true if the ASC target is supported.
+	ASCTarget bool
+	// SensorVariant is true if the sensor reports whether it is an SCD40 or
+	// an SCD41.
+	SensorVariant bool
+}
+
+func (f Features) String() string {
+	return fmt.Sprintf("ASCPeriods: %t ASCTarget: %t SensorVariant: %t", f.ASCPeriods, f.ASCTarget, f.SensorVariant)
+}
+
+// ErrCRC is wrapped by TransientError when the CRC of a response is invalid.
+var ErrCRC = errors.New("invalid crc")
+
+// TransientError is returned when a command failed because of a communication
+// error, either an I²C error or an invalid CRC, which may not happen again if
+// the command is retried. Other errors are caused by invalid arguments or an
+// unsupported state of the sensor.
+type TransientError struct {
+	// Cmd is the command word that failed.
+	Cmd uint16
+	// Err is the I²C error or ErrCRC.
+	Err error
+}
+
+func (e *TransientError) Error() string {
+	return fmt.Sprintf("scd4x cmd 0x%x: %v", e.Cmd, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *TransientError) Unwrap() error {
+	return e.Err
+}
+
+// RetryPolicy sets how commands failing with a TransientError are retried.
+//
+// Commands that change the state of the sensor in a way that can't be
+// repeated safely, like starting a measurement or a factory reset, are never
+// retried.
+type RetryPolicy struct {
+	// Retries is the number of times a command is retried. 0 disables
+	// retries.
+	Retries int
+	// Delay is the time waited before each retry.
+	Delay time.Duration
+}
+
 type cmd uint16
 
 // Structure to simplify sending commands to the device.
@@ -50,12 +105,15 @@ type command struct {
 	// True if this command is permitted while the sensor is running in
 	// acquisition mode.
 	whileSensing bool
+	// True if this command must not be retried.
+	noRetry bool
 }
 
 // The various implemented commands.
 
 var cmdStartMeasurement = command{
 	cmdWord: 0x21b1,
+	noRetry: true,
 }
 
 var cmdReadMeasurement = command{
@@ -67,6 +125,7 @@ var cmdReadMeasurement = command{
 var cmdStopMeasurement = command{
 	cmdWord:      0x3f86,
 	whileSensing: true,
+	noRetry:      true,
 }
 var cmdGetTemperatureOffset = command{
 	cmdWord:      0x2318,
@@ -112,6 +171,7 @@ var cmdGetDataReadyStatus = command{
 }
 var cmdPersistSettings = command{
 	cmdWord: 0x3615,
+	noRetry: true,
 }
 var cmdGetSerialNumber = command{
 	cmdWord:      0x3682,
@@ -119,9 +179,11 @@ var cmdGetSerialNumber = command{
 }
 var cmdPerformFactoryReset = command{
 	cmdWord: 0x3632,
+	noRetry: true,
 }
 var cmdReinit = command{
 	cmdWord: 0x3646,
+	noRetry: true,
 }
 var cmdGetSensorVariant = command{
 	cmdWord:      0x202f,
@@ -143,12 +205,17 @@ var cmdSetASCStandardPeriod = command{
 }
 var cmdWakeUp = command{
 	cmdWord: 0x36f6,
+	// The sensor doesn't acknowledge this command.
+	noRetry: true,
 }
 
 // DevConfig is the current running configuration of the device. Values prefixed
 // with ASC refer to Auto-Self-Calibration. Use Dev.GetConfiguration() to read
 // the value, and Dev.SetConfiguration() to apply changes.
 //
+// The ASC periods, ASC target and sensor type are only valid if Dev.Features
+// reports them as supported. Otherwise they are left to their zero value.
+//
 // Refer to the datasheet for more information on settings.
 type DevConfig struct {
 	// Ambient pressure value. Used to adjust operation of sensor.
@@ -184,6 +251,18 @@ type Dev struct {
 	mu     sync.Mutex
 	// True if the device is in continuous sense mode.
 	sensing bool
+	// The optional commands supported by the firmware, valid once detected
+	// is true.
+	features Features
+	detected bool
+	// Optional source of the ambient pressure, pushed to the sensor at most
+	// every pressureInterval.
+	pressure         physic.SenseEnv
+	pressureInterval time.Duration
+	pressureUpdated  time.Time
+	// Communication health, reported by the devices.Health methods.
+	health devices.HealthCounter
+	retry  RetryPolicy
 }
 
 func (ppm *PPM) String() string {
@@ -209,15 +288,39 @@ func NewI2C(b i2c.Bus, addr uint16) (*Dev, error) {
 	return d, d.start()
 }
 
+// SetRetryPolicy sets how commands failing with a TransientError are retried,
+// e.g. to cope with glitches on long cables. By default commands are not
+// retried.
+//
+// It must not be called concurrently with other methods.
+func (d *Dev) SetRetryPolicy(p RetryPolicy) error {
+	if p.Retries < 0 || p.Delay < 0 {
+		return errors.New("scd4x: invalid retry policy")
+	}
+	d.retry = p
+	return nil
+}
+
 // GetConfiguration returns a structure containing all of the scd4x configuration
 // variables. You can then alter settings and call SetConfiguration with it.
+// Continuous sensing is stopped, as the sensor doesn't accept these commands
+// while measuring.
 //
 // To examine the device use:
 //
 //	cfg, _ :=dev.GetConfiguration()
 //	fmt.Printf("Configuration=%#v\n", cfg)
 func (d *Dev) GetConfiguration() (*DevConfig, error) {
+	if err := d.Halt(); err != nil {
+		return nil, err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.getConfiguration()
+}
 
+// getConfiguration implements GetConfiguration. The lock must be held.
+func (d *Dev) getConfiguration() (*DevConfig, error) {
 	cfg := &DevConfig{}
 	var words []uint16
 	var err error
@@ -232,32 +335,33 @@ func (d *Dev) GetConfiguration() (*DevConfig, error) {
 	}
 	cfg.ASCEnabled = words[0] != 0
 
-	if words, err = d.sendCommand(cmdGetASCInitialPeriod, nil); err != nil {
+	if words, err = d.getOptional(cmdGetASCInitialPeriod, &d.features.ASCPeriods); err != nil {
 		return nil, err
 	}
-	cfg.ASCInitialPeriod = time.Hour * time.Duration(words[0])
-
-	if words, err = d.sendCommand(cmdGetASCStandardPeriod, nil); err != nil {
-		return nil, err
+	if words != nil {
+		cfg.ASCInitialPeriod = time.Hour * time.Duration(words[0])
+		if words, err = d.sendCommand(cmdGetASCStandardPeriod, nil); err != nil {
+			return nil, err
+		}
+		cfg.ASCStandardPeriod = time.Hour * time.Duration(words[0])
 	}
-	cfg.ASCStandardPeriod = time.Hour * time.Duration(words[0])
 
-	if words, err = d.sendCommand(cmdGetASCTarget, nil); err != nil {
+	if words, err = d.getOptional(cmdGetASCTarget, &d.features.ASCTarget); err != nil {
 		return nil, err
 	}
-	cfg.ASCTarget = PPM(words[0])
+	if words != nil {
+		cfg.ASCTarget = PPM(words[0])
+	}
 
 	if words, err = d.sendCommand(cmdGetSerialNumber, nil); err != nil {
 		return nil, err
 	}
 	cfg.SerialNumber = int64(words[0])<<32 | int64(words[1])<<16 | int64(words[2])
 
-	if words, err = d.sendCommand(cmdGetSensorVariant, nil); err != nil {
+	if words, err = d.getOptional(cmdGetSensorVariant, &d.features.SensorVariant); err != nil {
 		return nil, err
 	}
-	if (words[0]>>11)&0x07 == 0 {
-		cfg.SensorType = SCD40
-	} else {
+	if words != nil && (words[0]>>11)&0x07 != 0 {
 		cfg.SensorType = SCD41
 	}
 
@@ -271,9 +375,27 @@ func (d *Dev) GetConfiguration() (*DevConfig, error) {
 	}
 	cfg.TemperatureOffset = countToOffset(words[0])
 
+	d.detected = true
 	return cfg, nil
 }
 
+// Features returns the optional commands supported by the sensor firmware.
+// They are detected on the first call to Features or GetConfiguration.
+func (d *Dev) Features() (Features, error) {
+	d.mu.Lock()
+	detected, f := d.detected, d.features
+	d.mu.Unlock()
+	if detected {
+		return f, nil
+	}
+	if _, err := d.GetConfiguration(); err != nil {
+		return Features{}, err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.features, nil
+}
+
 // SetConfiguration alters the configuration of the sensor. Note that this call
 // does not persist the settings to EEPROM. You need to call Persist() to
 // commit the writes to EEPROM. If you do not persist changes, then those settings
@@ -285,7 +407,7 @@ func (d *Dev) SetConfiguration(newCfg *DevConfig) error {
 	defer d.mu.Unlock()
 
 	w := make([]uint16, 1)
-	currentConfig, err := d.GetConfiguration()
+	currentConfig, err := d.getConfiguration()
 	if err != nil {
 		return fmt.Errorf("scd4x GetConfiguration(): %w", err)
 	}
@@ -311,6 +433,13 @@ func (d *Dev) SetConfiguration(newCfg *DevConfig) error {
 		}
 	}
 
+	if err := checkSupported(d.features.ASCPeriods, newCfg.ASCInitialPeriod == 0 && newCfg.ASCStandardPeriod == 0, "ASC periods"); err != nil {
+		return err
+	}
+	if err := checkSupported(d.features.ASCTarget, newCfg.ASCTarget == 0, "ASC target"); err != nil {
+		return err
+	}
+
 	if currentConfig.ASCInitialPeriod != newCfg.ASCInitialPeriod {
 		if newCfg.ASCInitialPeriod%4 != 0 {
 			return fmt.Errorf("scd4x: invalid initial period %d. must be a multiple of 4", newCfg.ASCInitialPeriod)
@@ -361,6 +490,30 @@ func (d *Dev) SetConfiguration(newCfg *DevConfig) error {
 	return nil
 }
 
+// SetAmbientPressure sets the ambient pressure used to compensate the CO2
+// measurement, for example as measured by a barometer. Unlike
+// SetConfiguration, it can be called while sensing.
+func (d *Dev) SetAmbientPressure(p physic.Pressure) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.setAmbientPressure(p)
+}
+
+// SetPressureSource sets a sensor measuring the ambient pressure, for example
+// a bmxx80.Dev. The pressure is read from it and pushed to the SCD4x by Sense
+// and SenseContinuous, at most once every interval, to improve the accuracy of
+// the CO2 measurement.
+//
+// A failure to read the pressure source is logged and doesn't prevent the
+// measurement. Use nil to remove the source.
+func (d *Dev) SetPressureSource(s physic.SenseEnv, interval time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.pressure = s
+	d.pressureInterval = interval
+	d.pressureUpdated = time.Time{}
+}
+
 // Halt stops continuous sensing if enabled, and if a SenseContinuous operation
 // is in progress, it too is halted.
 func (d *Dev) Halt() error {
@@ -402,6 +555,39 @@ func (d *Dev) Reset(mode ResetMode) error {
 	return err
 }
 
+// checkSupported returns an error if a feature not supported by the firmware
+// is set.
+func checkSupported(supported, unset bool, name string) error {
+	if !supported && !unset {
+		return fmt.Errorf("scd4x: %s not supported by the sensor firmware", name)
+	}
+	return nil
+}
+
+// getOptional sends a get command that older firmware may not support. It
+// returns nil words if the command isn't supported.
+//
+// Until the features are detected, a failure is told apart from an
+// unsupported command by sending a command that every firmware supports.
+func (d *Dev) getOptional(cmd command, supported *bool) ([]uint16, error) {
+	if d.detected && !*supported {
+		return nil, nil
+	}
+	words, err := d.sendCommand(cmd, nil)
+	if err == nil {
+		*supported = true
+		return words, nil
+	}
+	if d.detected {
+		return nil, err
+	}
+	if _, err2 := d.sendCommand(cmdGetASCEnabled, nil); err2 != nil {
+		return nil, err
+	}
+	*supported = false
+	return nil, nil
+}
+
 func calcCRC(bytes []byte) byte {
 	polynomial := byte(0x31)
 	crc := byte(0xff)
@@ -431,6 +617,9 @@ func makeWriteData(data []uint16) []byte {
 }
 
 // All commands to read or write to the sensor go through this function.
+//
+// Commands failing with a TransientError are retried according to the retry
+// policy.
 func (d *Dev) sendCommand(cmd command, writeData []uint16) ([]uint16, error) {
 
 	if d.sensing && !cmd.whileSensing {
@@ -440,6 +629,22 @@ func (d *Dev) sendCommand(cmd command, writeData []uint16) ([]uint16, error) {
 		}
 	}
 
+	retries := d.retry.Retries
+	if cmd.noRetry {
+		retries = 0
+	}
+	for i := 0; ; i++ {
+		words, err := d.tx(cmd, writeData)
+		var te *TransientError
+		if err == nil || i >= retries || !errors.As(err, &te) {
+			return words, err
+		}
+		time.Sleep(d.retry.Delay)
+	}
+}
+
+// tx sends a command once.
+func (d *Dev) tx(cmd command, writeData []uint16) ([]uint16, error) {
 	w := make([]byte, 2)
 	w[0] = byte((cmd.cmdWord >> 8) & 0xff)
 	w[1] = byte(cmd.cmdWord & 0xff)
@@ -452,9 +657,9 @@ func (d *Dev) sendCommand(cmd command, writeData []uint16) ([]uint16, error) {
 		r = make([]byte, cmd.responseSize)
 	}
 
-	err := d.d.Tx(w, r)
+	err := d.health.Record(d.d.Tx(w, r))
 	if err != nil {
-		return nil, fmt.Errorf("scd4x cmd 0x%x: %w", cmd.cmdWord, err)
+		return nil, &TransientError{Cmd: uint16(cmd.cmdWord), Err: err}
 	}
 	if cmd.responseSize == 0 {
 		return nil, nil
@@ -466,7 +671,7 @@ func (d *Dev) sendCommand(cmd command, writeData []uint16) ([]uint16, error) {
 	for ix := range len(result) {
 		crc := calcCRC(r[ix*3 : ix*3+2])
 		if r[ix*3+2] != crc {
-			return nil, fmt.Errorf("scd4x cmd 0x%x: invalid crc", cmd.cmdWord)
+			return nil, d.health.RecordCRCError(&TransientError{Cmd: uint16(cmd.cmdWord), Err: ErrCRC})
 		}
 
 		word := uint16(r[ix*3])<<8 | uint16(r[ix*3+1])
@@ -477,6 +682,31 @@ func (d *Dev) sendCommand(cmd command, writeData []uint16) ([]uint16, error) {
 	return result, nil
 }
 
+func (d *Dev) setAmbientPressure(p physic.Pressure) error {
+	_, err := d.sendCommand(cmdSetAmbientPressure, []uint16{uint16(p / (100 * physic.Pascal))})
+	return err
+}
+
+// updatePressure pushes the pressure read from the pressure source to the
+// sensor if it is due.
+func (d *Dev) updatePressure() error {
+	if d.pressure == nil || time.Since(d.pressureUpdated) < d.pressureInterval {
+		return nil
+	}
+	e := physic.Env{}
+	if err := d.pressure.Sense(&e); err != nil {
+		return err
+	}
+	if e.Pressure == 0 {
+		return fmt.Errorf("%s doesn't measure pressure", d.pressure)
+	}
+	if err := d.setAmbientPressure(e.Pressure); err != nil {
+		return err
+	}
+	d.pressureUpdated = time.Now()
+	return nil
+}
+
 // start continuous sensing.
 func (d *Dev) start() error {
 	if d.sensing {
@@ -540,6 +770,10 @@ func (d *Dev) Sense(env *Env) error {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
+	if err := d.updatePressure(); err != nil {
+		log.Printf("%s: failed to update ambient pressure: %v", d, err)
+	}
+
 	ready := false
 	mask := uint16(1<<11 - 1)
 	tCutoff := time.Now().Unix() + 6
@@ -621,3 +855,20 @@ func (d *Dev) Precision(env *Env) {
 func (d *Dev) String() string {
 	return fmt.Sprintf("scd4x: %s", d.d.String())
 }
+
+// LastError implements devices.Health.
+func (d *Dev) LastError() error {
+	return d.health.LastError()
+}
+
+// TxCount implements devices.Health.
+func (d *Dev) TxCount() uint64 {
+	return d.health.TxCount()
+}
+
+// CRCErrorCount implements devices.Health.
+func (d *Dev) CRCErrorCount() uint64 {
+	return d.health.CRCErrorCount()
+}
+
+var _ devices.Health = &Dev{}
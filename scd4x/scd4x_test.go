@@ -10,6 +10,7 @@
 package scd4x
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"sync"
@@ -21,6 +22,7 @@ import (
 	"periph.io/x/conn/v3/i2c/i2creg"
 	"periph.io/x/conn/v3/i2c/i2ctest"
 	"periph.io/x/conn/v3/physic"
+	"periph.io/x/devices/v3/faulttest"
 	"periph.io/x/host/v3"
 )
 
@@ -220,6 +222,79 @@ func TestBasic(t *testing.T) {
 	}
 }
 
+func TestSetAmbientPressure(t *testing.T) {
+	crc := calcCRC([]byte{0x3, 0xf5})
+	dev, err := getDev(t, append(basicStartup, i2ctest.IO{Addr: SensorAddress, W: []uint8{0xe0, 0x0, 0x3, 0xf5, crc}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = dev.Halt() }()
+	defer shutdown(t)
+	if err := dev.SetAmbientPressure(101325 * physic.Pascal); err != nil {
+		t.Error(err)
+	}
+}
+
+type fakeBarometer struct {
+	p     physic.Pressure
+	count int
+}
+
+func (f *fakeBarometer) String() string { return "barometer" }
+
+func (f *fakeBarometer) Halt() error { return nil }
+
+func (f *fakeBarometer) Sense(e *physic.Env) error {
+	f.count++
+	e.Pressure = f.p
+	return nil
+}
+
+func (f *fakeBarometer) SenseContinuous(time.Duration) (<-chan physic.Env, error) {
+	return nil, nil
+}
+
+func (f *fakeBarometer) Precision(e *physic.Env) {
+	e.Pressure = physic.Pascal
+}
+
+func TestSetPressureSource(t *testing.T) {
+	if liveDevice {
+		t.Skip("requires playback")
+	}
+	crc := calcCRC([]byte{0x3, 0xf5})
+	ready := i2ctest.IO{Addr: SensorAddress, W: []uint8{0xe4, 0xb8}, R: []uint8{0x80, 0x6, 0x4}}
+	read := i2ctest.IO{Addr: SensorAddress, W: []uint8{0xec, 0x5}, R: []uint8{0x2, 0x2c, 0xa3, 0x67, 0xd, 0x36, 0x4d, 0x8, 0xf1}}
+	ops := append([]i2ctest.IO{}, basicStartup...)
+	ops = append(ops,
+		i2ctest.IO{Addr: SensorAddress, W: []uint8{0xe0, 0x0, 0x3, 0xf5, crc}},
+		ready, read,
+		// The pressure is not pushed again before the interval elapsed.
+		ready, read)
+	dev, err := getDev(t, ops)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = dev.Halt() }()
+	b := &fakeBarometer{p: 101325 * physic.Pascal}
+	dev.SetPressureSource(b, time.Hour)
+	for i := 0; i < 2; i++ {
+		env := Env{}
+		if err := dev.Sense(&env); err != nil {
+			t.Fatal(err)
+		}
+		if env.CO2 != 556 {
+			t.Fatal(env.CO2)
+		}
+	}
+	if b.count != 1 {
+		t.Fatalf("pressure read %d times", b.count)
+	}
+	if pb := bus.(*i2ctest.Playback); pb.Count != len(ops) {
+		t.Fatalf("%d operations out of %d", pb.Count, len(ops))
+	}
+}
+
 func TestSense(t *testing.T) {
 	dev, err := getDev(t, sensePlayback)
 	if err != nil {
@@ -341,6 +416,107 @@ func TestGetSetConfiguration(t *testing.T) {
 	_ = dev.Reset(ResetEEPROM) // and go back to our known state.
 }
 
+// playback values for TestFeatures_OldFirmware. The optional commands are NAKed
+// by faulttest and don't reach the playback.
+var oldFirmwarePlayback = []i2ctest.IO{
+	{Addr: SensorAddress, W: []uint8{0x36, 0xf6}},
+	{Addr: SensorAddress, W: []uint8{0x21, 0xb1}},
+	{Addr: SensorAddress, W: []uint8{0x3f, 0x86}},
+	{Addr: SensorAddress, W: []uint8{0xe0, 0x0}, R: []uint8{0x0, 0x5, 0x74}},
+	{Addr: SensorAddress, W: []uint8{0x23, 0x13}, R: []uint8{0x0, 0x1, 0xb0}},
+	{Addr: SensorAddress, W: []uint8{0x23, 0x13}, R: []uint8{0x0, 0x1, 0xb0}},
+	{Addr: SensorAddress, W: []uint8{0x23, 0x13}, R: []uint8{0x0, 0x1, 0xb0}},
+	{Addr: SensorAddress, W: []uint8{0x36, 0x82}, R: []uint8{0x73, 0xb1, 0x19, 0xeb, 0x7, 0x7a, 0x3b, 0xc, 0x54}},
+	{Addr: SensorAddress, W: []uint8{0x23, 0x13}, R: []uint8{0x0, 0x1, 0xb0}},
+	{Addr: SensorAddress, W: []uint8{0x23, 0x22}, R: []uint8{0x0, 0x0, 0x81}},
+	{Addr: SensorAddress, W: []uint8{0x23, 0x18}, R: []uint8{0x5, 0xda, 0x29}},
+	// GetConfiguration
+	{Addr: SensorAddress, W: []uint8{0xe0, 0x0}, R: []uint8{0x0, 0x5, 0x74}},
+	{Addr: SensorAddress, W: []uint8{0x23, 0x13}, R: []uint8{0x0, 0x1, 0xb0}},
+	{Addr: SensorAddress, W: []uint8{0x36, 0x82}, R: []uint8{0x73, 0xb1, 0x19, 0xeb, 0x7, 0x7a, 0x3b, 0xc, 0x54}},
+	{Addr: SensorAddress, W: []uint8{0x23, 0x22}, R: []uint8{0x0, 0x0, 0x81}},
+	{Addr: SensorAddress, W: []uint8{0x23, 0x18}, R: []uint8{0x5, 0xda, 0x29}},
+	// SetConfiguration
+	{Addr: SensorAddress, W: []uint8{0xe0, 0x0}, R: []uint8{0x0, 0x5, 0x74}},
+	{Addr: SensorAddress, W: []uint8{0x23, 0x13}, R: []uint8{0x0, 0x1, 0xb0}},
+	{Addr: SensorAddress, W: []uint8{0x36, 0x82}, R: []uint8{0x73, 0xb1, 0x19, 0xeb, 0x7, 0x7a, 0x3b, 0xc, 0x54}},
+	{Addr: SensorAddress, W: []uint8{0x23, 0x22}, R: []uint8{0x0, 0x0, 0x81}},
+	{Addr: SensorAddress, W: []uint8{0x23, 0x18}, R: []uint8{0x5, 0xda, 0x29}},
+	{Addr: SensorAddress, W: []uint8{0x24, 0x27, 0x6, 0x44, 0x22}},
+	// SetConfiguration with an unsupported field.
+	{Addr: SensorAddress, W: []uint8{0xe0, 0x0}, R: []uint8{0x0, 0x5, 0x74}},
+	{Addr: SensorAddress, W: []uint8{0x23, 0x13}, R: []uint8{0x0, 0x1, 0xb0}},
+	{Addr: SensorAddress, W: []uint8{0x36, 0x82}, R: []uint8{0x73, 0xb1, 0x19, 0xeb, 0x7, 0x7a, 0x3b, 0xc, 0x54}},
+	{Addr: SensorAddress, W: []uint8{0x23, 0x22}, R: []uint8{0x0, 0x0, 0x81}},
+	{Addr: SensorAddress, W: []uint8{0x23, 0x18}, R: []uint8{0x5, 0xda, 0x29}},
+}
+
+func TestFeatures_OldFirmware(t *testing.T) {
+	if liveDevice {
+		t.Skip("uses playback to simulate old firmware")
+	}
+	pb := &i2ctest.Playback{Ops: oldFirmwarePlayback, DontPanic: true}
+	// The ASC initial period, ASC target and sensor variant commands.
+	b := faulttest.NewI2C(pb,
+		faulttest.Fault{Tx: 5, Kind: faulttest.NAK},
+		faulttest.Fault{Tx: 7, Kind: faulttest.NAK},
+		faulttest.Fault{Tx: 10, Kind: faulttest.NAK})
+	dev, err := NewI2C(b, SensorAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := dev.Features()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f != (Features{}) {
+		t.Fatalf("unexpected features %s", f)
+	}
+	// The features are detected once; unsupported commands aren't sent again.
+	cfg, err := dev.GetConfiguration()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.ASCInitialPeriod != 0 || cfg.ASCStandardPeriod != 0 || cfg.ASCTarget != 0 || cfg.SensorType != SCD40 {
+		t.Fatalf("unsupported fields not zero: %#v", cfg)
+	}
+	if !cfg.ASCEnabled || cfg.SerialNumber == 0 {
+		t.Fatalf("unexpected configuration: %#v", cfg)
+	}
+
+	cfg.SensorAltitude = 1604 * physic.Metre
+	if err := dev.SetConfiguration(cfg); err != nil {
+		t.Fatal(err)
+	}
+	cfg.SensorAltitude = 0
+	cfg.ASCTarget = 420
+	if err := dev.SetConfiguration(cfg); err == nil {
+		t.Fatal("expected error setting an unsupported field")
+	}
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFeatures_Error(t *testing.T) {
+	if liveDevice {
+		t.Skip("uses playback to simulate a bus failure")
+	}
+	pb := &i2ctest.Playback{Ops: oldFirmwarePlayback[:5], DontPanic: true}
+	// A failure of the ASC initial period command followed by a failure of the
+	// probe is not mistaken for an unsupported command.
+	b := faulttest.NewI2C(pb,
+		faulttest.Fault{Tx: 5, Kind: faulttest.NAK},
+		faulttest.Fault{Tx: 6, Kind: faulttest.NAK})
+	dev, err := NewI2C(b, SensorAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dev.Features(); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
 // Since there are limited read/write cycles, by default DO NOT test persist
 // and reset factory. To perform the tests, define the environment variable
 // SCDRESET. Running this test will destructively clear customized values
@@ -449,3 +625,55 @@ func TestPersistAndResetFactory(t *testing.T) {
 		t.Errorf("Error resetting to factory. Sensor Altitude: %s expected 0m", cfg.SensorAltitude)
 	}
 }
+
+func TestRetryPolicy(t *testing.T) {
+	if liveDevice {
+		t.Skip("uses playback to simulate bus failures")
+	}
+	resp := makeWriteData([]uint16{1})
+	pb := &i2ctest.Playback{Ops: []i2ctest.IO{
+		{Addr: SensorAddress, W: []byte{0x23, 0x13}, R: resp},
+		{Addr: SensorAddress, W: []byte{0x23, 0x13}, R: resp},
+		{Addr: SensorAddress, W: []byte{0x23, 0x13}, R: resp},
+	}}
+	b := faulttest.NewI2C(pb,
+		faulttest.Fault{Tx: 0, Kind: faulttest.Corrupt},
+		faulttest.Fault{Tx: 1, Kind: faulttest.NAK},
+		faulttest.Fault{Tx: 3, Kind: faulttest.Corrupt},
+		faulttest.Fault{Tx: 4, Kind: faulttest.NAK})
+	dev := &Dev{d: &i2c.Dev{Bus: b, Addr: SensorAddress}}
+	if err := dev.SetRetryPolicy(RetryPolicy{Retries: -1}); err == nil {
+		t.Fatal("expected error")
+	}
+	if err := dev.SetRetryPolicy(RetryPolicy{Retries: 2}); err != nil {
+		t.Fatal(err)
+	}
+	words, err := dev.sendCommand(cmdGetASCEnabled, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if words[0] != 1 {
+		t.Fatal(words)
+	}
+	if err := dev.SetRetryPolicy(RetryPolicy{}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = dev.sendCommand(cmdGetASCEnabled, nil)
+	var te *TransientError
+	if !errors.As(err, &te) || !errors.Is(err, ErrCRC) || te.Cmd != 0x2313 {
+		t.Fatalf("expected CRC error, got %v", err)
+	}
+	if err.Error() != "scd4x cmd 0x2313: invalid crc" {
+		t.Fatal(err)
+	}
+	// Commands changing the sensor state are not retried.
+	if err := dev.SetRetryPolicy(RetryPolicy{Retries: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dev.sendCommand(cmdStartMeasurement, nil); !errors.Is(err, faulttest.ErrNAK) {
+		t.Fatalf("expected NAK, got %v", err)
+	}
+	if err := pb.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,23 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package sds011 controls a Nova Fitness SDS011 particulate matter sensor
+// over its UART interface.
+//
+// The sensor is switched to the query reporting mode, so it only reports a
+// measurement of the PM2.5 and PM10 concentrations when asked by Sense. Its
+// fan and laser wear out, use Sleep or SetWorkingPeriod to extend their
+// lifetime. After waking up, the measurements stabilize in about 30 seconds.
+//
+// Multiple sensors sharing a line are addressed by their ID, see Opts.ID and
+// SetID.
+//
+// # Datasheet
+//
+// https://cdn-reichelt.de/documents/datenblatt/X200/SDS011-DATASHEET.pdf
+//
+// # Protocol
+//
+// https://cdn.sparkfun.com/assets/parts/1/2/2/7/5/Laser_Dust_Sensor_Control_Protocol_V1.3.pdf
+package sds011
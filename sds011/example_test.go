@@ -0,0 +1,38 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sds011_test
+
+import (
+	"fmt"
+	"log"
+
+	"periph.io/x/conn/v3/uart/uartreg"
+	"periph.io/x/devices/v3/sds011"
+	"periph.io/x/host/v3"
+)
+
+func Example() {
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+	// Open default UART port.
+	p, err := uartreg.Open("")
+	if err != nil {
+		log.Fatalf("failed to open UART: %v", err)
+	}
+	defer p.Close()
+
+	dev, err := sds011.NewUART(p, &sds011.DefaultOpts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer dev.Halt()
+	var m sds011.Measurement
+	if err := dev.Sense(&m); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(m)
+}
@@ -0,0 +1,316 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sds011
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/uart"
+)
+
+// Baud is the fixed UART speed.
+const Baud = 9600 * physic.Hertz
+
+// AllDevices is the ID addressing all the sensors on the line.
+const AllDevices uint16 = 0xFFFF
+
+// Concentration is a mass concentration stored as an uint32 nanogram per
+// cubic metre.
+//
+// There is no mass concentration unit in periph.io/x/conn/v3/physic.
+type Concentration uint32
+
+// MicrogramPerCubicMetre is a mass concentration of 1µg/m³.
+const MicrogramPerCubicMetre Concentration = 1000
+
+func (c Concentration) String() string {
+	return strconv.FormatFloat(float64(c)/float64(MicrogramPerCubicMetre), 'f', -1, 64) + "µg/m³"
+}
+
+// Measurement is a measurement of the particulate matter concentrations.
+type Measurement struct {
+	// PM25 is the concentration of the particles smaller than 2.5µm.
+	PM25 Concentration
+	// PM10 is the concentration of the particles smaller than 10µm.
+	PM10 Concentration
+}
+
+func (m Measurement) String() string {
+	return fmt.Sprintf("PM2.5: %s, PM10: %s", m.PM25, m.PM10)
+}
+
+// Opts holds the configuration options.
+type Opts struct {
+	// ID of the sensor to control. AllDevices, the default when 0, is fine
+	// when the sensor is alone on the line.
+	ID uint16
+}
+
+// DefaultOpts is the recommended default options.
+var DefaultOpts = Opts{
+	ID: AllDevices,
+}
+
+// NewUART opens a handle to the sensor connected to p.
+func NewUART(p uart.Port, opts *Opts) (*Dev, error) {
+	c, err := p.Connect(Baud, uart.One, uart.NoParity, uart.NoFlow, 8)
+	if err != nil {
+		return nil, err
+	}
+	return New(c, opts)
+}
+
+// New opens a handle to the sensor on an already configured connection.
+//
+// The sensor is switched to the query reporting mode.
+func New(c conn.Conn, opts *Opts) (*Dev, error) {
+	d := &Dev{c: c, id: opts.ID}
+	if d.id == 0 {
+		d.id = AllDevices
+	}
+	if _, err := d.command(cmdReportingMode, setBit, reportingQuery); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Dev is a handle to a SDS011.
+type Dev struct {
+	mu sync.Mutex
+	c  conn.Conn
+	id uint16
+}
+
+func (d *Dev) String() string {
+	if d.id == AllDevices {
+		return fmt.Sprintf("SDS011{%s}", d.c)
+	}
+	return fmt.Sprintf("SDS011{%s, %#04x}", d.c, d.id)
+}
+
+// Sense queries a measurement.
+//
+// The sensor doesn't reply while asleep, the read then fails with the timeout
+// of the connection, if any.
+func (d *Dev) Sense(m *Measurement) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.send(cmdQuery); err != nil {
+		return err
+	}
+	r, err := d.receive(replyData, 0)
+	if err != nil {
+		return err
+	}
+	m.PM25 = Concentration(uint16(r[2])|uint16(r[3])<<8) * MicrogramPerCubicMetre / 10
+	m.PM10 = Concentration(uint16(r[4])|uint16(r[5])<<8) * MicrogramPerCubicMetre / 10
+	return nil
+}
+
+// Sleep stops the fan and the laser.
+func (d *Dev) Sleep() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, err := d.command(cmdSleepWork, setBit, 0)
+	return err
+}
+
+// Wake starts the fan and the laser.
+//
+// The measurements are stable after about 30 seconds.
+func (d *Dev) Wake() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, err := d.command(cmdSleepWork, setBit, 1)
+	return err
+}
+
+// SetWorkingPeriod sets the sensor to work 30 seconds every period of the
+// given minutes, from 1 to 30, sleeping in between. 0 restores the continuous
+// mode.
+//
+// The setting is persistent.
+func (d *Dev) SetWorkingPeriod(minutes int) error {
+	if minutes < 0 || minutes > 30 {
+		return fmt.Errorf("sds011: invalid working period %d", minutes)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, err := d.command(cmdWorkingPeriod, setBit, byte(minutes))
+	return err
+}
+
+// SetID changes the ID of the sensor. The handle addresses the sensor with
+// the new ID afterward.
+//
+// When the handle uses AllDevices, the sensor must be alone on the line. The
+// setting is persistent.
+func (d *Dev) SetID(id uint16) error {
+	if id == AllDevices {
+		return errors.New("sds011: invalid ID")
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var data [12]byte
+	data[10] = byte(id >> 8)
+	data[11] = byte(id)
+	if err := d.send(cmdSetID, data[:]...); err != nil {
+		return err
+	}
+	// The reply is sent with the new ID.
+	d.id = id
+	if _, err := d.receive(replyCommand, cmdSetID); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FirmwareVersion returns the version of the firmware, which is its date as
+// YY-MM-DD.
+func (d *Dev) FirmwareVersion() (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	r, err := d.command(cmdFirmware)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%02d-%02d-%02d", r[3], r[4], r[5]), nil
+}
+
+// Halt puts the sensor to sleep.
+//
+// Halt implements conn.Resource.
+func (d *Dev) Halt() error {
+	return d.Sleep()
+}
+
+//
+
+// Frames.
+const (
+	head      = 0xAA
+	tail      = 0xAB
+	commandID = 0xB4
+	// replyData is the kind of the measurement frames.
+	replyData = 0xC0
+	// replyCommand is the kind of the replies to the other commands.
+	replyCommand = 0xC5
+
+	commandLen = 19
+	replyLen   = 10
+)
+
+// maxSkipped is the number of bytes skipped while looking for a frame head
+// and maxReplies the number of frames read while looking for a reply.
+const (
+	maxSkipped = 2 * replyLen
+	maxReplies = 3
+)
+
+// Commands, sent as the first data byte.
+const (
+	cmdReportingMode = 2
+	cmdQuery         = 4
+	cmdSetID         = 5
+	cmdSleepWork     = 6
+	cmdFirmware      = 7
+	cmdWorkingPeriod = 8
+)
+
+// setBit is the first data byte of the commands that change a setting
+// rather than querying it.
+const setBit = 1
+
+// reportingQuery is the query reporting mode, the active mode being 0.
+const reportingQuery = 1
+
+var errChecksum = errors.New("sds011: invalid checksum")
+
+// command sends cmd with the data and returns its reply.
+func (d *Dev) command(cmd byte, data ...byte) ([replyLen]byte, error) {
+	if err := d.send(cmd, data...); err != nil {
+		return [replyLen]byte{}, err
+	}
+	return d.receive(replyCommand, cmd)
+}
+
+// send sends cmd followed by up to 12 data bytes.
+func (d *Dev) send(cmd byte, data ...byte) error {
+	var w [commandLen]byte
+	w[0] = head
+	w[1] = commandID
+	w[2] = cmd
+	copy(w[3:15], data)
+	w[15] = byte(d.id >> 8)
+	w[16] = byte(d.id)
+	w[17] = checksum(w[2:17])
+	w[18] = tail
+	if err := d.c.Tx(w[:], nil); err != nil {
+		return fmt.Errorf("sds011: %v", err)
+	}
+	return nil
+}
+
+// receive reads the reply of the given kind, skipping the frames that don't
+// match like the measurements reported in active mode. For replyCommand, the
+// reply must be for cmd.
+func (d *Dev) receive(kind, cmd byte) ([replyLen]byte, error) {
+	for i := 0; i < maxReplies; i++ {
+		r, err := d.readFrame()
+		if err != nil {
+			return r, err
+		}
+		if r[1] != kind || (kind == replyCommand && r[2] != cmd) {
+			continue
+		}
+		if id := uint16(r[6])<<8 | uint16(r[7]); d.id != AllDevices && id != d.id {
+			continue
+		}
+		return r, nil
+	}
+	return [replyLen]byte{}, errors.New("sds011: no reply")
+}
+
+// readFrame reads a 10 bytes frame, synchronizing on its head.
+func (d *Dev) readFrame() ([replyLen]byte, error) {
+	var r [replyLen]byte
+	for i := 0; ; i++ {
+		if err := d.c.Tx(nil, r[:1]); err != nil {
+			return r, fmt.Errorf("sds011: %v", err)
+		}
+		if r[0] == head {
+			break
+		}
+		if i == maxSkipped {
+			return r, errors.New("sds011: no frame head")
+		}
+	}
+	if err := d.c.Tx(nil, r[1:]); err != nil {
+		return r, fmt.Errorf("sds011: %v", err)
+	}
+	if r[9] != tail {
+		return r, errors.New("sds011: invalid frame tail")
+	}
+	if r[8] != checksum(r[2:8]) {
+		return r, errChecksum
+	}
+	return r, nil
+}
+
+// checksum is the sum of the data bytes.
+func checksum(b []byte) byte {
+	var s byte
+	for _, c := range b {
+		s += c
+	}
+	return s
+}
+
+var _ conn.Resource = &Dev{}
@@ -0,0 +1,191 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sds011
+
+import (
+	"errors"
+	"testing"
+
+	"periph.io/x/conn/v3"
+)
+
+// fakeUART emulates a SDS011.
+type fakeUART struct {
+	id       uint16
+	query    bool
+	sleeping bool
+	period   byte
+	// pm25 and pm10 are in 0.1µg/m³.
+	pm25, pm10 uint16
+	// noise is sent before the replies.
+	noise []byte
+	reply []byte
+}
+
+func (f *fakeUART) String() string {
+	return "fake"
+}
+
+func (f *fakeUART) Duplex() conn.Duplex {
+	return conn.Full
+}
+
+func (f *fakeUART) Tx(w, r []byte) error {
+	if w == nil {
+		if len(r) > len(f.reply) {
+			return errors.New("unexpected read")
+		}
+		copy(r, f.reply)
+		f.reply = f.reply[len(r):]
+		return nil
+	}
+	if len(w) != commandLen || w[0] != head || w[1] != commandID || w[18] != tail || w[17] != checksum(w[2:17]) {
+		return errors.New("invalid command")
+	}
+	if id := uint16(w[15])<<8 | uint16(w[16]); id != AllDevices && id != f.id {
+		return nil
+	}
+	f.reply = append([]byte{}, f.noise...)
+	switch w[2] {
+	case cmdReportingMode:
+		f.query = w[4] == reportingQuery
+		f.answer(replyCommand, w[2], w[3], w[4], 0)
+	case cmdQuery:
+		if !f.sleeping {
+			f.answer(replyData, byte(f.pm25), byte(f.pm25>>8), byte(f.pm10), byte(f.pm10>>8))
+		}
+	case cmdSetID:
+		f.id = uint16(w[13])<<8 | uint16(w[14])
+		f.answer(replyCommand, w[2], 0, 0, 0)
+	case cmdSleepWork:
+		f.sleeping = w[4] == 0
+		f.answer(replyCommand, w[2], w[3], w[4], 0)
+	case cmdFirmware:
+		f.answer(replyCommand, w[2], 18, 11, 16)
+	case cmdWorkingPeriod:
+		f.period = w[4]
+		f.answer(replyCommand, w[2], w[3], w[4], 0)
+	}
+	return nil
+}
+
+func (f *fakeUART) answer(kind byte, d ...byte) {
+	r := []byte{head, kind, d[0], d[1], d[2], d[3], byte(f.id >> 8), byte(f.id), 0, tail}
+	r[8] = checksum(r[2:8])
+	f.reply = append(f.reply, r...)
+}
+
+func TestNew(t *testing.T) {
+	f := &fakeUART{id: 0x1234}
+	d, err := New(f, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !f.query {
+		t.Fatal("query mode not set")
+	}
+	if s := d.String(); s != "SDS011{fake}" {
+		t.Fatal(s)
+	}
+	d, err = New(f, &Opts{ID: 0x1234})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := d.String(); s != "SDS011{fake, 0x1234}" {
+		t.Fatal(s)
+	}
+	if _, err := New(&fakeUART{id: 1}, &Opts{ID: 2}); err == nil {
+		t.Fatal("expected error with the wrong ID")
+	}
+}
+
+func TestSense(t *testing.T) {
+	f := &fakeUART{id: 0x1234, pm25: 123, pm10: 4567}
+	d, err := New(f, &Opts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Garbage and a stale reply to another command are skipped.
+	f.noise = []byte{0x00, head, replyCommand, cmdFirmware, 1, 2, 3, 0x12, 0x34, 0x53, tail}
+	var m Measurement
+	if err := d.Sense(&m); err != nil {
+		t.Fatal(err)
+	}
+	if m.PM25 != 12300 || m.PM10 != 456700 {
+		t.Fatal(m)
+	}
+	if s := m.String(); s != "PM2.5: 12.3µg/m³, PM10: 456.7µg/m³" {
+		t.Fatal(s)
+	}
+	f.noise = nil
+	if err := d.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	if !f.sleeping {
+		t.Fatal("not sleeping")
+	}
+	if err := d.Sense(&m); err == nil {
+		t.Fatal("expected error while sleeping")
+	}
+	if err := d.Wake(); err != nil {
+		t.Fatal(err)
+	}
+	if f.sleeping {
+		t.Fatal("sleeping")
+	}
+}
+
+func TestSettings(t *testing.T) {
+	f := &fakeUART{id: 0x1234}
+	d, err := New(f, &Opts{ID: 0x1234})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetWorkingPeriod(5); err != nil {
+		t.Fatal(err)
+	}
+	if f.period != 5 {
+		t.Fatal(f.period)
+	}
+	if err := d.SetWorkingPeriod(31); err == nil {
+		t.Fatal("expected error")
+	}
+	v, err := d.FirmwareVersion()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != "18-11-16" {
+		t.Fatal(v)
+	}
+	if err := d.SetID(AllDevices); err == nil {
+		t.Fatal("expected error")
+	}
+	if err := d.SetID(0xBEEF); err != nil {
+		t.Fatal(err)
+	}
+	if f.id != 0xBEEF {
+		t.Fatalf("%#x", f.id)
+	}
+	if err := d.Sleep(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadFrame_errors(t *testing.T) {
+	d := Dev{c: &fakeUART{}, id: AllDevices}
+	f := d.c.(*fakeUART)
+	f.reply = []byte{head, replyCommand, cmdFirmware, 1, 2, 3, 0, 0, 0, tail}
+	if _, err := d.readFrame(); err != errChecksum {
+		t.Fatal(err)
+	}
+	f.reply = []byte{head, replyCommand, cmdFirmware, 1, 2, 3, 0, 0, 6, 0}
+	if _, err := d.readFrame(); err == nil {
+		t.Fatal("expected error on tail")
+	}
+	f.reply = make([]byte, maxSkipped+1)
+	if _, err := d.readFrame(); err == nil {
+		t.Fatal("expected error on head")
+	}
+}
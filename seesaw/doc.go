@@ -0,0 +1,32 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package seesaw controls the Adafruit seesaw firmware over I²C.
+//
+// seesaw runs on a SAMD09 or an ATtiny8x7/16x7 microcontroller and is used as
+// an I/O bridge by many Adafruit breakouts, e.g. the rotary encoder, the soil
+// sensor, the gamepad and the NeoKey boards.
+//
+// # More Details
+//
+// The firmware is split in modules. Dev.Modules reports the ones built into
+// the firmware; this package supports:
+//
+// - GPIO: pin modes, levels and interrupts, set in bulk with pin bitmasks.
+//
+// - ADC: 10 bits analog readings.
+//
+// - PWM: duty cycle and frequency of the PWM capable pins.
+//
+// - NeoPixel: a strip of WS2812 LEDs driven by one of the pins.
+//
+// - Encoder: the position of the rotary encoders.
+//
+// The pin numbers are the ones of the microcontroller, as printed on the
+// breakout or documented in its guide.
+//
+// # Datasheet
+//
+// https://learn.adafruit.com/adafruit-seesaw-atsamd09-breakout/reading-and-writing-data
+package seesaw
@@ -0,0 +1,53 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package seesaw
+
+import (
+	"encoding/binary"
+)
+
+// EncoderPosition returns the position of the rotary encoder n, counted in
+// detents. Boards with a single encoder use n = 0.
+func (d *Dev) EncoderPosition(n uint8) (int32, error) {
+	return d.readEncoder(encoderPosition + n)
+}
+
+// SetEncoderPosition sets the position of the rotary encoder n.
+func (d *Dev) SetEncoderPosition(n uint8, pos int32) error {
+	return d.write32(ModuleEncoder, encoderPosition+n, uint32(pos))
+}
+
+// EncoderDelta returns the change of position of the rotary encoder n since
+// the last call.
+func (d *Dev) EncoderDelta(n uint8) (int32, error) {
+	return d.readEncoder(encoderDelta + n)
+}
+
+// SetEncoderInterrupt enables or disables the interrupt of the rotary
+// encoder n, asserted when its position changes.
+func (d *Dev) SetEncoderInterrupt(n uint8, enable bool) error {
+	if enable {
+		return d.write(ModuleEncoder, encoderIntEnSet+n, 0x01)
+	}
+	return d.write(ModuleEncoder, encoderIntEnClr+n, 0x01)
+}
+
+//
+
+// Encoder module registers.
+const (
+	encoderIntEnSet = 0x10
+	encoderIntEnClr = 0x20
+	encoderPosition = 0x30
+	encoderDelta    = 0x40
+)
+
+func (d *Dev) readEncoder(fn uint8) (int32, error) {
+	var b [4]byte
+	if err := d.read(ModuleEncoder, fn, b[:], readDelay); err != nil {
+		return 0, err
+	}
+	return int32(binary.BigEndian.Uint32(b[:])), nil
+}
@@ -0,0 +1,63 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package seesaw_test
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"periph.io/x/conn/v3/i2c/i2creg"
+	"periph.io/x/devices/v3/seesaw"
+	"periph.io/x/host/v3"
+)
+
+func Example() {
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+	// Open default I²C bus.
+	bus, err := i2creg.Open("")
+	if err != nil {
+		log.Fatalf("failed to open I²C: %v", err)
+	}
+	defer bus.Close()
+
+	// Adafruit I²C rotary encoder board, the push button is on pin 24 and
+	// the NeoPixel on pin 6.
+	d, err := seesaw.NewI2C(bus, 0x36)
+	if err != nil {
+		log.Fatal(err)
+	}
+	const button = 1 << 24
+	if err := d.SetPinMode(button, seesaw.InputPullUp); err != nil {
+		log.Fatal(err)
+	}
+	if err := d.SetupNeoPixels(6, 1, 3); err != nil {
+		log.Fatal(err)
+	}
+	for {
+		pos, err := d.EncoderPosition(0)
+		if err != nil {
+			log.Fatal(err)
+		}
+		pins, err := d.DigitalRead()
+		if err != nil {
+			log.Fatal(err)
+		}
+		pressed := pins&button == 0
+		fmt.Printf("position: %d, pressed: %t\n", pos, pressed)
+		// Show the position in green, red when pressed.
+		c := []byte{byte(pos * 8), 0, 0}
+		if pressed {
+			c = []byte{0, 0xFF, 0}
+		}
+		if err := d.WriteNeoPixels(c); err != nil {
+			log.Fatal(err)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
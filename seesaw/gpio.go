@@ -0,0 +1,199 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package seesaw
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/physic"
+)
+
+// PinMode is the mode of a GPIO pin.
+type PinMode uint8
+
+// Pin modes.
+const (
+	Input PinMode = iota
+	Output
+	InputPullUp
+	InputPullDown
+)
+
+func (m PinMode) String() string {
+	switch m {
+	case Input:
+		return "Input"
+	case Output:
+		return "Output"
+	case InputPullUp:
+		return "InputPullUp"
+	case InputPullDown:
+		return "InputPullDown"
+	default:
+		return fmt.Sprintf("PinMode(%d)", m)
+	}
+}
+
+// SetPinMode sets the mode of the pins set in the bitmask pins.
+func (d *Dev) SetPinMode(pins uint32, m PinMode) error {
+	switch m {
+	case Input:
+		if err := d.write32(ModuleGPIO, gpioDirClr, pins); err != nil {
+			return err
+		}
+		return d.write32(ModuleGPIO, gpioPullEnClr, pins)
+	case Output:
+		return d.write32(ModuleGPIO, gpioDirSet, pins)
+	case InputPullUp, InputPullDown:
+		if err := d.write32(ModuleGPIO, gpioDirClr, pins); err != nil {
+			return err
+		}
+		if err := d.write32(ModuleGPIO, gpioPullEnSet, pins); err != nil {
+			return err
+		}
+		// The output register selects the pull direction.
+		if m == InputPullUp {
+			return d.write32(ModuleGPIO, gpioSet, pins)
+		}
+		return d.write32(ModuleGPIO, gpioClr, pins)
+	default:
+		return fmt.Errorf("seesaw: invalid pin mode %s", m)
+	}
+}
+
+// DigitalWrite sets the level of the output pins set in the bitmask pins.
+func (d *Dev) DigitalWrite(pins uint32, l gpio.Level) error {
+	if l {
+		return d.write32(ModuleGPIO, gpioSet, pins)
+	}
+	return d.write32(ModuleGPIO, gpioClr, pins)
+}
+
+// DigitalToggle inverts the level of the output pins set in the bitmask pins.
+func (d *Dev) DigitalToggle(pins uint32) error {
+	return d.write32(ModuleGPIO, gpioToggle, pins)
+}
+
+// DigitalRead returns the levels of the pins as a bitmask.
+func (d *Dev) DigitalRead() (uint32, error) {
+	var b [4]byte
+	if err := d.read(ModuleGPIO, gpioBulk, b[:], readDelay); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint32(b[:]), nil
+}
+
+// SetGPIOInterrupts enables or disables the interrupt on change of the pins
+// set in the bitmask pins. The INT pin of the board is asserted until
+// GPIOInterruptFlags is called.
+func (d *Dev) SetGPIOInterrupts(pins uint32, enable bool) error {
+	if enable {
+		return d.write32(ModuleGPIO, gpioIntEnSet, pins)
+	}
+	return d.write32(ModuleGPIO, gpioIntEnClr, pins)
+}
+
+// GPIOInterruptFlags returns the pins that changed since the last call as a
+// bitmask, and clears the interrupt.
+func (d *Dev) GPIOInterruptFlags() (uint32, error) {
+	var b [4]byte
+	if err := d.read(ModuleGPIO, gpioIntFlag, b[:], readDelay); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint32(b[:]), nil
+}
+
+// ReadADC returns the 10 bits analog reading of pin.
+//
+// On the SAMD09 only the pins 2 to 5 are analog inputs.
+func (d *Dev) ReadADC(pin uint8) (uint16, error) {
+	ch := pin
+	if d.chip == SAMD09 {
+		if pin < 2 || pin > 5 {
+			return 0, fmt.Errorf("seesaw: pin %d is not an analog input", pin)
+		}
+		ch = pin - 2
+	}
+	var b [2]byte
+	if err := d.read(ModuleADC, adcChannelOffset+ch, b[:], adcDelay); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint16(b[:]), nil
+}
+
+// SetPWM sets the duty cycle of pin.
+//
+// On the SAMD09 only the pins 4 to 7 support PWM.
+func (d *Dev) SetPWM(pin uint8, duty gpio.Duty) error {
+	if duty < 0 || duty > gpio.DutyMax {
+		return fmt.Errorf("seesaw: invalid duty %s", duty)
+	}
+	p, err := d.pwmPin(pin)
+	if err != nil {
+		return err
+	}
+	v := uint16(int64(duty) * 0xFFFF / int64(gpio.DutyMax))
+	return d.write(ModuleTimer, timerPWM, p, byte(v>>8), byte(v))
+}
+
+// SetPWMFrequency sets the PWM frequency of pin, up to 65535Hz. On the
+// SAMD09 all the PWM pins share the same timer.
+func (d *Dev) SetPWMFrequency(pin uint8, f physic.Frequency) error {
+	if f < physic.Hertz || f > 65535*physic.Hertz {
+		return errors.New("seesaw: PWM frequency must be between 1Hz and 65535Hz")
+	}
+	p, err := d.pwmPin(pin)
+	if err != nil {
+		return err
+	}
+	v := uint16(f / physic.Hertz)
+	return d.write(ModuleTimer, timerFreq, p, byte(v>>8), byte(v))
+}
+
+//
+
+// GPIO module registers.
+const (
+	gpioDirSet    = 0x02
+	gpioDirClr    = 0x03
+	gpioBulk      = 0x04
+	gpioSet       = 0x05
+	gpioClr       = 0x06
+	gpioToggle    = 0x07
+	gpioIntEnSet  = 0x08
+	gpioIntEnClr  = 0x09
+	gpioIntFlag   = 0x0A
+	gpioPullEnSet = 0x0B
+	gpioPullEnClr = 0x0C
+)
+
+// ADC module registers.
+const (
+	adcChannelOffset = 0x07
+)
+
+// Timer module registers.
+const (
+	timerPWM  = 0x01
+	timerFreq = 0x02
+)
+
+// adcDelay is the time needed for an analog reading.
+const adcDelay = 500 * time.Microsecond
+
+// pwmPin returns the PWM output number of pin.
+func (d *Dev) pwmPin(pin uint8) (uint8, error) {
+	if d.chip != SAMD09 {
+		return pin, nil
+	}
+	if pin < 4 || pin > 7 {
+		return 0, fmt.Errorf("seesaw: pin %d doesn't support PWM", pin)
+	}
+	return pin - 4, nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package seesaw
+
+import (
+	"errors"
+	"fmt"
+)
+
+// SetupNeoPixels configures the NeoPixel module to drive n LEDs of bpp bytes
+// per pixel, 3 for RGB and 4 for RGBW, connected to pin at 800kHz.
+func (d *Dev) SetupNeoPixels(pin uint8, n, bpp int) error {
+	if bpp != 3 && bpp != 4 {
+		return errors.New("seesaw: bytes per pixel must be 3 or 4")
+	}
+	l := n * bpp
+	if n <= 0 || l > neoPixelMaxBuf {
+		return fmt.Errorf("seesaw: the NeoPixel buffer is limited to %d bytes", neoPixelMaxBuf)
+	}
+	if err := d.write(ModuleNeoPixel, neoPixelSpeed, 1); err != nil {
+		return err
+	}
+	if err := d.write(ModuleNeoPixel, neoPixelBufLength, byte(l>>8), byte(l)); err != nil {
+		return err
+	}
+	return d.write(ModuleNeoPixel, neoPixelPin, pin)
+}
+
+// WriteNeoPixels writes the raw pixels, in the order expected by the LEDs
+// (usually GRB or GRBW), at the start of the buffer and shows them.
+func (d *Dev) WriteNeoPixels(pixels []byte) error {
+	if len(pixels) > neoPixelMaxBuf {
+		return fmt.Errorf("seesaw: the NeoPixel buffer is limited to %d bytes", neoPixelMaxBuf)
+	}
+	// The firmware receive buffer is 32 bytes, including the register and
+	// the offset.
+	for off := 0; off < len(pixels); off += neoPixelChunk {
+		end := off + neoPixelChunk
+		if end > len(pixels) {
+			end = len(pixels)
+		}
+		w := append([]byte{byte(off >> 8), byte(off)}, pixels[off:end]...)
+		if err := d.write(ModuleNeoPixel, neoPixelBuf, w...); err != nil {
+			return err
+		}
+	}
+	return d.write(ModuleNeoPixel, neoPixelShow)
+}
+
+//
+
+// NeoPixel module registers.
+const (
+	neoPixelPin       = 0x01
+	neoPixelSpeed     = 0x02
+	neoPixelBufLength = 0x03
+	neoPixelBuf       = 0x04
+	neoPixelShow      = 0x05
+)
+
+const (
+	// neoPixelMaxBuf is the size of the pixels buffer of the firmware.
+	neoPixelMaxBuf = 63 * 3
+	// neoPixelChunk is the number of pixel bytes written at once, a
+	// multiple of 3 and 4.
+	neoPixelChunk = 24
+)
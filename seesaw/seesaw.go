@@ -0,0 +1,234 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package seesaw
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/physic"
+)
+
+// DefaultAddress is the default I²C address of the SAMD09 breakout. Many
+// boards use another address, e.g. 0x36 for the rotary encoder and the soil
+// sensor.
+const DefaultAddress = 0x49
+
+// Module is a seesaw firmware module.
+type Module uint8
+
+// Modules.
+const (
+	ModuleStatus    Module = 0x00
+	ModuleGPIO      Module = 0x01
+	ModuleSERCOM0   Module = 0x02
+	ModuleTimer     Module = 0x08
+	ModuleADC       Module = 0x09
+	ModuleDAC       Module = 0x0A
+	ModuleInterrupt Module = 0x0B
+	ModuleDAP       Module = 0x0C
+	ModuleEEPROM    Module = 0x0D
+	ModuleNeoPixel  Module = 0x0E
+	ModuleTouch     Module = 0x0F
+	ModuleKeypad    Module = 0x10
+	ModuleEncoder   Module = 0x11
+)
+
+// Chip is the microcontroller running the firmware, as reported by its
+// hardware ID.
+type Chip uint8
+
+// Known chips.
+const (
+	SAMD09     Chip = 0x55
+	ATtiny806  Chip = 0x84
+	ATtiny807  Chip = 0x85
+	ATtiny816  Chip = 0x86
+	ATtiny817  Chip = 0x87
+	ATtiny1616 Chip = 0x88
+	ATtiny1617 Chip = 0x89
+)
+
+func (c Chip) String() string {
+	switch c {
+	case SAMD09:
+		return "SAMD09"
+	case ATtiny806:
+		return "ATtiny806"
+	case ATtiny807:
+		return "ATtiny807"
+	case ATtiny816:
+		return "ATtiny816"
+	case ATtiny817:
+		return "ATtiny817"
+	case ATtiny1616:
+		return "ATtiny1616"
+	case ATtiny1617:
+		return "ATtiny1617"
+	default:
+		return fmt.Sprintf("Chip(0x%02X)", uint8(c))
+	}
+}
+
+// NewI2C returns a handle to a seesaw device at address addr.
+//
+// The device is soft reset and its hardware ID is checked.
+func NewI2C(b i2c.Bus, addr uint16) (*Dev, error) {
+	d := &Dev{c: i2c.Dev{Bus: b, Addr: addr}}
+	if err := d.Reset(); err != nil {
+		return nil, err
+	}
+	var id [1]byte
+	var err error
+	// The device doesn't answer while it boots.
+	for i := 0; i < resetRetries; i++ {
+		if err = d.read(ModuleStatus, statusHWID, id[:], readDelay); err == nil {
+			break
+		}
+		doSleep(resetTime)
+	}
+	if err != nil {
+		return nil, err
+	}
+	d.chip = Chip(id[0])
+	switch d.chip {
+	case SAMD09, ATtiny806, ATtiny807, ATtiny816, ATtiny817, ATtiny1616, ATtiny1617:
+	default:
+		return nil, fmt.Errorf("seesaw: unknown hardware ID 0x%02X", id[0])
+	}
+	return d, nil
+}
+
+// Dev is a handle to a seesaw device.
+type Dev struct {
+	c    i2c.Dev
+	chip Chip
+
+	mu sync.Mutex
+}
+
+func (d *Dev) String() string {
+	return fmt.Sprintf("seesaw{%s, %s}", &d.c, d.chip)
+}
+
+// Chip returns the microcontroller running the firmware.
+func (d *Dev) Chip() Chip {
+	return d.chip
+}
+
+// Version returns the product code of the board, e.g. 4991 for the rotary
+// encoder, and the date code of the firmware.
+func (d *Dev) Version() (product uint16, date uint16, err error) {
+	var b [4]byte
+	if err := d.read(ModuleStatus, statusVersion, b[:], readDelay); err != nil {
+		return 0, 0, err
+	}
+	return binary.BigEndian.Uint16(b[0:]), binary.BigEndian.Uint16(b[2:]), nil
+}
+
+// Modules returns the modules built into the firmware.
+func (d *Dev) Modules() ([]Module, error) {
+	var b [4]byte
+	if err := d.read(ModuleStatus, statusOptions, b[:], readDelay); err != nil {
+		return nil, err
+	}
+	options := binary.BigEndian.Uint32(b[:])
+	var m []Module
+	for i := 0; i < 32; i++ {
+		if options&(1<<i) != 0 {
+			m = append(m, Module(i))
+		}
+	}
+	return m, nil
+}
+
+// Temperature returns the temperature of the microcontroller. Only the
+// SAMD09 has a temperature sensor.
+func (d *Dev) Temperature() (physic.Temperature, error) {
+	var b [4]byte
+	if err := d.read(ModuleStatus, statusTemp, b[:], tempDelay); err != nil {
+		return 0, err
+	}
+	// 16.16 fixed point °C.
+	v := int64(binary.BigEndian.Uint32(b[:]) & 0x3FFFFFFF)
+	return physic.Temperature(v*int64(physic.Celsius)>>16) + physic.ZeroCelsius, nil
+}
+
+// Reset soft resets the device.
+func (d *Dev) Reset() error {
+	if err := d.write(ModuleStatus, statusSWRST, 0xFF); err != nil {
+		return err
+	}
+	doSleep(resetTime)
+	return nil
+}
+
+// Halt implements conn.Resource.
+//
+// It does nothing, the pins keep their state.
+func (d *Dev) Halt() error {
+	return nil
+}
+
+//
+
+// Status module registers.
+const (
+	statusHWID    = 0x01
+	statusVersion = 0x02
+	statusOptions = 0x03
+	statusTemp    = 0x04
+	statusSWRST   = 0x7F
+)
+
+const (
+	// readDelay is the time the firmware needs to prepare the data of a read.
+	readDelay = 250 * time.Microsecond
+	// tempDelay is the time needed for a temperature reading.
+	tempDelay = time.Millisecond
+	// resetTime is the time the device needs to boot after a reset.
+	resetTime    = 10 * time.Millisecond
+	resetRetries = 10
+)
+
+var doSleep = time.Sleep
+
+// read reads the register fn of module m into b, waiting delay between the
+// write of the register address and the read.
+func (d *Dev) read(m Module, fn uint8, b []byte, delay time.Duration) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.c.Tx([]byte{byte(m), fn}, nil); err != nil {
+		return fmt.Errorf("seesaw: %w", err)
+	}
+	doSleep(delay)
+	if err := d.c.Tx(nil, b); err != nil {
+		return fmt.Errorf("seesaw: %w", err)
+	}
+	return nil
+}
+
+// write writes data to the register fn of module m.
+func (d *Dev) write(m Module, fn uint8, data ...byte) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.c.Tx(append([]byte{byte(m), fn}, data...), nil); err != nil {
+		return fmt.Errorf("seesaw: %w", err)
+	}
+	return nil
+}
+
+// write32 writes the big endian v to the register fn of module m.
+func (d *Dev) write32(m Module, fn uint8, v uint32) error {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], v)
+	return d.write(m, fn, b[:]...)
+}
+
+var _ conn.Resource = &Dev{}
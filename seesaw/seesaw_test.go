@@ -0,0 +1,287 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package seesaw
+
+import (
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/i2c/i2ctest"
+	"periph.io/x/conn/v3/physic"
+)
+
+func TestNewI2C(t *testing.T) {
+	bus := &i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x36, W: []byte{0x00, 0x7F, 0xFF}},
+			{Addr: 0x36, W: []byte{0x00, 0x01}},
+			{Addr: 0x36, R: []byte{0x42}},
+		},
+		DontPanic: true,
+	}
+	if _, err := NewI2C(bus, 0x36); err == nil {
+		t.Fatal("expected unknown hardware ID")
+	}
+}
+
+func TestDev_status(t *testing.T) {
+	bus := &i2ctest.Playback{
+		Ops: append(initOps(SAMD09),
+			i2ctest.IO{Addr: 0x49, W: []byte{0x00, 0x02}},
+			i2ctest.IO{Addr: 0x49, R: []byte{0x13, 0x7F, 0x5A, 0x2B}},
+			i2ctest.IO{Addr: 0x49, W: []byte{0x00, 0x03}},
+			i2ctest.IO{Addr: 0x49, R: []byte{0x00, 0x02, 0x43, 0x03}},
+			i2ctest.IO{Addr: 0x49, W: []byte{0x00, 0x04}},
+			i2ctest.IO{Addr: 0x49, R: []byte{0x00, 0x19, 0x80, 0x00}},
+		),
+	}
+	d := newDev(t, bus)
+	if s := d.String(); s != "seesaw{playback(73), SAMD09}" {
+		t.Fatal(s)
+	}
+	if c := d.Chip(); c != SAMD09 {
+		t.Fatal(c)
+	}
+	product, date, err := d.Version()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if product != 4991 || date != 0x5A2B {
+		t.Fatal(product, date)
+	}
+	m, err := d.Modules()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Module{ModuleStatus, ModuleGPIO, ModuleTimer, ModuleADC, ModuleNeoPixel, ModuleEncoder}
+	if len(m) != len(want) {
+		t.Fatal(m)
+	}
+	for i := range want {
+		if m[i] != want[i] {
+			t.Fatal(m)
+		}
+	}
+	temp, err := d.Temperature()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 25500*physic.MilliCelsius + physic.ZeroCelsius; temp != want {
+		t.Fatalf("%s != %s", temp, want)
+	}
+	if err := d.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDev_gpio(t *testing.T) {
+	bus := &i2ctest.Playback{
+		Ops: append(initOps(ATtiny817),
+			// Output.
+			i2ctest.IO{Addr: 0x49, W: []byte{0x01, 0x02, 0x00, 0x00, 0x00, 0x03}},
+			// Input pull-up.
+			i2ctest.IO{Addr: 0x49, W: []byte{0x01, 0x03, 0x00, 0x01, 0x00, 0x00}},
+			i2ctest.IO{Addr: 0x49, W: []byte{0x01, 0x0B, 0x00, 0x01, 0x00, 0x00}},
+			i2ctest.IO{Addr: 0x49, W: []byte{0x01, 0x05, 0x00, 0x01, 0x00, 0x00}},
+			// Levels.
+			i2ctest.IO{Addr: 0x49, W: []byte{0x01, 0x05, 0x00, 0x00, 0x00, 0x01}},
+			i2ctest.IO{Addr: 0x49, W: []byte{0x01, 0x06, 0x00, 0x00, 0x00, 0x02}},
+			i2ctest.IO{Addr: 0x49, W: []byte{0x01, 0x07, 0x00, 0x00, 0x00, 0x03}},
+			i2ctest.IO{Addr: 0x49, W: []byte{0x01, 0x04}},
+			i2ctest.IO{Addr: 0x49, R: []byte{0x00, 0x01, 0x00, 0x02}},
+			// Interrupts.
+			i2ctest.IO{Addr: 0x49, W: []byte{0x01, 0x08, 0x00, 0x01, 0x00, 0x00}},
+			i2ctest.IO{Addr: 0x49, W: []byte{0x01, 0x0A}},
+			i2ctest.IO{Addr: 0x49, R: []byte{0x00, 0x01, 0x00, 0x00}},
+		),
+	}
+	d := newDev(t, bus)
+	if err := d.SetPinMode(1<<0|1<<1, Output); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetPinMode(1<<16, InputPullUp); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetPinMode(1, PinMode(10)); err == nil {
+		t.Fatal("invalid mode")
+	}
+	if err := d.DigitalWrite(1<<0, gpio.High); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.DigitalWrite(1<<1, gpio.Low); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.DigitalToggle(1<<0 | 1<<1); err != nil {
+		t.Fatal(err)
+	}
+	v, err := d.DigitalRead()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != 1<<16|1<<1 {
+		t.Fatalf("0x%X", v)
+	}
+	if err := d.SetGPIOInterrupts(1<<16, true); err != nil {
+		t.Fatal(err)
+	}
+	if v, err = d.GPIOInterruptFlags(); err != nil || v != 1<<16 {
+		t.Fatal(v, err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDev_analog(t *testing.T) {
+	bus := &i2ctest.Playback{
+		Ops: append(initOps(SAMD09),
+			i2ctest.IO{Addr: 0x49, W: []byte{0x09, 0x08}},
+			i2ctest.IO{Addr: 0x49, R: []byte{0x02, 0x00}},
+			i2ctest.IO{Addr: 0x49, W: []byte{0x08, 0x01, 0x01, 0x7F, 0xFF}},
+			i2ctest.IO{Addr: 0x49, W: []byte{0x08, 0x02, 0x00, 0x03, 0xE8}},
+		),
+	}
+	d := newDev(t, bus)
+	if _, err := d.ReadADC(6); err == nil {
+		t.Fatal("not an analog input")
+	}
+	v, err := d.ReadADC(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != 512 {
+		t.Fatal(v)
+	}
+	if err := d.SetPWM(3, gpio.DutyHalf); err == nil {
+		t.Fatal("not a PWM pin")
+	}
+	if err := d.SetPWM(5, -1); err == nil {
+		t.Fatal("invalid duty")
+	}
+	if err := d.SetPWM(5, gpio.DutyHalf); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetPWMFrequency(4, 0); err == nil {
+		t.Fatal("invalid frequency")
+	}
+	if err := d.SetPWMFrequency(4, physic.KiloHertz); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDev_neoPixels(t *testing.T) {
+	pixels := make([]byte, 30)
+	for i := range pixels {
+		pixels[i] = byte(i)
+	}
+	bus := &i2ctest.Playback{
+		Ops: append(initOps(ATtiny817),
+			i2ctest.IO{Addr: 0x49, W: []byte{0x0E, 0x02, 0x01}},
+			i2ctest.IO{Addr: 0x49, W: []byte{0x0E, 0x03, 0x00, 0x1E}},
+			i2ctest.IO{Addr: 0x49, W: []byte{0x0E, 0x01, 0x0F}},
+			i2ctest.IO{Addr: 0x49, W: append([]byte{0x0E, 0x04, 0x00, 0x00}, pixels[:24]...)},
+			i2ctest.IO{Addr: 0x49, W: append([]byte{0x0E, 0x04, 0x00, 0x18}, pixels[24:]...)},
+			i2ctest.IO{Addr: 0x49, W: []byte{0x0E, 0x05}},
+		),
+	}
+	d := newDev(t, bus)
+	if err := d.SetupNeoPixels(15, 10, 2); err == nil {
+		t.Fatal("invalid bytes per pixel")
+	}
+	if err := d.SetupNeoPixels(15, 100, 3); err == nil {
+		t.Fatal("too many pixels")
+	}
+	if err := d.SetupNeoPixels(15, 10, 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.WriteNeoPixels(pixels); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDev_encoder(t *testing.T) {
+	bus := &i2ctest.Playback{
+		Ops: append(initOps(ATtiny817),
+			i2ctest.IO{Addr: 0x49, W: []byte{0x11, 0x30}},
+			i2ctest.IO{Addr: 0x49, R: []byte{0xFF, 0xFF, 0xFF, 0xFD}},
+			i2ctest.IO{Addr: 0x49, W: []byte{0x11, 0x31, 0x00, 0x00, 0x00, 0x64}},
+			i2ctest.IO{Addr: 0x49, W: []byte{0x11, 0x40}},
+			i2ctest.IO{Addr: 0x49, R: []byte{0x00, 0x00, 0x00, 0x02}},
+			i2ctest.IO{Addr: 0x49, W: []byte{0x11, 0x10, 0x01}},
+			i2ctest.IO{Addr: 0x49, W: []byte{0x11, 0x21, 0x01}},
+		),
+	}
+	d := newDev(t, bus)
+	pos, err := d.EncoderPosition(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pos != -3 {
+		t.Fatal(pos)
+	}
+	if err := d.SetEncoderPosition(1, 100); err != nil {
+		t.Fatal(err)
+	}
+	delta, err := d.EncoderDelta(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if delta != 2 {
+		t.Fatal(delta)
+	}
+	if err := d.SetEncoderInterrupt(0, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetEncoderInterrupt(1, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestChip_String(t *testing.T) {
+	if s := ATtiny1616.String(); s != "ATtiny1616" {
+		t.Fatal(s)
+	}
+	if s := Chip(0x12).String(); s != "Chip(0x12)" {
+		t.Fatal(s)
+	}
+	if s := InputPullDown.String(); s != "InputPullDown" {
+		t.Fatal(s)
+	}
+}
+
+//
+
+func init() {
+	doSleep = func(time.Duration) {}
+}
+
+func initOps(c Chip) []i2ctest.IO {
+	return []i2ctest.IO{
+		{Addr: 0x49, W: []byte{0x00, 0x7F, 0xFF}},
+		{Addr: 0x49, W: []byte{0x00, 0x01}},
+		{Addr: 0x49, R: []byte{byte(c)}},
+	}
+}
+
+func newDev(t *testing.T, bus *i2ctest.Playback) *Dev {
+	d, err := NewI2C(bus, DefaultAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
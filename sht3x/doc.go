@@ -0,0 +1,18 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package sht3x controls a Sensirion SHT30, SHT31 or SHT35 temperature and
+// humidity sensor over I²C.
+//
+// Each Sense triggers a single shot measurement. The result is either read
+// with clock stretching, where the sensor holds SCL low until the measurement
+// is done, or by waiting for the maximum measurement duration when the I²C
+// controller doesn't support clock stretching.
+//
+// All the data read is verified with its CRC.
+//
+// # Datasheet
+//
+// https://sensirion.com/media/documents/213E6A3B/63A5A569/Datasheet_SHT3x_DIS.pdf
+package sht3x
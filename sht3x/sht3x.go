@@ -0,0 +1,358 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sht3x
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/physic"
+)
+
+// Repeatability is the trade-off between the noise and the duration of a
+// measurement.
+type Repeatability uint8
+
+// Possible repeatabilities.
+const (
+	High Repeatability = iota
+	Medium
+	Low
+)
+
+func (r Repeatability) String() string {
+	switch r {
+	case High:
+		return "High"
+	case Medium:
+		return "Medium"
+	case Low:
+		return "Low"
+	default:
+		return fmt.Sprintf("Repeatability(%d)", r)
+	}
+}
+
+// Status is the content of the status register.
+type Status uint16
+
+// Status flags.
+const (
+	StatusAlertPending     Status = 1 << 15
+	StatusHeaterOn         Status = 1 << 13
+	StatusHumidityAlert    Status = 1 << 11
+	StatusTemperatureAlert Status = 1 << 10
+	StatusReset            Status = 1 << 4
+	StatusCommandFailed    Status = 1 << 1
+	StatusChecksumFailed   Status = 1 << 0
+)
+
+// Opts holds the configuration options.
+type Opts struct {
+	// Repeatability of the measurements.
+	Repeatability Repeatability
+	// ClockStretching reads the measurements with clock stretching. The I²C
+	// controller must support it. Otherwise the driver waits for the maximum
+	// measurement duration before reading.
+	ClockStretching bool
+}
+
+// DefaultOpts is the recommended default options.
+var DefaultOpts = Opts{
+	Repeatability: High,
+}
+
+// DefaultSensorAddress is the address when ADDR is low. It is 0x45 when ADDR
+// is high.
+const DefaultSensorAddress = 0x44
+
+// NewI2C returns a handle to a SHT3x at address addr.
+//
+// The sensor is soft reset.
+func NewI2C(b i2c.Bus, addr uint16, opts *Opts) (*Dev, error) {
+	switch addr {
+	case DefaultSensorAddress, DefaultSensorAddress + 1:
+	default:
+		return nil, errors.New("sht3x: given address not supported by device")
+	}
+	if opts.Repeatability > Low {
+		return nil, fmt.Errorf("sht3x: invalid repeatability %s", opts.Repeatability)
+	}
+	d := &Dev{c: i2c.Dev{Bus: b, Addr: addr}, opts: *opts}
+	if err := d.c.Tx(cmdSoftReset, nil); err != nil {
+		return nil, fmt.Errorf("sht3x: %v", err)
+	}
+	doSleep(resetTime)
+	return d, nil
+}
+
+// Dev is a handle to a SHT3x.
+type Dev struct {
+	c    i2c.Dev
+	opts Opts
+
+	mu   sync.Mutex
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func (d *Dev) String() string {
+	return fmt.Sprintf("SHT3x{%s}", &d.c)
+}
+
+// Sense requests a one time measurement of the temperature and the
+// humidity.
+func (d *Dev) Sense(e *physic.Env) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.stop != nil {
+		return errors.New("sht3x: already sensing continuously")
+	}
+	return d.sense(e)
+}
+
+// SenseContinuous returns measurements on a continuous basis.
+//
+// The sensor is not continuously measuring, a single shot measurement is done
+// at each interval so the sensor sleeps in between.
+//
+// The application must call Halt() to stop the sensing when done and close
+// the channel.
+func (d *Dev) SenseContinuous(interval time.Duration) (<-chan physic.Env, error) {
+	if need := measureTimes[d.opts.Repeatability]; interval < need {
+		return nil, fmt.Errorf("sht3x: interval must be at least %s", need)
+	}
+	if err := d.Halt(); err != nil {
+		return nil, err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	sensing := make(chan physic.Env)
+	stop := make(chan struct{})
+	d.stop = stop
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		defer close(sensing)
+		d.sensingContinuous(interval, sensing, stop)
+	}()
+	return sensing, nil
+}
+
+// Precision implements physic.SenseEnv.
+//
+// The results are 16 bits, about 2.7m°C and 1.5m%rH.
+func (d *Dev) Precision(e *physic.Env) {
+	e.Temperature = 175 * physic.Kelvin / 65535
+	e.Pressure = 0
+	e.Humidity = 100 * physic.PercentRH / 65535
+}
+
+// SetHeater turns the internal heater on or off. The heater can be used to
+// check the sensor plausibility or to evaporate condensation. It raises the
+// temperature by a few degrees.
+func (d *Dev) SetHeater(on bool) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	cmd := cmdHeaterDisable
+	if on {
+		cmd = cmdHeaterEnable
+	}
+	if err := d.c.Tx(cmd, nil); err != nil {
+		return fmt.Errorf("sht3x: %v", err)
+	}
+	return nil
+}
+
+// Status returns the status register.
+func (d *Dev) Status() (Status, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var b [3]byte
+	if err := d.c.Tx(cmdReadStatus, b[:]); err != nil {
+		return 0, fmt.Errorf("sht3x: %v", err)
+	}
+	w, err := words(b[:])
+	if err != nil {
+		return 0, err
+	}
+	return Status(w[0]), nil
+}
+
+// ClearStatus clears the alert and reset flags of the status register.
+func (d *Dev) ClearStatus() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.c.Tx(cmdClearStatus, nil); err != nil {
+		return fmt.Errorf("sht3x: %v", err)
+	}
+	return nil
+}
+
+// SerialNumber returns the unique 32 bits serial number of the sensor.
+func (d *Dev) SerialNumber() (uint32, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.c.Tx(cmdReadSerial, nil); err != nil {
+		return 0, fmt.Errorf("sht3x: %v", err)
+	}
+	doSleep(serialTime)
+	var b [6]byte
+	if err := d.c.Tx(nil, b[:]); err != nil {
+		return 0, fmt.Errorf("sht3x: %v", err)
+	}
+	w, err := words(b[:])
+	if err != nil {
+		return 0, err
+	}
+	return uint32(w[0])<<16 | uint32(w[1]), nil
+}
+
+// Halt stops continuous sensing. The sensor sleeps between measurements.
+//
+// Halt implements conn.Resource.
+func (d *Dev) Halt() error {
+	d.mu.Lock()
+	if d.stop != nil {
+		close(d.stop)
+		d.stop = nil
+	}
+	d.mu.Unlock()
+	d.wg.Wait()
+	return nil
+}
+
+//
+
+// Commands.
+var (
+	cmdSoftReset     = []byte{0x30, 0xA2}
+	cmdHeaterEnable  = []byte{0x30, 0x6D}
+	cmdHeaterDisable = []byte{0x30, 0x66}
+	cmdReadStatus    = []byte{0xF3, 0x2D}
+	cmdClearStatus   = []byte{0x30, 0x41}
+	cmdReadSerial    = []byte{0x37, 0x80}
+
+	// Single shot measurements for each repeatability, with clock stretching.
+	cmdMeasureStretch = [...][]byte{{0x2C, 0x06}, {0x2C, 0x0D}, {0x2C, 0x10}}
+	// Single shot measurements for each repeatability, without clock
+	// stretching.
+	cmdMeasurePoll = [...][]byte{{0x24, 0x00}, {0x24, 0x0B}, {0x24, 0x16}}
+)
+
+// measureTimes is the maximum measurement duration for each repeatability.
+var measureTimes = [...]time.Duration{
+	15500 * time.Microsecond,
+	6500 * time.Microsecond,
+	4500 * time.Microsecond,
+}
+
+const (
+	resetTime  = 1500 * time.Microsecond
+	serialTime = time.Millisecond
+)
+
+var errInvalidCRC = errors.New("sht3x: invalid crc")
+
+func (d *Dev) sense(e *physic.Env) error {
+	var b [6]byte
+	if d.opts.ClockStretching {
+		if err := d.c.Tx(cmdMeasureStretch[d.opts.Repeatability], nil); err != nil {
+			return fmt.Errorf("sht3x: %v", err)
+		}
+	} else {
+		if err := d.c.Tx(cmdMeasurePoll[d.opts.Repeatability], nil); err != nil {
+			return fmt.Errorf("sht3x: %v", err)
+		}
+		doSleep(measureTimes[d.opts.Repeatability])
+	}
+	if err := d.c.Tx(nil, b[:]); err != nil {
+		return fmt.Errorf("sht3x: %v", err)
+	}
+	w, err := words(b[:])
+	if err != nil {
+		return err
+	}
+	e.Temperature = countToTemperature(w[0])
+	e.Pressure = 0
+	e.Humidity = countToHumidity(w[1])
+	return nil
+}
+
+// words verifies the CRC of each 16 bits word read and returns them.
+func words(b []byte) ([]uint16, error) {
+	w := make([]uint16, 0, len(b)/3)
+	for i := 0; i+3 <= len(b); i += 3 {
+		if crc8(b[i:i+2]) != b[i+2] {
+			return nil, errInvalidCRC
+		}
+		w = append(w, uint16(b[i])<<8|uint16(b[i+1]))
+	}
+	return w, nil
+}
+
+// countToTemperature converts a raw count to a temperature, -45°C + 175°C *
+// count / 65535.
+func countToTemperature(count uint16) physic.Temperature {
+	return physic.ZeroCelsius - 45*physic.Kelvin + physic.Temperature(int64(count)*int64(175*physic.Kelvin)/65535)
+}
+
+// countToHumidity converts a raw count to a relative humidity, 100% * count /
+// 65535.
+func countToHumidity(count uint16) physic.RelativeHumidity {
+	return physic.RelativeHumidity(int64(count) * int64(100*physic.PercentRH) / 65535)
+}
+
+// crc8 is the CRC-8 of the data with the polynomial 0x31 and initialization
+// 0xFF.
+func crc8(b []byte) byte {
+	crc := byte(0xFF)
+	for _, v := range b {
+		crc ^= v
+		for i := 0; i < 8; i++ {
+			if crc&0x80 != 0 {
+				crc = crc<<1 ^ 0x31
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+func (d *Dev) sensingContinuous(interval time.Duration, sensing chan<- physic.Env, stop <-chan struct{}) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		e := physic.Env{}
+		d.mu.Lock()
+		err := d.sense(&e)
+		d.mu.Unlock()
+		if err != nil {
+			log.Printf("%s: failed to sense: %v", d, err)
+			return
+		}
+		select {
+		case sensing <- e:
+		case <-stop:
+			return
+		}
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+		}
+	}
+}
+
+var doSleep = time.Sleep
+
+var _ conn.Resource = &Dev{}
+var _ physic.SenseEnv = &Dev{}
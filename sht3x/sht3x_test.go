@@ -0,0 +1,220 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sht3x
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3/i2c/i2ctest"
+	"periph.io/x/conn/v3/physic"
+)
+
+func TestNewI2C_fail(t *testing.T) {
+	bus := &i2ctest.Playback{DontPanic: true}
+	if _, err := NewI2C(bus, 0x40, &DefaultOpts); err == nil {
+		t.Fatal("invalid address")
+	}
+	if _, err := NewI2C(bus, DefaultSensorAddress, &Opts{Repeatability: 3}); err == nil {
+		t.Fatal("invalid repeatability")
+	}
+	if _, err := NewI2C(bus, DefaultSensorAddress, &DefaultOpts); err == nil {
+		t.Fatal("io error")
+	}
+}
+
+func TestSense(t *testing.T) {
+	data := []struct {
+		opts  Opts
+		cmd   []byte
+		sleep time.Duration
+	}{
+		{Opts{Repeatability: High}, []byte{0x24, 0x00}, 15500 * time.Microsecond},
+		{Opts{Repeatability: Medium}, []byte{0x24, 0x0B}, 6500 * time.Microsecond},
+		{Opts{Repeatability: Low}, []byte{0x24, 0x16}, 4500 * time.Microsecond},
+		{Opts{Repeatability: High, ClockStretching: true}, []byte{0x2C, 0x06}, 0},
+		{Opts{Repeatability: Low, ClockStretching: true}, []byte{0x2C, 0x10}, 0},
+	}
+	for _, line := range data {
+		t.Run(line.opts.Repeatability.String(), func(t *testing.T) {
+			var slept time.Duration
+			defer setSleep(&slept)()
+			bus := &i2ctest.Playback{
+				Ops: []i2ctest.IO{
+					{Addr: DefaultSensorAddress, W: []byte{0x30, 0xA2}},
+					{Addr: DefaultSensorAddress, W: line.cmd},
+					// 25°C, 50%RH.
+					{Addr: DefaultSensorAddress, R: frame(0x6666, 0x8000)},
+				},
+				DontPanic: true,
+			}
+			d, err := NewI2C(bus, DefaultSensorAddress, &line.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			e := physic.Env{}
+			if err := d.Sense(&e); err != nil {
+				t.Fatal(err)
+			}
+			if got := e.Temperature - physic.ZeroCelsius; got < 24990*physic.MilliKelvin || got > 25010*physic.MilliKelvin {
+				t.Fatalf("unexpected temperature %s", e.Temperature)
+			}
+			if e.Humidity < 4999*physic.PercentRH/100 || e.Humidity > 5001*physic.PercentRH/100 {
+				t.Fatalf("unexpected humidity %s", e.Humidity)
+			}
+			if want := line.sleep + resetTime; slept != want {
+				t.Fatalf("slept %s, want %s", slept, want)
+			}
+			if err := bus.Close(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestSense_crc(t *testing.T) {
+	defer setSleep(new(time.Duration))()
+	r := frame(0x6666, 0x8000)
+	r[5] ^= 1
+	bus := &i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: DefaultSensorAddress, W: []byte{0x30, 0xA2}},
+			{Addr: DefaultSensorAddress, W: []byte{0x24, 0x00}},
+			{Addr: DefaultSensorAddress, R: r},
+		},
+		DontPanic: true,
+	}
+	d, err := NewI2C(bus, DefaultSensorAddress, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Sense(&physic.Env{}); !errors.Is(err, errInvalidCRC) {
+		t.Fatalf("expected crc error, got %v", err)
+	}
+}
+
+func TestSenseContinuous(t *testing.T) {
+	defer setSleep(new(time.Duration))()
+	bus := &i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: DefaultSensorAddress, W: []byte{0x30, 0xA2}},
+			{Addr: DefaultSensorAddress, W: []byte{0x24, 0x00}},
+			{Addr: DefaultSensorAddress, R: frame(0x6666, 0x8000)},
+			{Addr: DefaultSensorAddress, W: []byte{0x24, 0x00}},
+			{Addr: DefaultSensorAddress, R: frame(0x6666, 0x4000)},
+		},
+		DontPanic: true,
+	}
+	d, err := NewI2C(bus, DefaultSensorAddress, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.SenseContinuous(time.Millisecond); err == nil {
+		t.Fatal("interval too short")
+	}
+	c, err := d.SenseContinuous(20 * time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := <-c; e.Humidity < 4999*physic.PercentRH/100 {
+		t.Fatalf("unexpected humidity %s", e.Humidity)
+	}
+	if err := d.Sense(&physic.Env{}); err == nil {
+		t.Fatal("Sense while sensing continuously")
+	}
+	if e := <-c; e.Humidity > 2501*physic.PercentRH/100 {
+		t.Fatalf("unexpected humidity %s", e.Humidity)
+	}
+	if err := d.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := <-c; ok {
+		t.Fatal("channel should be closed")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHeater_Status_Serial(t *testing.T) {
+	defer setSleep(new(time.Duration))()
+	bus := &i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: DefaultSensorAddress, W: []byte{0x30, 0xA2}},
+			{Addr: DefaultSensorAddress, W: []byte{0x30, 0x6D}},
+			{Addr: DefaultSensorAddress, W: []byte{0xF3, 0x2D}, R: frame(0x2010)},
+			{Addr: DefaultSensorAddress, W: []byte{0x30, 0x66}},
+			{Addr: DefaultSensorAddress, W: []byte{0x30, 0x41}},
+			{Addr: DefaultSensorAddress, W: []byte{0x37, 0x80}},
+			{Addr: DefaultSensorAddress, R: frame(0x1234, 0x5678)},
+		},
+		DontPanic: true,
+	}
+	d, err := NewI2C(bus, DefaultSensorAddress, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetHeater(true); err != nil {
+		t.Fatal(err)
+	}
+	s, err := d.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != StatusHeaterOn|StatusReset {
+		t.Fatalf("unexpected status %#x", s)
+	}
+	if err := d.SetHeater(false); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.ClearStatus(); err != nil {
+		t.Fatal(err)
+	}
+	sn, err := d.SerialNumber()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sn != 0x12345678 {
+		t.Fatalf("unexpected serial number %#x", sn)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCRC(t *testing.T) {
+	// Example from the datasheet.
+	if got := crc8([]byte{0xBE, 0xEF}); got != 0x92 {
+		t.Fatalf("crc8 = %#x", got)
+	}
+}
+
+func TestConversions(t *testing.T) {
+	if got := countToTemperature(0); got != physic.ZeroCelsius-45*physic.Kelvin {
+		t.Fatalf("unexpected %s", got)
+	}
+	if got := countToTemperature(0xFFFF); got != physic.ZeroCelsius+130*physic.Kelvin {
+		t.Fatalf("unexpected %s", got)
+	}
+	if got := countToHumidity(0xFFFF); got != 100*physic.PercentRH {
+		t.Fatalf("unexpected %s", got)
+	}
+}
+
+// frame returns the words followed by their CRC, as sent by the sensor.
+func frame(w ...uint16) []byte {
+	var b []byte
+	for _, v := range w {
+		d := []byte{byte(v >> 8), byte(v)}
+		b = append(b, d[0], d[1], crc8(d))
+	}
+	return b
+}
+
+func setSleep(slept *time.Duration) func() {
+	doSleep = func(d time.Duration) { *slept += d }
+	return func() { doSleep = time.Sleep }
+}
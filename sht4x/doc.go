@@ -0,0 +1,18 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package sht4x controls a Sensirion SHT40, SHT41 or SHT45 temperature and
+// humidity sensor over I²C.
+//
+// The SHT4x doesn't stretch the clock, each Sense triggers a measurement and
+// waits for its maximum duration before reading it. All the data read is
+// verified with its CRC.
+//
+// The heater is only turned on for a fixed duration, at the end of which a
+// measurement is returned. It must not be used more than 10% of the time.
+//
+// # Datasheet
+//
+// https://sensirion.com/media/documents/33FD6951/662A593A/HT_DS_Datasheet_SHT4x.pdf
+package sht4x
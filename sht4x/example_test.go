@@ -0,0 +1,42 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sht4x_test
+
+import (
+	"fmt"
+	"log"
+
+	"periph.io/x/conn/v3/i2c/i2creg"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/devices/v3/sht4x"
+	"periph.io/x/host/v3"
+)
+
+func Example() {
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+	// Open default I²C bus.
+	bus, err := i2creg.Open("")
+	if err != nil {
+		log.Fatalf("failed to open I²C: %v", err)
+	}
+	defer bus.Close()
+
+	sensor, err := sht4x.NewI2C(bus, sht4x.DefaultSensorAddress, &sht4x.DefaultOpts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	sn, err := sensor.SerialNumber()
+	if err != nil {
+		log.Fatal(err)
+	}
+	env := physic.Env{}
+	if err := sensor.Sense(&env); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%08x: %s\n", sn, env)
+}
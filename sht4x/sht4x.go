@@ -0,0 +1,344 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sht4x
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/physic"
+)
+
+// Precision is the trade-off between the noise and the duration of a
+// measurement.
+type Precision uint8
+
+// Possible precisions.
+const (
+	High Precision = iota
+	Medium
+	Low
+)
+
+func (p Precision) String() string {
+	switch p {
+	case High:
+		return "High"
+	case Medium:
+		return "Medium"
+	case Low:
+		return "Low"
+	default:
+		return fmt.Sprintf("Precision(%d)", p)
+	}
+}
+
+// Heater is a heater power and duration.
+type Heater uint8
+
+// Possible heater settings.
+const (
+	Heater200mW1s Heater = iota
+	Heater200mW100ms
+	Heater110mW1s
+	Heater110mW100ms
+	Heater20mW1s
+	Heater20mW100ms
+)
+
+func (h Heater) String() string {
+	if h > Heater20mW100ms {
+		return fmt.Sprintf("Heater(%d)", h)
+	}
+	return heaterNames[h]
+}
+
+// Opts holds the configuration options.
+type Opts struct {
+	// Precision of the measurements.
+	Precision Precision
+}
+
+// DefaultOpts is the recommended default options.
+var DefaultOpts = Opts{
+	Precision: High,
+}
+
+// DefaultSensorAddress is the address of the SHT40-AD1B. Other variants use
+// 0x45 or 0x46.
+const DefaultSensorAddress = 0x44
+
+// NewI2C returns a handle to a SHT4x at address addr.
+//
+// The sensor is soft reset.
+func NewI2C(b i2c.Bus, addr uint16, opts *Opts) (*Dev, error) {
+	switch addr {
+	case DefaultSensorAddress, DefaultSensorAddress + 1, DefaultSensorAddress + 2:
+	default:
+		return nil, errors.New("sht4x: given address not supported by device")
+	}
+	if opts.Precision > Low {
+		return nil, fmt.Errorf("sht4x: invalid precision %s", opts.Precision)
+	}
+	d := &Dev{c: i2c.Dev{Bus: b, Addr: addr}, opts: *opts}
+	if err := d.c.Tx([]byte{cmdSoftReset}, nil); err != nil {
+		return nil, fmt.Errorf("sht4x: %v", err)
+	}
+	doSleep(resetTime)
+	return d, nil
+}
+
+// Dev is a handle to a SHT4x.
+type Dev struct {
+	c    i2c.Dev
+	opts Opts
+
+	mu   sync.Mutex
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func (d *Dev) String() string {
+	return fmt.Sprintf("SHT4x{%s}", &d.c)
+}
+
+// Sense requests a one time measurement of the temperature and the
+// humidity.
+func (d *Dev) Sense(e *physic.Env) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.stop != nil {
+		return errors.New("sht4x: already sensing continuously")
+	}
+	return d.measure(measureCmds[d.opts.Precision], measureTimes[d.opts.Precision], e)
+}
+
+// SenseContinuous returns measurements on a continuous basis.
+//
+// A single measurement is done at each interval so the sensor sleeps in
+// between.
+//
+// The application must call Halt() to stop the sensing when done and close
+// the channel.
+func (d *Dev) SenseContinuous(interval time.Duration) (<-chan physic.Env, error) {
+	if need := measureTimes[d.opts.Precision]; interval < need {
+		return nil, fmt.Errorf("sht4x: interval must be at least %s", need)
+	}
+	if err := d.Halt(); err != nil {
+		return nil, err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	sensing := make(chan physic.Env)
+	stop := make(chan struct{})
+	d.stop = stop
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		defer close(sensing)
+		d.sensingContinuous(interval, sensing, stop)
+	}()
+	return sensing, nil
+}
+
+// Precision implements physic.SenseEnv.
+//
+// The results are 16 bits, about 2.7m°C and 1.9m%rH.
+func (d *Dev) Precision(e *physic.Env) {
+	e.Temperature = 175 * physic.Kelvin / 65535
+	e.Pressure = 0
+	e.Humidity = 125 * physic.PercentRH / 65535
+}
+
+// Heat turns the heater on with the given setting and returns the
+// measurement done at the end of the heating, with high precision.
+//
+// The heater raises the temperature so the measurement is not representative
+// of the environment; it is useful to evaporate condensation or check the
+// sensor plausibility. Heat blocks for the heating duration.
+func (d *Dev) Heat(h Heater, e *physic.Env) error {
+	if h > Heater20mW100ms {
+		return fmt.Errorf("sht4x: invalid heater setting %s", h)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.measure(heaterCmds[h], heaterTimes[h], e)
+}
+
+// SerialNumber returns the unique 32 bits serial number of the sensor.
+func (d *Dev) SerialNumber() (uint32, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	w, err := d.read(cmdReadSerial, serialTime)
+	if err != nil {
+		return 0, err
+	}
+	return uint32(w[0])<<16 | uint32(w[1]), nil
+}
+
+// Halt stops continuous sensing. The sensor sleeps between measurements.
+//
+// Halt implements conn.Resource.
+func (d *Dev) Halt() error {
+	d.mu.Lock()
+	if d.stop != nil {
+		close(d.stop)
+		d.stop = nil
+	}
+	d.mu.Unlock()
+	d.wg.Wait()
+	return nil
+}
+
+//
+
+// Commands.
+const (
+	cmdSoftReset  = 0x94
+	cmdReadSerial = 0x89
+)
+
+// measureCmds is the measurement command for each precision.
+var measureCmds = [...]byte{0xFD, 0xF6, 0xE0}
+
+// measureTimes is the maximum measurement duration for each precision.
+var measureTimes = [...]time.Duration{
+	8300 * time.Microsecond,
+	4500 * time.Microsecond,
+	1600 * time.Microsecond,
+}
+
+// heaterCmds is the command for each heater setting.
+var heaterCmds = [...]byte{0x39, 0x32, 0x2F, 0x24, 0x1E, 0x15}
+
+// heaterTimes is the maximum duration of the heating and the following
+// measurement for each heater setting.
+var heaterTimes = [...]time.Duration{
+	1100 * time.Millisecond,
+	110 * time.Millisecond,
+	1100 * time.Millisecond,
+	110 * time.Millisecond,
+	1100 * time.Millisecond,
+	110 * time.Millisecond,
+}
+
+var heaterNames = [...]string{
+	"200mW 1s",
+	"200mW 100ms",
+	"110mW 1s",
+	"110mW 100ms",
+	"20mW 1s",
+	"20mW 100ms",
+}
+
+const (
+	resetTime  = time.Millisecond
+	serialTime = time.Millisecond
+)
+
+var errInvalidCRC = errors.New("sht4x: invalid crc")
+
+func (d *Dev) measure(cmd byte, wait time.Duration, e *physic.Env) error {
+	w, err := d.read(cmd, wait)
+	if err != nil {
+		return err
+	}
+	e.Temperature = countToTemperature(w[0])
+	e.Pressure = 0
+	e.Humidity = countToHumidity(w[1])
+	return nil
+}
+
+// read sends the command, waits and reads two words with their CRC.
+func (d *Dev) read(cmd byte, wait time.Duration) ([2]uint16, error) {
+	var w [2]uint16
+	if err := d.c.Tx([]byte{cmd}, nil); err != nil {
+		return w, fmt.Errorf("sht4x: %v", err)
+	}
+	doSleep(wait)
+	var b [6]byte
+	if err := d.c.Tx(nil, b[:]); err != nil {
+		return w, fmt.Errorf("sht4x: %v", err)
+	}
+	for i := range w {
+		r := b[3*i : 3*i+3]
+		if crc8(r[:2]) != r[2] {
+			return w, errInvalidCRC
+		}
+		w[i] = uint16(r[0])<<8 | uint16(r[1])
+	}
+	return w, nil
+}
+
+// countToTemperature converts a raw count to a temperature, -45°C + 175°C *
+// count / 65535.
+func countToTemperature(count uint16) physic.Temperature {
+	return physic.ZeroCelsius - 45*physic.Kelvin + physic.Temperature(int64(count)*int64(175*physic.Kelvin)/65535)
+}
+
+// countToHumidity converts a raw count to a relative humidity, -6% + 125% *
+// count / 65535, clipped to 0-100% as recommended by the datasheet.
+func countToHumidity(count uint16) physic.RelativeHumidity {
+	h := -6*physic.PercentRH + physic.RelativeHumidity(int64(count)*int64(125*physic.PercentRH)/65535)
+	if h < 0 {
+		return 0
+	}
+	if h > 100*physic.PercentRH {
+		return 100 * physic.PercentRH
+	}
+	return h
+}
+
+// crc8 is the CRC-8 of the data with the polynomial 0x31 and initialization
+// 0xFF.
+func crc8(b []byte) byte {
+	crc := byte(0xFF)
+	for _, v := range b {
+		crc ^= v
+		for i := 0; i < 8; i++ {
+			if crc&0x80 != 0 {
+				crc = crc<<1 ^ 0x31
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+func (d *Dev) sensingContinuous(interval time.Duration, sensing chan<- physic.Env, stop <-chan struct{}) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		e := physic.Env{}
+		d.mu.Lock()
+		err := d.measure(measureCmds[d.opts.Precision], measureTimes[d.opts.Precision], &e)
+		d.mu.Unlock()
+		if err != nil {
+			log.Printf("%s: failed to sense: %v", d, err)
+			return
+		}
+		select {
+		case sensing <- e:
+		case <-stop:
+			return
+		}
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+		}
+	}
+}
+
+var doSleep = time.Sleep
+
+var _ conn.Resource = &Dev{}
+var _ physic.SenseEnv = &Dev{}
@@ -5,6 +5,10 @@
 // Package ssd1306 controls a 128x64 monochrome OLED display via a SSD1306
 // controller.
 //
+// Smaller panels are supported too, including the odd-geometry 72x40 and
+// 64x48 modules connected to the center columns of the controller; use
+// Opts72x40 and Opts64x48 for these.
+//
 // The driver does differential updates: it only sends modified pixels for the
 // smallest rectangle, to economize bus bandwidth. This is especially important
 // when using I²C as the bus default speed (often 100kHz) is slow enough to
@@ -14,6 +18,11 @@
 // with 4 wires. Changing between protocol is likely done through resistor
 // soldering, for boards that support both.
 //
+// Two displays can share an I²C bus when one has its SA0 pin set to use
+// I2CAddrAlt. NewI2CAll finds them with Scan, the only read done by the
+// driver. Each Dev has its own frame buffer, but a Dev must not be used
+// concurrently.
+//
 // Some boards expose a RES / Reset pin. If present, it must be normally be
 // High. When set to Low (Ground), it enables the reset circuitry. It can be
 // used externally to this driver, if used, the driver must be reinstantiated.
@@ -0,0 +1,169 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package image1bit
+
+import (
+	"image"
+	"image/draw"
+)
+
+// Image is implemented by all the 1 bit images of this package.
+type Image interface {
+	draw.Image
+	// BitAt is the optimized version of At().
+	BitAt(x, y int) Bit
+	// SetBit is the optimized version of Set().
+	SetBit(x, y int, b Bit)
+}
+
+// Draw copies the rectangle r of dst from src starting at sp, like
+// draw.Src.Draw, converting the pixels with BitModel.
+//
+// It is optimized for the cases commonly found in display drivers: filling
+// with an *image.Uniform and copying between images of this package. Copies
+// between two HorizontalMSB or two HorizontalLSB images having the same
+// alignment on 8 pixels are done a byte at a time.
+func Draw(dst Image, r image.Rectangle, src image.Image, sp image.Point) {
+	r, sp = clip(dst, r, src, sp)
+	if r.Empty() {
+		return
+	}
+	switch s := src.(type) {
+	case *image.Uniform:
+		b := convertBit(s.C)
+		if h, ok := asHorizontal(dst); ok {
+			h.fill(dst, r, b)
+			return
+		}
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			for x := r.Min.X; x < r.Max.X; x++ {
+				dst.SetBit(x, y, b)
+			}
+		}
+		return
+	case Image:
+		if sameLayout(dst, s) {
+			d, _ := asHorizontal(dst)
+			sh, _ := asHorizontal(s)
+			if d.copy(dst, r, s, sh, sp) {
+				return
+			}
+		}
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			sy := sp.Y + y - r.Min.Y
+			for x := r.Min.X; x < r.Max.X; x++ {
+				dst.SetBit(x, y, s.BitAt(sp.X+x-r.Min.X, sy))
+			}
+		}
+		return
+	}
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		sy := sp.Y + y - r.Min.Y
+		for x := r.Min.X; x < r.Max.X; x++ {
+			dst.SetBit(x, y, convertBit(src.At(sp.X+x-r.Min.X, sy)))
+		}
+	}
+}
+
+//
+
+// clip clips r against the bounds of dst and src, like image/draw does.
+func clip(dst Image, r image.Rectangle, src image.Image, sp image.Point) (image.Rectangle, image.Point) {
+	orig := r.Min
+	r = r.Intersect(dst.Bounds())
+	r = r.Intersect(src.Bounds().Add(orig.Sub(sp)))
+	return r, sp.Add(r.Min.Sub(orig))
+}
+
+// horizontal is the pixel storage shared by HorizontalMSB and HorizontalLSB.
+// Filling and copying whole bytes doesn't depend on the bit order.
+type horizontal struct {
+	pix    []byte
+	stride int
+	min    image.Point
+}
+
+func asHorizontal(i image.Image) (horizontal, bool) {
+	switch t := i.(type) {
+	case *HorizontalMSB:
+		return horizontal{t.Pix, t.Stride, image.Pt(t.Rect.Min.X&^7, t.Rect.Min.Y)}, true
+	case *HorizontalLSB:
+		return horizontal{t.Pix, t.Stride, image.Pt(t.Rect.Min.X&^7, t.Rect.Min.Y)}, true
+	}
+	return horizontal{}, false
+}
+
+func sameLayout(a, b image.Image) bool {
+	switch a.(type) {
+	case *HorizontalMSB:
+		_, ok := b.(*HorizontalMSB)
+		return ok
+	case *HorizontalLSB:
+		_, ok := b.(*HorizontalLSB)
+		return ok
+	}
+	return false
+}
+
+// span splits [x0, x1) in a head and a tail to be processed a pixel at a time
+// and the full bytes [b0, b1) in between, relative to h.min.X.
+func (h *horizontal) span(x0, x1 int) (head, b0, b1, tail int) {
+	head = (x0+7-h.min.X)&^7 + h.min.X
+	tail = (x1-h.min.X)&^7 + h.min.X
+	if head >= tail {
+		return x1, 0, 0, x1
+	}
+	return head, (head - h.min.X) / 8, (tail - h.min.X) / 8, tail
+}
+
+func (h *horizontal) fill(dst Image, r image.Rectangle, b Bit) {
+	var v byte
+	if b {
+		v = 0xFF
+	}
+	head, b0, b1, tail := h.span(r.Min.X, r.Max.X)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < head; x++ {
+			dst.SetBit(x, y, b)
+		}
+		row := h.pix[(y-h.min.Y)*h.stride:]
+		for i := b0; i < b1; i++ {
+			row[i] = v
+		}
+		for x := tail; x < r.Max.X; x++ {
+			dst.SetBit(x, y, b)
+		}
+	}
+}
+
+// copy copies src to dst a byte at a time. It returns false if the images
+// are not aligned the same way.
+func (h *horizontal) copy(dst Image, r image.Rectangle, src Image, s horizontal, sp image.Point) bool {
+	if (r.Min.X-h.min.X)&7 != (sp.X-s.min.X)&7 {
+		return false
+	}
+	head, b0, b1, tail := h.span(r.Min.X, r.Max.X)
+	// Byte index of the source matching b0.
+	sb0 := (sp.X + head - r.Min.X - s.min.X) / 8
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		sy := sp.Y + y - r.Min.Y
+		for x := r.Min.X; x < head; x++ {
+			dst.SetBit(x, y, src.BitAt(sp.X+x-r.Min.X, sy))
+		}
+		if b1 > b0 {
+			d := h.pix[(y-h.min.Y)*h.stride:]
+			sr := s.pix[(sy-s.min.Y)*s.stride:]
+			copy(d[b0:b1], sr[sb0:sb0+b1-b0])
+		}
+		for x := tail; x < r.Max.X; x++ {
+			dst.SetBit(x, y, src.BitAt(sp.X+x-r.Min.X, sy))
+		}
+	}
+	return true
+}
+
+var _ Image = &VerticalLSB{}
+var _ Image = &HorizontalMSB{}
+var _ Image = &HorizontalLSB{}
@@ -0,0 +1,129 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package image1bit
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+// pattern returns a gray image with an irregular pattern.
+func pattern(r image.Rectangle) *image.Gray {
+	img := image.NewGray(r)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if (x*7+y*13)%5 < 2 {
+				img.SetGray(x, y, color.Gray{Y: 255})
+			}
+		}
+	}
+	return img
+}
+
+func newImages(r image.Rectangle) []Image {
+	return []Image{NewVerticalLSB(r), NewHorizontalMSB(r), NewHorizontalLSB(r)}
+}
+
+func equal(t *testing.T, got, want Image) {
+	t.Helper()
+	r := got.Bounds()
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if g, w := got.BitAt(x, y), want.BitAt(x, y); g != w {
+				t.Fatalf("%T: (%d, %d) = %s, want %s", got, x, y, g, w)
+			}
+		}
+	}
+}
+
+func TestDraw(t *testing.T) {
+	bounds := image.Rect(3, 2, 45, 21)
+	src := pattern(image.Rect(-5, 0, 50, 30))
+	data := []struct {
+		r  image.Rectangle
+		sp image.Point
+	}{
+		{bounds, bounds.Min},
+		{image.Rect(0, 0, 100, 100), image.Pt(-5, 0)},
+		{image.Rect(8, 4, 40, 10), image.Pt(0, 0)},
+		{image.Rect(8, 4, 40, 10), image.Pt(3, 1)},
+		{image.Rect(9, 4, 13, 10), image.Pt(1, 1)},
+		{image.Rect(10, 4, 12, 10), image.Pt(2, 1)},
+		{image.Rect(50, 50, 60, 60), image.Pt(0, 0)},
+	}
+	for _, line := range data {
+		want := NewVerticalLSB(bounds)
+		draw.Src.Draw(want, line.r, src, line.sp)
+
+		// Generic path.
+		for _, dst := range newImages(bounds) {
+			Draw(dst, line.r, src, line.sp)
+			equal(t, dst, want)
+		}
+
+		// Between 1 bit images, including the byte copy path.
+		for _, src1 := range newImages(src.Bounds()) {
+			draw.Src.Draw(src1, src1.Bounds(), src, src.Bounds().Min)
+			for _, dst := range newImages(bounds) {
+				Draw(dst, line.r, src1, line.sp)
+				equal(t, dst, want)
+			}
+		}
+
+		// Uniform.
+		want = NewVerticalLSB(bounds)
+		draw.Src.Draw(want, bounds, &image.Uniform{On}, image.Point{})
+		draw.Src.Draw(want, line.r, &image.Uniform{Off}, image.Point{})
+		for _, dst := range newImages(bounds) {
+			Draw(dst, bounds, &image.Uniform{color.White}, image.Point{})
+			Draw(dst, line.r, &image.Uniform{color.Black}, image.Point{})
+			equal(t, dst, want)
+		}
+	}
+}
+
+func BenchmarkDraw_Gray(b *testing.B) {
+	r := image.Rect(0, 0, 250, 128)
+	src := pattern(r)
+	dst := NewHorizontalMSB(r)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Draw(dst, r, src, image.Point{})
+	}
+}
+
+func BenchmarkDraw_Copy(b *testing.B) {
+	r := image.Rect(0, 0, 250, 128)
+	src := NewHorizontalMSB(r)
+	draw.Src.Draw(src, r, pattern(r), image.Point{})
+	dst := NewHorizontalMSB(r)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Draw(dst, r, src, image.Point{})
+	}
+}
+
+func BenchmarkDraw_Copy_Std(b *testing.B) {
+	r := image.Rect(0, 0, 250, 128)
+	src := NewHorizontalMSB(r)
+	draw.Src.Draw(src, r, pattern(r), image.Point{})
+	dst := NewHorizontalMSB(r)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		draw.Src.Draw(dst, r, src, image.Point{})
+	}
+}
+
+func BenchmarkDraw_Uniform(b *testing.B) {
+	r := image.Rect(0, 0, 250, 128)
+	dst := NewHorizontalMSB(r)
+	u := &image.Uniform{On}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Draw(dst, r, u, image.Point{})
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package image1bit
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// HorizontalMSB is a 1 bit (black and white) image.
+//
+// Each byte is 8 horizontal pixels with the MSB being the leftmost pixel. Each
+// stride is a row of pixels. So the first byte represents the following
+// pixels, with the highest bit being the top left pixel.
+//
+//	7 6 5 4 3 2 1 0
+//	x x x x x x x x
+//
+// It is the layout used by most e-paper display controllers.
+type HorizontalMSB struct {
+	// Pix holds the image's pixels, as horizontally MSB-first packed bitmap.
+	Pix []byte
+	// Stride is the Pix stride (in bytes) between vertically adjacent pixels.
+	Stride int
+	// Rect is the image's bounds.
+	Rect image.Rectangle
+}
+
+// NewHorizontalMSB returns an initialized HorizontalMSB instance.
+func NewHorizontalMSB(r image.Rectangle) *HorizontalMSB {
+	stride := horizontalStride(r)
+	return &HorizontalMSB{Pix: make([]byte, stride*r.Dy()), Stride: stride, Rect: r}
+}
+
+// ColorModel implements image.Image.
+func (i *HorizontalMSB) ColorModel() color.Model {
+	return BitModel
+}
+
+// Bounds implements image.Image.
+func (i *HorizontalMSB) Bounds() image.Rectangle {
+	return i.Rect
+}
+
+// At implements image.Image.
+func (i *HorizontalMSB) At(x, y int) color.Color {
+	return i.BitAt(x, y)
+}
+
+// BitAt is the optimized version of At().
+func (i *HorizontalMSB) BitAt(x, y int) Bit {
+	if !(image.Point{x, y}.In(i.Rect)) {
+		return Off
+	}
+	offset, mask := i.PixOffset(x, y)
+	return Bit(i.Pix[offset]&mask != 0)
+}
+
+// Opaque scans the entire image and reports whether it is fully opaque.
+func (i *HorizontalMSB) Opaque() bool {
+	return true
+}
+
+// PixOffset returns the index of the first element of Pix that corresponds to
+// the pixel at (x, y) and the corresponding mask.
+func (i *HorizontalMSB) PixOffset(x, y int) (int, byte) {
+	// Adjust band.
+	pX := x - i.Rect.Min.X&^7
+	offset := (y-i.Rect.Min.Y)*i.Stride + pX/8
+	return offset, 0x80 >> uint(pX&7)
+}
+
+// Set implements draw.Image
+func (i *HorizontalMSB) Set(x, y int, c color.Color) {
+	i.SetBit(x, y, convertBit(c))
+}
+
+// SetBit is the optimized version of Set().
+func (i *HorizontalMSB) SetBit(x, y int, b Bit) {
+	if !(image.Point{x, y}.In(i.Rect)) {
+		return
+	}
+	offset, mask := i.PixOffset(x, y)
+	if b {
+		i.Pix[offset] |= mask
+	} else {
+		i.Pix[offset] &^= mask
+	}
+}
+
+// HorizontalLSB is a 1 bit (black and white) image.
+//
+// Each byte is 8 horizontal pixels with the LSB being the leftmost pixel. Each
+// stride is a row of pixels. So the first byte represents the following
+// pixels, with the lowest bit being the top left pixel.
+//
+//	0 1 2 3 4 5 6 7
+//	x x x x x x x x
+type HorizontalLSB struct {
+	// Pix holds the image's pixels, as horizontally LSB-first packed bitmap.
+	Pix []byte
+	// Stride is the Pix stride (in bytes) between vertically adjacent pixels.
+	Stride int
+	// Rect is the image's bounds.
+	Rect image.Rectangle
+}
+
+// NewHorizontalLSB returns an initialized HorizontalLSB instance.
+func NewHorizontalLSB(r image.Rectangle) *HorizontalLSB {
+	stride := horizontalStride(r)
+	return &HorizontalLSB{Pix: make([]byte, stride*r.Dy()), Stride: stride, Rect: r}
+}
+
+// ColorModel implements image.Image.
+func (i *HorizontalLSB) ColorModel() color.Model {
+	return BitModel
+}
+
+// Bounds implements image.Image.
+func (i *HorizontalLSB) Bounds() image.Rectangle {
+	return i.Rect
+}
+
+// At implements image.Image.
+func (i *HorizontalLSB) At(x, y int) color.Color {
+	return i.BitAt(x, y)
+}
+
+// BitAt is the optimized version of At().
+func (i *HorizontalLSB) BitAt(x, y int) Bit {
+	if !(image.Point{x, y}.In(i.Rect)) {
+		return Off
+	}
+	offset, mask := i.PixOffset(x, y)
+	return Bit(i.Pix[offset]&mask != 0)
+}
+
+// Opaque scans the entire image and reports whether it is fully opaque.
+func (i *HorizontalLSB) Opaque() bool {
+	return true
+}
+
+// PixOffset returns the index of the first element of Pix that corresponds to
+// the pixel at (x, y) and the corresponding mask.
+func (i *HorizontalLSB) PixOffset(x, y int) (int, byte) {
+	// Adjust band.
+	pX := x - i.Rect.Min.X&^7
+	offset := (y-i.Rect.Min.Y)*i.Stride + pX/8
+	return offset, 1 << uint(pX&7)
+}
+
+// Set implements draw.Image
+func (i *HorizontalLSB) Set(x, y int, c color.Color) {
+	i.SetBit(x, y, convertBit(c))
+}
+
+// SetBit is the optimized version of Set().
+func (i *HorizontalLSB) SetBit(x, y int, b Bit) {
+	if !(image.Point{x, y}.In(i.Rect)) {
+		return
+	}
+	offset, mask := i.PixOffset(x, y)
+	if b {
+		i.Pix[offset] |= mask
+	} else {
+		i.Pix[offset] &^= mask
+	}
+}
+
+//
+
+var _ draw.Image = &HorizontalMSB{}
+var _ draw.Image = &HorizontalLSB{}
+
+// horizontalStride returns the number of bytes per row, with the rows aligned
+// on a multiple of 8 pixels.
+func horizontalStride(r image.Rectangle) int {
+	if r.Empty() {
+		return 0
+	}
+	// Round down.
+	minX := r.Min.X &^ 7
+	// Round up.
+	maxX := (r.Max.X + 7) &^ 7
+	return (maxX - minX) / 8
+}
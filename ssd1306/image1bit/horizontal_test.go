@@ -0,0 +1,111 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package image1bit
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestHorizontal_New(t *testing.T) {
+	data := []struct {
+		r      image.Rectangle
+		l      int
+		stride int
+	}{
+		{image.Rect(0, 0, 0, 1), 0, 0},
+		{image.Rect(0, 0, 1, 0), 0, 0},
+		{image.Rect(0, 0, 1, 1), 1, 1},
+		{image.Rect(0, 0, 8, 2), 2, 1},
+		{image.Rect(0, 0, 9, 2), 4, 2},
+		{image.Rect(7, 0, 9, 1), 2, 2},
+		{image.Rect(8, 0, 16, 1), 1, 1},
+		{image.Rect(0, 0, 122, 250), 4000, 16},
+	}
+	for i, line := range data {
+		m := NewHorizontalMSB(line.r)
+		if l := len(m.Pix); l != line.l || m.Stride != line.stride || m.Rect != line.r {
+			t.Fatalf("#%d: MSB len(Pix)=%d Stride=%d", i, l, m.Stride)
+		}
+		l := NewHorizontalLSB(line.r)
+		if n := len(l.Pix); n != line.l || l.Stride != line.stride || l.Rect != line.r {
+			t.Fatalf("#%d: LSB len(Pix)=%d Stride=%d", i, n, l.Stride)
+		}
+	}
+}
+
+func TestHorizontalMSB_PixOffset(t *testing.T) {
+	img := NewHorizontalMSB(image.Rect(3, 1, 20, 4))
+	data := []struct {
+		x, y   int
+		offset int
+		mask   byte
+	}{
+		{3, 1, 0, 0x10},
+		{7, 1, 0, 0x01},
+		{8, 1, 1, 0x80},
+		{19, 3, 8, 0x10},
+	}
+	for i, line := range data {
+		if offset, mask := img.PixOffset(line.x, line.y); offset != line.offset || mask != line.mask {
+			t.Fatalf("#%d: PixOffset(%d, %d) = %d, %#x", i, line.x, line.y, offset, mask)
+		}
+	}
+}
+
+func TestHorizontalLSB_PixOffset(t *testing.T) {
+	img := NewHorizontalLSB(image.Rect(3, 1, 20, 4))
+	data := []struct {
+		x, y   int
+		offset int
+		mask   byte
+	}{
+		{3, 1, 0, 0x08},
+		{7, 1, 0, 0x80},
+		{8, 1, 1, 0x01},
+		{19, 3, 8, 0x08},
+	}
+	for i, line := range data {
+		if offset, mask := img.PixOffset(line.x, line.y); offset != line.offset || mask != line.mask {
+			t.Fatalf("#%d: PixOffset(%d, %d) = %d, %#x", i, line.x, line.y, offset, mask)
+		}
+	}
+}
+
+func TestHorizontal_Set(t *testing.T) {
+	for _, img := range []Image{NewHorizontalMSB(image.Rect(0, 0, 10, 2)), NewHorizontalLSB(image.Rect(0, 0, 10, 2))} {
+		if c := img.ColorModel(); c != BitModel {
+			t.Fatal(c)
+		}
+		if !img.(interface{ Opaque() bool }).Opaque() {
+			t.Fatal("expected opaque")
+		}
+		img.Set(9, 1, color.White)
+		img.SetBit(10, 1, On)
+		if c := img.At(9, 1); c != On {
+			t.Fatal(c)
+		}
+		if b := img.BitAt(10, 1); b != Off {
+			t.Fatal(b)
+		}
+		img.SetBit(9, 1, Off)
+		if b := img.BitAt(9, 1); b != Off {
+			t.Fatal(b)
+		}
+	}
+	m := NewHorizontalMSB(image.Rect(0, 0, 10, 2))
+	m.SetBit(0, 1, On)
+	m.SetBit(9, 1, On)
+	if m.Pix[2] != 0x80 || m.Pix[3] != 0x40 {
+		t.Fatalf("%#v", m.Pix)
+	}
+	l := NewHorizontalLSB(image.Rect(0, 0, 10, 2))
+	l.SetBit(0, 1, On)
+	l.SetBit(9, 1, On)
+	if l.Pix[2] != 0x01 || l.Pix[3] != 0x02 {
+		t.Fatalf("%#v", l.Pix)
+	}
+}
@@ -6,8 +6,10 @@
 //
 // It is compatible with package image/draw.
 //
-// VerticalLSB is the only bit packing implemented as it is used by the
-// ssd1306. Others would be VerticalMSB, HorizontalLSB and HorizontalMSB.
+// VerticalLSB is the bit packing used by the ssd1306. HorizontalMSB and
+// HorizontalLSB are the packings used by most e-paper display controllers.
+//
+// Draw copies between images faster than image/draw for the common cases.
 package image1bit
 
 import (
@@ -10,11 +10,11 @@ package ssd1306
 
 import (
 	"bytes"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"image"
 	"image/color"
-	"image/draw"
 
 	"periph.io/x/conn/v3"
 	"periph.io/x/conn/v3/display"
@@ -93,6 +93,22 @@ var DefaultOpts = Opts{
 	SwapTopBottom:    false,
 }
 
+// Opts72x40 is the options for the 0.42" 72x40 modules, which use the
+// center columns of the controller.
+var Opts72x40 = Opts{
+	W:         72,
+	H:         40,
+	ColOffset: 28,
+}
+
+// Opts64x48 is the options for the 0.66" 64x48 modules, which use the
+// center columns of the controller.
+var Opts64x48 = Opts{
+	W:         64,
+	H:         48,
+	ColOffset: 32,
+}
+
 // Opts defines the options for the device.
 type Opts struct {
 	W int
@@ -117,6 +133,18 @@ type Opts struct {
 	// the OLED panel hardware. Try toggling this if the top and bottom halves of
 	// your display are swapped.
 	SwapTopBottom bool
+	// ColOffset is the first column of the controller connected to the panel.
+	// Panels narrower than 128 pixels are usually connected to the center
+	// columns. W+ColOffset must not exceed 128.
+	ColOffset int
+	// RowOffset is the display offset, the row of the controller mapped to
+	// the first line of the panel. H+RowOffset must not exceed 64.
+	RowOffset int
+	// ReinitAfter is the number of consecutive failed transfers after which
+	// the initialization sequence is sent again before the next draw, to
+	// recover a controller that lost its state in a power glitch. 0 disables
+	// it; Reinit can still be called explicitly.
+	ReinitAfter int
 }
 
 // NewSPI returns a Dev object that communicates over SPI to a SSD1306 display
@@ -132,6 +160,11 @@ type Opts struct {
 // In 3-wire SPI mode, pass nil for 'dc'. In 4-wire SPI mode, pass a GPIO pin
 // to use.
 //
+// In 3-wire SPI mode each byte is sent as a 9 bits word with the D/C bit first.
+// The words are packed in a 8 bits SPI stream so no 9 bits support is needed
+// from the SPI controller; the trailing partial word is ignored by the
+// controller when CS is deasserted.
+//
 // The RES (reset) pin can be used outside of this driver but is not supported
 // natively. In case of external reset via the RES pin, this device drive must
 // be reinstantiated.
@@ -139,25 +172,75 @@ func NewSPI(p spi.Port, dc gpio.PinOut, opts *Opts) (*Dev, error) {
 	if dc == gpio.INVALID {
 		return nil, errors.New("ssd1306: use nil for dc to use 3-wire mode, do not use gpio.INVALID")
 	}
-	bits := 8
-	if dc == nil {
-		// 3-wire SPI uses 9 bits per word.
-		bits = 9
-	} else if err := dc.Out(gpio.Low); err != nil {
-		return nil, err
+	if dc != nil {
+		if err := dc.Out(gpio.Low); err != nil {
+			return nil, err
+		}
 	}
-	c, err := p.Connect(3300*physic.KiloHertz, spi.Mode0, bits)
+	c, err := p.Connect(3300*physic.KiloHertz, spi.Mode0, 8)
 	if err != nil {
 		return nil, err
 	}
 	return newDev(c, opts, true, dc)
 }
 
+// I²C addresses of the controller, selected by its SA0 pin.
+const (
+	I2CAddr    uint16 = 0x3C
+	I2CAddrAlt uint16 = 0x3D
+)
+
 // NewI2C returns a Dev object that communicates over I²C to a SSD1306 display
-// controller.
+// controller at I2CAddr.
 func NewI2C(i i2c.Bus, opts *Opts) (*Dev, error) {
+	return NewI2CAddr(i, I2CAddr, opts)
+}
+
+// NewI2CAddr returns a Dev object that communicates over I²C to a SSD1306
+// display controller at addr, usually I2CAddr or I2CAddrAlt.
+func NewI2CAddr(i i2c.Bus, addr uint16, opts *Opts) (*Dev, error) {
 	// Maximum clock speed is 1/2.5µs = 400KHz.
-	return newDev(&i2c.Dev{Bus: i, Addr: 0x3C}, opts, false, nil)
+	return newDev(&i2c.Dev{Bus: i, Addr: addr}, opts, false, nil)
+}
+
+// Scan returns the addresses among I2CAddr and I2CAddrAlt where a SSD1306
+// controller answers.
+//
+// The controller is identified by the ID in the low bits of its status byte,
+// which tells it apart from other devices at these addresses and from the
+// SH1106 controller.
+func Scan(i i2c.Bus) []uint16 {
+	var addrs []uint16
+	for _, addr := range []uint16{I2CAddr, I2CAddrAlt} {
+		var status [1]byte
+		if i.Tx(addr, nil, status[:]) != nil {
+			continue
+		}
+		if id := status[0] & statusIDMask; id == statusID128x32 || id == statusID128x64 {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
+// NewI2CAll returns a Dev for each SSD1306 controller found by Scan, all
+// configured with opts.
+//
+// This is convenient for the common setup of two displays sharing a bus.
+func NewI2CAll(i i2c.Bus, opts *Opts) ([]*Dev, error) {
+	addrs := Scan(i)
+	if len(addrs) == 0 {
+		return nil, errors.New("ssd1306: no display found")
+	}
+	devs := make([]*Dev, 0, len(addrs))
+	for _, addr := range addrs {
+		d, err := NewI2CAddr(i, addr, opts)
+		if err != nil {
+			return nil, fmt.Errorf("ssd1306: display at %#x: %w", addr, err)
+		}
+		devs = append(devs, d)
+	}
+	return devs, nil
 }
 
 // Dev is an open handle to the display controller.
@@ -169,6 +252,11 @@ type Dev struct {
 
 	// Display size controlled by the SSD1306.
 	rect image.Rectangle
+	// colOffset is the first column of the controller used by the panel.
+	colOffset int
+	// initCmd is the initialization sequence, sent again by Reinit.
+	initCmd     []byte
+	reinitAfter int
 
 	// Mutable
 	// See page 25 for the GDDRAM pages structure.
@@ -183,11 +271,23 @@ type Dev struct {
 	startPage, endPage int
 	startCol, endCol   int
 	scrolled           bool
+	scrollArea         bool
 	halted             bool
+	// failures is the number of consecutive failed transfers.
+	failures int
+	// needInit is set once failures reaches reinitAfter.
+	needInit bool
+	// Display settings changed by the user, restored by Reinit.
+	contrast byte
+	inverted bool
+	dimmed   bool
 }
 
 func (d *Dev) String() string {
 	if d.spi {
+		if d.dc == nil {
+			return fmt.Sprintf("ssd1360.Dev{%s, 3-wire, %s}", d.c, d.rect.Max)
+		}
 		return fmt.Sprintf("ssd1360.Dev{%s, %s, %s}", d.c, d.dc, d.rect.Max)
 	}
 	return fmt.Sprintf("ssd1360.Dev{%s, %s}", d.c, d.rect.Max)
@@ -212,6 +312,9 @@ func (d *Dev) Bounds() image.Rectangle {
 // to a background goroutine.
 func (d *Dev) Draw(r image.Rectangle, src image.Image, sp image.Point) error {
 	var next []byte
+	// dirty is the area that may have changed; only this area is compared
+	// with what was previously sent.
+	dirty := d.rect
 	if img, ok := src.(*image1bit.VerticalLSB); ok && r == d.rect && img.Rect == d.rect && sp.X == 0 && sp.Y == 0 {
 		// Exact size, full frame, image1bit encoding: fast path!
 		next = img.Pix
@@ -221,9 +324,10 @@ func (d *Dev) Draw(r image.Rectangle, src image.Image, sp image.Point) error {
 			d.next = image1bit.NewVerticalLSB(d.rect)
 		}
 		next = d.next.Pix
-		draw.Src.Draw(d.next, r, src, sp)
+		image1bit.Draw(d.next, r, src, sp)
+		dirty = r.Intersect(d.rect)
 	}
-	return d.drawInternal(next)
+	return d.drawInternal(next, dirty)
 }
 
 // Write writes a buffer of pixels to the display.
@@ -237,7 +341,7 @@ func (d *Dev) Write(pixels []byte) (int, error) {
 		return 0, fmt.Errorf("ssd1306: invalid pixel stream length; expected %d bytes, got %d bytes", len(d.buffer), len(pixels))
 	}
 	// Write() skips d.next so it saves 1kb of RAM.
-	if err := d.drawInternal(pixels); err != nil {
+	if err := d.drawInternal(pixels, d.rect); err != nil {
 		return 0, err
 	}
 	return len(pixels), nil
@@ -250,23 +354,14 @@ func (d *Dev) Write(pixels []byte) (int, error) {
 // Both startLine and endLine must be multiples of 8.
 //
 // Use -1 for endLine to extend to the bottom of the display.
+//
+// The vertical part of UpRight and UpLeft scrolls the whole display; use
+// ScrollRegion to keep the lines outside the band fixed.
 func (d *Dev) Scroll(o Orientation, rate FrameRate, startLine, endLine int) error {
-	h := d.rect.Dy()
-	if endLine == -1 {
-		endLine = h
-	}
-	if startLine >= endLine {
-		return fmt.Errorf("startLine (%d) must be lower than endLine (%d)", startLine, endLine)
-	}
-	if startLine&7 != 0 || startLine < 0 || startLine >= h {
-		return fmt.Errorf("invalid startLine %d", startLine)
-	}
-	if endLine&7 != 0 || endLine < 0 || endLine > h {
-		return fmt.Errorf("invalid endLine %d", endLine)
+	startPage, endPage, err := d.scrollPages(startLine, endLine)
+	if err != nil {
+		return err
 	}
-
-	startPage := uint8(startLine / 8)
-	endPage := uint8(endLine / 8)
 	d.scrolled = true
 	if o == Left || o == Right {
 		// page 28
@@ -275,8 +370,43 @@ func (d *Dev) Scroll(o Orientation, rate FrameRate, startLine, endLine int) erro
 	}
 	// page 29
 	// <op>, dummy, <start page>, <rate>,  <end page>, <offset>, <ENABLE>
-	// page 30: 0xA3 permits to set rows for scroll area.
-	return d.sendCommand([]byte{byte(o), 0x00, startPage, byte(rate), endPage - 1, 0x01, 0x2F})
+	cmd := []byte{byte(o), 0x00, startPage, byte(rate), endPage - 1, 0x01, 0x2F}
+	if d.scrollArea {
+		// Restore the whole display as the vertical scroll area set by
+		// ScrollRegion.
+		cmd = append([]byte{0xA3, 0x00, byte(d.rect.Dy())}, cmd...)
+		d.scrollArea = false
+	}
+	return d.sendCommand(cmd)
+}
+
+// ScrollRegion scrolls the band between startLine and endLine while the lines
+// above and below it stay in place, for example to keep a status bar fixed.
+//
+// It is the same as Scroll, except that the vertical part of UpRight and
+// UpLeft is limited to the band with the set vertical scroll area command.
+// Left and Right only scroll the band in both cases.
+//
+// Both startLine and endLine must be multiples of 8 as the horizontal
+// scrolling works on pages of 8 lines.
+//
+// Use -1 for endLine to extend to the bottom of the display.
+func (d *Dev) ScrollRegion(o Orientation, rate FrameRate, startLine, endLine int) error {
+	if o == Left || o == Right {
+		return d.Scroll(o, rate, startLine, endLine)
+	}
+	startPage, endPage, err := d.scrollPages(startLine, endLine)
+	if err != nil {
+		return err
+	}
+	d.scrolled = true
+	d.scrollArea = true
+	// page 30
+	// 0xA3, <fixed rows>, <scrolled rows>
+	return d.sendCommand([]byte{
+		0xA3, startPage * 8, (endPage - startPage) * 8,
+		byte(o), 0x00, startPage, byte(rate), endPage - 1, 0x01, 0x2F,
+	})
 }
 
 // StopScroll stops any scrolling previously set and resets the screen.
@@ -286,9 +416,35 @@ func (d *Dev) StopScroll() error {
 
 // SetContrast changes the screen contrast.
 //
+// While the display is dimmed, the contrast is only applied once SetDim(false)
+// is called.
+//
 // Note: values other than 0xff do not seem useful...
 func (d *Dev) SetContrast(level byte) error {
-	return d.sendCommand([]byte{0x81, level})
+	if d.dimmed {
+		d.contrast = level
+		return nil
+	}
+	if err := d.sendCommand([]byte{0x81, level}); err != nil {
+		return err
+	}
+	d.contrast = level
+	return nil
+}
+
+// SetDim lowers the brightness of the display by setting the minimum contrast
+// and a shorter pre-charge period. SetDim(false) restores the contrast set
+// with SetContrast.
+func (d *Dev) SetDim(dim bool) error {
+	c := []byte{0x81, d.contrast, 0xD9, 0xF1}
+	if dim {
+		c = []byte{0x81, 0x00, 0xD9, 0x22}
+	}
+	if err := d.sendCommand(c); err != nil {
+		return err
+	}
+	d.dimmed = dim
+	return nil
 }
 
 // SetDisplayStartLine causes the display to start from startLine, effectively
@@ -304,7 +460,8 @@ func (d *Dev) SetDisplayStartLine(startLine byte) error {
 
 // Halt turns off the display.
 //
-// Sending any other command afterward reenables the display.
+// Sending any other command afterward reenables the display, with the same
+// contrast, dimming and inversion.
 func (d *Dev) Halt() error {
 	d.halted = false
 	err := d.sendCommand([]byte{0xAE})
@@ -314,17 +471,52 @@ func (d *Dev) Halt() error {
 	return err
 }
 
+// Reinit sends the initialization sequence again and redraws the last frame.
+//
+// Use it when the controller lost its state, e.g. after a brown out, as the
+// following writes would otherwise render garbage. The contrast, dimming and
+// inversion are restored, the scrolling and start line are reset to their
+// defaults.
+func (d *Dev) Reinit() error {
+	if err := d.reinit(); err != nil {
+		return err
+	}
+	return d.drawInternal(d.buffer, d.rect)
+}
+
 // Invert the display (black on white vs white on black).
 func (d *Dev) Invert(blackOnWhite bool) error {
 	b := []byte{0xA6}
 	if blackOnWhite {
 		b[0] = 0xA7
 	}
-	return d.sendCommand(b)
+	if err := d.sendCommand(b); err != nil {
+		return err
+	}
+	d.inverted = blackOnWhite
+	return nil
 }
 
 //
 
+// scrollPages validates the band of lines to scroll and returns its pages.
+func (d *Dev) scrollPages(startLine, endLine int) (uint8, uint8, error) {
+	h := d.rect.Dy()
+	if endLine == -1 {
+		endLine = h
+	}
+	if startLine >= endLine {
+		return 0, 0, fmt.Errorf("startLine (%d) must be lower than endLine (%d)", startLine, endLine)
+	}
+	if startLine&7 != 0 || startLine < 0 || startLine >= h {
+		return 0, 0, fmt.Errorf("invalid startLine %d", startLine)
+	}
+	if endLine&7 != 0 || endLine < 0 || endLine > h {
+		return 0, 0, fmt.Errorf("invalid endLine %d", endLine)
+	}
+	return uint8(startLine / 8), uint8(endLine / 8), nil
+}
+
 // newDev is the common initialization code that is independent of the
 // communication protocol (I²C or SPI) being used.
 func newDev(c conn.Conn, opts *Opts, usingSPI bool, dc gpio.PinOut) (*Dev, error) {
@@ -334,6 +526,15 @@ func newDev(c conn.Conn, opts *Opts, usingSPI bool, dc gpio.PinOut) (*Dev, error
 	if opts.H < 8 || opts.H > 64 || opts.H&7 != 0 {
 		return nil, fmt.Errorf("ssd1306: invalid height %d", opts.H)
 	}
+	if opts.ColOffset < 0 || opts.W+opts.ColOffset > 128 {
+		return nil, fmt.Errorf("ssd1306: invalid column offset %d", opts.ColOffset)
+	}
+	if opts.RowOffset < 0 || opts.H+opts.RowOffset > 64 {
+		return nil, fmt.Errorf("ssd1306: invalid row offset %d", opts.RowOffset)
+	}
+	if opts.ReinitAfter < 0 {
+		return nil, fmt.Errorf("ssd1306: invalid ReinitAfter %d", opts.ReinitAfter)
+	}
 
 	nbPages := opts.H / 8
 	pageSize := opts.W
@@ -342,6 +543,8 @@ func newDev(c conn.Conn, opts *Opts, usingSPI bool, dc gpio.PinOut) (*Dev, error
 		spi:       usingSPI,
 		dc:        dc,
 		rect:      image.Rect(0, 0, opts.W, opts.H),
+		colOffset: opts.ColOffset,
+		initCmd:   getInitCmd(opts),
 		buffer:    make([]byte, nbPages*pageSize),
 		startPage: 0,
 		endPage:   nbPages,
@@ -349,10 +552,12 @@ func newDev(c conn.Conn, opts *Opts, usingSPI bool, dc gpio.PinOut) (*Dev, error
 		endCol:    opts.W,
 		// Signal that the screen must be redrawn on first draw().
 		scrolled: true,
+		contrast: 0xFF,
 	}
-	if err := d.sendCommand(getInitCmd(opts)); err != nil {
+	if err := d.sendCommand(d.initCmd); err != nil {
 		return nil, err
 	}
+	d.reinitAfter = opts.ReinitAfter
 	return d, nil
 }
 
@@ -389,8 +594,8 @@ func getInitCmd(opts *Opts) []byte {
 	// Page 64 has the full recommended flow.
 	// Page 28 lists all the commands.
 	return []byte{
-		0xAE,       // Display off
-		0xD3, 0x00, // Set display offset; 0
+		0xAE,                       // Display off
+		0xD3, byte(opts.RowOffset), // Set display offset
 		0x40,           // Start display start line; 0
 		columnAddr,     // Set segment remap; RESET is column 127.
 		comScan,        //
@@ -405,99 +610,195 @@ func getInitCmd(opts *Opts) []byte {
 		0x2E,                   // Deactivate scroll
 		0xA8, byte(opts.H - 1), // Set multiplex ratio (number of lines to display)
 		0x20, 0x00, // Set memory addressing mode to horizontal
-		0x21, 0, uint8(opts.W - 1), // Set column address (Width)
+		0x21, byte(opts.ColOffset), byte(opts.ColOffset + opts.W - 1), // Set column address (Width)
 		0x22, 0, uint8(opts.H/8 - 1), // Set page address (Pages)
 		0xAF, // Display on
 	}
 }
 
-func (d *Dev) calculateSubset(next []byte) (int, int, int, int, bool) {
+// calculateSubset returns the smallest area in pages and columns that differs
+// between what was last sent and next. Only the area covered by dirty is
+// compared, the rest is assumed to be unchanged.
+func (d *Dev) calculateSubset(next []byte, dirty image.Rectangle) (int, int, int, int, bool) {
 	w := d.rect.Dx()
 	h := d.rect.Dy()
-	startPage := 0
-	endPage := h / 8
-	startCol := 0
-	endCol := w
 	if d.scrolled {
 		// Painting disable scrolling but if scrolling was enabled, this requires a
 		// full screen redraw.
 		d.scrolled = false
-	} else {
-		// Calculate the smallest square that need to be sent.
-		pageSize := w
-
-		// Top.
-		for ; startPage < endPage; startPage++ {
-			x := pageSize * startPage
-			y := pageSize * (startPage + 1)
-			if !bytes.Equal(d.buffer[x:y], next[x:y]) {
-				break
-			}
-		}
-		// Bottom.
-		for ; endPage > startPage; endPage-- {
-			x := pageSize * (endPage - 1)
-			y := pageSize * endPage
-			if !bytes.Equal(d.buffer[x:y], next[x:y]) {
-				break
-			}
+		return 0, h / 8, 0, w, false
+	}
+	if dirty.Empty() {
+		return 0, 0, 0, 0, true
+	}
+
+	// Calculate the smallest square that need to be sent. Only the dirty area
+	// is compared, 8 bytes at a time.
+	pageSize := w
+	x0, x1 := dirty.Min.X, dirty.Max.X
+	startPage := dirty.Min.Y / 8
+	endPage := (dirty.Max.Y + 7) / 8
+
+	// Top.
+	for ; startPage < endPage; startPage++ {
+		x := startPage * pageSize
+		if !bytes.Equal(d.buffer[x+x0:x+x1], next[x+x0:x+x1]) {
+			break
 		}
-		if startPage == endPage {
-			// Early exit, the image is exactly the same.
-			return 0, 0, 0, 0, true
+	}
+	// Bottom.
+	for ; endPage > startPage; endPage-- {
+		x := (endPage - 1) * pageSize
+		if !bytes.Equal(d.buffer[x+x0:x+x1], next[x+x0:x+x1]) {
+			break
 		}
+	}
+	if startPage == endPage {
+		// Early exit, the image is exactly the same.
+		return 0, 0, 0, 0, true
+	}
 
-		// Left.
-		for ; startCol < endCol; startCol++ {
-			for i := startPage; i < endPage; i++ {
-				x := i*pageSize + startCol
-				if d.buffer[x] != next[x] {
-					goto breakLeft
-				}
+	// Left. A change on the edge column is checked first, as the 8 bytes
+	// chunks scan is slower when the first difference is there.
+	startCol := x1
+	if columnDiffers(d.buffer, next, pageSize, x0, startPage, endPage) {
+		startCol = x0
+	}
+	for c := x0; c < x1 && startCol == x1; c += 8 {
+		e := min(c+8, x1)
+		for page := startPage; page < endPage; page++ {
+			x := page * pageSize
+			old, cur := d.buffer[x+c:x+e], next[x+c:x+e]
+			if equal8(old, cur) {
+				continue
+			}
+			if i := firstDiff(old, cur); c+i < startCol {
+				startCol = c + i
 			}
 		}
-	breakLeft:
-
-		// Right.
-		for ; endCol > startCol; endCol-- {
-			for i := startPage; i < endPage; i++ {
-				x := i*pageSize + endCol - 1
-				if d.buffer[x] != next[x] {
-					goto breakRight
-				}
+	}
+
+	// Right.
+	endCol := startCol
+	if columnDiffers(d.buffer, next, pageSize, x1-1, startPage, endPage) {
+		endCol = x1
+	}
+	for c := x1; c > startCol && endCol == startCol; c -= 8 {
+		b := max(c-8, startCol)
+		for page := startPage; page < endPage; page++ {
+			x := page * pageSize
+			old, cur := d.buffer[x+b:x+c], next[x+b:x+c]
+			if equal8(old, cur) {
+				continue
+			}
+			if i := lastDiff(old, cur); b+i+1 > endCol {
+				endCol = b + i + 1
 			}
 		}
-	breakRight:
 	}
 	return startPage, endPage, startCol, endCol, false
 }
 
+// columnDiffers returns true if the column col of a and b differs on a page
+// between startPage and endPage.
+func columnDiffers(a, b []byte, pageSize, col, startPage, endPage int) bool {
+	for page := startPage; page < endPage; page++ {
+		if x := page*pageSize + col; a[x] != b[x] {
+			return true
+		}
+	}
+	return false
+}
+
+// equal8 returns true if a and b, of up to 8 bytes, are equal.
+func equal8(a, b []byte) bool {
+	if len(a) == 8 {
+		return binary.LittleEndian.Uint64(a) == binary.LittleEndian.Uint64(b)
+	}
+	return bytes.Equal(a, b)
+}
+
+// firstDiff returns the index of the first byte of a that differs from b, or
+// -1.
+func firstDiff(a, b []byte) int {
+	for i := range a {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	return -1
+}
+
+// lastDiff returns the index of the last byte of a that differs from b, or
+// -1.
+func lastDiff(a, b []byte) int {
+	for i := len(a) - 1; i >= 0; i-- {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	return -1
+}
+
+// reinit sends the initialization sequence. The next draw redraws the whole
+// screen.
+func (d *Dev) reinit() error {
+	// The sequence turns the display on.
+	d.halted = false
+	if err := d.sendCommand(append(d.initCmd[:len(d.initCmd):len(d.initCmd)], d.settings()...)); err != nil {
+		return err
+	}
+	d.needInit = false
+	d.scrollArea = false
+	d.scrolled = true
+	return nil
+}
+
+// settings returns the commands restoring the display settings that differ
+// from the initialization sequence.
+func (d *Dev) settings() []byte {
+	var c []byte
+	if d.dimmed {
+		c = append(c, 0x81, 0x00, 0xD9, 0x22)
+	} else if d.contrast != 0xFF {
+		c = append(c, 0x81, d.contrast)
+	}
+	if d.inverted {
+		c = append(c, 0xA7)
+	}
+	return c
+}
+
 // drawInternal sends image data to the controller.
-func (d *Dev) drawInternal(next []byte) error {
-	startPage, endPage, startCol, endCol, skip := d.calculateSubset(next)
+func (d *Dev) drawInternal(next []byte, dirty image.Rectangle) error {
+	if d.needInit {
+		if err := d.reinit(); err != nil {
+			return err
+		}
+	}
+	startPage, endPage, startCol, endCol, skip := d.calculateSubset(next, dirty)
 	if skip {
 		return nil
 	}
-	copy(d.buffer, next)
 
-	if d.startPage != startPage || d.endPage != endPage || d.startCol != startCol || d.endCol != endCol {
-		d.startPage = startPage
-		d.endPage = endPage
-		d.startCol = startCol
-		d.endCol = endCol
-	}
+	d.startPage = startPage
+	d.endPage = endPage
+	d.startCol = startCol
+	d.endCol = endCol
 
 	pageSize := d.rect.Dx()
+	col := byte(d.colOffset + d.startCol)
 	for page := d.startPage; page < d.endPage; page++ {
+		pageStart := page * pageSize
+		copy(d.buffer[pageStart+d.startCol:pageStart+d.endCol], next[pageStart+d.startCol:pageStart+d.endCol])
 		err := d.sendCommand([]byte{
 			_PAGESTARTADDRESS | byte(page),
-			_SETLOWCOLUMN | (byte(d.startCol) & 0x0F),
-			_SETHIGHCOLUMN | (byte(d.startCol) >> 4),
+			_SETLOWCOLUMN | (col & 0x0F),
+			_SETHIGHCOLUMN | (col >> 4),
 		})
 		if err != nil {
 			return err
 		}
-		pageStart := page * pageSize
 		err = d.sendData(d.buffer[pageStart+d.startCol : pageStart+d.endCol])
 		if err != nil {
 			return err
@@ -514,13 +815,17 @@ func (d *Dev) sendData(c []byte) error {
 		}
 	}
 	if d.spi {
+		if d.dc == nil {
+			// 3-wire SPI.
+			return d.tx(pack9Bits(true, c))
+		}
 		// 4-wire SPI.
 		if err := d.dc.Out(gpio.High); err != nil {
 			return err
 		}
-		return d.c.Tx(c, nil)
+		return d.tx(c)
 	}
-	return d.c.Tx(append([]byte{i2cData}, c...), nil)
+	return d.tx(append([]byte{i2cData}, c...))
 }
 
 func (d *Dev) sendCommand(c []byte) error {
@@ -532,15 +837,50 @@ func (d *Dev) sendCommand(c []byte) error {
 	if d.spi {
 		if d.dc == nil {
 			// 3-wire SPI.
-			return errors.New("ssd1306: 3-wire SPI mode is not yet implemented")
+			return d.tx(pack9Bits(false, c))
 		}
 		// 4-wire SPI.
 		if err := d.dc.Out(gpio.Low); err != nil {
 			return err
 		}
-		return d.c.Tx(c, nil)
+		return d.tx(c)
+	}
+	return d.tx(append([]byte{i2cCmd}, c...))
+}
+
+// tx sends w and counts the consecutive failures to detect a controller that
+// needs to be initialized again.
+func (d *Dev) tx(w []byte) error {
+	err := d.c.Tx(w, nil)
+	if err == nil {
+		d.failures = 0
+		return nil
+	}
+	d.failures++
+	if d.reinitAfter > 0 && d.failures >= d.reinitAfter {
+		d.needInit = true
+	}
+	return err
+}
+
+// pack9Bits packs each byte of c as a 9 bits word prefixed with the D/C bit,
+// MSB first. The last byte is padded with zeros.
+func pack9Bits(data bool, c []byte) []byte {
+	out := make([]byte, (len(c)*9+7)/8)
+	bit := 0
+	for _, b := range c {
+		w := uint16(b)
+		if data {
+			w |= 0x100
+		}
+		for i := 8; i >= 0; i-- {
+			if w&(1<<uint(i)) != 0 {
+				out[bit/8] |= 0x80 >> uint(bit%8)
+			}
+			bit++
+		}
 	}
-	return d.c.Tx(append([]byte{i2cCmd}, c...), nil)
+	return out
 }
 
 const (
@@ -548,4 +888,11 @@ const (
 	i2cData = 0x40 // I²C transaction has stream of data bytes
 )
 
+// The low bits of the status byte read over I²C identify the controller.
+const (
+	statusIDMask   = 0x0F
+	statusID128x32 = 0x03
+	statusID128x64 = 0x06
+)
+
 var _ display.Drawer = &Dev{}
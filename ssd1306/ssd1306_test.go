@@ -9,15 +9,19 @@ import (
 	"errors"
 	"image"
 	"image/color"
+	"image/draw"
 	"testing"
 
+	"periph.io/x/conn/v3"
 	"periph.io/x/conn/v3/conntest"
 	"periph.io/x/conn/v3/gpio"
 	"periph.io/x/conn/v3/gpio/gpiotest"
+	"periph.io/x/conn/v3/i2c"
 	"periph.io/x/conn/v3/i2c/i2ctest"
 	"periph.io/x/conn/v3/physic"
 	"periph.io/x/conn/v3/spi"
 	"periph.io/x/conn/v3/spi/spitest"
+	"periph.io/x/devices/v3/devicetest"
 	"periph.io/x/devices/v3/ssd1306/image1bit"
 )
 
@@ -66,6 +70,61 @@ func TestI2C_String(t *testing.T) {
 	}
 }
 
+func TestScan(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: I2CAddr, R: []byte{0x43}},
+			// A SH1106.
+			{Addr: I2CAddrAlt, R: []byte{0x08}},
+		},
+	}
+	if addrs := Scan(&bus); len(addrs) != 1 || addrs[0] != I2CAddr {
+		t.Fatal(addrs)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewI2CAll(t *testing.T) {
+	ops := []i2ctest.IO{
+		{Addr: I2CAddr, R: []byte{0x06}},
+		{Addr: I2CAddrAlt, R: []byte{0x46}},
+		{Addr: I2CAddr, W: initCmdI2C()},
+		{Addr: I2CAddrAlt, W: initCmdI2C()},
+	}
+	for page := 0; page < 8; page++ {
+		ops = append(ops,
+			i2ctest.IO{Addr: I2CAddrAlt, W: []byte{0x00, 0xB0 | byte(page), 0x00, 0x10}},
+			i2ctest.IO{Addr: I2CAddrAlt, W: append([]byte{i2cData}, bytes.Repeat([]byte{0xFF}, 128)...)})
+	}
+	bus := i2ctest.Playback{Ops: ops}
+	devs, err := NewI2CAll(&bus, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(devs) != 2 {
+		t.Fatal(devs)
+	}
+	if s := devs[1].String(); s != "ssd1360.Dev{playback(61), (128,64)}" {
+		t.Fatal(s)
+	}
+	// Only the second display is drawn.
+	if err := devs[1].Draw(devs[1].Bounds(), &image.Uniform{image1bit.On}, image.Point{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewI2CAll_none(t *testing.T) {
+	bus := i2ctest.Playback{DontPanic: true}
+	if devs, err := NewI2CAll(&bus, &DefaultOpts); devs != nil || err == nil {
+		t.Fatal(devs, err)
+	}
+}
+
 func TestI2C_Draw_VerticalLSD_fast(t *testing.T) {
 	// Exercise the fast path.
 	buf := make([]byte, 129)
@@ -120,6 +179,27 @@ func TestI2C_Draw_VerticalLSD_fast(t *testing.T) {
 	}
 }
 
+func TestI2C_Draw_golden(t *testing.T) {
+	bus := i2ctest.Record{}
+	dev, err := NewI2C(&bus, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A frame, a filled rectangle and a gray checkboard, which is converted to
+	// 1 bit.
+	img := image.NewGray(dev.Bounds())
+	draw.Draw(img, dev.Bounds(), &image.Uniform{color.White}, image.Point{}, draw.Src)
+	draw.Draw(img, dev.Bounds().Inset(1), &image.Uniform{color.Black}, image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(8, 8, 40, 24), &image.Uniform{color.White}, image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(64, 16, 120, 56), makeGrayCheckboard(dev.Bounds()), image.Point{}, draw.Src)
+	if err := dev.Draw(dev.Bounds(), img, image.Point{}); err != nil {
+		t.Fatal(err)
+	}
+	// The controller RAM has the same layout as image1bit.VerticalLSB.
+	got := &image1bit.VerticalLSB{Pix: dev.buffer, Stride: 128, Rect: dev.Bounds()}
+	devicetest.Golden(t, "testdata/draw.png", got)
+}
+
 func TestI2C_Halt_Write(t *testing.T) {
 	// Exercise the fast path.
 	buf := make([]byte, 129)
@@ -311,6 +391,44 @@ func TestI2C_DrawGray(t *testing.T) {
 	}
 }
 
+func TestI2C_Draw_dirty(t *testing.T) {
+	pix := make([]byte, 1024)
+	pix[0] = 0xFF
+	rect := make([]byte, 8)
+	for i := range rect {
+		rect[i] = 0xFF
+	}
+	ops := []i2ctest.IO{{Addr: 0x3c, W: initCmdI2C()}}
+	for page := 0; page < 8; page++ {
+		ops = append(ops,
+			i2ctest.IO{Addr: 0x3c, W: []byte{0x00, 0xB0 | byte(page), 0x00, 0x10}},
+			i2ctest.IO{Addr: 0x3c, W: append([]byte{i2cData}, pix[page*128:(page+1)*128]...)})
+	}
+	// Only the drawn area is compared and sent, even if the rest of the
+	// double buffer differs from what was written.
+	ops = append(ops,
+		i2ctest.IO{Addr: 0x3c, W: []byte{0x00, 0xB1, 0x08, 0x10}},
+		i2ctest.IO{Addr: 0x3c, W: append([]byte{i2cData}, rect...)})
+	bus := i2ctest.Playback{Ops: ops}
+	dev, err := NewI2C(&bus, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dev.Write(pix); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.Draw(image.Rect(8, 8, 16, 16), &image.Uniform{image1bit.On}, image.Point{}); err != nil {
+		t.Fatal(err)
+	}
+	// Outside of the display.
+	if err := dev.Draw(image.Rect(200, 8, 216, 16), &image.Uniform{image1bit.On}, image.Point{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestI2C_Scroll(t *testing.T) {
 	bus := i2ctest.Playback{
 		Ops: []i2ctest.IO{
@@ -350,6 +468,103 @@ func TestI2C_Scroll(t *testing.T) {
 	}
 }
 
+func TestI2C_ScrollRegion(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x3c, W: initCmdI2C()},
+			// ScrollRegion Left, same as Scroll.
+			{Addr: 0x3c, W: []byte{0x0, 0x27, 0x0, 0x1, 0x6, 0x7, 0x0, 0xff, 0x2f}},
+			// ScrollRegion UpLeft, keeping the 8 lines at the top fixed.
+			{Addr: 0x3c, W: []byte{0x0, 0xa3, 0x8, 0x38, 0x2a, 0x0, 0x1, 0x6, 0x7, 0x1, 0x2f}},
+			// Scroll UpRight restores the whole vertical scroll area.
+			{Addr: 0x3c, W: []byte{0x0, 0xa3, 0x0, 0x40, 0x29, 0x0, 0x0, 0x6, 0x7, 0x1, 0x2f}},
+			// Scroll UpRight.
+			{Addr: 0x3c, W: []byte{0x0, 0x29, 0x0, 0x0, 0x6, 0x7, 0x1, 0x2f}},
+		},
+	}
+	dev, err := NewI2C(&bus, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dev.ScrollRegion(UpLeft, FrameRate25, 4, 64) == nil {
+		t.Fatal("start not page aligned")
+	}
+	if dev.ScrollRegion(UpLeft, FrameRate25, 8, 60) == nil {
+		t.Fatal("end not page aligned")
+	}
+	if dev.ScrollRegion(UpLeft, FrameRate25, 8, 8) == nil {
+		t.Fatal("empty region")
+	}
+	if err := dev.ScrollRegion(Left, FrameRate25, 8, -1); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.ScrollRegion(UpLeft, FrameRate25, 8, -1); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.Scroll(UpRight, FrameRate25, 0, -1); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.Scroll(UpRight, FrameRate25, 0, -1); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestI2C_Reinit(t *testing.T) {
+	opts := Opts{W: 16, H: 8, ReinitAfter: 2}
+	initCmd := append([]byte{i2cCmd}, getInitCmd(&opts)...)
+	pix := make([]byte, 16)
+	pix[3] = 0xFF
+	data := append([]byte{i2cData}, pix...)
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Startup initialization.
+			{Addr: 0x3c, W: initCmd},
+			// Automatic reinitialization after 2 failures, then full redraw.
+			{Addr: 0x3c, W: initCmd},
+			{Addr: 0x3c, W: []byte{i2cCmd, 0xB0, 0x00, 0x10}},
+			{Addr: 0x3c, W: data},
+			// Reinit().
+			{Addr: 0x3c, W: initCmd},
+			{Addr: 0x3c, W: []byte{i2cCmd, 0xB0, 0x00, 0x10}},
+			{Addr: 0x3c, W: data},
+		},
+	}
+	c := &flakyConn{Conn: &i2c.Dev{Bus: &bus, Addr: 0x3c}}
+	dev, err := newDev(c, &opts, false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The first frame is lost.
+	c.fail = 2
+	if _, err := dev.Write(make([]byte, 16)); err == nil {
+		t.Fatal("expected failure")
+	}
+	if dev.needInit {
+		t.Fatal("a single failure must not trigger a reinitialization")
+	}
+	if _, err := dev.Write(pix); err == nil {
+		t.Fatal("expected failure")
+	}
+	if !dev.needInit {
+		t.Fatal("expected reinitialization")
+	}
+	if _, err := dev.Write(pix); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.Reinit(); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewI2C(&bus, &Opts{W: 128, H: 64, ReinitAfter: -1}); err == nil {
+		t.Fatal("invalid ReinitAfter")
+	}
+}
+
 func TestI2C_SetContrast(t *testing.T) {
 	bus := i2ctest.Playback{
 		Ops: []i2ctest.IO{
@@ -438,6 +653,58 @@ func TestI2C_Invert_Halt_resume(t *testing.T) {
 	}
 }
 
+func TestI2C_SetDim_Reinit(t *testing.T) {
+	opts := Opts{W: 16, H: 8}
+	initCmd := append([]byte{i2cCmd}, getInitCmd(&opts)...)
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x3c, W: initCmd},
+			// SetContrast(0x40)
+			{Addr: 0x3c, W: []byte{0x0, 0x81, 0x40}},
+			// SetDim(true)
+			{Addr: 0x3c, W: []byte{0x0, 0x81, 0x00, 0xd9, 0x22}},
+			// Invert(true)
+			{Addr: 0x3c, W: []byte{0x0, 0xa7}},
+			// Halt()
+			{Addr: 0x3c, W: []byte{0x0, 0xae}},
+			// Reinit() restores the settings, then redraws.
+			{Addr: 0x3c, W: append(initCmd, 0x81, 0x00, 0xd9, 0x22, 0xa7)},
+			{Addr: 0x3c, W: []byte{0x0, 0xb0, 0x00, 0x10}},
+			{Addr: 0x3c, W: append([]byte{i2cData}, make([]byte, 16)...)},
+			// SetDim(false) applies the contrast set while dimmed.
+			{Addr: 0x3c, W: []byte{0x0, 0x81, 0x80, 0xd9, 0xf1}},
+		},
+	}
+	dev, err := newDev(&i2c.Dev{Bus: &bus, Addr: 0x3c}, &opts, false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.SetContrast(0x40); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.SetDim(true); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.SetContrast(0x80); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.Invert(true); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.Reinit(); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.SetDim(false); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestI2C_Halt(t *testing.T) {
 	bus := i2ctest.Playback{
 		Ops: []i2ctest.IO{
@@ -482,9 +749,50 @@ func TestNewSPI_fail(t *testing.T) {
 }
 
 func TestSPI_3wire(t *testing.T) {
-	// Not supported yet.
-	if dev, err := NewSPI(&spitest.Playback{}, nil, &DefaultOpts); dev != nil || err == nil {
-		t.Fatal("SPI 3-wire is not supported")
+	port := spitest.Playback{
+		Playback: conntest.Playback{
+			Ops: []conntest.IO{
+				{W: pack9Bits(false, getInitCmd(&Opts{W: 128, H: 64}))},
+				// Page 1, column 3: 0xB1, 0x03, 0x10 as 9 bits words.
+				{W: []byte{0x58, 0x80, 0xC2, 0x00}},
+				{W: []byte{0x81, 0x00}},
+			},
+		},
+	}
+	dev, err := NewSPI(&port, nil, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := dev.String(); s != "ssd1360.Dev{playback, 3-wire, (128,64)}" {
+		t.Fatal(s)
+	}
+	dev.scrolled = false
+	pix := make([]byte, 1024)
+	pix[131] = 2
+	if n, err := dev.Write(pix); n != len(pix) || err != nil {
+		t.Fatal(n, err)
+	}
+	if err := port.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPack9Bits(t *testing.T) {
+	tests := []struct {
+		data bool
+		in   []byte
+		want []byte
+	}{
+		{false, []byte{0xAE}, []byte{0x57, 0x00}},
+		{true, []byte{0xFF}, []byte{0xFF, 0x80}},
+		// 8 words fit exactly in 9 bytes.
+		{true, []byte{0, 0, 0, 0, 0, 0, 0, 0}, []byte{0x80, 0x40, 0x20, 0x10, 0x08, 0x04, 0x02, 0x01, 0x00}},
+		{false, []byte{0x81, 0x7F}, []byte{0x40, 0x9F, 0xC0}},
+	}
+	for _, test := range tests {
+		if got := pack9Bits(test.data, test.in); !bytes.Equal(got, test.want) {
+			t.Errorf("pack9Bits(%t, %#v) = %#v, want %#v", test.data, test.in, got, test.want)
+		}
 	}
 }
 
@@ -644,6 +952,62 @@ func TestSPI_4wire_gpio_fail(t *testing.T) {
 	}
 }
 
+func TestNewI2C_offset_fail(t *testing.T) {
+	bus := i2ctest.Playback{DontPanic: true}
+	if d, err := NewI2C(&bus, &Opts{W: 72, H: 40, ColOffset: 60}); d != nil || err == nil {
+		t.Fatal(d, err)
+	}
+	if d, err := NewI2C(&bus, &Opts{W: 72, H: 40, RowOffset: 32}); d != nil || err == nil {
+		t.Fatal(d, err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestI2C_Write_72x40(t *testing.T) {
+	pix := make([]byte, 72*5)
+	pix[72+3] = 1
+	buf := append([]byte{i2cData}, pix[72:72+72]...)
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x3c, W: append([]byte{0}, getInitCmd(&Opts72x40)...)},
+			// Full redraw, starting at column 28.
+			{Addr: 0x3c, W: []byte{0x00, 0xB0, 0x0C, 0x11}},
+			{Addr: 0x3c, W: append([]byte{i2cData}, pix[:72]...)},
+			{Addr: 0x3c, W: []byte{0x00, 0xB1, 0x0C, 0x11}},
+			{Addr: 0x3c, W: buf},
+			{Addr: 0x3c, W: []byte{0x00, 0xB2, 0x0C, 0x11}},
+			{Addr: 0x3c, W: append([]byte{i2cData}, pix[:72]...)},
+			{Addr: 0x3c, W: []byte{0x00, 0xB3, 0x0C, 0x11}},
+			{Addr: 0x3c, W: append([]byte{i2cData}, pix[:72]...)},
+			{Addr: 0x3c, W: []byte{0x00, 0xB4, 0x0C, 0x11}},
+			{Addr: 0x3c, W: append([]byte{i2cData}, pix[:72]...)},
+			// Differential update of column 5 of page 1: 28+5 = 0x21.
+			{Addr: 0x3c, W: []byte{0x00, 0xB1, 0x01, 0x12}},
+			{Addr: 0x3c, W: []byte{i2cData, 1}},
+		},
+	}
+	dev, err := NewI2C(&bus, &Opts72x40)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := dev.Bounds(); r != image.Rect(0, 0, 72, 40) {
+		t.Fatal(r)
+	}
+	if _, err := dev.Write(pix); err != nil {
+		t.Fatal(err)
+	}
+	next := append([]byte{}, pix...)
+	next[72+5] = 1
+	if _, err := dev.Write(next); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestInitCmd(t *testing.T) {
 	tests := []struct {
 		opts         *Opts
@@ -653,6 +1017,9 @@ func TestInitCmd(t *testing.T) {
 		{opts: &Opts{W: 128, H: 64, Sequential: true}, wantSubslice: []byte{0xDA, 0x02}},
 		{opts: &Opts{W: 128, H: 64, SwapTopBottom: true}, wantSubslice: []byte{0xDA, 0x32}},
 		{opts: &Opts{W: 128, H: 64, Sequential: true, SwapTopBottom: true}, wantSubslice: []byte{0xDA, 0x22}},
+		{opts: &Opts72x40, wantSubslice: []byte{0xA8, 39, 0x20, 0x00, 0x21, 28, 99, 0x22, 0, 4}},
+		{opts: &Opts64x48, wantSubslice: []byte{0xA8, 47, 0x20, 0x00, 0x21, 32, 95, 0x22, 0, 5}},
+		{opts: &Opts{W: 128, H: 32, RowOffset: 16}, wantSubslice: []byte{0xD3, 16}},
 	}
 
 	for _, test := range tests {
@@ -665,6 +1032,20 @@ func TestInitCmd(t *testing.T) {
 
 //
 
+// flakyConn fails the next fail transactions.
+type flakyConn struct {
+	conn.Conn
+	fail int
+}
+
+func (f *flakyConn) Tx(w, r []byte) error {
+	if f.fail > 0 {
+		f.fail--
+		return errors.New("glitch")
+	}
+	return f.Conn.Tx(w, r)
+}
+
 func initCmdI2C() []byte {
 	return append([]byte{0}, getInitCmd(&Opts{W: 128, H: 64, MirrorVertical: false, MirrorHorizontal: false})...)
 }
@@ -722,3 +1103,108 @@ func (f *failPin) Out(l gpio.Level) error {
 	}
 	return nil
 }
+
+func TestI2C_Draw_edges(t *testing.T) {
+	img := image1bit.NewVerticalLSB(image.Rect(0, 0, 128, 64))
+	ops := []i2ctest.IO{{Addr: 0x3c, W: initCmdI2C()}}
+	for page := 0; page < 8; page++ {
+		ops = append(ops,
+			i2ctest.IO{Addr: 0x3c, W: []byte{0x00, 0xB0 | byte(page), 0x00, 0x10}},
+			i2ctest.IO{Addr: 0x3c, W: append([]byte{i2cData}, make([]byte, 128)...)})
+	}
+	// Changes on the first and last columns are sent full width.
+	img.SetBit(0, 63, image1bit.On)
+	img.SetBit(127, 0, image1bit.On)
+	for page := 0; page < 8; page++ {
+		ops = append(ops,
+			i2ctest.IO{Addr: 0x3c, W: []byte{0x00, 0xB0 | byte(page), 0x00, 0x10}},
+			i2ctest.IO{Addr: 0x3c, W: append([]byte{i2cData}, img.Pix[page*128:(page+1)*128]...)})
+	}
+	ops = append(ops,
+		i2ctest.IO{Addr: 0x3c, W: []byte{0x00, 0xB2, 0x05, 0x12}},
+		i2ctest.IO{Addr: 0x3c, W: []byte{i2cData, 0x10}})
+	bus := i2ctest.Playback{Ops: ops}
+	dev, err := NewI2C(&bus, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dev.Write(make([]byte, 1024)); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.Draw(dev.Bounds(), img, image.Point{}); err != nil {
+		t.Fatal(err)
+	}
+	// A change in the middle.
+	img.SetBit(37, 20, image1bit.On)
+	if err := dev.Draw(dev.Bounds(), img, image.Point{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func BenchmarkDraw_Unchanged(b *testing.B) {
+	dev := benchDev(b)
+	img := image1bit.NewVerticalLSB(dev.Bounds())
+	copy(img.Pix, grayCheckboard())
+	if err := dev.Draw(dev.Bounds(), img, image.Point{}); err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := dev.Draw(dev.Bounds(), img, image.Point{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDraw_Pixel(b *testing.B) {
+	dev := benchDev(b)
+	img := image1bit.NewVerticalLSB(dev.Bounds())
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		img.SetBit(64, 32, image1bit.Bit(i&1 == 0))
+		if err := dev.Draw(dev.Bounds(), img, image.Point{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDraw_Rect(b *testing.B) {
+	dev := benchDev(b)
+	r := image.Rect(60, 28, 68, 36)
+	on := &image.Uniform{image1bit.On}
+	off := &image.Uniform{image1bit.Off}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		src := on
+		if i&1 != 0 {
+			src = off
+		}
+		if err := dev.Draw(r, src, image.Point{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchDev(b *testing.B) *Dev {
+	dev, err := newDev(&conntest.Discard{}, &DefaultOpts, true, &gpiotest.Pin{})
+	if err != nil {
+		b.Fatal(err)
+	}
+	return dev
+}
+
+func BenchmarkDraw_Corners(b *testing.B) {
+	dev := benchDev(b)
+	img := image1bit.NewVerticalLSB(dev.Bounds())
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		img.SetBit(0, 63, image1bit.Bit(i&1 == 0))
+		img.SetBit(127, 0, image1bit.Bit(i&1 == 0))
+		if err := dev.Draw(dev.Bounds(), img, image.Point{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
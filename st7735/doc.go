@@ -0,0 +1,20 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package st7735 controls a small color TFT LCD via a ST7735R controller on a
+// 4-wire SPI bus.
+//
+// The controller drives panels up to 132x162, like the common 1.8" 128x160
+// ones. The panels are connected to a part of the controller RAM that varies
+// between manufacturers, often told apart by the color of the tab of their
+// protective film; their position is set with Opts.XOffset and Opts.YOffset.
+//
+// The driver keeps a copy of the image in RGB565 and only sends the area
+// passed to Draw, split in transfers no larger than what the SPI port
+// supports. The rotation is done by the controller.
+//
+// # Datasheet
+//
+// https://www.displayfuture.com/Display/datasheet/controller/ST7735.pdf
+package st7735
@@ -0,0 +1,205 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package st7735
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"periph.io/x/conn/v3/display"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/spi"
+	"periph.io/x/devices/v3/internal/mipidcs"
+)
+
+// Rotation is the rotation of the image relative to the native orientation of
+// the panel, done by the controller.
+type Rotation uint8
+
+// Rotations, clockwise.
+const (
+	Rotate0 Rotation = iota
+	Rotate90
+	Rotate180
+	Rotate270
+)
+
+// DefaultFrequency is the SPI clock used when Opts.Frequency is 0.
+const DefaultFrequency = 15 * physic.MegaHertz
+
+// Opts defines the options for the device.
+type Opts struct {
+	// Width and Height are the size of the panel in its native orientation.
+	Width  int
+	Height int
+	// XOffset and YOffset are the position of the panel in the 132x162
+	// controller RAM, in its native orientation.
+	XOffset int
+	YOffset int
+	// Rotation of the image.
+	Rotation Rotation
+	// Invert inverts the colors.
+	Invert bool
+	// BGR swaps the red and blue channels, for panels wired this way.
+	BGR bool
+	// Frequency of the SPI clock, DefaultFrequency if 0. The controller is
+	// specified up to 15MHz.
+	Frequency physic.Frequency
+	// ChunkSize is the largest SPI transfer. If 0, the limit of the SPI
+	// connection is used, or 4096 bytes.
+	ChunkSize int
+}
+
+// Opts128x160 is the options for the 1.8" 128x160 panels with a green tab
+// on their protective film, the most common ones.
+var Opts128x160 = Opts{
+	Width:   128,
+	Height:  160,
+	XOffset: 2,
+	YOffset: 1,
+	BGR:     true,
+}
+
+// Opts80x160 is the options for the 0.96" 80x160 panels.
+var Opts80x160 = Opts{
+	Width:   80,
+	Height:  160,
+	XOffset: 26,
+	YOffset: 1,
+	Invert:  true,
+}
+
+// New opens a handle to a ST7735 controller on a 4-wire SPI port and
+// initializes it.
+//
+// dc is the data/command pin. rst is the reset pin, nil if not connected.
+func New(p spi.Port, dc, rst gpio.PinOut, opts *Opts) (*Dev, error) {
+	f := opts.Frequency
+	if f == 0 {
+		f = DefaultFrequency
+	}
+	c, err := p.Connect(f, spi.Mode0, 8)
+	if err != nil {
+		return nil, fmt.Errorf("st7735: %v", err)
+	}
+	d, err := mipidcs.New(c, dc, rst, &mipidcs.Opts{
+		Width:       opts.Width,
+		Height:      opts.Height,
+		XOffset:     opts.XOffset,
+		YOffset:     opts.YOffset,
+		RAMWidth:    132,
+		RAMHeight:   162,
+		Rotations:   rotations,
+		Rotation:    int(opts.Rotation),
+		Invert:      opts.Invert,
+		BGR:         opts.BGR,
+		PixelFormat: mipidcs.FormatDBI16Bits,
+		Init:        initCmds,
+		MaxTxSize:   opts.ChunkSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("st7735: %v", err)
+	}
+	return &Dev{d: d}, nil
+}
+
+// Dev is an open handle to the display controller.
+type Dev struct {
+	d *mipidcs.Display
+}
+
+func (d *Dev) String() string {
+	return fmt.Sprintf("st7735.Dev{%s}", d.d)
+}
+
+// ColorModel implements display.Drawer.
+//
+// It converts the colors to RGB565.
+func (d *Dev) ColorModel() color.Model {
+	return mipidcs.RGB565Model
+}
+
+// Bounds implements display.Drawer.
+func (d *Dev) Bounds() image.Rectangle {
+	return d.d.Bounds()
+}
+
+// Draw implements display.Drawer.
+//
+// Only the area r is sent to the controller.
+func (d *Dev) Draw(r image.Rectangle, src image.Image, sp image.Point) error {
+	return wrap(d.d.Draw(r, src, sp))
+}
+
+// SetRotation rotates the image. The content of the display is lost; the
+// whole image must be drawn again.
+func (d *Dev) SetRotation(r Rotation) error {
+	return wrap(d.d.SetRotation(int(r)))
+}
+
+// Invert inverts the colors, relative to the colors set by Opts.Invert.
+func (d *Dev) Invert(on bool) error {
+	return wrap(d.d.Invert(on))
+}
+
+// Halt turns the display off and puts the controller in sleep mode. The next
+// Draw wakes it up.
+func (d *Dev) Halt() error {
+	return wrap(d.d.Halt())
+}
+
+//
+
+// Commands
+const (
+	frameRateNormal  byte = 0xB1
+	frameRateIdle    byte = 0xB2
+	frameRatePartial byte = 0xB3
+	inversionControl byte = 0xB4
+	powerControl1    byte = 0xC0
+	powerControl2    byte = 0xC1
+	powerControl3    byte = 0xC2
+	powerControl4    byte = 0xC3
+	powerControl5    byte = 0xC4
+	vcomControl1     byte = 0xC5
+	positiveGamma    byte = 0xE0
+	negativeGamma    byte = 0xE1
+)
+
+// rotations are the mipidcs.MemoryAccessControl flags of each rotation.
+var rotations = [...]byte{
+	Rotate0:   mipidcs.MX | mipidcs.MY,
+	Rotate90:  mipidcs.MY | mipidcs.MV,
+	Rotate180: 0,
+	Rotate270: mipidcs.MX | mipidcs.MV,
+}
+
+// initCmds configures the controller, before the common commands sent by
+// mipidcs.
+var initCmds = []mipidcs.Cmd{
+	{Cmd: frameRateNormal, Data: []byte{0x01, 0x2C, 0x2D}},
+	{Cmd: frameRateIdle, Data: []byte{0x01, 0x2C, 0x2D}},
+	{Cmd: frameRatePartial, Data: []byte{0x01, 0x2C, 0x2D, 0x01, 0x2C, 0x2D}},
+	{Cmd: inversionControl, Data: []byte{0x07}},
+	{Cmd: powerControl1, Data: []byte{0xA2, 0x02, 0x84}},
+	{Cmd: powerControl2, Data: []byte{0xC5}},
+	{Cmd: powerControl3, Data: []byte{0x0A, 0x00}},
+	{Cmd: powerControl4, Data: []byte{0x8A, 0x2A}},
+	{Cmd: powerControl5, Data: []byte{0x8A, 0xEE}},
+	{Cmd: vcomControl1, Data: []byte{0x0E}},
+	{Cmd: positiveGamma, Data: []byte{0x02, 0x1C, 0x07, 0x12, 0x37, 0x32, 0x29, 0x2D, 0x29, 0x25, 0x2B, 0x39, 0x00, 0x01, 0x03, 0x10}},
+	{Cmd: negativeGamma, Data: []byte{0x03, 0x1D, 0x07, 0x06, 0x2E, 0x2C, 0x29, 0x2D, 0x2E, 0x2E, 0x37, 0x3F, 0x00, 0x00, 0x02, 0x10}},
+}
+
+func wrap(err error) error {
+	if err != nil {
+		return fmt.Errorf("st7735: %v", err)
+	}
+	return nil
+}
+
+var _ display.Drawer = &Dev{}
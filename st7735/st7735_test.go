@@ -0,0 +1,104 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package st7735
+
+import (
+	"image"
+	"image/color"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"periph.io/x/devices/v3/devicetest"
+	"periph.io/x/devices/v3/internal/mipidcs"
+)
+
+type record = devicetest.Record
+
+func newDev(t *testing.T, opts *Opts) (*Dev, *devicetest.CommandConn) {
+	mipidcs.Sleep = func(time.Duration) {}
+	c := devicetest.NewCommandConn()
+	d, err := New(c, &c.DC, nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d, c
+}
+
+func TestNew(t *testing.T) {
+	d, c := newDev(t, &Opts80x160)
+	want := []record{{Cmd: mipidcs.SoftReset}}
+	for _, cmd := range initCmds {
+		want = append(want, record{Cmd: cmd.Cmd, Data: cmd.Data})
+	}
+	want = append(want,
+		record{Cmd: mipidcs.MemoryAccessControl, Data: []byte{0xC0}},
+		record{Cmd: mipidcs.PixelFormat, Data: []byte{0x05}},
+		record{Cmd: mipidcs.InvertOn},
+		record{Cmd: mipidcs.SleepOut},
+		record{Cmd: mipidcs.NormalDisplayOn},
+		record{Cmd: mipidcs.DisplayOn},
+	)
+	if diff := cmp.Diff(c.Records, want, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("New() difference (-got +want):\n%s", diff)
+	}
+	if s := d.String(); s != "st7735.Dev{devicetest.CommandConn, DC(0), Width: 80, Height: 160}" {
+		t.Errorf("String() = %q", s)
+	}
+
+	for _, opts := range []Opts{
+		{Width: 0, Height: 160},
+		{Width: 80, Height: 160, XOffset: 53},
+		{Width: 80, Height: 160, Rotation: 4},
+	} {
+		if _, err := New(c, &c.DC, nil, &opts); err == nil {
+			t.Errorf("New(%+v) should fail", opts)
+		}
+	}
+}
+
+func TestRotation(t *testing.T) {
+	for _, tc := range []struct {
+		rotation   Rotation
+		want0xC0   byte
+		wantBounds image.Rectangle
+	}{
+		{Rotate0, 0xC0, image.Rect(0, 0, 80, 160)},
+		{Rotate90, 0xA0, image.Rect(0, 0, 160, 80)},
+		{Rotate180, 0x00, image.Rect(0, 0, 80, 160)},
+		{Rotate270, 0x60, image.Rect(0, 0, 160, 80)},
+	} {
+		d, c := newDev(t, &Opts80x160)
+		c.Reset()
+		if err := d.SetRotation(tc.rotation); err != nil {
+			t.Fatal(err)
+		}
+		if diff := c.Diff([]record{{Cmd: mipidcs.MemoryAccessControl, Data: []byte{tc.want0xC0}}}); diff != "" {
+			t.Errorf("SetRotation(%d) difference (-got +want):\n%s", tc.rotation, diff)
+		}
+		if b := d.Bounds(); b != tc.wantBounds {
+			t.Errorf("SetRotation(%d) bounds = %v, want %v", tc.rotation, b, tc.wantBounds)
+		}
+	}
+}
+
+func TestDraw(t *testing.T) {
+	opts := Opts80x160
+	opts.Rotation = Rotate90
+	d, c := newDev(t, &opts)
+	c.Reset()
+	if err := d.Draw(image.Rect(0, 0, 1, 1), &image.Uniform{color.RGBA{R: 0xFF, A: 0xFF}}, image.Point{}); err != nil {
+		t.Fatal(err)
+	}
+	want := []record{
+		{Cmd: mipidcs.ColumnAddressSet, Data: []byte{0, 1, 0, 1}},
+		{Cmd: mipidcs.PageAddressSet, Data: []byte{0, 26, 0, 26}},
+		{Cmd: mipidcs.MemoryWrite, Data: []byte{0xF8, 0x00}},
+	}
+	if diff := c.Diff(want); diff != "" {
+		t.Errorf("Draw() difference (-got +want):\n%s", diff)
+	}
+}
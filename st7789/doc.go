@@ -0,0 +1,19 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package st7789 controls a color TFT LCD via a ST7789 controller on a 4-wire
+// SPI bus.
+//
+// The controller drives panels up to 240x320. The smaller panels, like the
+// 135x240 ones, are connected to a part of the controller RAM; their position
+// is set with Opts.XOffset and Opts.YOffset.
+//
+// The driver keeps a copy of the image in RGB565 and only sends the area
+// passed to Draw, split in transfers no larger than what the SPI port
+// supports. The rotation is done by the controller.
+//
+// # Datasheet
+//
+// https://www.newhavendisplay.com/appnotes/datasheets/LCDs/ST7789V.pdf
+package st7789
@@ -0,0 +1,49 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package st7789_test
+
+import (
+	"image"
+	"image/color"
+	"log"
+
+	"periph.io/x/conn/v3/gpio/gpioreg"
+	"periph.io/x/conn/v3/spi/spireg"
+	"periph.io/x/devices/v3/st7789"
+	"periph.io/x/host/v3"
+)
+
+func Example() {
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Use spireg SPI port registry to find the first available SPI bus.
+	p, err := spireg.Open("")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer p.Close()
+
+	dc := gpioreg.ByName("GPIO25")
+	rst := gpioreg.ByName("GPIO27")
+
+	opts := st7789.Opts240x240
+	opts.Rotation = st7789.Rotate90
+	dev, err := st7789.New(p, dc, rst, &opts)
+	if err != nil {
+		log.Fatalf("failed to initialize st7789: %v", err)
+	}
+
+	// Fill the display in blue, then draw a red square in the middle.
+	if err := dev.Draw(dev.Bounds(), &image.Uniform{color.RGBA{B: 0xFF, A: 0xFF}}, image.Point{}); err != nil {
+		log.Fatal(err)
+	}
+	r := image.Rect(100, 100, 140, 140)
+	if err := dev.Draw(r, &image.Uniform{color.RGBA{R: 0xFF, A: 0xFF}}, image.Point{}); err != nil {
+		log.Fatal(err)
+	}
+}
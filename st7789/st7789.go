@@ -0,0 +1,182 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package st7789
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"periph.io/x/conn/v3/display"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/spi"
+	"periph.io/x/devices/v3/internal/mipidcs"
+)
+
+// Rotation is the rotation of the image relative to the native orientation of
+// the panel, done by the controller.
+type Rotation uint8
+
+// Rotations, clockwise.
+const (
+	Rotate0 Rotation = iota
+	Rotate90
+	Rotate180
+	Rotate270
+)
+
+// DefaultFrequency is the SPI clock used when Opts.Frequency is 0.
+const DefaultFrequency = 32 * physic.MegaHertz
+
+// Opts defines the options for the device.
+type Opts struct {
+	// Width and Height are the size of the panel in its native orientation.
+	Width  int
+	Height int
+	// XOffset and YOffset are the position of the panel in the 240x320
+	// controller RAM, in its native orientation.
+	XOffset int
+	YOffset int
+	// Rotation of the image.
+	Rotation Rotation
+	// Invert inverts the colors. Most ST7789 panels need it.
+	Invert bool
+	// BGR swaps the red and blue channels, for panels wired this way.
+	BGR bool
+	// Frequency of the SPI clock, DefaultFrequency if 0. The controller is
+	// specified up to 62.5MHz.
+	Frequency physic.Frequency
+	// ChunkSize is the largest SPI transfer. If 0, the limit of the SPI
+	// connection is used, or 4096 bytes.
+	ChunkSize int
+}
+
+// Opts240x240 is the options for the common 1.3" and 1.54" 240x240 panels.
+var Opts240x240 = Opts{
+	Width:  240,
+	Height: 240,
+	Invert: true,
+}
+
+// Opts240x320 is the options for the 2" 240x320 panels.
+var Opts240x320 = Opts{
+	Width:  240,
+	Height: 320,
+	Invert: true,
+}
+
+// Opts135x240 is the options for the 1.14" 135x240 panels.
+var Opts135x240 = Opts{
+	Width:   135,
+	Height:  240,
+	XOffset: 52,
+	YOffset: 40,
+	Invert:  true,
+}
+
+// New opens a handle to a ST7789 controller on a 4-wire SPI port and
+// initializes it.
+//
+// dc is the data/command pin. rst is the reset pin, nil if not connected.
+//
+// SPI mode 3 is used, which also works with the modules without a CS pin.
+func New(p spi.Port, dc, rst gpio.PinOut, opts *Opts) (*Dev, error) {
+	f := opts.Frequency
+	if f == 0 {
+		f = DefaultFrequency
+	}
+	c, err := p.Connect(f, spi.Mode3, 8)
+	if err != nil {
+		return nil, fmt.Errorf("st7789: %v", err)
+	}
+	d, err := mipidcs.New(c, dc, rst, &mipidcs.Opts{
+		Width:       opts.Width,
+		Height:      opts.Height,
+		XOffset:     opts.XOffset,
+		YOffset:     opts.YOffset,
+		RAMWidth:    240,
+		RAMHeight:   320,
+		Rotations:   rotations,
+		Rotation:    int(opts.Rotation),
+		Invert:      opts.Invert,
+		BGR:         opts.BGR,
+		PixelFormat: mipidcs.FormatDPI16Bits | mipidcs.FormatDBI16Bits,
+		Init:        initCmds,
+		MaxTxSize:   opts.ChunkSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("st7789: %v", err)
+	}
+	return &Dev{d: d}, nil
+}
+
+// Dev is an open handle to the display controller.
+type Dev struct {
+	d *mipidcs.Display
+}
+
+func (d *Dev) String() string {
+	return fmt.Sprintf("st7789.Dev{%s}", d.d)
+}
+
+// ColorModel implements display.Drawer.
+//
+// It converts the colors to RGB565.
+func (d *Dev) ColorModel() color.Model {
+	return mipidcs.RGB565Model
+}
+
+// Bounds implements display.Drawer.
+func (d *Dev) Bounds() image.Rectangle {
+	return d.d.Bounds()
+}
+
+// Draw implements display.Drawer.
+//
+// Only the area r is sent to the controller.
+func (d *Dev) Draw(r image.Rectangle, src image.Image, sp image.Point) error {
+	return wrap(d.d.Draw(r, src, sp))
+}
+
+// SetRotation rotates the image. The content of the display is lost; the
+// whole image must be drawn again.
+func (d *Dev) SetRotation(r Rotation) error {
+	return wrap(d.d.SetRotation(int(r)))
+}
+
+// Invert inverts the colors, relative to the colors set by Opts.Invert.
+func (d *Dev) Invert(on bool) error {
+	return wrap(d.d.Invert(on))
+}
+
+// Halt turns the display off and puts the controller in sleep mode. The next
+// Draw wakes it up.
+func (d *Dev) Halt() error {
+	return wrap(d.d.Halt())
+}
+
+//
+
+// rotations are the mipidcs.MemoryAccessControl flags of each rotation.
+var rotations = [...]byte{
+	Rotate0:   0,
+	Rotate90:  mipidcs.MX | mipidcs.MV,
+	Rotate180: mipidcs.MX | mipidcs.MY,
+	Rotate270: mipidcs.MY | mipidcs.MV,
+}
+
+// initCmds is empty, the controller needs no other command than the common
+// ones sent by mipidcs.
+var initCmds []mipidcs.Cmd
+
+func wrap(err error) error {
+	if err != nil {
+		return fmt.Errorf("st7789: %v", err)
+	}
+	return nil
+}
+
+var _ display.Drawer = &Dev{}
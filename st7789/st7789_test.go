@@ -0,0 +1,104 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package st7789
+
+import (
+	"image"
+	"image/color"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"periph.io/x/devices/v3/devicetest"
+	"periph.io/x/devices/v3/internal/mipidcs"
+)
+
+type record = devicetest.Record
+
+func newDev(t *testing.T, opts *Opts) (*Dev, *devicetest.CommandConn) {
+	mipidcs.Sleep = func(time.Duration) {}
+	c := devicetest.NewCommandConn()
+	d, err := New(c, &c.DC, nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d, c
+}
+
+func TestNew(t *testing.T) {
+	d, c := newDev(t, &Opts135x240)
+	want := []record{{Cmd: mipidcs.SoftReset}}
+	for _, cmd := range initCmds {
+		want = append(want, record{Cmd: cmd.Cmd, Data: cmd.Data})
+	}
+	want = append(want,
+		record{Cmd: mipidcs.MemoryAccessControl, Data: []byte{0x00}},
+		record{Cmd: mipidcs.PixelFormat, Data: []byte{0x55}},
+		record{Cmd: mipidcs.InvertOn},
+		record{Cmd: mipidcs.SleepOut},
+		record{Cmd: mipidcs.NormalDisplayOn},
+		record{Cmd: mipidcs.DisplayOn},
+	)
+	if diff := cmp.Diff(c.Records, want, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("New() difference (-got +want):\n%s", diff)
+	}
+	if s := d.String(); s != "st7789.Dev{devicetest.CommandConn, DC(0), Width: 135, Height: 240}" {
+		t.Errorf("String() = %q", s)
+	}
+
+	for _, opts := range []Opts{
+		{Width: 0, Height: 240},
+		{Width: 135, Height: 240, XOffset: 106},
+		{Width: 135, Height: 240, Rotation: 4},
+	} {
+		if _, err := New(c, &c.DC, nil, &opts); err == nil {
+			t.Errorf("New(%+v) should fail", opts)
+		}
+	}
+}
+
+func TestRotation(t *testing.T) {
+	for _, tc := range []struct {
+		rotation   Rotation
+		want0x00   byte
+		wantBounds image.Rectangle
+	}{
+		{Rotate0, 0x00, image.Rect(0, 0, 135, 240)},
+		{Rotate90, 0x60, image.Rect(0, 0, 240, 135)},
+		{Rotate180, 0xC0, image.Rect(0, 0, 135, 240)},
+		{Rotate270, 0xA0, image.Rect(0, 0, 240, 135)},
+	} {
+		d, c := newDev(t, &Opts135x240)
+		c.Reset()
+		if err := d.SetRotation(tc.rotation); err != nil {
+			t.Fatal(err)
+		}
+		if diff := c.Diff([]record{{Cmd: mipidcs.MemoryAccessControl, Data: []byte{tc.want0x00}}}); diff != "" {
+			t.Errorf("SetRotation(%d) difference (-got +want):\n%s", tc.rotation, diff)
+		}
+		if b := d.Bounds(); b != tc.wantBounds {
+			t.Errorf("SetRotation(%d) bounds = %v, want %v", tc.rotation, b, tc.wantBounds)
+		}
+	}
+}
+
+func TestDraw(t *testing.T) {
+	opts := Opts135x240
+	opts.Rotation = Rotate90
+	d, c := newDev(t, &opts)
+	c.Reset()
+	if err := d.Draw(image.Rect(0, 0, 1, 1), &image.Uniform{color.RGBA{R: 0xFF, A: 0xFF}}, image.Point{}); err != nil {
+		t.Fatal(err)
+	}
+	want := []record{
+		{Cmd: mipidcs.ColumnAddressSet, Data: []byte{0, 40, 0, 40}},
+		{Cmd: mipidcs.PageAddressSet, Data: []byte{0, 53, 0, 53}},
+		{Cmd: mipidcs.MemoryWrite, Data: []byte{0xF8, 0x00}},
+	}
+	if diff := c.Diff(want); diff != "" {
+		t.Errorf("Draw() difference (-got +want):\n%s", diff)
+	}
+}
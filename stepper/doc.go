@@ -0,0 +1,26 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package stepper defines Stepper, a common interface for stepper motor
+// controllers, so applications can switch hardware without code changes.
+//
+// Stepper is implemented by tic.Dev, a controller generating the steps on its
+// own, and by StepDir, which drives a STEP/DIR driver like the Allegro A4988
+// or the TI DRV8825 with GPIO pins.
+//
+// # Motion profile
+//
+// StepDir generates the steps in a goroutine, following a trapezoidal profile:
+// the motor starts at the starting speed, accelerates up to the maximum speed
+// and decelerates to stop on the target position. Each step is scheduled on
+// an absolute deadline, the last part of the wait being a busy loop, so that
+// the scheduler latency does not accumulate. The jitter still depends on the
+// load of the host, keep the speed below a few kHz.
+//
+// # Datasheets
+//
+// https://www.allegromicro.com/-/media/files/datasheets/a4988-datasheet.pdf
+//
+// https://www.ti.com/lit/ds/symlink/drv8825.pdf
+package stepper
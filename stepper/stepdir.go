@@ -0,0 +1,393 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package stepper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"runtime"
+	"sync"
+	"time"
+
+	"periph.io/x/conn/v3/gpio"
+)
+
+// Chip is a STEP/DIR driver model. It defines the microstep configurations.
+type Chip int
+
+const (
+	// A4988 supports 1 to 1/16 microsteps.
+	A4988 Chip = iota
+	// DRV8825 supports 1 to 1/32 microsteps.
+	DRV8825
+)
+
+func (c Chip) String() string {
+	switch c {
+	case A4988:
+		return "A4988"
+	case DRV8825:
+		return "DRV8825"
+	default:
+		return "Chip(?)"
+	}
+}
+
+// Opts holds the configuration of a StepDir.
+type Opts struct {
+	Chip Chip
+	// Step and Dir are the STEP and DIR pins. They are required.
+	Step gpio.PinOut
+	Dir  gpio.PinOut
+	// Enable is the active low ENABLE pin, nil if not connected.
+	Enable gpio.PinOut
+	// Mode are the microstep selection pins, MS1 to MS3 on the A4988 or M0 to
+	// M2 on the DRV8825, nil if they are not connected.
+	Mode [3]gpio.PinOut
+	// StartingSpeed is the speed, in microsteps per second, the motor can
+	// start and stop at without acceleration.
+	StartingSpeed uint32
+	// MaxSpeed is the maximum speed in microsteps per second. It is required.
+	MaxSpeed uint32
+	// Acceleration in microsteps per second², used to accelerate and
+	// decelerate. 0 means the motor runs at MaxSpeed right away.
+	Acceleration uint32
+}
+
+// StepDir is a Stepper driving a STEP/DIR driver chip with GPIO pins.
+//
+// A rising edge on STEP moves the motor by a microstep, forward when DIR is
+// high.
+type StepDir struct {
+	mu       sync.Mutex
+	opts     Opts
+	profile  profile
+	position int32
+	target   int32
+	// dir is the current level of DIR, as 1 or -1.
+	dir  int32
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewStepDir returns a StepDir driving the pins in opts. The current position
+// is 0 and the driver is energized.
+func NewStepDir(opts *Opts) (*StepDir, error) {
+	if opts.Step == nil || opts.Dir == nil {
+		return nil, errors.New("stepper: STEP and DIR pins are required")
+	}
+	if _, ok := microsteps[opts.Chip]; !ok {
+		return nil, errors.New("stepper: invalid chip")
+	}
+	if opts.MaxSpeed == 0 || opts.StartingSpeed > opts.MaxSpeed {
+		return nil, errors.New("stepper: invalid speeds")
+	}
+	s := &StepDir{
+		opts: *opts,
+		profile: profile{
+			start: float64(opts.StartingSpeed),
+			max:   float64(opts.MaxSpeed),
+			accel: float64(opts.Acceleration),
+		},
+		dir: 1,
+	}
+	if err := opts.Step.Out(gpio.Low); err != nil {
+		return nil, err
+	}
+	if err := opts.Dir.Out(gpio.High); err != nil {
+		return nil, err
+	}
+	if err := s.Energize(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *StepDir) String() string {
+	return fmt.Sprintf("stepper.StepDir{%s, %s, %s}", s.opts.Chip, s.opts.Step, s.opts.Dir)
+}
+
+// SetMicrostep sets the microstep mode, as the number of microsteps per full
+// step. The Mode pins must be connected and the motor stopped.
+//
+// Positions and speeds are in microsteps, they are not converted.
+func (s *StepDir) SetMicrostep(n int) error {
+	levels, ok := microsteps[s.opts.Chip][n]
+	if !ok {
+		return fmt.Errorf("stepper: %s doesn't support 1/%d microsteps", s.opts.Chip, n)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop != nil {
+		return errors.New("stepper: can't change microsteps while moving")
+	}
+	for i, p := range s.opts.Mode {
+		if p == nil {
+			return errors.New("stepper: microstep pins not connected")
+		}
+		if err := p.Out(levels[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetTargetPosition implements Stepper.
+func (s *StepDir) SetTargetPosition(position int32) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.target = position
+	if s.stop == nil && s.position != position {
+		s.stop = make(chan struct{})
+		s.done = make(chan struct{})
+		go s.run(s.stop, s.done)
+	}
+	return nil
+}
+
+// GetTargetPosition implements Stepper.
+func (s *StepDir) GetTargetPosition() (int32, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.target, nil
+}
+
+// GetCurrentPosition implements Stepper.
+func (s *StepDir) GetCurrentPosition() (int32, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.position, nil
+}
+
+// Wait waits for the motor to reach the target position or ctx to be done.
+func (s *StepDir) Wait(ctx context.Context) error {
+	s.mu.Lock()
+	done := s.done
+	s.mu.Unlock()
+	if done == nil {
+		return nil
+	}
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// HaltAndSetPosition implements Stepper.
+func (s *StepDir) HaltAndSetPosition(position int32) error {
+	s.halt()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.position = position
+	s.target = position
+	return nil
+}
+
+// Energize implements Stepper.
+//
+// It has no effect if the ENABLE pin is not connected.
+func (s *StepDir) Energize() error {
+	if s.opts.Enable == nil {
+		return nil
+	}
+	return s.opts.Enable.Out(gpio.Low)
+}
+
+// Deenergize implements Stepper.
+//
+// It has no effect if the ENABLE pin is not connected.
+func (s *StepDir) Deenergize() error {
+	if s.opts.Enable == nil {
+		return nil
+	}
+	return s.opts.Enable.Out(gpio.High)
+}
+
+// Halt implements conn.Resource.
+//
+// It stops the motor abruptly, without respecting the deceleration. The
+// target position is set to the current position.
+func (s *StepDir) Halt() error {
+	s.halt()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.target = s.position
+	return nil
+}
+
+//
+
+// microsteps maps the microstep modes to the levels of the Mode pins.
+var microsteps = map[Chip]map[int][3]gpio.Level{
+	A4988: {
+		1:  {gpio.Low, gpio.Low, gpio.Low},
+		2:  {gpio.High, gpio.Low, gpio.Low},
+		4:  {gpio.Low, gpio.High, gpio.Low},
+		8:  {gpio.High, gpio.High, gpio.Low},
+		16: {gpio.High, gpio.High, gpio.High},
+	},
+	DRV8825: {
+		1:  {gpio.Low, gpio.Low, gpio.Low},
+		2:  {gpio.High, gpio.Low, gpio.Low},
+		4:  {gpio.Low, gpio.High, gpio.Low},
+		8:  {gpio.High, gpio.High, gpio.Low},
+		16: {gpio.Low, gpio.Low, gpio.High},
+		32: {gpio.High, gpio.Low, gpio.High},
+	},
+}
+
+const (
+	// pulseWidth is the minimum STEP high and low time, 1.9µs on the DRV8825.
+	pulseWidth = 2 * time.Microsecond
+	// spinTime is the last part of the wait between steps that is spent in a
+	// busy loop, to not depend on the scheduler latency.
+	spinTime = 500 * time.Microsecond
+)
+
+// halt stops the step generation and waits for it.
+func (s *StepDir) halt() {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop = nil
+	s.mu.Unlock()
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
+
+// run generates the steps until the target position is reached or stop is
+// closed.
+func (s *StepDir) run(stop, done chan struct{}) {
+	defer close(done)
+	// Reduce the jitter by not migrating between threads.
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	v := 0.0
+	next := time.Now()
+	for {
+		s.mu.Lock()
+		var dir int32
+		var ok bool
+		v, dir, ok = s.profile.next(s.target-s.position, v, s.dir)
+		if !ok {
+			if s.stop == stop {
+				s.stop = nil
+			}
+			s.mu.Unlock()
+			return
+		}
+		s.mu.Unlock()
+
+		select {
+		case <-stop:
+			return
+		default:
+		}
+		waitUntil(next)
+		if err := s.step(dir); err != nil {
+			log.Printf("%s: failed to step: %v", s, err)
+			s.mu.Lock()
+			if s.stop == stop {
+				s.stop = nil
+			}
+			s.mu.Unlock()
+			return
+		}
+		interval := time.Duration(float64(time.Second) / v)
+		next = next.Add(interval)
+		if time.Since(next) > interval {
+			// Too late, don't try to catch up.
+			next = time.Now()
+		}
+	}
+}
+
+// step moves the motor by one microstep in dir.
+func (s *StepDir) step(dir int32) error {
+	if dir != s.dir {
+		l := gpio.High
+		if dir < 0 {
+			l = gpio.Low
+		}
+		if err := s.opts.Dir.Out(l); err != nil {
+			return err
+		}
+	}
+	if err := s.opts.Step.Out(gpio.High); err != nil {
+		return err
+	}
+	waitUntil(time.Now().Add(pulseWidth))
+	if err := s.opts.Step.Out(gpio.Low); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.dir = dir
+	s.position += dir
+	s.mu.Unlock()
+	return nil
+}
+
+// waitUntil sleeps, then spins until t.
+func waitUntil(t time.Time) {
+	if d := time.Until(t) - spinTime; d > 0 {
+		time.Sleep(d)
+	}
+	for time.Now().Before(t) {
+	}
+}
+
+// profile is a trapezoidal motion profile. Speeds are in microsteps per
+// second and the acceleration in microsteps per second².
+type profile struct {
+	start, max, accel float64
+}
+
+// next returns the speed and the direction of the next step, given the
+// remaining microsteps to the target and the speed and direction of the last
+// step. It returns false once the target is reached.
+func (p *profile) next(remaining int32, v float64, dir int32) (float64, int32, bool) {
+	if p.accel == 0 {
+		if remaining == 0 {
+			return 0, dir, false
+		}
+		return p.max, sign(remaining), true
+	}
+	// The motor can't go slower than the speed reached after one step from
+	// standstill.
+	vmin := math.Max(p.start, math.Sqrt(2*p.accel))
+	if remaining == 0 && v <= vmin {
+		return 0, dir, false
+	}
+	// Decelerate when going the wrong way or when the remaining distance is
+	// the braking distance.
+	if remaining == 0 || sign(remaining) != dir || math.Abs(float64(remaining)) <= v*v/(2*p.accel) {
+		v = math.Sqrt(math.Max(v*v-2*p.accel, 0))
+	} else {
+		v = math.Min(math.Sqrt(v*v+2*p.accel), p.max)
+	}
+	if v < vmin {
+		if remaining == 0 {
+			return 0, dir, false
+		}
+		v = math.Min(vmin, p.max)
+		dir = sign(remaining)
+	}
+	return v, dir, true
+}
+
+func sign(v int32) int32 {
+	if v < 0 {
+		return -1
+	}
+	return 1
+}
+
+var _ Stepper = &StepDir{}
@@ -0,0 +1,206 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package stepper
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpiotest"
+)
+
+// stepPin counts the rising edges.
+type stepPin struct {
+	gpiotest.Pin
+	rises int
+}
+
+func (p *stepPin) Out(l gpio.Level) error {
+	p.Lock()
+	defer p.Unlock()
+	if l && !p.L {
+		p.rises++
+	}
+	p.L = l
+	return nil
+}
+
+func (p *stepPin) count() int {
+	p.Lock()
+	defer p.Unlock()
+	return p.rises
+}
+
+// simulate runs p until the target is reached, changing the target to
+// retarget after n steps. It returns the final position and the maximum
+// speed.
+func simulate(t *testing.T, p *profile, target int32, n int, retarget int32) (int32, float64) {
+	var pos int32
+	v, dir, peak := 0.0, int32(1), 0.0
+	for i := 0; ; i++ {
+		if i == n {
+			target = retarget
+		}
+		if i > 100000 {
+			t.Fatal("runaway")
+		}
+		var ok bool
+		if v, dir, ok = p.next(target-pos, v, dir); !ok {
+			return pos, peak
+		}
+		if v > peak {
+			peak = v
+		}
+		if v < p.start && v < p.max {
+			t.Fatalf("speed %f below the starting speed", v)
+		}
+		pos += dir
+	}
+}
+
+func TestProfile(t *testing.T) {
+	p := &profile{start: 100, max: 1000, accel: 10000}
+	if pos, peak := simulate(t, p, 1000, -1, 0); pos != 1000 || peak != 1000 {
+		t.Fatal(pos, peak)
+	}
+	// Too short to reach the maximum speed.
+	if pos, peak := simulate(t, p, -20, -1, 0); pos != -20 || peak >= 1000 {
+		t.Fatal(pos, peak)
+	}
+	// Reverse while running at full speed.
+	if pos, _ := simulate(t, p, 1000, 500, -10); pos != -10 {
+		t.Fatal(pos)
+	}
+	// No starting speed, the move must not overshoot.
+	p = &profile{max: 20000, accel: 1000000}
+	if pos, _ := simulate(t, p, 100, -1, 0); pos != 100 {
+		t.Fatal(pos)
+	}
+	// No acceleration.
+	p = &profile{max: 500}
+	if pos, peak := simulate(t, p, 30, -1, 0); pos != 30 || peak != 500 {
+		t.Fatal(pos, peak)
+	}
+}
+
+func TestStepDir(t *testing.T) {
+	step := &stepPin{Pin: gpiotest.Pin{N: "STEP"}}
+	dir := &gpiotest.Pin{N: "DIR"}
+	en := &gpiotest.Pin{N: "EN", L: gpio.High}
+	if _, err := NewStepDir(&Opts{Step: step, Dir: dir}); err == nil {
+		t.Fatal("expected error without MaxSpeed")
+	}
+	s, err := NewStepDir(&Opts{Chip: DRV8825, Step: step, Dir: dir, Enable: en, MaxSpeed: 20000, Acceleration: 1000000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if str := s.String(); str != "stepper.StepDir{DRV8825, STEP(0), DIR(0)}" {
+		t.Fatal(str)
+	}
+	if en.Read() != gpio.Low {
+		t.Fatal("not energized")
+	}
+	if err := s.SetTargetPosition(100); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := s.Wait(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if p, _ := s.GetCurrentPosition(); p != 100 {
+		t.Fatal(p)
+	}
+	if n := step.count(); n != 100 {
+		t.Fatal(n)
+	}
+	if dir.Read() != gpio.High {
+		t.Fatal("DIR must be high going forward")
+	}
+
+	if err := s.SetTargetPosition(40); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Wait(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if p, _ := s.GetCurrentPosition(); p != 40 {
+		t.Fatal(p)
+	}
+	if n := step.count(); n != 160 {
+		t.Fatal(n)
+	}
+	if dir.Read() != gpio.Low {
+		t.Fatal("DIR must be low going backward")
+	}
+
+	if err := s.HaltAndSetPosition(1000); err != nil {
+		t.Fatal(err)
+	}
+	if p, _ := s.GetTargetPosition(); p != 1000 {
+		t.Fatal(p)
+	}
+	if err := s.Deenergize(); err != nil {
+		t.Fatal(err)
+	}
+	if en.Read() != gpio.High {
+		t.Fatal("not deenergized")
+	}
+}
+
+func TestStepDir_Halt(t *testing.T) {
+	step := &stepPin{}
+	s, err := NewStepDir(&Opts{Step: step, Dir: &gpiotest.Pin{}, MaxSpeed: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetTargetPosition(1000000); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if err := s.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	p, _ := s.GetCurrentPosition()
+	if p == 0 || p == 1000000 {
+		t.Fatal(p)
+	}
+	if target, _ := s.GetTargetPosition(); target != p {
+		t.Fatal(target, p)
+	}
+	if n := step.count(); n != int(p) {
+		t.Fatal(n, p)
+	}
+}
+
+func TestSetMicrostep(t *testing.T) {
+	var mode [3]*gpiotest.Pin
+	opts := Opts{Chip: A4988, Step: &gpiotest.Pin{}, Dir: &gpiotest.Pin{}, MaxSpeed: 100}
+	s, err := NewStepDir(&opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetMicrostep(16); err == nil {
+		t.Fatal("expected error without mode pins")
+	}
+	for i := range mode {
+		mode[i] = &gpiotest.Pin{}
+		opts.Mode[i] = mode[i]
+	}
+	if s, err = NewStepDir(&opts); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetMicrostep(32); err == nil {
+		t.Fatal("A4988 doesn't support 1/32")
+	}
+	if err := s.SetMicrostep(8); err != nil {
+		t.Fatal(err)
+	}
+	if mode[0].Read() != gpio.High || mode[1].Read() != gpio.High || mode[2].Read() != gpio.Low {
+		t.Fatal("unexpected mode pins")
+	}
+}
@@ -0,0 +1,27 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package stepper
+
+import "periph.io/x/conn/v3"
+
+// Stepper is a stepper motor controller moving to target positions. Positions
+// are in microsteps.
+type Stepper interface {
+	conn.Resource
+	// SetTargetPosition starts moving the motor to position. It returns
+	// without waiting for the motion to complete.
+	SetTargetPosition(position int32) error
+	// GetTargetPosition returns the position the motor is moving to.
+	GetTargetPosition() (int32, error)
+	// GetCurrentPosition returns the position of the motor.
+	GetCurrentPosition() (int32, error)
+	// HaltAndSetPosition stops the motor abruptly, without respecting the
+	// deceleration, and sets the current position.
+	HaltAndSetPosition(position int32) error
+	// Energize enables the motor driver.
+	Energize() error
+	// Deenergize disables the motor driver, the motor is free to move.
+	Deenergize() error
+}
@@ -0,0 +1,131 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package tca95xx
+
+import (
+	"errors"
+
+	"periph.io/x/conn/v3/gpio"
+)
+
+// DriveStrength is the output drive strength of a pin on a PCAL series
+// device, as a fraction of the full drive capability.
+type DriveStrength uint8
+
+const (
+	DriveQuarter       DriveStrength = 0 // 0.25x drive strength.
+	DriveHalf          DriveStrength = 1 // 0.5x drive strength.
+	DriveThreeQuarters DriveStrength = 2 // 0.75x drive strength.
+	DriveFull          DriveStrength = 3 // 1x drive strength, the power on default.
+)
+
+// AgilePin extends Pin with the "agile I/O" features of the PCAL series.
+type AgilePin interface {
+	Pin
+	// SetDriveStrength sets the output drive strength of the pin.
+	SetDriveStrength(s DriveStrength) error
+	// DriveStrength returns the output drive strength of the pin.
+	DriveStrength() (DriveStrength, error)
+	// SetInputLatch enables or disables latching of the input. When latched,
+	// a change of the input state is held until the input register is read.
+	SetInputLatch(l bool) error
+	// IsInputLatched returns true if the input is latched.
+	IsInputLatched() (bool, error)
+	// SetInterruptMasked masks or unmasks the pin from asserting the INT
+	// line. All pins are masked on power on.
+	SetInterruptMasked(m bool) error
+	// IsInterruptMasked returns true if the pin doesn't assert the INT line.
+	IsInterruptMasked() (bool, error)
+	// InterruptStatus returns true if the pin is the source of an interrupt.
+	//
+	// Reading the input register of the port clears the interrupt.
+	InterruptStatus() (bool, error)
+}
+
+// agileRegisters are the extended registers of the PCAL series for a port.
+type agileRegisters struct {
+	drive     [2]registerCache // output drive strength, 2 bits per pin
+	latch     registerCache    // input latch
+	pullEn    registerCache    // pull-up/pull-down enable
+	pullSel   registerCache    // pull-up/pull-down selection, 1 is pull-up
+	intMask   registerCache    // interrupt mask, 1 is masked
+	intStatus registerCache    // interrupt status, read only
+}
+
+type agilePortpin struct {
+	portpin
+}
+
+func (p *agilePortpin) Halt() error {
+	return p.In(gpio.Float, gpio.NoEdge)
+}
+
+func (p *agilePortpin) In(pull gpio.Pull, edge gpio.Edge) error {
+	a := p.port.agile
+	switch pull {
+	case gpio.PullDown, gpio.PullUp:
+		if err := a.pullSel.getAndSetBit(p.pinbit, pull == gpio.PullUp, true); err != nil {
+			return err
+		}
+		if err := a.pullEn.getAndSetBit(p.pinbit, true, true); err != nil {
+			return err
+		}
+	case gpio.Float:
+		if err := a.pullEn.getAndSetBit(p.pinbit, false, true); err != nil {
+			return err
+		}
+	case gpio.PullNoChange:
+	}
+	return p.portpin.In(gpio.PullNoChange, edge)
+}
+
+func (p *agilePortpin) Pull() gpio.Pull {
+	a := p.port.agile
+	if en, _ := a.pullEn.getBit(p.pinbit, true); !en {
+		return gpio.Float
+	}
+	if up, _ := a.pullSel.getBit(p.pinbit, true); up {
+		return gpio.PullUp
+	}
+	return gpio.PullDown
+}
+
+func (p *agilePortpin) DefaultPull() gpio.Pull {
+	// Pull-up resistors are selected but disabled on power on.
+	return gpio.Float
+}
+
+func (p *agilePortpin) SetDriveStrength(s DriveStrength) error {
+	if s > DriveFull {
+		return errors.New("tca95xx: invalid drive strength")
+	}
+	return p.port.agile.drive[p.pinbit/4].getAndSetBits((p.pinbit%4)*2, 0x03, uint8(s), true)
+}
+
+func (p *agilePortpin) DriveStrength() (DriveStrength, error) {
+	v, err := p.port.agile.drive[p.pinbit/4].getBits((p.pinbit%4)*2, 0x03, true)
+	return DriveStrength(v), err
+}
+
+func (p *agilePortpin) SetInputLatch(l bool) error {
+	return p.port.agile.latch.getAndSetBit(p.pinbit, l, true)
+}
+
+func (p *agilePortpin) IsInputLatched() (bool, error) {
+	return p.port.agile.latch.getBit(p.pinbit, true)
+}
+
+func (p *agilePortpin) SetInterruptMasked(m bool) error {
+	return p.port.agile.intMask.getAndSetBit(p.pinbit, m, true)
+}
+
+func (p *agilePortpin) IsInterruptMasked() (bool, error) {
+	return p.port.agile.intMask.getBit(p.pinbit, true)
+}
+
+func (p *agilePortpin) InterruptStatus() (bool, error) {
+	// Never cached, the status changes with the input.
+	return p.port.agile.intStatus.getBit(p.pinbit, false)
+}
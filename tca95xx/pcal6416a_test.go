@@ -0,0 +1,164 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package tca95xx
+
+import (
+	"testing"
+
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpioreg"
+	"periph.io/x/conn/v3/i2c/i2ctest"
+)
+
+// pcal6416aInit are the transactions issued by New for a PCAL6416A.
+func pcal6416aInit(address uint16) []i2ctest.IO {
+	return []i2ctest.IO{
+		// iodir and the pull configuration are read on creation
+		{Addr: address, W: []byte{0x06}, R: []byte{0xFF}},
+		{Addr: address, W: []byte{0x46}, R: []byte{0x00}},
+		{Addr: address, W: []byte{0x48}, R: []byte{0xFF}},
+		{Addr: address, W: []byte{0x07}, R: []byte{0xFF}},
+		{Addr: address, W: []byte{0x47}, R: []byte{0x00}},
+		{Addr: address, W: []byte{0x49}, R: []byte{0xFF}},
+	}
+}
+
+func TestPCAL6416A_pull(t *testing.T) {
+	const address uint16 = 0x20
+	scenario := &i2ctest.Playback{
+		Ops: append(pcal6416aInit(address),
+			// pull-down selected on P1_2
+			i2ctest.IO{Addr: address, W: []byte{0x49, 0xFB}},
+			// pull enabled on P1_2
+			i2ctest.IO{Addr: address, W: []byte{0x47, 0x04}},
+			// pull disabled again
+			i2ctest.IO{Addr: address, W: []byte{0x47, 0x00}},
+		),
+	}
+
+	dev, err := New(scenario, PCAL6416A, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dev.Close()
+
+	p := gpioreg.ByName("PCAL6416A_20_P1_2")
+	if p == nil {
+		t.Fatal("pin is nil")
+	}
+	if p.Pull() != gpio.Float {
+		t.Errorf("Pull() should return 'gpio.Float'")
+	}
+	if err := p.In(gpio.PullDown, gpio.NoEdge); err != nil {
+		t.Fatal(err)
+	}
+	if p.Pull() != gpio.PullDown {
+		t.Errorf("Pull() should return 'gpio.PullDown'")
+	}
+	if err := p.In(gpio.Float, gpio.NoEdge); err != nil {
+		t.Fatal(err)
+	}
+	if err := scenario.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPCAL6416A_agile(t *testing.T) {
+	const address uint16 = 0x20
+	scenario := &i2ctest.Playback{
+		Ops: append(pcal6416aInit(address),
+			// drive strength of P0_5 is set to half
+			i2ctest.IO{Addr: address, W: []byte{0x41}, R: []byte{0xFF}},
+			i2ctest.IO{Addr: address, W: []byte{0x41, 0xF7}},
+			// input latch enabled
+			i2ctest.IO{Addr: address, W: []byte{0x44}, R: []byte{0x00}},
+			i2ctest.IO{Addr: address, W: []byte{0x44, 0x20}},
+			// interrupt unmasked
+			i2ctest.IO{Addr: address, W: []byte{0x4A}, R: []byte{0xFF}},
+			i2ctest.IO{Addr: address, W: []byte{0x4A, 0xDF}},
+			// interrupt status is read
+			i2ctest.IO{Addr: address, W: []byte{0x4C}, R: []byte{0x20}},
+		),
+	}
+
+	dev, err := New(scenario, PCAL6416A, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dev.Close()
+
+	p, ok := dev.Pins[0][5].(AgilePin)
+	if !ok {
+		t.Fatal("pin must implement AgilePin")
+	}
+	if err := p.SetDriveStrength(DriveHalf); err != nil {
+		t.Fatal(err)
+	}
+	if s, err := p.DriveStrength(); s != DriveHalf || err != nil {
+		t.Errorf("DriveStrength() = %d, %v; want %d", s, err, DriveHalf)
+	}
+	if err := p.SetDriveStrength(DriveFull + 1); err == nil {
+		t.Errorf("invalid drive strength should return an error")
+	}
+	if err := p.SetInputLatch(true); err != nil {
+		t.Fatal(err)
+	}
+	if l, err := p.IsInputLatched(); !l || err != nil {
+		t.Errorf("input should be latched")
+	}
+	if err := p.SetInterruptMasked(false); err != nil {
+		t.Fatal(err)
+	}
+	if m, err := p.IsInterruptMasked(); m || err != nil {
+		t.Errorf("interrupt should be unmasked")
+	}
+	if s, err := p.InterruptStatus(); !s || err != nil {
+		t.Errorf("interrupt status should be set")
+	}
+	if err := scenario.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTCA6424A_ports(t *testing.T) {
+	const address uint16 = 0x22
+	scenario := &i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// iodir is read on creation
+			{Addr: address, W: []byte{0x0c}, R: []byte{0xFF}},
+			{Addr: address, W: []byte{0x0d}, R: []byte{0xFF}},
+			{Addr: address, W: []byte{0x0e}, R: []byte{0xFF}},
+			// P2_7 is set to output
+			{Addr: address, W: []byte{0x0e, 0x7F}},
+			{Addr: address, W: []byte{0x06}, R: []byte{0x00}},
+			{Addr: address, W: []byte{0x06, 0x80}},
+		},
+	}
+
+	dev, err := New(scenario, TCA6424A, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dev.Close()
+
+	if len(dev.Pins) != 3 || len(dev.Conns) != 3 {
+		t.Fatalf("expected 3 ports, got %d", len(dev.Pins))
+	}
+	if dev.Conns[2].String() != "TCA6424A_22_P2" {
+		t.Errorf("String() should return 'TCA6424A_22_P2'")
+	}
+	if _, ok := dev.Pins[0][0].(AgilePin); ok {
+		t.Errorf("TCA6424A pins must not implement AgilePin")
+	}
+	if err := dev.Pins[2][7].Out(gpio.High); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.Pins[0][0].In(gpio.PullUp, gpio.NoEdge); err == nil {
+		t.Errorf("PullUp should return an error")
+	}
+	if err := scenario.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -36,16 +36,24 @@ type port struct {
 	output registerCache // output control, or flipflop state if read
 	iodir  registerCache // direction
 	ipol   registerCache // polarity setting
+
+	// agile is only set on PCAL series devices.
+	agile *agileRegisters
 }
 
 func (p *port) pins(count int) []Pin {
 	result := make([]Pin, count)
 	var i uint8
 	for i = 0; i < uint8(count); i++ {
-		result[i] = &portpin{
+		pp := portpin{
 			port:   p,
 			pinbit: i,
 		}
+		if p.agile != nil {
+			result[i] = &agilePortpin{portpin: pp}
+		} else {
+			result[i] = &pp
+		}
 	}
 	return result
 }
@@ -76,3 +76,20 @@ func (r *registerCache) getBit(bit uint8, cached bool) (bool, error) {
 	v, err := r.readValue(cached)
 	return (v & (1 << bit)) != 0, err
 }
+
+// getAndSetBits replaces the bits selected by mask, shifted left by shift, with
+// value.
+func (r *registerCache) getAndSetBits(shift, mask, value uint8, cached bool) error {
+	v, err := r.readValue(cached)
+	if err != nil {
+		return err
+	}
+	v = (v &^ (mask << shift)) | ((value & mask) << shift)
+	return r.writeValue(v, cached)
+}
+
+// getBits returns the bits selected by mask, shifted left by shift.
+func (r *registerCache) getBits(shift, mask uint8, cached bool) (uint8, error) {
+	v, err := r.readValue(cached)
+	return (v >> shift) & mask, err
+}
@@ -3,14 +3,17 @@
 // that can be found in the LICENSE file.
 
 // Package tca95xx provides an interface to the Texas Instruments TCA95 series
-// of 8-bit I²C extenders.
+// of 8-bit I²C extenders, along with the pin compatible NXP PCAL series.
 //
 // The following variants are supported:
 //
 //   - PCA9536 - address: 0x41
+//   - PCAL6408A - addresses: 0x20, 0x21
+//   - PCAL6416A - addresses: 0x20, 0x21
 //   - TCA6408A - addresses: 0x20, 0x21
 //   - TCA6416 - addresses: 0x20, 0x21
 //   - TCA6416A - addresses: 0x20, 0x21
+//   - TCA6424A - addresses: 0x22, 0x23
 //   - TCA9534 - addresses: 0x20, 0x21, 0x22, 0x23, 0x24, 0x25, 0x26, 0x27
 //   - TCA9534A - addresses: 0x38, 0x39, 0x3a, 0x3b, 0x3c, 0x3d, 0x3e, 0x3f
 //   - TCA9535 - addresses: 0x20, 0x21, 0x22, 0x23, 0x24, 0x25, 0x26, 0x27
@@ -21,6 +24,10 @@
 //   - TCA9555 - addresses: 0x20, 0x21, 0x22, 0x23, 0x24, 0x25, 0x26, 0x27
 //
 // Both gpio.Pin and conn.Conn interfaces are supported.
+//
+// The PCAL series "agile I/O" variants additionally support pull-up and
+// pull-down resistors, input latching, output drive strength and interrupt
+// masking. Their pins implement AgilePin.
 package tca95xx
 
 import (
@@ -68,6 +75,15 @@ func New(bus i2c.Bus, variant Variant, addr uint16) (*Dev, error) {
 		if err != nil {
 			return nil, err
 		}
+		if ports[i].agile != nil {
+			// pre-cache the pull configuration so Pull() doesn't hit the bus.
+			if _, err := ports[i].agile.pullEn.readValue(false); err != nil {
+				return nil, err
+			}
+			if _, err := ports[i].agile.pullSel.readValue(false); err != nil {
+				return nil, err
+			}
+		}
 		if pinsLeft > 8 {
 			pins[i] = ports[i].pins(8)
 			pinsLeft -= 8
@@ -5,6 +5,8 @@
 package tca95xx
 
 import (
+	"strconv"
+
 	"periph.io/x/conn/v3/i2c"
 )
 
@@ -12,39 +14,48 @@ import (
 type Variant string
 
 const (
-	PCA9536  Variant = "PCA9536"  // PCA9536  8-bit I²C extender. Datasheet: https://www.ti.com/lit/gpn/pca9536
-	TCA6408A Variant = "TCA6408A" // TCA6408A 8-bit I²C extender. Datasheet: https://www.ti.com/lit/gpn/tca6408a
-	TCA6416  Variant = "TCA6416"  // TCA6416  8-bit I²C extender. Datasheet: https://www.ti.com/lit/gpn/tca6416
-	TCA6416A Variant = "TCA6416A" // TCA6416A 8-bit I²C extender. Datasheet: https://www.ti.com/lit/gpn/tca6416a
-	TCA9534  Variant = "TCA9534"  // TCA9534  8-bit I²C extender. Datasheet: https://www.ti.com/lit/gpn/tca9534
-	TCA9534A Variant = "TCA9534A" // TCA9534A 8-bit I²C extender. Datasheet: https://www.ti.com/lit/gpn/tca9534a
-	TCA9535  Variant = "TCA9535"  // TCA9535  8-bit I²C extender. Datasheet: https://www.ti.com/lit/gpn/tca9535
-	TCA9537  Variant = "TCA9537"  // TCA9537  8-bit I²C extender. Datasheet: https://www.ti.com/lit/gpn/tca9537
-	TCA9538  Variant = "TCA9538"  // TCA9538  8-bit I²C extender. Datasheet: https://www.ti.com/lit/gpn/tca9538
-	TCA9539  Variant = "TCA9539"  // TCA9539  8-bit I²C extender. Datasheet: https://www.ti.com/lit/gpn/tca9539
-	TCA9554  Variant = "TCA9554"  // TCA9554  8-bit I²C extender. Datasheet: https://www.ti.com/lit/gpn/tca9554
-	TCA9555  Variant = "TCA9555"  // TCA9555  8-bit I²C extender. Datasheet: https://www.ti.com/lit/gpn/tca9555
+	PCA9536   Variant = "PCA9536"   // PCA9536  8-bit I²C extender. Datasheet: https://www.ti.com/lit/gpn/pca9536
+	PCAL6408A Variant = "PCAL6408A" // PCAL6408A 8-bit I²C extender with agile I/O. Datasheet: https://www.nxp.com/docs/en/data-sheet/PCAL6408A.pdf
+	PCAL6416A Variant = "PCAL6416A" // PCAL6416A 16-bit I²C extender with agile I/O. Datasheet: https://www.nxp.com/docs/en/data-sheet/PCAL6416A.pdf
+	TCA6408A  Variant = "TCA6408A"  // TCA6408A 8-bit I²C extender. Datasheet: https://www.ti.com/lit/gpn/tca6408a
+	TCA6416   Variant = "TCA6416"   // TCA6416  8-bit I²C extender. Datasheet: https://www.ti.com/lit/gpn/tca6416
+	TCA6416A  Variant = "TCA6416A"  // TCA6416A 8-bit I²C extender. Datasheet: https://www.ti.com/lit/gpn/tca6416a
+	TCA6424A  Variant = "TCA6424A"  // TCA6424A 24-bit I²C extender. Datasheet: https://www.ti.com/lit/gpn/tca6424a
+	TCA9534   Variant = "TCA9534"   // TCA9534  8-bit I²C extender. Datasheet: https://www.ti.com/lit/gpn/tca9534
+	TCA9534A  Variant = "TCA9534A"  // TCA9534A 8-bit I²C extender. Datasheet: https://www.ti.com/lit/gpn/tca9534a
+	TCA9535   Variant = "TCA9535"   // TCA9535  8-bit I²C extender. Datasheet: https://www.ti.com/lit/gpn/tca9535
+	TCA9537   Variant = "TCA9537"   // TCA9537  8-bit I²C extender. Datasheet: https://www.ti.com/lit/gpn/tca9537
+	TCA9538   Variant = "TCA9538"   // TCA9538  8-bit I²C extender. Datasheet: https://www.ti.com/lit/gpn/tca9538
+	TCA9539   Variant = "TCA9539"   // TCA9539  8-bit I²C extender. Datasheet: https://www.ti.com/lit/gpn/tca9539
+	TCA9554   Variant = "TCA9554"   // TCA9554  8-bit I²C extender. Datasheet: https://www.ti.com/lit/gpn/tca9554
+	TCA9555   Variant = "TCA9555"   // TCA9555  8-bit I²C extender. Datasheet: https://www.ti.com/lit/gpn/tca9555
 )
 
 type variant struct {
 	addStart uint16
 	addEnd   uint16
 	pins     int
+	// agile is set on the PCAL series which have the extended "agile I/O"
+	// register set.
+	agile bool
 }
 
 var variants = map[Variant]variant{
-	PCA9536:  {addStart: 0x41, addEnd: 0x41, pins: 4},
-	TCA6408A: {addStart: 0x20, addEnd: 0x21, pins: 8},
-	TCA6416:  {addStart: 0x20, addEnd: 0x21, pins: 16},
-	TCA6416A: {addStart: 0x20, addEnd: 0x21, pins: 16},
-	TCA9534:  {addStart: 0x20, addEnd: 0x27, pins: 8},
-	TCA9534A: {addStart: 0x38, addEnd: 0x3f, pins: 8},
-	TCA9535:  {addStart: 0x20, addEnd: 0x27, pins: 16},
-	TCA9537:  {addStart: 0x49, addEnd: 0x49, pins: 4},
-	TCA9538:  {addStart: 0x70, addEnd: 0x73, pins: 8},
-	TCA9539:  {addStart: 0x74, addEnd: 0x77, pins: 16},
-	TCA9554:  {addStart: 0x20, addEnd: 0x27, pins: 8},
-	TCA9555:  {addStart: 0x20, addEnd: 0x27, pins: 16},
+	PCA9536:   {addStart: 0x41, addEnd: 0x41, pins: 4},
+	PCAL6408A: {addStart: 0x20, addEnd: 0x21, pins: 8, agile: true},
+	PCAL6416A: {addStart: 0x20, addEnd: 0x21, pins: 16, agile: true},
+	TCA6408A:  {addStart: 0x20, addEnd: 0x21, pins: 8},
+	TCA6416:   {addStart: 0x20, addEnd: 0x21, pins: 16},
+	TCA6416A:  {addStart: 0x20, addEnd: 0x21, pins: 16},
+	TCA6424A:  {addStart: 0x22, addEnd: 0x23, pins: 24},
+	TCA9534:   {addStart: 0x20, addEnd: 0x27, pins: 8},
+	TCA9534A:  {addStart: 0x38, addEnd: 0x3f, pins: 8},
+	TCA9535:   {addStart: 0x20, addEnd: 0x27, pins: 16},
+	TCA9537:   {addStart: 0x49, addEnd: 0x49, pins: 4},
+	TCA9538:   {addStart: 0x70, addEnd: 0x73, pins: 8},
+	TCA9539:   {addStart: 0x74, addEnd: 0x77, pins: 16},
+	TCA9554:   {addStart: 0x20, addEnd: 0x27, pins: 8},
+	TCA9555:   {addStart: 0x20, addEnd: 0x27, pins: 16},
 }
 
 // isAddrInvalid checks to see if the address is used by the chip.
@@ -55,11 +66,25 @@ func (v variant) isAddrInvalid(addr uint16) bool {
 	return false
 }
 
-// getVariantRegMap returns the register map based on the number of pins the
-// chip expands to.
+// getPorts returns the register map based on the number of pins the chip
+// expands to.
 func (v variant) getPorts(i2c *i2c.Dev, devicename string) []*port {
-	if v.pins == 16 {
-		return []*port{
+	switch v.pins {
+	case 24:
+		ports := make([]*port, 3)
+		for i := range ports {
+			a := uint8(i)
+			ports[i] = &port{
+				name:   devicename + "_P" + strconv.Itoa(i),
+				input:  newRegister(i2c, 0x00+a),
+				output: newRegister(i2c, 0x04+a),
+				ipol:   newRegister(i2c, 0x08+a),
+				iodir:  newRegister(i2c, 0x0c+a),
+			}
+		}
+		return ports
+	case 16:
+		ports := []*port{
 			{
 				name:   devicename + "_P0",
 				input:  newRegister(i2c, 0x00),
@@ -75,15 +100,38 @@ func (v variant) getPorts(i2c *i2c.Dev, devicename string) []*port {
 				iodir:  newRegister(i2c, 0x07),
 			},
 		}
+		if v.agile {
+			for i, p := range ports {
+				a := uint8(i)
+				p.agile = &agileRegisters{
+					drive:     [2]registerCache{newRegister(i2c, 0x40+2*a), newRegister(i2c, 0x41+2*a)},
+					latch:     newRegister(i2c, 0x44+a),
+					pullEn:    newRegister(i2c, 0x46+a),
+					pullSel:   newRegister(i2c, 0x48+a),
+					intMask:   newRegister(i2c, 0x4a+a),
+					intStatus: newRegister(i2c, 0x4c+a),
+				}
+			}
+		}
+		return ports
 	}
 
-	return []*port{
-		{
-			name:   devicename + "_P0",
-			input:  newRegister(i2c, 0x00),
-			output: newRegister(i2c, 0x01),
-			ipol:   newRegister(i2c, 0x02),
-			iodir:  newRegister(i2c, 0x03),
-		},
+	p := &port{
+		name:   devicename + "_P0",
+		input:  newRegister(i2c, 0x00),
+		output: newRegister(i2c, 0x01),
+		ipol:   newRegister(i2c, 0x02),
+		iodir:  newRegister(i2c, 0x03),
+	}
+	if v.agile {
+		p.agile = &agileRegisters{
+			drive:     [2]registerCache{newRegister(i2c, 0x40), newRegister(i2c, 0x41)},
+			latch:     newRegister(i2c, 0x42),
+			pullEn:    newRegister(i2c, 0x43),
+			pullSel:   newRegister(i2c, 0x44),
+			intMask:   newRegister(i2c, 0x45),
+			intStatus: newRegister(i2c, 0x46),
+		}
 	}
+	return []*port{p}
 }
@@ -4,6 +4,8 @@
 
 // Package tic interfaces with Tic Stepper Motor Controllers via I²C.
 //
+// Dev implements stepper.Stepper.
+//
 // # More Details
 //
 // See https://www.pololu.com/category/212/tic-stepper-motor-controllers for
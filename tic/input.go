@@ -0,0 +1,154 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package tic
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// ErrInputNotValid is returned by GetScaledInput when the main input is not
+// ready or invalid.
+var ErrInputNotValid = errors.New("input not valid")
+
+// ControlMode describes how the Tic is controlled.
+type ControlMode uint8
+
+const (
+	ControlModeSerial          ControlMode = 0 // Serial, I²C or USB.
+	ControlModeStepDir         ControlMode = 1
+	ControlModeRCPosition      ControlMode = 2
+	ControlModeRCSpeed         ControlMode = 3
+	ControlModeAnalogPosition  ControlMode = 4
+	ControlModeAnalogSpeed     ControlMode = 5
+	ControlModeEncoderPosition ControlMode = 6
+	ControlModeEncoderSpeed    ControlMode = 7
+)
+
+// GetControlMode gets the control mode setting.
+func (d *Dev) GetControlMode() (ControlMode, error) {
+	b, err := d.GetSetting(settingControlMode, 1)
+	if err != nil {
+		return 0, err
+	}
+	return ControlMode(b[0]), nil
+}
+
+// ScalingDegree describes the relationship between the input and the target
+// in the RC and analog control modes.
+type ScalingDegree uint8
+
+const (
+	ScalingDegreeLinear    ScalingDegree = 0
+	ScalingDegreeQuadratic ScalingDegree = 1
+	ScalingDegreeCubic     ScalingDegree = 2
+)
+
+// InputScaling holds the settings converting the RC pulse width or the
+// analog voltage of the main input into a target position or velocity.
+//
+// The inputs are in units of 1/12 µs in RC mode and in 1/4095 of the 5V
+// supply in analog mode. The targets are in microsteps, or microsteps per
+// 10000 seconds in the speed control modes.
+type InputScaling struct {
+	// Invert swaps the directions.
+	Invert bool
+	Degree ScalingDegree
+	// Inputs between InputNeutralMin and InputNeutralMax are mapped to a
+	// target of 0. Inputs below InputMin or above InputMax are mapped to
+	// TargetMin and TargetMax.
+	InputMin        uint16
+	InputNeutralMin uint16
+	InputNeutralMax uint16
+	InputMax        uint16
+	TargetMin       int32
+	TargetMax       int32
+}
+
+// GetInputScaling gets the input scaling settings.
+//
+// The settings are stored in the Tic's EEPROM. They can only be changed over
+// USB, e.g. with the Tic Control Center, as the Tic doesn't support writing
+// settings over I²C.
+func (d *Dev) GetInputScaling() (InputScaling, error) {
+	// The target limits aren't next to the input ones.
+	a, err := d.GetSetting(settingInputScalingDegree, 10)
+	if err != nil {
+		return InputScaling{}, err
+	}
+	lo, err := d.GetSetting(settingOutputMin, 4)
+	if err != nil {
+		return InputScaling{}, err
+	}
+	hi, err := d.GetSetting(settingOutputMax, 4)
+	if err != nil {
+		return InputScaling{}, err
+	}
+	le := binary.LittleEndian
+	return InputScaling{
+		Degree:          ScalingDegree(a[0]),
+		Invert:          a[1] != 0,
+		InputMin:        le.Uint16(a[2:]),
+		InputNeutralMin: le.Uint16(a[4:]),
+		InputNeutralMax: le.Uint16(a[6:]),
+		InputMax:        le.Uint16(a[8:]),
+		TargetMin:       int32(le.Uint32(lo)),
+		TargetMax:       int32(le.Uint32(hi)),
+	}, nil
+}
+
+// GetScaledInput gets the target specified by the main input after scaling,
+// converted to full steps using the current step mode.
+//
+// If speed is true, the input specifies a target velocity and v is in full
+// steps per second; otherwise it specifies a target position and v is in
+// full steps. v is 0 when the input tells the Tic to halt the motor.
+//
+// ErrInputNotValid is returned if the input is not ready or invalid.
+func (d *Dev) GetScaledInput() (v float64, speed bool, err error) {
+	state, err := d.GetInputState()
+	if err != nil {
+		return 0, false, err
+	}
+	switch state {
+	case InputStateHalt:
+		return 0, false, nil
+	case InputStatePosition:
+	case InputStateVelocity:
+		speed = true
+	default:
+		return 0, false, ErrInputNotValid
+	}
+	scaled, err := d.GetInputAfterScaling()
+	if err != nil {
+		return 0, false, err
+	}
+	mode, err := d.GetStepMode()
+	if err != nil {
+		return 0, false, err
+	}
+	if int(mode) >= len(microsteps) {
+		return 0, false, ErrInvalidSetting
+	}
+	v = float64(scaled) / float64(microsteps[mode])
+	if speed {
+		v /= 10000
+	}
+	return v, speed, nil
+}
+
+//
+
+// microsteps is the number of microsteps per full step for each StepMode.
+var microsteps = [...]int{1, 2, 4, 8, 16, 32, 2, 64, 128, 256}
+
+// Settings offsets, see the "Settings reference" section of the Tic user's
+// guide.
+const (
+	settingControlMode        offset = 0x01 // uint8
+	settingInputScalingDegree offset = 0x20 // uint8
+	settingOutputMin          offset = 0x2A // int32
+	settingOutputMax          offset = 0x32 // int32
+)
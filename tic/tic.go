@@ -13,6 +13,7 @@ import (
 	"periph.io/x/conn/v3"
 	"periph.io/x/conn/v3/i2c"
 	"periph.io/x/conn/v3/physic"
+	"periph.io/x/devices/v3/stepper"
 )
 
 // I2CAddr is the default I²C address for the Tic.
@@ -1337,3 +1338,4 @@ const (
 
 var _ conn.Resource = &Dev{}
 var _ fmt.Stringer = &Dev{}
+var _ stepper.Stepper = &Dev{}
@@ -0,0 +1,21 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package tictest implements a simulated Tic stepper motor controller.
+//
+// Tic implements i2c.Bus and answers the Tic's I²C command and variable
+// protocol, so code built on top of the tic package can be tested without
+// hardware:
+//
+//	sim := tictest.New()
+//	dev, err := tic.NewI2C(sim, tic.TicT825, tic.I2CAddr)
+//	...
+//	sim.Advance(time.Second)
+//
+// Time only passes when Advance is called. The motion model is intentionally
+// simple: the current velocity ramps toward the planned velocity within the
+// acceleration and deceleration limits, and the position integrates the
+// velocity. Limit switches and errors can be set by the test to exercise the
+// error handling paths of the application.
+package tictest
@@ -0,0 +1,38 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package tictest_test
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"periph.io/x/devices/v3/tic"
+	"periph.io/x/devices/v3/tic/tictest"
+)
+
+func Example() {
+	sim := tictest.New()
+	dev, err := tic.NewI2C(sim, tic.TicT825, tic.I2CAddr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := dev.ExitSafeStart(); err != nil {
+		log.Fatal(err)
+	}
+	if err := dev.SetTargetPosition(1000); err != nil {
+		log.Fatal(err)
+	}
+
+	// Let the simulated motor move for 10 seconds.
+	sim.Advance(10 * time.Second)
+
+	pos, err := dev.GetCurrentPosition()
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(pos)
+	// Output: 1000
+}
@@ -0,0 +1,635 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package tictest
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/devices/v3/tic"
+)
+
+// Default settings loaded on power up and on the Reset command. They match
+// the Tic's factory settings.
+const (
+	DefaultSpeedMax = 2000000 // 200 steps per second
+	DefaultAccelMax = 40000   // 400 steps per second²
+	DefaultVoltage  = 12 * physic.Volt
+)
+
+// step is the longest interval integrated at once by Advance.
+const step = time.Millisecond
+
+// errorsDeenergize are the errors that de-energize the motor. The other
+// errors make the motor decelerate to a stop.
+const errorsDeenergize = 1<<tic.ErrorBitIntentionallyDeenergized |
+	1<<tic.ErrorBitMotorDriverError |
+	1<<tic.ErrorBitLowVin |
+	1<<tic.ErrorBitErrLineHigh
+
+// Tic is a simulated Tic stepper motor controller.
+//
+// It implements i2c.Bus. It is safe for concurrent use.
+type Tic struct {
+	// CommandTimeout enables the "Command timeout" error when non-zero. Every
+	// command other than the reads restarts the timer. The real device
+	// defaults to 1s but the simulation defaults to disabled so that tests
+	// don't need to call ResetCommandTimeout.
+	CommandTimeout time.Duration
+
+	mu   sync.Mutex
+	addr uint16
+
+	// Conditions forced by the test with SetError, and errors caused by
+	// commands.
+	forced  uint16
+	latched uint16
+	// errorsOccurred is cleared by reading it with "Get variable and clear
+	// errors".
+	errorsOccurred uint32
+
+	planning       tic.PlanningMode
+	targetPosition int32
+	targetVelocity int32
+	startingSpeed  uint32
+	speedMax       uint32
+	accelMax       uint32
+	decelMax       uint32
+
+	// position is in microsteps and velocity in microsteps per 10000 seconds.
+	position          float64
+	velocity          float64
+	positionUncertain bool
+	homing            bool
+	homeForward       bool
+	forwardLimit      bool
+	reverseLimit      bool
+
+	vin          physic.ElectricPotential
+	encoder      int32
+	stepMode     uint8
+	currentLimit uint8
+	decayMode    uint8
+	agc          [4]uint8
+	settings     [256]byte
+	inputState   tic.InputState
+	inputScaled  int32
+
+	uptime       time.Duration
+	sinceCommand time.Duration
+
+	// Read command latched by the write phase, served by the next read.
+	pending    bool
+	pendingCmd byte
+	pendingOff byte
+}
+
+// New returns a simulated Tic answering at tic.I2CAddr.
+//
+// Like a real Tic controlled over I²C, it starts with the "Safe start
+// violation" error set; send ExitSafeStart before commanding motion.
+func New() *Tic {
+	t := &Tic{addr: tic.I2CAddr, vin: DefaultVoltage}
+	t.reset()
+	return t
+}
+
+// SetAddr changes the I²C address the simulated Tic answers to.
+func (t *Tic) SetAddr(addr uint16) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.addr = addr
+}
+
+// String implements i2c.Bus.
+func (t *Tic) String() string {
+	return "tictest"
+}
+
+// Close implements i2c.BusCloser.
+func (t *Tic) Close() error {
+	return nil
+}
+
+// SetSpeed implements i2c.Bus.
+func (t *Tic) SetSpeed(f physic.Frequency) error {
+	return nil
+}
+
+// Tx implements i2c.Bus.
+//
+// w is interpreted as a command. A "Get variable" or "Get setting" command
+// selects the data returned by the following read, which is how the tic
+// package reads variables.
+func (t *Tic) Tx(addr uint16, w, r []byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if addr != t.addr {
+		return fmt.Errorf("tictest: no device at address 0x%02X", addr)
+	}
+	if len(w) != 0 {
+		if err := t.command(w); err != nil {
+			return err
+		}
+	}
+	if len(r) != 0 {
+		return t.read(r)
+	}
+	return nil
+}
+
+// Advance runs the motion model for d of simulated time.
+func (t *Tic) Advance(d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for d > 0 {
+		dt := min(d, step)
+		t.advance(dt)
+		d -= dt
+	}
+}
+
+// SetError forces the error condition bit until ClearError is called.
+//
+// The bit is also reported in the "Errors occurred" variable.
+func (t *Tic) SetError(bit tic.ErrorBit) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if bit < 16 {
+		t.forced |= 1 << bit
+	}
+	t.errorsOccurred |= 1 << bit
+	t.update()
+}
+
+// ClearError removes an error condition, either forced by SetError or caused
+// by a command.
+func (t *Tic) ClearError(bit tic.ErrorBit) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.forced &^= 1 << bit
+	t.latched &^= 1 << bit
+}
+
+// SetLimitSwitches sets the state of the forward and reverse limit switches.
+//
+// An active limit switch stops motion in its direction and ends homing
+// toward it, setting the current position to zero.
+func (t *Tic) SetLimitSwitches(forward, reverse bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.forwardLimit = forward
+	t.reverseLimit = reverse
+	t.limits()
+}
+
+// SetVoltage sets the VIN voltage measurement.
+func (t *Tic) SetVoltage(v physic.ElectricPotential) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.vin = v
+}
+
+// SetEncoderPosition sets the raw encoder count.
+func (t *Tic) SetEncoderPosition(p int32) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.encoder = p
+}
+
+// SetSetting sets the content of the settings EEPROM returned by
+// "Get setting".
+func (t *Tic) SetSetting(offset uint8, b []byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	copy(t.settings[offset:], b)
+}
+
+// SetInput sets the state of the main input and its value after scaling, as
+// if the Tic was controlled in RC, analog or encoder mode.
+func (t *Tic) SetInput(state tic.InputState, scaled int32) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.inputState = state
+	t.inputScaled = scaled
+}
+
+// Position returns the current position, in microsteps.
+func (t *Tic) Position() int32 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return int32(math.Round(t.position))
+}
+
+// Velocity returns the current velocity, in microsteps per 10000 seconds.
+func (t *Tic) Velocity() int32 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return int32(math.Round(t.velocity))
+}
+
+// Energized returns true if the motor coils are energized.
+func (t *Tic) Energized() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.energized()
+}
+
+// ErrorStatus returns the active error bits.
+func (t *Tic) ErrorStatus() uint16 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.errorStatus()
+}
+
+// Homing returns true while the homing procedure is running.
+func (t *Tic) Homing() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.homing
+}
+
+// Command codes. See the "Command reference" section of the Tic user's guide.
+const (
+	cmdSetTargetPosition         = 0xE0
+	cmdSetTargetVelocity         = 0xE3
+	cmdHaltAndSetPosition        = 0xEC
+	cmdHaltAndHold               = 0x89
+	cmdGoHome                    = 0x97
+	cmdResetCommandTimeout       = 0x8C
+	cmdDeenergize                = 0x86
+	cmdEnergize                  = 0x85
+	cmdExitSafeStart             = 0x83
+	cmdEnterSafeStart            = 0x8F
+	cmdReset                     = 0xB0
+	cmdClearDriverError          = 0x8A
+	cmdSetSpeedMax               = 0xE6
+	cmdSetStartingSpeed          = 0xE5
+	cmdSetAccelMax               = 0xEA
+	cmdSetDecelMax               = 0xE9
+	cmdSetStepMode               = 0x94
+	cmdSetCurrentLimit           = 0x91
+	cmdSetDecayMode              = 0x92
+	cmdSetAGCOption              = 0x98
+	cmdGetVariable               = 0xA1
+	cmdGetVariableAndClearErrors = 0xA2
+	cmdGetSetting                = 0xA8
+)
+
+var errNoPendingRead = errors.New("tictest: read without a preceding get command")
+
+// command executes the command in w.
+func (t *Tic) command(w []byte) error {
+	cmd := w[0]
+	arg := w[1:]
+	switch cmd {
+	case cmdGetVariable, cmdGetVariableAndClearErrors, cmdGetSetting:
+		if len(arg) != 1 {
+			return errLength(cmd, len(arg))
+		}
+		t.pending = true
+		t.pendingCmd = cmd
+		t.pendingOff = arg[0]
+		return nil
+
+	case cmdHaltAndHold, cmdResetCommandTimeout, cmdDeenergize, cmdEnergize,
+		cmdExitSafeStart, cmdEnterSafeStart, cmdReset, cmdClearDriverError:
+		if len(arg) != 0 {
+			return errLength(cmd, len(arg))
+		}
+	case cmdGoHome, cmdSetStepMode, cmdSetCurrentLimit, cmdSetDecayMode,
+		cmdSetAGCOption:
+		if len(arg) != 1 {
+			return errLength(cmd, len(arg))
+		}
+		if arg[0]&0x80 != 0 {
+			return fmt.Errorf("tictest: command 0x%02X: data byte 0x%02X has its MSB set", cmd, arg[0])
+		}
+	case cmdSetTargetPosition, cmdSetTargetVelocity, cmdHaltAndSetPosition,
+		cmdSetSpeedMax, cmdSetStartingSpeed, cmdSetAccelMax, cmdSetDecelMax:
+		if len(arg) != 4 {
+			return errLength(cmd, len(arg))
+		}
+	default:
+		return fmt.Errorf("tictest: unknown command 0x%02X", cmd)
+	}
+
+	t.sinceCommand = 0
+	switch cmd {
+	case cmdHaltAndHold:
+		t.halt()
+		t.positionUncertain = true
+	case cmdResetCommandTimeout:
+		t.latched &^= 1 << tic.ErrorBitCommandTimeout
+	case cmdDeenergize:
+		t.latch(tic.ErrorBitIntentionallyDeenergized)
+	case cmdEnergize:
+		t.latched &^= 1 << tic.ErrorBitIntentionallyDeenergized
+	case cmdExitSafeStart:
+		t.latched &^= 1 << tic.ErrorBitSafeStartViolation
+	case cmdEnterSafeStart:
+		t.latch(tic.ErrorBitSafeStartViolation)
+	case cmdReset:
+		t.reset()
+	case cmdClearDriverError:
+		t.forced &^= 1 << tic.ErrorBitMotorDriverError
+		t.latched &^= 1 << tic.ErrorBitMotorDriverError
+	case cmdGoHome:
+		t.planning = tic.PlanningModeOff
+		t.homing = true
+		t.homeForward = arg[0] != 0
+		t.positionUncertain = true
+		t.limits()
+	case cmdSetStepMode:
+		t.stepMode = arg[0]
+	case cmdSetCurrentLimit:
+		t.currentLimit = arg[0]
+	case cmdSetDecayMode:
+		t.decayMode = arg[0]
+	case cmdSetAGCOption:
+		if i := arg[0] >> 4; int(i) < len(t.agc) {
+			t.agc[i] = arg[0] & 0xF
+		}
+	default:
+		v := binary.LittleEndian.Uint32(arg)
+		switch cmd {
+		case cmdSetTargetPosition:
+			t.homing = false
+			t.planning = tic.PlanningModeTargetPosition
+			t.targetPosition = int32(v)
+		case cmdSetTargetVelocity:
+			t.homing = false
+			t.planning = tic.PlanningModeTargetVelocity
+			t.targetVelocity = int32(v)
+		case cmdHaltAndSetPosition:
+			t.halt()
+			t.position = float64(int32(v))
+			t.positionUncertain = false
+		case cmdSetSpeedMax:
+			t.speedMax = v
+		case cmdSetStartingSpeed:
+			t.startingSpeed = v
+		case cmdSetAccelMax:
+			t.accelMax = v
+		case cmdSetDecelMax:
+			t.decelMax = v
+		}
+	}
+	t.update()
+	return nil
+}
+
+// read serves the read requested by the last get command.
+func (t *Tic) read(r []byte) error {
+	if !t.pending {
+		return errNoPendingRead
+	}
+	t.pending = false
+	var src []byte
+	if t.pendingCmd == cmdGetSetting {
+		src = t.settings[t.pendingOff:]
+	} else {
+		vars := t.variables()
+		src = vars[t.pendingOff:]
+		if t.pendingCmd == cmdGetVariableAndClearErrors {
+			t.errorsOccurred = 0
+		}
+	}
+	if len(r) > len(src) {
+		return fmt.Errorf("tictest: read of %d bytes at offset 0x%02X is out of range", len(r), t.pendingOff)
+	}
+	copy(r, src)
+	return nil
+}
+
+// variables returns the variable block as described in the "Variable
+// reference" section of the Tic user's guide.
+func (t *Tic) variables() [256]byte {
+	var b [256]byte
+	b[tic.OffsetOperationState] = byte(t.operationState())
+	var flags byte
+	if t.energized() {
+		flags |= 1 << 0
+	}
+	if t.positionUncertain {
+		flags |= 1 << 1
+	}
+	if t.forwardLimit {
+		flags |= 1 << 2
+	}
+	if t.reverseLimit {
+		flags |= 1 << 3
+	}
+	if t.homing {
+		flags |= 1 << 4
+	}
+	b[tic.OffsetMiscFlags1] = flags
+	le := binary.LittleEndian
+	le.PutUint16(b[tic.OffsetErrorStatus:], t.errorStatus())
+	le.PutUint32(b[tic.OffsetErrorsOccurred:], t.errorsOccurred)
+	b[tic.OffsetPlanningMode] = byte(t.planning)
+	le.PutUint32(b[tic.OffsetTargetPosition:], uint32(t.targetPosition))
+	le.PutUint32(b[tic.OffsetTargetVelocity:], uint32(t.targetVelocity))
+	le.PutUint32(b[tic.OffsetStartingSpeed:], t.startingSpeed)
+	le.PutUint32(b[tic.OffsetSpeedMax:], t.speedMax)
+	le.PutUint32(b[tic.OffsetDecelMax:], t.decelMax)
+	le.PutUint32(b[tic.OffsetAccelMax:], t.accelMax)
+	pos := int32(math.Round(t.position))
+	le.PutUint32(b[tic.OffsetCurrentPosition:], uint32(pos))
+	le.PutUint32(b[tic.OffsetCurrentVelocity:], uint32(int32(math.Round(t.velocity))))
+	acting := pos
+	if t.planning == tic.PlanningModeTargetPosition {
+		acting = t.targetPosition
+	}
+	le.PutUint32(b[tic.OffsetActingTargetPosition:], uint32(acting))
+	b[tic.OffsetDeviceReset] = tic.ResetCausePowerUp
+	le.PutUint16(b[tic.OffsetVoltageIn:], uint16(t.vin/physic.MilliVolt))
+	le.PutUint32(b[tic.OffsetUpTime:], uint32(t.uptime/time.Millisecond))
+	le.PutUint32(b[tic.OffsetEncoderPosition:], uint32(t.encoder))
+	b[tic.OffsetStepMode] = t.stepMode
+	b[tic.OffsetCurrentLimit] = t.currentLimit
+	b[tic.OffsetDecayMode] = t.decayMode
+	copy(b[tic.OffsetAGCMode:], t.agc[:])
+	b[tic.OffsetInputState] = byte(t.inputState)
+	le.PutUint32(b[tic.OffsetInputAfterScaling:], uint32(t.inputScaled))
+	return b
+}
+
+// reset loads the default settings and forgets the motion state, like the
+// Reset command.
+func (t *Tic) reset() {
+	t.halt()
+	t.positionUncertain = true
+	t.targetPosition = 0
+	t.targetVelocity = 0
+	t.startingSpeed = 0
+	t.speedMax = DefaultSpeedMax
+	t.accelMax = DefaultAccelMax
+	t.decelMax = 0
+	t.stepMode = 0
+	t.currentLimit = 0
+	t.decayMode = 0
+	t.agc = [4]uint8{}
+	t.latched = 0
+	t.latch(tic.ErrorBitSafeStartViolation)
+	t.pending = false
+	t.sinceCommand = 0
+}
+
+// halt stops the motor abruptly.
+func (t *Tic) halt() {
+	t.planning = tic.PlanningModeOff
+	t.homing = false
+	t.velocity = 0
+}
+
+// latch sets an error caused by a command or by the simulation.
+func (t *Tic) latch(bit tic.ErrorBit) {
+	t.latched |= 1 << bit
+	t.errorsOccurred |= 1 << bit
+	t.update()
+}
+
+// update applies the side effects of the error state.
+func (t *Tic) update() {
+	if !t.energized() {
+		t.positionUncertain = true
+		t.halt()
+	}
+}
+
+func (t *Tic) errorStatus() uint16 {
+	return t.forced | t.latched
+}
+
+func (t *Tic) energized() bool {
+	return t.errorStatus()&errorsDeenergize == 0
+}
+
+func (t *Tic) operationState() tic.OperationState {
+	switch {
+	case !t.energized():
+		return tic.OperationStateDeenergized
+	case t.errorStatus() != 0:
+		return tic.OperationStateSoftError
+	default:
+		return tic.OperationStateNormal
+	}
+}
+
+// limits stops motion toward an active limit switch and completes homing.
+func (t *Tic) limits() {
+	if t.homing && ((t.homeForward && t.forwardLimit) || (!t.homeForward && t.reverseLimit)) {
+		t.homing = false
+		t.position = 0
+		t.positionUncertain = false
+	}
+	if (t.forwardLimit && t.velocity > 0) || (t.reverseLimit && t.velocity < 0) {
+		t.velocity = 0
+	}
+}
+
+// advance integrates the motion model over dt.
+func (t *Tic) advance(dt time.Duration) {
+	t.uptime += dt
+	t.sinceCommand += dt
+	if t.CommandTimeout > 0 && t.sinceCommand > t.CommandTimeout && t.latched&(1<<tic.ErrorBitCommandTimeout) == 0 {
+		t.latch(tic.ErrorBitCommandTimeout)
+	}
+	t.update()
+	if !t.energized() {
+		return
+	}
+
+	s := dt.Seconds()
+	want := t.plannedVelocity()
+	if (t.forwardLimit && want > 0) || (t.reverseLimit && want < 0) {
+		want = 0
+	}
+	t.velocity = t.ramp(want, s)
+	prev := t.position
+	t.position += t.velocity / 10000 * s
+
+	if t.planning == tic.PlanningModeTargetPosition && t.errorStatus() == 0 {
+		target := float64(t.targetPosition)
+		if math.Abs(target-t.position) < 1 || (prev < target) != (t.position < target) {
+			t.position = target
+			t.velocity = 0
+		}
+	}
+	t.limits()
+}
+
+// plannedVelocity returns the velocity the step planner aims for.
+func (t *Tic) plannedVelocity() float64 {
+	vmax := float64(t.speedMax)
+	switch {
+	case t.errorStatus() != 0:
+		return 0
+	case t.homing:
+		if t.homeForward {
+			return vmax
+		}
+		return -vmax
+	case t.planning == tic.PlanningModeTargetVelocity:
+		return math.Max(-vmax, math.Min(vmax, float64(t.targetVelocity)))
+	case t.planning == tic.PlanningModeTargetPosition:
+		dist := float64(t.targetPosition) - t.position
+		// Fastest speed that still allows to stop at the target. The
+		// deceleration is in microsteps per 100 s² and the speed in
+		// microsteps per 10000 s.
+		if d := float64(t.decel()); d != 0 {
+			vmax = math.Min(vmax, math.Sqrt(2*d/100*math.Abs(dist))*10000)
+		}
+		return math.Copysign(vmax, dist)
+	default:
+		return 0
+	}
+}
+
+// ramp returns the velocity after moving from the current velocity toward
+// want during s seconds within the acceleration limits.
+func (t *Tic) ramp(want, s float64) float64 {
+	v := t.velocity
+	accelerating := math.Abs(want) > math.Abs(v) && (v == 0 || (want > 0) == (v > 0))
+	rate := float64(t.decel())
+	if accelerating {
+		rate = float64(t.accelMax)
+		if start := float64(t.startingSpeed); math.Abs(v) < start {
+			v = math.Copysign(math.Min(math.Abs(want), start), want)
+		}
+	}
+	if rate == 0 {
+		return want
+	}
+	// The acceleration is in microsteps per 100 s², which is 100 times the
+	// velocity unit per second.
+	dv := rate * 100 * s
+	if want > v {
+		return math.Min(want, v+dv)
+	}
+	return math.Max(want, v-dv)
+}
+
+// decel returns the deceleration limit; zero means it's the same as the
+// acceleration limit.
+func (t *Tic) decel() uint32 {
+	if t.decelMax == 0 {
+		return t.accelMax
+	}
+	return t.decelMax
+}
+
+func errLength(cmd byte, n int) error {
+	return fmt.Errorf("tictest: command 0x%02X: unexpected %d data bytes", cmd, n)
+}
+
+var _ i2c.BusCloser = &Tic{}
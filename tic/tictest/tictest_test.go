@@ -0,0 +1,376 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package tictest
+
+import (
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/devices/v3/tic"
+)
+
+func newDev(t *testing.T) (*Tic, *tic.Dev) {
+	sim := New()
+	dev, err := tic.NewI2C(sim, tic.TicT825, tic.I2CAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sim, dev
+}
+
+func TestNew(t *testing.T) {
+	sim, dev := newDev(t)
+	if _, err := tic.NewI2C(sim, tic.TicT825, 0x10); err == nil {
+		t.Fatal("expected error on wrong address")
+	}
+	if err := sim.Tx(tic.I2CAddr, nil, make([]byte, 1)); err == nil {
+		t.Fatal("expected error on read without command")
+	}
+	if err := sim.Tx(tic.I2CAddr, []byte{0x42}, nil); err == nil {
+		t.Fatal("expected error on unknown command")
+	}
+
+	state, err := dev.GetOperationState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state != tic.OperationStateSoftError {
+		t.Fatalf("got state %d, want soft error", state)
+	}
+	if ok, err := dev.HasError(tic.ErrorBitSafeStartViolation); err != nil || !ok {
+		t.Fatalf("safe start violation: %t, %v", ok, err)
+	}
+	if v, err := dev.GetMaxSpeed(); err != nil || v != DefaultSpeedMax {
+		t.Fatalf("max speed: %d, %v", v, err)
+	}
+	if v, err := dev.GetVoltageIn(); err != nil || v != DefaultVoltage {
+		t.Fatalf("voltage: %s, %v", v, err)
+	}
+
+	// Safe start prevents motion.
+	if err := dev.SetTargetVelocity(1000000); err != nil {
+		t.Fatal(err)
+	}
+	sim.Advance(time.Second)
+	if p := sim.Position(); p != 0 {
+		t.Fatalf("moved to %d during safe start violation", p)
+	}
+	if err := dev.ExitSafeStart(); err != nil {
+		t.Fatal(err)
+	}
+	if state, err = dev.GetOperationState(); err != nil || state != tic.OperationStateNormal {
+		t.Fatalf("state: %d, %v", state, err)
+	}
+}
+
+func TestSettings(t *testing.T) {
+	sim, dev := newDev(t)
+	if err := dev.SetStepMode(tic.StepModeMicrostep8); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := dev.GetStepMode(); err != nil || v != tic.StepModeMicrostep8 {
+		t.Fatalf("step mode: %d, %v", v, err)
+	}
+	if err := dev.SetCurrentLimit(640 * physic.MilliAmpere); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := dev.GetCurrentLimit(); err != nil || v != 640*physic.MilliAmpere {
+		t.Fatalf("current limit: %s, %v", v, err)
+	}
+	if err := dev.SetMaxDecel(100000); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := dev.GetMaxDecel(); err != nil || v != 100000 {
+		t.Fatalf("max decel: %d, %v", v, err)
+	}
+	sim.SetSetting(0x10, []byte{1, 2, 3})
+	if v, err := dev.GetSetting(0x10, 3); err != nil || v[0] != 1 || v[2] != 3 {
+		t.Fatalf("setting: %v, %v", v, err)
+	}
+	if err := dev.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := dev.GetMaxDecel(); err != nil || v != 0 {
+		t.Fatalf("max decel after reset: %d, %v", v, err)
+	}
+}
+
+func TestInput(t *testing.T) {
+	sim, dev := newDev(t)
+	sim.SetSetting(0x01, []byte{byte(tic.ControlModeAnalogSpeed)})
+	sim.SetSetting(0x20, []byte{
+		byte(tic.ScalingDegreeQuadratic), 1,
+		0x00, 0x00, 0xF8, 0x07, 0x08, 0x08, 0xFF, 0x0F,
+	})
+	sim.SetSetting(0x2A, []byte{0x80, 0x7B, 0xE1, 0xFF})
+	sim.SetSetting(0x32, []byte{0x80, 0x84, 0x1E, 0x00})
+	if v, err := dev.GetControlMode(); err != nil || v != tic.ControlModeAnalogSpeed {
+		t.Fatalf("control mode: %d, %v", v, err)
+	}
+	want := tic.InputScaling{
+		Invert:          true,
+		Degree:          tic.ScalingDegreeQuadratic,
+		InputMin:        0,
+		InputNeutralMin: 2040,
+		InputNeutralMax: 2056,
+		InputMax:        4095,
+		TargetMin:       -2000000,
+		TargetMax:       2000000,
+	}
+	if v, err := dev.GetInputScaling(); err != nil || v != want {
+		t.Fatalf("input scaling: %+v, %v", v, err)
+	}
+
+	if _, _, err := dev.GetScaledInput(); err != tic.ErrInputNotValid {
+		t.Fatalf("input not ready: %v", err)
+	}
+	if err := dev.SetStepMode(tic.StepModeHalf); err != nil {
+		t.Fatal(err)
+	}
+	sim.SetInput(tic.InputStateVelocity, -1000000)
+	if v, speed, err := dev.GetScaledInput(); err != nil || !speed || v != -50 {
+		t.Fatalf("velocity: %g, %t, %v", v, speed, err)
+	}
+	sim.SetInput(tic.InputStatePosition, 300)
+	if v, speed, err := dev.GetScaledInput(); err != nil || speed || v != 150 {
+		t.Fatalf("position: %g, %t, %v", v, speed, err)
+	}
+	sim.SetInput(tic.InputStateHalt, 300)
+	if v, _, err := dev.GetScaledInput(); err != nil || v != 0 {
+		t.Fatalf("halt: %g, %v", v, err)
+	}
+}
+
+func TestTargetVelocity(t *testing.T) {
+	sim, dev := newDev(t)
+	if err := dev.ExitSafeStart(); err != nil {
+		t.Fatal(err)
+	}
+	// 100 steps/s reached after 250ms at 400 steps/s².
+	if err := dev.SetTargetVelocity(1000000); err != nil {
+		t.Fatal(err)
+	}
+	sim.Advance(100 * time.Millisecond)
+	if v, err := dev.GetCurrentVelocity(); err != nil || v != 400000 {
+		t.Fatalf("velocity: %d, %v", v, err)
+	}
+	sim.Advance(900 * time.Millisecond)
+	if v := sim.Velocity(); v != 1000000 {
+		t.Fatalf("velocity: %d", v)
+	}
+	// 12.5 steps while accelerating then 75 steps at full speed.
+	if p, err := dev.GetCurrentPosition(); err != nil || p < 87 || p > 88 {
+		t.Fatalf("position: %d, %v", p, err)
+	}
+
+	// The speed is capped to the maximum speed.
+	if err := dev.SetTargetVelocity(-5000000); err != nil {
+		t.Fatal(err)
+	}
+	sim.Advance(2 * time.Second)
+	if v := sim.Velocity(); v != -DefaultSpeedMax {
+		t.Fatalf("velocity: %d", v)
+	}
+
+	if err := dev.HaltAndHold(); err != nil {
+		t.Fatal(err)
+	}
+	if v := sim.Velocity(); v != 0 {
+		t.Fatalf("velocity after halt: %d", v)
+	}
+	if ok, err := dev.IsPositionUncertain(); err != nil || !ok {
+		t.Fatalf("position uncertain: %t, %v", ok, err)
+	}
+}
+
+func TestTargetPosition(t *testing.T) {
+	sim, dev := newDev(t)
+	if err := dev.ExitSafeStart(); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.HaltAndSetPosition(-100); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.SetTargetPosition(200); err != nil {
+		t.Fatal(err)
+	}
+	sim.Advance(500 * time.Millisecond)
+	p, err := dev.GetCurrentPosition()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p <= -100 || p >= 200 {
+		t.Fatalf("position while moving: %d", p)
+	}
+	if v, err := dev.GetTargetPosition(); err != nil || v != 200 {
+		t.Fatalf("target: %d, %v", v, err)
+	}
+	sim.Advance(5 * time.Second)
+	if p := sim.Position(); p != 200 {
+		t.Fatalf("position: %d", p)
+	}
+	if v := sim.Velocity(); v != 0 {
+		t.Fatalf("velocity: %d", v)
+	}
+	if ok, err := dev.IsPositionUncertain(); err != nil || ok {
+		t.Fatalf("position uncertain: %t, %v", ok, err)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	sim, dev := newDev(t)
+	if err := dev.ExitSafeStart(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dev.GetErrorsOccurred(); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.SetTargetVelocity(1000000); err != nil {
+		t.Fatal(err)
+	}
+	sim.Advance(time.Second)
+
+	sim.SetError(tic.ErrorBitLowVin)
+	if ok, err := dev.IsEnergized(); err != nil || ok {
+		t.Fatalf("energized: %t, %v", ok, err)
+	}
+	if state, err := dev.GetOperationState(); err != nil || state != tic.OperationStateDeenergized {
+		t.Fatalf("state: %d, %v", state, err)
+	}
+	p := sim.Position()
+	sim.Advance(time.Second)
+	if sim.Position() != p {
+		t.Fatal("moved while de-energized")
+	}
+	if v, err := dev.GetErrorsOccurred(); err != nil || v != 1<<tic.ErrorBitLowVin {
+		t.Fatalf("errors occurred: %#x, %v", v, err)
+	}
+	if v, err := dev.GetErrorsOccurred(); err != nil || v != 0 {
+		t.Fatalf("errors occurred not cleared: %#x, %v", v, err)
+	}
+
+	sim.ClearError(tic.ErrorBitLowVin)
+	if ok, err := dev.IsEnergized(); err != nil || !ok {
+		t.Fatalf("energized: %t, %v", ok, err)
+	}
+
+	// A soft error decelerates to a stop.
+	if err := dev.SetTargetVelocity(1000000); err != nil {
+		t.Fatal(err)
+	}
+	sim.Advance(time.Second)
+	sim.SetError(tic.ErrorBitKillSwitch)
+	sim.Advance(100 * time.Millisecond)
+	if v := sim.Velocity(); v != 600000 {
+		t.Fatalf("velocity: %d", v)
+	}
+	sim.Advance(time.Second)
+	if v := sim.Velocity(); v != 0 {
+		t.Fatalf("velocity: %d", v)
+	}
+}
+
+func TestDeenergize(t *testing.T) {
+	sim, dev := newDev(t)
+	if err := dev.ExitSafeStart(); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.HaltAndSetPosition(0); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.Deenergize(); err != nil {
+		t.Fatal(err)
+	}
+	if sim.Energized() {
+		t.Fatal("energized")
+	}
+	if err := dev.Energize(); err != nil {
+		t.Fatal(err)
+	}
+	if !sim.Energized() {
+		t.Fatal("not energized")
+	}
+	if ok, err := dev.IsPositionUncertain(); err != nil || !ok {
+		t.Fatalf("position uncertain: %t, %v", ok, err)
+	}
+}
+
+func TestLimitSwitches(t *testing.T) {
+	sim, dev := newDev(t)
+	if err := dev.ExitSafeStart(); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.SetTargetVelocity(1000000); err != nil {
+		t.Fatal(err)
+	}
+	sim.Advance(time.Second)
+	sim.SetLimitSwitches(true, false)
+	if ok, err := dev.IsForwardLimitActive(); err != nil || !ok {
+		t.Fatalf("forward limit: %t, %v", ok, err)
+	}
+	p := sim.Position()
+	sim.Advance(time.Second)
+	if sim.Position() != p || sim.Velocity() != 0 {
+		t.Fatalf("moved past the limit: %d", sim.Position())
+	}
+	if err := dev.SetTargetVelocity(-1000000); err != nil {
+		t.Fatal(err)
+	}
+	sim.Advance(time.Second)
+	if sim.Position() >= p {
+		t.Fatal("can't move away from the limit")
+	}
+}
+
+func TestHoming(t *testing.T) {
+	sim, dev := newDev(t)
+	if err := dev.ExitSafeStart(); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.GoHomeReverse(); err != nil {
+		t.Fatal(err)
+	}
+	sim.Advance(time.Second)
+	if ok, err := dev.IsHomingActive(); err != nil || !ok {
+		t.Fatalf("homing: %t, %v", ok, err)
+	}
+	if sim.Position() >= 0 {
+		t.Fatalf("position: %d", sim.Position())
+	}
+	sim.SetLimitSwitches(false, true)
+	if sim.Homing() || sim.Position() != 0 {
+		t.Fatalf("homing: %t, position: %d", sim.Homing(), sim.Position())
+	}
+	if ok, err := dev.IsPositionUncertain(); err != nil || ok {
+		t.Fatalf("position uncertain: %t, %v", ok, err)
+	}
+}
+
+func TestCommandTimeout(t *testing.T) {
+	sim, dev := newDev(t)
+	sim.CommandTimeout = time.Second
+	if err := dev.ExitSafeStart(); err != nil {
+		t.Fatal(err)
+	}
+	sim.Advance(900 * time.Millisecond)
+	if err := dev.ResetCommandTimeout(); err != nil {
+		t.Fatal(err)
+	}
+	sim.Advance(900 * time.Millisecond)
+	if ok, err := dev.HasError(tic.ErrorBitCommandTimeout); err != nil || ok {
+		t.Fatalf("command timeout: %t, %v", ok, err)
+	}
+	sim.Advance(200 * time.Millisecond)
+	if ok, err := dev.HasError(tic.ErrorBitCommandTimeout); err != nil || !ok {
+		t.Fatalf("command timeout: %t, %v", ok, err)
+	}
+	if err := dev.ResetCommandTimeout(); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := dev.HasError(tic.ErrorBitCommandTimeout); err != nil || ok {
+		t.Fatalf("command timeout: %t, %v", ok, err)
+	}
+}
@@ -4,6 +4,14 @@
 
 // Package tm1637 controls a TM1637 device over GPIO pins.
 //
+// The TM1637 drives up to 6 digits of 7 segments LEDs and is found on the
+// ubiquitous cheap 4 digits modules, with or without a clock colon. Its 2-wire
+// protocol resembles I²C without addressing and is bit-banged on any two GPIO
+// pins.
+//
+// Write, WriteAt and SetDigit update the digits, SetColon toggles the colon
+// and WriteClock displays HH:MM.
+//
 // # More details
 //
 // See https://periph.io/device/tm1637/ for more details about the device.
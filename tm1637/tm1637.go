@@ -16,16 +16,23 @@ import (
 )
 
 // Clock converts time to a slice of bytes as segments.
+//
+// Values outside the range [0, 99] are displayed as blank. showDots lights the
+// colon on the common 4 digits clock modules.
 func Clock(hour, minute int, showDots bool) []byte {
 	seg := make([]byte, 4)
-	seg[0] = byte(digitToSegment[hour/10])
-	seg[1] = byte(digitToSegment[hour%10])
-	seg[2] = byte(digitToSegment[minute/10])
-	seg[3] = byte(digitToSegment[minute%10])
+	if hour >= 0 && hour < 100 {
+		seg[0] = digitToSegment[hour/10]
+		seg[1] = digitToSegment[hour%10]
+	}
+	if minute >= 0 && minute < 100 {
+		seg[2] = digitToSegment[minute/10]
+		seg[3] = digitToSegment[minute%10]
+	}
 	if showDots {
-		seg[1] |= 0x80
+		seg[1] |= Colon
 	}
-	return seg[:]
+	return seg
 }
 
 // Digits converts hex numbers to a slice of bytes as segments.
@@ -42,6 +49,10 @@ func Digits(n ...int) []byte {
 	return seg
 }
 
+// Colon is the segment bit driving the colon of 4 digits clock modules when
+// set on the second digit. On other modules, it is the dot of the digit.
+const Colon byte = 0x80
+
 // Brightness defines the screen brightness as controlled by the internal PWM.
 type Brightness uint8
 
@@ -75,6 +86,8 @@ func New(clk gpio.PinOut, data gpio.PinIO) (*Dev, error) {
 type Dev struct {
 	clk  gpio.PinOut
 	data gpio.PinIO
+	// seg is a copy of the display memory, used by SetColon.
+	seg [6]byte
 }
 
 func (d *Dev) String() string {
@@ -105,36 +118,58 @@ func (d *Dev) SetBrightness(b Brightness) error {
 //	E   C
 //	 -D-   P
 func (d *Dev) Write(seg []byte) (int, error) {
-	if len(seg) > 6 {
+	if len(seg) > len(d.seg) {
 		return 0, errors.New("tm1637: up to 6 segment groups are supported")
 	}
-	// This helps reduce jitter a little.
-	runtime.LockOSThread()
-	defer runtime.UnlockOSThread()
-	// Use auto-incrementing address. It is possible to write to a single
-	// segment but there isn't much point.
-	d.start()
-	if _, err := d.writeByte(0x40); err != nil {
+	var b [6]byte
+	copy(b[:], seg)
+	if err := d.writeAt(0, b[:]); err != nil {
 		return 0, err
 	}
-	d.stop()
-	d.start()
-	if _, err := d.writeByte(0xC0); err != nil {
-		return 0, err
+	return len(seg), nil
+}
+
+// WriteAt writes raw segments starting at the digit pos, leaving the other
+// digits untouched.
+//
+// See Write for the encoding of the segments.
+func (d *Dev) WriteAt(pos int, seg []byte) error {
+	if pos < 0 || pos+len(seg) > len(d.seg) {
+		return fmt.Errorf("tm1637: invalid digits [%d, %d); up to 6 segment groups are supported", pos, pos+len(seg))
 	}
-	for i := 0; i < 6; i++ {
-		if len(seg) <= i {
-			if _, err := d.writeByte(0); err != nil {
-				return i, err
-			}
-		} else {
-			if _, err := d.writeByte(seg[i]); err != nil {
-				return i, err
-			}
-		}
+	if len(seg) == 0 {
+		return nil
 	}
-	d.stop()
-	return len(seg), nil
+	return d.writeAt(pos, seg)
+}
+
+// SetDigit displays the hex digit n at pos. Numbers outside the range [0, 15]
+// are displayed as blank.
+//
+// The dot, or the colon if pos is 1 on clock modules, is preserved.
+func (d *Dev) SetDigit(pos, n int) error {
+	if pos < 0 || pos >= len(d.seg) {
+		return fmt.Errorf("tm1637: invalid digit %d", pos)
+	}
+	return d.writeAt(pos, []byte{Digits(n)[0] | d.seg[pos]&Colon})
+}
+
+// SetColon turns the colon of 4 digits clock modules on or off, leaving the
+// digits untouched.
+func (d *Dev) SetColon(on bool) error {
+	b := d.seg[1] &^ Colon
+	if on {
+		b |= Colon
+	}
+	return d.writeAt(1, []byte{b})
+}
+
+// WriteClock displays the time as HH:MM on 4 digits clock modules.
+//
+// It is a shorthand for Write(Clock(hour, minute, colon)).
+func (d *Dev) WriteClock(hour, minute int, colon bool) error {
+	_, err := d.Write(Clock(hour, minute, colon))
+	return err
 }
 
 // Halt turns the display off.
@@ -152,11 +187,42 @@ func (d *Dev) Halt() error {
 // At 250KHz, this is 296µs.
 const clockHalfCycle = time.Second / 250000 / 2
 
+// Commands.
+const (
+	cmdData    byte = 0x40 // Write data to the display memory, auto-increment.
+	cmdAddress byte = 0xC0 // Set the display memory address, OR'ed with [0, 5].
+)
+
 // Hex digits from 0 to F.
 var digitToSegment = []byte{
 	0x3f, 0x06, 0x5b, 0x4f, 0x66, 0x6d, 0x7d, 0x07, 0x7f, 0x6f, 0x77, 0x7c, 0x39, 0x5e, 0x79, 0x71,
 }
 
+// writeAt writes seg to the display memory starting at pos.
+func (d *Dev) writeAt(pos int, seg []byte) error {
+	// This helps reduce jitter a little.
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	// Use auto-incrementing address.
+	d.start()
+	if _, err := d.writeByte(cmdData); err != nil {
+		return err
+	}
+	d.stop()
+	d.start()
+	if _, err := d.writeByte(cmdAddress | byte(pos)); err != nil {
+		return err
+	}
+	for _, b := range seg {
+		if _, err := d.writeByte(b); err != nil {
+			return err
+		}
+	}
+	d.stop()
+	copy(d.seg[pos:], seg)
+	return nil
+}
+
 func (d *Dev) start() {
 	_ = d.data.Out(gpio.Low)
 	d.sleepHalfCycle()
@@ -44,6 +44,77 @@ func TestDigits(t *testing.T) {
 	}
 }
 
+func TestClock(t *testing.T) {
+	if b := Clock(12, 34, true); !bytes.Equal(b, []byte{0x06, 0x5B | Colon, 0x4F, 0x66}) {
+		t.Fatal(b)
+	}
+	if b := Clock(-1, 100, false); !bytes.Equal(b, []byte{0, 0, 0, 0}) {
+		t.Fatal(b)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	dev, bus := newRecorded(t)
+	if _, err := dev.Write(Digits(1, 2)); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.SetBrightness(Brightness14); err != nil {
+		t.Fatal(err)
+	}
+	want := [][]byte{{0x40}, {0xC0, 0x06, 0x5B, 0, 0, 0, 0}, {0x8F}}
+	if !equalFrames(bus.frames, want) {
+		t.Fatalf("%#v != %#v", bus.frames, want)
+	}
+}
+
+func TestWriteAt(t *testing.T) {
+	dev, bus := newRecorded(t)
+	if err := dev.WriteAt(2, []byte{0x3F, 0x06}); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.WriteAt(0, nil); err != nil {
+		t.Fatal(err)
+	}
+	want := [][]byte{{0x40}, {0xC2, 0x3F, 0x06}}
+	if !equalFrames(bus.frames, want) {
+		t.Fatalf("%#v != %#v", bus.frames, want)
+	}
+	if err := dev.WriteAt(5, []byte{0, 0}); err == nil {
+		t.Fatal("out of range")
+	}
+	if err := dev.WriteAt(-1, []byte{0}); err == nil {
+		t.Fatal("out of range")
+	}
+}
+
+func TestSetColon_SetDigit(t *testing.T) {
+	dev, bus := newRecorded(t)
+	if err := dev.WriteClock(9, 5, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.SetColon(true); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.SetDigit(1, 8); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.SetColon(false); err != nil {
+		t.Fatal(err)
+	}
+	want := [][]byte{
+		{0x40}, {0xC0, 0x3F, 0x6F, 0x3F, 0x6D, 0, 0},
+		{0x40}, {0xC1, 0x6F | Colon},
+		{0x40}, {0xC1, 0x7F | Colon},
+		{0x40}, {0xC1, 0x7F},
+	}
+	if !equalFrames(bus.frames, want) {
+		t.Fatalf("%#v != %#v", bus.frames, want)
+	}
+	if err := dev.SetDigit(6, 0); err == nil {
+		t.Fatal("out of range")
+	}
+}
+
 func TestNew_clk_fail(t *testing.T) {
 	clk := failPin{fail: true}
 	data := gpiotest.Pin{}
@@ -72,6 +143,83 @@ func TestWrite_fail(t *testing.T) {
 
 //
 
+// bus decodes the TM1637 protocol from the pin transitions.
+type bus struct {
+	clk, data gpio.Level
+	frames    [][]byte
+	bits      int
+}
+
+func (b *bus) setClk(l gpio.Level) {
+	if l && !b.clk && len(b.frames) != 0 {
+		// Sample data on the rising edge; the 9th clock is the ACK.
+		if b.bits%9 == 0 {
+			b.frames[len(b.frames)-1] = append(b.frames[len(b.frames)-1], 0)
+		}
+		if i := b.bits % 9; i < 8 && b.data {
+			f := b.frames[len(b.frames)-1]
+			f[len(f)-1] |= 1 << uint(i)
+		}
+		b.bits++
+	}
+	b.clk = l
+}
+
+func (b *bus) setData(l gpio.Level) {
+	if b.clk && b.data && !l {
+		// Start condition.
+		b.frames = append(b.frames, nil)
+		b.bits = 0
+	}
+	if b.clk && !b.data && l && len(b.frames) != 0 && b.bits%9 == 1 {
+		// Stop condition; drop the byte started by its clock edge.
+		f := b.frames[len(b.frames)-1]
+		b.frames[len(b.frames)-1] = f[:len(f)-1]
+	}
+	b.data = l
+}
+
+type clkPin struct {
+	gpiotest.Pin
+	b *bus
+}
+
+func (c *clkPin) Out(l gpio.Level) error {
+	c.b.setClk(l)
+	return nil
+}
+
+type dataPin struct {
+	gpiotest.Pin
+	b *bus
+}
+
+func (d *dataPin) Out(l gpio.Level) error {
+	d.b.setData(l)
+	return nil
+}
+
+func newRecorded(t *testing.T) (*Dev, *bus) {
+	b := &bus{}
+	dev, err := New(&clkPin{b: b}, &dataPin{b: b})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dev, b
+}
+
+func equalFrames(a, b [][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !bytes.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
 type failPin struct {
 	gpiotest.Pin
 	fail bool
@@ -0,0 +1,29 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package tmc2209 controls a Trinamic TMC2209 stepper motor driver over its
+// single wire UART interface.
+//
+// The UART configures the driver: chopper mode, motor current, microsteps
+// and StallGuard. The motor is moved either with STEP/DIR pins, see
+// NewStepDir, or with the internal step generator, see SetVelocity.
+//
+// # Wiring
+//
+// The PDN_UART pin is both the receive and the transmit line. Connect it to
+// RX and, through a 1kΩ resistor, to TX of the host. The host then receives
+// the bytes it sends, set Opts.Echo accordingly. Up to four drivers can share
+// the line, their address is selected with the MS1 and MS2 pins.
+//
+// # Sensorless homing
+//
+// StallGuard compares the load of the motor to the threshold set with
+// SetStallThreshold when running in stealthChop above the speed set with
+// SetCoolStepThreshold. The DIAG pin is pulsed on a stall, StallGuard returns
+// the measured load, lower meaning a higher load.
+//
+// # Datasheet
+//
+// https://www.analog.com/media/en/technical-documentation/data-sheets/TMC2209_datasheet_rev1.09.pdf
+package tmc2209
@@ -0,0 +1,512 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package tmc2209
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/uart"
+	"periph.io/x/devices/v3/stepper"
+)
+
+// Baud is the default UART speed. The driver detects the speed, from 9600 to
+// 500k bauds.
+const Baud = 115200 * physic.Hertz
+
+// ChopperMode is the current regulation mode.
+type ChopperMode int
+
+const (
+	// StealthChop is the quiet voltage chopper mode. StallGuard only works in
+	// this mode.
+	StealthChop ChopperMode = iota
+	// SpreadCycle is the current chopper mode, for high speed and high torque.
+	SpreadCycle
+)
+
+func (m ChopperMode) String() string {
+	switch m {
+	case StealthChop:
+		return "StealthChop"
+	case SpreadCycle:
+		return "SpreadCycle"
+	default:
+		return "ChopperMode(?)"
+	}
+}
+
+// Opts holds the configuration options.
+type Opts struct {
+	// Addr is the node address, from 0 to 3, set by the MS1 and MS2 pins.
+	Addr byte
+	// RSense is the value of the sense resistors. 0 means 110mΩ, the value
+	// used on most modules.
+	RSense physic.ElectricResistance
+	// Echo must be true when the bytes sent are received back, which is the
+	// case when RX and TX are tied together on the single wire interface.
+	Echo bool
+	// Chopper is the initial chopper mode.
+	Chopper ChopperMode
+}
+
+// DefaultOpts is the recommended default options.
+var DefaultOpts = Opts{
+	RSense: 110 * physic.MilliOhm,
+	Echo:   true,
+}
+
+// Status is the driver status.
+type Status struct {
+	// Standstill is true when no step was received for 2^20 clocks.
+	Standstill bool
+	// StealthChop is true when the driver runs in stealthChop.
+	StealthChop bool
+	// CurrentScale is the actual current scale, from 0 to 31.
+	CurrentScale uint8
+	// OverTemperature is true when the driver shut down because of its
+	// temperature.
+	OverTemperature bool
+	// OverTemperatureWarning is true when the prewarning threshold is reached.
+	OverTemperatureWarning bool
+	// ShortToGround and ShortToSupply are true when a short was detected on a
+	// coil. The driver is disabled until it is deenergized.
+	ShortToGround bool
+	ShortToSupply bool
+	// OpenLoad is true when a coil appears disconnected. It is only reliable
+	// at low speed in spreadCycle.
+	OpenLoad bool
+}
+
+// Dev is a handle to a TMC2209.
+type Dev struct {
+	mu       sync.Mutex
+	c        conn.Conn
+	opts     Opts
+	gconf    uint32
+	chopconf uint32
+	irun     uint8
+}
+
+// NewUART opens a handle to the device connected to p.
+func NewUART(p uart.Port, opts *Opts) (*Dev, error) {
+	c, err := p.Connect(Baud, uart.One, uart.NoParity, uart.NoFlow, 8)
+	if err != nil {
+		return nil, err
+	}
+	return New(c, opts)
+}
+
+// New opens a handle to the device on an already configured connection.
+//
+// It checks the version of the chip, clears the reset flags and gives the
+// control of the microsteps and the current to the UART.
+func New(c conn.Conn, opts *Opts) (*Dev, error) {
+	if opts.Addr > 3 {
+		return nil, errors.New("tmc2209: invalid address")
+	}
+	d := &Dev{c: c, opts: *opts}
+	if d.opts.RSense == 0 {
+		d.opts.RSense = DefaultOpts.RSense
+	}
+	ioin, err := d.readReg(regIOIN)
+	if err != nil {
+		return nil, err
+	}
+	if v := ioin >> 24; v != version {
+		return nil, fmt.Errorf("tmc2209: unexpected version %#x", v)
+	}
+	if err := d.writeReg(regGSTAT, 0x07); err != nil {
+		return nil, err
+	}
+	if d.chopconf, err = d.readReg(regCHOPCONF); err != nil {
+		return nil, err
+	}
+	gconf := uint32(gconfPDNDisable | gconfMstepRegSelect | gconfMultistepFilt)
+	if opts.Chopper == SpreadCycle {
+		gconf |= gconfSpreadCycle
+	}
+	if err := d.setGCONF(gconf); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *Dev) String() string {
+	return fmt.Sprintf("TMC2209{%s, %d}", d.c, d.opts.Addr)
+}
+
+// ReadRegister reads the 32 bits register reg.
+func (d *Dev) ReadRegister(reg byte) (uint32, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.readReg(reg)
+}
+
+// WriteRegister writes the 32 bits register reg.
+//
+// Changing GCONF or CHOPCONF this way is not reflected by the other methods.
+func (d *Dev) WriteRegister(reg byte, v uint32) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.writeReg(reg, v)
+}
+
+// SetChopperMode sets the chopper mode.
+func (d *Dev) SetChopperMode(m ChopperMode) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	switch m {
+	case StealthChop:
+		return d.setGCONF(d.gconf &^ gconfSpreadCycle)
+	case SpreadCycle:
+		return d.setGCONF(d.gconf | gconfSpreadCycle)
+	default:
+		return errors.New("tmc2209: invalid chopper mode")
+	}
+}
+
+// SetStealthChopThreshold sets the step interval, in 1/12MHz units as
+// reported by TSTEP, below which the driver switches from stealthChop to
+// spreadCycle. 0 disables the switch.
+func (d *Dev) SetStealthChopThreshold(tstep uint32) error {
+	if tstep >= 1<<20 {
+		return errors.New("tmc2209: invalid threshold")
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.writeReg(regTPWMTHRS, tstep)
+}
+
+// SetInverted inverts the direction of the motor.
+func (d *Dev) SetInverted(invert bool) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if invert {
+		return d.setGCONF(d.gconf | gconfShaft)
+	}
+	return d.setGCONF(d.gconf &^ gconfShaft)
+}
+
+// SetMicrostep sets the number of microsteps per full step, a power of two
+// from 1 to 256. The steps are interpolated to 256 microsteps.
+func (d *Dev) SetMicrostep(n int) error {
+	mres := -1
+	for i := 0; i <= 8; i++ {
+		if n == 256>>i {
+			mres = i
+		}
+	}
+	if mres < 0 {
+		return fmt.Errorf("tmc2209: invalid microsteps %d", n)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.setCHOPCONF(d.chopconf&^chopconfMRESMask | uint32(mres)<<chopconfMRESShift | chopconfIntpol)
+}
+
+// Microstep returns the number of microsteps per full step.
+func (d *Dev) Microstep() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return 256 >> ((d.chopconf & chopconfMRESMask) >> chopconfMRESShift)
+}
+
+// SetCurrent sets the RMS motor current while running and at standstill.
+//
+// The current is scaled in 32 steps, the sensitive sense range is used for
+// low currents to keep the resolution.
+func (d *Dev) SetCurrent(run, hold physic.ElectricCurrent) error {
+	if run <= 0 || hold < 0 || hold > run {
+		return errors.New("tmc2209: invalid current")
+	}
+	r := float64(d.opts.RSense) / float64(physic.Ohm)
+	vsense := false
+	cs := currentScale(run, r, vfsHigh)
+	if cs < 16 {
+		vsense = true
+		cs = currentScale(run, r, vfsLow)
+	}
+	if cs > 31.5 {
+		return fmt.Errorf("tmc2209: current %s too high", run)
+	}
+	vfs := vfsHigh
+	if vsense {
+		vfs = vfsLow
+	}
+	irun := clampScale(cs)
+	ihold := clampScale(currentScale(hold, r, vfs))
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	c := d.chopconf &^ chopconfVsense
+	if vsense {
+		c |= chopconfVsense
+	}
+	if err := d.setCHOPCONF(c); err != nil {
+		return err
+	}
+	if err := d.writeReg(regIHOLDIRUN, uint32(ihold)|uint32(irun)<<8|holdDelay<<16); err != nil {
+		return err
+	}
+	d.irun = irun
+	return nil
+}
+
+// Current returns the RMS motor current while running, as set by SetCurrent.
+func (d *Dev) Current() physic.ElectricCurrent {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	vfs := vfsHigh
+	if d.chopconf&chopconfVsense != 0 {
+		vfs = vfsLow
+	}
+	r := float64(d.opts.RSense) / float64(physic.Ohm)
+	i := (float64(d.irun) + 1) / 32 * vfs / (r + 0.02) / math.Sqrt2
+	return physic.ElectricCurrent(i*float64(physic.Ampere) + 0.5)
+}
+
+// SetStallThreshold sets the StallGuard threshold. A stall is signaled on
+// DIAG when StallGuard falls below twice the threshold, so a higher value is
+// more sensitive.
+func (d *Dev) SetStallThreshold(sgthrs uint8) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.writeReg(regSGTHRS, uint32(sgthrs))
+}
+
+// SetCoolStepThreshold sets the step interval, in 1/12MHz units as reported
+// by TSTEP, above which CoolStep and the stall output are disabled. Set it
+// to the interval at the homing speed, or slightly above, to home.
+func (d *Dev) SetCoolStepThreshold(tstep uint32) error {
+	if tstep >= 1<<20 {
+		return errors.New("tmc2209: invalid threshold")
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.writeReg(regTCOOLTHRS, tstep)
+}
+
+// StallGuard returns the StallGuard result, from 0 to 510. It is updated on
+// every full step, lower values mean a higher load.
+func (d *Dev) StallGuard() (uint16, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	v, err := d.readReg(regSGRESULT)
+	return uint16(v & 0x3FF), err
+}
+
+// Status returns the driver status.
+func (d *Dev) Status() (Status, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	v, err := d.readReg(regDRVSTATUS)
+	if err != nil {
+		return Status{}, err
+	}
+	return Status{
+		Standstill:             v&(1<<31) != 0,
+		StealthChop:            v&(1<<30) != 0,
+		CurrentScale:           uint8(v>>16) & 0x1F,
+		OverTemperature:        v&(1<<1) != 0,
+		OverTemperatureWarning: v&(1<<0) != 0,
+		ShortToGround:          v&(3<<2) != 0,
+		ShortToSupply:          v&(3<<4) != 0,
+		OpenLoad:               v&(3<<6) != 0,
+	}, nil
+}
+
+// SetVelocity moves the motor with the internal step generator, in
+// microsteps per second. The sign is the direction. 0 stops the motor and
+// gives the control back to the STEP input.
+func (d *Dev) SetVelocity(v int32) error {
+	vactual := int64(math.Round(float64(v) * (1 << 24) / fCLK))
+	if vactual >= 1<<23 || vactual < -(1<<23) {
+		return errors.New("tmc2209: invalid velocity")
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.writeReg(regVACTUAL, uint32(vactual)&0xFFFFFF)
+}
+
+// NewStepDir returns a stepper.Stepper moving the motor with the STEP and
+// DIR pins in opts.
+//
+// The microsteps are set with SetMicrostep, opts must not specify Mode pins.
+// The positions and speeds are in microsteps.
+func (d *Dev) NewStepDir(opts *stepper.Opts) (*stepper.StepDir, error) {
+	for _, p := range opts.Mode {
+		if p != nil {
+			return nil, errors.New("tmc2209: microsteps are set over UART")
+		}
+	}
+	return stepper.NewStepDir(opts)
+}
+
+// Halt stops the internal step generator.
+//
+// Halt implements conn.Resource.
+func (d *Dev) Halt() error {
+	return d.SetVelocity(0)
+}
+
+//
+
+// Registers.
+const (
+	regGCONF     = 0x00
+	regGSTAT     = 0x01
+	regIFCNT     = 0x02
+	regIOIN      = 0x06
+	regIHOLDIRUN = 0x10
+	regTPWMTHRS  = 0x13
+	regTCOOLTHRS = 0x14
+	regVACTUAL   = 0x22
+	regSGTHRS    = 0x40
+	regSGRESULT  = 0x41
+	regCHOPCONF  = 0x6C
+	regDRVSTATUS = 0x6F
+
+	version = 0x21
+)
+
+// GCONF bits.
+const (
+	gconfSpreadCycle    = 1 << 2
+	gconfShaft          = 1 << 3
+	gconfPDNDisable     = 1 << 6
+	gconfMstepRegSelect = 1 << 7
+	gconfMultistepFilt  = 1 << 8
+)
+
+// CHOPCONF bits.
+const (
+	chopconfVsense    = 1 << 17
+	chopconfMRESShift = 24
+	chopconfMRESMask  = 0xF << chopconfMRESShift
+	chopconfIntpol    = 1 << 28
+)
+
+const (
+	syncByte  = 0x05
+	writeBit  = 0x80
+	replyAddr = 0xFF
+
+	// fCLK is the internal clock frequency in Hz.
+	fCLK = 12e6
+	// vfsHigh and vfsLow are the full scale sense voltages with vsense 0 and
+	// 1.
+	vfsHigh = 0.325
+	vfsLow  = 0.180
+	// holdDelay is the number of 2^18 clocks steps to reduce the current to
+	// the hold current after standstill.
+	holdDelay = 1
+)
+
+// currentScale returns the unrounded current scale for the RMS current i.
+func currentScale(i physic.ElectricCurrent, r, vfs float64) float64 {
+	return 32*math.Sqrt2*float64(i)/float64(physic.Ampere)*(r+0.02)/vfs - 1
+}
+
+func clampScale(cs float64) uint8 {
+	return uint8(math.Max(0, math.Min(31, math.Round(cs))))
+}
+
+func (d *Dev) setGCONF(v uint32) error {
+	if err := d.writeReg(regGCONF, v); err != nil {
+		return err
+	}
+	d.gconf = v
+	return nil
+}
+
+func (d *Dev) setCHOPCONF(v uint32) error {
+	if err := d.writeReg(regCHOPCONF, v); err != nil {
+		return err
+	}
+	d.chopconf = v
+	return nil
+}
+
+func (d *Dev) readReg(reg byte) (uint32, error) {
+	w := []byte{syncByte, d.opts.Addr, reg, 0}
+	w[3] = crc8(w[:3])
+	if err := d.send(w); err != nil {
+		return 0, err
+	}
+	var r [8]byte
+	if err := d.c.Tx(nil, r[:]); err != nil {
+		return 0, err
+	}
+	if r[7] != crc8(r[:7]) {
+		return 0, errors.New("tmc2209: invalid CRC")
+	}
+	if r[0] != syncByte || r[1] != replyAddr || r[2] != reg {
+		return 0, errors.New("tmc2209: unexpected reply")
+	}
+	return binary.BigEndian.Uint32(r[3:]), nil
+}
+
+// writeReg writes reg and checks that the write was acknowledged by the
+// interface transmission counter.
+func (d *Dev) writeReg(reg byte, v uint32) error {
+	before, err := d.readReg(regIFCNT)
+	if err != nil {
+		return err
+	}
+	w := []byte{syncByte, d.opts.Addr, reg | writeBit, 0, 0, 0, 0, 0}
+	binary.BigEndian.PutUint32(w[3:], v)
+	w[7] = crc8(w[:7])
+	if err := d.send(w); err != nil {
+		return err
+	}
+	after, err := d.readReg(regIFCNT)
+	if err != nil {
+		return err
+	}
+	if byte(after) != byte(before+1) {
+		return fmt.Errorf("tmc2209: write to %#x not acknowledged", reg)
+	}
+	return nil
+}
+
+// send writes w and consumes the echo if any.
+func (d *Dev) send(w []byte) error {
+	if !d.opts.Echo {
+		return d.c.Tx(w, nil)
+	}
+	e := make([]byte, len(w))
+	if err := d.c.Tx(w, e); err != nil {
+		return err
+	}
+	if !bytes.Equal(w, e) {
+		return errors.New("tmc2209: unexpected echo")
+	}
+	return nil
+}
+
+// crc8 is the CRC of the datagrams, polynomial x^8+x^2+x+1 with the bits of
+// each byte processed LSB first.
+func crc8(b []byte) byte {
+	var crc byte
+	for _, c := range b {
+		for i := 0; i < 8; i++ {
+			if (crc>>7)^(c&1) != 0 {
+				crc = crc<<1 ^ 0x07
+			} else {
+				crc <<= 1
+			}
+			c >>= 1
+		}
+	}
+	return crc
+}
+
+var _ conn.Resource = &Dev{}
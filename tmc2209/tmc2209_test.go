@@ -0,0 +1,282 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package tmc2209
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/gpio/gpiotest"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/devices/v3/stepper"
+)
+
+// fakeUART emulates the registers of a TMC2209 on a single wire interface.
+type fakeUART struct {
+	regs  map[byte]uint32
+	echo  bool
+	ifcnt byte
+	// dropWrites ignores the write datagrams, like on a bad connection.
+	dropWrites bool
+	reply      []byte
+}
+
+func newFakeUART(echo bool) *fakeUART {
+	return &fakeUART{
+		regs: map[byte]uint32{regIOIN: version << 24, regCHOPCONF: 0x10000053},
+		echo: echo,
+	}
+}
+
+func (f *fakeUART) String() string {
+	return "fake"
+}
+
+func (f *fakeUART) Duplex() conn.Duplex {
+	return conn.Half
+}
+
+func (f *fakeUART) Tx(w, r []byte) error {
+	if w == nil {
+		if len(r) != len(f.reply) {
+			return errors.New("unexpected read")
+		}
+		copy(r, f.reply)
+		f.reply = nil
+		return nil
+	}
+	if (len(w) != 4 && len(w) != 8) || w[0] != syncByte || w[len(w)-1] != crc8(w[:len(w)-1]) {
+		return errors.New("invalid datagram")
+	}
+	if f.echo {
+		if len(r) != len(w) {
+			return errors.New("echo not read")
+		}
+		copy(r, w)
+	} else if r != nil {
+		return errors.New("unexpected read")
+	}
+	if len(w) == 8 {
+		if !f.dropWrites {
+			f.regs[w[2]&^writeBit] = binary.BigEndian.Uint32(w[3:])
+			f.ifcnt++
+		}
+		return nil
+	}
+	v := f.regs[w[2]]
+	if w[2] == regIFCNT {
+		v = uint32(f.ifcnt)
+	}
+	f.reply = []byte{syncByte, replyAddr, w[2], 0, 0, 0, 0, 0}
+	binary.BigEndian.PutUint32(f.reply[3:], v)
+	f.reply[7] = crc8(f.reply[:7])
+	return nil
+}
+
+func TestCRC(t *testing.T) {
+	if c := crc8([]byte{0x05, 0x00, 0x00}); c != 0x48 {
+		t.Fatalf("%#x", c)
+	}
+}
+
+func TestNew(t *testing.T) {
+	for _, echo := range []bool{false, true} {
+		f := newFakeUART(echo)
+		d, err := New(f, &Opts{Echo: echo, Chopper: SpreadCycle})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s := d.String(); s != "TMC2209{fake, 0}" {
+			t.Fatal(s)
+		}
+		if v := f.regs[regGCONF]; v != 0x1C4 {
+			t.Fatalf("%#x", v)
+		}
+		if v := f.regs[regGSTAT]; v != 7 {
+			t.Fatalf("%#x", v)
+		}
+		if d.Microstep() != 256 {
+			t.Fatal(d.Microstep())
+		}
+	}
+	f := newFakeUART(true)
+	f.regs[regIOIN] = 0x20 << 24
+	if _, err := New(f, &DefaultOpts); err == nil {
+		t.Fatal("expected error on version")
+	}
+	if _, err := New(newFakeUART(false), &DefaultOpts); err == nil {
+		t.Fatal("expected error without echo")
+	}
+	if _, err := New(newFakeUART(true), &Opts{Addr: 4}); err == nil {
+		t.Fatal("expected error on address")
+	}
+	f = newFakeUART(true)
+	f.dropWrites = true
+	if _, err := New(f, &DefaultOpts); err == nil {
+		t.Fatal("expected error on unacknowledged write")
+	}
+}
+
+func TestConfig(t *testing.T) {
+	f := newFakeUART(true)
+	d, err := New(f, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetChopperMode(SpreadCycle); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetInverted(true); err != nil {
+		t.Fatal(err)
+	}
+	if v := f.regs[regGCONF]; v != 0x1CC {
+		t.Fatalf("%#x", v)
+	}
+	if err := d.SetChopperMode(StealthChop); err != nil {
+		t.Fatal(err)
+	}
+	if v := f.regs[regGCONF]; v != 0x1C8 {
+		t.Fatalf("%#x", v)
+	}
+	if err := d.SetMicrostep(3); err == nil {
+		t.Fatal("expected error")
+	}
+	if err := d.SetMicrostep(16); err != nil {
+		t.Fatal(err)
+	}
+	if v := f.regs[regCHOPCONF]; v != 0x14000053 {
+		t.Fatalf("%#x", v)
+	}
+	if d.Microstep() != 16 {
+		t.Fatal(d.Microstep())
+	}
+	if err := d.SetCurrent(800*physic.MilliAmpere, 400*physic.MilliAmpere); err != nil {
+		t.Fatal(err)
+	}
+	// 0.8A needs the low sense range with 110mΩ.
+	if v := f.regs[regCHOPCONF]; v != 0x14020053 {
+		t.Fatalf("%#x", v)
+	}
+	if v := f.regs[regIHOLDIRUN]; v != 0x1190C {
+		t.Fatalf("%#x", v)
+	}
+	if i := d.Current(); i < 795*physic.MilliAmpere || i > 796*physic.MilliAmpere {
+		t.Fatal(i)
+	}
+	if err := d.SetCurrent(3*physic.Ampere, 0); err == nil {
+		t.Fatal("expected error")
+	}
+	if err := d.SetStealthChopThreshold(1 << 20); err == nil {
+		t.Fatal("expected error")
+	}
+	if err := d.SetStealthChopThreshold(500); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetVelocity(-1000); err != nil {
+		t.Fatal(err)
+	}
+	if v := f.regs[regVACTUAL]; v != 0xFFFA8A {
+		t.Fatalf("%#x", v)
+	}
+	if err := d.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	if v := f.regs[regVACTUAL]; v != 0 {
+		t.Fatalf("%#x", v)
+	}
+}
+
+func TestStallGuard(t *testing.T) {
+	f := newFakeUART(true)
+	d, err := New(f, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetStallThreshold(50); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetCoolStepThreshold(0xFFFFF); err != nil {
+		t.Fatal(err)
+	}
+	if f.regs[regSGTHRS] != 50 || f.regs[regTCOOLTHRS] != 0xFFFFF {
+		t.Fatal(f.regs)
+	}
+	f.regs[regSGRESULT] = 0x123
+	if v, err := d.StallGuard(); err != nil || v != 0x123 {
+		t.Fatal(v, err)
+	}
+	f.regs[regDRVSTATUS] = 1<<31 | 0x1F<<16 | 1<<3 | 1<<0
+	s, err := d.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Status{Standstill: true, CurrentScale: 31, ShortToGround: true, OverTemperatureWarning: true}); s != want {
+		t.Fatalf("%+v", s)
+	}
+}
+
+func TestReadRegister_CRC(t *testing.T) {
+	f := newFakeUART(false)
+	d, err := New(f, &Opts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.WriteRegister(0x70, 0xC10D0024); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := d.ReadRegister(0x70); err != nil || v != 0xC10D0024 {
+		t.Fatal(v, err)
+	}
+	b := &badCRC{fakeUART: f}
+	d.c = b
+	if _, err := d.ReadRegister(0x70); err == nil {
+		t.Fatal("expected CRC error")
+	}
+}
+
+// badCRC corrupts the replies.
+type badCRC struct {
+	*fakeUART
+}
+
+func (b *badCRC) Tx(w, r []byte) error {
+	if err := b.fakeUART.Tx(w, r); err != nil {
+		return err
+	}
+	if w == nil {
+		r[7] ^= 1
+	}
+	return nil
+}
+
+func TestNewStepDir(t *testing.T) {
+	d, err := New(newFakeUART(true), &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := stepper.Opts{Step: &gpiotest.Pin{N: "STEP"}, Dir: &gpiotest.Pin{N: "DIR"}, MaxSpeed: 1000}
+	s, err := d.NewStepDir(&opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	opts.Mode[0] = &gpiotest.Pin{}
+	if _, err := d.NewStepDir(&opts); err == nil {
+		t.Fatal("expected error with mode pins")
+	}
+}
+
+func TestChopperMode_String(t *testing.T) {
+	if s := SpreadCycle.String(); s != "SpreadCycle" {
+		t.Fatal(s)
+	}
+	if s := ChopperMode(5).String(); s != "ChopperMode(?)" {
+		t.Fatal(s)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"periph.io/x/conn/v3"
 	"periph.io/x/conn/v3/i2c"
 	"periph.io/x/conn/v3/physic"
+	"periph.io/x/devices/v3"
 )
 
 type ConversionRate byte
@@ -25,6 +26,9 @@ type Dev struct {
 	shutdown chan bool
 	mu       sync.Mutex
 	opts     *Opts
+	// Communication health, reported by the devices.Health methods. The
+	// TMP102 replies have no CRC.
+	health devices.HealthCounter
 }
 
 const (
@@ -97,7 +101,7 @@ func (dev *Dev) start() error {
 	w[1] = byte(config>>8) & 0xff
 	w[2] = byte(config & 0xff)
 
-	err := dev.d.Tx(w, nil)
+	err := dev.tx(w, nil)
 	if err != nil {
 		return err
 	}
@@ -110,7 +114,7 @@ func (dev *Dev) start() error {
 		w[0] = _REGISTER_RANGE_LOW
 		w[1] = bits[0]
 		w[2] = bits[1]
-		err = dev.d.Tx(w, nil)
+		err = dev.tx(w, nil)
 		if err != nil {
 			return err
 		}
@@ -124,7 +128,7 @@ func (dev *Dev) start() error {
 		w[0] = _REGISTER_RANGE_HIGH
 		w[1] = bits[0]
 		w[2] = bits[1]
-		err = dev.d.Tx(w, nil)
+		err = dev.tx(w, nil)
 	}
 	return err
 }
@@ -186,7 +190,7 @@ func (dev *Dev) ReadConfiguration() uint16 {
 	w := make([]byte, 1)
 	w[0] = _REGISTER_CONFIGURATION
 	r := make([]byte, 2)
-	_ = dev.d.Tx(w, r)
+	_ = dev.tx(w, r)
 	result := uint16(r[0])<<8 | uint16(r[1])
 
 	return result
@@ -202,7 +206,7 @@ func (dev *Dev) readTemperature() (physic.Temperature, error) {
 		}
 	}
 	r := make([]byte, 2)
-	err = dev.d.Tx([]byte{_REGISTER_TEMPERATURE}, r)
+	err = dev.tx([]byte{_REGISTER_TEMPERATURE}, r)
 	if err != nil {
 		return MinimumTemperature, err
 	}
@@ -233,14 +237,14 @@ func (dev *Dev) GetAlertMode() (mode AlertMode, rangeLow, rangeHigh physic.Tempe
 	r := make([]byte, 2)
 
 	w[0] = _REGISTER_RANGE_LOW
-	err = dev.d.Tx(w, r)
+	err = dev.tx(w, r)
 	if err != nil {
 		return
 	}
 	rangeLow = countToTemperature(r)
 
 	w[0] = _REGISTER_RANGE_HIGH
-	err = dev.d.Tx(w, r)
+	err = dev.tx(w, r)
 	if err != nil {
 		return
 	}
@@ -268,7 +272,7 @@ func (dev *Dev) Halt() error {
 		w[0] = _REGISTER_CONFIGURATION
 		w[1] = byte(new >> 8)
 		w[2] = byte(new & 0xff)
-		err = dev.d.Tx(w, nil)
+		err = dev.tx(w, nil)
 	}
 
 	return err
@@ -344,7 +348,7 @@ func (dev *Dev) SetAlertMode(mode AlertMode, rangeLow, rangeHigh physic.Temperat
 	w[0] = _REGISTER_RANGE_LOW
 	w[1] = rangeBytes[0]
 	w[2] = rangeBytes[1]
-	err = dev.d.Tx(w, nil)
+	err = dev.tx(w, nil)
 	if err != nil {
 		return err
 	}
@@ -353,7 +357,7 @@ func (dev *Dev) SetAlertMode(mode AlertMode, rangeLow, rangeHigh physic.Temperat
 	w[0] = _REGISTER_RANGE_HIGH
 	w[1] = rangeBytes[0]
 	w[2] = rangeBytes[1]
-	err = dev.d.Tx(w, nil)
+	err = dev.tx(w, nil)
 	if err != nil {
 		return err
 	}
@@ -366,7 +370,7 @@ func (dev *Dev) SetAlertMode(mode AlertMode, rangeLow, rangeHigh physic.Temperat
 		w[0] = _REGISTER_CONFIGURATION
 		w[1] = byte(new >> 8)
 		w[2] = byte(new & 0xff)
-		err = dev.d.Tx(w, nil)
+		err = dev.tx(w, nil)
 	}
 
 	return err
@@ -385,5 +389,26 @@ func (dev *Dev) String() string {
 	return fmt.Sprintf("tmp102: %s", dev.d.String())
 }
 
+// LastError implements devices.Health.
+func (dev *Dev) LastError() error {
+	return dev.health.LastError()
+}
+
+// TxCount implements devices.Health.
+func (dev *Dev) TxCount() uint64 {
+	return dev.health.TxCount()
+}
+
+// CRCErrorCount implements devices.Health. It is always 0.
+func (dev *Dev) CRCErrorCount() uint64 {
+	return dev.health.CRCErrorCount()
+}
+
+// tx runs a transaction on the bus and records it.
+func (dev *Dev) tx(w, r []byte) error {
+	return dev.health.Record(dev.d.Tx(w, r))
+}
+
 var _ conn.Resource = &Dev{}
 var _ physic.SenseEnv = &Dev{}
+var _ devices.Health = &Dev{}
@@ -0,0 +1,21 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package tsl2591 controls an AMS TSL2591 high dynamic range ambient light
+// sensor over I²C.
+//
+// The sensor has two photodiodes, one sensitive to the full spectrum and one
+// to infrared only. Channels returns their raw counts and Illuminance the
+// lux computed from both.
+//
+// # Interrupts
+//
+// The INT pin is asserted when the full spectrum channel goes out of the
+// thresholds set with SetThresholds for a number of consecutive cycles, or
+// immediately for the ones set with SetNoPersistThresholds.
+//
+// # Datasheet
+//
+// https://ams.com/documents/20143/36005/TSL2591_DS000338_6-00.pdf
+package tsl2591
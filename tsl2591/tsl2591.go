@@ -0,0 +1,329 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package tsl2591
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/physic"
+)
+
+// I2CAddr is the only I²C address of the device.
+const I2CAddr uint16 = 0x29
+
+// ErrOverflow is returned by Illuminance when a channel is saturated. Lower
+// the gain or the integration time.
+var ErrOverflow = errors.New("tsl2591: channel saturated")
+
+// Gain is the gain of the ALS amplifier.
+type Gain byte
+
+const (
+	GainLow    Gain = 0x00 // 1x
+	GainMedium Gain = 0x10 // 25x
+	GainHigh   Gain = 0x20 // 428x
+	GainMax    Gain = 0x30 // 9876x
+)
+
+func (g Gain) String() string {
+	switch g {
+	case GainLow:
+		return "1x"
+	case GainMedium:
+		return "25x"
+	case GainHigh:
+		return "428x"
+	case GainMax:
+		return "9876x"
+	default:
+		return "Gain(?)"
+	}
+}
+
+// factor returns the typical gain, relative to GainLow.
+func (g Gain) factor() float64 {
+	switch g {
+	case GainMedium:
+		return 25
+	case GainHigh:
+		return 428
+	case GainMax:
+		return 9876
+	default:
+		return 1
+	}
+}
+
+// IntegrationTime is the ALS integration time.
+type IntegrationTime byte
+
+const (
+	Integration100ms IntegrationTime = 0
+	Integration200ms IntegrationTime = 1
+	Integration300ms IntegrationTime = 2
+	Integration400ms IntegrationTime = 3
+	Integration500ms IntegrationTime = 4
+	Integration600ms IntegrationTime = 5
+)
+
+// Duration returns the integration time as a time.Duration.
+func (i IntegrationTime) Duration() time.Duration {
+	return time.Duration(i+1) * 100 * time.Millisecond
+}
+
+func (i IntegrationTime) String() string {
+	return i.Duration().String()
+}
+
+// Opts holds the configuration options.
+type Opts struct {
+	Gain        Gain
+	Integration IntegrationTime
+}
+
+// DefaultOpts is the recommended default options.
+var DefaultOpts = Opts{
+	Gain:        GainMedium,
+	Integration: Integration100ms,
+}
+
+// Dev is a handle to a TSL2591.
+type Dev struct {
+	mu     sync.Mutex
+	c      i2c.Dev
+	gain   Gain
+	atime  IntegrationTime
+	enable byte
+}
+
+// NewI2C opens a handle to the device, applies opts and starts the ALS.
+func NewI2C(bus i2c.Bus, opts *Opts) (*Dev, error) {
+	d := &Dev{c: i2c.Dev{Bus: bus, Addr: I2CAddr}}
+	id, err := d.readReg(regID)
+	if err != nil {
+		return nil, err
+	}
+	if id != deviceID {
+		return nil, fmt.Errorf("tsl2591: unexpected device ID %#x", id)
+	}
+	if err := d.setConfig(opts.Gain, opts.Integration); err != nil {
+		return nil, err
+	}
+	if err := d.setEnable(enablePON | enableAEN); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *Dev) String() string {
+	return fmt.Sprintf("TSL2591{%s}", &d.c)
+}
+
+// SetGain sets the gain of the ALS amplifier.
+func (d *Dev) SetGain(g Gain) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.setConfig(g, d.atime)
+}
+
+// SetIntegrationTime sets the ALS integration time.
+func (d *Dev) SetIntegrationTime(i IntegrationTime) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.setConfig(d.gain, i)
+}
+
+// Channels returns the raw counts of the full spectrum and infrared channels.
+func (d *Dev) Channels() (full, ir uint16, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.channels()
+}
+
+// Illuminance returns the illuminance in lux, as lumen per square metre.
+//
+// It returns ErrOverflow when a channel is saturated.
+func (d *Dev) Illuminance() (physic.LuminousFlux, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	full, ir, err := d.channels()
+	if err != nil {
+		return 0, err
+	}
+	if full == 0xFFFF || ir == 0xFFFF {
+		return 0, ErrOverflow
+	}
+	if full == 0 {
+		return 0, nil
+	}
+	// Counts per lux.
+	cpl := float64(d.atime.Duration()/time.Millisecond) * d.gain.factor() / luxDF
+	f, i := float64(full), float64(ir)
+	lux := (f - i) * (1 - i/f) / cpl
+	return physic.LuminousFlux(lux * float64(physic.Lumen)), nil
+}
+
+// SetThresholds sets the full spectrum channel counts out of which the ALS
+// interrupt is asserted, after persist consecutive out of range cycles.
+//
+// persist is the PERSIST register value: 0 asserts on every cycle, 1 on any
+// value out of range, 2 to 15 after 2, 3, 5, 10, 15, ..., 60 cycles.
+func (d *Dev) SetThresholds(low, high uint16, persist byte) error {
+	if persist > 15 {
+		return errors.New("tsl2591: invalid persist filter")
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.writeRegs(regAILTL, byte(low), byte(low>>8), byte(high), byte(high>>8)); err != nil {
+		return err
+	}
+	return d.writeRegs(regPersist, persist)
+}
+
+// SetNoPersistThresholds sets the full spectrum channel counts out of which
+// the no-persist interrupt is asserted immediately.
+func (d *Dev) SetNoPersistThresholds(low, high uint16) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.writeRegs(regNPAILTL, byte(low), byte(low>>8), byte(high), byte(high>>8))
+}
+
+// EnableInterrupts enables or disables the assertion of the INT pin by the
+// ALS and the no-persist interrupts.
+func (d *Dev) EnableInterrupts(als, noPersist bool) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	e := d.enable &^ (enableAIEN | enableNPIEN)
+	if als {
+		e |= enableAIEN
+	}
+	if noPersist {
+		e |= enableNPIEN
+	}
+	return d.setEnable(e)
+}
+
+// ClearInterrupts clears the ALS and no-persist interrupts, releasing the INT
+// pin.
+func (d *Dev) ClearInterrupts() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.c.Tx([]byte{cmdSpecial | sfClearAll}, nil)
+}
+
+// Halt powers the device off.
+//
+// Halt implements conn.Resource.
+func (d *Dev) Halt() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.setEnable(0)
+}
+
+//
+
+// Command register bits.
+const (
+	cmdNormal  = 0xA0
+	cmdSpecial = 0xE0
+
+	sfClearAll = 0x07
+)
+
+// Registers.
+const (
+	regEnable  = 0x00
+	regConfig  = 0x01
+	regAILTL   = 0x04
+	regNPAILTL = 0x08
+	regPersist = 0x0C
+	regID      = 0x12
+	regStatus  = 0x13
+	regC0DataL = 0x14
+
+	deviceID = 0x50
+)
+
+// ENABLE bits.
+const (
+	enablePON   = 0x01
+	enableAEN   = 0x02
+	enableAIEN  = 0x10
+	enableNPIEN = 0x80
+)
+
+const (
+	statusAValid = 0x01
+
+	// luxDF is the device and glass factor.
+	luxDF = 408.0
+)
+
+var sleep = time.Sleep
+
+func (d *Dev) setConfig(g Gain, i IntegrationTime) error {
+	if g&^0x30 != 0 {
+		return errors.New("tsl2591: invalid gain")
+	}
+	if i > Integration600ms {
+		return errors.New("tsl2591: invalid integration time")
+	}
+	if err := d.writeRegs(regConfig, byte(g)|byte(i)); err != nil {
+		return err
+	}
+	d.gain = g
+	d.atime = i
+	return nil
+}
+
+// channels waits for a valid measurement, sleeping at most once for the
+// integration time, and reads both channels.
+func (d *Dev) channels() (uint16, uint16, error) {
+	s, err := d.readReg(regStatus)
+	if err != nil {
+		return 0, 0, err
+	}
+	if s&statusAValid == 0 {
+		sleep(d.atime.Duration())
+		if s, err = d.readReg(regStatus); err != nil {
+			return 0, 0, err
+		}
+		if s&statusAValid == 0 {
+			return 0, 0, errors.New("tsl2591: no valid measurement")
+		}
+	}
+	var b [4]byte
+	if err := d.c.Tx([]byte{cmdNormal | regC0DataL}, b[:]); err != nil {
+		return 0, 0, err
+	}
+	return binary.LittleEndian.Uint16(b[:]), binary.LittleEndian.Uint16(b[2:]), nil
+}
+
+func (d *Dev) setEnable(e byte) error {
+	if err := d.writeRegs(regEnable, e); err != nil {
+		return err
+	}
+	d.enable = e
+	return nil
+}
+
+func (d *Dev) readReg(reg byte) (byte, error) {
+	var b [1]byte
+	err := d.c.Tx([]byte{cmdNormal | reg}, b[:])
+	return b[0], err
+}
+
+// writeRegs writes v to the registers starting at reg.
+func (d *Dev) writeRegs(reg byte, v ...byte) error {
+	return d.c.Tx(append([]byte{cmdNormal | reg}, v...), nil)
+}
+
+var _ conn.Resource = &Dev{}
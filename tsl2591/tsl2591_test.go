@@ -0,0 +1,135 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package tsl2591
+
+import (
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3/i2c/i2ctest"
+	"periph.io/x/conn/v3/physic"
+)
+
+var initOps = []i2ctest.IO{
+	{Addr: I2CAddr, W: []byte{0xB2}, R: []byte{deviceID}},
+	{Addr: I2CAddr, W: []byte{0xA1, 0x10}},
+	{Addr: I2CAddr, W: []byte{0xA0, 0x03}},
+}
+
+func TestNewI2C_BadID(t *testing.T) {
+	bus := i2ctest.Playback{Ops: []i2ctest.IO{{Addr: I2CAddr, W: []byte{0xB2}, R: []byte{0x12}}}}
+	if _, err := NewI2C(&bus, &DefaultOpts); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestIlluminance(t *testing.T) {
+	defer func(f func(time.Duration)) { sleep = f }(sleep)
+	var slept time.Duration
+	sleep = func(d time.Duration) { slept += d }
+	bus := i2ctest.Playback{
+		Ops: append(initOps,
+			i2ctest.IO{Addr: I2CAddr, W: []byte{0xB3}, R: []byte{0x00}},
+			i2ctest.IO{Addr: I2CAddr, W: []byte{0xB3}, R: []byte{0x01}},
+			i2ctest.IO{Addr: I2CAddr, W: []byte{0xB4}, R: []byte{0xE8, 0x03, 0xC8, 0x00}},
+			i2ctest.IO{Addr: I2CAddr, W: []byte{0xB3}, R: []byte{0x01}},
+			i2ctest.IO{Addr: I2CAddr, W: []byte{0xB4}, R: []byte{0xFF, 0xFF, 0xC8, 0x00}},
+			i2ctest.IO{Addr: I2CAddr, W: []byte{0xA1, 0x15}},
+			i2ctest.IO{Addr: I2CAddr, W: []byte{0xB3}, R: []byte{0x01}},
+			i2ctest.IO{Addr: I2CAddr, W: []byte{0xB4}, R: []byte{0x34, 0x12, 0x78, 0x06}},
+			i2ctest.IO{Addr: I2CAddr, W: []byte{0xA0, 0x00}},
+		),
+	}
+	dev, err := NewI2C(&bus, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := dev.String(); s != "TSL2591{playback(41)}" {
+		t.Fatal(s)
+	}
+	lux, err := dev.Illuminance()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// (1000-200) * (1-200/1000) / (100*25/408)
+	if want := 104448 * physic.MilliLumen; lux < want-physic.MicroLumen || lux > want+physic.MicroLumen {
+		t.Fatal(lux)
+	}
+	if slept != 100*time.Millisecond {
+		t.Fatal(slept)
+	}
+	if _, err := dev.Illuminance(); err != ErrOverflow {
+		t.Fatal(err)
+	}
+	if err := dev.SetIntegrationTime(Integration600ms); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.SetIntegrationTime(IntegrationTime(6)); err == nil {
+		t.Fatal("expected error")
+	}
+	if err := dev.SetGain(Gain(0x40)); err == nil {
+		t.Fatal("expected error")
+	}
+	full, ir, err := dev.Channels()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if full != 0x1234 || ir != 0x0678 {
+		t.Fatal(full, ir)
+	}
+	if err := dev.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInterrupts(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: append(initOps,
+			i2ctest.IO{Addr: I2CAddr, W: []byte{0xA4, 0x34, 0x12, 0x78, 0x56}},
+			i2ctest.IO{Addr: I2CAddr, W: []byte{0xAC, 0x04}},
+			i2ctest.IO{Addr: I2CAddr, W: []byte{0xA8, 0x10, 0x00, 0x00, 0xF0}},
+			i2ctest.IO{Addr: I2CAddr, W: []byte{0xA0, 0x93}},
+			i2ctest.IO{Addr: I2CAddr, W: []byte{0xA0, 0x13}},
+			i2ctest.IO{Addr: I2CAddr, W: []byte{0xE7}},
+		),
+	}
+	dev, err := NewI2C(&bus, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.SetThresholds(0x1234, 0x5678, 16); err == nil {
+		t.Fatal("expected error")
+	}
+	if err := dev.SetThresholds(0x1234, 0x5678, 4); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.SetNoPersistThresholds(0x10, 0xF000); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.EnableInterrupts(true, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.EnableInterrupts(true, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.ClearInterrupts(); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStrings(t *testing.T) {
+	if s := GainHigh.String(); s != "428x" {
+		t.Fatal(s)
+	}
+	if s := Integration300ms.String(); s != "300ms" {
+		t.Fatal(s)
+	}
+}
@@ -0,0 +1,22 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package uc8151 controls black and white e-paper displays driven by a
+// UltraChip UC8151 or a compatible IL0373 controller, like the 2.9in 296x128
+// panel of the Pimoroni Badger 2040.
+//
+// Full refreshes flash the whole panel to clear the ghosting. Partial
+// refreshes only update the drawn area, without flashing, but leave ghosting
+// behind; see SetUpdateMode.
+//
+// The refresh speed is chosen with Opts.Speed or SetSpeed. At the Default
+// speed full refreshes use the waveforms stored in the controller OTP; the
+// other speeds, and all partial refreshes, use waveforms generated by the
+// driver and written to the controller registers. Faster speeds leave more
+// ghosting.
+//
+// # Datasheet
+//
+// https://www.buydisplay.com/download/ic/UC8151C.pdf
+package uc8151
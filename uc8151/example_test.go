@@ -0,0 +1,77 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package uc8151_test
+
+import (
+	"image"
+	"image/draw"
+	"log"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+
+	"periph.io/x/conn/v3/gpio/gpioreg"
+	"periph.io/x/conn/v3/spi/spireg"
+	"periph.io/x/devices/v3/ssd1306/image1bit"
+	"periph.io/x/devices/v3/uc8151"
+	"periph.io/x/host/v3"
+)
+
+func Example() {
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Use spireg SPI bus registry to find the first available SPI bus.
+	b, err := spireg.Open("")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer b.Close()
+
+	dc := gpioreg.ByName("GPIO25")
+	cs := gpioreg.ByName("GPIO8")
+	rst := gpioreg.ByName("GPIO17")
+	busy := gpioreg.ByName("GPIO24")
+	dev, err := uc8151.New(b, dc, cs, rst, busy, &uc8151.Badger2040)
+	if err != nil {
+		log.Fatalf("Failed to initialize driver: %v", err)
+	}
+	if err := dev.Init(); err != nil {
+		log.Fatalf("Failed to initialize display: %v", err)
+	}
+
+	// Draw on it. Black text on a white background.
+	img := image1bit.NewVerticalLSB(dev.Bounds())
+	draw.Draw(img, img.Bounds(), &image.Uniform{image1bit.On}, image.Point{}, draw.Src)
+	f := basicfont.Face7x13
+	drawer := font.Drawer{
+		Dst:  img,
+		Src:  &image.Uniform{image1bit.Off},
+		Face: f,
+		Dot:  fixed.P(0, img.Bounds().Dy()-1-f.Descent),
+	}
+	drawer.DrawString("Hello from periph!")
+	if err := dev.Draw(dev.Bounds(), img, image.Point{}); err != nil {
+		log.Fatal(err)
+	}
+
+	// Update the text quickly, without flashing the display.
+	if err := dev.SetSpeed(uc8151.Fast); err != nil {
+		log.Fatal(err)
+	}
+	if err := dev.SetUpdateMode(uc8151.Partial); err != nil {
+		log.Fatal(err)
+	}
+	r := image.Rect(0, 0, 7*13, 13)
+	draw.Draw(img, r, &image.Uniform{image1bit.On}, image.Point{}, draw.Src)
+	drawer.Dot = fixed.P(0, 13-f.Descent)
+	drawer.DrawString("Updated")
+	if err := dev.Draw(r, img, r.Min); err != nil {
+		log.Fatal(err)
+	}
+}
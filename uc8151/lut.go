@@ -0,0 +1,84 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package uc8151
+
+// A LUT is made of 7 groups of 6 bytes: the levels of the 4 phases, 2 bits
+// each, the number of frames of each phase, and the number of repeats of the
+// group. The VCOM LUT has 2 more bytes.
+const (
+	lutGroups    = 7
+	lutGroupSize = 6
+)
+
+// Phase levels
+const (
+	levelVCOM = 0b00
+	// levelHigh drives a pixel toward black.
+	levelHigh = 0b01
+	// levelLow drives a pixel toward white.
+	levelLow = 0b10
+)
+
+// levels packs the levels of the 4 phases of a group.
+func levels(p0, p1, p2, p3 byte) byte {
+	return p0<<6 | p1<<4 | p2<<2 | p3
+}
+
+// frames is the number of frames of a phase at each speed.
+var frames = [...]byte{
+	Default: 20,
+	Medium:  12,
+	Fast:    6,
+	Turbo:   4,
+}
+
+// luts returns the VCOM, WW, BW, WB and BB LUTs of a refresh at speed s. The
+// last 4 apply to the pixels going from white to white, black to white, white
+// to black and black to black.
+//
+// A full refresh flashes the pixels twice before driving them to their color,
+// twice as long at Default and Medium speeds. A partial refresh only drives
+// the pixels changing color, and shortly shakes the others to limit the
+// ghosting.
+func luts(s Speed, partial bool) [5][]byte {
+	n := frames[s]
+	var out [5][]byte
+	for i := range out {
+		out[i] = make([]byte, lutGroups*lutGroupSize)
+	}
+	out[0] = append(out[0], 0x00, 0x00)
+	group := func(g int, lvl [5]byte, tp0, tp1, tp2, tp3, repeat byte) {
+		for i := range out {
+			copy(out[i][g*lutGroupSize:], []byte{lvl[i], tp0, tp1, tp2, tp3, repeat})
+		}
+	}
+
+	if partial {
+		group(0, [5]byte{
+			levels(levelVCOM, levelVCOM, levelVCOM, levelVCOM),
+			levels(levelVCOM, levelHigh, levelLow, levelVCOM),
+			levels(levelHigh, levelHigh, levelLow, levelLow),
+			levels(levelLow, levelLow, levelHigh, levelHigh),
+			levels(levelVCOM, levelLow, levelHigh, levelVCOM),
+		}, n, 1, n, 1, 1)
+		return out
+	}
+
+	repeat := byte(1)
+	if s <= Medium {
+		repeat = 2
+	}
+	toWhite := levels(levelHigh, levelLow, levelHigh, levelLow)
+	toBlack := levels(levelLow, levelHigh, levelLow, levelHigh)
+	group(0, [5]byte{0, toWhite, toWhite, toBlack, toBlack}, n, n, n, n, repeat)
+	group(1, [5]byte{
+		0,
+		levels(levelLow, levelVCOM, levelVCOM, levelVCOM),
+		levels(levelLow, levelVCOM, levelVCOM, levelVCOM),
+		levels(levelHigh, levelVCOM, levelVCOM, levelVCOM),
+		levels(levelHigh, levelVCOM, levelVCOM, levelVCOM),
+	}, 2*n, 0, 0, 0, 1)
+	return out
+}
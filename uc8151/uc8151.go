@@ -0,0 +1,416 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package uc8151
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/display"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/spi"
+	"periph.io/x/devices/v3/ssd1306/image1bit"
+)
+
+// Commands
+const (
+	panelSetting           byte = 0x00
+	powerSetting           byte = 0x01
+	powerOff               byte = 0x02
+	powerOffSequence       byte = 0x03
+	powerOn                byte = 0x04
+	boosterSoftStart       byte = 0x06
+	deepSleep              byte = 0x07
+	dataStop               byte = 0x11
+	displayRefresh         byte = 0x12
+	dataStartTransmission2 byte = 0x13
+	lutVCOM                byte = 0x20
+	lutWW                  byte = 0x21
+	lutBW                  byte = 0x22
+	lutWB                  byte = 0x23
+	lutBB                  byte = 0x24
+	pllControl             byte = 0x30
+	temperatureSensor      byte = 0x41
+	vcomDataInterval       byte = 0x50
+	tconSetting            byte = 0x60
+	resolutionSetting      byte = 0x61
+	partialWindow          byte = 0x90
+	partialIn              byte = 0x91
+	partialOut             byte = 0x92
+)
+
+// Flags of the panelSetting command
+const (
+	panelResetNone   byte = 1 << 0
+	panelBoosterOn   byte = 1 << 1
+	panelShiftRight  byte = 1 << 2
+	panelScanUp      byte = 1 << 3
+	panelBlackWhite  byte = 1 << 4
+	panelLUTRegister byte = 1 << 5
+	panelRes160x296  byte = 3 << 6
+)
+
+// PLL frequencies
+const (
+	pll100Hz byte = 0x3A
+	pll200Hz byte = 0x39
+)
+
+// deepSleepCheck must follow the deepSleep command.
+const deepSleepCheck = 0xA5
+
+// Rotation is the rotation of the image relative to the native orientation of
+// the panel, where the rows follow the gate lines.
+type Rotation uint8
+
+// Rotations, clockwise.
+const (
+	Rotate0 Rotation = iota
+	Rotate90
+	Rotate180
+	Rotate270
+)
+
+// PartialUpdate defines if the display should do a full update or just a partial update.
+type PartialUpdate bool
+
+const (
+	// Full should update the complete display.
+	Full PartialUpdate = false
+	// Partial should update only partial parts of the display.
+	Partial PartialUpdate = true
+)
+
+// Speed is the speed of the refreshes.
+type Speed uint8
+
+const (
+	// Default uses the waveforms of the controller OTP for the full
+	// refreshes. It is the slowest and leaves no ghosting.
+	Default Speed = iota
+	Medium
+	Fast
+	// Turbo also doubles the frame rate.
+	Turbo
+)
+
+func (s Speed) String() string {
+	switch s {
+	case Default:
+		return "Default"
+	case Medium:
+		return "Medium"
+	case Fast:
+		return "Fast"
+	case Turbo:
+		return "Turbo"
+	default:
+		return fmt.Sprintf("Speed(%d)", s)
+	}
+}
+
+// Opts defines the structure of the display configuration.
+type Opts struct {
+	// Width and Height are the size of the panel in its native orientation:
+	// the number of source lines, a multiple of 8 up to 160, and the number of
+	// gate lines, up to 296.
+	Width  int
+	Height int
+	// Rotation of the image drawn.
+	Rotation Rotation
+	// Speed of the refreshes.
+	Speed Speed
+}
+
+// Badger2040 contains display configuration for the Pimoroni Badger 2040,
+// in landscape orientation.
+var Badger2040 = Opts{
+	Width:    128,
+	Height:   296,
+	Rotation: Rotate90,
+}
+
+// New creates new handler which is used to access the display.
+func New(p spi.Port, dc, cs, rst gpio.PinOut, busy gpio.PinIn, opts *Opts) (*Dev, error) {
+	if opts.Width <= 0 || opts.Width > 160 || opts.Width%8 != 0 {
+		return nil, fmt.Errorf("uc8151: invalid width %d", opts.Width)
+	}
+	if opts.Height <= 0 || opts.Height > 296 {
+		return nil, fmt.Errorf("uc8151: invalid height %d", opts.Height)
+	}
+	if opts.Rotation > Rotate270 {
+		return nil, fmt.Errorf("uc8151: invalid rotation %d", opts.Rotation)
+	}
+	if opts.Speed > Turbo {
+		return nil, fmt.Errorf("uc8151: invalid speed %s", opts.Speed)
+	}
+
+	c, err := p.Connect(10*physic.MegaHertz, spi.Mode0, 8)
+	if err != nil {
+		return nil, err
+	}
+
+	// The busy pin is active low.
+	if err := busy.In(gpio.Float, gpio.RisingEdge); err != nil {
+		return nil, err
+	}
+
+	bounds := image.Rect(0, 0, opts.Width, opts.Height)
+	if opts.Rotation == Rotate90 || opts.Rotation == Rotate270 {
+		bounds = image.Rect(0, 0, opts.Height, opts.Width)
+	}
+
+	d := &Dev{
+		c:      c,
+		dc:     dc,
+		cs:     cs,
+		rst:    rst,
+		busy:   busy,
+		bounds: bounds,
+		// Default color
+		mem:   bytes.Repeat([]byte{0xFF}, opts.Width/8*opts.Height),
+		mode:  Full,
+		speed: opts.Speed,
+		opts:  *opts,
+	}
+	return d, nil
+}
+
+// Dev defines the handler which is used to access the display.
+type Dev struct {
+	c conn.Conn
+
+	dc   gpio.PinOut
+	cs   gpio.PinOut
+	rst  gpio.PinOut
+	busy gpio.PinIn
+
+	bounds image.Rectangle
+	// mem is the image in the controller RAM layout, 1 for white.
+	mem   []byte
+	mode  PartialUpdate
+	speed Speed
+
+	opts Opts
+}
+
+// Init configures the display for usage through the other functions.
+func (d *Dev) Init() error {
+	if err := d.reset(); err != nil {
+		return err
+	}
+
+	eh := errorHandler{d: *d}
+
+	eh.waitUntilIdle()
+	eh.command(panelSetting, d.panelSetting(false))
+	eh.command(powerSetting, 0x03, 0x00, 0x2B, 0x2B, 0x2B)
+	eh.command(powerOn)
+	eh.waitUntilIdle()
+	eh.command(boosterSoftStart, 0x17, 0x17, 0x17)
+	eh.command(powerOffSequence, 0x00)
+	eh.command(temperatureSensor, 0x00)
+	eh.command(tconSetting, 0x22)
+	eh.command(vcomDataInterval, 0x97)
+	eh.command(pllControl, d.pll())
+	eh.command(resolutionSetting, byte(d.opts.Width), byte(d.opts.Height>>8), byte(d.opts.Height))
+	eh.command(powerOff)
+	eh.waitUntilIdle()
+
+	return eh.err
+}
+
+// SetUpdateMode changes the way updates to the displayed image are applied. In
+// Full mode (the default) a full refresh is done with all pixels cleared and
+// re-applied. In Partial mode only the drawn area is updated, potentially
+// leaving behind small optical artifacts due to the way e-paper displays work.
+func (d *Dev) SetUpdateMode(mode PartialUpdate) error {
+	d.mode = mode
+	return nil
+}
+
+// SetSpeed changes the speed of the next refreshes.
+func (d *Dev) SetSpeed(s Speed) error {
+	if s > Turbo {
+		return fmt.Errorf("uc8151: invalid speed %s", s)
+	}
+	d.speed = s
+	eh := errorHandler{d: *d}
+	eh.command(pllControl, d.pll())
+	return eh.err
+}
+
+// Clear clears the display with color, usually image1bit.On (white) or
+// image1bit.Off (black). A full refresh is always done.
+func (d *Dev) Clear(color color.Color) error {
+	d.fill(d.bounds, &image.Uniform{C: color}, image.Point{})
+	return d.refresh(d.bounds, false)
+}
+
+// ColorModel returns a 1Bit color model.
+func (d *Dev) ColorModel() color.Model {
+	return image1bit.BitModel
+}
+
+// Bounds returns the bounds for the configurated display.
+func (d *Dev) Bounds() image.Rectangle {
+	return d.bounds
+}
+
+// Draw draws the given image to the display. Depending on the update mode the
+// whole display or the destination area is refreshed.
+func (d *Dev) Draw(dstRect image.Rectangle, src image.Image, srcPts image.Point) error {
+	r := dstRect.Intersect(d.bounds)
+	if r.Empty() {
+		return nil
+	}
+	d.fill(r, src, srcPts.Add(r.Min.Sub(dstRect.Min)))
+	return d.refresh(r, d.mode == Partial)
+}
+
+// Halt clears the display.
+func (d *Dev) Halt() error {
+	return d.Clear(image1bit.On)
+}
+
+// String returns a string containing configuration information.
+func (d *Dev) String() string {
+	return fmt.Sprintf("uc8151.Dev{%s, %s, Width: %d, Height: %d}", d.c, d.dc, d.bounds.Dx(), d.bounds.Dy())
+}
+
+// Sleep makes the controller enter deep sleep mode. It can be woken up by
+// calling Init again.
+func (d *Dev) Sleep() error {
+	eh := errorHandler{d: *d}
+	eh.command(deepSleep, deepSleepCheck)
+	return eh.err
+}
+
+//
+
+// panelSetting returns the panel setting for the LUTs of the refresh.
+func (d *Dev) panelSetting(lutRegister bool) byte {
+	s := panelRes160x296 | panelBlackWhite | panelScanUp | panelShiftRight | panelBoosterOn | panelResetNone
+	if lutRegister {
+		s |= panelLUTRegister
+	}
+	return s
+}
+
+func (d *Dev) pll() byte {
+	if d.speed == Turbo {
+		return pll200Hz
+	}
+	return pll100Hz
+}
+
+// memPoint returns the position in the controller RAM of the pixel at p.
+func (d *Dev) memPoint(p image.Point) image.Point {
+	w, h := d.opts.Width, d.opts.Height
+	switch d.opts.Rotation {
+	case Rotate90:
+		return image.Pt(w-1-p.Y, p.X)
+	case Rotate180:
+		return image.Pt(w-1-p.X, h-1-p.Y)
+	case Rotate270:
+		return image.Pt(p.Y, h-1-p.X)
+	default:
+		return p
+	}
+}
+
+// memRect returns the area of the controller RAM covering r, aligned on 8
+// pixels horizontally.
+func (d *Dev) memRect(r image.Rectangle) image.Rectangle {
+	a, b := d.memPoint(r.Min), d.memPoint(r.Max.Sub(image.Pt(1, 1)))
+	m := image.Rectangle{Min: a, Max: b}.Canon()
+	m.Max = m.Max.Add(image.Pt(1, 1))
+	m.Min.X &^= 7
+	m.Max.X = (m.Max.X + 7) &^ 7
+	return m
+}
+
+// fill draws src in the area r of the RAM image.
+func (d *Dev) fill(r image.Rectangle, src image.Image, sp image.Point) {
+	img := image1bit.NewVerticalLSB(image.Rectangle{Min: r.Min, Max: r.Max})
+	draw.Src.Draw(img, r, src, sp)
+	stride := d.opts.Width / 8
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			m := d.memPoint(image.Pt(x, y))
+			i, bit := m.Y*stride+m.X/8, byte(0x80>>(m.X%8))
+			if img.BitAt(x, y) {
+				d.mem[i] |= bit
+			} else {
+				d.mem[i] &^= bit
+			}
+		}
+	}
+}
+
+// refresh sends the area r of the image and refreshes the display, only in r
+// if partial is set.
+func (d *Dev) refresh(r image.Rectangle, partial bool) error {
+	eh := errorHandler{d: *d}
+
+	eh.command(powerOn)
+	eh.waitUntilIdle()
+
+	lutRegister := partial || d.speed != Default
+	eh.command(panelSetting, d.panelSetting(lutRegister))
+	if lutRegister {
+		l := luts(d.speed, partial)
+		for i, cmd := range []byte{lutVCOM, lutWW, lutBW, lutWB, lutBB} {
+			eh.command(cmd, l[i]...)
+		}
+	}
+
+	stride := d.opts.Width / 8
+	if partial {
+		m := d.memRect(r)
+		eh.command(partialIn)
+		eh.command(partialWindow,
+			byte(m.Min.X), byte(m.Max.X-1),
+			byte(m.Min.Y>>8), byte(m.Min.Y),
+			byte((m.Max.Y-1)>>8), byte(m.Max.Y-1),
+			0x01)
+		eh.sendCommand(dataStartTransmission2)
+		for y := m.Min.Y; y < m.Max.Y; y++ {
+			eh.sendData(d.mem[y*stride+m.Min.X/8 : y*stride+m.Max.X/8])
+		}
+	} else {
+		eh.command(dataStartTransmission2, d.mem...)
+	}
+	eh.command(dataStop)
+	eh.command(displayRefresh)
+	eh.waitUntilIdle()
+	if partial {
+		eh.command(partialOut)
+	}
+	eh.command(powerOff)
+	eh.waitUntilIdle()
+
+	return eh.err
+}
+
+// Reset the hardware
+func (d *Dev) reset() error {
+	eh := errorHandler{d: *d}
+
+	eh.rstOut(gpio.Low)
+	time.Sleep(10 * time.Millisecond)
+	eh.rstOut(gpio.High)
+	time.Sleep(10 * time.Millisecond)
+
+	return eh.err
+}
+
+var _ display.Drawer = &Dev{}
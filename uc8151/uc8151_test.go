@@ -0,0 +1,251 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package uc8151
+
+import (
+	"bytes"
+	"image"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpiotest"
+	"periph.io/x/conn/v3/spi/spitest"
+	"periph.io/x/devices/v3/devicetest"
+	"periph.io/x/devices/v3/ssd1306/image1bit"
+)
+
+type record = devicetest.Record
+
+func newDev(t *testing.T, opts *Opts) (*Dev, *devicetest.CommandConn) {
+	c := devicetest.NewCommandConn()
+	d, err := New(c, &c.DC, &gpiotest.Pin{}, &gpiotest.Pin{}, &gpiotest.Pin{
+		L:         gpio.High,
+		EdgesChan: make(chan gpio.Level, 1),
+	}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d, c
+}
+
+func TestNew(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		opts       Opts
+		wantString string
+		wantBounds image.Rectangle
+		wantErr    bool
+	}{
+		{
+			name:       "Badger2040",
+			opts:       Badger2040,
+			wantBounds: image.Rect(0, 0, 296, 128),
+			wantString: "uc8151.Dev{playback, (0), Width: 296, Height: 128}",
+		},
+		{
+			name:       "portrait",
+			opts:       Opts{Width: 128, Height: 296, Rotation: Rotate180},
+			wantBounds: image.Rect(0, 0, 128, 296),
+			wantString: "uc8151.Dev{playback, (0), Width: 128, Height: 296}",
+		},
+		{
+			name:    "invalid width",
+			opts:    Opts{Width: 100, Height: 296},
+			wantErr: true,
+		},
+		{
+			name:    "invalid height",
+			opts:    Opts{Width: 128, Height: 300},
+			wantErr: true,
+		},
+		{
+			name:    "invalid speed",
+			opts:    Opts{Width: 128, Height: 296, Speed: 4},
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dev, err := New(&spitest.Playback{}, &gpiotest.Pin{}, &gpiotest.Pin{}, &gpiotest.Pin{}, &gpiotest.Pin{
+				EdgesChan: make(chan gpio.Level, 1),
+			}, &tc.opts)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("New() should fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("New() failed: %v", err)
+			}
+
+			if diff := cmp.Diff(dev.String(), tc.wantString); diff != "" {
+				t.Errorf("String() difference (-got +want):\n%s", diff)
+			}
+
+			if diff := cmp.Diff(dev.Bounds(), tc.wantBounds); diff != "" {
+				t.Errorf("Bounds() difference (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestInit(t *testing.T) {
+	d, c := newDev(t, &Badger2040)
+	if err := d.Init(); err != nil {
+		t.Fatal(err)
+	}
+	want := []record{
+		{Cmd: panelSetting, Data: []byte{0xDF}},
+		{Cmd: powerSetting, Data: []byte{0x03, 0x00, 0x2B, 0x2B, 0x2B}},
+		{Cmd: powerOn},
+		{Cmd: boosterSoftStart, Data: []byte{0x17, 0x17, 0x17}},
+		{Cmd: powerOffSequence, Data: []byte{0x00}},
+		{Cmd: temperatureSensor, Data: []byte{0x00}},
+		{Cmd: tconSetting, Data: []byte{0x22}},
+		{Cmd: vcomDataInterval, Data: []byte{0x97}},
+		{Cmd: pllControl, Data: []byte{0x3A}},
+		{Cmd: resolutionSetting, Data: []byte{128, 0x01, 0x28}},
+		{Cmd: powerOff},
+	}
+	if diff := c.Diff(want); diff != "" {
+		t.Errorf("Init() difference (-got +want):\n%s", diff)
+	}
+
+	c.Reset()
+	if err := d.SetSpeed(Turbo); err != nil {
+		t.Fatal(err)
+	}
+	if diff := c.Diff([]record{{Cmd: pllControl, Data: []byte{0x39}}}); diff != "" {
+		t.Errorf("SetSpeed() difference (-got +want):\n%s", diff)
+	}
+	if err := d.SetSpeed(4); err == nil {
+		t.Error("SetSpeed() should fail with an invalid speed")
+	}
+}
+
+func TestDraw_full(t *testing.T) {
+	d, c := newDev(t, &Badger2040)
+
+	// A black pixel at the top left corner is at the end of the first row of
+	// the RAM.
+	if err := d.Draw(image.Rect(0, 0, 1, 1), &image.Uniform{image1bit.Off}, image.Point{}); err != nil {
+		t.Fatal(err)
+	}
+	mem := bytes.Repeat([]byte{0xFF}, 128/8*296)
+	mem[15] = 0xFE
+	want := []record{
+		{Cmd: powerOn},
+		{Cmd: panelSetting, Data: []byte{0xDF}},
+		{Cmd: dataStartTransmission2, Data: mem},
+		{Cmd: dataStop},
+		{Cmd: displayRefresh},
+		{Cmd: powerOff},
+	}
+	if diff := c.Diff(want); diff != "" {
+		t.Errorf("Draw() difference (-got +want):\n%s", diff)
+	}
+
+	// Other speeds load the waveforms.
+	c.Reset()
+	if err := d.SetSpeed(Fast); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Clear(image1bit.On); err != nil {
+		t.Fatal(err)
+	}
+	l := luts(Fast, false)
+	want = []record{
+		{Cmd: pllControl, Data: []byte{0x3A}},
+		{Cmd: powerOn},
+		{Cmd: panelSetting, Data: []byte{0xFF}},
+		{Cmd: lutVCOM, Data: l[0]},
+		{Cmd: lutWW, Data: l[1]},
+		{Cmd: lutBW, Data: l[2]},
+		{Cmd: lutWB, Data: l[3]},
+		{Cmd: lutBB, Data: l[4]},
+		{Cmd: dataStartTransmission2, Data: bytes.Repeat([]byte{0xFF}, 128/8*296)},
+		{Cmd: dataStop},
+		{Cmd: displayRefresh},
+		{Cmd: powerOff},
+	}
+	if diff := c.Diff(want); diff != "" {
+		t.Errorf("Clear() difference (-got +want):\n%s", diff)
+	}
+}
+
+func TestDraw_partial(t *testing.T) {
+	d, c := newDev(t, &Opts{Width: 128, Height: 296})
+	if err := d.SetUpdateMode(Partial); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := d.Draw(image.Rect(10, 2, 20, 4), &image.Uniform{image1bit.Off}, image.Point{}); err != nil {
+		t.Fatal(err)
+	}
+	l := luts(Default, true)
+	row := []byte{0xC0, 0x0F}
+	want := []record{
+		{Cmd: powerOn},
+		{Cmd: panelSetting, Data: []byte{0xFF}},
+		{Cmd: lutVCOM, Data: l[0]},
+		{Cmd: lutWW, Data: l[1]},
+		{Cmd: lutBW, Data: l[2]},
+		{Cmd: lutWB, Data: l[3]},
+		{Cmd: lutBB, Data: l[4]},
+		{Cmd: partialIn},
+		{Cmd: partialWindow, Data: []byte{8, 23, 0, 2, 0, 3, 0x01}},
+		{Cmd: dataStartTransmission2, Data: append(append([]byte{}, row...), row...)},
+		{Cmd: dataStop},
+		{Cmd: displayRefresh},
+		{Cmd: partialOut},
+		{Cmd: powerOff},
+	}
+	if diff := c.Diff(want); diff != "" {
+		t.Errorf("Draw() difference (-got +want):\n%s", diff)
+	}
+}
+
+func TestMemRect(t *testing.T) {
+	d, _ := newDev(t, &Badger2040)
+	// The columns of the landscape image are the rows of the RAM, from the
+	// right.
+	got := d.memRect(image.Rect(5, 0, 10, 3))
+	if want := image.Rect(120, 5, 128, 10); got != want {
+		t.Errorf("memRect() = %v, want %v", got, want)
+	}
+}
+
+func TestLUTs(t *testing.T) {
+	for s := Default; s <= Turbo; s++ {
+		for _, partial := range []bool{false, true} {
+			l := luts(s, partial)
+			if len(l[0]) != 44 {
+				t.Errorf("len(VCOM) = %d", len(l[0]))
+			}
+			for _, lut := range l[1:] {
+				if len(lut) != 42 {
+					t.Errorf("len(LUT) = %d", len(lut))
+				}
+			}
+		}
+	}
+	l := luts(Fast, true)
+	if got := []byte{l[1][0], l[2][0], l[3][0], l[4][0]}; !bytes.Equal(got, []byte{0x18, 0x5A, 0xA5, 0x24}) {
+		t.Errorf("partial levels = %#v", got)
+	}
+	if l = luts(Medium, false); l[1][5] != 2 || l[1][6] != 0x80 || l[1][7] != 24 {
+		t.Errorf("full WW = %#v", l[1])
+	}
+}
+
+func TestSpeed_String(t *testing.T) {
+	if s := Turbo.String(); s != "Turbo" {
+		t.Error(s)
+	}
+	if s := Speed(4).String(); s != "Speed(4)" {
+		t.Error(s)
+	}
+}
@@ -0,0 +1,23 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package veml6070 controls a Vishay VEML6070 UVA light sensor over I²C.
+//
+// The sensor uses three fixed I²C addresses, 0x38 and 0x39 for the command and
+// the data, and 0x0C to clear its acknowledge signal. It must not share the
+// bus with other devices on these addresses.
+//
+// The integration time is set by the RSET resistor; the common breakout
+// boards use 270kΩ for a 1T integration time of about 125ms. The UV index is
+// estimated from the count with the sensitivity documented for this resistor
+// in the application note.
+//
+// # Datasheet
+//
+// https://www.vishay.com/docs/84277/veml6070.pdf
+//
+// # Application note
+//
+// https://www.vishay.com/docs/84310/designingveml6070.pdf
+package veml6070
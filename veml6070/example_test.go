@@ -0,0 +1,40 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package veml6070_test
+
+import (
+	"fmt"
+	"log"
+
+	"periph.io/x/conn/v3/i2c/i2creg"
+	"periph.io/x/devices/v3/veml6070"
+	"periph.io/x/host/v3"
+)
+
+func Example() {
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Open default I²C bus.
+	bus, err := i2creg.Open("")
+	if err != nil {
+		log.Fatalf("failed to open I²C: %v", err)
+	}
+	defer bus.Close()
+
+	sensor, err := veml6070.NewI2C(bus, &veml6070.DefaultOpts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer sensor.Halt()
+
+	uv, err := sensor.Sense()
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(uv)
+}
@@ -0,0 +1,309 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package veml6070
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/i2c"
+)
+
+// IntegrationTime is the duration of a measurement, as a multiple of T.
+type IntegrationTime uint8
+
+// Possible integration times.
+const (
+	IntegrationHalf IntegrationTime = 0 // ½T
+	Integration1T   IntegrationTime = 1 // 1T
+	Integration2T   IntegrationTime = 2 // 2T
+	Integration4T   IntegrationTime = 3 // 4T
+)
+
+// Duration returns the integration time with a 270kΩ RSET.
+func (i IntegrationTime) Duration() time.Duration {
+	return halfT << i
+}
+
+func (i IntegrationTime) String() string {
+	switch i {
+	case IntegrationHalf:
+		return "½T"
+	case Integration1T, Integration2T, Integration4T:
+		return fmt.Sprintf("%dT", 1<<(i-1))
+	default:
+		return fmt.Sprintf("IntegrationTime(%d)", i)
+	}
+}
+
+// Risk is the exposure risk level of an UV index, as defined by the World
+// Health Organization.
+type Risk uint8
+
+// Risk levels.
+const (
+	Low Risk = iota
+	Moderate
+	High
+	VeryHigh
+	Extreme
+)
+
+func (r Risk) String() string {
+	if r > Extreme {
+		return fmt.Sprintf("Risk(%d)", r)
+	}
+	return riskNames[r]
+}
+
+// UV is a measurement.
+type UV struct {
+	// Count is the raw measurement, proportional to the UVA irradiance and
+	// to the integration time.
+	Count uint16
+	// Index is the UV index estimated from Count.
+	Index float64
+}
+
+// Risk returns the exposure risk level of the UV index.
+func (u UV) Risk() Risk {
+	switch {
+	case u.Index < 3:
+		return Low
+	case u.Index < 6:
+		return Moderate
+	case u.Index < 8:
+		return High
+	case u.Index < 11:
+		return VeryHigh
+	default:
+		return Extreme
+	}
+}
+
+func (u UV) String() string {
+	return fmt.Sprintf("UV index %.1f (%s)", u.Index, u.Risk())
+}
+
+// Opts holds the configuration options.
+type Opts struct {
+	IntegrationTime IntegrationTime
+}
+
+// DefaultOpts is the recommended default options.
+var DefaultOpts = Opts{
+	IntegrationTime: Integration1T,
+}
+
+// NewI2C returns a handle to a VEML6070 and starts measuring.
+func NewI2C(b i2c.Bus, opts *Opts) (*Dev, error) {
+	if opts.IntegrationTime > Integration4T {
+		return nil, fmt.Errorf("veml6070: invalid integration time %s", opts.IntegrationTime)
+	}
+	d := &Dev{
+		cmd: i2c.Dev{Bus: b, Addr: cmdAddr},
+		msb: i2c.Dev{Bus: b, Addr: msbAddr},
+		ara: i2c.Dev{Bus: b, Addr: araAddr},
+		it:  opts.IntegrationTime,
+	}
+	// Clear the acknowledge signal as required at power on. The read fails on
+	// some buses when no acknowledge is pending.
+	var b1 [1]byte
+	_ = d.ara.Tx(nil, b1[:])
+	if err := d.configure(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Dev is a handle to a VEML6070.
+type Dev struct {
+	cmd i2c.Dev
+	msb i2c.Dev
+	ara i2c.Dev
+
+	mu sync.Mutex
+	it IntegrationTime
+	// shutdown is set while the sensor is shut down by Halt.
+	shutdown bool
+	// started is when the sensor was last configured.
+	started time.Time
+	stop    chan struct{}
+	wg      sync.WaitGroup
+}
+
+func (d *Dev) String() string {
+	return fmt.Sprintf("VEML6070{%s}", d.cmd.Bus)
+}
+
+// SetIntegrationTime changes the integration time. The next measurement is
+// available after the new integration time.
+func (d *Dev) SetIntegrationTime(i IntegrationTime) error {
+	if i > Integration4T {
+		return fmt.Errorf("veml6070: invalid integration time %s", i)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.it = i
+	return d.configure()
+}
+
+// Sense returns the UV measurement.
+//
+// After the sensor was configured or halted, Sense waits for the first
+// measurement.
+func (d *Dev) Sense() (UV, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.stop != nil {
+		return UV{}, errors.New("veml6070: continuous sensing in progress")
+	}
+	return d.sense()
+}
+
+// SenseContinuous returns measurements every interval, on a continuous basis.
+//
+// The application must call Halt() to stop the sensing when done to shut the
+// sensor down and close the channel.
+func (d *Dev) SenseContinuous(interval time.Duration) (<-chan UV, error) {
+	d.mu.Lock()
+	need := d.it.Duration()
+	d.mu.Unlock()
+	if interval < need {
+		return nil, errors.New("veml6070: interval shorter than the integration time")
+	}
+	d.stopSensing()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	sensing := make(chan UV)
+	stop := make(chan struct{})
+	d.stop = stop
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		defer close(sensing)
+		d.sensingContinuous(interval, sensing, stop)
+	}()
+	return sensing, nil
+}
+
+// Halt stops continuous sensing, if any, and shuts the sensor down. The next
+// Sense powers it on again.
+func (d *Dev) Halt() error {
+	d.stopSensing()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.shutdown = true
+	return d.writeCommand(uint8(d.it)<<cmdITShift | cmdShutdown)
+}
+
+//
+
+// Fixed I²C addresses.
+const (
+	cmdAddr = 0x38 // Command write and LSB read.
+	msbAddr = 0x39 // MSB read.
+	araAddr = 0x0C // Alert Response Address, read to clear the acknowledge.
+)
+
+// Command bits.
+const (
+	cmdShutdown = 0x01
+	cmdReserved = 0x02 // Must be set.
+	cmdITShift  = 2
+)
+
+// halfT is the ½T integration time with a 270kΩ RSET.
+const halfT = 62500 * time.Microsecond
+
+// countsPerIndex is the count of one UV index at 1T with a 270kΩ RSET. The
+// application note puts the boundary of the moderate risk at 561 counts, for
+// an UV index of 3.
+const countsPerIndex = 560. / 3
+
+var riskNames = [...]string{"Low", "Moderate", "High", "Very High", "Extreme"}
+
+var sleep = time.Sleep
+
+// configure writes the command, which powers the sensor on.
+func (d *Dev) configure() error {
+	if err := d.writeCommand(uint8(d.it) << cmdITShift); err != nil {
+		return err
+	}
+	d.shutdown = false
+	d.started = time.Now()
+	return nil
+}
+
+func (d *Dev) sense() (UV, error) {
+	if d.shutdown {
+		if err := d.configure(); err != nil {
+			return UV{}, err
+		}
+	}
+	if w := d.it.Duration() - time.Since(d.started); w > 0 {
+		sleep(w)
+	}
+	var msb, lsb [1]byte
+	if err := d.msb.Tx(nil, msb[:]); err != nil {
+		return UV{}, fmt.Errorf("veml6070: %v", err)
+	}
+	if err := d.cmd.Tx(nil, lsb[:]); err != nil {
+		return UV{}, fmt.Errorf("veml6070: %v", err)
+	}
+	c := uint16(msb[0])<<8 | uint16(lsb[0])
+	// The count doubles with the integration time.
+	scale := float64(d.it.Duration()) / float64(2*halfT)
+	return UV{Count: c, Index: float64(c) / countsPerIndex / scale}, nil
+}
+
+// stopSensing stops the continuous sensing, if any. The lock must not be held,
+// since the sensing goroutine takes it after each tick.
+func (d *Dev) stopSensing() {
+	d.mu.Lock()
+	if d.stop != nil {
+		close(d.stop)
+		d.stop = nil
+	}
+	d.mu.Unlock()
+	d.wg.Wait()
+}
+
+func (d *Dev) sensingContinuous(interval time.Duration, sensing chan<- UV, stop <-chan struct{}) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+		}
+		d.mu.Lock()
+		uv, err := d.sense()
+		d.mu.Unlock()
+		if err != nil {
+			log.Printf("%s: failed to sense: %v", d, err)
+			return
+		}
+		select {
+		case sensing <- uv:
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (d *Dev) writeCommand(c uint8) error {
+	if err := d.cmd.Tx([]byte{c | cmdReserved}, nil); err != nil {
+		return fmt.Errorf("veml6070: %v", err)
+	}
+	return nil
+}
+
+var _ conn.Resource = &Dev{}
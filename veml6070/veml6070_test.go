@@ -0,0 +1,140 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package veml6070
+
+import (
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3/i2c/i2ctest"
+)
+
+var initOps = []i2ctest.IO{
+	{Addr: araAddr, R: []byte{0x00}},
+	{Addr: cmdAddr, W: []byte{0x06}},
+}
+
+func TestSense(t *testing.T) {
+	defer func(f func(time.Duration)) { sleep = f }(sleep)
+	var slept time.Duration
+	sleep = func(d time.Duration) { slept += d }
+	bus := i2ctest.Playback{
+		Ops: append(append([]i2ctest.IO{}, initOps...),
+			// 1T, 1120 counts.
+			i2ctest.IO{Addr: msbAddr, R: []byte{0x04}},
+			i2ctest.IO{Addr: cmdAddr, R: []byte{0x60}},
+			// 4T, 1120 counts.
+			i2ctest.IO{Addr: cmdAddr, W: []byte{0x0E}},
+			i2ctest.IO{Addr: msbAddr, R: []byte{0x04}},
+			i2ctest.IO{Addr: cmdAddr, R: []byte{0x60}},
+			// Halt then Sense powers the sensor on again.
+			i2ctest.IO{Addr: cmdAddr, W: []byte{0x0F}},
+			i2ctest.IO{Addr: cmdAddr, W: []byte{0x0E}},
+			i2ctest.IO{Addr: msbAddr, R: []byte{0x00}},
+			i2ctest.IO{Addr: cmdAddr, R: []byte{0x00}},
+		),
+	}
+	dev, err := NewI2C(&bus, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := dev.String(); s != "VEML6070{playback}" {
+		t.Fatal(s)
+	}
+	uv, err := dev.Sense()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uv.Count != 1120 || uv.Index != 6 || uv.Risk() != High {
+		t.Fatal(uv)
+	}
+	if s := uv.String(); s != "UV index 6.0 (High)" {
+		t.Fatal(s)
+	}
+	if slept <= 0 || slept > 125*time.Millisecond {
+		t.Fatal(slept)
+	}
+	if err := dev.SetIntegrationTime(Integration4T); err != nil {
+		t.Fatal(err)
+	}
+	if uv, err = dev.Sense(); err != nil {
+		t.Fatal(err)
+	}
+	if uv.Count != 1120 || uv.Index != 1.5 || uv.Risk() != Low {
+		t.Fatal(uv)
+	}
+	if err := dev.SetIntegrationTime(4); err == nil {
+		t.Fatal("invalid integration time")
+	}
+	if err := dev.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	if uv, err = dev.Sense(); err != nil || uv.Count != 0 {
+		t.Fatal(uv, err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSenseContinuous(t *testing.T) {
+	defer func(f func(time.Duration)) { sleep = f }(sleep)
+	sleep = func(time.Duration) {}
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: araAddr, R: []byte{0x00}},
+			{Addr: cmdAddr, W: []byte{0x02}},
+			{Addr: msbAddr, R: []byte{0x01}},
+			{Addr: cmdAddr, R: []byte{0x00}},
+			{Addr: cmdAddr, W: []byte{0x03}},
+		},
+	}
+	dev, err := NewI2C(&bus, &Opts{IntegrationTime: IntegrationHalf})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dev.SenseContinuous(time.Millisecond); err == nil {
+		t.Fatal("expected error")
+	}
+	c, err := dev.SenseContinuous(70 * time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dev.Sense(); err == nil {
+		t.Fatal("expected error while sensing continuously")
+	}
+	if uv := <-c; uv.Count != 256 {
+		t.Fatal(uv)
+	}
+	if err := dev.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := <-c; ok {
+		t.Fatal("channel not closed")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStrings(t *testing.T) {
+	data := []struct {
+		got, want string
+	}{
+		{IntegrationHalf.String(), "½T"},
+		{Integration4T.String(), "4T"},
+		{IntegrationTime(4).String(), "IntegrationTime(4)"},
+		{Extreme.String(), "Extreme"},
+		{Risk(5).String(), "Risk(5)"},
+	}
+	for _, line := range data {
+		if line.got != line.want {
+			t.Errorf("%q != %q", line.got, line.want)
+		}
+	}
+	if d := Integration2T.Duration(); d != 250*time.Millisecond {
+		t.Fatal(d)
+	}
+}
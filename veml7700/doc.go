@@ -0,0 +1,24 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package veml7700 controls a Vishay VEML7700 ambient light sensor over I²C.
+//
+// The illuminance is returned in lux as a physic.LuminousFlux, that is in
+// lumen per square metre. The resolution depends on the gain and the
+// integration time, from 0.0036lx to 1.8432lx. At the 1/4 and 1/8 gains, used
+// for bright light, the nonlinearity of the sensor is corrected as
+// recommended by Vishay.
+//
+// The sensor measures continuously. The power saving modes add a wait time
+// between the measurements to reduce the supply current, and Halt shuts the
+// sensor down until the next Sense.
+//
+// # Datasheet
+//
+// https://www.vishay.com/docs/84286/veml7700.pdf
+//
+// # Application note
+//
+// https://www.vishay.com/docs/84323/designingveml7700.pdf
+package veml7700
@@ -0,0 +1,423 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package veml7700
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/physic"
+)
+
+// I2CAddr is the fixed I²C address.
+const I2CAddr uint16 = 0x10
+
+// Gain is the sensitivity of the sensor.
+type Gain uint8
+
+// Possible gains.
+const (
+	Gain1   Gain = 0 // 1x
+	Gain2   Gain = 1 // 2x
+	Gain1_8 Gain = 2 // 1/8x
+	Gain1_4 Gain = 3 // 1/4x
+)
+
+func (g Gain) String() string {
+	switch g {
+	case Gain1:
+		return "1x"
+	case Gain2:
+		return "2x"
+	case Gain1_8:
+		return "1/8x"
+	case Gain1_4:
+		return "1/4x"
+	default:
+		return fmt.Sprintf("Gain(%d)", g)
+	}
+}
+
+// IntegrationTime is the duration of a measurement.
+type IntegrationTime uint8
+
+// Possible integration times.
+const (
+	Integration25ms  IntegrationTime = 0xC
+	Integration50ms  IntegrationTime = 0x8
+	Integration100ms IntegrationTime = 0x0
+	Integration200ms IntegrationTime = 0x1
+	Integration400ms IntegrationTime = 0x2
+	Integration800ms IntegrationTime = 0x3
+)
+
+// Duration returns the integration time as a time.Duration, or 0 if invalid.
+func (i IntegrationTime) Duration() time.Duration {
+	switch i {
+	case Integration25ms:
+		return 25 * time.Millisecond
+	case Integration50ms:
+		return 50 * time.Millisecond
+	case Integration100ms, Integration200ms, Integration400ms, Integration800ms:
+		return 100 * time.Millisecond << i
+	default:
+		return 0
+	}
+}
+
+func (i IntegrationTime) String() string {
+	if d := i.Duration(); d != 0 {
+		return d.String()
+	}
+	return fmt.Sprintf("IntegrationTime(%d)", i)
+}
+
+// PowerSaving is the power saving mode, which adds a wait time between the
+// measurements.
+type PowerSaving uint8
+
+// Possible power saving modes.
+const (
+	PowerSavingOff PowerSaving = iota
+	PowerSaving1               // 500ms wait.
+	PowerSaving2               // 1s wait.
+	PowerSaving3               // 2s wait.
+	PowerSaving4               // 4s wait.
+)
+
+func (p PowerSaving) String() string {
+	if p == PowerSavingOff {
+		return "Off"
+	}
+	if p > PowerSaving4 {
+		return fmt.Sprintf("PowerSaving(%d)", p)
+	}
+	return fmt.Sprintf("PowerSaving%d", p)
+}
+
+// Opts holds the configuration options.
+type Opts struct {
+	Gain            Gain
+	IntegrationTime IntegrationTime
+	PowerSaving     PowerSaving
+}
+
+// DefaultOpts is the recommended default options. The low gain avoids
+// saturating in daylight.
+var DefaultOpts = Opts{
+	Gain:            Gain1_8,
+	IntegrationTime: Integration100ms,
+	PowerSaving:     PowerSavingOff,
+}
+
+// NewI2C returns a handle to a VEML7700 and configures it.
+func NewI2C(b i2c.Bus, opts *Opts) (*Dev, error) {
+	if err := opts.check(); err != nil {
+		return nil, err
+	}
+	d := &Dev{c: i2c.Dev{Bus: b, Addr: I2CAddr}, opts: *opts}
+	if err := d.configure(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Dev is a handle to a VEML7700.
+type Dev struct {
+	c i2c.Dev
+
+	mu   sync.Mutex
+	opts Opts
+	// shutdown is set while the sensor is shut down by Halt.
+	shutdown bool
+	// started is when the sensor was last configured.
+	started time.Time
+	stop    chan struct{}
+	wg      sync.WaitGroup
+}
+
+func (d *Dev) String() string {
+	return fmt.Sprintf("VEML7700{%s}", &d.c)
+}
+
+// SetGain changes the gain. The next measurement is available after the
+// integration time.
+func (d *Dev) SetGain(g Gain) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	o := d.opts
+	o.Gain = g
+	return d.setOpts(&o)
+}
+
+// SetIntegrationTime changes the integration time. The next measurement is
+// available after the new integration time.
+func (d *Dev) SetIntegrationTime(i IntegrationTime) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	o := d.opts
+	o.IntegrationTime = i
+	return d.setOpts(&o)
+}
+
+// SetPowerSaving changes the power saving mode.
+func (d *Dev) SetPowerSaving(p PowerSaving) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	o := d.opts
+	o.PowerSaving = p
+	return d.setOpts(&o)
+}
+
+// Counts returns the raw counts of the ambient light and the white channels.
+func (d *Dev) Counts() (als, white uint16, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.wait(); err != nil {
+		return 0, 0, err
+	}
+	if als, err = d.readRegister(regALS); err != nil {
+		return 0, 0, err
+	}
+	if white, err = d.readRegister(regWhite); err != nil {
+		return 0, 0, err
+	}
+	return als, white, nil
+}
+
+// Sense returns the illuminance, in lux.
+//
+// After the sensor was configured or halted, Sense waits for the first
+// measurement.
+func (d *Dev) Sense() (physic.LuminousFlux, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.stop != nil {
+		return 0, errors.New("veml7700: continuous sensing in progress")
+	}
+	return d.sense()
+}
+
+// SenseContinuous returns measurements every interval, on a continuous basis.
+//
+// The application must call Halt() to stop the sensing when done to shut the
+// sensor down and close the channel.
+func (d *Dev) SenseContinuous(interval time.Duration) (<-chan physic.LuminousFlux, error) {
+	d.mu.Lock()
+	need := d.opts.refreshTime()
+	d.mu.Unlock()
+	if interval < need {
+		return nil, errors.New("veml7700: interval shorter than the measurement time")
+	}
+	d.stopSensing()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	sensing := make(chan physic.LuminousFlux)
+	stop := make(chan struct{})
+	d.stop = stop
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		defer close(sensing)
+		d.sensingContinuous(interval, sensing, stop)
+	}()
+	return sensing, nil
+}
+
+// Precision returns the resolution of the measurements, in lux.
+func (d *Dev) Precision() physic.LuminousFlux {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.opts.resolution()
+}
+
+// Halt stops continuous sensing, if any, and shuts the sensor down. The next
+// Sense powers it on again.
+func (d *Dev) Halt() error {
+	d.stopSensing()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.shutdown = true
+	return d.writeRegister(regConf, d.opts.conf()|confShutdown)
+}
+
+//
+
+// Registers.
+const (
+	regConf        = 0x00
+	regPowerSaving = 0x03
+	regALS         = 0x04
+	regWhite       = 0x05
+)
+
+// Configuration register bits.
+const (
+	confShutdown  = 0x0001
+	confITShift   = 6
+	confGainShift = 11
+)
+
+// powerOnTime is the wait time after powering the sensor on.
+const powerOnTime = 3 * time.Millisecond
+
+var sleep = time.Sleep
+
+func (o *Opts) check() error {
+	if o.Gain > Gain1_4 {
+		return fmt.Errorf("veml7700: invalid gain %s", o.Gain)
+	}
+	if o.IntegrationTime.Duration() == 0 {
+		return fmt.Errorf("veml7700: invalid integration time %s", o.IntegrationTime)
+	}
+	if o.PowerSaving > PowerSaving4 {
+		return fmt.Errorf("veml7700: invalid power saving mode %s", o.PowerSaving)
+	}
+	return nil
+}
+
+func (o *Opts) conf() uint16 {
+	return uint16(o.Gain)<<confGainShift | uint16(o.IntegrationTime)<<confITShift
+}
+
+// refreshTime is the time between two measurements.
+func (o *Opts) refreshTime() time.Duration {
+	t := o.IntegrationTime.Duration()
+	if o.PowerSaving != PowerSavingOff {
+		t += 250 * time.Millisecond << o.PowerSaving
+	}
+	return t
+}
+
+// resolution is the illuminance of a count: 0.0036lx at 2x gain and 800ms
+// integration time, inversely proportional to both.
+func (o *Opts) resolution() physic.LuminousFlux {
+	eighths := [...]int64{Gain1: 8, Gain2: 16, Gain1_8: 1, Gain1_4: 2}[o.Gain]
+	ms := int64(o.IntegrationTime.Duration() / time.Millisecond)
+	return physic.LuminousFlux(3600 * 800 * 16 * int64(physic.MilliLumen) / 1000 / (ms * eighths))
+}
+
+// setOpts validates and applies o.
+func (d *Dev) setOpts(o *Opts) error {
+	if err := o.check(); err != nil {
+		return err
+	}
+	d.opts = *o
+	return d.configure()
+}
+
+// configure writes the options and powers the sensor on.
+func (d *Dev) configure() error {
+	ps := uint16(0)
+	if d.opts.PowerSaving != PowerSavingOff {
+		ps = uint16(d.opts.PowerSaving-1)<<1 | 1
+	}
+	if err := d.writeRegister(regPowerSaving, ps); err != nil {
+		return err
+	}
+	if err := d.writeRegister(regConf, d.opts.conf()); err != nil {
+		return err
+	}
+	d.shutdown = false
+	d.started = time.Now()
+	sleep(powerOnTime)
+	return nil
+}
+
+// wait powers the sensor on if needed and waits for the first measurement.
+func (d *Dev) wait() error {
+	if d.shutdown {
+		if err := d.configure(); err != nil {
+			return err
+		}
+	}
+	if w := d.opts.refreshTime() - time.Since(d.started); w > 0 {
+		sleep(w)
+	}
+	return nil
+}
+
+func (d *Dev) sense() (physic.LuminousFlux, error) {
+	if err := d.wait(); err != nil {
+		return 0, err
+	}
+	raw, err := d.readRegister(regALS)
+	if err != nil {
+		return 0, err
+	}
+	return d.toLux(raw), nil
+}
+
+// toLux converts the raw count to lux, correcting the nonlinearity at low
+// gains as recommended in the application note.
+func (d *Dev) toLux(raw uint16) physic.LuminousFlux {
+	lux := physic.LuminousFlux(raw) * d.opts.resolution()
+	if d.opts.Gain != Gain1_8 && d.opts.Gain != Gain1_4 {
+		return lux
+	}
+	l := float64(lux) / float64(physic.Lumen)
+	l = ((6.0135e-13*l-9.3924e-9)*l+8.1488e-5)*l*l + 1.0023*l
+	return physic.LuminousFlux(l * float64(physic.Lumen))
+}
+
+// stopSensing stops the continuous sensing, if any. The lock must not be held,
+// since the sensing goroutine takes it after each tick.
+func (d *Dev) stopSensing() {
+	d.mu.Lock()
+	if d.stop != nil {
+		close(d.stop)
+		d.stop = nil
+	}
+	d.mu.Unlock()
+	d.wg.Wait()
+}
+
+func (d *Dev) sensingContinuous(interval time.Duration, sensing chan<- physic.LuminousFlux, stop <-chan struct{}) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+		}
+		d.mu.Lock()
+		lux, err := d.sense()
+		d.mu.Unlock()
+		if err != nil {
+			log.Printf("%s: failed to sense: %v", d, err)
+			return
+		}
+		select {
+		case sensing <- lux:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// readRegister reads a little endian 16 bits register.
+func (d *Dev) readRegister(reg uint8) (uint16, error) {
+	var b [2]byte
+	if err := d.c.Tx([]byte{reg}, b[:]); err != nil {
+		return 0, fmt.Errorf("veml7700: %v", err)
+	}
+	return uint16(b[0]) | uint16(b[1])<<8, nil
+}
+
+// writeRegister writes a little endian 16 bits register.
+func (d *Dev) writeRegister(reg uint8, v uint16) error {
+	if err := d.c.Tx([]byte{reg, byte(v), byte(v >> 8)}, nil); err != nil {
+		return fmt.Errorf("veml7700: %v", err)
+	}
+	return nil
+}
+
+var _ conn.Resource = &Dev{}
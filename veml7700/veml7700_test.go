@@ -0,0 +1,170 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package veml7700
+
+import (
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3/i2c/i2ctest"
+	"periph.io/x/conn/v3/physic"
+)
+
+var initOps = []i2ctest.IO{
+	{Addr: I2CAddr, W: []byte{regPowerSaving, 0x00, 0x00}},
+	{Addr: I2CAddr, W: []byte{regConf, 0x00, 0x10}},
+}
+
+func TestSense(t *testing.T) {
+	defer func(f func(time.Duration)) { sleep = f }(sleep)
+	var slept time.Duration
+	sleep = func(d time.Duration) { slept += d }
+	bus := i2ctest.Playback{
+		Ops: append(append([]i2ctest.IO{}, initOps...),
+			// 1/8x, 100ms, with the nonlinearity correction.
+			i2ctest.IO{Addr: I2CAddr, W: []byte{regALS}, R: []byte{0xE8, 0x03}},
+			// 2x, 100ms.
+			i2ctest.IO{Addr: I2CAddr, W: []byte{regPowerSaving, 0x00, 0x00}},
+			i2ctest.IO{Addr: I2CAddr, W: []byte{regConf, 0x00, 0x08}},
+			i2ctest.IO{Addr: I2CAddr, W: []byte{regALS}, R: []byte{0xE8, 0x03}},
+			i2ctest.IO{Addr: I2CAddr, W: []byte{regALS}, R: []byte{0x10, 0x00}},
+			i2ctest.IO{Addr: I2CAddr, W: []byte{regWhite}, R: []byte{0x20, 0x00}},
+			// Halt then Sense powers the sensor on again.
+			i2ctest.IO{Addr: I2CAddr, W: []byte{regConf, 0x01, 0x08}},
+			i2ctest.IO{Addr: I2CAddr, W: []byte{regPowerSaving, 0x00, 0x00}},
+			i2ctest.IO{Addr: I2CAddr, W: []byte{regConf, 0x00, 0x08}},
+			i2ctest.IO{Addr: I2CAddr, W: []byte{regALS}, R: []byte{0x00, 0x00}},
+		),
+	}
+	dev, err := NewI2C(&bus, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := dev.String(); s != "VEML7700{playback(16)}" {
+		t.Fatal(s)
+	}
+	lux, err := dev.Sense()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lux < 478200*physic.MilliLumen || lux > 478300*physic.MilliLumen {
+		t.Fatal(lux)
+	}
+	if slept < DefaultOpts.IntegrationTime.Duration() {
+		t.Fatal(slept)
+	}
+	if err := dev.SetGain(Gain2); err != nil {
+		t.Fatal(err)
+	}
+	if p := dev.Precision(); p != 28800*physic.MicroLumen {
+		t.Fatal(p)
+	}
+	if lux, err = dev.Sense(); err != nil {
+		t.Fatal(err)
+	}
+	if lux != 28800*physic.MilliLumen {
+		t.Fatal(lux)
+	}
+	als, white, err := dev.Counts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if als != 16 || white != 32 {
+		t.Fatal(als, white)
+	}
+	if err := dev.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	if lux, err = dev.Sense(); err != nil || lux != 0 {
+		t.Fatal(lux, err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOpts(t *testing.T) {
+	if _, err := NewI2C(&i2ctest.Playback{}, &Opts{Gain: 4}); err == nil {
+		t.Fatal("invalid gain")
+	}
+	if _, err := NewI2C(&i2ctest.Playback{}, &Opts{IntegrationTime: 4}); err == nil {
+		t.Fatal("invalid integration time")
+	}
+	if _, err := NewI2C(&i2ctest.Playback{}, &Opts{PowerSaving: 5}); err == nil {
+		t.Fatal("invalid power saving mode")
+	}
+	data := []struct {
+		o    Opts
+		res  physic.LuminousFlux
+		wait time.Duration
+	}{
+		{Opts{Gain: Gain2, IntegrationTime: Integration800ms}, 3600 * physic.MicroLumen, 800 * time.Millisecond},
+		{Opts{Gain: Gain1_8, IntegrationTime: Integration25ms}, 1843200 * physic.MicroLumen, 25 * time.Millisecond},
+		{Opts{Gain: Gain1_4, IntegrationTime: Integration50ms, PowerSaving: PowerSaving1}, 460800 * physic.MicroLumen, 550 * time.Millisecond},
+		{Opts{Gain: Gain1, IntegrationTime: Integration400ms, PowerSaving: PowerSaving4}, 14400 * physic.MicroLumen, 4400 * time.Millisecond},
+	}
+	for i, line := range data {
+		if r := line.o.resolution(); r != line.res {
+			t.Errorf("#%d: resolution() = %s, want %s", i, r, line.res)
+		}
+		if w := line.o.refreshTime(); w != line.wait {
+			t.Errorf("#%d: refreshTime() = %s, want %s", i, w, line.wait)
+		}
+	}
+	if s := Gain1_4.String(); s != "1/4x" {
+		t.Fatal(s)
+	}
+	if s := Integration200ms.String(); s != "200ms" {
+		t.Fatal(s)
+	}
+	if s := PowerSaving3.String(); s != "PowerSaving3" {
+		t.Fatal(s)
+	}
+}
+
+func TestSenseContinuous(t *testing.T) {
+	defer func(f func(time.Duration)) { sleep = f }(sleep)
+	sleep = func(time.Duration) {}
+	opts := Opts{Gain: Gain1, IntegrationTime: Integration25ms, PowerSaving: PowerSaving1}
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: I2CAddr, W: []byte{regPowerSaving, 0x01, 0x00}},
+			{Addr: I2CAddr, W: []byte{regConf, 0x00, 0x03}},
+			{Addr: I2CAddr, W: []byte{regPowerSaving, 0x00, 0x00}},
+			{Addr: I2CAddr, W: []byte{regConf, 0x00, 0x03}},
+			{Addr: I2CAddr, W: []byte{regALS}, R: []byte{0x0A, 0x00}},
+			{Addr: I2CAddr, W: []byte{regConf, 0x01, 0x03}},
+		},
+	}
+	dev, err := NewI2C(&bus, &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dev.SenseContinuous(100 * time.Millisecond); err == nil {
+		t.Fatal("expected error")
+	}
+	if err := dev.SetPowerSaving(PowerSavingOff); err != nil {
+		t.Fatal(err)
+	}
+	c, err := dev.SenseContinuous(30 * time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dev.Sense(); err == nil {
+		t.Fatal("expected error while sensing continuously")
+	}
+	if lux := <-c; lux != 10*230400*physic.MicroLumen {
+		t.Fatal(lux)
+	}
+	if err := dev.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := <-c; ok {
+		t.Fatal("channel not closed")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,26 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package vl53l0x controls a ST VL53L0X time-of-flight ranging sensor over
+// I²C.
+//
+// The sensor measures the distance to a target up to about 2m. ST does not
+// publish a register map, the initialization, tuning and timing budget
+// sequences follow the ones of the ST API, as reimplemented by Pololu.
+//
+// # Timing budget
+//
+// The timing budget is the time allowed for one measurement. A longer budget
+// improves the accuracy, from about 20ms up to a few hundreds of ms.
+//
+// # Distance mode
+//
+// DistanceLong lowers the signal rate limit and lengthens the laser pulses to
+// range farther, at the cost of more noise and more sensitivity to ambient
+// light.
+//
+// # Datasheet
+//
+// https://www.st.com/resource/en/datasheet/vl53l0x.pdf
+package vl53l0x
@@ -0,0 +1,274 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package vl53l0x
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Overheads of the measurement sequence steps, in µs.
+const (
+	startOverhead      = 1910
+	endOverhead        = 960
+	msrcOverhead       = 660
+	tccOverhead        = 590
+	dssOverhead        = 690
+	preRangeOverhead   = 660
+	finalRangeOverhead = 550
+
+	// minTimingBudget is the shortest timing budget in µs.
+	minTimingBudget = 20000
+)
+
+// sequenceSteps are the steps of a measurement, from SYSTEM_SEQUENCE_CONFIG,
+// and their timeouts.
+type sequenceSteps struct {
+	tcc, dss, msrc, preRange, finalRange bool
+
+	preRangeVCSEL, finalRangeVCSEL uint32
+	msrcDSSTCCMclks, preRangeMclks uint32
+	msrcDSSTCCUS, preRangeUS       uint32
+	finalRangeUS                   uint32
+}
+
+func (d *Dev) sequenceSteps() (*sequenceSteps, error) {
+	c, err := d.readReg(regSystemSequenceConfig)
+	if err != nil {
+		return nil, err
+	}
+	s := &sequenceSteps{
+		tcc:        c&0x10 != 0,
+		dss:        c&0x08 != 0,
+		msrc:       c&0x04 != 0,
+		preRange:   c&0x40 != 0,
+		finalRange: c&0x80 != 0,
+	}
+	v, err := d.readReg(regPreRangeVCSELPeriod)
+	if err != nil {
+		return nil, err
+	}
+	s.preRangeVCSEL = decodeVCSELPeriod(v)
+	if v, err = d.readReg(regMSRCConfigTimeout); err != nil {
+		return nil, err
+	}
+	s.msrcDSSTCCMclks = uint32(v) + 1
+	s.msrcDSSTCCUS = mclksToMicroseconds(s.msrcDSSTCCMclks, s.preRangeVCSEL)
+	t, err := d.readReg16(regPreRangeTimeout)
+	if err != nil {
+		return nil, err
+	}
+	s.preRangeMclks = decodeTimeout(t)
+	s.preRangeUS = mclksToMicroseconds(s.preRangeMclks, s.preRangeVCSEL)
+	if v, err = d.readReg(regFinalRangeVCSELPeriod); err != nil {
+		return nil, err
+	}
+	s.finalRangeVCSEL = decodeVCSELPeriod(v)
+	if t, err = d.readReg16(regFinalRangeTimeout); err != nil {
+		return nil, err
+	}
+	final := decodeTimeout(t)
+	if s.preRange {
+		final -= s.preRangeMclks
+	}
+	s.finalRangeUS = mclksToMicroseconds(final, s.finalRangeVCSEL)
+	return s, nil
+}
+
+// overhead returns the time used by all the steps but the final range, in
+// µs.
+func (s *sequenceSteps) overhead() uint32 {
+	us := uint32(startOverhead + endOverhead)
+	if s.tcc {
+		us += s.msrcDSSTCCUS + tccOverhead
+	}
+	if s.dss {
+		us += 2 * (s.msrcDSSTCCUS + dssOverhead)
+	} else if s.msrc {
+		us += s.msrcDSSTCCUS + msrcOverhead
+	}
+	if s.preRange {
+		us += s.preRangeUS + preRangeOverhead
+	}
+	return us
+}
+
+// timingBudget computes the measurement timing budget from the device
+// configuration, in µs.
+func (d *Dev) timingBudget() (uint32, error) {
+	s, err := d.sequenceSteps()
+	if err != nil {
+		return 0, err
+	}
+	us := s.overhead()
+	if s.finalRange {
+		us += s.finalRangeUS + finalRangeOverhead
+	}
+	return us, nil
+}
+
+// setTimingBudgetUS sets the final range timeout to fit the budget, in µs.
+func (d *Dev) setTimingBudgetUS(budget uint32) error {
+	s, err := d.sequenceSteps()
+	if err != nil {
+		return err
+	}
+	if s.finalRange {
+		used := s.overhead() + finalRangeOverhead
+		if used > budget {
+			return fmt.Errorf("vl53l0x: timing budget too short, need more than %dµs", used)
+		}
+		mclks := microsecondsToMclks(budget-used, s.finalRangeVCSEL)
+		if s.preRange {
+			mclks += s.preRangeMclks
+		}
+		if err := d.writeReg16(regFinalRangeTimeout, encodeTimeout(mclks)); err != nil {
+			return err
+		}
+	}
+	d.budget = budget
+	return nil
+}
+
+// setVCSELPeriods sets the pre-range and final range VCSEL pulse periods, in
+// PCLKs, keeping the step timeouts and the timing budget, then runs a phase
+// calibration.
+func (d *Dev) setVCSELPeriods(pre, final byte) error {
+	var preHigh byte
+	switch pre {
+	case 12:
+		preHigh = 0x18
+	case 14:
+		preHigh = 0x30
+	case 16:
+		preHigh = 0x40
+	case 18:
+		preHigh = 0x50
+	default:
+		return errors.New("vl53l0x: invalid pre-range VCSEL period")
+	}
+	// Valid phase high, VCSEL width, phasecal timeout and limit.
+	var f [4]byte
+	switch final {
+	case 8:
+		f = [4]byte{0x10, 0x02, 0x0C, 0x30}
+	case 10:
+		f = [4]byte{0x28, 0x03, 0x09, 0x20}
+	case 12:
+		f = [4]byte{0x38, 0x03, 0x08, 0x20}
+	case 14:
+		f = [4]byte{0x48, 0x03, 0x07, 0x20}
+	default:
+		return errors.New("vl53l0x: invalid final range VCSEL period")
+	}
+	s, err := d.sequenceSteps()
+	if err != nil {
+		return err
+	}
+
+	if err := d.writeRegs([][2]byte{
+		{regPreRangeValidPhaseHigh, preHigh},
+		{regPreRangeValidPhaseLow, 0x08},
+		{regPreRangeVCSELPeriod, encodeVCSELPeriod(pre)},
+	}); err != nil {
+		return err
+	}
+	preMclks := microsecondsToMclks(s.preRangeUS, uint32(pre))
+	if err := d.writeReg16(regPreRangeTimeout, encodeTimeout(preMclks)); err != nil {
+		return err
+	}
+	msrc := microsecondsToMclks(s.msrcDSSTCCUS, uint32(pre))
+	if msrc > 256 {
+		msrc = 256
+	} else if msrc == 0 {
+		msrc = 1
+	}
+	if err := d.writeReg(regMSRCConfigTimeout, byte(msrc-1)); err != nil {
+		return err
+	}
+
+	if err := d.writeRegs([][2]byte{
+		{regFinalRangeValidPhaseHigh, f[0]},
+		{regFinalRangeValidPhaseLow, 0x08},
+		{regGlobalConfigVCSELWidth, f[1]},
+		{regAlgoPhasecalConfigTO, f[2]},
+		{0xFF, 0x01},
+		{regAlgoPhasecalLim, f[3]},
+		{0xFF, 0x00},
+		{regFinalRangeVCSELPeriod, encodeVCSELPeriod(final)},
+	}); err != nil {
+		return err
+	}
+	finalMclks := microsecondsToMclks(s.finalRangeUS, uint32(final))
+	if s.preRange {
+		finalMclks += preMclks
+	}
+	if err := d.writeReg16(regFinalRangeTimeout, encodeTimeout(finalMclks)); err != nil {
+		return err
+	}
+
+	budget := d.budget
+	if budget == 0 {
+		budget = s.overhead() + s.finalRangeUS + finalRangeOverhead
+	}
+	if err := d.setTimingBudgetUS(budget); err != nil {
+		return err
+	}
+	c, err := d.readReg(regSystemSequenceConfig)
+	if err != nil {
+		return err
+	}
+	if err := d.writeReg(regSystemSequenceConfig, 0x02); err != nil {
+		return err
+	}
+	if err := d.refCalibration(0x00); err != nil {
+		return err
+	}
+	return d.writeReg(regSystemSequenceConfig, c)
+}
+
+// decodeVCSELPeriod returns the VCSEL pulse period in PCLKs from the register
+// value.
+func decodeVCSELPeriod(v byte) uint32 {
+	return (uint32(v) + 1) << 1
+}
+
+func encodeVCSELPeriod(pclks byte) byte {
+	return pclks>>1 - 1
+}
+
+// macroPeriod returns the macro period in ns for a VCSEL period in PCLKs.
+func macroPeriod(vcsel uint32) uint32 {
+	return (2304*vcsel*1655 + 500) / 1000
+}
+
+func mclksToMicroseconds(mclks, vcsel uint32) uint32 {
+	p := uint64(macroPeriod(vcsel))
+	return uint32((uint64(mclks)*p + 500) / 1000)
+}
+
+func microsecondsToMclks(us, vcsel uint32) uint32 {
+	p := uint64(macroPeriod(vcsel))
+	return uint32((uint64(us)*1000 + p/2) / p)
+}
+
+// decodeTimeout decodes a timeout register, in (LSB * 2^MSB) + 1 format, to
+// MCLKs.
+func decodeTimeout(v uint16) uint32 {
+	return uint32(v&0xFF)<<(v>>8) + 1
+}
+
+func encodeTimeout(mclks uint32) uint16 {
+	if mclks == 0 {
+		return 0
+	}
+	ls := mclks - 1
+	ms := uint16(0)
+	for ls&^0xFF != 0 {
+		ls >>= 1
+		ms++
+	}
+	return ms<<8 | uint16(ls)
+}
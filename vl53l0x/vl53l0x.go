@@ -0,0 +1,623 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package vl53l0x
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/physic"
+)
+
+// I2CAddr is the default I²C address of the device.
+const I2CAddr uint16 = 0x29
+
+// ErrOutOfRange is returned by Range when no target was detected.
+var ErrOutOfRange = errors.New("vl53l0x: no target in range")
+
+// DistanceMode trades the maximum range against the accuracy.
+type DistanceMode int
+
+const (
+	// DistanceStandard ranges up to about 1.2m.
+	DistanceStandard DistanceMode = iota
+	// DistanceLong ranges up to about 2m in the dark.
+	DistanceLong
+)
+
+func (m DistanceMode) String() string {
+	switch m {
+	case DistanceStandard:
+		return "Standard"
+	case DistanceLong:
+		return "Long"
+	default:
+		return "DistanceMode(?)"
+	}
+}
+
+// Opts holds the configuration options.
+type Opts struct {
+	// TimingBudget is the time allowed for one measurement, at least 20ms.
+	// 0 means 33ms.
+	TimingBudget time.Duration
+	// DistanceMode selects the range profile.
+	DistanceMode DistanceMode
+	// IO1V8 keeps the I/O pins in 1.8V mode. By default they are switched to
+	// 2.8V, as required by most breakout boards.
+	IO1V8 bool
+}
+
+// DefaultOpts is the recommended default options.
+var DefaultOpts = Opts{
+	TimingBudget: 33 * time.Millisecond,
+	DistanceMode: DistanceStandard,
+}
+
+// Dev is a handle to a VL53L0X.
+type Dev struct {
+	mu sync.Mutex
+	c  i2c.Dev
+	// stopVariable is read from the device during initialization and written
+	// back before each ranging.
+	stopVariable byte
+	// budget is the measurement timing budget in µs.
+	budget uint32
+	mode   DistanceMode
+	stop   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewI2C opens a handle to the device at addr, runs the initialization and
+// reference calibration sequences and applies opts.
+//
+// To use on the default address, vl53l0x.I2CAddr must be passed as argument.
+func NewI2C(bus i2c.Bus, addr uint16, opts *Opts) (*Dev, error) {
+	budget := opts.TimingBudget
+	if budget == 0 {
+		budget = DefaultOpts.TimingBudget
+	}
+	d := &Dev{c: i2c.Dev{Bus: bus, Addr: addr}}
+	id, err := d.readReg(regModelID)
+	if err != nil {
+		return nil, err
+	}
+	if id != modelID {
+		return nil, fmt.Errorf("vl53l0x: unexpected model ID %#x", id)
+	}
+	if err := d.init(!opts.IO1V8); err != nil {
+		return nil, err
+	}
+	if err := d.setDistanceMode(opts.DistanceMode); err != nil {
+		return nil, err
+	}
+	if err := d.setTimingBudget(budget); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *Dev) String() string {
+	return fmt.Sprintf("VL53L0X{%s}", &d.c)
+}
+
+// Range runs a single measurement and returns the distance to the target.
+//
+// It returns ErrOutOfRange when no target was detected.
+func (d *Dev) Range() (physic.Distance, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.stop != nil {
+		return 0, errors.New("vl53l0x: continuous ranging in progress")
+	}
+	if err := d.writeStopVariable(); err != nil {
+		return 0, err
+	}
+	if err := d.writeReg(regSysRangeStart, 0x01); err != nil {
+		return 0, err
+	}
+	if err := d.waitReg(regSysRangeStart, 0x01, false); err != nil {
+		return 0, err
+	}
+	return d.readRange()
+}
+
+// RangeContinuous starts continuous ranging and returns the distances every
+// interval, which must be at least the timing budget.
+//
+// Measurements without a target in range are skipped. The application must
+// call Halt to stop the ranging and close the channel.
+//
+// It's the responsibility of the caller to retrieve the values from the
+// channel as fast as possible, otherwise the interval may not be respected.
+func (d *Dev) RangeContinuous(interval time.Duration) (<-chan physic.Distance, error) {
+	d.mu.Lock()
+	budget := time.Duration(d.budget) * time.Microsecond
+	d.mu.Unlock()
+	if interval < budget {
+		return nil, errors.New("vl53l0x: interval shorter than the timing budget")
+	}
+	d.stopRanging()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.startContinuous(interval); err != nil {
+		return nil, err
+	}
+	ranging := make(chan physic.Distance)
+	stop := make(chan struct{})
+	d.stop = stop
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		defer close(ranging)
+		d.rangingContinuous(interval, ranging, stop)
+	}()
+	return ranging, nil
+}
+
+// SetTimingBudget sets the time allowed for one measurement, at least 20ms.
+func (d *Dev) SetTimingBudget(budget time.Duration) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.setTimingBudget(budget)
+}
+
+// TimingBudget returns the time allowed for one measurement.
+func (d *Dev) TimingBudget() time.Duration {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return time.Duration(d.budget) * time.Microsecond
+}
+
+// SetDistanceMode changes the range profile. The timing budget is kept.
+func (d *Dev) SetDistanceMode(m DistanceMode) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.setDistanceMode(m)
+}
+
+// DistanceMode returns the current range profile.
+func (d *Dev) DistanceMode() DistanceMode {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.mode
+}
+
+// Halt stops continuous ranging, if any.
+//
+// Halt implements conn.Resource.
+func (d *Dev) Halt() error {
+	if !d.stopRanging() {
+		return nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.stopContinuous()
+}
+
+//
+
+// Registers.
+const (
+	regSysRangeStart            = 0x00
+	regSystemSequenceConfig     = 0x01
+	regSystemIntermeasurement   = 0x04
+	regSystemInterruptConfig    = 0x0A
+	regSystemInterruptClear     = 0x0B
+	regResultInterruptStatus    = 0x13
+	regResultRangeStatus        = 0x14
+	regAlgoPhasecalConfigTO     = 0x30
+	regGlobalConfigVCSELWidth   = 0x32
+	regFinalRangeMinCountRate   = 0x44
+	regMSRCConfigTimeout        = 0x46
+	regFinalRangeValidPhaseLow  = 0x47
+	regFinalRangeValidPhaseHigh = 0x48
+	regDynamicSPADNumRequested  = 0x4E
+	regDynamicSPADStartOffset   = 0x4F
+	regPreRangeVCSELPeriod      = 0x50
+	regPreRangeTimeout          = 0x51
+	regPreRangeValidPhaseLow    = 0x56
+	regPreRangeValidPhaseHigh   = 0x57
+	regMSRCConfigControl        = 0x60
+	regFinalRangeVCSELPeriod    = 0x70
+	regFinalRangeTimeout        = 0x71
+	regGPIOHVMuxActiveHigh      = 0x84
+	regVHVConfigPadSCLSDA       = 0x89
+	regGlobalConfigSPADEnables  = 0xB0
+	regGlobalConfigRefEnStart   = 0xB6
+	regModelID                  = 0xC0
+	regOscCalibrateVal          = 0xF8
+	// regAlgoPhasecalLim is on page 1.
+	regAlgoPhasecalLim = 0x30
+
+	modelID = 0xEE
+)
+
+const (
+	// ioTimeout is the longest time the device may take to answer.
+	ioTimeout    = 500 * time.Millisecond
+	pollInterval = time.Millisecond
+	// outOfRange is the smallest distance in mm reported without a target.
+	outOfRange = 8190
+)
+
+var sleep = time.Sleep
+
+// tuningSettings is the default tuning of the ST API, applied during
+// initialization.
+var tuningSettings = [][2]byte{
+	{0xFF, 0x01}, {0x00, 0x00},
+	{0xFF, 0x00}, {0x09, 0x00}, {0x10, 0x00}, {0x11, 0x00},
+	{0x24, 0x01}, {0x25, 0xFF}, {0x75, 0x00},
+	{0xFF, 0x01}, {0x4E, 0x2C}, {0x48, 0x00}, {0x30, 0x20},
+	{0xFF, 0x00}, {0x30, 0x09}, {0x54, 0x00}, {0x31, 0x04},
+	{0x32, 0x03}, {0x40, 0x83}, {0x46, 0x25}, {0x60, 0x00},
+	{0x27, 0x00}, {0x50, 0x06}, {0x51, 0x00}, {0x52, 0x96},
+	{0x56, 0x08}, {0x57, 0x30}, {0x61, 0x00}, {0x62, 0x00},
+	{0x64, 0x00}, {0x65, 0x00}, {0x66, 0xA0},
+	{0xFF, 0x01}, {0x22, 0x32}, {0x47, 0x14}, {0x49, 0xFF}, {0x4A, 0x00},
+	{0xFF, 0x00}, {0x7A, 0x0A}, {0x7B, 0x00}, {0x78, 0x21},
+	{0xFF, 0x01}, {0x23, 0x34}, {0x42, 0x00}, {0x44, 0xFF},
+	{0x45, 0x26}, {0x46, 0x05}, {0x40, 0x40}, {0x0E, 0x06},
+	{0x20, 0x1A}, {0x43, 0x40},
+	{0xFF, 0x00}, {0x34, 0x03}, {0x35, 0x44},
+	{0xFF, 0x01}, {0x31, 0x04}, {0x4B, 0x09}, {0x4C, 0x05}, {0x4D, 0x04},
+	{0xFF, 0x00}, {0x44, 0x00}, {0x45, 0x20}, {0x47, 0x08},
+	{0x48, 0x28}, {0x67, 0x00}, {0x70, 0x04}, {0x71, 0x01},
+	{0x72, 0xFE}, {0x76, 0x00}, {0x77, 0x00},
+	{0xFF, 0x01}, {0x0D, 0x01},
+	{0xFF, 0x00}, {0x80, 0x01}, {0x01, 0xF8},
+	{0xFF, 0x01}, {0x8E, 0x01}, {0x00, 0x01},
+	{0xFF, 0x00}, {0x80, 0x00},
+}
+
+// init runs the DataInit, StaticInit and PerformRefCalibration steps of the
+// ST API.
+func (d *Dev) init(io2V8 bool) error {
+	if io2V8 {
+		if err := d.updateReg(regVHVConfigPadSCLSDA, 0x01, 0x01); err != nil {
+			return err
+		}
+	}
+	// Set I²C standard mode and read the stop variable.
+	if err := d.writeRegs([][2]byte{{0x88, 0x00}, {0x80, 0x01}, {0xFF, 0x01}, {0x00, 0x00}}); err != nil {
+		return err
+	}
+	v, err := d.readReg(0x91)
+	if err != nil {
+		return err
+	}
+	d.stopVariable = v
+	if err := d.writeRegs([][2]byte{{0x00, 0x01}, {0xFF, 0x00}, {0x80, 0x00}}); err != nil {
+		return err
+	}
+	// Disable the MSRC and pre-range signal rate limit checks.
+	if err := d.updateReg(regMSRCConfigControl, 0x12, 0x12); err != nil {
+		return err
+	}
+	if err := d.writeReg(regSystemSequenceConfig, 0xFF); err != nil {
+		return err
+	}
+
+	if err := d.setReferenceSPADs(); err != nil {
+		return err
+	}
+	if err := d.writeRegs(tuningSettings); err != nil {
+		return err
+	}
+	// Interrupt on new sample ready, active low.
+	if err := d.writeReg(regSystemInterruptConfig, 0x04); err != nil {
+		return err
+	}
+	if err := d.updateReg(regGPIOHVMuxActiveHigh, 0x10, 0x00); err != nil {
+		return err
+	}
+	if err := d.writeReg(regSystemInterruptClear, 0x01); err != nil {
+		return err
+	}
+	budget, err := d.timingBudget()
+	if err != nil {
+		return err
+	}
+	// Disable MSRC and TCC, then recalculate the timing budget.
+	if err := d.writeReg(regSystemSequenceConfig, 0xE8); err != nil {
+		return err
+	}
+	if err := d.setTimingBudgetUS(budget); err != nil {
+		return err
+	}
+
+	// VHV then phase calibrations.
+	if err := d.writeReg(regSystemSequenceConfig, 0x01); err != nil {
+		return err
+	}
+	if err := d.refCalibration(0x40); err != nil {
+		return err
+	}
+	if err := d.writeReg(regSystemSequenceConfig, 0x02); err != nil {
+		return err
+	}
+	if err := d.refCalibration(0x00); err != nil {
+		return err
+	}
+	return d.writeReg(regSystemSequenceConfig, 0xE8)
+}
+
+// setReferenceSPADs enables the reference SPADs as counted in the NVM.
+func (d *Dev) setReferenceSPADs() error {
+	count, aperture, err := d.spadInfo()
+	if err != nil {
+		return err
+	}
+	var m [6]byte
+	if err := d.c.Tx([]byte{regGlobalConfigSPADEnables}, m[:]); err != nil {
+		return err
+	}
+	if err := d.writeRegs([][2]byte{
+		{0xFF, 0x01},
+		{regDynamicSPADStartOffset, 0x00},
+		{regDynamicSPADNumRequested, 0x2C},
+		{0xFF, 0x00},
+		{regGlobalConfigRefEnStart, 0xB4},
+	}); err != nil {
+		return err
+	}
+	first := 0
+	if aperture {
+		// 12 is the first aperture SPAD.
+		first = 12
+	}
+	enabled := byte(0)
+	for i := 0; i < 48; i++ {
+		if i < first || enabled == count {
+			m[i/8] &^= 1 << uint(i%8)
+		} else if m[i/8]&(1<<uint(i%8)) != 0 {
+			enabled++
+		}
+	}
+	return d.c.Tx(append([]byte{regGlobalConfigSPADEnables}, m[:]...), nil)
+}
+
+// spadInfo reads the reference SPAD count and type from the NVM.
+func (d *Dev) spadInfo() (byte, bool, error) {
+	if err := d.writeRegs([][2]byte{{0x80, 0x01}, {0xFF, 0x01}, {0x00, 0x00}, {0xFF, 0x06}}); err != nil {
+		return 0, false, err
+	}
+	if err := d.updateReg(0x83, 0x04, 0x04); err != nil {
+		return 0, false, err
+	}
+	if err := d.writeRegs([][2]byte{{0xFF, 0x07}, {0x81, 0x01}, {0x80, 0x01}, {0x94, 0x6B}, {0x83, 0x00}}); err != nil {
+		return 0, false, err
+	}
+	if err := d.waitReg(0x83, 0xFF, true); err != nil {
+		return 0, false, err
+	}
+	if err := d.writeReg(0x83, 0x01); err != nil {
+		return 0, false, err
+	}
+	v, err := d.readReg(0x92)
+	if err != nil {
+		return 0, false, err
+	}
+	if err := d.writeRegs([][2]byte{{0x81, 0x00}, {0xFF, 0x06}}); err != nil {
+		return 0, false, err
+	}
+	if err := d.updateReg(0x83, 0x04, 0x00); err != nil {
+		return 0, false, err
+	}
+	if err := d.writeRegs([][2]byte{{0xFF, 0x01}, {0x00, 0x01}, {0xFF, 0x00}, {0x80, 0x00}}); err != nil {
+		return 0, false, err
+	}
+	return v & 0x7F, v&0x80 != 0, nil
+}
+
+// refCalibration runs a single reference calibration.
+func (d *Dev) refCalibration(vhvInit byte) error {
+	if err := d.writeReg(regSysRangeStart, 0x01|vhvInit); err != nil {
+		return err
+	}
+	if err := d.waitReg(regResultInterruptStatus, 0x07, true); err != nil {
+		return err
+	}
+	if err := d.writeReg(regSystemInterruptClear, 0x01); err != nil {
+		return err
+	}
+	return d.writeReg(regSysRangeStart, 0x00)
+}
+
+// setDistanceMode sets the signal rate limit and VCSEL pulse periods of the
+// mode.
+func (d *Dev) setDistanceMode(m DistanceMode) error {
+	var rate uint16
+	var pre, final byte
+	switch m {
+	case DistanceStandard:
+		// 0.25 MCPS in 9.7 fixed point.
+		rate, pre, final = 32, 14, 10
+	case DistanceLong:
+		// 0.1 MCPS in 9.7 fixed point.
+		rate, pre, final = 13, 18, 14
+	default:
+		return errors.New("vl53l0x: invalid distance mode")
+	}
+	if err := d.writeReg16(regFinalRangeMinCountRate, rate); err != nil {
+		return err
+	}
+	if err := d.setVCSELPeriods(pre, final); err != nil {
+		return err
+	}
+	d.mode = m
+	return nil
+}
+
+func (d *Dev) setTimingBudget(budget time.Duration) error {
+	if budget < minTimingBudget*time.Microsecond || budget > time.Second {
+		return errors.New("vl53l0x: timing budget must be between 20ms and 1s")
+	}
+	return d.setTimingBudgetUS(uint32(budget / time.Microsecond))
+}
+
+// writeStopVariable writes back the stop variable, as required before
+// starting a measurement.
+func (d *Dev) writeStopVariable() error {
+	return d.writeRegs([][2]byte{
+		{0x80, 0x01}, {0xFF, 0x01}, {0x00, 0x00},
+		{0x91, d.stopVariable},
+		{0x00, 0x01}, {0xFF, 0x00}, {0x80, 0x00},
+	})
+}
+
+// startContinuous starts timed continuous ranging.
+func (d *Dev) startContinuous(interval time.Duration) error {
+	if err := d.writeStopVariable(); err != nil {
+		return err
+	}
+	period := uint32(interval / time.Millisecond)
+	osc, err := d.readReg16(regOscCalibrateVal)
+	if err != nil {
+		return err
+	}
+	if osc != 0 {
+		period *= uint32(osc)
+	}
+	if err := d.writeReg32(regSystemIntermeasurement, period); err != nil {
+		return err
+	}
+	return d.writeReg(regSysRangeStart, 0x04)
+}
+
+func (d *Dev) stopContinuous() error {
+	return d.writeRegs([][2]byte{
+		{regSysRangeStart, 0x01},
+		{0xFF, 0x01}, {0x00, 0x00}, {0x91, 0x00}, {0x00, 0x01}, {0xFF, 0x00},
+	})
+}
+
+// stopRanging stops the ranging goroutine, if any, and reports whether it was
+// running. The lock must not be held, since the goroutine takes it after each
+// tick.
+func (d *Dev) stopRanging() bool {
+	d.mu.Lock()
+	running := d.stop != nil
+	if running {
+		close(d.stop)
+		d.stop = nil
+	}
+	d.mu.Unlock()
+	d.wg.Wait()
+	return running
+}
+
+func (d *Dev) rangingContinuous(interval time.Duration, ranging chan<- physic.Distance, stop <-chan struct{}) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+		}
+		d.mu.Lock()
+		r, err := d.readRange()
+		d.mu.Unlock()
+		if err == ErrOutOfRange {
+			continue
+		}
+		if err != nil {
+			log.Printf("%s: failed to range: %v", d, err)
+			return
+		}
+		select {
+		case ranging <- r:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// readRange waits for a measurement, reads it and clears the interrupt.
+func (d *Dev) readRange() (physic.Distance, error) {
+	if err := d.waitReg(regResultInterruptStatus, 0x07, true); err != nil {
+		return 0, err
+	}
+	var b [12]byte
+	if err := d.c.Tx([]byte{regResultRangeStatus}, b[:]); err != nil {
+		return 0, err
+	}
+	if err := d.writeReg(regSystemInterruptClear, 0x01); err != nil {
+		return 0, err
+	}
+	mm := binary.BigEndian.Uint16(b[10:])
+	if mm >= outOfRange {
+		return 0, ErrOutOfRange
+	}
+	return physic.Distance(mm) * physic.MilliMetre, nil
+}
+
+// waitReg polls reg until the bits in mask are set, or cleared.
+func (d *Dev) waitReg(reg, mask byte, set bool) error {
+	for start := time.Now(); ; sleep(pollInterval) {
+		v, err := d.readReg(reg)
+		if err != nil {
+			return err
+		}
+		if (v&mask != 0) == set {
+			return nil
+		}
+		if time.Since(start) > ioTimeout {
+			return fmt.Errorf("vl53l0x: timeout waiting for register %#x", reg)
+		}
+	}
+}
+
+func (d *Dev) readReg(reg byte) (byte, error) {
+	var b [1]byte
+	err := d.c.Tx([]byte{reg}, b[:])
+	return b[0], err
+}
+
+func (d *Dev) readReg16(reg byte) (uint16, error) {
+	var b [2]byte
+	err := d.c.Tx([]byte{reg}, b[:])
+	return binary.BigEndian.Uint16(b[:]), err
+}
+
+func (d *Dev) writeReg(reg, v byte) error {
+	return d.c.Tx([]byte{reg, v}, nil)
+}
+
+func (d *Dev) writeReg16(reg byte, v uint16) error {
+	return d.c.Tx([]byte{reg, byte(v >> 8), byte(v)}, nil)
+}
+
+func (d *Dev) writeReg32(reg byte, v uint32) error {
+	return d.c.Tx([]byte{reg, byte(v >> 24), byte(v >> 16), byte(v >> 8), byte(v)}, nil)
+}
+
+func (d *Dev) writeRegs(regs [][2]byte) error {
+	for _, r := range regs {
+		if err := d.writeReg(r[0], r[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateReg sets the bits in mask of reg to v.
+func (d *Dev) updateReg(reg, mask, v byte) error {
+	old, err := d.readReg(reg)
+	if err != nil {
+		return err
+	}
+	return d.writeReg(reg, old&^mask|v&mask)
+}
+
+var _ conn.Resource = &Dev{}
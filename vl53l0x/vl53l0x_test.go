@@ -0,0 +1,245 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package vl53l0x
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3/physic"
+)
+
+// fakeBus is a register file mimicking the device. Register pages are
+// ignored.
+type fakeBus struct {
+	mu   sync.Mutex
+	regs [256]byte
+	// fixed registers always read the same value.
+	fixed map[byte]byte
+}
+
+func newFakeBus() *fakeBus {
+	f := &fakeBus{
+		fixed: map[byte]byte{
+			regSysRangeStart:         0x00,
+			regResultInterruptStatus: 0x07,
+			0x83:                     0x10,
+			regModelID:               modelID,
+		},
+	}
+	// 5 aperture SPADs.
+	f.regs[0x92] = 0x85
+	f.regs[0x91] = 0x3C
+	for i := 0; i < 6; i++ {
+		f.regs[regGlobalConfigSPADEnables+i] = 0xFF
+	}
+	f.regs[regOscCalibrateVal+1] = 0x10
+	f.setRange(300)
+	return f
+}
+
+func (f *fakeBus) setRange(mm uint16) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.regs[regResultRangeStatus+10] = byte(mm >> 8)
+	f.regs[regResultRangeStatus+11] = byte(mm)
+}
+
+func (f *fakeBus) reg(r byte) byte {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.regs[r]
+}
+
+func (f *fakeBus) String() string {
+	return "fake"
+}
+
+func (f *fakeBus) Tx(addr uint16, w, r []byte) error {
+	if addr != I2CAddr {
+		return errors.New("wrong address")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	reg := int(w[0])
+	for i, v := range w[1:] {
+		f.regs[(reg+i)&0xFF] = v
+	}
+	for i := range r {
+		a := byte(reg + i)
+		if v, ok := f.fixed[a]; ok {
+			r[i] = v
+		} else {
+			r[i] = f.regs[a]
+		}
+	}
+	return nil
+}
+
+func (f *fakeBus) SetSpeed(physic.Frequency) error {
+	return nil
+}
+
+func TestNewI2C(t *testing.T) {
+	bus := newFakeBus()
+	bus.fixed[regModelID] = 0x12
+	if _, err := NewI2C(bus, I2CAddr, &DefaultOpts); err == nil {
+		t.Fatal("expected error on wrong model ID")
+	}
+	bus = newFakeBus()
+	dev, err := NewI2C(bus, I2CAddr, &Opts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := dev.String(); s != "VL53L0X{fake(41)}" {
+		t.Fatal(s)
+	}
+	if dev.stopVariable != 0x3C {
+		t.Fatalf("stop variable %#x", dev.stopVariable)
+	}
+	// The first 12 SPADs are not apertures, only 5 are kept.
+	want := []byte{0x00, 0xF0, 0x01, 0x00, 0x00, 0x00}
+	for i, w := range want {
+		if v := bus.reg(regGlobalConfigSPADEnables + byte(i)); v != w {
+			t.Fatalf("SPAD map byte %d: %#x, want %#x", i, v, w)
+		}
+	}
+	if v := bus.reg(regVHVConfigPadSCLSDA); v != 0x01 {
+		t.Fatalf("I/O not in 2.8V mode: %#x", v)
+	}
+	if b := dev.TimingBudget(); b != 33*time.Millisecond {
+		t.Fatal(b)
+	}
+	if m := dev.DistanceMode(); m != DistanceStandard {
+		t.Fatal(m)
+	}
+}
+
+func TestRange(t *testing.T) {
+	bus := newFakeBus()
+	dev, err := NewI2C(bus, I2CAddr, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := dev.Range()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d != 300*physic.MilliMetre {
+		t.Fatal(d)
+	}
+	bus.setRange(8190)
+	if _, err := dev.Range(); err != ErrOutOfRange {
+		t.Fatal(err)
+	}
+}
+
+func TestSetTimingBudget(t *testing.T) {
+	dev, err := NewI2C(newFakeBus(), I2CAddr, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.SetTimingBudget(19 * time.Millisecond); err == nil {
+		t.Fatal("expected error")
+	}
+	if err := dev.SetTimingBudget(200 * time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if b := dev.TimingBudget(); b != 200*time.Millisecond {
+		t.Fatal(b)
+	}
+}
+
+func TestSetDistanceMode(t *testing.T) {
+	bus := newFakeBus()
+	dev, err := NewI2C(bus, I2CAddr, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.SetDistanceMode(DistanceLong); err != nil {
+		t.Fatal(err)
+	}
+	if v := bus.reg(regPreRangeVCSELPeriod); v != 8 {
+		t.Fatalf("pre-range VCSEL period %#x", v)
+	}
+	if v := bus.reg(regFinalRangeVCSELPeriod); v != 6 {
+		t.Fatalf("final range VCSEL period %#x", v)
+	}
+	if v := bus.reg(regFinalRangeMinCountRate + 1); v != 13 {
+		t.Fatalf("signal rate limit %#x", v)
+	}
+	if b := dev.TimingBudget(); b != 33*time.Millisecond {
+		t.Fatal(b)
+	}
+	if err := dev.SetDistanceMode(DistanceMode(5)); err == nil {
+		t.Fatal("expected error")
+	}
+	if s := DistanceLong.String(); s != "Long" {
+		t.Fatal(s)
+	}
+}
+
+func TestRangeContinuous(t *testing.T) {
+	bus := newFakeBus()
+	dev, err := NewI2C(bus, I2CAddr, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dev.RangeContinuous(time.Millisecond); err == nil {
+		t.Fatal("expected error")
+	}
+	c, err := dev.RangeContinuous(40 * time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := bus.reg(regSysRangeStart); v != 0x04 {
+		t.Fatalf("not in timed mode: %#x", v)
+	}
+	// 40ms * 16.
+	if v := bus.reg(regSystemIntermeasurement + 2); v != 0x02 || bus.reg(regSystemIntermeasurement+3) != 0x80 {
+		t.Fatal("unexpected intermeasurement period")
+	}
+	if _, err := dev.Range(); err == nil {
+		t.Fatal("expected error while ranging continuously")
+	}
+	if d := <-c; d != 300*physic.MilliMetre {
+		t.Fatal(d)
+	}
+	if err := dev.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := <-c; ok {
+		t.Fatal("channel not closed")
+	}
+	if v := bus.reg(regSysRangeStart); v != 0x01 {
+		t.Fatalf("not stopped: %#x", v)
+	}
+}
+
+func TestTimeouts(t *testing.T) {
+	if v := encodeTimeout(509); v != 0x01FE {
+		t.Fatalf("%#x", v)
+	}
+	if v := decodeTimeout(0x01FE); v != 509 {
+		t.Fatal(v)
+	}
+	if v := decodeTimeout(encodeTimeout(1)); v != 1 {
+		t.Fatal(v)
+	}
+	if v := macroPeriod(14); v != 53384 {
+		t.Fatal(v)
+	}
+	if v := mclksToMicroseconds(151, 14); v != 8061 {
+		t.Fatal(v)
+	}
+	if v := microsecondsToMclks(8061, 14); v != 151 {
+		t.Fatal(v)
+	}
+	if v := decodeVCSELPeriod(encodeVCSELPeriod(18)); v != 18 {
+		t.Fatal(v)
+	}
+}
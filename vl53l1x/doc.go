@@ -0,0 +1,26 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package vl53l1x controls a ST VL53L1X time-of-flight ranging sensor over
+// I²C.
+//
+// The sensor measures the distance to a target up to about 4m. The
+// initialization, distance mode and timing budget configurations follow the
+// ones of the ST ultra lite driver (ULD).
+//
+// # Distance mode
+//
+// DistanceShort ranges up to about 1.3m and is less sensitive to ambient
+// light. DistanceLong ranges up to about 4m in the dark.
+//
+// # Timing budget
+//
+// The timing budget is the time allowed for one measurement, one of 15ms
+// (DistanceShort only), 20ms, 33ms, 50ms, 100ms, 200ms or 500ms. A longer
+// budget improves the accuracy and the maximum range.
+//
+// # Datasheet
+//
+// https://www.st.com/resource/en/datasheet/vl53l1x.pdf
+package vl53l1x
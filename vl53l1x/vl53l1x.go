@@ -0,0 +1,505 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package vl53l1x
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/physic"
+)
+
+// I2CAddr is the default I²C address of the device.
+const I2CAddr uint16 = 0x29
+
+// ErrOutOfRange is returned by Range when no valid target was detected.
+var ErrOutOfRange = errors.New("vl53l1x: no target in range")
+
+// DistanceMode trades the maximum range against the immunity to ambient
+// light.
+type DistanceMode int
+
+const (
+	// DistanceShort ranges up to about 1.3m.
+	DistanceShort DistanceMode = 1
+	// DistanceLong ranges up to about 4m in the dark.
+	DistanceLong DistanceMode = 2
+)
+
+func (m DistanceMode) String() string {
+	switch m {
+	case DistanceShort:
+		return "Short"
+	case DistanceLong:
+		return "Long"
+	default:
+		return "DistanceMode(?)"
+	}
+}
+
+// Opts holds the configuration options.
+type Opts struct {
+	// DistanceMode selects the range profile. 0 means DistanceLong.
+	DistanceMode DistanceMode
+	// TimingBudget is the time allowed for one measurement. 0 means 100ms.
+	TimingBudget time.Duration
+}
+
+// DefaultOpts is the recommended default options.
+var DefaultOpts = Opts{
+	DistanceMode: DistanceLong,
+	TimingBudget: 100 * time.Millisecond,
+}
+
+// Dev is a handle to a VL53L1X.
+type Dev struct {
+	mu     sync.Mutex
+	c      i2c.Dev
+	mode   DistanceMode
+	budget time.Duration
+	stop   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewI2C opens a handle to the device at addr, waits for it to boot, loads
+// the default configuration and applies opts.
+//
+// To use on the default address, vl53l1x.I2CAddr must be passed as argument.
+func NewI2C(bus i2c.Bus, addr uint16, opts *Opts) (*Dev, error) {
+	mode := opts.DistanceMode
+	if mode == 0 {
+		mode = DefaultOpts.DistanceMode
+	}
+	budget := opts.TimingBudget
+	if budget == 0 {
+		budget = DefaultOpts.TimingBudget
+	}
+	d := &Dev{c: i2c.Dev{Bus: bus, Addr: addr}}
+	if err := d.waitBoot(); err != nil {
+		return nil, err
+	}
+	id, err := d.readReg16(regModelID)
+	if err != nil {
+		return nil, err
+	}
+	if id != modelID {
+		return nil, fmt.Errorf("vl53l1x: unexpected model ID %#x", id)
+	}
+	if err := d.init(); err != nil {
+		return nil, err
+	}
+	if err := d.setDistanceMode(mode); err != nil {
+		return nil, err
+	}
+	if err := d.setTimingBudget(budget); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *Dev) String() string {
+	return fmt.Sprintf("VL53L1X{%s}", &d.c)
+}
+
+// Range runs a single measurement and returns the distance to the target.
+//
+// It returns ErrOutOfRange when no valid target was detected.
+func (d *Dev) Range() (physic.Distance, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.stop != nil {
+		return 0, errors.New("vl53l1x: continuous ranging in progress")
+	}
+	if err := d.writeReg(regSystemInterruptClear, 0x01); err != nil {
+		return 0, err
+	}
+	if err := d.writeReg(regSystemModeStart, modeStartSingle); err != nil {
+		return 0, err
+	}
+	return d.readRange()
+}
+
+// RangeContinuous starts continuous ranging and returns the distances every
+// interval, which must be at least the timing budget.
+//
+// Measurements without a valid target are skipped. The application must call
+// Halt to stop the ranging and close the channel.
+//
+// It's the responsibility of the caller to retrieve the values from the
+// channel as fast as possible, otherwise the interval may not be respected.
+func (d *Dev) RangeContinuous(interval time.Duration) (<-chan physic.Distance, error) {
+	d.mu.Lock()
+	budget := d.budget
+	d.mu.Unlock()
+	if interval < budget {
+		return nil, errors.New("vl53l1x: interval shorter than the timing budget")
+	}
+	d.stopRanging()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.setIntermeasurement(interval); err != nil {
+		return nil, err
+	}
+	if err := d.writeReg(regSystemInterruptClear, 0x01); err != nil {
+		return nil, err
+	}
+	if err := d.writeReg(regSystemModeStart, modeStartTimed); err != nil {
+		return nil, err
+	}
+	ranging := make(chan physic.Distance)
+	stop := make(chan struct{})
+	d.stop = stop
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		defer close(ranging)
+		d.rangingContinuous(interval, ranging, stop)
+	}()
+	return ranging, nil
+}
+
+// SetDistanceMode changes the range profile. The timing budget is kept, it
+// must be supported by the new mode.
+func (d *Dev) SetDistanceMode(m DistanceMode) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := timingBudgets[m][d.budget]; !ok {
+		return fmt.Errorf("vl53l1x: timing budget %s not supported in %s mode", d.budget, m)
+	}
+	if err := d.setDistanceMode(m); err != nil {
+		return err
+	}
+	return d.setTimingBudget(d.budget)
+}
+
+// DistanceMode returns the current range profile.
+func (d *Dev) DistanceMode() DistanceMode {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.mode
+}
+
+// SetTimingBudget sets the time allowed for one measurement. It must be one
+// of 15ms (DistanceShort only), 20ms, 33ms, 50ms, 100ms, 200ms or 500ms.
+func (d *Dev) SetTimingBudget(budget time.Duration) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.setTimingBudget(budget)
+}
+
+// TimingBudget returns the time allowed for one measurement.
+func (d *Dev) TimingBudget() time.Duration {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.budget
+}
+
+// Halt stops continuous ranging, if any.
+//
+// Halt implements conn.Resource.
+func (d *Dev) Halt() error {
+	if !d.stopRanging() {
+		return nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.writeReg(regSystemModeStart, modeStop)
+}
+
+//
+
+// Registers.
+const (
+	regVHVConfigTimeoutLoopBound = 0x0008
+	regVHVConfigInit             = 0x000B
+	regDefaultConfig             = 0x002D
+	regGPIOHVMuxCtrl             = 0x0030
+	regGPIOTIOHVStatus           = 0x0031
+	regPhasecalConfigTimeout     = 0x004B
+	regRangeConfigTimeoutA       = 0x005E
+	regRangeConfigVCSELPeriodA   = 0x0060
+	regRangeConfigTimeoutB       = 0x0061
+	regRangeConfigVCSELPeriodB   = 0x0063
+	regRangeConfigValidPhaseHigh = 0x0069
+	regIntermeasurementPeriod    = 0x006C
+	regSDConfigWOISD0            = 0x0078
+	regSDConfigInitialPhaseSD0   = 0x007A
+	regSystemInterruptClear      = 0x0086
+	regSystemModeStart           = 0x0087
+	regResultRangeStatus         = 0x0089
+	regResultOscCalibrateVal     = 0x00DE
+	regFirmwareSystemStatus      = 0x00E5
+	regModelID                   = 0x010F
+
+	modelID = 0xEACC
+
+	modeStop        = 0x00
+	modeStartSingle = 0x10
+	modeStartTimed  = 0x40
+)
+
+const (
+	// ioTimeout is the longest time the device may take to answer, longer
+	// than the longest timing budget.
+	ioTimeout    = time.Second
+	pollInterval = time.Millisecond
+)
+
+var sleep = time.Sleep
+
+// defaultConfig is the configuration of the ST ULD, written from register
+// 0x2D to 0x87.
+var defaultConfig = [...]byte{
+	0x00, 0x00, 0x00, 0x01, 0x02, 0x00, 0x02, 0x08, // 0x2D
+	0x00, 0x08, 0x10, 0x01, 0x01, 0x00, 0x00, 0x00, // 0x35
+	0x00, 0xFF, 0x00, 0x0F, 0x00, 0x00, 0x00, 0x00, // 0x3D
+	0x00, 0x20, 0x0B, 0x00, 0x00, 0x02, 0x0A, 0x21, // 0x45
+	0x00, 0x00, 0x05, 0x00, 0x00, 0x00, 0x00, 0xC8, // 0x4D
+	0x00, 0x00, 0x38, 0xFF, 0x01, 0x00, 0x08, 0x00, // 0x55
+	0x00, 0x01, 0xCC, 0x0F, 0x01, 0xF1, 0x0D, 0x01, // 0x5D
+	0x68, 0x00, 0x80, 0x08, 0xB8, 0x00, 0x00, 0x00, // 0x65
+	0x00, 0x0F, 0x89, 0x00, 0x00, 0x00, 0x00, 0x00, // 0x6D
+	0x00, 0x00, 0x01, 0x0F, 0x0D, 0x0E, 0x0E, 0x00, // 0x75
+	0x00, 0x02, 0xC7, 0xFF, 0x9B, 0x00, 0x00, 0x00, // 0x7D
+	0x01, 0x00, 0x00, // 0x85
+}
+
+// distanceModes holds the PHASECAL_CONFIG__TIMEOUT_MACROP,
+// RANGE_CONFIG__VCSEL_PERIOD_A, RANGE_CONFIG__VCSEL_PERIOD_B,
+// RANGE_CONFIG__VALID_PHASE_HIGH, SD_CONFIG__WOI_SD0 and
+// SD_CONFIG__INITIAL_PHASE_SD0 values of each mode.
+var distanceModes = map[DistanceMode][8]byte{
+	DistanceShort: {0x14, 0x07, 0x05, 0x38, 0x07, 0x05, 0x06, 0x06},
+	DistanceLong:  {0x0A, 0x0F, 0x0D, 0xB8, 0x0F, 0x0D, 0x0E, 0x0E},
+}
+
+// timingBudgets holds the RANGE_CONFIG__TIMEOUT_MACROP_A and _B values of
+// each mode and timing budget.
+var timingBudgets = map[DistanceMode]map[time.Duration][2]uint16{
+	DistanceShort: {
+		15 * time.Millisecond:  {0x001D, 0x0027},
+		20 * time.Millisecond:  {0x0051, 0x006E},
+		33 * time.Millisecond:  {0x00D6, 0x006E},
+		50 * time.Millisecond:  {0x01AE, 0x01E8},
+		100 * time.Millisecond: {0x02E1, 0x0388},
+		200 * time.Millisecond: {0x03E1, 0x0496},
+		500 * time.Millisecond: {0x0591, 0x05C1},
+	},
+	DistanceLong: {
+		20 * time.Millisecond:  {0x001E, 0x0022},
+		33 * time.Millisecond:  {0x0060, 0x006E},
+		50 * time.Millisecond:  {0x00AD, 0x00C6},
+		100 * time.Millisecond: {0x01CC, 0x01EA},
+		200 * time.Millisecond: {0x02D9, 0x02F8},
+		500 * time.Millisecond: {0x048F, 0x04A4},
+	},
+}
+
+// rangeStatuses maps RESULT__RANGE_STATUS to the ULD range status, where 0
+// is a valid measurement.
+var rangeStatuses = [24]byte{
+	255, 255, 255, 5, 2, 4, 1, 7, 3, 0, 255, 255,
+	9, 13, 255, 255, 255, 255, 10, 6, 255, 255, 11, 12,
+}
+
+// waitBoot waits for the firmware to boot.
+func (d *Dev) waitBoot() error {
+	for start := time.Now(); ; sleep(pollInterval) {
+		var b [1]byte
+		if err := d.readReg(regFirmwareSystemStatus, b[:]); err != nil {
+			return err
+		}
+		if b[0]&0x01 != 0 {
+			return nil
+		}
+		if time.Since(start) > ioTimeout {
+			return errors.New("vl53l1x: timeout waiting for boot")
+		}
+	}
+}
+
+// init loads the default configuration and runs a first measurement to
+// calibrate the VHV.
+func (d *Dev) init() error {
+	if err := d.writeReg(regDefaultConfig, defaultConfig[:]...); err != nil {
+		return err
+	}
+	if err := d.writeReg(regSystemModeStart, modeStartTimed); err != nil {
+		return err
+	}
+	if err := d.waitDataReady(); err != nil {
+		return err
+	}
+	if err := d.writeReg(regSystemInterruptClear, 0x01); err != nil {
+		return err
+	}
+	if err := d.writeReg(regSystemModeStart, modeStop); err != nil {
+		return err
+	}
+	// Two bounds VHV, starting from the previous temperature.
+	if err := d.writeReg(regVHVConfigTimeoutLoopBound, 0x09); err != nil {
+		return err
+	}
+	return d.writeReg(regVHVConfigInit, 0x00)
+}
+
+func (d *Dev) setDistanceMode(m DistanceMode) error {
+	v, ok := distanceModes[m]
+	if !ok {
+		return errors.New("vl53l1x: invalid distance mode")
+	}
+	for _, w := range []struct {
+		reg uint16
+		v   []byte
+	}{
+		{regPhasecalConfigTimeout, v[0:1]},
+		{regRangeConfigVCSELPeriodA, v[1:2]},
+		{regRangeConfigVCSELPeriodB, v[2:3]},
+		{regRangeConfigValidPhaseHigh, v[3:4]},
+		{regSDConfigWOISD0, v[4:6]},
+		{regSDConfigInitialPhaseSD0, v[6:8]},
+	} {
+		if err := d.writeReg(w.reg, w.v...); err != nil {
+			return err
+		}
+	}
+	d.mode = m
+	return nil
+}
+
+func (d *Dev) setTimingBudget(budget time.Duration) error {
+	v, ok := timingBudgets[d.mode][budget]
+	if !ok {
+		return fmt.Errorf("vl53l1x: timing budget %s not supported in %s mode", budget, d.mode)
+	}
+	if err := d.writeReg(regRangeConfigTimeoutA, byte(v[0]>>8), byte(v[0])); err != nil {
+		return err
+	}
+	if err := d.writeReg(regRangeConfigTimeoutB, byte(v[1]>>8), byte(v[1])); err != nil {
+		return err
+	}
+	d.budget = budget
+	return nil
+}
+
+// setIntermeasurement sets the period of timed ranging.
+func (d *Dev) setIntermeasurement(interval time.Duration) error {
+	var b [2]byte
+	if err := d.readReg(regResultOscCalibrateVal, b[:]); err != nil {
+		return err
+	}
+	pll := uint64(binary.BigEndian.Uint16(b[:]) & 0x3FF)
+	// The oscillator runs 7.5% slower than its calibration.
+	p := uint32(uint64(interval/time.Millisecond) * pll * 1075 / 1000)
+	return d.writeReg(regIntermeasurementPeriod, byte(p>>24), byte(p>>16), byte(p>>8), byte(p))
+}
+
+// stopRanging stops the ranging goroutine, if any, and reports whether it was
+// running. The lock must not be held, since the goroutine takes it after each
+// tick.
+func (d *Dev) stopRanging() bool {
+	d.mu.Lock()
+	running := d.stop != nil
+	if running {
+		close(d.stop)
+		d.stop = nil
+	}
+	d.mu.Unlock()
+	d.wg.Wait()
+	return running
+}
+
+func (d *Dev) rangingContinuous(interval time.Duration, ranging chan<- physic.Distance, stop <-chan struct{}) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+		}
+		d.mu.Lock()
+		r, err := d.readRange()
+		d.mu.Unlock()
+		if err == ErrOutOfRange {
+			continue
+		}
+		if err != nil {
+			log.Printf("%s: failed to range: %v", d, err)
+			return
+		}
+		select {
+		case ranging <- r:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// readRange waits for a measurement, reads it and clears the interrupt.
+func (d *Dev) readRange() (physic.Distance, error) {
+	if err := d.waitDataReady(); err != nil {
+		return 0, err
+	}
+	// From RESULT__RANGE_STATUS to
+	// RESULT__FINAL_CROSSTALK_CORRECTED_RANGE_MM_SD0.
+	var b [15]byte
+	if err := d.readReg(regResultRangeStatus, b[:]); err != nil {
+		return 0, err
+	}
+	if err := d.writeReg(regSystemInterruptClear, 0x01); err != nil {
+		return 0, err
+	}
+	if s := b[0] & 0x1F; int(s) >= len(rangeStatuses) || rangeStatuses[s] != 0 {
+		return 0, ErrOutOfRange
+	}
+	return physic.Distance(binary.BigEndian.Uint16(b[13:])) * physic.MilliMetre, nil
+}
+
+// waitDataReady polls the interrupt status until a measurement is available.
+func (d *Dev) waitDataReady() error {
+	var b [2]byte
+	if err := d.readReg(regGPIOHVMuxCtrl, b[:1]); err != nil {
+		return err
+	}
+	// Bit 4 set means the interrupt is active low.
+	ready := byte(1)
+	if b[0]&0x10 != 0 {
+		ready = 0
+	}
+	for start := time.Now(); ; sleep(pollInterval) {
+		if err := d.readReg(regGPIOTIOHVStatus, b[1:]); err != nil {
+			return err
+		}
+		if b[1]&0x01 == ready {
+			return nil
+		}
+		if time.Since(start) > ioTimeout {
+			return errors.New("vl53l1x: timeout waiting for measurement")
+		}
+	}
+}
+
+func (d *Dev) readReg(reg uint16, b []byte) error {
+	return d.c.Tx([]byte{byte(reg >> 8), byte(reg)}, b)
+}
+
+func (d *Dev) readReg16(reg uint16) (uint16, error) {
+	var b [2]byte
+	err := d.readReg(reg, b[:])
+	return binary.BigEndian.Uint16(b[:]), err
+}
+
+func (d *Dev) writeReg(reg uint16, v ...byte) error {
+	return d.c.Tx(append([]byte{byte(reg >> 8), byte(reg)}, v...), nil)
+}
+
+var _ conn.Resource = &Dev{}
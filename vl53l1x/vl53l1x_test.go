@@ -0,0 +1,200 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package vl53l1x
+
+import (
+	"encoding/binary"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3/physic"
+)
+
+// fakeBus is a register file mimicking the device.
+type fakeBus struct {
+	mu   sync.Mutex
+	regs [0x200]byte
+	// fixed registers always read the same value.
+	fixed map[uint16]byte
+}
+
+func newFakeBus() *fakeBus {
+	f := &fakeBus{
+		fixed: map[uint16]byte{
+			regFirmwareSystemStatus: 0x01,
+			regGPIOTIOHVStatus:      0x01,
+		},
+	}
+	binary.BigEndian.PutUint16(f.regs[regModelID:], modelID)
+	binary.BigEndian.PutUint16(f.regs[regResultOscCalibrateVal:], 100)
+	f.setRange(9, 300)
+	return f
+}
+
+func (f *fakeBus) setRange(status byte, mm uint16) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.regs[regResultRangeStatus] = status
+	binary.BigEndian.PutUint16(f.regs[regResultRangeStatus+13:], mm)
+}
+
+func (f *fakeBus) reg(r uint16, n int) []byte {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]byte{}, f.regs[r:int(r)+n]...)
+}
+
+func (f *fakeBus) String() string {
+	return "fake"
+}
+
+func (f *fakeBus) Tx(addr uint16, w, r []byte) error {
+	if addr != I2CAddr || len(w) < 2 {
+		return errors.New("unexpected transaction")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	reg := binary.BigEndian.Uint16(w)
+	copy(f.regs[reg:], w[2:])
+	for i := range r {
+		a := reg + uint16(i)
+		if v, ok := f.fixed[a]; ok {
+			r[i] = v
+		} else {
+			r[i] = f.regs[a]
+		}
+	}
+	return nil
+}
+
+func (f *fakeBus) SetSpeed(physic.Frequency) error {
+	return nil
+}
+
+func TestNewI2C(t *testing.T) {
+	bus := newFakeBus()
+	bus.regs[regModelID] = 0x12
+	if _, err := NewI2C(bus, I2CAddr, &DefaultOpts); err == nil {
+		t.Fatal("expected error on wrong model ID")
+	}
+	bus = newFakeBus()
+	dev, err := NewI2C(bus, I2CAddr, &Opts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := dev.String(); s != "VL53L1X{fake(41)}" {
+		t.Fatal(s)
+	}
+	if m := dev.DistanceMode(); m != DistanceLong {
+		t.Fatal(m)
+	}
+	if b := dev.TimingBudget(); b != 100*time.Millisecond {
+		t.Fatal(b)
+	}
+	if a, b := bus.reg(regRangeConfigTimeoutA, 2), bus.reg(regRangeConfigTimeoutB, 2); a[0] != 0x01 || a[1] != 0xCC || b[0] != 0x01 || b[1] != 0xEA {
+		t.Fatalf("timeouts %#v %#v", a, b)
+	}
+	if v := bus.reg(regVHVConfigTimeoutLoopBound, 1); v[0] != 0x09 {
+		t.Fatalf("VHV loop bound %#x", v[0])
+	}
+	if v := bus.reg(regSystemModeStart, 1); v[0] != modeStop {
+		t.Fatalf("ranging not stopped %#x", v[0])
+	}
+}
+
+func TestRange(t *testing.T) {
+	bus := newFakeBus()
+	dev, err := NewI2C(bus, I2CAddr, &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := dev.Range()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d != 300*physic.MilliMetre {
+		t.Fatal(d)
+	}
+	// Signal fail.
+	bus.setRange(4, 300)
+	if _, err := dev.Range(); err != ErrOutOfRange {
+		t.Fatal(err)
+	}
+}
+
+func TestDistanceModeTimingBudget(t *testing.T) {
+	bus := newFakeBus()
+	dev, err := NewI2C(bus, I2CAddr, &Opts{DistanceMode: DistanceShort, TimingBudget: 15 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := bus.reg(regRangeConfigVCSELPeriodA, 1); v[0] != 0x07 {
+		t.Fatalf("VCSEL period %#x", v[0])
+	}
+	if v := bus.reg(regSDConfigWOISD0, 4); v[0] != 0x07 || v[1] != 0x05 || v[2] != 0x06 || v[3] != 0x06 {
+		t.Fatalf("SD config %#v", v)
+	}
+	// 15ms is not supported in long mode.
+	if err := dev.SetDistanceMode(DistanceLong); err == nil {
+		t.Fatal("expected error")
+	}
+	if err := dev.SetTimingBudget(21 * time.Millisecond); err == nil {
+		t.Fatal("expected error")
+	}
+	if err := dev.SetTimingBudget(50 * time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.SetDistanceMode(DistanceLong); err != nil {
+		t.Fatal(err)
+	}
+	if a, b := bus.reg(regRangeConfigTimeoutA, 2), bus.reg(regRangeConfigTimeoutB, 2); a[0] != 0x00 || a[1] != 0xAD || b[0] != 0x00 || b[1] != 0xC6 {
+		t.Fatalf("timeouts %#v %#v", a, b)
+	}
+	if v := bus.reg(regRangeConfigVCSELPeriodA, 1); v[0] != 0x0F {
+		t.Fatalf("VCSEL period %#x", v[0])
+	}
+	if s := DistanceShort.String(); s != "Short" {
+		t.Fatal(s)
+	}
+}
+
+func TestRangeContinuous(t *testing.T) {
+	bus := newFakeBus()
+	dev, err := NewI2C(bus, I2CAddr, &Opts{TimingBudget: 20 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dev.RangeContinuous(10 * time.Millisecond); err == nil {
+		t.Fatal("expected error")
+	}
+	c, err := dev.RangeContinuous(40 * time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 40ms * 100 * 1.075.
+	if v := binary.BigEndian.Uint32(bus.reg(regIntermeasurementPeriod, 4)); v != 4300 {
+		t.Fatal(v)
+	}
+	if v := bus.reg(regSystemModeStart, 1); v[0] != modeStartTimed {
+		t.Fatalf("not in timed mode %#x", v[0])
+	}
+	if _, err := dev.Range(); err == nil {
+		t.Fatal("expected error while ranging continuously")
+	}
+	if d := <-c; d != 300*physic.MilliMetre {
+		t.Fatal(d)
+	}
+	if err := dev.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := <-c; ok {
+		t.Fatal("channel not closed")
+	}
+	if v := bus.reg(regSystemModeStart, 1); v[0] != modeStop {
+		t.Fatalf("not stopped %#x", v[0])
+	}
+}
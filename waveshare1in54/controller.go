@@ -0,0 +1,83 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package waveshare1in54
+
+import "image"
+
+type controller interface {
+	sendCommand(byte)
+	sendData([]byte)
+	waitUntilIdle()
+}
+
+func initDisplay(ctrl controller, opts *Opts) {
+	ctrl.waitUntilIdle()
+	ctrl.sendCommand(swReset)
+	ctrl.waitUntilIdle()
+
+	ctrl.sendCommand(driverOutputControl)
+	ctrl.sendData([]byte{
+		byte((opts.Height - 1) % 0x100),
+		byte((opts.Height - 1) / 0x100),
+		0x00,
+	})
+
+	setMemoryArea(ctrl, image.Rect(0, 0, (opts.Width+7)/8, opts.Height))
+
+	ctrl.sendCommand(displayUpdateControl1)
+	ctrl.sendData([]byte{0x00, 0x80})
+
+	ctrl.sendCommand(temperatureSensorControl)
+	ctrl.sendData([]byte{temperatureSensorInternal})
+	ctrl.waitUntilIdle()
+}
+
+func configDisplayMode(ctrl controller, mode PartialUpdate) {
+	var borderWaveformControlValue byte
+
+	switch mode {
+	case Full:
+		borderWaveformControlValue = 0x05
+	case Partial:
+		borderWaveformControlValue = 0x80
+	}
+
+	ctrl.sendCommand(borderWaveformControl)
+	ctrl.sendData([]byte{borderWaveformControlValue})
+}
+
+func updateDisplay(ctrl controller, mode PartialUpdate) {
+	flags := displayUpdateEnableClock |
+		displayUpdateEnableAnalog |
+		displayUpdateLoadTemperature |
+		displayUpdateLoadLUTFromOTP |
+		displayUpdateDisplay |
+		displayUpdateDisableAnalog |
+		displayUpdateDisableClock
+
+	if mode == Partial {
+		flags |= displayUpdateMode2
+	}
+
+	ctrl.sendCommand(displayUpdateControl2)
+	ctrl.sendData([]byte{flags})
+
+	ctrl.sendCommand(masterActivation)
+	ctrl.waitUntilIdle()
+}
+
+// refreshDisplay refreshes the display in the given mode, or with a full
+// refresh if full is set.
+func refreshDisplay(ctrl controller, mode PartialUpdate, full bool) {
+	if !full || mode == Full {
+		updateDisplay(ctrl, mode)
+		return
+	}
+	// The border waveform is switched to the full one for the duration of the
+	// refresh.
+	configDisplayMode(ctrl, Full)
+	updateDisplay(ctrl, Full)
+	configDisplayMode(ctrl, Partial)
+}
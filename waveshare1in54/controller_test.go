@@ -0,0 +1,162 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package waveshare1in54
+
+import (
+	"testing"
+
+	"periph.io/x/devices/v3/devicetest"
+)
+
+type record = devicetest.Record
+
+type fakeController struct {
+	devicetest.Recorder
+}
+
+func (r *fakeController) sendCommand(cmd byte) {
+	r.Command(cmd)
+}
+
+func (r *fakeController) sendData(data []byte) {
+	r.Data(data)
+}
+
+func (*fakeController) waitUntilIdle() {
+}
+
+func TestInitDisplay(t *testing.T) {
+	var got fakeController
+
+	initDisplay(&got, &EPD1in54)
+
+	want := []record{
+		{Cmd: swReset},
+		{Cmd: driverOutputControl, Data: []byte{200 - 1, 0, 0}},
+		{Cmd: dataEntryModeSetting, Data: []byte{0x03}},
+		{Cmd: setRAMXAddressStartEndPosition, Data: []byte{0, 24}},
+		{Cmd: setRAMYAddressStartEndPosition, Data: []byte{0, 0, 199, 0}},
+		{Cmd: setRAMXAddressCounter, Data: []byte{0}},
+		{Cmd: setRAMYAddressCounter, Data: []byte{0, 0}},
+		{Cmd: displayUpdateControl1, Data: []byte{0x00, 0x80}},
+		{Cmd: temperatureSensorControl, Data: []byte{0x80}},
+	}
+
+	if diff := got.Diff(want); diff != "" {
+		t.Errorf("initDisplay() difference (-got +want):\n%s", diff)
+	}
+}
+
+func TestConfigDisplayMode(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		mode PartialUpdate
+		want []record
+	}{
+		{
+			name: "full",
+			mode: Full,
+			want: []record{{Cmd: borderWaveformControl, Data: []byte{0x05}}},
+		},
+		{
+			name: "partial",
+			mode: Partial,
+			want: []record{{Cmd: borderWaveformControl, Data: []byte{0x80}}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var got fakeController
+
+			configDisplayMode(&got, tc.mode)
+
+			if diff := got.Diff(tc.want); diff != "" {
+				t.Errorf("configDisplayMode() difference (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdateDisplay(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		mode PartialUpdate
+		want []record
+	}{
+		{
+			name: "full",
+			mode: Full,
+			want: []record{
+				{Cmd: displayUpdateControl2, Data: []byte{0xf7}},
+				{Cmd: masterActivation},
+			},
+		},
+		{
+			name: "partial",
+			mode: Partial,
+			want: []record{
+				{Cmd: displayUpdateControl2, Data: []byte{0xff}},
+				{Cmd: masterActivation},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var got fakeController
+
+			updateDisplay(&got, tc.mode)
+
+			if diff := got.Diff(tc.want); diff != "" {
+				t.Errorf("updateDisplay() difference (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRefreshDisplay(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		mode PartialUpdate
+		full bool
+		want []record
+	}{
+		{
+			name: "full",
+			mode: Full,
+			full: true,
+			want: []record{
+				{Cmd: displayUpdateControl2, Data: []byte{0xf7}},
+				{Cmd: masterActivation},
+			},
+		},
+		{
+			name: "partial",
+			mode: Partial,
+			want: []record{
+				{Cmd: displayUpdateControl2, Data: []byte{0xff}},
+				{Cmd: masterActivation},
+			},
+		},
+		{
+			name: "full in partial mode",
+			mode: Partial,
+			full: true,
+			want: []record{
+				{Cmd: borderWaveformControl, Data: []byte{0x05}},
+				{Cmd: displayUpdateControl2, Data: []byte{0xf7}},
+				{Cmd: masterActivation},
+				{Cmd: borderWaveformControl, Data: []byte{0x80}},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var got fakeController
+
+			refreshDisplay(&got, tc.mode, tc.full)
+
+			if diff := got.Diff(tc.want); diff != "" {
+				t.Errorf("refreshDisplay() difference (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
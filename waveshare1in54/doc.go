@@ -0,0 +1,18 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package waveshare1in54 controls the Waveshare 1.54 v2 e-paper display.
+//
+// Datasheet:
+// https://www.waveshare.com/w/upload/e/e9/SSD1681_Datasheet.pdf
+//
+// Product page:
+// https://www.waveshare.com/wiki/1.54inch_e-Paper_Module_Manual
+//
+// The Waveshare 1.54in v2 display is a 200x200 panel driven by a SSD1681
+// controller. Like the SSD1680 of the 2.13in v3 and v4 displays, the
+// waveforms are loaded from the controller OTP so no LUT has to be sent. The
+// original 1.54in display uses another controller and isn't supported; the
+// revision is printed on the back of the module.
+package waveshare1in54
@@ -0,0 +1,60 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package waveshare1in54_test
+
+import (
+	"image"
+	"image/draw"
+	"log"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+
+	"periph.io/x/conn/v3/spi/spireg"
+	"periph.io/x/devices/v3/ssd1306/image1bit"
+	"periph.io/x/devices/v3/waveshare1in54"
+	"periph.io/x/host/v3"
+)
+
+func Example() {
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Use spireg SPI bus registry to find the first available SPI bus.
+	b, err := spireg.Open("")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer b.Close()
+
+	dev, err := waveshare1in54.NewHat(b, &waveshare1in54.EPD1in54) // Display config and size
+	if err != nil {
+		log.Fatalf("Failed to initialize driver: %v", err)
+	}
+
+	err = dev.Init()
+	if err != nil {
+		log.Fatalf("Failed to initialize display: %v", err)
+	}
+
+	// Draw on it. Black text on a white background.
+	img := image1bit.NewVerticalLSB(dev.Bounds())
+	draw.Draw(img, img.Bounds(), &image.Uniform{image1bit.On}, image.Point{}, draw.Src)
+	f := basicfont.Face7x13
+	drawer := font.Drawer{
+		Dst:  img,
+		Src:  &image.Uniform{image1bit.Off},
+		Face: f,
+		Dot:  fixed.P(0, img.Bounds().Dy()-1-f.Descent),
+	}
+	drawer.DrawString("Hello from periph!")
+
+	if err := dev.Draw(dev.Bounds(), img, image.Point{}); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,340 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package waveshare1in54
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/display"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/spi"
+	"periph.io/x/devices/v3/ssd1306/image1bit"
+	"periph.io/x/host/v3/rpi"
+)
+
+// Commands
+const (
+	driverOutputControl            byte = 0x01
+	deepSleepMode                  byte = 0x10
+	dataEntryModeSetting           byte = 0x11
+	swReset                        byte = 0x12
+	temperatureSensorControl       byte = 0x18
+	masterActivation               byte = 0x20
+	displayUpdateControl1          byte = 0x21
+	displayUpdateControl2          byte = 0x22
+	writeRAMBW                     byte = 0x24
+	writeRAMRed                    byte = 0x26
+	borderWaveformControl          byte = 0x3C
+	setRAMXAddressStartEndPosition byte = 0x44
+	setRAMYAddressStartEndPosition byte = 0x45
+	setRAMXAddressCounter          byte = 0x4E
+	setRAMYAddressCounter          byte = 0x4F
+)
+
+// Register values
+const (
+	temperatureSensorInternal = 0x80
+)
+
+// Flags for the displayUpdateControl2 command
+const (
+	displayUpdateDisableClock byte = 1 << iota
+	displayUpdateDisableAnalog
+	displayUpdateDisplay
+	displayUpdateMode2
+	displayUpdateLoadLUTFromOTP
+	displayUpdateLoadTemperature
+	displayUpdateEnableClock
+	displayUpdateEnableAnalog
+)
+
+// Dev defines the handler which is used to access the display.
+type Dev struct {
+	c conn.Conn
+
+	dc   gpio.PinOut
+	cs   gpio.PinOut
+	rst  gpio.PinOut
+	busy gpio.PinIn
+
+	bounds image.Rectangle
+	buffer *image1bit.VerticalLSB
+	mode   PartialUpdate
+
+	opts *Opts
+
+	// partialDraws is the number of partial refreshes since the last full
+	// refresh done at lastFull.
+	partialDraws int
+	lastFull     time.Time
+}
+
+// Corner describes a corner on the physical device and is used to define the
+// origin for drawing operations.
+type Corner uint8
+
+const (
+	TopLeft Corner = iota
+	TopRight
+	BottomRight
+	BottomLeft
+)
+
+// Opts definies the structure of the display configuration.
+type Opts struct {
+	Width  int
+	Height int
+	Origin Corner
+	// FullRefreshEvery, if not zero, makes Draw do a full refresh instead of
+	// a partial one after this many partial refreshes, to clear the ghosting
+	// they accumulate.
+	FullRefreshEvery int
+	// FullRefreshInterval, if not zero, makes Draw do a full refresh instead
+	// of a partial one when the last full refresh is older than this.
+	FullRefreshInterval time.Duration
+}
+
+// PartialUpdate defines if the display should do a full update or just a partial update.
+type PartialUpdate bool
+
+const (
+	// Full should update the complete display.
+	Full PartialUpdate = false
+	// Partial should update only partial parts of the display.
+	Partial PartialUpdate = true
+)
+
+// EPD1in54 contains display configuration for the Waveshare 1in54 v2.
+var EPD1in54 = Opts{
+	Width:  200,
+	Height: 200,
+}
+
+// flipPt returns a new image.Point with the X and Y coordinates exchanged.
+func flipPt(pt image.Point) image.Point {
+	return image.Point{X: pt.Y, Y: pt.X}
+}
+
+// New creates new handler which is used to access the display.
+func New(p spi.Port, dc, cs, rst gpio.PinOut, busy gpio.PinIn, opts *Opts) (*Dev, error) {
+	c, err := p.Connect(5*physic.MegaHertz, spi.Mode0, 8)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := busy.In(gpio.Float, gpio.FallingEdge); err != nil {
+		return nil, err
+	}
+
+	displaySize := image.Pt(opts.Width, opts.Height)
+
+	// The physical X axis is sized to have one-byte alignment on the (0,0)
+	// on-display position after rotation.
+	bufferSize := image.Pt((opts.Width+7)/8*8, opts.Height)
+
+	switch opts.Origin {
+	case TopLeft, BottomRight:
+	case TopRight, BottomLeft:
+		displaySize = flipPt(displaySize)
+		bufferSize = flipPt(bufferSize)
+	default:
+		return nil, fmt.Errorf("waveshare1in54: unknown corner %v", opts.Origin)
+	}
+	if opts.FullRefreshEvery < 0 || opts.FullRefreshInterval < 0 {
+		return nil, errors.New("waveshare1in54: full refresh policy cannot be negative")
+	}
+
+	d := &Dev{
+		c:      c,
+		dc:     dc,
+		cs:     cs,
+		rst:    rst,
+		busy:   busy,
+		bounds: image.Rectangle{Max: displaySize},
+		buffer: image1bit.NewVerticalLSB(image.Rectangle{
+			Max: bufferSize,
+		}),
+		mode: Full,
+		opts: opts,
+	}
+
+	// Default color
+	draw.Src.Draw(d.buffer, d.buffer.Bounds(), &image.Uniform{image1bit.On}, image.Point{})
+
+	return d, nil
+}
+
+// NewHat creates new handler which is used to access the display. Default Waveshare Hat configuration is used.
+func NewHat(p spi.Port, opts *Opts) (*Dev, error) {
+	dc := rpi.P1_22
+	cs := rpi.P1_24
+	rst := rpi.P1_11
+	busy := rpi.P1_18
+	return New(p, dc, cs, rst, busy, opts)
+}
+
+// Init configures the display for usage through the other functions.
+func (d *Dev) Init() error {
+	// Hardware Reset
+	if err := d.reset(); err != nil {
+		return err
+	}
+
+	eh := errorHandler{d: *d}
+
+	initDisplay(&eh, d.opts)
+
+	if eh.err == nil {
+		configDisplayMode(&eh, d.mode)
+	}
+
+	return eh.err
+}
+
+// SetUpdateMode changes the way updates to the displayed image are applied. In
+// Full mode (the default) a full refresh is done with all pixels cleared and
+// re-applied. In Partial mode only the changed pixels are updated, potentially
+// leaving behind small optical artifacts due to the way e-paper displays work.
+func (d *Dev) SetUpdateMode(mode PartialUpdate) error {
+	d.mode = mode
+
+	eh := errorHandler{d: *d}
+	configDisplayMode(&eh, d.mode)
+
+	return eh.err
+}
+
+// Clear clears the display with color, usually image1bit.On (white) or
+// image1bit.Off (black).
+//
+// A full refresh is always done, also clearing the ghosting left by partial
+// updates.
+func (d *Dev) Clear(color color.Color) error {
+	return d.draw(d.buffer.Bounds(), &image.Uniform{
+		C: image1bit.BitModel.Convert(color).(image1bit.Bit),
+	}, image.Point{}, true)
+}
+
+// FullRefresh refreshes the whole display with the image last drawn, clearing
+// the ghosting left by partial updates.
+func (d *Dev) FullRefresh() error {
+	eh := errorHandler{d: *d}
+	refreshDisplay(&eh, d.mode, true)
+	if eh.err == nil {
+		d.refreshed(true)
+	}
+	return eh.err
+}
+
+// ColorModel returns a 1Bit color model.
+func (d *Dev) ColorModel() color.Model {
+	return image1bit.BitModel
+}
+
+// Bounds returns the bounds for the configurated display.
+func (d *Dev) Bounds() image.Rectangle {
+	return d.bounds
+}
+
+// Draw draws the given image to the display. Only the destination area is
+// uploaded. Depending on the update mode the whole display or the destination
+// area is refreshed.
+//
+// In Partial mode, a full refresh is done instead when required by
+// Opts.FullRefreshEvery or Opts.FullRefreshInterval.
+func (d *Dev) Draw(dstRect image.Rectangle, src image.Image, srcPts image.Point) error {
+	return d.draw(dstRect, src, srcPts, d.mode == Full || d.fullRefreshDue(time.Now()))
+}
+
+// Halt clears the display.
+func (d *Dev) Halt() error {
+	return d.Clear(image1bit.On)
+}
+
+// String returns a string containing configuration information.
+func (d *Dev) String() string {
+	return fmt.Sprintf("epd.Dev{%s, %s, Width: %d, Height: %d}", d.c, d.dc, d.bounds.Dx(), d.bounds.Dy())
+}
+
+// Sleep makes the controller enter deep sleep mode. It can be woken up by
+// calling Init again.
+func (d *Dev) Sleep() error {
+	eh := errorHandler{d: *d}
+
+	// Deep sleep mode 1, RAM content is retained.
+	eh.sendCommand(deepSleepMode)
+	eh.sendData([]byte{0x01})
+
+	return eh.err
+}
+
+func (d *Dev) draw(dstRect image.Rectangle, src image.Image, srcPts image.Point, full bool) error {
+	opts := drawOpts{
+		devSize: d.bounds.Max,
+		origin:  d.opts.Origin,
+		buffer:  d.buffer,
+		dstRect: dstRect,
+		src:     src,
+		srcPts:  srcPts,
+	}
+
+	eh := errorHandler{d: *d}
+
+	drawImage(&eh, &opts)
+
+	if eh.err == nil {
+		refreshDisplay(&eh, d.mode, full)
+	}
+	if eh.err == nil {
+		d.refreshed(full)
+	}
+
+	return eh.err
+}
+
+// fullRefreshDue returns true if the refresh policy requires the next
+// refresh to be a full one.
+func (d *Dev) fullRefreshDue(now time.Time) bool {
+	if n := d.opts.FullRefreshEvery; n > 0 && d.partialDraws >= n {
+		return true
+	}
+	if i := d.opts.FullRefreshInterval; i > 0 && now.Sub(d.lastFull) >= i {
+		return true
+	}
+	return false
+}
+
+// refreshed records a refresh for the refresh policy.
+func (d *Dev) refreshed(full bool) {
+	if full {
+		d.partialDraws = 0
+		d.lastFull = time.Now()
+	} else {
+		d.partialDraws++
+	}
+}
+
+// Reset the hardware
+func (d *Dev) reset() error {
+	eh := errorHandler{d: *d}
+
+	eh.rstOut(gpio.High)
+	time.Sleep(20 * time.Millisecond)
+	eh.rstOut(gpio.Low)
+	time.Sleep(2 * time.Millisecond)
+	eh.rstOut(gpio.High)
+	time.Sleep(20 * time.Millisecond)
+
+	return eh.err
+}
+
+var _ display.Drawer = &Dev{}
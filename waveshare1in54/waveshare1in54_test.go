@@ -0,0 +1,159 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package waveshare1in54
+
+import (
+	"bytes"
+	"image"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpiotest"
+	"periph.io/x/conn/v3/spi/spitest"
+	"periph.io/x/devices/v3/devicetest"
+	"periph.io/x/devices/v3/ssd1306/image1bit"
+)
+
+func TestNew(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		opts       Opts
+		wantString string
+		wantBounds image.Rectangle
+		wantErr    bool
+	}{
+		{
+			name:       "EPD1in54",
+			opts:       EPD1in54,
+			wantBounds: image.Rect(0, 0, 200, 200),
+			wantString: "epd.Dev{playback, (0), Width: 200, Height: 200}",
+		},
+		{
+			name: "unknown corner",
+			opts: func() Opts {
+				opts := EPD1in54
+				opts.Origin = 4
+				return opts
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "negative refresh policy",
+			opts: func() Opts {
+				opts := EPD1in54
+				opts.FullRefreshEvery = -1
+				return opts
+			}(),
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dev, err := New(&spitest.Playback{}, &gpiotest.Pin{}, &gpiotest.Pin{}, &gpiotest.Pin{}, &gpiotest.Pin{
+				EdgesChan: make(chan gpio.Level, 1),
+			}, &tc.opts)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("New() should fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("New() failed: %v", err)
+			}
+
+			if diff := cmp.Diff(dev.String(), tc.wantString); diff != "" {
+				t.Errorf("String() difference (-got +want):\n%s", diff)
+			}
+
+			if diff := cmp.Diff(dev.Bounds(), tc.wantBounds); diff != "" {
+				t.Errorf("Bounds() difference (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDraw(t *testing.T) {
+	c := devicetest.NewCommandConn()
+	dev, err := New(c, &c.DC, &gpiotest.Pin{}, &gpiotest.Pin{}, &gpiotest.Pin{
+		EdgesChan: make(chan gpio.Level, 1),
+	}, &EPD1in54)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An 8x2 black rectangle at the top left corner.
+	if err := dev.Draw(image.Rect(0, 0, 8, 2), &image.Uniform{image1bit.Off}, image.Point{}); err != nil {
+		t.Fatal(err)
+	}
+	area := []record{
+		{Cmd: dataEntryModeSetting, Data: []byte{0x03}},
+		{Cmd: setRAMXAddressStartEndPosition, Data: []byte{0, 0}},
+		{Cmd: setRAMYAddressStartEndPosition, Data: []byte{0, 0, 1, 0}},
+		{Cmd: setRAMXAddressCounter, Data: []byte{0}},
+		{Cmd: setRAMYAddressCounter, Data: []byte{0, 0}},
+	}
+	var want []record
+	for _, cmd := range []byte{writeRAMBW, writeRAMRed} {
+		want = append(want, area...)
+		want = append(want, record{Cmd: cmd, Data: []byte{0x00, 0x00}})
+	}
+	want = append(want,
+		record{Cmd: displayUpdateControl2, Data: []byte{0xf7}},
+		record{Cmd: masterActivation})
+	if diff := c.Diff(want); diff != "" {
+		t.Errorf("Draw() difference (-got +want):\n%s", diff)
+	}
+
+	c.Reset()
+	if err := dev.Clear(image1bit.On); err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range c.Records {
+		if r.Cmd == writeRAMBW && !bytes.Equal(r.Data, bytes.Repeat([]byte{0xFF}, 200*200/8)) {
+			t.Errorf("Clear() sent %d bytes, want all white", len(r.Data))
+		}
+	}
+}
+
+func TestFullRefreshDue(t *testing.T) {
+	now := time.Now()
+	for _, tc := range []struct {
+		name         string
+		opts         Opts
+		partialDraws int
+		lastFull     time.Time
+		want         bool
+	}{
+		{
+			name:         "no policy",
+			partialDraws: 100,
+		},
+		{
+			name:         "count reached",
+			opts:         Opts{FullRefreshEvery: 5},
+			partialDraws: 5,
+			want:         true,
+		},
+		{
+			name:     "interval reached",
+			opts:     Opts{FullRefreshInterval: time.Hour},
+			lastFull: now.Add(-time.Hour),
+			want:     true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			d := Dev{opts: &tc.opts, partialDraws: tc.partialDraws, lastFull: tc.lastFull}
+			if got := d.fullRefreshDue(now); got != tc.want {
+				t.Errorf("fullRefreshDue() = %t, want %t", got, tc.want)
+			}
+			d.refreshed(true)
+			if d.fullRefreshDue(now) {
+				t.Error("fullRefreshDue() = true after a full refresh")
+			}
+		})
+	}
+}
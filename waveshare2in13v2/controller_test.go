@@ -8,26 +8,21 @@ import (
 	"bytes"
 	"testing"
 
-	"github.com/google/go-cmp/cmp"
-	"github.com/google/go-cmp/cmp/cmpopts"
+	"periph.io/x/devices/v3/devicetest"
 )
 
-type record struct {
-	cmd  byte
-	data []byte
-}
+type record = devicetest.Record
 
-type fakeController []record
+type fakeController struct {
+	devicetest.Recorder
+}
 
 func (r *fakeController) sendCommand(cmd byte) {
-	*r = append(*r, record{
-		cmd: cmd,
-	})
+	r.Command(cmd)
 }
 
 func (r *fakeController) sendData(data []byte) {
-	cur := &(*r)[len(*r)-1]
-	cur.data = append(cur.data, data...)
+	r.Data(data)
 }
 
 func (*fakeController) waitUntilIdle() {
@@ -43,24 +38,24 @@ func TestInitDisplay(t *testing.T) {
 			name: "epd2in13v2",
 			opts: EPD2in13v2,
 			want: []record{
-				{cmd: swReset},
-				{cmd: setAnalogBlockControl, data: []byte{0x54}},
-				{cmd: setDigitalBlockControl, data: []byte{0x3b}},
+				{Cmd: swReset},
+				{Cmd: setAnalogBlockControl, Data: []byte{0x54}},
+				{Cmd: setDigitalBlockControl, Data: []byte{0x3b}},
 				{
-					cmd:  driverOutputControl,
-					data: []byte{250 - 1, 0, 0},
+					Cmd:  driverOutputControl,
+					Data: []byte{250 - 1, 0, 0},
 				},
-				{cmd: gateDrivingVoltageControl, data: []byte{gateDrivingVoltage19V}},
+				{Cmd: gateDrivingVoltageControl, Data: []byte{gateDrivingVoltage19V}},
 				{
-					cmd: sourceDrivingVoltageControl,
-					data: []byte{
+					Cmd: sourceDrivingVoltageControl,
+					Data: []byte{
 						sourceDrivingVoltageVSH1_15V,
 						sourceDrivingVoltageVSH2_5V,
 						sourceDrivingVoltageVSL_neg15V,
 					},
 				},
-				{cmd: setDummyLinePeriod, data: []byte{0x30}},
-				{cmd: setGateTime, data: []byte{0x0a}},
+				{Cmd: setDummyLinePeriod, Data: []byte{0x30}},
+				{Cmd: setGateTime, Data: []byte{0x0a}},
 			},
 		},
 	} {
@@ -69,7 +64,7 @@ func TestInitDisplay(t *testing.T) {
 
 			initDisplay(&got, &tc.opts)
 
-			if diff := cmp.Diff([]record(got), tc.want, cmpopts.EquateEmpty(), cmp.AllowUnexported(record{})); diff != "" {
+			if diff := got.Diff(tc.want); diff != "" {
 				t.Errorf("initDisplay() difference (-got +want):\n%s", diff)
 			}
 		})
@@ -88,12 +83,12 @@ func TestConfigDisplayMode(t *testing.T) {
 			mode: Full,
 			lut:  bytes.Repeat([]byte{'F'}, 100),
 			want: []record{
-				{cmd: writeVcomRegister, data: []byte{0x55}},
-				{cmd: borderWaveformControl, data: []byte{0x03}},
-				{cmd: writeLutRegister, data: bytes.Repeat([]byte{'F'}, 70)},
-				{cmd: 0x37, data: []byte{0x00, 0x00, 0x00, 0x00, 0x40, 0x00, 0x00}},
-				{cmd: displayUpdateControl2, data: []byte{0xc0}},
-				{cmd: masterActivation},
+				{Cmd: writeVcomRegister, Data: []byte{0x55}},
+				{Cmd: borderWaveformControl, Data: []byte{0x03}},
+				{Cmd: writeLutRegister, Data: bytes.Repeat([]byte{'F'}, 70)},
+				{Cmd: 0x37, Data: []byte{0x00, 0x00, 0x00, 0x00, 0x40, 0x00, 0x00}},
+				{Cmd: displayUpdateControl2, Data: []byte{0xc0}},
+				{Cmd: masterActivation},
 			},
 		},
 		{
@@ -101,12 +96,12 @@ func TestConfigDisplayMode(t *testing.T) {
 			mode: Partial,
 			lut:  bytes.Repeat([]byte{'P'}, 70),
 			want: []record{
-				{cmd: writeVcomRegister, data: []byte{0x24}},
-				{cmd: borderWaveformControl, data: []byte{0x01}},
-				{cmd: writeLutRegister, data: bytes.Repeat([]byte{'P'}, 70)},
-				{cmd: 0x37, data: []byte{0x00, 0x00, 0x00, 0x00, 0x40, 0x00, 0x00}},
-				{cmd: displayUpdateControl2, data: []byte{0xc0}},
-				{cmd: masterActivation},
+				{Cmd: writeVcomRegister, Data: []byte{0x24}},
+				{Cmd: borderWaveformControl, Data: []byte{0x01}},
+				{Cmd: writeLutRegister, Data: bytes.Repeat([]byte{'P'}, 70)},
+				{Cmd: 0x37, Data: []byte{0x00, 0x00, 0x00, 0x00, 0x40, 0x00, 0x00}},
+				{Cmd: displayUpdateControl2, Data: []byte{0xc0}},
+				{Cmd: masterActivation},
 			},
 		},
 	} {
@@ -115,7 +110,7 @@ func TestConfigDisplayMode(t *testing.T) {
 
 			configDisplayMode(&got, tc.mode, tc.lut)
 
-			if diff := cmp.Diff([]record(got), tc.want, cmpopts.EquateEmpty(), cmp.AllowUnexported(record{})); diff != "" {
+			if diff := got.Diff(tc.want); diff != "" {
 				t.Errorf("configDisplayMode() difference (-got +want):\n%s", diff)
 			}
 		})
@@ -132,18 +127,18 @@ func TestUpdateDisplay(t *testing.T) {
 			name: "full",
 			mode: Full,
 			want: []record{
-				{cmd: displayUpdateControl1, data: []byte{0}},
-				{cmd: displayUpdateControl2, data: []byte{0xc7}},
-				{cmd: masterActivation},
+				{Cmd: displayUpdateControl1, Data: []byte{0}},
+				{Cmd: displayUpdateControl2, Data: []byte{0xc7}},
+				{Cmd: masterActivation},
 			},
 		},
 		{
 			name: "partial",
 			mode: Partial,
 			want: []record{
-				{cmd: displayUpdateControl1, data: []byte{0x80}},
-				{cmd: displayUpdateControl2, data: []byte{0xc7}},
-				{cmd: masterActivation},
+				{Cmd: displayUpdateControl1, Data: []byte{0x80}},
+				{Cmd: displayUpdateControl2, Data: []byte{0xc7}},
+				{Cmd: masterActivation},
 			},
 		},
 	} {
@@ -152,7 +147,7 @@ func TestUpdateDisplay(t *testing.T) {
 
 			updateDisplay(&got, tc.mode)
 
-			if diff := cmp.Diff([]record(got), tc.want, cmpopts.EquateEmpty(), cmp.AllowUnexported(record{})); diff != "" {
+			if diff := got.Diff(tc.want); diff != "" {
 				t.Errorf("updateDisplay() difference (-got +want):\n%s", diff)
 			}
 		})
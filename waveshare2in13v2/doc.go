@@ -14,4 +14,7 @@
 // controller is compatible with the SSD1675A (sometimes also referred to as
 // SSD1675). The SSD1675A should not be mixed up with the SSD1675B. They have
 // different LUT formats (70 bytes for SSD1675A, 100 bytes for SSD1675B).
+//
+// Communication failures are reported as *IOError, while a controller that
+// stays busy longer than Opts.BusyTimeout fails with ErrBusyTimeout.
 package waveshare2in13v2
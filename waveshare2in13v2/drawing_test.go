@@ -11,7 +11,6 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
-	"github.com/google/go-cmp/cmp/cmpopts"
 	"periph.io/x/devices/v3/ssd1306/image1bit"
 )
 
@@ -339,14 +338,14 @@ func TestSendImage(t *testing.T) {
 				buffer:  image1bit.NewVerticalLSB(image.Rect(0, 0, 64, 64)),
 			},
 			want: []record{
-				{cmd: dataEntryModeSetting, data: []byte{0x3}},
-				{cmd: setRAMXAddressStartEndPosition, data: []byte{2, 4 - 1}},
-				{cmd: setRAMYAddressStartEndPosition, data: []byte{20, 0, 40 - 1, 0}},
-				{cmd: setRAMXAddressCounter, data: []byte{2}},
-				{cmd: setRAMYAddressCounter, data: []byte{20, 0}},
+				{Cmd: dataEntryModeSetting, Data: []byte{0x3}},
+				{Cmd: setRAMXAddressStartEndPosition, Data: []byte{2, 4 - 1}},
+				{Cmd: setRAMYAddressStartEndPosition, Data: []byte{20, 0, 40 - 1, 0}},
+				{Cmd: setRAMXAddressCounter, Data: []byte{2}},
+				{Cmd: setRAMYAddressCounter, Data: []byte{20, 0}},
 				{
-					cmd:  writeRAMBW,
-					data: bytes.Repeat([]byte{0}, 2*(30-10)),
+					Cmd:  writeRAMBW,
+					Data: bytes.Repeat([]byte{0}, 2*(30-10)),
 				},
 			},
 		},
@@ -363,14 +362,14 @@ func TestSendImage(t *testing.T) {
 				}(),
 			},
 			want: []record{
-				{cmd: dataEntryModeSetting, data: []byte{0x3}},
-				{cmd: setRAMXAddressStartEndPosition, data: []byte{2, 6 - 1}},
-				{cmd: setRAMYAddressStartEndPosition, data: []byte{4, 0, 8 - 1, 0}},
-				{cmd: setRAMXAddressCounter, data: []byte{2}},
-				{cmd: setRAMYAddressCounter, data: []byte{4, 0}},
+				{Cmd: dataEntryModeSetting, Data: []byte{0x3}},
+				{Cmd: setRAMXAddressStartEndPosition, Data: []byte{2, 6 - 1}},
+				{Cmd: setRAMYAddressStartEndPosition, Data: []byte{4, 0, 8 - 1, 0}},
+				{Cmd: setRAMXAddressCounter, Data: []byte{2}},
+				{Cmd: setRAMYAddressCounter, Data: []byte{4, 0}},
 				{
-					cmd:  writeRAMRed,
-					data: bytes.Repeat([]byte{0x7f, 0xff, 0xff, 0x80}, 4),
+					Cmd:  writeRAMRed,
+					Data: bytes.Repeat([]byte{0x7f, 0xff, 0xff, 0x80}, 4),
 				},
 			},
 		},
@@ -387,14 +386,14 @@ func TestSendImage(t *testing.T) {
 				}(),
 			},
 			want: []record{
-				{cmd: dataEntryModeSetting, data: []byte{0x3}},
-				{cmd: setRAMXAddressStartEndPosition, data: []byte{0, 10 - 1}},
-				{cmd: setRAMYAddressStartEndPosition, data: []byte{0, 0, 120 - 1, 0}},
-				{cmd: setRAMXAddressCounter, data: []byte{0}},
-				{cmd: setRAMYAddressCounter, data: []byte{0, 0}},
+				{Cmd: dataEntryModeSetting, Data: []byte{0x3}},
+				{Cmd: setRAMXAddressStartEndPosition, Data: []byte{0, 10 - 1}},
+				{Cmd: setRAMYAddressStartEndPosition, Data: []byte{0, 0, 120 - 1, 0}},
+				{Cmd: setRAMXAddressCounter, Data: []byte{0}},
+				{Cmd: setRAMYAddressCounter, Data: []byte{0, 0}},
 				{
-					cmd:  writeRAMBW,
-					data: bytes.Repeat([]byte{0xff}, 80/8*120),
+					Cmd:  writeRAMBW,
+					Data: bytes.Repeat([]byte{0xff}, 80/8*120),
 				},
 			},
 		},
@@ -412,14 +411,14 @@ func TestSendImage(t *testing.T) {
 				}(),
 			},
 			want: []record{
-				{cmd: dataEntryModeSetting, data: []byte{0x3}},
-				{cmd: setRAMXAddressStartEndPosition, data: []byte{2, 5}},
-				{cmd: setRAMYAddressStartEndPosition, data: []byte{17 - 5, 0, 29 + 5 - 1, 0}},
-				{cmd: setRAMXAddressCounter, data: []byte{2}},
-				{cmd: setRAMYAddressCounter, data: []byte{12, 0}},
+				{Cmd: dataEntryModeSetting, Data: []byte{0x3}},
+				{Cmd: setRAMXAddressStartEndPosition, Data: []byte{2, 5}},
+				{Cmd: setRAMYAddressStartEndPosition, Data: []byte{17 - 5, 0, 29 + 5 - 1, 0}},
+				{Cmd: setRAMXAddressCounter, Data: []byte{2}},
+				{Cmd: setRAMYAddressCounter, Data: []byte{12, 0}},
 				{
-					cmd: writeRAMBW,
-					data: append(
+					Cmd: writeRAMBW,
+					Data: append(
 						append(
 							bytes.Repeat([]byte{0x00, 0x00, 0x00, 0x00}, 5),
 							bytes.Repeat([]byte{0x0f, 0xff, 0xff, 0xf0}, 29-17)...),
@@ -442,14 +441,14 @@ func TestSendImage(t *testing.T) {
 				}(),
 			},
 			want: []record{
-				{cmd: dataEntryModeSetting, data: []byte{0x3}},
-				{cmd: setRAMXAddressStartEndPosition, data: []byte{(48 - 40) / 8, ((48 - 16 + 7) / 8) - 1}},
-				{cmd: setRAMYAddressStartEndPosition, data: []byte{15 - 5, 0, (30 + 5) - 1, 0}},
-				{cmd: setRAMXAddressCounter, data: []byte{1}},
-				{cmd: setRAMYAddressCounter, data: []byte{10, 0}},
+				{Cmd: dataEntryModeSetting, Data: []byte{0x3}},
+				{Cmd: setRAMXAddressStartEndPosition, Data: []byte{(48 - 40) / 8, ((48 - 16 + 7) / 8) - 1}},
+				{Cmd: setRAMYAddressStartEndPosition, Data: []byte{15 - 5, 0, (30 + 5) - 1, 0}},
+				{Cmd: setRAMXAddressCounter, Data: []byte{1}},
+				{Cmd: setRAMYAddressCounter, Data: []byte{10, 0}},
 				{
-					cmd: writeRAMBW,
-					data: append(
+					Cmd: writeRAMBW,
+					Data: append(
 						append(
 							bytes.Repeat([]byte{0x00, 0x00, 0x00}, 5),
 							bytes.Repeat([]byte{0x0f, 0xff, 0xf0}, 30-15)...),
@@ -473,14 +472,14 @@ func TestSendImage(t *testing.T) {
 				}(),
 			},
 			want: []record{
-				{cmd: dataEntryModeSetting, data: []byte{0x3}},
-				{cmd: setRAMXAddressStartEndPosition, data: []byte{(53 - 32) / 8, ((53 - 16 + 7) / 8) - 1}},
-				{cmd: setRAMYAddressStartEndPosition, data: []byte{15 - 5, 0, (30 + 5) - 1, 0}},
-				{cmd: setRAMXAddressCounter, data: []byte{2}},
-				{cmd: setRAMYAddressCounter, data: []byte{10, 0}},
+				{Cmd: dataEntryModeSetting, Data: []byte{0x3}},
+				{Cmd: setRAMXAddressStartEndPosition, Data: []byte{(53 - 32) / 8, ((53 - 16 + 7) / 8) - 1}},
+				{Cmd: setRAMYAddressStartEndPosition, Data: []byte{15 - 5, 0, (30 + 5) - 1, 0}},
+				{Cmd: setRAMXAddressCounter, Data: []byte{2}},
+				{Cmd: setRAMYAddressCounter, Data: []byte{10, 0}},
 				{
-					cmd: writeRAMBW,
-					data: append(
+					Cmd: writeRAMBW,
+					Data: append(
 						append(
 							bytes.Repeat([]byte{0x00, 0x00, 0x00}, 5),
 							bytes.Repeat([]byte{0x0f, 0xff, 0xf0}, 30-15)...),
@@ -503,14 +502,14 @@ func TestSendImage(t *testing.T) {
 				}(),
 			},
 			want: []record{
-				{cmd: dataEntryModeSetting, data: []byte{0x3}},
-				{cmd: setRAMXAddressStartEndPosition, data: []byte{(64 - 40) / 8, ((64 - 16 + 7) / 8) - 1}},
-				{cmd: setRAMYAddressStartEndPosition, data: []byte{48 - (30 + 5), 0, 48 - (15 - 5) - 1, 0}},
-				{cmd: setRAMXAddressCounter, data: []byte{3}},
-				{cmd: setRAMYAddressCounter, data: []byte{48 - (30 + 5), 0}},
+				{Cmd: dataEntryModeSetting, Data: []byte{0x3}},
+				{Cmd: setRAMXAddressStartEndPosition, Data: []byte{(64 - 40) / 8, ((64 - 16 + 7) / 8) - 1}},
+				{Cmd: setRAMYAddressStartEndPosition, Data: []byte{48 - (30 + 5), 0, 48 - (15 - 5) - 1, 0}},
+				{Cmd: setRAMXAddressCounter, Data: []byte{3}},
+				{Cmd: setRAMYAddressCounter, Data: []byte{48 - (30 + 5), 0}},
 				{
-					cmd: writeRAMRed,
-					data: append(
+					Cmd: writeRAMRed,
+					Data: append(
 						append(
 							bytes.Repeat([]byte{0x00, 0x00, 0x00}, 5),
 							bytes.Repeat([]byte{0x0f, 0xff, 0xf0}, 30-15)...),
@@ -533,14 +532,14 @@ func TestSendImage(t *testing.T) {
 				}(),
 			},
 			want: []record{
-				{cmd: dataEntryModeSetting, data: []byte{0x3}},
-				{cmd: setRAMXAddressStartEndPosition, data: []byte{16 / 8, ((40 + 7) / 8) - 1}},
-				{cmd: setRAMYAddressStartEndPosition, data: []byte{64 - (30 + 5), 0, 64 - (15 - 5) - 1, 0}},
-				{cmd: setRAMXAddressCounter, data: []byte{2}},
-				{cmd: setRAMYAddressCounter, data: []byte{64 - (30 + 5), 0}},
+				{Cmd: dataEntryModeSetting, Data: []byte{0x3}},
+				{Cmd: setRAMXAddressStartEndPosition, Data: []byte{16 / 8, ((40 + 7) / 8) - 1}},
+				{Cmd: setRAMYAddressStartEndPosition, Data: []byte{64 - (30 + 5), 0, 64 - (15 - 5) - 1, 0}},
+				{Cmd: setRAMXAddressCounter, Data: []byte{2}},
+				{Cmd: setRAMYAddressCounter, Data: []byte{64 - (30 + 5), 0}},
 				{
-					cmd: writeRAMRed,
-					data: append(
+					Cmd: writeRAMRed,
+					Data: append(
 						append(
 							bytes.Repeat([]byte{0x00, 0x00, 0x00}, 5),
 							bytes.Repeat([]byte{0x0f, 0xff, 0xf0}, 30-15)...),
@@ -559,7 +558,7 @@ func TestSendImage(t *testing.T) {
 
 			tc.opts.sendImage(&got, tc.cmd, &spec)
 
-			if diff := cmp.Diff([]record(got), tc.want, cmpopts.EquateEmpty(), cmp.AllowUnexported(record{})); diff != "" {
+			if diff := got.Diff(tc.want); diff != "" {
 				t.Errorf("sendImage() difference (-got +want):\n%s", diff)
 			}
 		})
@@ -589,14 +588,14 @@ func TestDrawImage(t *testing.T) {
 				srcPts:   image.Pt(0, 0),
 			},
 			want: []record{
-				{cmd: dataEntryModeSetting, data: []byte{0x3}},
-				{cmd: setRAMXAddressStartEndPosition, data: []byte{2, 6 - 1}},
-				{cmd: setRAMYAddressStartEndPosition, data: []byte{4, 0, 8 - 1, 0}},
-				{cmd: setRAMXAddressCounter, data: []byte{2}},
-				{cmd: setRAMYAddressCounter, data: []byte{4, 0}},
+				{Cmd: dataEntryModeSetting, Data: []byte{0x3}},
+				{Cmd: setRAMXAddressStartEndPosition, Data: []byte{2, 6 - 1}},
+				{Cmd: setRAMYAddressStartEndPosition, Data: []byte{4, 0, 8 - 1, 0}},
+				{Cmd: setRAMXAddressCounter, Data: []byte{2}},
+				{Cmd: setRAMYAddressCounter, Data: []byte{4, 0}},
 				{
-					cmd:  writeRAMBW,
-					data: bytes.Repeat([]byte{0x7f, 0xff, 0xff, 0x80}, 4),
+					Cmd:  writeRAMBW,
+					Data: bytes.Repeat([]byte{0x7f, 0xff, 0xff, 0x80}, 4),
 				},
 			},
 		},
@@ -611,14 +610,14 @@ func TestDrawImage(t *testing.T) {
 				srcPts:   image.Pt(33, 44),
 			},
 			want: []record{
-				{cmd: dataEntryModeSetting, data: []byte{0x3}},
-				{cmd: setRAMXAddressStartEndPosition, data: []byte{0, 10 - 1}},
-				{cmd: setRAMYAddressStartEndPosition, data: []byte{0, 0, 120 - 1, 0}},
-				{cmd: setRAMXAddressCounter, data: []byte{0}},
-				{cmd: setRAMYAddressCounter, data: []byte{0, 0}},
+				{Cmd: dataEntryModeSetting, Data: []byte{0x3}},
+				{Cmd: setRAMXAddressStartEndPosition, Data: []byte{0, 10 - 1}},
+				{Cmd: setRAMYAddressStartEndPosition, Data: []byte{0, 0, 120 - 1, 0}},
+				{Cmd: setRAMXAddressCounter, Data: []byte{0}},
+				{Cmd: setRAMYAddressCounter, Data: []byte{0, 0}},
 				{
-					cmd:  writeRAMRed,
-					data: bytes.Repeat([]byte{0xff}, 80/8*120),
+					Cmd:  writeRAMRed,
+					Data: bytes.Repeat([]byte{0xff}, 80/8*120),
 				},
 			},
 		},
@@ -628,7 +627,7 @@ func TestDrawImage(t *testing.T) {
 
 			drawImage(&got, &tc.opts)
 
-			if diff := cmp.Diff([]record(got), tc.want, cmpopts.EquateEmpty(), cmp.AllowUnexported(record{})); diff != "" {
+			if diff := got.Diff(tc.want); diff != "" {
 				t.Errorf("drawImage() difference (-got +want):\n%s", diff)
 			}
 		})
@@ -20,33 +20,56 @@ func (eh *errorHandler) rstOut(l gpio.Level) {
 	if eh.err != nil {
 		return
 	}
-	eh.err = eh.d.rst.Out(l)
+	if err := eh.d.rst.Out(l); err != nil {
+		eh.err = &IOError{Op: "rst pin", Err: err}
+	}
 }
 
 func (eh *errorHandler) cTx(w []byte, r []byte) {
 	if eh.err != nil {
 		return
 	}
-	eh.err = eh.d.c.Tx(w, r)
+	if err := eh.d.c.Tx(w, r); err != nil {
+		eh.err = &IOError{Op: "spi tx", Err: err}
+	}
 }
 
 func (eh *errorHandler) dcOut(l gpio.Level) {
 	if eh.err != nil {
 		return
 	}
-	eh.err = eh.d.dc.Out(l)
+	if err := eh.d.dc.Out(l); err != nil {
+		eh.err = &IOError{Op: "dc pin", Err: err}
+	}
 }
 
 func (eh *errorHandler) csOut(l gpio.Level) {
 	if eh.err != nil {
 		return
 	}
-	eh.err = eh.d.cs.Out(l)
+	if err := eh.d.cs.Out(l); err != nil {
+		eh.err = &IOError{Op: "cs pin", Err: err}
+	}
 }
 
+// waitUntilIdle waits for the controller to release the busy pin, failing with
+// ErrBusyTimeout after Opts.BusyTimeout.
 func (eh *errorHandler) waitUntilIdle() {
+	if eh.err != nil {
+		return
+	}
+	timeout := eh.d.opts.BusyTimeout
+	if timeout <= 0 {
+		timeout = defaultBusyTimeout
+	}
+	start := time.Now()
 	for busy := eh.d.busy; busy.Read() == gpio.High; {
-		busy.WaitForEdge(100 * time.Millisecond)
+		remaining := timeout - time.Since(start)
+		if remaining <= 0 {
+			eh.err = ErrBusyTimeout
+			return
+		}
+		busy.WaitForEdge(min(remaining, 100*time.Millisecond))
 	}
 }
 
@@ -5,6 +5,7 @@
 package waveshare2in13v2
 
 import (
+	"errors"
 	"fmt"
 	"image"
 	"image/color"
@@ -49,6 +50,9 @@ const (
 
 // Register values
 const (
+	deepSleepRetainRAM = 0x01
+	deepSleepLoseRAM   = 0x03
+
 	gateDrivingVoltage19V = 0x15
 
 	sourceDrivingVoltageVSH1_15V   = 0x41
@@ -80,6 +84,11 @@ type Dev struct {
 	bounds image.Rectangle
 	buffer *image1bit.VerticalLSB
 	mode   PartialUpdate
+	// asleep is set while the controller is in deep sleep and ignores all
+	// commands until the next hardware reset.
+	asleep bool
+	// lut overrides the LUT of the update modes when set.
+	lut LUT
 
 	opts *Opts
 }
@@ -98,6 +107,10 @@ const (
 // LUT contains the waveform that is used to program the display.
 type LUT []byte
 
+// LUTSize is the size of a LUT in the SSD1675A format: 5 groups of 7 bytes
+// for the voltage sources followed by 7 groups of 5 bytes for the timings.
+const LUTSize = 70
+
 // Opts definies the structure of the display configuration.
 type Opts struct {
 	Width         int
@@ -105,6 +118,34 @@ type Opts struct {
 	Origin        Corner
 	FullUpdate    LUT
 	PartialUpdate LUT
+	// CustomLUT, when set, is used for both the full and partial update modes
+	// instead of FullUpdate and PartialUpdate. It must be LUTSize bytes long.
+	CustomLUT LUT
+	// BusyTimeout is the maximum time to wait for the controller to release
+	// its busy pin, e.g. during a refresh. Defaults to 10 seconds when 0.
+	BusyTimeout time.Duration
+}
+
+// ErrBusyTimeout is returned when the controller stays busy longer than
+// Opts.BusyTimeout. It usually means the display is disconnected or the busy
+// pin is wired incorrectly.
+var ErrBusyTimeout = errors.New("waveshare2in13v2: timed out waiting for the controller")
+
+// IOError is returned when the communication with the controller fails, either
+// on the SPI bus or on one of its control pins.
+type IOError struct {
+	// Op is the failed operation, e.g. "spi tx" or "dc pin".
+	Op  string
+	Err error
+}
+
+func (e *IOError) Error() string {
+	return "waveshare2in13v2: " + e.Op + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *IOError) Unwrap() error {
+	return e.Err
 }
 
 // PartialUpdate defines if the display should do a full update or just a partial update.
@@ -117,40 +158,47 @@ const (
 	Partial PartialUpdate = true
 )
 
+// FullUpdateLUT is the waveform recommended by the vendor for full updates.
+var FullUpdateLUT = LUT{
+	0x80, 0x60, 0x40, 0x00, 0x00, 0x00, 0x00, //LUT0: BB:     VS 0 ~7
+	0x10, 0x60, 0x20, 0x00, 0x00, 0x00, 0x00, //LUT1: BW:     VS 0 ~7
+	0x80, 0x60, 0x40, 0x00, 0x00, 0x00, 0x00, //LUT2: WB:     VS 0 ~7
+	0x10, 0x60, 0x20, 0x00, 0x00, 0x00, 0x00, //LUT3: WW:     VS 0 ~7
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, //LUT4: VCOM:   VS 0 ~7
+
+	0x03, 0x03, 0x00, 0x00, 0x02, // TP0 A~D RP0
+	0x09, 0x09, 0x00, 0x00, 0x02, // TP1 A~D RP1
+	0x03, 0x03, 0x00, 0x00, 0x02, // TP2 A~D RP2
+	0x00, 0x00, 0x00, 0x00, 0x00, // TP3 A~D RP3
+	0x00, 0x00, 0x00, 0x00, 0x00, // TP4 A~D RP4
+	0x00, 0x00, 0x00, 0x00, 0x00, // TP5 A~D RP5
+	0x00, 0x00, 0x00, 0x00, 0x00, // TP6 A~D RP6
+}
+
+// PartialUpdateLUT is the waveform recommended by the vendor for partial
+// updates.
+var PartialUpdateLUT = LUT{
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, //LUT0: BB:     VS 0 ~7
+	0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, //LUT1: BW:     VS 0 ~7
+	0x40, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, //LUT2: WB:     VS 0 ~7
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, //LUT3: WW:     VS 0 ~7
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, //LUT4: VCOM:   VS 0 ~7
+
+	0x0A, 0x00, 0x00, 0x00, 0x00, // TP0 A~D RP0
+	0x00, 0x00, 0x00, 0x00, 0x00, // TP1 A~D RP1
+	0x00, 0x00, 0x00, 0x00, 0x00, // TP2 A~D RP2
+	0x00, 0x00, 0x00, 0x00, 0x00, // TP3 A~D RP3
+	0x00, 0x00, 0x00, 0x00, 0x00, // TP4 A~D RP4
+	0x00, 0x00, 0x00, 0x00, 0x00, // TP5 A~D RP5
+	0x00, 0x00, 0x00, 0x00, 0x00, // TP6 A~D RP6
+}
+
 // EPD2in13v2 cointains display configuration for the Waveshare 2in13v2.
 var EPD2in13v2 = Opts{
-	Width:  122,
-	Height: 250,
-	FullUpdate: LUT{
-		0x80, 0x60, 0x40, 0x00, 0x00, 0x00, 0x00, //LUT0: BB:     VS 0 ~7
-		0x10, 0x60, 0x20, 0x00, 0x00, 0x00, 0x00, //LUT1: BW:     VS 0 ~7
-		0x80, 0x60, 0x40, 0x00, 0x00, 0x00, 0x00, //LUT2: WB:     VS 0 ~7
-		0x10, 0x60, 0x20, 0x00, 0x00, 0x00, 0x00, //LUT3: WW:     VS 0 ~7
-		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, //LUT4: VCOM:   VS 0 ~7
-
-		0x03, 0x03, 0x00, 0x00, 0x02, // TP0 A~D RP0
-		0x09, 0x09, 0x00, 0x00, 0x02, // TP1 A~D RP1
-		0x03, 0x03, 0x00, 0x00, 0x02, // TP2 A~D RP2
-		0x00, 0x00, 0x00, 0x00, 0x00, // TP3 A~D RP3
-		0x00, 0x00, 0x00, 0x00, 0x00, // TP4 A~D RP4
-		0x00, 0x00, 0x00, 0x00, 0x00, // TP5 A~D RP5
-		0x00, 0x00, 0x00, 0x00, 0x00, // TP6 A~D RP6
-	},
-	PartialUpdate: LUT{
-		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, //LUT0: BB:     VS 0 ~7
-		0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, //LUT1: BW:     VS 0 ~7
-		0x40, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, //LUT2: WB:     VS 0 ~7
-		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, //LUT3: WW:     VS 0 ~7
-		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, //LUT4: VCOM:   VS 0 ~7
-
-		0x0A, 0x00, 0x00, 0x00, 0x00, // TP0 A~D RP0
-		0x00, 0x00, 0x00, 0x00, 0x00, // TP1 A~D RP1
-		0x00, 0x00, 0x00, 0x00, 0x00, // TP2 A~D RP2
-		0x00, 0x00, 0x00, 0x00, 0x00, // TP3 A~D RP3
-		0x00, 0x00, 0x00, 0x00, 0x00, // TP4 A~D RP4
-		0x00, 0x00, 0x00, 0x00, 0x00, // TP5 A~D RP5
-		0x00, 0x00, 0x00, 0x00, 0x00, // TP6 A~D RP6
-	},
+	Width:         122,
+	Height:        250,
+	FullUpdate:    FullUpdateLUT,
+	PartialUpdate: PartialUpdateLUT,
 }
 
 // flipPt returns a new image.Point with the X and Y coordinates exchanged.
@@ -169,6 +217,12 @@ func New(p spi.Port, dc, cs, rst gpio.PinOut, busy gpio.PinIn, opts *Opts) (*Dev
 		return nil, err
 	}
 
+	if opts.CustomLUT != nil {
+		if err := checkLUT(opts.CustomLUT); err != nil {
+			return nil, err
+		}
+	}
+
 	displaySize := image.Pt(opts.Width, opts.Height)
 
 	// The physical X axis is sized to have one-byte alignment on the (0,0)
@@ -195,6 +249,7 @@ func New(p spi.Port, dc, cs, rst gpio.PinOut, busy gpio.PinIn, opts *Opts) (*Dev
 			Max: bufferSize,
 		}),
 		mode: Full,
+		lut:  opts.CustomLUT,
 		opts: opts,
 	}
 
@@ -214,12 +269,14 @@ func NewHat(p spi.Port, opts *Opts) (*Dev, error) {
 }
 
 func (d *Dev) configMode(ctrl controller) {
-	var lut LUT
-
-	if d.mode == Full {
-		lut = d.opts.FullUpdate
-	} else {
-		lut = d.opts.PartialUpdate
+	lut := d.lut
+
+	if lut == nil {
+		if d.mode == Full {
+			lut = d.opts.FullUpdate
+		} else {
+			lut = d.opts.PartialUpdate
+		}
 	}
 
 	configDisplayMode(ctrl, d.mode, lut)
@@ -240,6 +297,10 @@ func (d *Dev) Init() error {
 		d.configMode(&eh)
 	}
 
+	if eh.err == nil {
+		d.asleep = false
+	}
+
 	return eh.err
 }
 
@@ -253,6 +314,10 @@ func (d *Dev) Init() error {
 // When using partial updates the Clear function can be used for the purpose,
 // followed by re-drawing.
 func (d *Dev) SetUpdateMode(mode PartialUpdate) error {
+	if d.asleep {
+		return errAsleep
+	}
+
 	d.mode = mode
 
 	eh := errorHandler{d: *d}
@@ -261,6 +326,29 @@ func (d *Dev) SetUpdateMode(mode PartialUpdate) error {
 	return eh.err
 }
 
+// SetLUT replaces the waveform used by both update modes, for example to
+// tune the refresh speed or the ghosting. The LUT must be LUTSize bytes long.
+// Use nil to restore the LUTs of the options, FullUpdate and PartialUpdate.
+//
+// The controller is reprogrammed immediately unless asleep, in which case the
+// LUT is applied by Wake.
+func (d *Dev) SetLUT(lut LUT) error {
+	if lut != nil {
+		if err := checkLUT(lut); err != nil {
+			return err
+		}
+	}
+	d.lut = lut
+	if d.asleep {
+		return nil
+	}
+
+	eh := errorHandler{d: *d}
+	d.configMode(&eh)
+
+	return eh.err
+}
+
 // Clear clears the display.
 func (d *Dev) Clear(color color.Color) error {
 	return d.Draw(d.buffer.Bounds(), &image.Uniform{
@@ -282,6 +370,10 @@ func (d *Dev) Bounds() image.Rectangle {
 // uploaded. Depending on the update mode the whole display or the destination
 // area is refreshed.
 func (d *Dev) Draw(dstRect image.Rectangle, src image.Image, srcPts image.Point) error {
+	if d.asleep {
+		return errAsleep
+	}
+
 	opts := drawOpts{
 		devSize: d.bounds.Max,
 		origin:  d.opts.Origin,
@@ -304,6 +396,9 @@ func (d *Dev) Draw(dstRect image.Rectangle, src image.Image, srcPts image.Point)
 
 // DrawPartial draws the given image to the display.
 //
+// Partial updates are selected with SetUpdateMode(Partial) and applied by
+// Draw.
+//
 // Deprecated: Use Draw instead. DrawPartial merely forwards all calls.
 func (d *Dev) DrawPartial(dstRect image.Rectangle, src image.Image, srcPts image.Point) error {
 	return d.Draw(dstRect, src, srcPts)
@@ -322,12 +417,46 @@ func (d *Dev) String() string {
 // Sleep makes the controller enter deep sleep mode. It can be woken up by
 // calling Init again.
 func (d *Dev) Sleep() error {
-	eh := errorHandler{d: *d}
-
 	// Turn off DC/DC converter, clock, output load and MCU. RAM content is
 	// retained.
-	eh.sendCommand(deepSleepMode)
-	eh.sendData([]byte{0x01})
+	return d.enterDeepSleep(deepSleepRetainRAM)
+}
+
+// DeepSleep makes the controller enter its lowest power mode, where the
+// display RAM is not retained. The displayed image stays visible as e-paper
+// doesn't need power to retain it.
+//
+// Draw and SetUpdateMode fail until Wake is called. This permits long-lived
+// applications to keep the Dev between infrequent updates.
+func (d *Dev) DeepSleep() error {
+	return d.enterDeepSleep(deepSleepLoseRAM)
+}
+
+// Wake wakes up the controller from Sleep or DeepSleep. The controller is
+// reset and initialized again, and the last drawn image is restored in its
+// RAM without refreshing the display so partial updates keep working.
+//
+// It does nothing if the controller is not asleep.
+func (d *Dev) Wake() error {
+	if !d.asleep {
+		return nil
+	}
+
+	if err := d.Init(); err != nil {
+		return err
+	}
+
+	opts := drawOpts{
+		devSize: d.bounds.Max,
+		origin:  d.opts.Origin,
+		buffer:  d.buffer,
+		dstRect: d.buffer.Bounds(),
+		src:     d.buffer,
+	}
+
+	eh := errorHandler{d: *d}
+
+	drawImage(&eh, &opts)
 
 	return eh.err
 }
@@ -337,13 +466,42 @@ func (d *Dev) reset() error {
 	eh := errorHandler{d: *d}
 
 	eh.rstOut(gpio.High)
-	time.Sleep(200 * time.Millisecond)
+	doSleep(200 * time.Millisecond)
 	eh.rstOut(gpio.Low)
-	time.Sleep(200 * time.Millisecond)
+	doSleep(200 * time.Millisecond)
 	eh.rstOut(gpio.High)
-	time.Sleep(200 * time.Millisecond)
+	doSleep(200 * time.Millisecond)
+
+	return eh.err
+}
+
+func (d *Dev) enterDeepSleep(mode byte) error {
+	eh := errorHandler{d: *d}
+
+	eh.sendCommand(deepSleepMode)
+	eh.sendData([]byte{mode})
+
+	if eh.err == nil {
+		d.asleep = true
+	}
 
 	return eh.err
 }
 
+var errAsleep = errors.New("waveshare2in13v2: controller is asleep, call Wake first")
+
+// checkLUT verifies that lut is in the SSD1675A format.
+func checkLUT(lut LUT) error {
+	if len(lut) != LUTSize {
+		return fmt.Errorf("waveshare2in13v2: invalid LUT size %d, expected %d bytes", len(lut), LUTSize)
+	}
+	return nil
+}
+
+// defaultBusyTimeout is used when Opts.BusyTimeout is 0. A full refresh takes
+// about 2 seconds.
+const defaultBusyTimeout = 10 * time.Second
+
+var doSleep = time.Sleep
+
 var _ display.Drawer = &Dev{}
@@ -5,10 +5,14 @@
 package waveshare2in13v2
 
 import (
+	"bytes"
+	"errors"
 	"image"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"periph.io/x/conn/v3/conntest"
 	"periph.io/x/conn/v3/gpio"
 	"periph.io/x/conn/v3/gpio/gpiotest"
 	"periph.io/x/conn/v3/spi/spitest"
@@ -93,3 +97,168 @@ func TestNew(t *testing.T) {
 		})
 	}
 }
+
+func TestDeepSleepWake(t *testing.T) {
+	defer func(f func(time.Duration)) { doSleep = f }(doSleep)
+	doSleep = func(time.Duration) {}
+
+	port := spitest.Record{}
+	rst := gpiotest.Pin{}
+	dev, err := New(&port, &gpiotest.Pin{}, &gpiotest.Pin{}, &rst, &gpiotest.Pin{
+		EdgesChan: make(chan gpio.Level, 1),
+	}, &EPD2in13v2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Waking up an awake controller is a no-op.
+	if err := dev.Wake(); err != nil {
+		t.Fatal(err)
+	}
+	if len(port.Ops) != 0 {
+		t.Fatalf("unexpected ops: %v", port.Ops)
+	}
+
+	if err := dev.DeepSleep(); err != nil {
+		t.Fatal(err)
+	}
+	want := []conntest.IO{{W: []byte{deepSleepMode}}, {W: []byte{deepSleepLoseRAM}}}
+	if diff := cmp.Diff(port.Ops, want); diff != "" {
+		t.Errorf("DeepSleep() difference (-got +want):\n%s", diff)
+	}
+	if err := dev.Draw(dev.Bounds(), &image.Uniform{image1bit.Off}, image.Point{}); err != errAsleep {
+		t.Fatalf("Draw() = %v, want %v", err, errAsleep)
+	}
+	if err := dev.SetUpdateMode(Partial); err != errAsleep {
+		t.Fatalf("SetUpdateMode() = %v, want %v", err, errAsleep)
+	}
+
+	port.Ops = nil
+	if err := dev.Wake(); err != nil {
+		t.Fatal(err)
+	}
+	if rst.L != gpio.High {
+		t.Error("reset pin not released")
+	}
+	if diff := cmp.Diff(port.Ops[0], conntest.IO{W: []byte{swReset}}); diff != "" {
+		t.Errorf("Wake() difference (-got +want):\n%s", diff)
+	}
+	// Both RAM banks are restored from the buffer.
+	var ram []byte
+	for _, op := range port.Ops {
+		if len(op.W) == 1 && (op.W[0] == writeRAMBW || op.W[0] == writeRAMRed) {
+			ram = append(ram, op.W[0])
+		}
+	}
+	if diff := cmp.Diff(ram, []byte{writeRAMBW, writeRAMRed}); diff != "" {
+		t.Errorf("Wake() RAM writes difference (-got +want):\n%s", diff)
+	}
+
+	if err := dev.Sleep(); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.Wake(); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.Draw(dev.Bounds(), &image.Uniform{image1bit.Off}, image.Point{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSetLUT(t *testing.T) {
+	defer func(f func(time.Duration)) { doSleep = f }(doSleep)
+	doSleep = func(time.Duration) {}
+
+	opts := EPD2in13v2
+	opts.CustomLUT = LUT{1, 2, 3}
+	if _, err := New(&spitest.Record{}, &gpiotest.Pin{}, &gpiotest.Pin{}, &gpiotest.Pin{}, &gpiotest.Pin{
+		EdgesChan: make(chan gpio.Level, 1),
+	}, &opts); err == nil {
+		t.Fatal("New() should fail with an invalid LUT size")
+	}
+
+	custom := bytes.Repeat([]byte{0x42}, LUTSize)
+	opts.CustomLUT = custom
+	port := spitest.Record{}
+	dev, err := New(&port, &gpiotest.Pin{}, &gpiotest.Pin{}, &gpiotest.Pin{}, &gpiotest.Pin{
+		EdgesChan: make(chan gpio.Level, 1),
+	}, &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// lutWrites returns the LUTs sent to the controller.
+	lutWrites := func() []LUT {
+		var luts []LUT
+		for i, op := range port.Ops {
+			if len(op.W) == 1 && op.W[0] == writeLutRegister && i+1 < len(port.Ops) {
+				luts = append(luts, port.Ops[i+1].W)
+			}
+		}
+		port.Ops = nil
+		return luts
+	}
+
+	// The custom LUT is used in both modes.
+	if err := dev.SetUpdateMode(Partial); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(lutWrites(), []LUT{custom}); diff != "" {
+		t.Errorf("SetUpdateMode() difference (-got +want):\n%s", diff)
+	}
+
+	if err := dev.SetLUT(LUT{1}); err == nil {
+		t.Fatal("SetLUT() should fail with an invalid LUT size")
+	}
+	if err := dev.SetLUT(nil); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(lutWrites(), []LUT{PartialUpdateLUT}); diff != "" {
+		t.Errorf("SetLUT(nil) difference (-got +want):\n%s", diff)
+	}
+
+	// A LUT set while asleep is applied on Wake.
+	if err := dev.Sleep(); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.SetLUT(FullUpdateLUT); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(lutWrites(), []LUT(nil)); diff != "" {
+		t.Errorf("SetLUT() while asleep difference (-got +want):\n%s", diff)
+	}
+	if err := dev.Wake(); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(lutWrites(), []LUT{FullUpdateLUT}); diff != "" {
+		t.Errorf("Wake() difference (-got +want):\n%s", diff)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	defer func(f func(time.Duration)) { doSleep = f }(doSleep)
+	doSleep = func(time.Duration) {}
+
+	opts := EPD2in13v2
+	opts.BusyTimeout = time.Millisecond
+	busy := gpiotest.Pin{L: gpio.High, EdgesChan: make(chan gpio.Level, 1)}
+	dev, err := New(&spitest.Record{}, &gpiotest.Pin{}, &gpiotest.Pin{}, &gpiotest.Pin{}, &busy, &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.Init(); err != ErrBusyTimeout {
+		t.Fatalf("Init() = %v, want %v", err, ErrBusyTimeout)
+	}
+
+	busy.L = gpio.Low
+	port := spitest.Playback{Playback: conntest.Playback{DontPanic: true}}
+	dev, err = New(&port, &gpiotest.Pin{}, &gpiotest.Pin{}, &gpiotest.Pin{}, &busy, &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = dev.Init()
+	var ioErr *IOError
+	if !errors.As(err, &ioErr) || ioErr.Op != "spi tx" {
+		t.Fatalf("Init() = %v, want an IOError", err)
+	}
+}
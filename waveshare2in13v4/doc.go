@@ -0,0 +1,26 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package waveshare2in13v4 controls Waveshare 2.13 v3 and v4 e-paper displays.
+//
+// Datasheet:
+// https://www.waveshare.com/w/upload/5/59/SSD1680_Datasheet.pdf
+//
+// Product page:
+// 2.13 inch version 4: https://www.waveshare.com/wiki/2.13inch_e-Paper_HAT_Manual
+//
+// The Waveshare 2.13in v3 and v4 displays use a SSD1680 controller. Unlike
+// the SSD1675A of the v2, the waveforms are loaded from the controller OTP so
+// no LUT has to be sent. Use package waveshare2in13v2 for the v2 display; the
+// revision is printed on the back of the HAT.
+//
+// # Panel detection
+//
+// When Opts.Probe is set, New resets the controller and checks that it is a
+// SSD1680 before returning, see PanelError. The busy pin alone can't tell the
+// SSD1680 from the SSD1675A of the v2, so the controller status register must
+// be read: this requires the bidirectional SDA line of the panel to be
+// connected to MISO, e.g. through a resistor. It isn't the case on the
+// Waveshare HAT, which is why the probe is disabled by default.
+package waveshare2in13v4
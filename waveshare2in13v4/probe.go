@@ -0,0 +1,83 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package waveshare2in13v4
+
+import (
+	"fmt"
+	"time"
+)
+
+// PanelError is returned by New when the attached panel doesn't behave like a
+// Waveshare 2.13in v3 or v4 display. This usually means that another revision
+// of the display is connected and the matching package must be used instead.
+type PanelError struct {
+	// Reason describes the failed check.
+	Reason string
+	// Status is the content of the controller status register, or 0 if it
+	// wasn't read.
+	Status byte
+}
+
+func (e *PanelError) Error() string {
+	if e.Status != 0 {
+		return fmt.Sprintf("waveshare2in13v4: unexpected panel: %s (status %#02x)", e.Reason, e.Status)
+	}
+	return "waveshare2in13v4: unexpected panel: " + e.Reason
+}
+
+// Status register bits.
+const (
+	statusBusy   = 1 << 2
+	statusChipID = 0x03
+
+	// chipIDSSD1680 is the chip ID reported by the SSD1680.
+	chipIDSSD1680 = 0x01
+)
+
+// probeTimeout is the longest the controller may stay busy after a software
+// reset; the SSD1680 needs a few milliseconds.
+var probeTimeout = time.Second
+
+// probe resets the controller and checks it behaves like a SSD1680.
+//
+// The busy pin of the SSD1680 is active high and released shortly after a
+// software reset, which rules out the controllers keeping it high when idle.
+// The SSD1675A of the v2 behaves the same way, so the status register is then
+// read and its chip ID checked. A status of 0x00 or 0xFF means MISO isn't
+// connected and the controller can't be identified.
+func (d *Dev) probe() error {
+	if err := d.reset(); err != nil {
+		return err
+	}
+
+	eh := errorHandler{d: *d}
+
+	eh.sendCommand(swReset)
+	if eh.err != nil {
+		return eh.err
+	}
+	if !eh.waitUntilIdleTimeout(probeTimeout) {
+		return &PanelError{Reason: "busy pin still asserted after reset; wrong busy polarity or not connected"}
+	}
+
+	var status [1]byte
+	eh.sendCommand(statusBitRead)
+	eh.readData(status[:])
+	if eh.err != nil {
+		return eh.err
+	}
+
+	s := status[0]
+	if s == 0x00 || s == 0xFF {
+		return &PanelError{Reason: "status not readable; MISO must be connected to the panel SDA line", Status: s}
+	}
+	if s&statusBusy != 0 {
+		return &PanelError{Reason: "controller reports busy while the busy pin is released", Status: s}
+	}
+	if s&statusChipID != chipIDSSD1680 {
+		return &PanelError{Reason: "unexpected controller revision", Status: s}
+	}
+	return nil
+}
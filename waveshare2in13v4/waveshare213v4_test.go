@@ -0,0 +1,179 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package waveshare2in13v4
+
+import (
+	"errors"
+	"image"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"periph.io/x/conn/v3/conntest"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpiotest"
+	"periph.io/x/conn/v3/spi/spitest"
+)
+
+func TestNew(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		opts       Opts
+		wantString string
+		wantBounds image.Rectangle
+	}{
+		{
+			name:       "EPD2in13v4",
+			opts:       EPD2in13v4,
+			wantBounds: image.Rect(0, 0, 122, 250),
+			wantString: "epd.Dev{playback, (0), Width: 122, Height: 250}",
+		},
+		{
+			name: "EPD2in13v4, top right",
+			opts: func() Opts {
+				opts := EPD2in13v4
+				opts.Origin = TopRight
+				return opts
+			}(),
+			wantBounds: image.Rect(0, 0, 250, 122),
+			wantString: "epd.Dev{playback, (0), Width: 250, Height: 122}",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dev, err := New(&spitest.Playback{}, &gpiotest.Pin{}, &gpiotest.Pin{}, &gpiotest.Pin{}, &gpiotest.Pin{
+				EdgesChan: make(chan gpio.Level, 1),
+			}, &tc.opts)
+			if err != nil {
+				t.Fatalf("New() failed: %v", err)
+			}
+
+			if diff := cmp.Diff(dev.String(), tc.wantString); diff != "" {
+				t.Errorf("String() difference (-got +want):\n%s", diff)
+			}
+
+			if diff := cmp.Diff(dev.Bounds(), tc.wantBounds); diff != "" {
+				t.Errorf("Bounds() difference (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNew_probe(t *testing.T) {
+	defer func(d time.Duration) { probeTimeout = d }(probeTimeout)
+	probeTimeout = 20 * time.Millisecond
+
+	for _, tc := range []struct {
+		name    string
+		busy    gpio.Level
+		ops     []conntest.IO
+		wantErr bool
+	}{
+		{
+			name: "SSD1680",
+			ops: []conntest.IO{
+				{W: []byte{swReset}},
+				{W: []byte{statusBitRead}},
+				{R: []byte{0x21}},
+			},
+		},
+		{
+			name: "MISO not connected",
+			ops: []conntest.IO{
+				{W: []byte{swReset}},
+				{W: []byte{statusBitRead}},
+				{R: []byte{0xff}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "wrong revision",
+			ops: []conntest.IO{
+				{W: []byte{swReset}},
+				{W: []byte{statusBitRead}},
+				{R: []byte{0x22}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "busy stuck",
+			busy: gpio.High,
+			ops: []conntest.IO{
+				{W: []byte{swReset}},
+			},
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			port := &spitest.Playback{Playback: conntest.Playback{Ops: tc.ops}}
+			opts := EPD2in13v4
+			opts.Probe = true
+			_, err := New(port, &gpiotest.Pin{}, &gpiotest.Pin{}, &gpiotest.Pin{}, &gpiotest.Pin{
+				L:         tc.busy,
+				EdgesChan: make(chan gpio.Level, 1),
+			}, &opts)
+
+			var pe *PanelError
+			if tc.wantErr {
+				if !errors.As(err, &pe) {
+					t.Fatalf("New() = %v, want *PanelError", err)
+				}
+			} else if err != nil {
+				t.Fatalf("New() failed: %v", err)
+			}
+			if err := port.Close(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestFullRefreshDue(t *testing.T) {
+	now := time.Now()
+	for _, tc := range []struct {
+		name         string
+		opts         Opts
+		partialDraws int
+		lastFull     time.Time
+		want         bool
+	}{
+		{
+			name:         "no policy",
+			partialDraws: 100,
+		},
+		{
+			name:         "count not reached",
+			opts:         Opts{FullRefreshEvery: 5},
+			partialDraws: 4,
+		},
+		{
+			name:         "count reached",
+			opts:         Opts{FullRefreshEvery: 5},
+			partialDraws: 5,
+			want:         true,
+		},
+		{
+			name:     "interval not reached",
+			opts:     Opts{FullRefreshInterval: time.Hour},
+			lastFull: now.Add(-time.Minute),
+		},
+		{
+			name:     "interval reached",
+			opts:     Opts{FullRefreshInterval: time.Hour},
+			lastFull: now.Add(-time.Hour),
+			want:     true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			d := Dev{opts: &tc.opts, partialDraws: tc.partialDraws, lastFull: tc.lastFull}
+			if got := d.fullRefreshDue(now); got != tc.want {
+				t.Errorf("fullRefreshDue() = %t, want %t", got, tc.want)
+			}
+			d.refreshed(true)
+			if d.fullRefreshDue(now) {
+				t.Error("fullRefreshDue() = true after a full refresh")
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package waveshare3in7
+
+import "image"
+
+type controller interface {
+	sendCommand(byte)
+	sendData([]byte)
+	waitUntilIdle()
+}
+
+// monoLUT is the waveform of the Mono mode.
+var monoLUT = []byte{
+	0x2A, 0x05, 0x15, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x05, 0x2A, 0x15, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x2A, 0x05, 0x15, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x05, 0x2A, 0x15, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x02, 0x03, 0x0A, 0x00, 0x02, 0x06, 0x0A, 0x05, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x22, 0x22, 0x22, 0x22, 0x22,
+}
+
+// gray4LUT is the waveform of the Gray4 mode.
+var gray4LUT = []byte{
+	0x2A, 0x06, 0x15, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x28, 0x06, 0x14, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x20, 0x06, 0x10, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x14, 0x06, 0x28, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x02, 0x02, 0x0A, 0x00, 0x00, 0x00, 0x08, 0x08, 0x02,
+	0x00, 0x02, 0x02, 0x0A, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x22, 0x22, 0x22, 0x22, 0x22,
+}
+
+func initDisplay(ctrl controller, opts *Opts) {
+	ctrl.waitUntilIdle()
+	ctrl.sendCommand(swReset)
+	ctrl.waitUntilIdle()
+
+	ctrl.sendCommand(driverOutputControl)
+	ctrl.sendData([]byte{
+		byte((opts.Height - 1) % 0x100),
+		byte((opts.Height - 1) / 0x100),
+		0x00,
+	})
+
+	// The LUTs aren't loaded from the OTP, so neither are the voltages.
+	ctrl.sendCommand(gateVoltageControl)
+	ctrl.sendData([]byte{0x00})
+
+	ctrl.sendCommand(sourceVoltageControl)
+	ctrl.sendData([]byte{0x41, 0xA8, 0x32})
+
+	ctrl.sendCommand(writeVCOMRegister)
+	ctrl.sendData([]byte{0x44})
+
+	ctrl.sendCommand(boosterSoftStartControl)
+	ctrl.sendData([]byte{0xAE, 0xC7, 0xC3, 0xC0, 0xC0})
+
+	ctrl.sendCommand(borderWaveformControl)
+	ctrl.sendData([]byte{0x03})
+
+	setMemoryArea(ctrl, image.Rect(0, 0, (opts.Width+7)/8, opts.Height))
+
+	ctrl.sendCommand(temperatureSensorControl)
+	ctrl.sendData([]byte{temperatureSensorInternal})
+	ctrl.waitUntilIdle()
+}
+
+// updateDisplay loads the waveform of mode and refreshes the display.
+func updateDisplay(ctrl controller, mode Mode) {
+	lut := monoLUT
+	if mode == Gray4 {
+		lut = gray4LUT
+	}
+	ctrl.sendCommand(writeLUTRegister)
+	ctrl.sendData(lut)
+
+	ctrl.sendCommand(displayUpdateControl2)
+	ctrl.sendData([]byte{
+		displayUpdateEnableClock |
+			displayUpdateEnableAnalog |
+			displayUpdateDisplay |
+			displayUpdateDisableAnalog |
+			displayUpdateDisableClock,
+	})
+
+	ctrl.sendCommand(masterActivation)
+	ctrl.waitUntilIdle()
+}
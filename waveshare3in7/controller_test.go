@@ -0,0 +1,96 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package waveshare3in7
+
+import (
+	"testing"
+
+	"periph.io/x/devices/v3/devicetest"
+)
+
+type record = devicetest.Record
+
+type fakeController struct {
+	devicetest.Recorder
+}
+
+func (r *fakeController) sendCommand(cmd byte) {
+	r.Command(cmd)
+}
+
+func (r *fakeController) sendData(data []byte) {
+	r.Data(data)
+}
+
+func (*fakeController) waitUntilIdle() {
+}
+
+func TestInitDisplay(t *testing.T) {
+	var got fakeController
+
+	initDisplay(&got, &EPD3in7)
+
+	want := []record{
+		{Cmd: swReset},
+		{Cmd: driverOutputControl, Data: []byte{0xDF, 0x01, 0x00}},
+		{Cmd: gateVoltageControl, Data: []byte{0x00}},
+		{Cmd: sourceVoltageControl, Data: []byte{0x41, 0xA8, 0x32}},
+		{Cmd: writeVCOMRegister, Data: []byte{0x44}},
+		{Cmd: boosterSoftStartControl, Data: []byte{0xAE, 0xC7, 0xC3, 0xC0, 0xC0}},
+		{Cmd: borderWaveformControl, Data: []byte{0x03}},
+		{Cmd: dataEntryModeSetting, Data: []byte{0x03}},
+		{Cmd: setRAMXAddressStartEndPosition, Data: []byte{0, 0, 0x17, 0x01}},
+		{Cmd: setRAMYAddressStartEndPosition, Data: []byte{0, 0, 0xDF, 0x01}},
+		{Cmd: setRAMXAddressCounter, Data: []byte{0, 0}},
+		{Cmd: setRAMYAddressCounter, Data: []byte{0, 0}},
+		{Cmd: temperatureSensorControl, Data: []byte{0x80}},
+	}
+
+	if diff := got.Diff(want); diff != "" {
+		t.Errorf("initDisplay() difference (-got +want):\n%s", diff)
+	}
+}
+
+func TestUpdateDisplay(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		mode Mode
+		lut  []byte
+	}{
+		{
+			name: "mono",
+			mode: Mono,
+			lut:  monoLUT,
+		},
+		{
+			name: "gray4",
+			mode: Gray4,
+			lut:  gray4LUT,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var got fakeController
+
+			updateDisplay(&got, tc.mode)
+
+			want := []record{
+				{Cmd: writeLUTRegister, Data: tc.lut},
+				{Cmd: displayUpdateControl2, Data: []byte{0xc7}},
+				{Cmd: masterActivation},
+			}
+			if diff := got.Diff(want); diff != "" {
+				t.Errorf("updateDisplay() difference (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLUTSize(t *testing.T) {
+	for _, lut := range [][]byte{monoLUT, gray4LUT} {
+		if len(lut) != 105 {
+			t.Errorf("len(lut) = %d, want 105", len(lut))
+		}
+	}
+}
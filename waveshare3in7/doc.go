@@ -0,0 +1,20 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package waveshare3in7 controls the Waveshare 3.7 e-paper display.
+//
+// Datasheet:
+// https://www.waveshare.com/w/upload/2/2a/SSD1677_1.0.pdf
+//
+// Product page:
+// https://www.waveshare.com/wiki/3.7inch_e-Paper_HAT_Manual
+//
+// The Waveshare 3.7in display is a 280x480 panel driven by a SSD1677
+// controller. It shows either black and white (Mono) or 4 levels of gray
+// (Gray4), see Opts.Mode and SetMode. The waveforms of both modes are sent
+// with each refresh; they come from the Waveshare reference driver.
+//
+// Only full refreshes are supported. Draw uploads the destination area only,
+// then refreshes the whole panel.
+package waveshare3in7
@@ -0,0 +1,227 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package waveshare3in7
+
+import (
+	"encoding/binary"
+	"image"
+	"image/draw"
+)
+
+// setMemoryArea configures the target drawing area (horizontal is in bytes,
+// vertical in pixels). Unlike the SSD1680, the SSD1677 addresses the RAM
+// horizontally in pixels.
+func setMemoryArea(ctrl controller, area image.Rectangle) {
+	startX, endX := uint16(area.Min.X*8), uint16(area.Max.X*8-1)
+	startY, endY := uint16(area.Min.Y), uint16(area.Max.Y-1)
+
+	startEndX := [4]byte{}
+	binary.LittleEndian.PutUint16(startEndX[0:], startX)
+	binary.LittleEndian.PutUint16(startEndX[2:], endX)
+
+	startEndY := [4]byte{}
+	binary.LittleEndian.PutUint16(startEndY[0:], startY)
+	binary.LittleEndian.PutUint16(startEndY[2:], endY)
+
+	ctrl.sendCommand(dataEntryModeSetting)
+	ctrl.sendData([]byte{
+		// Y increment, X increment; update address counter in X direction
+		0b011,
+	})
+
+	ctrl.sendCommand(setRAMXAddressStartEndPosition)
+	ctrl.sendData(startEndX[:4])
+
+	ctrl.sendCommand(setRAMYAddressStartEndPosition)
+	ctrl.sendData(startEndY[:4])
+
+	ctrl.sendCommand(setRAMXAddressCounter)
+	ctrl.sendData(startEndX[:2])
+
+	ctrl.sendCommand(setRAMYAddressCounter)
+	ctrl.sendData(startEndY[:2])
+}
+
+type drawOpts struct {
+	devSize image.Point
+	origin  Corner
+	mode    Mode
+	buffer  *image.Gray
+	dstRect image.Rectangle
+	src     image.Image
+	srcPts  image.Point
+}
+
+type drawSpec struct {
+	// Amount by which buffer contents must be moved to align with the physical
+	// top-left corner of the display.
+	//
+	// The offset shifts the buffer contents to be aligned such that the
+	// translated position of the physical, on-display (0,0) location is at
+	// a multiple of 8 on the equivalent to the physical X axis.
+	bufferDstOffset image.Point
+
+	// Destination in buffer in pixels.
+	bufferDstRect image.Rectangle
+
+	// Destination in device RAM, rotated and shifted to match the origin.
+	memDstRect image.Rectangle
+
+	// Area to send to device; horizontally in bytes (thus aligned to
+	// 8 pixels), vertically in pixels. Computed from memDstRect.
+	memRect image.Rectangle
+}
+
+// spec pre-computes the various offsets required for sending image updates to
+// the device.
+func (o *drawOpts) spec() drawSpec {
+	s := drawSpec{
+		bufferDstRect: image.Rectangle{Max: o.devSize}.Intersect(o.dstRect),
+	}
+
+	switch o.origin {
+	case TopRight:
+		s.bufferDstOffset.Y = o.buffer.Bounds().Dy() - o.devSize.Y
+	case BottomRight:
+		s.bufferDstOffset.Y = o.buffer.Bounds().Dy() - o.devSize.Y
+		s.bufferDstOffset.X = o.buffer.Bounds().Dx() - o.devSize.X
+	case BottomLeft:
+		s.bufferDstOffset.Y = o.buffer.Bounds().Dy() - o.devSize.Y
+		s.bufferDstOffset.X = o.buffer.Bounds().Dx() - o.devSize.X
+	}
+
+	if !s.bufferDstRect.Empty() {
+		switch o.origin {
+		case TopLeft:
+			s.memDstRect = s.bufferDstRect
+
+		case TopRight:
+			s.memDstRect.Min.X = o.devSize.Y - s.bufferDstRect.Max.Y
+			s.memDstRect.Max.X = o.devSize.Y - s.bufferDstRect.Min.Y
+
+			s.memDstRect.Min.Y = s.bufferDstRect.Min.X
+			s.memDstRect.Max.Y = s.bufferDstRect.Max.X
+
+		case BottomRight:
+			s.memDstRect.Min.X = o.devSize.X - s.bufferDstRect.Max.X
+			s.memDstRect.Max.X = o.devSize.X - s.bufferDstRect.Min.X
+
+			s.memDstRect.Min.Y = o.devSize.Y - s.bufferDstRect.Max.Y
+			s.memDstRect.Max.Y = o.devSize.Y - s.bufferDstRect.Min.Y
+
+		case BottomLeft:
+			s.memDstRect.Min.X = s.bufferDstRect.Min.Y
+			s.memDstRect.Max.X = s.bufferDstRect.Max.Y
+
+			s.memDstRect.Min.Y = o.devSize.X - s.bufferDstRect.Max.X
+			s.memDstRect.Max.Y = o.devSize.X - s.bufferDstRect.Min.X
+		}
+
+		s.bufferDstRect = s.bufferDstRect.Add(s.bufferDstOffset)
+
+		s.memRect.Min.X = s.memDstRect.Min.X / 8
+		s.memRect.Max.X = (s.memDstRect.Max.X + 7) / 8
+		s.memRect.Min.Y = s.memDstRect.Min.Y
+		s.memRect.Max.Y = s.memDstRect.Max.Y
+	}
+
+	return s
+}
+
+// level returns the level of the gray y in mode: 0 or 3 in Mono mode, 0 to 3
+// in Gray4 mode.
+func level(mode Mode, y uint8) uint8 {
+	if mode == Gray4 {
+		return uint8((int(y) + 0x2A) / 0x55)
+	}
+	if y >= 0x80 {
+		return 3
+	}
+	return 0
+}
+
+// sendImage sends the bit plane of the image selected by mask to the
+// controller after setting up the registers.
+func (o *drawOpts) sendImage(ctrl controller, cmd byte, mask uint8, spec *drawSpec) {
+	if spec.memRect.Empty() {
+		return
+	}
+
+	setMemoryArea(ctrl, spec.memRect)
+
+	ctrl.sendCommand(cmd)
+
+	var posFor func(destY, destX, bit int) image.Point
+
+	switch o.origin {
+	case TopLeft:
+		posFor = func(destY, destX, bit int) image.Point {
+			return image.Point{
+				X: destX + bit,
+				Y: destY,
+			}
+		}
+
+	case TopRight:
+		posFor = func(destY, destX, bit int) image.Point {
+			return image.Point{
+				X: destY,
+				Y: o.devSize.Y - destX - bit - 1,
+			}
+		}
+
+	case BottomRight:
+		posFor = func(destY, destX, bit int) image.Point {
+			return image.Point{
+				X: o.devSize.X - destX - bit - 1,
+				Y: o.devSize.Y - destY - 1,
+			}
+		}
+
+	case BottomLeft:
+		posFor = func(destY, destX, bit int) image.Point {
+			return image.Point{
+				X: o.devSize.X - destY - 1,
+				Y: destX + bit,
+			}
+		}
+	}
+
+	rowData := make([]byte, spec.memRect.Dx())
+
+	for destY := spec.memRect.Min.Y; destY < spec.memRect.Max.Y; destY++ {
+		for destX := 0; destX < len(rowData); destX++ {
+			rowData[destX] = 0
+
+			for bit := 0; bit < 8; bit++ {
+				bufPos := posFor(destY, (spec.memRect.Min.X+destX)*8, bit)
+				bufPos = bufPos.Add(spec.bufferDstOffset)
+
+				if level(o.mode, o.buffer.GrayAt(bufPos.X, bufPos.Y).Y)&mask != 0 {
+					rowData[destX] |= 0x80 >> bit
+				}
+			}
+		}
+
+		ctrl.sendData(rowData)
+	}
+}
+
+func drawImage(ctrl controller, opts *drawOpts) {
+	s := opts.spec()
+
+	if s.memRect.Empty() {
+		return
+	}
+
+	// The buffer is kept in logical orientation. Rotation and alignment with
+	// the origin happens while sending the image data.
+	draw.Src.Draw(opts.buffer, s.bufferDstRect, opts.src, opts.srcPts)
+
+	// The BW RAM holds the high bit of the level and the red RAM the low bit,
+	// both are the same in Mono mode.
+	opts.sendImage(ctrl, writeRAMBW, 2, &s)
+	opts.sendImage(ctrl, writeRAMRed, 1, &s)
+}
@@ -0,0 +1,287 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package waveshare3in7
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/display"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/spi"
+	"periph.io/x/devices/v3/ssd1306/image1bit"
+	"periph.io/x/host/v3/rpi"
+)
+
+// Commands
+const (
+	driverOutputControl            byte = 0x01
+	gateVoltageControl             byte = 0x03
+	sourceVoltageControl           byte = 0x04
+	boosterSoftStartControl        byte = 0x0C
+	deepSleepMode                  byte = 0x10
+	dataEntryModeSetting           byte = 0x11
+	swReset                        byte = 0x12
+	temperatureSensorControl       byte = 0x18
+	masterActivation               byte = 0x20
+	displayUpdateControl2          byte = 0x22
+	writeRAMBW                     byte = 0x24
+	writeRAMRed                    byte = 0x26
+	writeVCOMRegister              byte = 0x2C
+	writeLUTRegister               byte = 0x32
+	borderWaveformControl          byte = 0x3C
+	setRAMXAddressStartEndPosition byte = 0x44
+	setRAMYAddressStartEndPosition byte = 0x45
+	setRAMXAddressCounter          byte = 0x4E
+	setRAMYAddressCounter          byte = 0x4F
+)
+
+// Register values
+const (
+	temperatureSensorInternal = 0x80
+)
+
+// Flags for the displayUpdateControl2 command
+const (
+	displayUpdateDisableClock byte = 1 << iota
+	displayUpdateDisableAnalog
+	displayUpdateDisplay
+	displayUpdateMode2
+	displayUpdateLoadLUTFromOTP
+	displayUpdateLoadTemperature
+	displayUpdateEnableClock
+	displayUpdateEnableAnalog
+)
+
+// Dev defines the handler which is used to access the display.
+type Dev struct {
+	c conn.Conn
+
+	dc   gpio.PinOut
+	cs   gpio.PinOut
+	rst  gpio.PinOut
+	busy gpio.PinIn
+
+	bounds image.Rectangle
+	buffer *image.Gray
+	mode   Mode
+
+	opts *Opts
+}
+
+// Corner describes a corner on the physical device and is used to define the
+// origin for drawing operations.
+type Corner uint8
+
+const (
+	TopLeft Corner = iota
+	TopRight
+	BottomRight
+	BottomLeft
+)
+
+// Mode is the number of colors shown by the display.
+type Mode uint8
+
+const (
+	// Mono shows black and white.
+	Mono Mode = iota
+	// Gray4 shows black, white and two levels of gray.
+	Gray4
+)
+
+// Gray4Model is the color model of the display in Gray4 mode.
+var Gray4Model = color.Palette{
+	color.Gray{Y: 0x00},
+	color.Gray{Y: 0x55},
+	color.Gray{Y: 0xAA},
+	color.Gray{Y: 0xFF},
+}
+
+// Opts definies the structure of the display configuration.
+type Opts struct {
+	Width  int
+	Height int
+	Origin Corner
+	Mode   Mode
+}
+
+// EPD3in7 contains display configuration for the Waveshare 3in7.
+var EPD3in7 = Opts{
+	Width:  280,
+	Height: 480,
+}
+
+// flipPt returns a new image.Point with the X and Y coordinates exchanged.
+func flipPt(pt image.Point) image.Point {
+	return image.Point{X: pt.Y, Y: pt.X}
+}
+
+// New creates new handler which is used to access the display.
+func New(p spi.Port, dc, cs, rst gpio.PinOut, busy gpio.PinIn, opts *Opts) (*Dev, error) {
+	c, err := p.Connect(5*physic.MegaHertz, spi.Mode0, 8)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := busy.In(gpio.Float, gpio.FallingEdge); err != nil {
+		return nil, err
+	}
+
+	displaySize := image.Pt(opts.Width, opts.Height)
+
+	// The physical X axis is sized to have one-byte alignment on the (0,0)
+	// on-display position after rotation.
+	bufferSize := image.Pt((opts.Width+7)/8*8, opts.Height)
+
+	switch opts.Origin {
+	case TopLeft, BottomRight:
+	case TopRight, BottomLeft:
+		displaySize = flipPt(displaySize)
+		bufferSize = flipPt(bufferSize)
+	default:
+		return nil, fmt.Errorf("waveshare3in7: unknown corner %v", opts.Origin)
+	}
+	if opts.Mode > Gray4 {
+		return nil, fmt.Errorf("waveshare3in7: unknown mode %v", opts.Mode)
+	}
+
+	d := &Dev{
+		c:      c,
+		dc:     dc,
+		cs:     cs,
+		rst:    rst,
+		busy:   busy,
+		bounds: image.Rectangle{Max: displaySize},
+		buffer: image.NewGray(image.Rectangle{Max: bufferSize}),
+		mode:   opts.Mode,
+		opts:   opts,
+	}
+
+	// Default color
+	draw.Src.Draw(d.buffer, d.buffer.Bounds(), image.White, image.Point{})
+
+	return d, nil
+}
+
+// NewHat creates new handler which is used to access the display. Default Waveshare Hat configuration is used.
+func NewHat(p spi.Port, opts *Opts) (*Dev, error) {
+	dc := rpi.P1_22
+	cs := rpi.P1_24
+	rst := rpi.P1_11
+	busy := rpi.P1_18
+	return New(p, dc, cs, rst, busy, opts)
+}
+
+// Init configures the display for usage through the other functions.
+func (d *Dev) Init() error {
+	// Hardware Reset
+	if err := d.reset(); err != nil {
+		return err
+	}
+
+	eh := errorHandler{d: *d}
+
+	initDisplay(&eh, d.opts)
+
+	return eh.err
+}
+
+// SetMode changes the number of colors shown by the display. It takes effect
+// on the next refresh; the image already drawn is kept with its colors
+// quantized to the new mode.
+func (d *Dev) SetMode(mode Mode) error {
+	if mode > Gray4 {
+		return fmt.Errorf("waveshare3in7: unknown mode %v", mode)
+	}
+	d.mode = mode
+	return nil
+}
+
+// Clear clears the display with color, usually color.White or color.Black.
+func (d *Dev) Clear(color color.Color) error {
+	return d.Draw(d.buffer.Bounds(), &image.Uniform{C: color}, image.Point{})
+}
+
+// ColorModel returns the color model of the current mode, image1bit.BitModel
+// in Mono mode and Gray4Model in Gray4 mode.
+func (d *Dev) ColorModel() color.Model {
+	if d.mode == Gray4 {
+		return Gray4Model
+	}
+	return image1bit.BitModel
+}
+
+// Bounds returns the bounds for the configurated display.
+func (d *Dev) Bounds() image.Rectangle {
+	return d.bounds
+}
+
+// Draw draws the given image to the display. Only the destination area is
+// uploaded, then the whole display is refreshed.
+func (d *Dev) Draw(dstRect image.Rectangle, src image.Image, srcPts image.Point) error {
+	opts := drawOpts{
+		devSize: d.bounds.Max,
+		origin:  d.opts.Origin,
+		mode:    d.mode,
+		buffer:  d.buffer,
+		dstRect: dstRect,
+		src:     src,
+		srcPts:  srcPts,
+	}
+
+	eh := errorHandler{d: *d}
+
+	drawImage(&eh, &opts)
+
+	if eh.err == nil {
+		updateDisplay(&eh, d.mode)
+	}
+
+	return eh.err
+}
+
+// Halt clears the display.
+func (d *Dev) Halt() error {
+	return d.Clear(color.White)
+}
+
+// String returns a string containing configuration information.
+func (d *Dev) String() string {
+	return fmt.Sprintf("epd.Dev{%s, %s, Width: %d, Height: %d}", d.c, d.dc, d.bounds.Dx(), d.bounds.Dy())
+}
+
+// Sleep makes the controller enter deep sleep mode. It can be woken up by
+// calling Init again.
+func (d *Dev) Sleep() error {
+	eh := errorHandler{d: *d}
+
+	// Deep sleep mode 1, RAM content is retained.
+	eh.sendCommand(deepSleepMode)
+	eh.sendData([]byte{0x01})
+
+	return eh.err
+}
+
+// Reset the hardware
+func (d *Dev) reset() error {
+	eh := errorHandler{d: *d}
+
+	eh.rstOut(gpio.High)
+	time.Sleep(20 * time.Millisecond)
+	eh.rstOut(gpio.Low)
+	time.Sleep(2 * time.Millisecond)
+	eh.rstOut(gpio.High)
+	time.Sleep(20 * time.Millisecond)
+
+	return eh.err
+}
+
+var _ display.Drawer = &Dev{}
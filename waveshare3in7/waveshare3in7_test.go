@@ -0,0 +1,164 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package waveshare3in7
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpiotest"
+	"periph.io/x/conn/v3/spi/spitest"
+	"periph.io/x/devices/v3/devicetest"
+	"periph.io/x/devices/v3/ssd1306/image1bit"
+)
+
+func TestNew(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		opts       Opts
+		wantString string
+		wantBounds image.Rectangle
+		wantErr    bool
+	}{
+		{
+			name:       "EPD3in7",
+			opts:       EPD3in7,
+			wantBounds: image.Rect(0, 0, 280, 480),
+			wantString: "epd.Dev{playback, (0), Width: 280, Height: 480}",
+		},
+		{
+			name: "EPD3in7, top right",
+			opts: func() Opts {
+				opts := EPD3in7
+				opts.Origin = TopRight
+				return opts
+			}(),
+			wantBounds: image.Rect(0, 0, 480, 280),
+			wantString: "epd.Dev{playback, (0), Width: 480, Height: 280}",
+		},
+		{
+			name: "unknown mode",
+			opts: func() Opts {
+				opts := EPD3in7
+				opts.Mode = 2
+				return opts
+			}(),
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dev, err := New(&spitest.Playback{}, &gpiotest.Pin{}, &gpiotest.Pin{}, &gpiotest.Pin{}, &gpiotest.Pin{
+				EdgesChan: make(chan gpio.Level, 1),
+			}, &tc.opts)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("New() should fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("New() failed: %v", err)
+			}
+
+			if diff := cmp.Diff(dev.String(), tc.wantString); diff != "" {
+				t.Errorf("String() difference (-got +want):\n%s", diff)
+			}
+
+			if diff := cmp.Diff(dev.Bounds(), tc.wantBounds); diff != "" {
+				t.Errorf("Bounds() difference (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLevel(t *testing.T) {
+	for _, tc := range []struct {
+		mode Mode
+		y    uint8
+		want uint8
+	}{
+		{Mono, 0x00, 0},
+		{Mono, 0x7F, 0},
+		{Mono, 0x80, 3},
+		{Gray4, 0x00, 0},
+		{Gray4, 0x2A, 0},
+		{Gray4, 0x2B, 1},
+		{Gray4, 0x55, 1},
+		{Gray4, 0xAA, 2},
+		{Gray4, 0xD4, 2},
+		{Gray4, 0xD5, 3},
+		{Gray4, 0xFF, 3},
+	} {
+		if got := level(tc.mode, tc.y); got != tc.want {
+			t.Errorf("level(%d, %#x) = %d, want %d", tc.mode, tc.y, got, tc.want)
+		}
+	}
+}
+
+func TestDraw(t *testing.T) {
+	c := devicetest.NewCommandConn()
+	opts := EPD3in7
+	opts.Mode = Gray4
+	dev, err := New(c, &c.DC, &gpiotest.Pin{}, &gpiotest.Pin{}, &gpiotest.Pin{
+		EdgesChan: make(chan gpio.Level, 1),
+	}, &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m, ok := dev.ColorModel().(color.Palette); !ok || len(m) != 4 {
+		t.Error("ColorModel() is not Gray4Model")
+	}
+
+	// One row of 8 pixels: black, dark gray, light gray and white, twice.
+	src := image.NewGray(image.Rect(0, 0, 8, 1))
+	for x := 0; x < 8; x++ {
+		src.SetGray(x, 0, Gray4Model[x%4].(color.Gray))
+	}
+	if err := dev.Draw(image.Rect(0, 0, 8, 1), src, image.Point{}); err != nil {
+		t.Fatal(err)
+	}
+	area := []record{
+		{Cmd: dataEntryModeSetting, Data: []byte{0x03}},
+		{Cmd: setRAMXAddressStartEndPosition, Data: []byte{0, 0, 7, 0}},
+		{Cmd: setRAMYAddressStartEndPosition, Data: []byte{0, 0, 0, 0}},
+		{Cmd: setRAMXAddressCounter, Data: []byte{0, 0}},
+		{Cmd: setRAMYAddressCounter, Data: []byte{0, 0}},
+	}
+	var want []record
+	want = append(want, area...)
+	want = append(want, record{Cmd: writeRAMBW, Data: []byte{0b00110011}})
+	want = append(want, area...)
+	want = append(want, record{Cmd: writeRAMRed, Data: []byte{0b01010101}})
+	want = append(want,
+		record{Cmd: writeLUTRegister, Data: gray4LUT},
+		record{Cmd: displayUpdateControl2, Data: []byte{0xc7}},
+		record{Cmd: masterActivation})
+	if diff := c.Diff(want); diff != "" {
+		t.Errorf("Draw() difference (-got +want):\n%s", diff)
+	}
+
+	// In Mono mode, the grays are quantized to black or white in both RAMs.
+	if err := dev.SetMode(Mono); err != nil {
+		t.Fatal(err)
+	}
+	if dev.ColorModel() != image1bit.BitModel {
+		t.Error("ColorModel() is not image1bit.BitModel")
+	}
+	c.Reset()
+	if err := dev.Draw(image.Rect(0, 0, 8, 1), src, image.Point{}); err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range c.Records {
+		if (r.Cmd == writeRAMBW || r.Cmd == writeRAMRed) && r.Data[0] != 0b00110011 {
+			t.Errorf("Draw() sent %#08b to %#x, want 0b00110011", r.Data[0], r.Cmd)
+		}
+	}
+	if err := dev.SetMode(3); err == nil {
+		t.Error("SetMode() should fail with an unknown mode")
+	}
+}
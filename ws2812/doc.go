@@ -0,0 +1,25 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package ws2812 drives WS2812B and SK6812, RGB and RGBW, addressable LEDs
+// A.K.A. NeoPixels with the MOSI line of a SPI port.
+//
+// Each data bit is encoded as 3 SPI bits at 2.4MHz, 110 for a one and 100 for
+// a zero, so that no precise GPIO timing is needed. The pixels can be laid
+// out as a strip or as a matrix, and are corrected for gamma and brightness
+// before being sent.
+//
+// Unlike package nrzled, the white channel of RGBW LEDs is derived from the
+// color drawn, and Draw accepts any rectangle of a matrix.
+//
+// On the Raspberry Pi, the SPI clock depends on the core clock; add
+// `core_freq=250` to /boot/config.txt and raise the spidev buffer size with
+// `spidev.bufsize=65536` for long strips.
+//
+// # Datasheets
+//
+// https://cdn-shop.adafruit.com/datasheets/WS2812B.pdf
+//
+// https://cdn-shop.adafruit.com/product-files/2757/p2757_SK6812RGBW_REV01.pdf
+package ws2812
@@ -0,0 +1,49 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ws2812_test
+
+import (
+	"image"
+	"image/color"
+	"log"
+
+	"periph.io/x/conn/v3/spi/spireg"
+	"periph.io/x/devices/v3/ws2812"
+	"periph.io/x/host/v3"
+)
+
+func Example() {
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Use spireg SPI port registry to find the first available SPI bus.
+	p, err := spireg.Open("")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer p.Close()
+
+	// Opens a 16x16 serpentine matrix at 25% brightness.
+	o := ws2812.DefaultOpts
+	o.Width = 16
+	o.Height = 16
+	o.Layout = ws2812.SerpentineRows
+	o.Brightness = 64
+	dev, err := ws2812.NewSPI(p, &o)
+	if err != nil {
+		log.Fatalf("failed to open: %v", err)
+	}
+	img := image.NewNRGBA(dev.Bounds())
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 16; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{uint8(16 * x), uint8(16 * y), 128, 255})
+		}
+	}
+	if err := dev.Draw(dev.Bounds(), img, image.Point{}); err != nil {
+		log.Fatalf("failed to draw: %v", err)
+	}
+}
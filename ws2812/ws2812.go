@@ -0,0 +1,324 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ws2812
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"sync"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/display"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/spi"
+)
+
+// Format is the order and number of the color channels of the LEDs.
+type Format int
+
+const (
+	// GRB is used by the WS2812B and the SK6812 RGB.
+	GRB Format = iota
+	// GRBW is used by the SK6812 RGBW.
+	GRBW
+)
+
+func (f Format) String() string {
+	switch f {
+	case GRB:
+		return "GRB"
+	case GRBW:
+		return "GRBW"
+	default:
+		return "Format(?)"
+	}
+}
+
+// channels returns the number of bytes per pixel.
+func (f Format) channels() int {
+	if f == GRBW {
+		return 4
+	}
+	return 3
+}
+
+// Layout is the order in which the LEDs of a matrix are chained.
+type Layout int
+
+const (
+	// Rows chains the rows left to right, from the top.
+	Rows Layout = iota
+	// SerpentineRows chains the rows from the top, in alternating directions:
+	// the first row left to right, the second right to left, etc.
+	SerpentineRows
+	// Columns chains the columns top to bottom, from the left.
+	Columns
+	// SerpentineColumns chains the columns from the left, in alternating
+	// directions: the first column top to bottom, the second bottom to top,
+	// etc.
+	SerpentineColumns
+)
+
+// DefaultOpts is the recommended default options.
+var DefaultOpts = Opts{
+	Width:      150, // 150 LEDs is a common strip length.
+	Height:     1,
+	Format:     GRB,
+	Brightness: 255,
+	Gamma:      2.8,
+}
+
+// Opts defines the options for the device.
+type Opts struct {
+	// Width and Height are the dimensions of the matrix. A strip has a Height
+	// of 1, 0 is treated as 1.
+	Width  int
+	Height int
+	Format Format
+	Layout Layout
+	// Brightness scales the intensity of all the channels, 255 is full
+	// intensity.
+	Brightness uint8
+	// Gamma is the exponent applied to the channels so that the perceived
+	// intensity is linear. 0 or 1 disables the correction.
+	Gamma float64
+}
+
+// Dev is a handle to a chain of WS2812B or SK6812 LEDs.
+type Dev struct {
+	// Immutable.
+	s      spi.Conn
+	opts   Opts
+	rect   image.Rectangle
+	buf    []byte // Whole SPI transfer, including the padding
+	rawBuf []byte // Encoded pixels, excluding the padding
+
+	mu     sync.Mutex
+	lut    [256]byte
+	pixels []byte // Back buffer in the LED channel order, before correction
+}
+
+// NewSPI returns a handle to LEDs whose data input is connected to the MOSI
+// line of p.
+//
+// The SPI port must support 2.4MHz reliably and transfers of
+// 9*Width*Height+93 bytes for RGB, 12*Width*Height+93 bytes for RGBW.
+func NewSPI(p spi.Port, opts *Opts) (*Dev, error) {
+	o := *opts
+	if o.Height == 0 {
+		o.Height = 1
+	}
+	if o.Width <= 0 || o.Height < 0 {
+		return nil, errors.New("ws2812: invalid dimensions")
+	}
+	if o.Format != GRB && o.Format != GRBW {
+		return nil, errors.New("ws2812: invalid format")
+	}
+	if o.Layout < Rows || o.Layout > SerpentineColumns {
+		return nil, errors.New("ws2812: invalid layout")
+	}
+	if o.Gamma < 0 {
+		return nil, errors.New("ws2812: invalid gamma")
+	}
+	n := o.Width * o.Height * o.Format.channels()
+	bufSize := headLen + 3*n + resetLen
+	if l, ok := p.(conn.Limits); ok {
+		if s := l.MaxTxSize(); s < bufSize {
+			return nil, fmt.Errorf("ws2812: SPI buffer of %d bytes is too short, %d bytes are needed", s, bufSize)
+		}
+	}
+	c, err := p.Connect(spiFreq, spi.Mode0, 8)
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, bufSize)
+	d := &Dev{
+		s:      c,
+		opts:   o,
+		rect:   image.Rect(0, 0, o.Width, o.Height),
+		buf:    buf,
+		rawBuf: buf[headLen : headLen+3*n],
+		pixels: make([]byte, n),
+	}
+	d.updateLUT()
+	d.encode()
+	return d, nil
+}
+
+func (d *Dev) String() string {
+	return fmt.Sprintf("ws2812{%s}", d.s)
+}
+
+// ColorModel implements display.Drawer.
+//
+// It is color.NRGBAModel.
+func (d *Dev) ColorModel() color.Model {
+	return color.NRGBAModel
+}
+
+// Bounds implements display.Drawer. Min is guaranteed to be {0, 0}.
+func (d *Dev) Bounds() image.Rectangle {
+	return d.rect
+}
+
+// Draw implements display.Drawer.
+//
+// The alpha channel is ignored. With GRBW LEDs, the white channel is the
+// common part of the red, green and blue channels, which is removed from
+// them.
+//
+// A back buffer is kept so that partial updates are supported, albeit all
+// the LEDs are updated.
+func (d *Dev) Draw(r image.Rectangle, src image.Image, sp image.Point) error {
+	if r = r.Intersect(d.rect); r.Empty() {
+		return nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	ch := d.opts.Format.channels()
+	img, fast := src.(*image.NRGBA)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			p := sp.Add(image.Pt(x-r.Min.X, y-r.Min.Y))
+			var c color.NRGBA
+			if fast {
+				c = img.NRGBAAt(p.X, p.Y)
+			} else {
+				c = color.NRGBAModel.Convert(src.At(p.X, p.Y)).(color.NRGBA)
+			}
+			i := d.index(x, y) * ch
+			if ch == 4 {
+				w := min(c.R, min(c.G, c.B))
+				d.pixels[i+0] = c.G - w
+				d.pixels[i+1] = c.R - w
+				d.pixels[i+2] = c.B - w
+				d.pixels[i+3] = w
+			} else {
+				d.pixels[i+0] = c.G
+				d.pixels[i+1] = c.R
+				d.pixels[i+2] = c.B
+			}
+		}
+	}
+	d.encode()
+	return d.s.Tx(d.buf, nil)
+}
+
+// Write accepts a stream of raw RGB or RGBW pixels, in the order the LEDs
+// are chained, and sends it. The gamma and brightness corrections are
+// applied.
+func (d *Dev) Write(pixels []byte) (int, error) {
+	ch := d.opts.Format.channels()
+	if len(pixels)%ch != 0 || len(pixels) > len(d.pixels) {
+		return 0, errors.New("ws2812: invalid RGB stream length")
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for i := 0; i < len(pixels); i += ch {
+		d.pixels[i+0] = pixels[i+1]
+		d.pixels[i+1] = pixels[i+0]
+		copy(d.pixels[i+2:i+ch], pixels[i+2:i+ch])
+	}
+	d.encode()
+	return len(pixels), d.s.Tx(d.buf, nil)
+}
+
+// SetBrightness sets the brightness, 255 being full intensity, and redraws
+// the LEDs.
+func (d *Dev) SetBrightness(b uint8) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.opts.Brightness = b
+	d.updateLUT()
+	d.encode()
+	return d.s.Tx(d.buf, nil)
+}
+
+// Halt turns the LEDs off.
+//
+// It doesn't affect the back buffer.
+func (d *Dev) Halt() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for i := 0; i < len(d.rawBuf); i += 3 {
+		copy(d.rawBuf[i:], nrz3[0][:])
+	}
+	return d.s.Tx(d.buf, nil)
+}
+
+//
+
+const (
+	// spiFreq is 3 SPI bits per data bit of 1.25µs.
+	spiFreq = 2400 * physic.KiloHertz
+	// headLen keeps MOSI low while the SPI controller starts the transfer, so
+	// the first bit is not misread.
+	headLen = 3
+	// resetLen keeps MOSI low for more than the 280µs needed by the latest
+	// WS2812B revisions to latch the data.
+	resetLen = 90
+)
+
+// nrz3 is the MSB first encoding of each byte value as 3 SPI bits per bit.
+var nrz3 = func() (t [256][3]byte) {
+	for v := range t {
+		var bits uint32
+		for i := 7; i >= 0; i-- {
+			bits <<= 3
+			if v&(1<<uint(i)) != 0 {
+				bits |= 6
+			} else {
+				bits |= 4
+			}
+		}
+		t[v] = [3]byte{byte(bits >> 16), byte(bits >> 8), byte(bits)}
+	}
+	return t
+}()
+
+// index returns the position of the LED at x, y in the chain.
+func (d *Dev) index(x, y int) int {
+	w, h := d.opts.Width, d.opts.Height
+	switch d.opts.Layout {
+	case SerpentineRows:
+		if y&1 != 0 {
+			x = w - 1 - x
+		}
+		return y*w + x
+	case Columns:
+		return x*h + y
+	case SerpentineColumns:
+		if x&1 != 0 {
+			y = h - 1 - y
+		}
+		return x*h + y
+	default:
+		return y*w + x
+	}
+}
+
+// updateLUT computes the gamma and brightness correction table.
+func (d *Dev) updateLUT() {
+	g := d.opts.Gamma
+	if g == 0 {
+		g = 1
+	}
+	scale := float64(d.opts.Brightness)
+	for i := range d.lut {
+		d.lut[i] = byte(math.Round(math.Pow(float64(i)/255, g) * scale))
+	}
+}
+
+// encode converts the back buffer into the SPI bit stream.
+func (d *Dev) encode() {
+	for i, v := range d.pixels {
+		copy(d.rawBuf[3*i:], nrz3[d.lut[v]][:])
+	}
+}
+
+var _ display.Drawer = &Dev{}
@@ -0,0 +1,193 @@
+// Copyright 2024 The Periph Authors. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ws2812
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+
+	"periph.io/x/conn/v3/spi/spitest"
+)
+
+// decode returns the channel values of the last transfer recorded in buf.
+func decode(t *testing.T, buf *bytes.Buffer, d *Dev) []byte {
+	b := buf.Bytes()
+	b = b[len(b)-len(d.buf):]
+	buf.Reset()
+	for _, v := range b[:headLen] {
+		if v != 0 {
+			t.Fatal("head is not low")
+		}
+	}
+	for _, v := range b[len(b)-resetLen:] {
+		if v != 0 {
+			t.Fatal("reset is not low")
+		}
+	}
+	raw := b[headLen : len(b)-resetLen]
+	out := make([]byte, len(raw)/3)
+	for i := range out {
+		bits := uint32(raw[3*i])<<16 | uint32(raw[3*i+1])<<8 | uint32(raw[3*i+2])
+		for j := 7; j >= 0; j-- {
+			switch (bits >> uint(3*j)) & 7 {
+			case 6:
+				out[i] |= 1 << uint(j)
+			case 4:
+			default:
+				t.Fatalf("invalid symbol in %#06x", bits)
+			}
+		}
+	}
+	return out
+}
+
+func TestNewSPI(t *testing.T) {
+	for _, o := range []Opts{
+		{Width: 0},
+		{Width: 1, Format: Format(2)},
+		{Width: 1, Layout: Layout(4)},
+		{Width: 1, Gamma: -1},
+	} {
+		if _, err := NewSPI(spitest.NewRecordRaw(&bytes.Buffer{}), &o); err == nil {
+			t.Fatalf("%+v: expected error", o)
+		}
+	}
+	d, err := NewSPI(spitest.NewRecordRaw(&bytes.Buffer{}), &DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := d.String(); s != "ws2812{recordraw}" {
+		t.Fatal(s)
+	}
+	if r := d.Bounds(); r != image.Rect(0, 0, 150, 1) {
+		t.Fatal(r)
+	}
+	if l := len(d.buf); l != 3+9*150+90 {
+		t.Fatal(l)
+	}
+}
+
+func TestDraw_Matrix(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	img.SetNRGBA(0, 0, color.NRGBA{R: 255, A: 255})
+	img.SetNRGBA(1, 0, color.NRGBA{G: 255, A: 255})
+	img.SetNRGBA(0, 1, color.NRGBA{B: 255, A: 255})
+	img.SetNRGBA(1, 1, color.NRGBA{R: 255, G: 255, B: 255, A: 255})
+	data := []struct {
+		layout Layout
+		want   []byte
+	}{
+		{Rows, []byte{0, 255, 0, 255, 0, 0, 0, 0, 255, 255, 255, 255}},
+		{SerpentineRows, []byte{0, 255, 0, 255, 0, 0, 255, 255, 255, 0, 0, 255}},
+		{Columns, []byte{0, 255, 0, 0, 0, 255, 255, 0, 0, 255, 255, 255}},
+		{SerpentineColumns, []byte{0, 255, 0, 0, 0, 255, 255, 255, 255, 255, 0, 0}},
+	}
+	for _, line := range data {
+		buf := bytes.Buffer{}
+		d, err := NewSPI(spitest.NewRecordRaw(&buf), &Opts{Width: 2, Height: 2, Layout: line.layout, Brightness: 255})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := d.Draw(d.Bounds(), img, image.Point{}); err != nil {
+			t.Fatal(err)
+		}
+		if got := decode(t, &buf, d); !bytes.Equal(got, line.want) {
+			t.Fatalf("%d: %v != %v", line.layout, got, line.want)
+		}
+	}
+}
+
+func TestDraw_Partial(t *testing.T) {
+	buf := bytes.Buffer{}
+	d, err := NewSPI(spitest.NewRecordRaw(&buf), &Opts{Width: 4, Brightness: 255})
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := &image.Uniform{C: color.NRGBA{R: 10, G: 20, B: 30, A: 255}}
+	if err := d.Draw(image.Rect(1, 0, 3, 5), src, image.Point{}); err != nil {
+		t.Fatal(err)
+	}
+	want := []byte{0, 0, 0, 20, 10, 30, 20, 10, 30, 0, 0, 0}
+	if got := decode(t, &buf, d); !bytes.Equal(got, want) {
+		t.Fatal(got)
+	}
+	if err := d.Draw(image.Rect(5, 0, 6, 1), src, image.Point{}); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 0 {
+		t.Fatal("out of bounds draw must not transfer")
+	}
+	if err := d.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	if got := decode(t, &buf, d); !bytes.Equal(got, make([]byte, 12)) {
+		t.Fatal(got)
+	}
+	// The back buffer is preserved.
+	if err := d.SetBrightness(128); err != nil {
+		t.Fatal(err)
+	}
+	want = []byte{0, 0, 0, 10, 5, 15, 10, 5, 15, 0, 0, 0}
+	if got := decode(t, &buf, d); !bytes.Equal(got, want) {
+		t.Fatal(got)
+	}
+}
+
+func TestRGBW(t *testing.T) {
+	buf := bytes.Buffer{}
+	d, err := NewSPI(spitest.NewRecordRaw(&buf), &Opts{Width: 2, Format: GRBW, Brightness: 255, Gamma: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	img := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	img.Set(0, 0, color.RGBA{R: 255, G: 255, B: 128, A: 255})
+	img.Set(1, 0, color.RGBA{R: 255, A: 255})
+	if err := d.Draw(d.Bounds(), img, image.Point{}); err != nil {
+		t.Fatal(err)
+	}
+	// 127² / 255 and 128² / 255.
+	want := []byte{63, 63, 0, 64, 0, 255, 0, 0}
+	if got := decode(t, &buf, d); !bytes.Equal(got, want) {
+		t.Fatal(got)
+	}
+	if _, err := d.Write([]byte{1, 2, 3}); err == nil {
+		t.Fatal("expected error")
+	}
+	if n, err := d.Write([]byte{1, 2, 3, 4}); n != 4 || err != nil {
+		t.Fatal(n, err)
+	}
+	want = []byte{0, 0, 0, 0, 0, 255, 0, 0}
+	if got := decode(t, &buf, d); !bytes.Equal(got, want) {
+		t.Fatal(got)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	buf := bytes.Buffer{}
+	d, err := NewSPI(spitest.NewRecordRaw(&buf), &Opts{Width: 2, Brightness: 255})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := d.Write([]byte{1, 2, 3, 4, 5, 6}); n != 6 || err != nil {
+		t.Fatal(n, err)
+	}
+	if got, want := decode(t, &buf, d), []byte{2, 1, 3, 5, 4, 6}; !bytes.Equal(got, want) {
+		t.Fatal(got)
+	}
+	if _, err := d.Write(make([]byte, 9)); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestStrings(t *testing.T) {
+	if s := GRBW.String(); s != "GRBW" {
+		t.Fatal(s)
+	}
+	if s := Format(3).String(); s != "Format(?)" {
+		t.Fatal(s)
+	}
+}